@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/ai"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"os"
+	"slices"
+	"time"
+)
+
+// aibench compares the latency of requests to the AI endpoint on a cold transport, a new one per
+// request like the first lookup of a fresh instance, with the pooled transport of a warm instance.
+// Only the connection is measured, the requests aren't authenticated and no model is called.
+func main() {
+	target := flag.String("url", ai.VertexBaseURL, "endpoint receiving the requests")
+	local := flag.Bool("local", false, "measure against a local HTTP/2 TLS server instead of the endpoint")
+	requests := flag.Int("n", 20, "requests of every run")
+	maxIdleConns := flag.Int("max-idle-conns", 16, "idle connections of the pooled transport")
+	connectTimeout := flag.Duration("connect-timeout", 5*time.Second, "dial and TLS handshake timeout")
+	flag.Parse()
+
+	options := ai.TransportOptions{MaxIdleConns: *maxIdleConns, IdleConnTimeout: 90 * time.Second, ConnectTimeout: *connectTimeout}
+	newTransport := func() *http.Transport {
+		transport, err := ai.NewTransport(options)
+		if err != nil {
+			log.Fatalf("Failed to create transport: %v", err)
+		}
+		return transport
+	}
+
+	url := *target
+	if *local {
+		server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		server.EnableHTTP2 = true
+		server.StartTLS()
+		defer server.Close()
+
+		url = server.URL
+		trusted := server.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs
+		base := newTransport
+		newTransport = func() *http.Transport {
+			transport := base()
+			transport.TLSClientConfig.RootCAs = trusted
+			return transport
+		}
+	}
+
+	cold := make([]time.Duration, 0, *requests)
+	for range *requests {
+		transport := newTransport()
+		latency, _, err := measure(&http.Client{Transport: transport}, url)
+		transport.CloseIdleConnections()
+		if err != nil {
+			log.Fatalf("Cold request failed: %v", err)
+		}
+		cold = append(cold, latency)
+	}
+
+	transport := newTransport()
+	client := &http.Client{Transport: transport}
+	if err := ai.WarmUp(context.Background(), client, url); err != nil {
+		log.Fatalf("Failed to warm up: %v", err)
+	}
+	warm := make([]time.Duration, 0, *requests)
+	var reused int
+	for range *requests {
+		latency, wasReused, err := measure(client, url)
+		if err != nil {
+			log.Fatalf("Warm request failed: %v", err)
+		}
+		if wasReused {
+			reused++
+		}
+		warm = append(warm, latency)
+	}
+	transport.CloseIdleConnections()
+
+	fmt.Printf("%-6s %10s %10s %10s\n", "run", "p50", "p95", "max")
+	fmt.Printf("%-6s %10s %10s %10s\n", "cold", percentile(cold, 50), percentile(cold, 95), percentile(cold, 100))
+	fmt.Printf("%-6s %10s %10s %10s\n", "warm", percentile(warm, 50), percentile(warm, 95), percentile(warm, 100))
+	fmt.Printf("%d of %d warm requests reused a pooled connection\n", reused, *requests)
+
+	if percentile(warm, 50) >= percentile(cold, 50) {
+		fmt.Println("FAIL the pooled transport is not faster than cold connections")
+		os.Exit(1)
+	}
+	fmt.Printf("Warm requests are %.1fx faster at p50\n", float64(percentile(cold, 50))/float64(percentile(warm, 50)))
+}
+
+// measure sends one request and returns its latency up to the end of the body and whether it reused
+// a pooled connection
+func measure(client *http.Client, url string) (time.Duration, bool, error) {
+	var reused bool
+	trace := &httptrace.ClientTrace{GotConn: func(info httptrace.GotConnInfo) { reused = info.Reused }}
+	request, err := http.NewRequestWithContext(httptrace.WithClientTrace(context.Background(), trace), http.MethodHead, url, nil)
+	if err != nil {
+		return 0, false, err
+	}
+
+	start := time.Now()
+	response, err := client.Do(request)
+	if err != nil {
+		return 0, false, err
+	}
+	_, _ = io.Copy(io.Discard, response.Body)
+	_ = response.Body.Close()
+
+	return time.Since(start), reused, nil
+}
+
+// percentile returns the latency below which p percent of the samples are
+func percentile(samples []time.Duration, p int) time.Duration {
+	sorted := slices.Clone(samples)
+	slices.Sort(sorted)
+	index := (len(sorted)*p+99)/100 - 1
+
+	return sorted[max(index, 0)]
+}
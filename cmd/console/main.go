@@ -3,38 +3,319 @@ package main
 import (
 	"context"
 	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/adapters/console"
+	"github.com/DeryabinSergey/germanarticlebot/internal/application/usecases"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
 	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/container"
-	"log"
+	"github.com/spf13/cobra"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
 )
 
 func main() {
-	ctx := context.Background()
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
 
-	// Initialize container
-	appContainer, err := container.NewContainer(ctx)
+	if err := newRootCommand().ExecuteContext(ctx); err != nil {
+		cancel()
+		os.Exit(1)
+	}
+}
+
+// app lazily builds the container, so help and flag errors don't need the configuration
+type app struct {
+	container *container.Container
+	noColor   bool
+}
+
+func (a *app) handler(ctx context.Context) (*console.Handler, error) {
+	if a.container == nil {
+		appContainer, err := container.NewContainer(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize application: %w", err)
+		}
+		a.container = appContainer
+		a.container.ConsoleHandler.SetColor(!a.noColor && colorTerminal())
+	}
+
+	return a.container.ConsoleHandler, nil
+}
+
+func (a *app) flush(ctx context.Context) {
+	if a.container == nil {
+		return
+	}
+	_ = a.container.Tracer.Flush(context.WithoutCancel(ctx))
+	_ = a.container.Logger.Flush(context.WithoutCancel(ctx))
+}
+
+func newRootCommand() *cobra.Command {
+	a := &app{}
+	var language, format string
+
+	root := &cobra.Command{
+		Use:   "gab [word] [language]",
+		Short: "German article lookup, quiz and vocabulary export",
+		Long: "gab looks up the article of German nouns.\n" +
+			"Without arguments it starts an interactive session, with a word it prints the lookup result.",
+		Args:         cobra.MaximumNArgs(2),
+		SilenceUsage: true,
+		PersistentPostRun: func(cmd *cobra.Command, _ []string) {
+			a.flush(cmd.Context())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return runREPL(cmd, a, language, format)
+			}
+			// gab <word> [language] keeps the arguments of the former console command
+			if len(args) > 1 {
+				language = args[1]
+			}
+			return runLookup(cmd, a, args[0], language, format)
+		},
+	}
+	root.PersistentFlags().StringVarP(&language, "lang", "l", "en", "answer language")
+	root.PersistentFlags().BoolVar(&a.noColor, "no-color", false, "disable colors of the table format")
+	root.Flags().StringVarP(&format, "format", "f", string(console.FormatJSON), formatUsage())
+
+	root.AddCommand(
+		newLookupCommand(a, &language),
+		newQuizCommand(a),
+		newExportCommand(a, &language),
+		newBatchCommand(a, &language),
+		newREPLCommand(a, &language),
+	)
+
+	return root
+}
+
+func newLookupCommand(a *app, language *string) *cobra.Command {
+	var format string
+	cmd := &cobra.Command{
+		Use:     "lookup <word>",
+		Short:   "Look up the article of a German noun",
+		Example: "  gab lookup Haus --lang ru --format table",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runLookup(cmd, a, args[0], *language, format)
+		},
+	}
+	cmd.Flags().StringVarP(&format, "format", "f", string(console.FormatJSON), formatUsage())
+
+	return cmd
+}
+
+func newQuizCommand(a *app) *cobra.Command {
+	var rounds int
+	var levelName string
+	cmd := &cobra.Command{
+		Use:     "quiz",
+		Short:   "Guess the articles of random dictionary nouns",
+		Example: "  gab quiz --rounds 5 --level A1",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			var level entities.Level
+			if levelName != "" {
+				parsed, ok := entities.ParseLevel(levelName)
+				if !ok {
+					return fmt.Errorf("unknown level %q, expected one of %v", levelName, entities.Levels)
+				}
+				level = parsed
+			}
+
+			handler, err := a.handler(cmd.Context())
+			if err != nil {
+				return err
+			}
+			_, err = handler.RunQuiz(cmd.Context(), console.NewPrompter(cmd.InOrStdin(), cmd.OutOrStdout()), cmd.OutOrStdout(), rounds, level)
+			return err
+		},
+	}
+	cmd.Flags().IntVarP(&rounds, "rounds", "n", 10, "number of questions, 0 asks until you quit")
+	cmd.Flags().StringVar(&levelName, "level", "", "only nouns of the level, A1 to C2")
+
+	return cmd
+}
+
+func newExportCommand(a *app, language *string) *cobra.Command {
+	var format, out string
+	cmd := &cobra.Command{
+		Use:   "export [word...]",
+		Short: "Export the articles of a word list as CSV or JSON",
+		Long:  "Export looks up the words given as arguments, or read from stdin when there are none, one per line or delimited.",
+		Example: "  gab export Haus Katze Hund --out vocabulary.csv\n" +
+			"  cat words.txt | gab export --format json",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			words := args
+			if len(words) == 0 {
+				parsed, err := usecases.ParseWordList(cmd.InOrStdin())
+				if err != nil {
+					return err
+				}
+				words = parsed
+			}
+
+			handler, err := a.handler(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			writer := cmd.OutOrStdout()
+			if out != "" {
+				file, err := os.Create(out)
+				if err != nil {
+					return err
+				}
+				defer file.Close()
+				writer = file
+			}
+
+			return handler.Export(cmd.Context(), words, *language, format, writer)
+		},
+	}
+	cmd.Flags().StringVarP(&format, "format", "f", "csv", "export format: csv or json")
+	cmd.Flags().StringVarP(&out, "out", "o", "", "output file, stdout by default")
+
+	return cmd
+}
+
+func newBatchCommand(a *app, language *string) *cobra.Command {
+	var format, out string
+	var concurrency int
+	cmd := &cobra.Command{
+		Use:   "batch <file>",
+		Short: "Look up a word list file of any size with parallel lookups",
+		Long: "Batch reads a word list, one word per line or the first CSV column, \"-\" reads stdin.\n" +
+			"The format follows the extension of --out when it is not given, CSV by default.",
+		Example: "  gab batch words.txt --out results.csv\n" +
+			"  gab batch words.txt --out results.json --concurrency 8",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			input := cmd.InOrStdin()
+			if args[0] != "-" {
+				file, err := os.Open(args[0])
+				if err != nil {
+					return err
+				}
+				defer file.Close()
+				input = file
+			}
+			words, err := usecases.ParseWordList(input)
+			if err != nil {
+				return err
+			}
+
+			if format == "" {
+				format = "csv"
+				if strings.EqualFold(filepath.Ext(out), ".json") {
+					format = "json"
+				}
+			}
+
+			handler, err := a.handler(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			writer := cmd.OutOrStdout()
+			if out != "" {
+				file, err := os.Create(out)
+				if err != nil {
+					return err
+				}
+				defer file.Close()
+				writer = file
+			}
+
+			return handler.Batch(cmd.Context(), words, *language, format, concurrency, writer, cmd.ErrOrStderr())
+		},
+	}
+	cmd.Flags().StringVarP(&format, "format", "f", "", "output format: csv or json")
+	cmd.Flags().StringVarP(&out, "out", "o", "", "output file, stdout by default")
+	cmd.Flags().IntVarP(&concurrency, "concurrency", "c", 4, "number of parallel lookups")
+
+	return cmd
+}
+
+func newREPLCommand(a *app, language *string) *cobra.Command {
+	var format string
+	cmd := &cobra.Command{
+		Use:   "repl",
+		Short: "Start an interactive session with line history",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runREPL(cmd, a, *language, format)
+		},
+	}
+	cmd.Flags().StringVarP(&format, "format", "f", string(console.FormatText), formatUsage())
+
+	return cmd
+}
+
+func runLookup(cmd *cobra.Command, a *app, word, language, formatName string) error {
+	format, err := console.ParseFormat(formatName)
 	if err != nil {
-		log.Fatalf("Failed to initialize application: %v", err)
+		return err
 	}
 
-	// Get word and language from command line arguments
-	if len(os.Args) < 2 {
-		fmt.Println("Usage: go run cmd/console/main.go <word> [language]")
-		fmt.Println("Example: go run cmd/console/main.go Haus en")
-		os.Exit(1)
+	handler, err := a.handler(cmd.Context())
+	if err != nil {
+		return err
 	}
 
-	word := os.Args[1]
-	language := "en"
-	if len(os.Args) > 2 {
-		language = os.Args[2]
+	response, err := handler.Lookup(cmd.Context(), word, language, format)
+	if err != nil {
+		return fmt.Errorf("failed to process request: %w", err)
 	}
 
-	// Process request
-	response, err := appContainer.ConsoleHandler.ProcessRequest(ctx, word, language)
+	fmt.Fprintln(cmd.OutOrStdout(), response)
+	return nil
+}
+
+func runREPL(cmd *cobra.Command, a *app, language, formatName string) error {
+	format, err := console.ParseFormat(formatName)
 	if err != nil {
-		log.Fatalf("Failed to process request: %v", err)
+		return err
+	}
+
+	handler, err := a.handler(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	return handler.RunREPL(cmd.Context(), language, format, historyPath())
+}
+
+// historyPath returns ~/.gab_history, or no history when the home directory is unknown
+func historyPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(home, ".gab_history")
+}
+
+// colorTerminal reports whether stdout is a terminal and NO_COLOR is not set
+func colorTerminal() bool {
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return false
+	}
+	info, err := os.Stdout.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+func formatUsage() string {
+	names := make([]string, 0, len(console.Formats))
+	for _, format := range console.Formats {
+		names = append(names, string(format))
 	}
 
-	fmt.Println(response)
+	return "output format: " + strings.Join(names, ", ")
 }
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// loadtest sends article lookups to a running instance at a constant rate and reports the latency
+// percentiles. Start the instance with AI_PROVIDER=mock, so the run measures the service and not Gemini.
+// The requests are sent on schedule whatever the latency of the previous ones, so a slow instance shows up
+// as growing latencies instead of a lower rate.
+func main() {
+	target := flag.String("url", "http://localhost:8080/article", "article endpoint receiving the lookups")
+	rps := flag.Int("rps", 50, "lookups per second")
+	duration := flag.Duration("duration", 10*time.Second, "length of the run")
+	words := flag.String("words", "Haus,Katze,See", "comma-separated words looked up in turn")
+	language := flag.String("language", "en", "language of the translations")
+	token := flag.String("token", "", "bearer token of the lookups, for instances requiring one")
+	timeout := flag.Duration("timeout", 10*time.Second, "timeout of a single lookup")
+	maxInFlight := flag.Int("max-in-flight", 1000, "lookups waiting for an answer before new ones are skipped")
+	maxP99 := flag.Duration("max-p99", 0, "fail when the p99 latency is higher, 0 only reports it")
+	flag.Parse()
+
+	if *rps <= 0 || *duration <= 0 {
+		log.Fatalf("The rate and the duration must be positive")
+	}
+	lookups := strings.Split(*words, ",")
+	client := &http.Client{
+		Timeout:   *timeout,
+		Transport: &http.Transport{MaxIdleConnsPerHost: *maxInFlight, IdleConnTimeout: 90 * time.Second},
+	}
+
+	var (
+		mu        sync.Mutex
+		latencies = make([]time.Duration, 0, *rps*int(duration.Seconds()+1))
+		statuses  = make(map[string]int)
+		wg        sync.WaitGroup
+		skipped   int
+	)
+	inFlight := make(chan struct{}, *maxInFlight)
+	ticker := time.NewTicker(time.Second / time.Duration(*rps))
+	defer ticker.Stop()
+
+	start := time.Now()
+	for i := 0; time.Since(start) < *duration; i++ {
+		<-ticker.C
+		select {
+		case inFlight <- struct{}{}:
+		default:
+			skipped++
+			continue
+		}
+
+		wg.Add(1)
+		go func(word string) {
+			defer wg.Done()
+			defer func() { <-inFlight }()
+
+			latency, status := lookup(client, *target, strings.TrimSpace(word), *language, *token)
+			mu.Lock()
+			defer mu.Unlock()
+			statuses[status]++
+			if status == "200" {
+				latencies = append(latencies, latency)
+			}
+		}(lookups[i%len(lookups)])
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	var sent int
+	codes := make([]string, 0, len(statuses))
+	for code, count := range statuses {
+		codes = append(codes, code)
+		sent += count
+	}
+	sort.Strings(codes)
+
+	fmt.Printf("%d lookups in %s, %.1f per second, %d skipped\n", sent, elapsed.Round(time.Millisecond), float64(sent)/elapsed.Seconds(), skipped)
+	for _, code := range codes {
+		fmt.Printf("  %-8s %d\n", code, statuses[code])
+	}
+	if len(latencies) == 0 {
+		fmt.Println("FAIL no lookup succeeded")
+		os.Exit(1)
+	}
+	fmt.Printf("%10s %10s %10s %10s\n", "p50", "p90", "p99", "max")
+	fmt.Printf("%10s %10s %10s %10s\n", percentile(latencies, 50), percentile(latencies, 90), percentile(latencies, 99), percentile(latencies, 100))
+
+	failed := sent - statuses["200"]
+	if failed > 0 {
+		fmt.Printf("FAIL %d of %d lookups didn't succeed\n", failed, sent)
+		os.Exit(1)
+	}
+	if *maxP99 > 0 && percentile(latencies, 99) > *maxP99 {
+		fmt.Printf("FAIL the p99 latency is higher than %s\n", *maxP99)
+		os.Exit(1)
+	}
+}
+
+// lookup sends one lookup and returns its latency up to the end of the body and its status code, or the
+// kind of the failure of lookups without an answer
+func lookup(client *http.Client, target, word, language, token string) (time.Duration, string) {
+	query := url.Values{"word": {word}}
+	request, err := http.NewRequestWithContext(context.Background(), http.MethodGet, target+"?"+query.Encode(), nil)
+	if err != nil {
+		log.Fatalf("Invalid URL %s: %v", target, err)
+	}
+	request.Header.Set("Accept-Language", language)
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	start := time.Now()
+	response, err := client.Do(request)
+	if err != nil {
+		if os.IsTimeout(err) {
+			return 0, "timeout"
+		}
+		return 0, "error"
+	}
+	_, _ = io.Copy(io.Discard, response.Body)
+	_ = response.Body.Close()
+
+	return time.Since(start), fmt.Sprint(response.StatusCode)
+}
+
+// percentile returns the latency below which p percent of the samples are
+func percentile(samples []time.Duration, p int) time.Duration {
+	sorted := slices.Clone(samples)
+	slices.Sort(sorted)
+	index := (len(sorted)*p+99)/100 - 1
+
+	return sorted[max(index, 0)]
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/container"
+	"log"
+	"os"
+	"os/signal"
+)
+
+func main() {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	// Stdout carries the protocol messages, so local logs are redirected to stderr
+	protocol := os.Stdout
+	os.Stdout = os.Stderr
+	log.SetOutput(os.Stderr)
+
+	appContainer, err := container.NewContainer(ctx)
+	if err != nil {
+		log.Fatalf("Failed to initialize application: %v", err)
+	}
+	defer func() {
+		_ = appContainer.Tracer.Flush(context.WithoutCancel(ctx))
+		_ = appContainer.Logger.Flush(context.WithoutCancel(ctx))
+	}()
+
+	if err := appContainer.MCPServer.ServeStdio(ctx, os.Stdin, protocol); err != nil && ctx.Err() == nil {
+		log.Printf("MCP server stopped: %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/config"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/container"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/storage/migration"
+	"log"
+	"os"
+	"os/signal"
+)
+
+// migrate applies the missing migrations of the schema of the configured storage backend, the same
+// environment as the deployed instances selects the backend. With -status it only reports the version.
+func main() {
+	statusOnly := flag.Bool("status", false, "report the schema version without migrating")
+	flag.Parse()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+	migrator, err := container.NewMigrator(ctx, cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect to the %s storage: %v", cfg.Storage, err)
+	}
+	if migrator == nil {
+		log.Printf("The %s storage has no schema to migrate", cfg.Storage)
+		return
+	}
+
+	var status migration.Status
+	if *statusOnly {
+		status, err = migrator.Status(ctx)
+	} else {
+		status, err = migrator.Up(ctx)
+	}
+	if err != nil {
+		log.Fatalf("Failed to migrate the %s storage: %v", cfg.Storage, err)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(status); err != nil {
+		log.Fatalf("Failed to write the status: %v", err)
+	}
+	if !status.Current() {
+		os.Exit(1)
+	}
+}
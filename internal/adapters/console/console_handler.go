@@ -4,42 +4,91 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/adapters/presenter"
 	"github.com/DeryabinSergey/germanarticlebot/internal/application/usecases"
 	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
 	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
 	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+	"github.com/DeryabinSergey/germanarticlebot/libs/requestid"
 )
 
+// Format is the output format of console lookups
+type Format string
+
+const (
+	FormatJSON     Format = "json"
+	FormatText     Format = "text"
+	FormatTable    Format = "table"
+	FormatMarkdown Format = "markdown"
+)
+
+// Formats lists the supported lookup formats
+var Formats = []Format{FormatJSON, FormatText, FormatTable, FormatMarkdown}
+
+// ParseFormat validates the format name
+func ParseFormat(name string) (Format, error) {
+	for _, format := range Formats {
+		if string(format) == name {
+			return format, nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown format %q, expected one of %v", name, Formats)
+}
+
 // Handler handles console-based interactions for testing
 type Handler struct {
-	useCase *usecases.DetermineArticleUseCase
-	logger  logging.Logger
-	tracer  tracing.Tracer
+	useCase   *usecases.DetermineArticleUseCase
+	importer  *usecases.ImportVocabularyUseCase
+	batch     *usecases.BatchLookupUseCase
+	quiz      *usecases.QuizUseCase
+	presenter *presenter.Console
+	logger    logging.Logger
+	tracer    tracing.Tracer
 }
 
 // NewConsoleHandler creates a new console handler
 func NewConsoleHandler(
 	useCase *usecases.DetermineArticleUseCase,
+	importer *usecases.ImportVocabularyUseCase,
+	batch *usecases.BatchLookupUseCase,
+	quiz *usecases.QuizUseCase,
 	logger logging.Logger,
 	tracer tracing.Tracer,
 ) *Handler {
 	return &Handler{
-		useCase: useCase,
-		logger:  logger,
-		tracer:  tracer,
+		useCase:   useCase,
+		importer:  importer,
+		batch:     batch,
+		quiz:      quiz,
+		presenter: presenter.NewConsole(false),
+		logger:    logger,
+		tracer:    tracer,
 	}
 }
 
+// SetColor enables ANSI colors of the table format, meant for interactive terminals
+func (h *Handler) SetColor(enabled bool) {
+	h.presenter = presenter.NewConsole(enabled)
+}
+
 // ProcessRequest processes a console request and returns JSON response
 func (h *Handler) ProcessRequest(ctx context.Context, word, language string) (string, error) {
+	return h.Lookup(ctx, word, language, FormatJSON)
+}
+
+// Lookup determines the article of the word and renders the response in the format
+func (h *Handler) Lookup(ctx context.Context, word, language string, format Format) (string, error) {
+	ctx = withRequestID(ctx)
+	ctx = usecases.WithRequestContext(ctx, &entities.RequestContext{
+		Adapter:   entities.AdapterConsole,
+		Locale:    language,
+		RequestID: requestid.FromContext(ctx),
+	})
 	spanCtx, span := h.tracer.Start(ctx, "ConsoleHandler.ProcessRequest")
 	defer span.End()
 
-	h.logger.Info(spanCtx, map[string]interface{}{
-		"message":  "Processing console request",
-		"word":     word,
-		"language": language,
-	})
+	h.logger.With(spanCtx).Field("word", word).Field("language", language).Info("Processing console request")
 
 	// Create request entity
 	request := entities.NewArticleRequest(word, language)
@@ -47,14 +96,19 @@ func (h *Handler) ProcessRequest(ctx context.Context, word, language string) (st
 	// Execute a use case
 	response, err := h.useCase.Execute(spanCtx, request)
 	if err != nil {
-		h.logger.Error(spanCtx, map[string]interface{}{
-			"message": "Failed to process console request",
-			"error":   err.Error(),
-			"word":    word,
-		})
+		h.logger.With(spanCtx).Err(err).Field("word", word).Error("Failed to process console request")
 		return "", err
 	}
 
+	switch format {
+	case FormatText:
+		return h.presenter.FormatText(response), nil
+	case FormatTable:
+		return h.presenter.FormatTable(response), nil
+	case FormatMarkdown:
+		return h.presenter.FormatMarkdown(response), nil
+	}
+
 	// Convert to JSON
 	jsonResponse, err := json.MarshalIndent(response, "", "  ")
 	if err != nil {
@@ -63,3 +117,12 @@ func (h *Handler) ProcessRequest(ctx context.Context, word, language string) (st
 
 	return string(jsonResponse), nil
 }
+
+// withRequestID correlates the logs of a console command unless the context is already correlated
+func withRequestID(ctx context.Context) context.Context {
+	if requestid.FromContext(ctx) == "" {
+		return requestid.NewContext(ctx, requestid.New())
+	}
+
+	return ctx
+}
@@ -0,0 +1,73 @@
+package console
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/adapters/presenter"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"io"
+)
+
+// Export looks up the words and writes the vocabulary table as "csv" or "json"
+func (h *Handler) Export(ctx context.Context, words []string, language, format string, out io.Writer) error {
+	ctx = withRequestID(ctx)
+	spanCtx, span := h.tracer.Start(ctx, "ConsoleHandler.Export")
+	defer span.End()
+
+	if err := validateExportFormat(format); err != nil {
+		return err
+	}
+
+	result, err := h.importer.Execute(spanCtx, words, language)
+	if err != nil {
+		return err
+	}
+
+	return writeResult(out, format, result)
+}
+
+// Batch looks up a word list of any size with bounded concurrency, reports the progress to
+// progress and writes the vocabulary table as "csv" or "json". An interrupted batch still
+// writes the words done so far.
+func (h *Handler) Batch(ctx context.Context, words []string, language, format string, concurrency int, out, progress io.Writer) error {
+	ctx = withRequestID(ctx)
+	spanCtx, span := h.tracer.Start(ctx, "ConsoleHandler.Batch")
+	defer span.End()
+
+	if err := validateExportFormat(format); err != nil {
+		return err
+	}
+
+	result, batchErr := h.batch.Execute(spanCtx, words, language, concurrency, func(done, total int) {
+		fmt.Fprintf(progress, "\r%d/%d words processed", done, total)
+	})
+	fmt.Fprintf(progress, "\n%d rows, %d failed words\n", len(result.Rows), len(result.Failures))
+
+	if err := writeResult(out, format, result); err != nil {
+		return err
+	}
+	if batchErr != nil {
+		return fmt.Errorf("batch interrupted: %w", batchErr)
+	}
+
+	return nil
+}
+
+func validateExportFormat(format string) error {
+	if format != "csv" && format != "json" {
+		return fmt.Errorf("unknown export format %q, expected csv or json", format)
+	}
+
+	return nil
+}
+
+func writeResult(out io.Writer, format string, result *entities.ImportResult) error {
+	if format == "json" {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	}
+
+	return presenter.NewCSV().FormatImport(out, result)
+}
@@ -0,0 +1,73 @@
+package console
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"io"
+	"strings"
+)
+
+// Prompter shows the prompt and returns the line entered by the user, io.EOF ends the input
+type Prompter func(prompt string) (string, error)
+
+// NewPrompter creates a prompter reading lines from in and writing prompts to out
+func NewPrompter(in io.Reader, out io.Writer) Prompter {
+	scanner := bufio.NewScanner(in)
+	return func(prompt string) (string, error) {
+		fmt.Fprint(out, prompt)
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				return "", err
+			}
+			return "", io.EOF
+		}
+		return scanner.Text(), nil
+	}
+}
+
+// RunQuiz asks the articles of random dictionary nouns of the level until the rounds are over or
+// the user quits, zero rounds ask until the user quits. It returns the number of correct answers.
+func (h *Handler) RunQuiz(ctx context.Context, prompt Prompter, out io.Writer, rounds int, level entities.Level) (int, error) {
+	ctx = withRequestID(ctx)
+	spanCtx, span := h.tracer.Start(ctx, "ConsoleHandler.RunQuiz")
+	defer span.End()
+
+	fmt.Fprintln(out, "Type der, die or das. An empty line or q quits.")
+
+	var asked, correct int
+	for rounds <= 0 || asked < rounds {
+		question, err := h.quiz.Execute(spanCtx, level)
+		if err != nil {
+			return correct, err
+		}
+
+		label := question.Noun
+		if question.Level != "" {
+			label += fmt.Sprintf(" (%s)", question.Level)
+		}
+		answer, err := prompt(fmt.Sprintf("%d. ___ %s? ", asked+1, label))
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return correct, err
+		}
+		if answer = strings.TrimSpace(answer); answer == "" || answer == "q" {
+			break
+		}
+
+		asked++
+		if question.Check(answer) {
+			correct++
+			fmt.Fprintf(out, "✔ Richtig: %s %s\n", question.Article, question.Noun)
+		} else {
+			fmt.Fprintf(out, "✘ Falsch: %s %s\n", question.Article, question.Noun)
+		}
+	}
+
+	fmt.Fprintf(out, "Score: %d of %d\n", correct, asked)
+	return correct, nil
+}
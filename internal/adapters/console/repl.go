@@ -0,0 +1,112 @@
+package console
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/peterh/liner"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const replHelp = `Type a German noun to look it up, or a command:
+  :lang <code>      answer language, e.g. :lang ru
+  :format <name>    output format, json, text, table or markdown
+  :quiz [rounds] [level]
+                    article quiz with dictionary nouns, level is A1 to C2
+  :help             this help
+  :quit             exit (or Ctrl+D)`
+
+// RunREPL reads words and commands interactively until the user quits, the line history
+// is kept in historyPath when it is not empty
+func (h *Handler) RunREPL(ctx context.Context, language string, format Format, historyPath string) error {
+	line := liner.NewLiner()
+	defer line.Close()
+	line.SetCtrlCAborts(true)
+
+	if historyPath != "" {
+		if history, err := os.Open(historyPath); err == nil {
+			_, _ = line.ReadHistory(history)
+			_ = history.Close()
+		}
+		defer func() {
+			if history, err := os.Create(historyPath); err == nil {
+				_, _ = line.WriteHistory(history)
+				_ = history.Close()
+			}
+		}()
+	}
+
+	prompt := func(p string) (string, error) {
+		input, err := line.Prompt(p)
+		if errors.Is(err, liner.ErrPromptAborted) {
+			return "", io.EOF
+		}
+		return input, err
+	}
+
+	fmt.Println(replHelp)
+	for {
+		input, err := prompt(fmt.Sprintf("gab [%s]> ", language))
+		if errors.Is(err, io.EOF) {
+			fmt.Println()
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if input = strings.TrimSpace(input); input == "" {
+			continue
+		}
+		line.AppendHistory(input)
+
+		command, argument, _ := strings.Cut(input, " ")
+		argument = strings.TrimSpace(argument)
+		switch command {
+		case ":quit", ":q", ":exit":
+			return nil
+		case ":help", ":h":
+			fmt.Println(replHelp)
+		case ":lang":
+			if argument == "" {
+				fmt.Println("Usage: :lang <code>")
+				continue
+			}
+			language = argument
+		case ":format":
+			parsed, err := ParseFormat(argument)
+			if err != nil {
+				fmt.Println(err)
+				continue
+			}
+			format = parsed
+		case ":quiz":
+			var rounds int
+			var level entities.Level
+			for _, field := range strings.Fields(argument) {
+				if parsed, ok := entities.ParseLevel(field); ok {
+					level = parsed
+				} else {
+					rounds, _ = strconv.Atoi(field)
+				}
+			}
+			if _, err := h.RunQuiz(ctx, prompt, os.Stdout, rounds, level); err != nil {
+				fmt.Println("Quiz failed:", err)
+			}
+		default:
+			if strings.HasPrefix(command, ":") {
+				fmt.Printf("Unknown command %s, type :help for the list\n", command)
+				continue
+			}
+			output, err := h.Lookup(ctx, input, language, format)
+			if err != nil {
+				fmt.Println("Lookup failed:", err)
+				continue
+			}
+			fmt.Println(output)
+		}
+	}
+}
@@ -0,0 +1,668 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// byteOrderMark is ignored like whitespace
+const byteOrderMark = "\uFEFF"
+
+// maxDepth bounds the nesting of selection sets and values, the parser recurses into every level
+const maxDepth = 32
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenPunctuator
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+type selectionKind int
+
+const (
+	selectionField selectionKind = iota
+	selectionFragmentSpread
+	selectionInlineFragment
+)
+
+// selection is a field, a fragment spread or an inline fragment of a selection set
+type selection struct {
+	Kind          selectionKind
+	Alias         string
+	Name          string
+	Arguments     []argument
+	Directives    []directive
+	Selections    []selection
+	TypeCondition string
+}
+
+type argument struct {
+	Name  string
+	Value interface{}
+}
+
+type directive struct {
+	Name      string
+	Arguments []argument
+}
+
+// variable is a reference to an operation variable in a value
+type variable string
+
+type variableDefinition struct {
+	Name       string
+	Required   bool
+	Default    interface{}
+	HasDefault bool
+}
+
+type operation struct {
+	Type       string
+	Name       string
+	Variables  []variableDefinition
+	Selections []selection
+}
+
+type fragment struct {
+	TypeCondition string
+	Selections    []selection
+}
+
+// document is a parsed GraphQL request document
+type document struct {
+	Operations []operation
+	Fragments  map[string]fragment
+}
+
+type parser struct {
+	source string
+	pos    int
+	token  token
+	// depth is the nesting of the selection set or value being read
+	depth int
+}
+
+// parse reads the executable definitions of the query, schema definitions are not accepted
+func parse(source string) (*document, error) {
+	p := &parser{source: source}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	doc := &document{Fragments: make(map[string]fragment)}
+	for p.token.kind != tokenEOF {
+		if p.peekName("fragment") {
+			name, definition, err := p.parseFragment()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := doc.Fragments[name]; ok {
+				return nil, fmt.Errorf("there can be only one fragment named %q", name)
+			}
+			doc.Fragments[name] = definition
+			continue
+		}
+
+		op, err := p.parseOperation()
+		if err != nil {
+			return nil, err
+		}
+		doc.Operations = append(doc.Operations, op)
+	}
+	if len(doc.Operations) == 0 {
+		return nil, fmt.Errorf("the document contains no operation")
+	}
+
+	return doc, nil
+}
+
+func (p *parser) parseOperation() (operation, error) {
+	op := operation{Type: "query"}
+	if p.peek("{") {
+		selections, err := p.parseSelectionSet()
+		op.Selections = selections
+		return op, err
+	}
+
+	if p.token.kind != tokenName {
+		return op, p.unexpected()
+	}
+	switch op.Type = p.token.value; op.Type {
+	case "query", "mutation", "subscription":
+	default:
+		return op, p.unexpected()
+	}
+	if err := p.advance(); err != nil {
+		return op, err
+	}
+	if p.token.kind == tokenName {
+		op.Name = p.token.value
+		if err := p.advance(); err != nil {
+			return op, err
+		}
+	}
+
+	if p.peek("(") {
+		variables, err := p.parseVariableDefinitions()
+		if err != nil {
+			return op, err
+		}
+		op.Variables = variables
+	}
+	if _, err := p.parseDirectives(); err != nil {
+		return op, err
+	}
+
+	selections, err := p.parseSelectionSet()
+	op.Selections = selections
+	return op, err
+}
+
+func (p *parser) parseFragment() (string, fragment, error) {
+	var definition fragment
+	if err := p.advance(); err != nil {
+		return "", definition, err
+	}
+	name, err := p.expectName()
+	if err != nil {
+		return "", definition, err
+	}
+	if name == "on" {
+		return "", definition, fmt.Errorf("fragment cannot be named \"on\"")
+	}
+	if !p.peekName("on") {
+		return "", definition, p.unexpected()
+	}
+	if err := p.advance(); err != nil {
+		return "", definition, err
+	}
+	if definition.TypeCondition, err = p.expectName(); err != nil {
+		return "", definition, err
+	}
+	if _, err := p.parseDirectives(); err != nil {
+		return "", definition, err
+	}
+
+	definition.Selections, err = p.parseSelectionSet()
+	return name, definition, err
+}
+
+func (p *parser) parseVariableDefinitions() ([]variableDefinition, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+
+	var definitions []variableDefinition
+	for !p.peek(")") {
+		if err := p.expect("$"); err != nil {
+			return nil, err
+		}
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		required, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+
+		definition := variableDefinition{Name: name, Required: required}
+		if p.peek("=") {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			if definition.Default, err = p.parseValue(true); err != nil {
+				return nil, err
+			}
+			definition.HasDefault = true
+		}
+		if _, err := p.parseDirectives(); err != nil {
+			return nil, err
+		}
+		definitions = append(definitions, definition)
+	}
+
+	return definitions, p.advance()
+}
+
+// parseType skips a type reference, required is set for non-null types
+func (p *parser) parseType() (required bool, err error) {
+	if p.peek("[") {
+		if err := p.advance(); err != nil {
+			return false, err
+		}
+		if _, err := p.parseType(); err != nil {
+			return false, err
+		}
+		if err := p.expect("]"); err != nil {
+			return false, err
+		}
+	} else if _, err := p.expectName(); err != nil {
+		return false, err
+	}
+
+	if p.peek("!") {
+		return true, p.advance()
+	}
+
+	return false, nil
+}
+
+func (p *parser) parseSelectionSet() ([]selection, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+
+	var selections []selection
+	for !p.peek("}") {
+		item, err := p.parseSelection()
+		if err != nil {
+			return nil, err
+		}
+		selections = append(selections, item)
+	}
+	if len(selections) == 0 {
+		return nil, fmt.Errorf("selection set at %d is empty", p.token.pos)
+	}
+
+	return selections, p.advance()
+}
+
+func (p *parser) parseSelection() (selection, error) {
+	var item selection
+	if p.peek("...") {
+		if err := p.advance(); err != nil {
+			return item, err
+		}
+
+		if p.token.kind == tokenName && p.token.value != "on" {
+			item.Kind, item.Name = selectionFragmentSpread, p.token.value
+			if err := p.advance(); err != nil {
+				return item, err
+			}
+			var err error
+			item.Directives, err = p.parseDirectives()
+			return item, err
+		}
+
+		item.Kind = selectionInlineFragment
+		if p.peekName("on") {
+			if err := p.advance(); err != nil {
+				return item, err
+			}
+			condition, err := p.expectName()
+			if err != nil {
+				return item, err
+			}
+			item.TypeCondition = condition
+		}
+		var err error
+		if item.Directives, err = p.parseDirectives(); err != nil {
+			return item, err
+		}
+		item.Selections, err = p.parseSelectionSet()
+		return item, err
+	}
+
+	name, err := p.expectName()
+	if err != nil {
+		return item, err
+	}
+	item.Kind, item.Name = selectionField, name
+	if p.peek(":") {
+		if err := p.advance(); err != nil {
+			return item, err
+		}
+		if item.Name, err = p.expectName(); err != nil {
+			return item, err
+		}
+		item.Alias = name
+	}
+	if item.Arguments, err = p.parseArguments(); err != nil {
+		return item, err
+	}
+	if item.Directives, err = p.parseDirectives(); err != nil {
+		return item, err
+	}
+	if p.peek("{") {
+		item.Selections, err = p.parseSelectionSet()
+	}
+
+	return item, err
+}
+
+func (p *parser) parseArguments() ([]argument, error) {
+	if !p.peek("(") {
+		return nil, nil
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	var arguments []argument
+	for !p.peek(")") {
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		value, err := p.parseValue(false)
+		if err != nil {
+			return nil, err
+		}
+		arguments = append(arguments, argument{Name: name, Value: value})
+	}
+
+	return arguments, p.advance()
+}
+
+func (p *parser) parseDirectives() ([]directive, error) {
+	var directives []directive
+	for p.peek("@") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		arguments, err := p.parseArguments()
+		if err != nil {
+			return nil, err
+		}
+		directives = append(directives, directive{Name: name, Arguments: arguments})
+	}
+
+	return directives, nil
+}
+
+// parseValue reads a literal or a variable, constant values like variable defaults can't refer to variables
+func (p *parser) parseValue(constant bool) (interface{}, error) {
+	current := p.token
+	switch current.kind {
+	case tokenInt:
+		value, err := strconv.ParseInt(current.value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer %s at %d", current.value, current.pos)
+		}
+		return value, p.advance()
+
+	case tokenFloat:
+		value, err := strconv.ParseFloat(current.value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %s at %d", current.value, current.pos)
+		}
+		return value, p.advance()
+
+	case tokenString:
+		return current.value, p.advance()
+
+	case tokenName:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		switch current.value {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+		// Enum values are passed on as strings
+		return current.value, nil
+
+	case tokenPunctuator:
+		switch current.value {
+		case "$":
+			if constant {
+				return nil, p.unexpected()
+			}
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			name, err := p.expectName()
+			return variable(name), err
+
+		case "[":
+			if err := p.enter(); err != nil {
+				return nil, err
+			}
+			defer p.leave()
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			values := []interface{}{}
+			for !p.peek("]") {
+				value, err := p.parseValue(constant)
+				if err != nil {
+					return nil, err
+				}
+				values = append(values, value)
+			}
+			return values, p.advance()
+
+		case "{":
+			if err := p.enter(); err != nil {
+				return nil, err
+			}
+			defer p.leave()
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			values := map[string]interface{}{}
+			for !p.peek("}") {
+				name, err := p.expectName()
+				if err != nil {
+					return nil, err
+				}
+				if err := p.expect(":"); err != nil {
+					return nil, err
+				}
+				if values[name], err = p.parseValue(constant); err != nil {
+					return nil, err
+				}
+			}
+			return values, p.advance()
+		}
+	}
+
+	return nil, p.unexpected()
+}
+
+// enter descends into a nested selection set or value, documents nested deeper than maxDepth are rejected
+func (p *parser) enter() error {
+	if p.depth++; p.depth > maxDepth {
+		return fmt.Errorf("the document at %d is nested deeper than %d levels", p.token.pos, maxDepth)
+	}
+
+	return nil
+}
+
+func (p *parser) leave() {
+	p.depth--
+}
+
+func (p *parser) peek(punctuator string) bool {
+	return p.token.kind == tokenPunctuator && p.token.value == punctuator
+}
+
+func (p *parser) peekName(name string) bool {
+	return p.token.kind == tokenName && p.token.value == name
+}
+
+func (p *parser) expect(punctuator string) error {
+	if !p.peek(punctuator) {
+		return p.unexpected()
+	}
+
+	return p.advance()
+}
+
+func (p *parser) expectName() (string, error) {
+	if p.token.kind != tokenName {
+		return "", p.unexpected()
+	}
+	name := p.token.value
+
+	return name, p.advance()
+}
+
+func (p *parser) unexpected() error {
+	if p.token.kind == tokenEOF {
+		return fmt.Errorf("unexpected end of the document")
+	}
+
+	return fmt.Errorf("unexpected %q at %d", p.token.value, p.token.pos)
+}
+
+// advance reads the next token, whitespace, commas and comments are ignored
+func (p *parser) advance() error {
+	for p.pos < len(p.source) {
+		switch c := p.source[p.pos]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			p.pos++
+		case c == '#':
+			for p.pos < len(p.source) && p.source[p.pos] != '\n' && p.source[p.pos] != '\r' {
+				p.pos++
+			}
+		case strings.HasPrefix(p.source[p.pos:], byteOrderMark):
+			p.pos += len(byteOrderMark)
+		default:
+			return p.readToken()
+		}
+	}
+
+	p.token = token{kind: tokenEOF, pos: p.pos}
+	return nil
+}
+
+func (p *parser) readToken() error {
+	start := p.pos
+	c := p.source[start]
+
+	switch {
+	case strings.HasPrefix(p.source[start:], "..."):
+		p.pos += 3
+		p.token = token{kind: tokenPunctuator, value: "...", pos: start}
+
+	case strings.IndexByte("!$():=@[]{}|&", c) >= 0:
+		p.pos++
+		p.token = token{kind: tokenPunctuator, value: string(c), pos: start}
+
+	case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+		for p.pos < len(p.source) && isNameChar(p.source[p.pos]) {
+			p.pos++
+		}
+		p.token = token{kind: tokenName, value: p.source[start:p.pos], pos: start}
+
+	case c == '-' || c >= '0' && c <= '9':
+		return p.readNumber()
+
+	case c == '"':
+		return p.readString()
+
+	default:
+		r, _ := utf8.DecodeRuneInString(p.source[start:])
+		return fmt.Errorf("unexpected character %q at %d", r, start)
+	}
+
+	return nil
+}
+
+func (p *parser) readNumber() error {
+	start := p.pos
+	kind := tokenInt
+	if p.source[p.pos] == '-' {
+		p.pos++
+	}
+	p.skipDigits()
+	if p.pos < len(p.source) && p.source[p.pos] == '.' {
+		kind = tokenFloat
+		p.pos++
+		p.skipDigits()
+	}
+	if p.pos < len(p.source) && (p.source[p.pos] == 'e' || p.source[p.pos] == 'E') {
+		kind = tokenFloat
+		p.pos++
+		if p.pos < len(p.source) && (p.source[p.pos] == '+' || p.source[p.pos] == '-') {
+			p.pos++
+		}
+		p.skipDigits()
+	}
+	if p.pos < len(p.source) && isNameChar(p.source[p.pos]) {
+		return fmt.Errorf("invalid number at %d", start)
+	}
+
+	p.token = token{kind: kind, value: p.source[start:p.pos], pos: start}
+	return nil
+}
+
+func (p *parser) skipDigits() {
+	for p.pos < len(p.source) && p.source[p.pos] >= '0' && p.source[p.pos] <= '9' {
+		p.pos++
+	}
+}
+
+// readString reads a quoted or a block string, the escapes of quoted strings are the ones of JSON
+func (p *parser) readString() error {
+	start := p.pos
+	if strings.HasPrefix(p.source[start:], `"""`) {
+		end := strings.Index(p.source[start+3:], `"""`)
+		if end < 0 {
+			return fmt.Errorf("unterminated string at %d", start)
+		}
+		p.pos = start + 3 + end + 3
+		value := strings.ReplaceAll(p.source[start+3:start+3+end], `\"""`, `"""`)
+		p.token = token{kind: tokenString, value: strings.TrimSpace(value), pos: start}
+		return nil
+	}
+
+	p.pos++
+	for p.pos < len(p.source) {
+		switch p.source[p.pos] {
+		case '\\':
+			p.pos += 2
+		case '\n', '\r':
+			return fmt.Errorf("unterminated string at %d", start)
+		case '"':
+			p.pos++
+			value, err := strconv.Unquote(p.source[start:p.pos])
+			if err != nil {
+				return fmt.Errorf("invalid string at %d", start)
+			}
+			p.token = token{kind: tokenString, value: value, pos: start}
+			return nil
+		default:
+			p.pos++
+		}
+	}
+
+	return fmt.Errorf("unterminated string at %d", start)
+}
+
+func isNameChar(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
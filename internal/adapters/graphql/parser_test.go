@@ -0,0 +1,109 @@
+package graphql
+
+import (
+	"strings"
+	"testing"
+)
+
+// nested wraps the innermost text into depth levels of the opening and closing strings
+func nested(open, inner, closing string, depth int) string {
+	return strings.Repeat(open, depth) + inner + strings.Repeat(closing, depth)
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		wantErr string
+	}{
+		{name: "shorthand query", query: `{ word(word: "Haus") { success } }`},
+		{name: "named query", query: `query Lookup { word(word: "Haus") { success } }`},
+		{name: "variables with default", query: `query ($w: String!, $l: String = "en") { word(word: $w, lang: $l) { success } }`},
+		{name: "list type", query: `query ($w: [String!]!) { word(word: "Haus") { success } }`},
+		{name: "alias", query: `{ house: word(word: "Haus") { success } }`},
+		{name: "fragment", query: `fragment F on ArticleResponse { success } { word(word: "Haus") { ...F } }`},
+		{name: "inline fragment", query: `{ word(word: "Haus") { ... on ArticleResponse { success } } }`},
+		{name: "directive", query: `{ word(word: "Haus") @include(if: true) { success } }`},
+		{name: "values", query: `{ word(word: "Haus", a: 1, b: -1.5e3, c: null, d: [1, [2]], e: {f: true}, g: ENUM) { success } }`},
+		{name: "block string", query: `{ word(word: """ Haus """) { success } }`},
+		{name: "comments and commas", query: "# lookup\n{ word(word: \"Haus\"),, { success } }"},
+		{name: "byte order mark", query: byteOrderMark + "{ word(word: \"Haus\") { success } }"},
+		{name: "selection sets at the depth limit", query: nested("{ a ", "", "}", maxDepth)},
+		{name: "values at the depth limit", query: `{ word(word: ` + nested("[", "1", "]", maxDepth-1) + `) { success } }`},
+
+		{name: "empty document", query: "", wantErr: "the document contains no operation"},
+		{name: "only fragments", query: `fragment F on Query { word }`, wantErr: "the document contains no operation"},
+		{name: "unclosed selection set", query: `{ word(word: "Haus") { success }`, wantErr: "unexpected end of the document"},
+		{name: "empty selection set", query: `{ }`, wantErr: "selection set at 2 is empty"},
+		{name: "unclosed arguments", query: `{ word(word: "Haus" { success } }`, wantErr: `unexpected "{"`},
+		{name: "unterminated string", query: `{ word(word: "Haus) { success } }`, wantErr: "unterminated string"},
+		{name: "string across lines", query: "{ word(word: \"Ha\nus\") { success } }", wantErr: "unterminated string"},
+		{name: "unterminated block string", query: `{ word(word: """Haus) { success } }`, wantErr: "unterminated string"},
+		{name: "invalid escape", query: `{ word(word: "Ha\qus") { success } }`, wantErr: "invalid string"},
+		{name: "invalid number", query: `{ word(word: 12ab) { success } }`, wantErr: "invalid number"},
+		{name: "unexpected character", query: `{ word(word: "Haus") { success } ; }`, wantErr: "unexpected character ';'"},
+		{name: "unknown operation type", query: `select { word }`, wantErr: `unexpected "select"`},
+		{name: "missing variable type", query: `query ($w: ) { word }`, wantErr: `unexpected ")"`},
+		{name: "variable in default value", query: `query ($w: String = $x) { word }`, wantErr: `unexpected "$"`},
+		{name: "duplicate fragment", query: `fragment F on Query { word } fragment F on Query { word } { ...F }`, wantErr: `only one fragment named "F"`},
+		{name: "fragment named on", query: `fragment on on Query { word } { word }`, wantErr: `fragment cannot be named "on"`},
+		{name: "fragment without type condition", query: `fragment F { word } { word }`, wantErr: `unexpected "{"`},
+		{name: "selection sets too deep", query: nested("{ a ", "", "}", maxDepth+1), wantErr: "nested deeper than 32 levels"},
+		{name: "lists too deep", query: `{ word(word: ` + nested("[", "1", "]", maxDepth) + `) { success } }`, wantErr: "nested deeper than 32 levels"},
+		{name: "objects too deep", query: `{ word(word: ` + nested("{a: ", "1", "}", maxDepth) + `) { success } }`, wantErr: "nested deeper than 32 levels"},
+		{name: "deeply nested list is rejected early", query: `{ word(word: ` + strings.Repeat("[", 100000) + `) }`, wantErr: "nested deeper than 32 levels"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parse(tt.query)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("parse(%q) failed: %v", tt.query, err)
+			case tt.wantErr != "" && err == nil:
+				t.Fatalf("parse(%q) succeeded, want an error containing %q", tt.query, tt.wantErr)
+			case tt.wantErr != "" && !strings.Contains(err.Error(), tt.wantErr):
+				t.Fatalf("parse(%q) failed with %q, want an error containing %q", tt.query, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestParseSelections checks the parsed aliases, arguments and variables of a document
+func TestParseSelections(t *testing.T) {
+	doc, err := parse(`query Lookup($w: String!, $l: String = "de") { house: word(word: $w, lang: $l) { success } stats }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Operations) != 1 {
+		t.Fatalf("got %d operations, want 1", len(doc.Operations))
+	}
+
+	op := doc.Operations[0]
+	if op.Type != "query" || op.Name != "Lookup" {
+		t.Errorf("got the %s operation %q, want the query \"Lookup\"", op.Type, op.Name)
+	}
+	if len(op.Variables) != 2 {
+		t.Fatalf("got %d variables, want 2", len(op.Variables))
+	}
+	if w := op.Variables[0]; w.Name != "w" || !w.Required || w.HasDefault {
+		t.Errorf("got the variable %+v, want the required $w without a default", w)
+	}
+	if l := op.Variables[1]; l.Name != "l" || l.Required || !l.HasDefault || l.Default != "de" {
+		t.Errorf("got the variable %+v, want the optional $l defaulting to \"de\"", l)
+	}
+
+	if len(op.Selections) != 2 {
+		t.Fatalf("got %d selections, want 2", len(op.Selections))
+	}
+	word := op.Selections[0]
+	if word.Alias != "house" || word.Name != "word" {
+		t.Errorf("got the field %q aliased %q, want \"word\" aliased \"house\"", word.Name, word.Alias)
+	}
+	if len(word.Arguments) != 2 || word.Arguments[0].Value != variable("w") || word.Arguments[1].Value != variable("l") {
+		t.Errorf("got the arguments %+v, want the variables $w and $l", word.Arguments)
+	}
+	if stats := op.Selections[1]; stats.Alias != "" || stats.Name != "stats" || stats.Selections != nil {
+		t.Errorf("got the field %+v, want \"stats\" without alias and selections", stats)
+	}
+}
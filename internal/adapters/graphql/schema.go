@@ -0,0 +1,334 @@
+package graphql
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// objectField is a field of a result object
+type objectField struct {
+	Key   string
+	Value interface{}
+}
+
+// object is a result object, its fields keep the order of the selection set as GraphQL requires
+type object []objectField
+
+// MarshalJSON encodes the fields in their order
+func (o object) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteByte('{')
+	for i, field := range o {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+		key, _ := json.Marshal(field.Key)
+		buffer.Write(key)
+		buffer.WriteByte(':')
+		value, err := json.Marshal(field.Value)
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(value)
+	}
+	buffer.WriteByte('}')
+
+	return buffer.Bytes(), nil
+}
+
+// collectedField is a field of a selection set after the fragments are expanded, the selections of
+// fields with the same response key are merged
+type collectedField struct {
+	Key        string
+	Name       string
+	Arguments  []argument
+	Selections []selection
+}
+
+// collectFields expands the fragments of the selection set applying to the type and drops the
+// selections excluded by @skip and @include
+func (e *executor) collectFields(typeName string, selections []selection, fields []*collectedField, visited map[string]bool) ([]*collectedField, error) {
+	for _, item := range selections {
+		included, err := e.included(item.Directives)
+		if err != nil {
+			return nil, err
+		}
+		if !included {
+			continue
+		}
+
+		switch item.Kind {
+		case selectionField:
+			key := item.Name
+			if item.Alias != "" {
+				key = item.Alias
+			}
+			merged := false
+			for _, field := range fields {
+				if field.Key != key {
+					continue
+				}
+				if field.Name != item.Name {
+					return nil, fmt.Errorf("fields %q conflict because %s and %s are different fields", key, field.Name, item.Name)
+				}
+				field.Selections = append(field.Selections, item.Selections...)
+				merged = true
+				break
+			}
+			if !merged {
+				fields = append(fields, &collectedField{Key: key, Name: item.Name, Arguments: item.Arguments, Selections: item.Selections})
+			}
+
+		case selectionFragmentSpread:
+			if visited[item.Name] {
+				continue
+			}
+			definition, ok := e.doc.Fragments[item.Name]
+			if !ok {
+				return nil, fmt.Errorf("unknown fragment %q", item.Name)
+			}
+			if definition.TypeCondition != typeName {
+				return nil, fmt.Errorf("fragment %q cannot be spread on type %q", item.Name, typeName)
+			}
+			visited[item.Name] = true
+			if fields, err = e.collectFields(typeName, definition.Selections, fields, visited); err != nil {
+				return nil, err
+			}
+
+		case selectionInlineFragment:
+			if item.TypeCondition != "" && item.TypeCondition != typeName {
+				return nil, fmt.Errorf("fragment cannot be spread on type %q, it only applies to %q", typeName, item.TypeCondition)
+			}
+			if fields, err = e.collectFields(typeName, item.Selections, fields, visited); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return fields, nil
+}
+
+// included evaluates the @skip and @include directives
+func (e *executor) included(directives []directive) (bool, error) {
+	for _, item := range directives {
+		if item.Name != "skip" && item.Name != "include" {
+			return false, fmt.Errorf("unknown directive @%s", item.Name)
+		}
+
+		arguments, err := e.arguments(item.Arguments)
+		if err != nil {
+			return false, err
+		}
+		condition, ok := arguments["if"].(bool)
+		if !ok {
+			return false, fmt.Errorf("directive @%s requires a boolean \"if\" argument", item.Name)
+		}
+		if condition == (item.Name == "skip") {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// validateSelections checks the selection set against the fields of the result type before anything is executed
+func (e *executor) validateSelections(t reflect.Type, selections []selection) error {
+	t = elementType(t)
+	fields, err := e.collectFields(typeName(t), selections, nil, make(map[string]bool))
+	if err != nil {
+		return err
+	}
+
+	for _, field := range fields {
+		if field.Name == "__typename" {
+			continue
+		}
+		structField, _, ok := jsonField(t, field.Name)
+		if !ok {
+			return fmt.Errorf("cannot query field %q on type %q", field.Name, typeName(t))
+		}
+		if len(field.Arguments) > 0 {
+			return fmt.Errorf("field %q of type %q has no arguments", field.Name, typeName(t))
+		}
+		if err := e.validateField(structField.Type, field); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateField checks that objects have a selection set and scalars don't
+func (e *executor) validateField(t reflect.Type, field *collectedField) error {
+	if isLeaf(elementType(t)) {
+		if len(field.Selections) > 0 {
+			return fmt.Errorf("field %q must not have a selection since type %q has no subfields", field.Name, typeName(elementType(t)))
+		}
+		return nil
+	}
+	if len(field.Selections) == 0 {
+		return fmt.Errorf("field %q of type %q must have a selection of subfields", field.Name, typeName(elementType(t)))
+	}
+
+	return e.validateSelections(t, field.Selections)
+}
+
+// complete projects the value on the validated selection set, values the JSON encoding omits are null
+func (e *executor) complete(value reflect.Value, selections []selection) interface{} {
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+
+	switch {
+	case isLeaf(value.Type()):
+		return value.Interface()
+
+	case value.Kind() == reflect.Slice || value.Kind() == reflect.Array:
+		// Lists the JSON encoding doesn't omit are non-null, so nil slices are empty lists
+		items := make([]interface{}, value.Len())
+		for i := range items {
+			items[i] = e.complete(value.Index(i), selections)
+		}
+		return items
+	}
+
+	// The selection set was validated, so collecting it again can't fail
+	fields, _ := e.collectFields(typeName(value.Type()), selections, nil, make(map[string]bool))
+	result := make(object, 0, len(fields))
+	for _, field := range fields {
+		if field.Name == "__typename" {
+			result = append(result, objectField{Key: field.Key, Value: typeName(value.Type())})
+			continue
+		}
+
+		structField, omitEmpty, _ := jsonField(value.Type(), field.Name)
+		fieldValue := value.FieldByIndex(structField.Index)
+		if omitEmpty && fieldValue.IsZero() {
+			result = append(result, objectField{Key: field.Key})
+			continue
+		}
+		result = append(result, objectField{Key: field.Key, Value: e.complete(fieldValue, field.Selections)})
+	}
+
+	return result
+}
+
+// jsonField finds the struct field with the JSON name, the GraphQL fields are named like the JSON ones
+func jsonField(t reflect.Type, name string) (field reflect.StructField, omitEmpty bool, ok bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field = t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if tag == "-" {
+			continue
+		}
+		if tag == "" {
+			tag = field.Name
+		}
+		if tag == name {
+			return field, strings.Contains(options, "omitempty"), true
+		}
+	}
+
+	return reflect.StructField{}, false, false
+}
+
+// elementType strips pointers and lists off the type
+func elementType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+
+	return t
+}
+
+func isLeaf(t reflect.Type) bool {
+	return t == timeType || t.Kind() != reflect.Struct && t.Kind() != reflect.Slice && t.Kind() != reflect.Array && t.Kind() != reflect.Pointer
+}
+
+// typeName is the GraphQL name of the Go type
+func typeName(t reflect.Type) string {
+	return strings.ToUpper(t.Name()[:1]) + t.Name()[1:]
+}
+
+// scalarName is the GraphQL scalar of a leaf type
+func scalarName(t reflect.Type) string {
+	if t == timeType {
+		return "String"
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return "Boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "Int"
+	case reflect.Float32, reflect.Float64:
+		return "Float"
+	default:
+		return "String"
+	}
+}
+
+// typeDefinitions writes the SDL of the object types reachable from the types, fields without
+// omitempty are non-null
+func typeDefinitions(types ...reflect.Type) string {
+	var sdl strings.Builder
+	defined := make(map[reflect.Type]bool)
+	for len(types) > 0 {
+		t := elementType(types[0])
+		types = types[1:]
+		if isLeaf(t) || defined[t] {
+			continue
+		}
+		defined[t] = true
+
+		fmt.Fprintf(&sdl, "\ntype %s {\n", typeName(t))
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if !field.IsExported() || name == "-" {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			nullable := strings.Contains(options, "omitempty") || field.Type.Kind() == reflect.Pointer
+			fmt.Fprintf(&sdl, "  %s: %s\n", name, typeReference(field.Type, nullable))
+			types = append(types, field.Type)
+		}
+		sdl.WriteString("}\n")
+	}
+
+	return sdl.String()
+}
+
+func typeReference(t reflect.Type, nullable bool) string {
+	var reference string
+	switch {
+	case t.Kind() == reflect.Pointer:
+		return typeReference(t.Elem(), true)
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		reference = "[" + typeReference(t.Elem(), false) + "]"
+	case isLeaf(t):
+		reference = scalarName(t)
+	default:
+		reference = typeName(t)
+	}
+	if !nullable {
+		reference += "!"
+	}
+
+	return reference
+}
@@ -0,0 +1,502 @@
+package graphql
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/application/usecases"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/secrets"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+	"io"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+const (
+	// maxWordFields bounds the lookups of one request, every one of them may be an AI call
+	maxWordFields = 5
+
+	defaultHistoryLimit = 20
+	maxHistoryLimit     = 100
+)
+
+// querySchema is the SDL of the root query type, the object types are generated from the entities
+const querySchema = `type Query {
+  "Article, translation, plural and examples of a German noun, the same lookup as GET /v2/words/{word}"
+  word(word: String!, lang: String = "en", level: String): ArticleResponse
+  "Looked-up words, most looked-up first, requires the admin bearer token"
+  history(prefix: String, first: Int = 20, after: String): WordStatPage
+  "Usage metrics of the admin dashboard, requires the admin bearer token"
+  stats(topWords: Int = 10): DashboardStats
+}
+`
+
+// wordStatPage is a page of the looked-up words, after takes the nextPageToken of the previous page
+type wordStatPage struct {
+	Items         []entities.WordStat `json:"items"`
+	NextPageToken string              `json:"nextPageToken,omitempty"`
+}
+
+// rootTypes are the result types of the root fields
+var rootTypes = map[string]reflect.Type{
+	"word":    reflect.TypeOf(entities.ArticleResponse{}),
+	"history": reflect.TypeOf(wordStatPage{}),
+	"stats":   reflect.TypeOf(entities.DashboardStats{}),
+}
+
+// rootArguments are the arguments the root fields accept
+var rootArguments = map[string][]string{
+	"word":    {"word", "lang", "level"},
+	"history": {"prefix", "first", "after"},
+	"stats":   {"topWords"},
+}
+
+type request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+type response struct {
+	Data   interface{}     `json:"data,omitempty"`
+	Errors []responseError `json:"errors,omitempty"`
+}
+
+type responseError struct {
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path,omitempty"`
+}
+
+// Server answers GraphQL queries, so clients can select exactly the fields they need instead of
+// the full lookup payload. Only queries are supported, there are no mutations or subscriptions.
+type Server struct {
+	lookup    *usecases.DetermineArticleUseCase
+	words     *usecases.ListWordsUseCase
+	dashboard *usecases.AdminDashboardUseCase
+	token     secrets.Source
+	schema    string
+	logger    logging.Logger
+	tracer    tracing.Tracer
+}
+
+// NewServer creates a new GraphQL server, an empty admin token disables the history and stats queries
+func NewServer(
+	lookup *usecases.DetermineArticleUseCase,
+	words *usecases.ListWordsUseCase,
+	dashboard *usecases.AdminDashboardUseCase,
+	token secrets.Source,
+	logger logging.Logger,
+	tracer tracing.Tracer,
+) *Server {
+	return &Server{
+		lookup:    lookup,
+		words:     words,
+		dashboard: dashboard,
+		token:     token,
+		schema:    querySchema + typeDefinitions(rootTypes["word"], rootTypes["history"], rootTypes["stats"]),
+		logger:    logger,
+		tracer:    tracer,
+	}
+}
+
+// HandleHTTP serves queries as GET with the query parameters or as POST with a JSON body, a GET
+// without a query returns the schema in SDL
+func (s *Server) HandleHTTP(w http.ResponseWriter, r *http.Request) {
+	spanCtx, span := s.tracer.Start(r.Context(), "GraphQL Handler")
+	defer span.End()
+
+	var req request
+	switch r.Method {
+	case http.MethodGet:
+		query := r.URL.Query()
+		if req.Query = query.Get("query"); req.Query == "" {
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			_, _ = io.WriteString(w, s.schema)
+			return
+		}
+		req.OperationName = query.Get("operationName")
+		if variables := query.Get("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				writeResponse(w, errorResponse("variables must be a JSON object"), http.StatusBadRequest)
+				return
+			}
+		}
+
+	case http.MethodPost:
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				writeResponse(w, errorResponse("request body too large"), http.StatusRequestEntityTooLarge)
+				return
+			}
+			writeResponse(w, errorResponse("invalid JSON body"), http.StatusBadRequest)
+			return
+		}
+
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		writeResponse(w, errorResponse("method not allowed"), http.StatusMethodNotAllowed)
+		return
+	}
+
+	writeResponse(w, s.execute(spanCtx, r, req), http.StatusOK)
+}
+
+// execute runs the query of the request, the HTTP request carries the credentials of the admin queries
+func (s *Server) execute(ctx context.Context, r *http.Request, req request) *response {
+	spanCtx, span := s.tracer.Start(ctx, "GraphQL Execute")
+	defer span.End()
+
+	doc, err := parse(req.Query)
+	if err != nil {
+		return errorResponse("syntax error: " + err.Error())
+	}
+	op, err := selectOperation(doc, req.OperationName)
+	if err != nil {
+		return errorResponse(err.Error())
+	}
+	if op.Type != "query" {
+		return errorResponse(fmt.Sprintf("%s operations are not supported", op.Type))
+	}
+
+	e := &executor{doc: doc}
+	if e.variables, err = coerceVariables(op.Variables, req.Variables); err != nil {
+		return errorResponse(err.Error())
+	}
+	fields, err := e.validateQuery(op.Selections)
+	if err != nil {
+		return errorResponse(err.Error())
+	}
+
+	data := make(object, 0, len(fields))
+	var errs []responseError
+	for _, field := range fields {
+		value, err := s.resolve(spanCtx, r, e, field)
+		if err != nil {
+			errs = append(errs, responseError{Message: err.Error(), Path: []interface{}{field.Key}})
+		}
+		data = append(data, objectField{Key: field.Key, Value: value})
+	}
+
+	return &response{Data: data, Errors: errs}
+}
+
+// resolve runs the root field and projects its result on the selection set
+func (s *Server) resolve(ctx context.Context, r *http.Request, e *executor, field *collectedField) (interface{}, error) {
+	if field.Name == "__typename" {
+		return "Query", nil
+	}
+
+	arguments, err := e.arguments(field.Arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	var result interface{}
+	switch field.Name {
+	case "word":
+		result, err = s.resolveWord(ctx, arguments)
+	case "history":
+		result, err = s.resolveHistory(ctx, r, arguments)
+	case "stats":
+		result, err = s.resolveStats(ctx, r, arguments)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return e.complete(reflect.ValueOf(result), field.Selections), nil
+}
+
+func (s *Server) resolveWord(ctx context.Context, arguments map[string]interface{}) (interface{}, error) {
+	word, err := stringArgument(arguments, "word", "")
+	if err != nil {
+		return nil, err
+	}
+	language, err := stringArgument(arguments, "lang", "en")
+	if err != nil {
+		return nil, err
+	}
+	levelValue, err := stringArgument(arguments, "level", "")
+	if err != nil {
+		return nil, err
+	}
+
+	articleRequest := entities.NewArticleRequest(word, strings.ToLower(strings.TrimSpace(language)))
+	if !articleRequest.IsValid() {
+		return nil, errors.New("argument \"word\" must not be empty")
+	}
+	if levelValue != "" {
+		level, ok := entities.ParseLevel(levelValue)
+		if !ok {
+			return nil, errors.New("argument \"level\" must be one of A1, A2, B1, B2, C1 or C2")
+		}
+		articleRequest.Level = level
+	}
+
+	result, err := s.lookup.Execute(ctx, articleRequest)
+	if err != nil {
+		s.logger.With(ctx).Err(err).Field("word", articleRequest.Word).Error("GraphQL lookup failed")
+		return nil, errors.New("internal server error")
+	}
+
+	return result, nil
+}
+
+// resolveHistory lists the words looked up by all users, which is dashboard data like the stats
+func (s *Server) resolveHistory(ctx context.Context, r *http.Request, arguments map[string]interface{}) (interface{}, error) {
+	if !s.authorize(ctx, r) {
+		return nil, errors.New("unauthorized, history requires the admin bearer token")
+	}
+
+	prefix, err := stringArgument(arguments, "prefix", "")
+	if err != nil {
+		return nil, err
+	}
+	after, err := stringArgument(arguments, "after", "")
+	if err != nil {
+		return nil, err
+	}
+	first, err := intArgument(arguments, "first", defaultHistoryLimit)
+	if err != nil {
+		return nil, err
+	}
+	if first <= 0 || first > maxHistoryLimit {
+		return nil, fmt.Errorf("argument \"first\" must be between 1 and %d", maxHistoryLimit)
+	}
+
+	page, err := s.words.Execute(ctx, entities.WordStatFilter{
+		Prefix: strings.ToLower(strings.TrimSpace(prefix)),
+		Page:   entities.PageRequest{Limit: first, PageToken: after},
+	})
+	if errors.Is(err, entities.ErrInvalidPageToken) {
+		return nil, errors.New("argument \"after\" is not a valid page token")
+	}
+	if err != nil {
+		return nil, errors.New("internal server error")
+	}
+
+	return wordStatPage{Items: page.Items, NextPageToken: page.NextPageToken}, nil
+}
+
+func (s *Server) resolveStats(ctx context.Context, r *http.Request, arguments map[string]interface{}) (interface{}, error) {
+	if !s.authorize(ctx, r) {
+		return nil, errors.New("unauthorized, stats require the admin bearer token")
+	}
+
+	topWords, err := intArgument(arguments, "topWords", 10)
+	if err != nil {
+		return nil, err
+	}
+	if topWords < 0 || topWords > maxHistoryLimit {
+		return nil, fmt.Errorf("argument \"topWords\" must be between 0 and %d", maxHistoryLimit)
+	}
+
+	stats, err := s.dashboard.Execute(ctx, topWords)
+	if err != nil {
+		return nil, errors.New("internal server error")
+	}
+
+	return stats, nil
+}
+
+// authorize checks the bearer token of the request against the configured admin token
+func (s *Server) authorize(ctx context.Context, r *http.Request) bool {
+	expected, err := s.token(ctx)
+	if err != nil {
+		s.logger.With(ctx).Err(err).Error("Failed to read admin token")
+		return false
+	}
+	if expected == "" {
+		return false
+	}
+
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(token), []byte(expected)) == 1
+}
+
+// executor holds the state of one query execution
+type executor struct {
+	doc       *document
+	variables map[string]interface{}
+}
+
+// validateQuery checks the root selection set, nothing is executed when it fails
+func (e *executor) validateQuery(selections []selection) ([]*collectedField, error) {
+	fields, err := e.collectFields("Query", selections, nil, make(map[string]bool))
+	if err != nil {
+		return nil, err
+	}
+
+	lookups := 0
+	for _, field := range fields {
+		if field.Name == "__typename" {
+			continue
+		}
+		if strings.HasPrefix(field.Name, "__") {
+			return nil, errors.New("introspection is not supported, GET /graphql returns the schema")
+		}
+
+		t, ok := rootTypes[field.Name]
+		if !ok {
+			return nil, fmt.Errorf("cannot query field %q on type \"Query\"", field.Name)
+		}
+		for _, item := range field.Arguments {
+			if !slices.Contains(rootArguments[field.Name], item.Name) {
+				return nil, fmt.Errorf("unknown argument %q on field \"Query.%s\"", item.Name, field.Name)
+			}
+		}
+		if field.Name == "word" {
+			if !slices.ContainsFunc(field.Arguments, func(item argument) bool { return item.Name == "word" }) {
+				return nil, errors.New("field \"Query.word\" argument \"word\" of type \"String!\" is required")
+			}
+			if lookups++; lookups > maxWordFields {
+				return nil, fmt.Errorf("a query can look up at most %d words", maxWordFields)
+			}
+		}
+		if err := e.validateField(t, field); err != nil {
+			return nil, err
+		}
+	}
+
+	return fields, nil
+}
+
+// arguments resolves the variables of the arguments
+func (e *executor) arguments(arguments []argument) (map[string]interface{}, error) {
+	values := make(map[string]interface{}, len(arguments))
+	for _, item := range arguments {
+		value, err := e.value(item.Value)
+		if err != nil {
+			return nil, err
+		}
+		values[item.Name] = value
+	}
+
+	return values, nil
+}
+
+func (e *executor) value(value interface{}) (interface{}, error) {
+	switch value := value.(type) {
+	case variable:
+		resolved, ok := e.variables[string(value)]
+		if !ok {
+			return nil, fmt.Errorf("variable \"$%s\" is not defined", value)
+		}
+		return resolved, nil
+
+	case []interface{}:
+		values := make([]interface{}, len(value))
+		for i, item := range value {
+			resolved, err := e.value(item)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = resolved
+		}
+		return values, nil
+
+	case map[string]interface{}:
+		values := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			resolved, err := e.value(item)
+			if err != nil {
+				return nil, err
+			}
+			values[key] = resolved
+		}
+		return values, nil
+	}
+
+	return value, nil
+}
+
+// selectOperation picks the operation to execute, the name is required when there are several
+func selectOperation(doc *document, name string) (operation, error) {
+	if name == "" {
+		if len(doc.Operations) > 1 {
+			return operation{}, errors.New("operationName is required for documents with several operations")
+		}
+		return doc.Operations[0], nil
+	}
+
+	for _, op := range doc.Operations {
+		if op.Name == name {
+			return op, nil
+		}
+	}
+
+	return operation{}, fmt.Errorf("unknown operation %q", name)
+}
+
+// coerceVariables applies the defaults of the variable definitions to the provided values
+func coerceVariables(definitions []variableDefinition, provided map[string]interface{}) (map[string]interface{}, error) {
+	variables := make(map[string]interface{}, len(definitions))
+	for _, definition := range definitions {
+		value, ok := provided[definition.Name]
+		switch {
+		case ok:
+			variables[definition.Name] = value
+		case definition.HasDefault:
+			variables[definition.Name] = definition.Default
+		case definition.Required:
+			return nil, fmt.Errorf("variable \"$%s\" of required type was not provided", definition.Name)
+		}
+		if value == nil && ok && definition.Required {
+			return nil, fmt.Errorf("variable \"$%s\" of required type must not be null", definition.Name)
+		}
+	}
+
+	return variables, nil
+}
+
+// stringArgument reads a string argument, null and missing arguments take the default
+func stringArgument(arguments map[string]interface{}, name, defaultValue string) (string, error) {
+	value, ok := arguments[name]
+	if !ok || value == nil {
+		return defaultValue, nil
+	}
+
+	text, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("argument %q must be a string", name)
+	}
+
+	return text, nil
+}
+
+// intArgument reads an integer argument, variables are decoded from JSON as float64
+func intArgument(arguments map[string]interface{}, name string, defaultValue int) (int, error) {
+	switch value := arguments[name].(type) {
+	case nil:
+		return defaultValue, nil
+	case int64:
+		return int(value), nil
+	case float64:
+		if value == float64(int(value)) {
+			return int(value), nil
+		}
+	}
+
+	return 0, fmt.Errorf("argument %q must be an integer", name)
+}
+
+func errorResponse(message string) *response {
+	return &response{Errors: []responseError{{Message: message}}}
+}
+
+func writeResponse(w http.ResponseWriter, data *response, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(data)
+}
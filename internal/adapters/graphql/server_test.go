@@ -0,0 +1,257 @@
+package graphql
+
+import (
+	cloudlogging "cloud.google.com/go/logging"
+	"context"
+	"encoding/json"
+	"github.com/DeryabinSergey/germanarticlebot/internal/application/usecases"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/secrets"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/storage/memory"
+	"github.com/DeryabinSergey/germanarticlebot/libs/logger"
+	"github.com/DeryabinSergey/germanarticlebot/libs/tracer"
+	"go.opentelemetry.io/otel/trace/noop"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// adminToken is the admin bearer token of the test server
+const adminToken = "secret"
+
+// newTestServer returns a server answering the lookups of "Haus" and "Tisch" from the cache, the AI and
+// the dictionary are left out so every other word fails
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+	ctx := context.Background()
+	l := logger.NewSlog(io.Discard, cloudlogging.Error, false)
+	tr := tracer.New(nil, noop.NewTracerProvider().Tracer(""))
+
+	cache := memory.NewCacheRepository(10)
+	for word, translation := range map[string]string{"Haus": "house", "Tisch": "table"} {
+		response := entities.NewSuccessResponse([]entities.ArticleInfo{{WordWithArticle: word, Translation: translation}})
+		if err := cache.Set(ctx, entities.NewArticleRequest(word, "en").CacheKey(), response, time.Hour); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stats := memory.NewStatsRepository()
+	stats.RecordLookup(ctx, "haus", "en", entities.AdapterHTTP)
+	budget := usecases.NewBudgetGuard(stats, 0, l)
+	lookup := usecases.NewDetermineArticleUseCase(nil, nil, nil, cache, time.Hour, stats, budget, l, tr)
+
+	return NewServer(
+		lookup,
+		usecases.NewListWordsUseCase(stats, l, tr),
+		usecases.NewAdminDashboardUseCase(stats, 0, budget, l, tr),
+		secrets.StaticSource(adminToken),
+		l,
+		tr,
+	)
+}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		operation string
+		variables map[string]interface{}
+		token     string
+		want      string
+	}{
+		{
+			name:  "word",
+			query: `{ word(word: "Haus") { success data { translation } } }`,
+			want:  `{"data":{"word":{"success":true,"data":[{"translation":"house"}]}}}`,
+		},
+		{
+			name:  "aliases",
+			query: `{ house: word(word: "Haus") { data { translation } } table: word(word: "Tisch") { data { t: translation } } }`,
+			want:  `{"data":{"house":{"data":[{"translation":"house"}]},"table":{"data":[{"t":"table"}]}}}`,
+		},
+		{
+			name:      "variables",
+			query:     `query ($w: String!) { word(word: $w) { data { translation } } }`,
+			variables: map[string]interface{}{"w": "Tisch"},
+			want:      `{"data":{"word":{"data":[{"translation":"table"}]}}}`,
+		},
+		{
+			name:  "variable default",
+			query: `query ($w: String = "Haus") { word(word: $w) { data { translation } } }`,
+			want:  `{"data":{"word":{"data":[{"translation":"house"}]}}}`,
+		},
+		{
+			name:  "missing required variable",
+			query: `query ($w: String!) { word(word: $w) { success } }`,
+			want:  `{"errors":[{"message":"variable \"$w\" of required type was not provided"}]}`,
+		},
+		{
+			name:      "null required variable",
+			query:     `query ($w: String!) { word(word: $w) { success } }`,
+			variables: map[string]interface{}{"w": nil},
+			want:      `{"errors":[{"message":"variable \"$w\" of required type must not be null"}]}`,
+		},
+		{
+			name:  "undefined variable",
+			query: `{ word(word: $w) { success } }`,
+			want:  `{"data":{"word":null},"errors":[{"message":"variable \"$w\" is not defined","path":["word"]}]}`,
+		},
+		{
+			name:  "fragments and directives",
+			query: `fragment F on ArticleResponse { success } { word(word: "Haus") { ...F data @skip(if: true) { translation } } }`,
+			want:  `{"data":{"word":{"success":true}}}`,
+		},
+		{
+			name:  "word fields at the limit",
+			query: `{ a: word(word: "Haus") { success } b: word(word: "Haus") { success } c: word(word: "Haus") { success } d: word(word: "Haus") { success } e: word(word: "Haus") { success } }`,
+			want:  `{"data":{"a":{"success":true},"b":{"success":true},"c":{"success":true},"d":{"success":true},"e":{"success":true}}}`,
+		},
+		{
+			name:  "too many word fields",
+			query: `{ a: word(word: "Haus") { success } b: word(word: "Haus") { success } c: word(word: "Haus") { success } d: word(word: "Haus") { success } e: word(word: "Haus") { success } f: word(word: "Haus") { success } }`,
+			want:  `{"errors":[{"message":"a query can look up at most 5 words"}]}`,
+		},
+		{
+			name:  "empty word",
+			query: `{ word(word: " ") { success } }`,
+			want:  `{"data":{"word":null},"errors":[{"message":"argument \"word\" must not be empty","path":["word"]}]}`,
+		},
+		{
+			name:  "invalid level",
+			query: `{ word(word: "Haus", level: "D1") { success } }`,
+			want:  `{"data":{"word":null},"errors":[{"message":"argument \"level\" must be one of A1, A2, B1, B2, C1 or C2","path":["word"]}]}`,
+		},
+		{
+			name:  "argument of the wrong type",
+			query: `{ word(word: 1) { success } }`,
+			want:  `{"data":{"word":null},"errors":[{"message":"argument \"word\" must be a string","path":["word"]}]}`,
+		},
+		{
+			name:  "unknown root field",
+			query: `{ words { success } }`,
+			want:  `{"errors":[{"message":"cannot query field \"words\" on type \"Query\""}]}`,
+		},
+		{
+			name:  "unknown argument",
+			query: `{ word(word: "Haus", plural: true) { success } }`,
+			want:  `{"errors":[{"message":"unknown argument \"plural\" on field \"Query.word\""}]}`,
+		},
+		{
+			name:  "unknown field",
+			query: `{ word(word: "Haus") { article } }`,
+			want:  `{"errors":[{"message":"cannot query field \"article\" on type \"ArticleResponse\""}]}`,
+		},
+		{
+			name:  "missing selection",
+			query: `{ word(word: "Haus") }`,
+			want:  `{"errors":[{"message":"field \"word\" of type \"ArticleResponse\" must have a selection of subfields"}]}`,
+		},
+		{
+			name:  "unknown fragment",
+			query: `{ word(word: "Haus") { ...F } }`,
+			want:  `{"errors":[{"message":"unknown fragment \"F\""}]}`,
+		},
+		{
+			name:  "syntax error",
+			query: `{ word(word: "Haus") { success }`,
+			want:  `{"errors":[{"message":"syntax error: unexpected end of the document"}]}`,
+		},
+		{
+			name:  "nested too deep",
+			query: nested("{ word(word: \"Haus\") ", "{ success }", "}", maxDepth),
+			want:  `{"errors":[{"message":"syntax error: the document at 672 is nested deeper than 32 levels"}]}`,
+		},
+		{
+			name:      "operation name",
+			query:     `query House { word(word: "Haus") { data { translation } } } query Table { word(word: "Tisch") { data { translation } } }`,
+			operation: "Table",
+			want:      `{"data":{"word":{"data":[{"translation":"table"}]}}}`,
+		},
+		{
+			name:  "missing operation name",
+			query: `query House { word(word: "Haus") { success } } query Table { word(word: "Tisch") { success } }`,
+			want:  `{"errors":[{"message":"operationName is required for documents with several operations"}]}`,
+		},
+		{
+			name:      "unknown operation name",
+			query:     `query House { word(word: "Haus") { success } }`,
+			operation: "Table",
+			want:      `{"errors":[{"message":"unknown operation \"Table\""}]}`,
+		},
+		{
+			name:  "mutation",
+			query: `mutation { word(word: "Haus") { success } }`,
+			want:  `{"errors":[{"message":"mutation operations are not supported"}]}`,
+		},
+		{
+			name:  "typename",
+			query: `{ __typename }`,
+			want:  `{"data":{"__typename":"Query"}}`,
+		},
+		{
+			name:  "history without token",
+			query: `{ history { items { word } } }`,
+			want:  `{"data":{"history":null},"errors":[{"message":"unauthorized, history requires the admin bearer token","path":["history"]}]}`,
+		},
+		{
+			name:  "history with wrong token",
+			query: `{ history { items { word } } }`,
+			token: "guess",
+			want:  `{"data":{"history":null},"errors":[{"message":"unauthorized, history requires the admin bearer token","path":["history"]}]}`,
+		},
+		{
+			name:  "history",
+			query: `{ history(first: 10) { items { word lookups } } }`,
+			token: adminToken,
+			want:  `{"data":{"history":{"items":[{"word":"haus","lookups":1}]}}}`,
+		},
+		{
+			name:  "history limit",
+			query: `{ history(first: 1000) { items { word } } }`,
+			token: adminToken,
+			want:  `{"data":{"history":null},"errors":[{"message":"argument \"first\" must be between 1 and 100","path":["history"]}]}`,
+		},
+		{
+			name:  "invalid page token",
+			query: `{ history(after: "garbage") { items { word } } }`,
+			token: adminToken,
+			want:  `{"data":{"history":null},"errors":[{"message":"argument \"after\" is not a valid page token","path":["history"]}]}`,
+		},
+		{
+			name:  "stats without token",
+			query: `{ stats { topWords { word } } }`,
+			want:  `{"data":{"stats":null},"errors":[{"message":"unauthorized, stats require the admin bearer token","path":["stats"]}]}`,
+		},
+		{
+			name:  "stats",
+			query: `{ stats(topWords: 1) { topWords { word } } }`,
+			token: adminToken,
+			want:  `{"data":{"stats":{"topWords":[{"word":"haus"}]}}}`,
+		},
+		{
+			name:  "partial failure",
+			query: `{ word(word: "Haus") { success } stats { topWords { word } } }`,
+			want:  `{"data":{"word":{"success":true},"stats":null},"errors":[{"message":"unauthorized, stats require the admin bearer token","path":["stats"]}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t)
+			r := httptest.NewRequest("POST", "/graphql", nil)
+			if tt.token != "" {
+				r.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+
+			resp := s.execute(context.Background(), r, request{Query: tt.query, OperationName: tt.operation, Variables: tt.variables})
+			got, err := json.Marshal(resp)
+			if err != nil {
+				t.Fatalf("Failed to encode the response: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("got  %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
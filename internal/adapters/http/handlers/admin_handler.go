@@ -0,0 +1,598 @@
+package handlers
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"github.com/DeryabinSergey/germanarticlebot/internal/application/usecases"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/secrets"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultTopWords = 10
+	maxTopWords     = 100
+
+	defaultFeedbackLimit = 50
+	maxFeedbackLimit     = 500
+
+	defaultWarmupWords = 100
+	maxWarmupWords     = 1000
+
+	defaultDeadLetterLimit = 50
+	maxDeadLetterLimit     = 500
+
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+
+	// maxDictionaryImportBytes bounds the size of an imported dictionary file
+	maxDictionaryImportBytes = 4 << 20
+
+	// maxActorLength bounds the operator name claimed by the X-Admin-Actor header
+	maxActorLength = 64
+)
+
+// AdminHandler handles HTTP requests of the operator dashboard
+type AdminHandler struct {
+	token       secrets.Source
+	dashboard   *usecases.AdminDashboardUseCase
+	feedback    *usecases.ListFeedbackUseCase
+	words       *usecases.ListWordsUseCase
+	warmup      *usecases.WarmCacheUseCase
+	purge       *usecases.PurgeCacheUseCase
+	leaderboard *usecases.LeaderboardUseCase
+	deadLetters *usecases.DeadLetterUseCase
+	broadcast   *usecases.BroadcastUseCase
+	audit       *usecases.AuditLogUseCase
+	retention   *usecases.RetentionSweepUseCase
+	tenants     *usecases.TenantUseCase
+	webhooks    *usecases.BotWebhooksUseCase
+	reminders   *usecases.ReminderUseCase
+	dictionary  *usecases.ImportDictionaryUseCase
+	logger      logging.Logger
+	tracer      tracing.Tracer
+}
+
+// NewAdminHandler creates a new admin handler, an empty token disables the admin routes
+func NewAdminHandler(
+	token secrets.Source,
+	dashboard *usecases.AdminDashboardUseCase,
+	feedback *usecases.ListFeedbackUseCase,
+	words *usecases.ListWordsUseCase,
+	warmup *usecases.WarmCacheUseCase,
+	purge *usecases.PurgeCacheUseCase,
+	leaderboard *usecases.LeaderboardUseCase,
+	deadLetters *usecases.DeadLetterUseCase,
+	broadcast *usecases.BroadcastUseCase,
+	audit *usecases.AuditLogUseCase,
+	retention *usecases.RetentionSweepUseCase,
+	tenants *usecases.TenantUseCase,
+	webhooks *usecases.BotWebhooksUseCase,
+	reminders *usecases.ReminderUseCase,
+	dictionary *usecases.ImportDictionaryUseCase,
+	logger logging.Logger,
+	tracer tracing.Tracer,
+) *AdminHandler {
+	return &AdminHandler{
+		token:       token,
+		dashboard:   dashboard,
+		feedback:    feedback,
+		words:       words,
+		warmup:      warmup,
+		purge:       purge,
+		leaderboard: leaderboard,
+		deadLetters: deadLetters,
+		broadcast:   broadcast,
+		audit:       audit,
+		retention:   retention,
+		tenants:     tenants,
+		webhooks:    webhooks,
+		reminders:   reminders,
+		dictionary:  dictionary,
+		logger:      logger,
+		tracer:      tracer,
+	}
+}
+
+// HandleAdminRequest routes requests of the /admin group
+func (h *AdminHandler) HandleAdminRequest(w http.ResponseWriter, r *http.Request) {
+	spanCtx, span := h.tracer.Start(r.Context(), "HTTP Admin Handler")
+	defer span.End()
+	r = r.WithContext(spanCtx)
+
+	if !h.authorize(spanCtx, r) {
+		h.logger.With(spanCtx).Field("path", r.URL.Path).Warning("Unauthorized admin request")
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeErrorResponse(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	switch route := strings.TrimSuffix(r.URL.Path, "/"); route {
+	case "/admin", "/admin/stats":
+		allowMethod(w, r, http.MethodGet, h.handleStats)
+	case "/admin/top-words":
+		allowMethod(w, r, http.MethodGet, h.handleTopWords)
+	case "/admin/feedback":
+		allowMethod(w, r, http.MethodGet, h.handleFeedback)
+	case "/admin/cache":
+		allowMethod(w, r, http.MethodDelete, h.handleDeleteCache)
+	case "/admin/cache/purge":
+		allowMethod(w, r, http.MethodPost, h.handlePurgeCache)
+	case "/admin/cache/prewarm":
+		allowMethod(w, r, http.MethodPost, h.handlePrewarm)
+	case "/admin/leaderboard/summary":
+		allowMethod(w, r, http.MethodPost, h.handleLeaderboardSummary)
+	case "/admin/broadcast":
+		allowMethod(w, r, http.MethodPost, h.handleBroadcast)
+	case "/admin/dead-letters":
+		allowMethod(w, r, http.MethodGet, h.handleDeadLetters)
+	case "/admin/dead-letters/replay":
+		allowMethod(w, r, http.MethodPost, h.handleReplayDeadLetters)
+	case "/admin/audit":
+		allowMethod(w, r, http.MethodGet, h.handleAudit)
+	case "/admin/retention/sweep":
+		allowMethod(w, r, http.MethodPost, h.handleRetentionSweep)
+	case "/admin/reminders/send":
+		allowMethod(w, r, http.MethodPost, h.handleSendReminders)
+	case "/admin/dictionary/import":
+		allowMethod(w, r, http.MethodPost, h.handleDictionaryImport)
+	case "/admin/tenants":
+		allowMethod(w, r, http.MethodGet, h.handleTenants)
+	case "/admin/telegram/webhooks":
+		allowMethod(w, r, http.MethodGet, h.handleBotWebhooks)
+	case "/admin/telegram/webhooks/set":
+		allowMethod(w, r, http.MethodPost, h.handleSetBotWebhooks)
+	default:
+		writeErrorResponse(w, "Not found", http.StatusNotFound)
+	}
+}
+
+// allowMethod serves the request with the handler of the route, other methods get 405
+func allowMethod(w http.ResponseWriter, r *http.Request, method string, handler http.HandlerFunc) {
+	if r.Method != method {
+		w.Header().Set("Allow", method)
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	handler(w, r)
+}
+
+// handleStats returns the full dashboard snapshot
+func (h *AdminHandler) handleStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := h.dashboard.Execute(r.Context(), parseLimit(r, defaultTopWords, maxTopWords))
+	if err != nil {
+		writeErrorResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSONResponse(w, stats, http.StatusOK)
+}
+
+// handleTopWords lists the looked-up words page by page, the most looked-up first by default
+func (h *AdminHandler) handleTopWords(w http.ResponseWriter, r *http.Request) {
+	page, err := parsePageRequest(r, defaultTopWords, maxTopWords, entities.WordStatOrderFields)
+	if err != nil {
+		writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	words, err := h.words.Execute(r.Context(), entities.WordStatFilter{
+		Prefix: r.URL.Query().Get("prefix"),
+		Page:   page,
+	})
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+
+	writePage(w, "topWords", words)
+}
+
+// handleFeedback lists rated answers, downvoted ones by default
+func (h *AdminHandler) handleFeedback(w http.ResponseWriter, r *http.Request) {
+	verdict := entities.Verdict(r.URL.Query().Get("verdict"))
+	switch verdict {
+	case "":
+		verdict = entities.VerdictDown
+	case "all":
+		verdict = ""
+	default:
+		if !verdict.Valid() {
+			writeErrorResponse(w, "Verdict must be up, down or all", http.StatusBadRequest)
+			return
+		}
+	}
+
+	page, err := parsePageRequest(r, defaultFeedbackLimit, maxFeedbackLimit, entities.FeedbackOrderFields)
+	if err != nil {
+		writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	feedback, err := h.feedback.Execute(r.Context(), entities.FeedbackFilter{
+		Verdict:  verdict,
+		Word:     r.URL.Query().Get("word"),
+		Language: r.URL.Query().Get("language"),
+		Page:     page,
+	})
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+
+	writePage(w, "feedback", feedback)
+}
+
+// handlePrewarm starts a background job looking up the "limit" most frequent nouns in the
+// comma-separated "lang" languages, en by default
+func (h *AdminHandler) handlePrewarm(w http.ResponseWriter, r *http.Request) {
+	warmup := entities.CacheWarmup{Words: parseLimit(r, defaultWarmupWords, maxWarmupWords)}
+	for _, language := range strings.Split(r.URL.Query().Get("lang"), ",") {
+		if language = strings.ToLower(strings.TrimSpace(language)); language != "" {
+			warmup.Languages = append(warmup.Languages, language)
+		}
+	}
+	if len(warmup.Languages) == 0 {
+		warmup.Languages = []string{"en"}
+	}
+
+	job, err := h.warmup.Submit(r.Context(), warmup)
+	if err != nil {
+		writeErrorResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	h.record(r, entities.AuditActionCachePrewarm, map[string]interface{}{"warmup": warmup, "jobId": job.ID})
+
+	w.Header().Set("Location", jobLocation(job.ID))
+	writeJSONResponse(w, map[string]interface{}{"success": true, "jobId": job.ID}, http.StatusAccepted)
+}
+
+// handleLeaderboardSummary starts a background job broadcasting the leaderboards of the "week", like 2026-W42,
+// the previous week by default. It's meant to be called weekly by a scheduler.
+func (h *AdminHandler) handleLeaderboardSummary(w http.ResponseWriter, r *http.Request) {
+	week := r.URL.Query().Get("week")
+	job, err := h.leaderboard.SubmitSummary(r.Context(), week)
+	if errors.Is(err, usecases.ErrInvalidWeek) {
+		writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		writeErrorResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	h.record(r, entities.AuditActionLeaderboardSummary, map[string]interface{}{"week": week, "jobId": job.ID})
+
+	w.Header().Set("Location", jobLocation(job.ID))
+	writeJSONResponse(w, map[string]interface{}{"success": true, "jobId": job.ID}, http.StatusAccepted)
+}
+
+// handleBroadcast starts a background job sending the announcement of the JSON body to every known
+// Telegram chat that didn't opt out with /announcements off
+func (h *AdminHandler) handleBroadcast(w http.ResponseWriter, r *http.Request) {
+	var broadcast entities.Broadcast
+	if err := json.NewDecoder(r.Body).Decode(&broadcast); err != nil {
+		writeBodyErrorResponse(w, err, "Invalid JSON format")
+		return
+	}
+
+	job, err := h.broadcast.Submit(r.Context(), broadcast)
+	if errors.Is(err, usecases.ErrInvalidBroadcast) {
+		writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		writeErrorResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	h.record(r, entities.AuditActionBroadcast, map[string]interface{}{"broadcast": broadcast, "jobId": job.ID})
+
+	w.Header().Set("Location", jobLocation(job.ID))
+	writeJSONResponse(w, map[string]interface{}{"success": true, "jobId": job.ID}, http.StatusAccepted)
+}
+
+// handleRetentionSweep starts a background job purging the ratings and the learning activity older than
+// DATA_RETENTION. It's meant to be called daily by a scheduler.
+func (h *AdminHandler) handleRetentionSweep(w http.ResponseWriter, r *http.Request) {
+	job, err := h.retention.Submit(r.Context())
+	if errors.Is(err, usecases.ErrRetentionDisabled) {
+		writeErrorResponse(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	if err != nil {
+		writeErrorResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	h.record(r, entities.AuditActionRetentionSweep, map[string]interface{}{"jobId": job.ID})
+
+	w.Header().Set("Location", jobLocation(job.ID))
+	writeJSONResponse(w, map[string]interface{}{"success": true, "jobId": job.ID}, http.StatusAccepted)
+}
+
+// handleSendReminders starts a background job sending the practice reminders due now. It's meant to be
+// called every few minutes by a scheduler, so the jobs aren't recorded in the audit log.
+func (h *AdminHandler) handleSendReminders(w http.ResponseWriter, r *http.Request) {
+	job, err := h.reminders.Submit(r.Context())
+	if err != nil {
+		writeErrorResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Location", jobLocation(job.ID))
+	writeJSONResponse(w, map[string]interface{}{"success": true, "jobId": job.ID}, http.StatusAccepted)
+}
+
+// handleDictionaryImport imports the curated nouns of a CSV or JSON Lines file sent as the body or the
+// "file" form field. The format is taken from the "format" query parameter, the content type or the first
+// character of the file, "dryRun=true" only validates the entries.
+func (h *AdminHandler) handleDictionaryImport(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxDictionaryImportBytes)
+	var body io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			writeBodyErrorResponse(w, err, "The dictionary must be sent in the \"file\" form field")
+			return
+		}
+		defer file.Close()
+		body = file
+	}
+
+	rows, err := usecases.ParseDictionaryFile(body, dictionaryFormat(r))
+	if errors.Is(err, usecases.ErrInvalidDictionaryFile) {
+		writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		writeBodyErrorResponse(w, err, "Invalid dictionary file")
+		return
+	}
+	if len(rows) == 0 {
+		writeErrorResponse(w, "The dictionary file is empty", http.StatusBadRequest)
+		return
+	}
+
+	dryRun, _ := strconv.ParseBool(r.URL.Query().Get("dryRun"))
+	result, err := h.dictionary.Execute(r.Context(), rows, dryRun)
+	if errors.Is(err, usecases.ErrTooManyWords) {
+		writeErrorResponse(w, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err != nil {
+		writeErrorResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if !dryRun {
+		h.record(r, entities.AuditActionDictionaryImport, map[string]interface{}{"imported": result.Imported, "errors": len(result.Errors)})
+	}
+
+	writeJSONResponse(w, map[string]interface{}{"success": true, "data": result}, http.StatusOK)
+}
+
+// dictionaryFormat returns the format of the imported dictionary named by the query or the content type,
+// empty to detect it from the file
+func dictionaryFormat(r *http.Request) string {
+	if format := strings.ToLower(r.URL.Query().Get("format")); format != "" {
+		return format
+	}
+
+	switch mediaType, _, _ := strings.Cut(r.Header.Get("Content-Type"), ";"); strings.TrimSpace(mediaType) {
+	case "text/csv":
+		return usecases.DictionaryFormatCSV
+	case "application/jsonl", "application/x-ndjson", "application/x-jsonlines":
+		return usecases.DictionaryFormatJSONL
+	}
+
+	return ""
+}
+
+// handleTenants lists the usage of every tenant with the given number of top words each
+func (h *AdminHandler) handleTenants(w http.ResponseWriter, r *http.Request) {
+	tenants, err := h.tenants.List(r.Context(), parseLimit(r, defaultTopWords, maxTopWords))
+	if err != nil {
+		writeErrorResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSONResponse(w, tenants, http.StatusOK)
+}
+
+// handleBotWebhooks lists the webhook state of the main bot and of the white-label bots
+func (h *AdminHandler) handleBotWebhooks(w http.ResponseWriter, r *http.Request) {
+	webhooks, err := h.webhooks.List(r.Context())
+	switch {
+	case errors.Is(err, usecases.ErrBotsUnavailable):
+		writeErrorResponse(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	case err != nil:
+		writeErrorResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSONResponse(w, map[string]interface{}{"success": true, "webhooks": webhooks}, http.StatusOK)
+}
+
+// handleSetBotWebhooks points the webhook of the bot of the "botId", or of every bot without it, to the
+// deployment. It's meant to be called after deploying a new URL or adding a white-label bot.
+func (h *AdminHandler) handleSetBotWebhooks(w http.ResponseWriter, r *http.Request) {
+	var botID int64
+	if value := r.URL.Query().Get("botId"); value != "" {
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || id <= 0 {
+			writeErrorResponse(w, "botId must be the numeric ID of a bot", http.StatusBadRequest)
+			return
+		}
+		botID = id
+	}
+
+	webhooks, err := h.webhooks.Set(r.Context(), botID)
+	switch {
+	case errors.Is(err, usecases.ErrBotNotFound):
+		writeErrorResponse(w, "Bot not found", http.StatusNotFound)
+		return
+	case errors.Is(err, usecases.ErrBotsUnavailable), errors.Is(err, usecases.ErrWebhookURLMissing):
+		writeErrorResponse(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	case err != nil:
+		writeErrorResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	h.record(r, entities.AuditActionBotWebhooks, map[string]interface{}{"botId": botID, "webhooks": len(webhooks)})
+
+	writeJSONResponse(w, map[string]interface{}{"success": true, "webhooks": webhooks}, http.StatusOK)
+}
+
+// handleDeadLetters lists the Telegram updates whose processing failed, oldest first
+func (h *AdminHandler) handleDeadLetters(w http.ResponseWriter, r *http.Request) {
+	letters, err := h.deadLetters.List(r.Context(), parseLimit(r, defaultDeadLetterLimit, maxDeadLetterLimit))
+	if err != nil {
+		writeErrorResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSONResponse(w, map[string]interface{}{"success": true, "deadLetters": letters}, http.StatusOK)
+}
+
+// handleReplayDeadLetters processes the dead-lettered update with the "id" again, or the oldest ones
+// without it. Replayed updates leave the dead letters, failing ones stay with their new error.
+func (h *AdminHandler) handleReplayDeadLetters(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimSpace(r.URL.Query().Get("id"))
+	replay, err := h.deadLetters.Replay(r.Context(), id)
+	switch {
+	case errors.Is(err, usecases.ErrDeadLetterNotFound):
+		writeErrorResponse(w, "Dead letter not found", http.StatusNotFound)
+		return
+	case errors.Is(err, usecases.ErrReplayUnavailable):
+		writeErrorResponse(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	case err != nil:
+		writeErrorResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	h.record(r, entities.AuditActionDeadLetterReplay, map[string]interface{}{"id": id, "replayed": replay.Replayed, "failed": replay.Failed})
+
+	writeJSONResponse(w, map[string]interface{}{"success": true, "replayed": replay.Replayed, "failed": replay.Failed}, http.StatusOK)
+}
+
+// handleAudit lists the recorded admin operations of the optional "action", newest first
+func (h *AdminHandler) handleAudit(w http.ResponseWriter, r *http.Request) {
+	entries, err := h.audit.List(r.Context(), entities.AuditAction(r.URL.Query().Get("action")), parseLimit(r, defaultAuditLimit, maxAuditLimit))
+	if err != nil {
+		writeErrorResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSONResponse(w, map[string]interface{}{"success": true, "entries": entries}, http.StatusOK)
+}
+
+// handleDeleteCache evicts the cached answers of the "word" in the "lang" language, all languages without it
+func (h *AdminHandler) handleDeleteCache(w http.ResponseWriter, r *http.Request) {
+	word := r.URL.Query().Get("word")
+	if strings.TrimSpace(word) == "" {
+		writeErrorResponse(w, "Word parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	h.writePurged(w, r, entities.AuditActionCacheDelete, []string{word}, r.URL.Query().Get("lang"), false)
+}
+
+// handlePurgeCache evicts the cached answers of the "words" of the JSON body in the optional
+// "lang" language, or every answer with "all"
+func (h *AdminHandler) handlePurgeCache(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Words    []string `json:"words"`
+		Language string   `json:"lang"`
+		All      bool     `json:"all"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeBodyErrorResponse(w, err, "Invalid JSON format")
+		return
+	}
+	if len(request.Words) == 0 && !request.All {
+		writeErrorResponse(w, "Words or all are required", http.StatusBadRequest)
+		return
+	}
+
+	h.writePurged(w, r, entities.AuditActionCachePurge, request.Words, request.Language, request.All)
+}
+
+func (h *AdminHandler) writePurged(w http.ResponseWriter, r *http.Request, action entities.AuditAction, words []string, language string, all bool) {
+	var removed int
+	var err error
+	if all {
+		removed, err = h.purge.PurgeAll(r.Context())
+	} else {
+		removed, err = h.purge.Execute(r.Context(), words, strings.ToLower(strings.TrimSpace(language)))
+	}
+	if err != nil {
+		writeErrorResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	h.record(r, action, map[string]interface{}{"words": words, "lang": language, "all": all, "removed": removed})
+
+	writeJSONResponse(w, map[string]interface{}{"success": true, "removed": removed}, http.StatusOK)
+}
+
+// record appends the admin operation of the request to the audit log
+func (h *AdminHandler) record(r *http.Request, action entities.AuditAction, payload interface{}) {
+	h.audit.Record(r.Context(), action, adminActor(r), clientIP(r), payload)
+}
+
+// adminActor returns the operator named by the X-Admin-Actor header, all operators share the admin token
+func adminActor(r *http.Request) string {
+	actor := strings.TrimSpace(r.Header.Get("X-Admin-Actor"))
+	if actor == "" {
+		return "admin"
+	}
+	if len(actor) > maxActorLength {
+		actor = strings.ToValidUTF8(actor[:maxActorLength], "")
+	}
+
+	return actor
+}
+
+// writeListError answers stale or foreign page tokens with 400 and other failures with 500
+func writeListError(w http.ResponseWriter, err error) {
+	if errors.Is(err, entities.ErrInvalidPageToken) {
+		writeErrorResponse(w, "Invalid page token", http.StatusBadRequest)
+		return
+	}
+
+	writeErrorResponse(w, "Internal server error", http.StatusInternalServerError)
+}
+
+// authorize checks the bearer token of the request against the configured admin token
+func (h *AdminHandler) authorize(ctx context.Context, r *http.Request) bool {
+	expected, err := h.token(ctx)
+	if err != nil {
+		h.logger.With(ctx).Err(err).Error("Failed to read admin token")
+		return false
+	}
+	if expected == "" {
+		return false
+	}
+
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(token), []byte(expected)) == 1
+}
+
+// parseLimit reads the limit query parameter bounded by maxValue
+func parseLimit(r *http.Request, defaultValue, maxValue int) int {
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		return defaultValue
+	}
+
+	return min(limit, maxValue)
+}
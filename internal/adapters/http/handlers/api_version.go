@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// apiVersion is a version of the article API schema
+type apiVersion int
+
+const (
+	// apiV1 is the original schema, kept stable for existing clients and served by default
+	apiV1 apiVersion = 1
+	// apiV2 adds the word type, the confidence and the declension table of every interpretation
+	apiV2 apiVersion = 2
+)
+
+// apiVersionHeader selects the version of unversioned paths and reports the served one
+const apiVersionHeader = "API-Version"
+
+// vendorMediaType is the Accept media type selecting a version, e.g. application/vnd.germanarticle.v2+json
+const vendorMediaType = "application/vnd.germanarticle.v%d+json"
+
+const unsupportedVersionMessage = "API version must be 1 or 2"
+
+// negotiateVersion picks the schema version of the request: a /v1/ or /v2/ path prefix wins over
+// the API-Version header, which wins over the Accept media type, and v1 is the default.
+// ok is false for unknown versions.
+func negotiateVersion(r *http.Request) (version apiVersion, ok bool) {
+	switch {
+	case strings.HasPrefix(r.URL.Path, "/v1/"):
+		return apiV1, true
+	case strings.HasPrefix(r.URL.Path, "/v2/"):
+		return apiV2, true
+	}
+
+	if value := strings.TrimSpace(r.Header.Get(apiVersionHeader)); value != "" {
+		number, err := strconv.Atoi(strings.TrimPrefix(strings.ToLower(value), "v"))
+		if err != nil {
+			return 0, false
+		}
+		return checkVersion(apiVersion(number))
+	}
+
+	for _, mediaType := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, _ = strings.Cut(strings.TrimSpace(mediaType), ";")
+		var number int
+		if _, err := fmt.Sscanf(strings.ToLower(mediaType), vendorMediaType, &number); err == nil {
+			return checkVersion(apiVersion(number))
+		}
+	}
+
+	return apiV1, true
+}
+
+func checkVersion(version apiVersion) (apiVersion, bool) {
+	if version != apiV1 && version != apiV2 {
+		return 0, false
+	}
+
+	return version, true
+}
+
+// articleResponseV2 is the v2 schema, the response with its schema version
+type articleResponseV2 struct {
+	SchemaVersion apiVersion `json:"schemaVersion"`
+	*entities.ArticleResponse
+}
+
+// versionedResponse shapes the response for the schema version, the response itself is not modified
+// because it may be shared with the cache
+func versionedResponse(version apiVersion, response *entities.ArticleResponse) interface{} {
+	if version == apiV2 {
+		return articleResponseV2{SchemaVersion: apiV2, ArticleResponse: response}
+	}
+
+	v1 := *response
+	v1.Data = make([]entities.ArticleInfo, len(response.Data))
+	for i, info := range response.Data {
+		info.WordType, info.Confidence, info.Declension = "", 0, nil
+		v1.Data[i] = info
+	}
+
+	return &v1
+}
+
+// writeVersionedResponse writes the response in the schema of the version, a positive maxAge makes
+// it cacheable by clients and CDNs
+func writeVersionedResponse(w http.ResponseWriter, r *http.Request, version apiVersion, response *entities.ArticleResponse, maxAge time.Duration) {
+	w.Header().Set(apiVersionHeader, strconv.Itoa(int(version)))
+	if maxAge > 0 {
+		writeCacheableJSONResponse(w, r, versionedResponse(version, response), maxAge)
+		return
+	}
+
+	writeJSONResponse(w, versionedResponse(version, response), http.StatusOK)
+}
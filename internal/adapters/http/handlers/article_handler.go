@@ -1,20 +1,32 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/adapters/presenter"
 	"github.com/DeryabinSergey/germanarticlebot/internal/application/usecases"
 	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/repositories"
 	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
 	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // ArticleHandler handles HTTP requests for article determination
 type ArticleHandler struct {
-	useCase *usecases.DetermineArticleUseCase
-	logger  logging.Logger
-	tracer  tracing.Tracer
+	useCase     *usecases.DetermineArticleUseCase
+	articleOnly *usecases.ArticleOnlyUseCase
+	suggest     *usecases.SuggestUseCase
+	preferences repositories.PreferencesRepository
+	cacheMaxAge time.Duration
+	logger      logging.Logger
+	tracer      tracing.Tracer
 }
 
 // NewArticleHandler creates a new article handler
@@ -30,96 +42,369 @@ func NewArticleHandler(
 	}
 }
 
+// SetCacheMaxAge lets clients and CDNs cache successful GET answers for maxAge, zero disables it
+func (h *ArticleHandler) SetCacheMaxAge(maxAge time.Duration) {
+	h.cacheMaxAge = maxAge
+}
+
+// SetPreferences applies the bot preferences of the profile to requests of linked accounts
+func (h *ArticleHandler) SetPreferences(preferences repositories.PreferencesRepository) {
+	h.preferences = preferences
+}
+
+// SetArticleOnly serves the article-only lookups, nil disables them
+func (h *ArticleHandler) SetArticleOnly(articleOnly *usecases.ArticleOnlyUseCase) {
+	h.articleOnly = articleOnly
+}
+
+// SetSuggest serves the suggestions of partial words, nil disables them
+func (h *ArticleHandler) SetSuggest(suggest *usecases.SuggestUseCase) {
+	h.suggest = suggest
+}
+
 // HandleArticleRequest handles HTTP requests for article determination
 func (h *ArticleHandler) HandleArticleRequest(w http.ResponseWriter, r *http.Request) {
-	// Add CORS headers
-	h.setCORSHeaders(w)
+	spanCtx, span := h.tracer.Start(r.Context(), "HTTP Handler")
+	defer span.End()
 
-	// Handle preflight OPTIONS request
-	if r.Method == http.MethodOptions {
-		w.WriteHeader(http.StatusOK)
+	version, ok := negotiateVersion(r)
+	if !ok {
+		writeErrorResponse(w, unsupportedVersionMessage, http.StatusNotAcceptable)
 		return
 	}
 
-	spanCtx, span := h.tracer.Start(r.Context(), "HTTP Handler")
-	defer span.End()
-
 	// Extract language from Accept-Language header
-	language := h.extractLanguageFromHeader(r.Header.Get("Accept-Language"))
+	language := extractLanguageFromHeader(r.Header.Get("Accept-Language"))
 	if language == "" {
-		h.logger.Warning(spanCtx, map[string]interface{}{
-			"message": "No language specified, defaulting to 'en'",
-		})
+		h.logger.With(spanCtx).Warning("No language specified, defaulting to 'en'")
 		language = "en"
 	}
 
+	level, ok := parseLevel(r.URL.Query().Get("level"))
+	if !ok {
+		writeErrorResponse(w, invalidLevelMessage, http.StatusBadRequest)
+		return
+	}
+	verbosity, ok := parseVerbosity(r.URL.Query().Get("verbosity"))
+	if !ok {
+		writeErrorResponse(w, invalidVerbosityMessage, http.StatusBadRequest)
+		return
+	}
+	stage, ok := parseStage(r.URL.Query().Get("stage"))
+	if !ok {
+		writeErrorResponse(w, invalidStageMessage, http.StatusBadRequest)
+		return
+	}
+
 	var word string
 	var err error
 
 	switch r.Method {
 	case http.MethodGet:
 		if err = r.ParseForm(); err != nil {
-			h.logger.Error(spanCtx, map[string]interface{}{
-				"message": "Failed to parse form",
-				"error":   err.Error(),
-			})
-			h.writeErrorResponse(w, "Invalid request format", http.StatusBadRequest)
+			h.logger.With(spanCtx).Err(err).Error("Failed to parse form")
+			writeErrorResponse(w, "Invalid request format", http.StatusBadRequest)
 			return
 		}
 		word = r.Form.Get("word")
 
 	case http.MethodPost:
 		var request struct {
-			Word string `json:"word"`
+			Word      string `json:"word"`
+			Level     string `json:"level"`
+			Verbosity string `json:"verbosity"`
+			Stage     string `json:"stage"`
 		}
 		if err = json.NewDecoder(r.Body).Decode(&request); err != nil {
-			h.logger.Error(spanCtx, map[string]interface{}{
-				"message": "Failed to decode JSON body",
-				"error":   err.Error(),
-			})
-			h.writeErrorResponse(w, "Invalid JSON format", http.StatusBadRequest)
+			h.logger.With(spanCtx).Err(err).Error("Failed to decode JSON body")
+			writeBodyErrorResponse(w, err, "Invalid JSON format")
 			return
 		}
 		word = request.Word
+		if request.Level != "" {
+			if level, ok = parseLevel(request.Level); !ok {
+				writeErrorResponse(w, invalidLevelMessage, http.StatusBadRequest)
+				return
+			}
+		}
+		if request.Verbosity != "" {
+			if verbosity, ok = parseVerbosity(request.Verbosity); !ok {
+				writeErrorResponse(w, invalidVerbosityMessage, http.StatusBadRequest)
+				return
+			}
+		}
+		if request.Stage != "" {
+			if stage, ok = parseStage(request.Stage); !ok {
+				writeErrorResponse(w, invalidStageMessage, http.StatusBadRequest)
+				return
+			}
+		}
 
 	default:
-		h.writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
 	if word == "" {
-		h.writeErrorResponse(w, "Word parameter is required", http.StatusBadRequest)
+		writeErrorResponse(w, "Word parameter is required", http.StatusBadRequest)
 		return
 	}
 
 	// Create request entity
 	articleRequest := entities.NewArticleRequest(word, language)
+	articleRequest.Level = level
+	articleRequest.Verbosity = verbosity
+	hideHints := false
+	if preferences := h.profilePreferences(spanCtx); preferences != nil {
+		if articleRequest.Level == "" {
+			articleRequest.Level = preferences.Level
+		}
+		if articleRequest.Verbosity == "" {
+			articleRequest.Verbosity = preferences.Verbosity
+		}
+		hideHints = preferences.HideHints
+	}
+
+	// The language and the version may come from the headers
+	w.Header().Add("Vary", "Accept, Accept-Language, "+apiVersionHeader)
+	h.respond(spanCtx, w, r, version, articleRequest, stage, hideHints)
+}
+
+// HandleWordRequest handles the canonical GET /v1/words/{word}?lang=en route, every input of the
+// lookup is part of the URL so CDNs can cache the answers keyed on it. Other spellings of the same
+// lookup are redirected to the canonical URL.
+func (h *ArticleHandler) HandleWordRequest(w http.ResponseWriter, r *http.Request) {
+	spanCtx, span := h.tracer.Start(r.Context(), "HTTP Word Handler")
+	defer span.End()
+
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	version, _ := negotiateVersion(r)
+	prefix := fmt.Sprintf("/v%d/words/", version)
+	word, ok := strings.CutPrefix(r.URL.Path, prefix)
+	if word = entities.NormalizeWord(word); !ok || word == "" || strings.Contains(word, "/") {
+		writeErrorResponse(w, "Word parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	language := strings.ToLower(strings.TrimSpace(query.Get("lang")))
+	if language == "" {
+		language = "en"
+	}
+	level, ok := parseLevel(query.Get("level"))
+	if !ok {
+		writeErrorResponse(w, invalidLevelMessage, http.StatusBadRequest)
+		return
+	}
+	verbosity, ok := parseVerbosity(query.Get("verbosity"))
+	if !ok {
+		writeErrorResponse(w, invalidVerbosityMessage, http.StatusBadRequest)
+		return
+	}
+
+	canonical := url.Values{"lang": {language}}
+	if level != "" {
+		canonical.Set("level", string(level))
+	}
+	// The full profile is the default, so it has no parameter in the canonical URL
+	if verbosity.Reduced() {
+		canonical.Set("verbosity", string(verbosity))
+	}
+	location := prefix + url.PathEscape(word) + "?" + canonical.Encode()
+	if location != r.URL.RequestURI() {
+		if h.cacheMaxAge > 0 {
+			w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(h.cacheMaxAge.Seconds())))
+		}
+		http.Redirect(w, r, location, http.StatusMovedPermanently)
+		return
+	}
+
+	articleRequest := entities.NewArticleRequest(word, language)
+	articleRequest.Level = level
+	articleRequest.Verbosity = verbosity
+	h.respond(spanCtx, w, r, version, articleRequest, "", false)
+}
+
+// HandleArticleOnlyRequest handles GET /v1/article-only?word=Haus, answering just the article and the
+// gender of the noun for autocomplete-style integrations
+func (h *ArticleHandler) HandleArticleOnlyRequest(w http.ResponseWriter, r *http.Request) {
+	spanCtx, span := h.tracer.Start(r.Context(), "HTTP Article Only Handler")
+	defer span.End()
+
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.articleOnly == nil {
+		writeErrorResponse(w, "Article-only lookups are not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	word := entities.NormalizeWord(r.URL.Query().Get("word"))
+	if word == "" {
+		writeErrorResponse(w, "Word parameter is required", http.StatusBadRequest)
+		return
+	}
+	if problem := entities.CheckWord(word); problem != "" {
+		writeErrorResponse(w, problem.Explanation("en"), http.StatusBadRequest)
+		return
+	}
+
+	answer, err := h.articleOnly.Execute(spanCtx, word)
+	switch {
+	case errors.Is(err, usecases.ErrNoArticle):
+		writeErrorResponse(w, "No article found for the word", http.StatusNotFound)
+		return
+	case errors.Is(err, usecases.ErrDeadlineExceeded) || errors.Is(spanCtx.Err(), context.DeadlineExceeded):
+		writeErrorResponse(w, "Request timed out", http.StatusGatewayTimeout)
+		return
+	case errors.Is(err, usecases.ErrAIUnavailable):
+		writeErrorResponse(w, "Service temporarily unavailable", http.StatusServiceUnavailable)
+		return
+	case err != nil:
+		h.logger.With(spanCtx).Err(err).Field("word", word).Error("Article-only lookup failed")
+		writeErrorResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Cached answers would skip the quota of the tenant
+	if h.cacheMaxAge > 0 && usecases.TenantFromContext(spanCtx) == nil {
+		writeCacheableJSONResponse(w, r, answer, h.cacheMaxAge)
+		return
+	}
+	writeJSONResponse(w, answer, http.StatusOK)
+}
+
+// defaultSuggestions is the number of suggestions of requests without a limit
+const defaultSuggestions = 10
+
+// HandleSuggestRequest handles GET /v1/suggest?prefix=Hau&lang=en&limit=10, completing the partial word
+// to known nouns with their articles for as-you-type suggestions
+func (h *ArticleHandler) HandleSuggestRequest(w http.ResponseWriter, r *http.Request) {
+	spanCtx, span := h.tracer.Start(r.Context(), "HTTP Suggest Handler")
+	defer span.End()
+
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.suggest == nil {
+		writeErrorResponse(w, "Suggestions are not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	query := r.URL.Query()
+	limit := defaultSuggestions
+	if value := query.Get("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil || limit < 1 || limit > usecases.MaxSuggestions {
+			writeErrorResponse(w, fmt.Sprintf("Limit must be between 1 and %d", usecases.MaxSuggestions), http.StatusBadRequest)
+			return
+		}
+	}
+	language := strings.ToLower(strings.TrimSpace(query.Get("lang")))
+	if language == "" {
+		language = "en"
+	}
+
+	prefix := strings.TrimSpace(query.Get("prefix"))
+	suggestions, err := h.suggest.Execute(spanCtx, prefix, language, limit)
+	if errors.Is(err, usecases.ErrSuggestPrefixTooShort) {
+		writeErrorResponse(w, fmt.Sprintf("Prefix must have at least %d letters", usecases.MinSuggestPrefix), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		h.logger.With(spanCtx).Err(err).Field("prefix", prefix).Error("Suggestions failed")
+		writeErrorResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	response := struct {
+		Prefix      string                    `json:"prefix"`
+		Suggestions []entities.WordSuggestion `json:"suggestions"`
+	}{Prefix: prefix, Suggestions: suggestions}
+	if h.cacheMaxAge > 0 {
+		writeCacheableJSONResponse(w, r, response, h.cacheMaxAge)
+		return
+	}
+	writeJSONResponse(w, response, http.StatusOK)
+}
+
+// profilePreferences returns the bot preferences of the linked account of the request, nil for
+// anonymous requests, identities not linked to the bot and unreadable preferences
+func (h *ArticleHandler) profilePreferences(ctx context.Context) *entities.UserPreferences {
+	identity := usecases.IdentityFromContext(ctx)
+	if identity == nil || identity.UserID == 0 || h.preferences == nil {
+		return nil
+	}
 
-	// Execute use case
-	response, err := h.useCase.Execute(spanCtx, articleRequest)
+	preferences, err := h.preferences.Get(ctx, identity.UserID)
+	if err != nil {
+		h.logger.With(ctx).Err(err).Warning("Failed to read user preferences")
+		return nil
+	}
+
+	return preferences
+}
+
+// respond executes the lookup of the stage and writes the answer in the schema of the version
+func (h *ArticleHandler) respond(ctx context.Context, w http.ResponseWriter, r *http.Request, version apiVersion, request *entities.ArticleRequest, stage lookupStage, hideHints bool) {
+	var response *entities.ArticleResponse
+	var err error
+	switch stage {
+	case stageCore:
+		response, err = h.useCase.Execute(ctx, request.Core())
+	case stageExamples:
+		response, err = h.useCase.ExecuteExamples(ctx, request)
+	default:
+		response, err = h.useCase.Execute(ctx, request)
+	}
 	if err != nil {
-		h.logger.Error(spanCtx, map[string]interface{}{
-			"message": "Use case execution failed",
-			"error":   err.Error(),
-			"word":    word,
-		})
-		h.writeErrorResponse(w, "Internal server error", http.StatusInternalServerError)
+		h.logger.With(ctx).Err(err).Field("word", request.Word).Error("Use case execution failed")
+		// Degraded answers carry the localized explanation of the missing article
+		if errors.Is(err, usecases.ErrDeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			writeErrorResponse(w, degradedMessage(response, "Request timed out"), http.StatusGatewayTimeout)
+			return
+		}
+		if errors.Is(err, usecases.ErrAIUnavailable) {
+			writeErrorResponse(w, degradedMessage(response, "Service temporarily unavailable"), http.StatusServiceUnavailable)
+			return
+		}
+		writeErrorResponse(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
-	// Write response
-	h.writeJSONResponse(w, response, http.StatusOK)
+	if hideHints && response.Success {
+		response = response.WithoutHints()
+	}
+	response = presenter.ApplyVerbosity(response, request.Verbosity)
+
+	// Complete answers of GET requests are stable per word, language and level, so they may be cached,
+	// answers of linked accounts depend on the profile and answers of tenants on their prompt and quota
+	var maxAge time.Duration
+	if r.Method == http.MethodGet && response.Success && !response.Partial &&
+		usecases.IdentityFromContext(ctx) == nil && usecases.TenantFromContext(ctx) == nil {
+		maxAge = h.cacheMaxAge
+	}
+
+	// Write response in the negotiated schema
+	writeVersionedResponse(w, r, version, response, maxAge)
 }
 
-// setCORSHeaders sets CORS headers to allow all origins
-func (h *ArticleHandler) setCORSHeaders(w http.ResponseWriter) {
-	w.Header().Set("Access-Control-Allow-Origin", "*")
-	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Accept-Language, Authorization")
-	w.Header().Set("Access-Control-Max-Age", "86400") // 24 hours
+// degradedMessage returns the localized error of a degraded answer or the fallback
+func degradedMessage(response *entities.ArticleResponse, fallback string) string {
+	if response == nil || response.Degraded == "" {
+		return fallback
+	}
+
+	return response.Error
 }
 
-func (h *ArticleHandler) extractLanguageFromHeader(acceptLanguage string) string {
+func extractLanguageFromHeader(acceptLanguage string) string {
 	if acceptLanguage == "" {
 		return "en"
 	}
@@ -140,13 +425,46 @@ func (h *ArticleHandler) extractLanguageFromHeader(acceptLanguage string) string
 	return "en"
 }
 
-func (h *ArticleHandler) writeJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-	_ = json.NewEncoder(w).Encode(data)
+const invalidLevelMessage = "Level must be one of A1, A2, B1, B2, C1 or C2"
+
+// parseLevel reads the optional CEFR level of the example sentences, ok is false for unknown levels
+func parseLevel(value string) (level entities.Level, ok bool) {
+	if value == "" {
+		return "", true
+	}
+
+	return entities.ParseLevel(value)
 }
 
-func (h *ArticleHandler) writeErrorResponse(w http.ResponseWriter, message string, statusCode int) {
-	response := entities.NewErrorResponse(message)
-	h.writeJSONResponse(w, response, statusCode)
-}
\ No newline at end of file
+const invalidVerbosityMessage = "Verbosity must be one of minimal, standard or full"
+
+// parseVerbosity reads the optional answer profile, ok is false for unknown profiles
+func parseVerbosity(value string) (verbosity entities.Verbosity, ok bool) {
+	if value == "" {
+		return "", true
+	}
+
+	return entities.ParseVerbosity(value)
+}
+
+// lookupStage is the stage of a staged lookup, the empty stage is a single full lookup
+type lookupStage string
+
+const (
+	// stageCore answers the articles, translations and hints without the example sentences
+	stageCore lookupStage = "core"
+	// stageExamples answers the full lookup continuing the interpretations of the core stage
+	stageExamples lookupStage = "examples"
+)
+
+const invalidStageMessage = "Stage must be core or examples"
+
+// parseStage reads the optional stage of a staged lookup, ok is false for unknown stages
+func parseStage(value string) (stage lookupStage, ok bool) {
+	switch stage = lookupStage(strings.ToLower(strings.TrimSpace(value))); stage {
+	case "", stageCore, stageExamples:
+		return stage, true
+	}
+
+	return "", false
+}
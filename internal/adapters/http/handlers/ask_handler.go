@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/DeryabinSergey/germanarticlebot/internal/application/usecases"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// maxQuestionBytes bounds the size of a grammar question request body
+const maxQuestionBytes = 16 << 10
+
+// AskHandler handles HTTP grammar questions
+type AskHandler struct {
+	useCase *usecases.AskGrammarUseCase
+	logger  logging.Logger
+	tracer  tracing.Tracer
+}
+
+// NewAskHandler creates a new grammar question handler
+func NewAskHandler(useCase *usecases.AskGrammarUseCase, logger logging.Logger, tracer tracing.Tracer) *AskHandler {
+	return &AskHandler{
+		useCase: useCase,
+		logger:  logger,
+		tracer:  tracer,
+	}
+}
+
+// HandleAskRequest answers the "question" of the JSON body in its "language", or in the
+// Accept-Language one, questions are rate limited per profile of linked accounts and per client
+// address otherwise
+func (h *AskHandler) HandleAskRequest(w http.ResponseWriter, r *http.Request) {
+	spanCtx, span := h.tracer.Start(r.Context(), "HTTP Ask Handler")
+	defer span.End()
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var request struct {
+		Question string `json:"question"`
+		Language string `json:"language"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxQuestionBytes)).Decode(&request); err != nil {
+		h.logger.With(spanCtx).Err(err).Warning("Failed to decode JSON body")
+		writeErrorResponse(w, "Invalid JSON format", http.StatusBadRequest)
+		return
+	}
+	if strings.TrimSpace(request.Question) == "" {
+		writeErrorResponse(w, "Question parameter is required", http.StatusBadRequest)
+		return
+	}
+	if request.Language == "" {
+		request.Language = extractLanguageFromHeader(r.Header.Get("Accept-Language"))
+	}
+
+	answer, err := h.useCase.Execute(spanCtx, entities.NewGrammarQuestion(request.Question, request.Language))
+	if errors.Is(err, usecases.ErrTooManyQuestions) {
+		w.Header().Set("Retry-After", "60")
+		writeErrorResponse(w, err.Error(), http.StatusTooManyRequests)
+		return
+	}
+	if err != nil {
+		h.logger.With(spanCtx).Err(err).Error("Grammar question failed")
+		writeErrorResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSONResponse(w, answer, http.StatusOK)
+}
+
+// clientIP returns the address of the client, the first X-Forwarded-For hop behind the Cloud Functions proxy
+func clientIP(r *http.Request) string {
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		first, _, _ := strings.Cut(forwarded, ",")
+		return strings.TrimSpace(first)
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+
+	return r.RemoteAddr
+}
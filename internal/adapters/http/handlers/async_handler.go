@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/DeryabinSergey/germanarticlebot/internal/application/usecases"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+	"net/http"
+)
+
+// AsyncHandler handles lookups answered by a callback instead of the response
+type AsyncHandler struct {
+	useCase *usecases.AsyncLookupUseCase
+	logger  logging.Logger
+	tracer  tracing.Tracer
+}
+
+// NewAsyncHandler creates a new async lookup handler, a nil use case disables async lookups
+func NewAsyncHandler(useCase *usecases.AsyncLookupUseCase, logger logging.Logger, tracer tracing.Tracer) *AsyncHandler {
+	return &AsyncHandler{
+		useCase: useCase,
+		logger:  logger,
+		tracer:  tracer,
+	}
+}
+
+// HandleAsyncRequest enqueues the lookup of the "word" of the JSON body and responds with the job ID,
+// the result is posted to the "callbackUrl" once it's ready
+func (h *AsyncHandler) HandleAsyncRequest(w http.ResponseWriter, r *http.Request) {
+	spanCtx, span := h.tracer.Start(r.Context(), "HTTP Async Handler")
+	defer span.End()
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.useCase == nil {
+		writeErrorResponse(w, "Async lookups are not enabled", http.StatusNotImplemented)
+		return
+	}
+
+	var request struct {
+		Word        string `json:"word"`
+		Level       string `json:"level"`
+		CallbackURL string `json:"callbackUrl"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.logger.With(spanCtx).Err(err).Warning("Failed to decode JSON body")
+		writeBodyErrorResponse(w, err, "Invalid JSON format")
+		return
+	}
+	if request.Word == "" {
+		writeErrorResponse(w, "Word parameter is required", http.StatusBadRequest)
+		return
+	}
+	level, ok := parseLevel(request.Level)
+	if !ok {
+		writeErrorResponse(w, invalidLevelMessage, http.StatusBadRequest)
+		return
+	}
+
+	job, err := h.useCase.Submit(spanCtx, entities.AsyncLookup{
+		Word:        entities.NormalizeWord(request.Word),
+		Language:    extractLanguageFromHeader(r.Header.Get("Accept-Language")),
+		Level:       level,
+		CallbackURL: request.CallbackURL,
+	})
+	if errors.Is(err, usecases.ErrInvalidCallback) {
+		writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		writeErrorResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// The result can be polled at the job status URL besides the callback
+	w.Header().Set("Location", jobLocation(job.ID))
+	writeJSONResponse(w, map[string]interface{}{"success": true, "jobId": job.ID}, http.StatusAccepted)
+}
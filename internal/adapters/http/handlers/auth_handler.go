@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/DeryabinSergey/germanarticlebot/internal/application/usecases"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/services"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+	"net/http"
+	"strings"
+)
+
+// maxLinkBytes bounds the size of an account link request body
+const maxLinkBytes = 1 << 10
+
+// AuthHandler exchanges the link codes of the bot for API tokens and credentials for session tokens
+type AuthHandler struct {
+	accounts *usecases.LinkAccountUseCase
+	sessions *usecases.IssueSessionUseCase
+	logger   logging.Logger
+	tracer   tracing.Tracer
+}
+
+// NewAuthHandler creates a new account link handler, session tokens are disabled without the sessions use case
+func NewAuthHandler(
+	accounts *usecases.LinkAccountUseCase,
+	sessions *usecases.IssueSessionUseCase,
+	logger logging.Logger,
+	tracer tracing.Tracer,
+) *AuthHandler {
+	return &AuthHandler{
+		accounts: accounts,
+		sessions: sessions,
+		logger:   logger,
+		tracer:   tracer,
+	}
+}
+
+// HandleLinkRequest exchanges the "code" of the JSON body, sent by the bot for /link, for an API token
+// of the same profile. The external identity of an authenticated request is linked to the profile.
+func (h *AuthHandler) HandleLinkRequest(w http.ResponseWriter, r *http.Request) {
+	spanCtx, span := h.tracer.Start(r.Context(), "HTTP Account Link Handler")
+	defer span.End()
+
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var request struct {
+		Code string `json:"code"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxLinkBytes)).Decode(&request); err != nil {
+		writeBodyErrorResponse(w, err, "Invalid JSON format")
+		return
+	}
+	if strings.TrimSpace(request.Code) == "" {
+		writeErrorResponse(w, "Code parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	account, err := h.accounts.Link(spanCtx, request.Code, usecases.IdentityFromContext(spanCtx))
+	if errors.Is(err, usecases.ErrInvalidLinkCode) {
+		writeErrorResponse(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if err != nil {
+		h.logger.With(spanCtx).Err(err).Error("Failed to link account")
+		writeErrorResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// The token is only ever shown in this response
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSONResponse(w, account, http.StatusOK)
+}
+
+// HandleTokenRequest exchanges the API token or the ID token of the authenticated request for a
+// short-lived session token, so browsers don't have to keep the long-lived credentials
+func (h *AuthHandler) HandleTokenRequest(w http.ResponseWriter, r *http.Request) {
+	spanCtx, span := h.tracer.Start(r.Context(), "HTTP Session Token Handler")
+	defer span.End()
+
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.sessions == nil {
+		writeErrorResponse(w, "Session tokens are disabled", http.StatusNotFound)
+		return
+	}
+
+	token, err := h.sessions.Execute(spanCtx, usecases.IdentityFromContext(spanCtx))
+	if errors.Is(err, usecases.ErrSessionRenewal) {
+		writeErrorResponse(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		h.logger.With(spanCtx).Err(err).Error("Failed to issue session token")
+		writeErrorResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSONResponse(w, token, http.StatusOK)
+}
+
+// Authentication attaches the identity of the bearer token to the request, an API token of a linked
+// account, the ID token of Google Sign-In or Firebase Auth or a session token of the backend. Requests of linked accounts share the
+// preferences and quotas of the profile with the bot.
+type Authentication struct {
+	accounts *usecases.LinkAccountUseCase
+	logger   logging.Logger
+}
+
+// NewAuthentication creates the authentication middleware
+func NewAuthentication(accounts *usecases.LinkAccountUseCase, logger logging.Logger) *Authentication {
+	return &Authentication{
+		accounts: accounts,
+		logger:   logger,
+	}
+}
+
+// Wrap returns the handler of the request authenticated with the bearer token. Requests without the
+// Authorization header stay anonymous, unknown tokens get 401.
+func (a *Authentication) Wrap(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		authorization := r.Header.Get("Authorization")
+		if authorization == "" {
+			next(w, r)
+			return
+		}
+
+		ctx := r.Context()
+		token, ok := strings.CutPrefix(authorization, "Bearer ")
+		if !ok || token == "" {
+			writeUnauthorized(w, "Authorization must be a bearer token")
+			return
+		}
+		identity, err := a.accounts.Authenticate(ctx, token)
+		if errors.Is(err, services.ErrInvalidIDToken) {
+			a.logger.With(ctx).Err(err).Info("Rejected bearer token")
+			writeUnauthorized(w, "Invalid or expired token")
+			return
+		}
+		if err != nil {
+			a.logger.With(ctx).Err(err).Error("Failed to authenticate request")
+			writeErrorResponse(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		if identity == nil {
+			writeUnauthorized(w, "Invalid or revoked API token")
+			return
+		}
+
+		next(w, r.WithContext(usecases.WithIdentity(ctx, identity)))
+	}
+}
+
+// Require returns the handler of the protected route, anonymous requests get 401
+func (a *Authentication) Require(next http.HandlerFunc) http.HandlerFunc {
+	return a.Wrap(func(w http.ResponseWriter, r *http.Request) {
+		if usecases.IdentityFromContext(r.Context()) == nil {
+			writeUnauthorized(w, "Authorization with a bearer token is required")
+			return
+		}
+
+		next(w, r)
+	})
+}
+
+func writeUnauthorized(w http.ResponseWriter, message string) {
+	w.Header().Set("WWW-Authenticate", "Bearer")
+	writeErrorResponse(w, message, http.StatusUnauthorized)
+}
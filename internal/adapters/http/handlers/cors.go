@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORSPolicy is the cross-origin policy of a route: the methods and request headers browsers may use
+// and the response headers scripts may read
+type CORSPolicy struct {
+	Methods        []string
+	Headers        []string
+	ExposedHeaders []string
+}
+
+var (
+	// ArticleCORSPolicy covers the article lookups with the version negotiation and HTTP caching headers
+	ArticleCORSPolicy = CORSPolicy{
+		Methods:        []string{http.MethodGet, http.MethodPost},
+		Headers:        []string{"Content-Type", "Accept-Language", "Authorization", apiVersionHeader, idempotencyKeyHeader, tenantKeyHeader},
+		ExposedHeaders: []string{apiVersionHeader, "ETag", "Idempotent-Replayed", signatureHeader, signatureKeyIDHeader, quotaRemainingHeader},
+	}
+	// WordCORSPolicy covers the canonical word route, the article-only route and the suggestions, every input
+	// but the API key of tenants is part of the URL
+	WordCORSPolicy = CORSPolicy{
+		Methods:        []string{http.MethodGet},
+		Headers:        []string{tenantKeyHeader},
+		ExposedHeaders: []string{apiVersionHeader, "ETag", signatureHeader, signatureKeyIDHeader, quotaRemainingHeader},
+	}
+	// GraphQLCORSPolicy covers the GraphQL queries, the stats query takes the admin bearer token
+	GraphQLCORSPolicy = CORSPolicy{
+		Methods:        []string{http.MethodGet, http.MethodPost},
+		Headers:        []string{"Content-Type", "Authorization", tenantKeyHeader},
+		ExposedHeaders: []string{signatureHeader, signatureKeyIDHeader, quotaRemainingHeader},
+	}
+	// AuthCORSPolicy covers the account link and session token routes of the web app
+	AuthCORSPolicy = CORSPolicy{
+		Methods: []string{http.MethodPost},
+		Headers: []string{"Content-Type", "Authorization"},
+	}
+	// MeCORSPolicy covers the profile routes of the authenticated user
+	MeCORSPolicy = CORSPolicy{
+		Methods:        []string{http.MethodGet, http.MethodPost},
+		Headers:        []string{"Authorization"},
+		ExposedHeaders: []string{"Location", "Content-Disposition"},
+	}
+	// StreamCORSPolicy covers the Server-Sent Events lookups
+	StreamCORSPolicy = CORSPolicy{
+		Methods: []string{http.MethodGet},
+		Headers: []string{"Accept-Language", tenantKeyHeader},
+	}
+	// TenantCORSPolicy covers the stats and the prompt overrides of the tenant of the API key
+	TenantCORSPolicy = CORSPolicy{
+		Methods: []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete},
+		Headers: []string{"Content-Type", "Accept-Language", tenantKeyHeader},
+	}
+)
+
+// CORS applies the cross-origin policies of the routes for the configured origins
+type CORS struct {
+	origins     []string
+	anyOrigin   bool
+	credentials bool
+	maxAge      time.Duration
+}
+
+// NewCORS creates the CORS middleware, "*" in origins allows every origin. With credentials the
+// allowed origin is echoed instead of "*" since browsers reject credentials for any origin.
+func NewCORS(origins []string, credentials bool, maxAge time.Duration) *CORS {
+	return &CORS{
+		origins:     origins,
+		anyOrigin:   slices.Contains(origins, "*"),
+		credentials: credentials,
+		maxAge:      maxAge,
+	}
+}
+
+// AllowsOrigin reports whether the origin of the request may use the API, requests without an
+// origin don't come from browsers and are always allowed
+func (c *CORS) AllowsOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	return origin == "" || c.anyOrigin || slices.Contains(c.origins, origin)
+}
+
+// Wrap returns the handler with the CORS headers of the policy, OPTIONS requests are answered
+// as preflights without reaching the handler
+func (c *CORS) Wrap(policy CORSPolicy, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		allowed := origin != "" && c.AllowsOrigin(r)
+		if allowed {
+			c.setOrigin(w, origin)
+			if len(policy.ExposedHeaders) > 0 {
+				w.Header().Set("Access-Control-Expose-Headers", strings.Join(policy.ExposedHeaders, ", "))
+			}
+		}
+
+		if r.Method != http.MethodOptions {
+			next(w, r)
+			return
+		}
+
+		// Preflights of disallowed origins and methods get no CORS headers, so the browser blocks the request
+		method := r.Header.Get("Access-Control-Request-Method")
+		if allowed && slices.Contains(policy.Methods, method) {
+			w.Header().Set("Access-Control-Allow-Methods", strings.Join(slices.Concat(policy.Methods, []string{http.MethodOptions}), ", "))
+			if len(policy.Headers) > 0 {
+				w.Header().Set("Access-Control-Allow-Headers", strings.Join(policy.Headers, ", "))
+			}
+			w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(c.maxAge.Seconds())))
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (c *CORS) setOrigin(w http.ResponseWriter, origin string) {
+	if c.anyOrigin && !c.credentials {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", origin)
+	w.Header().Add("Vary", "Origin")
+	if c.credentials {
+		w.Header().Set("Access-Control-Allow-Credentials", "true")
+	}
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"github.com/DeryabinSergey/germanarticlebot/internal/adapters/presenter"
+	"github.com/DeryabinSergey/germanarticlebot/internal/application/usecases"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// embedSecurityPolicy lets any page frame the card, which itself may only use its inline styles
+const embedSecurityPolicy = "default-src 'none'; style-src 'unsafe-inline'; frame-ancestors *"
+
+// EmbedHandler serves lookups as HTML cards for iframes of blogs and learning sites
+type EmbedHandler struct {
+	useCase     *usecases.DetermineArticleUseCase
+	presenter   *presenter.Embed
+	cacheMaxAge time.Duration
+	logger      logging.Logger
+	tracer      tracing.Tracer
+}
+
+// NewEmbedHandler creates a new embed handler
+func NewEmbedHandler(useCase *usecases.DetermineArticleUseCase, logger logging.Logger, tracer tracing.Tracer) *EmbedHandler {
+	return &EmbedHandler{
+		useCase:   useCase,
+		presenter: presenter.NewEmbed(),
+		logger:    logger,
+		tracer:    tracer,
+	}
+}
+
+// SetCacheMaxAge lets browsers and CDNs cache the cards of successful lookups for maxAge, zero disables it
+func (h *EmbedHandler) SetCacheMaxAge(maxAge time.Duration) {
+	h.cacheMaxAge = maxAge
+}
+
+// HandleEmbedRequest serves GET /embed?word=Haus&lang=en&level=A2 with a self-contained HTML card
+func (h *EmbedHandler) HandleEmbedRequest(w http.ResponseWriter, r *http.Request) {
+	spanCtx, span := h.tracer.Start(r.Context(), "HTTP Embed Handler")
+	defer span.End()
+
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	word := query.Get("word")
+	if word == "" {
+		writeErrorResponse(w, "Word parameter is required", http.StatusBadRequest)
+		return
+	}
+	level, ok := parseLevel(query.Get("level"))
+	if !ok {
+		writeErrorResponse(w, invalidLevelMessage, http.StatusBadRequest)
+		return
+	}
+
+	request := entities.NewArticleRequest(word, strings.ToLower(strings.TrimSpace(query.Get("lang"))))
+	request.Level = level
+	response, err := h.useCase.Execute(spanCtx, request)
+	if err != nil {
+		h.logger.With(spanCtx).Err(err).Field("word", request.Word).Error("Use case execution failed")
+		if errors.Is(spanCtx.Err(), context.DeadlineExceeded) {
+			writeErrorResponse(w, "Request timed out", http.StatusGatewayTimeout)
+			return
+		}
+		writeErrorResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Security-Policy", embedSecurityPolicy)
+	body := []byte(h.presenter.Format(response))
+	if response.Success && !response.Partial && h.cacheMaxAge > 0 {
+		writeCacheableResponse(w, r, body, "text/html; charset=utf-8", h.cacheMaxAge)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/health"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"net/http"
+)
+
+// HealthHandler handles liveness and readiness probes
+type HealthHandler struct {
+	health *health.Service
+	logger logging.Logger
+}
+
+// NewHealthHandler creates a new health handler
+func NewHealthHandler(health *health.Service, logger logging.Logger) *HealthHandler {
+	return &HealthHandler{
+		health: health,
+		logger: logger,
+	}
+}
+
+// HandleLiveness reports that the process is running
+func (h *HealthHandler) HandleLiveness(w http.ResponseWriter, r *http.Request) {
+	writeJSONResponse(w, h.health.Liveness(r.Context()), http.StatusOK)
+}
+
+// HandleReadiness probes the dependencies and reports whether requests can be served
+func (h *HealthHandler) HandleReadiness(w http.ResponseWriter, r *http.Request) {
+	report := h.health.Readiness(r.Context())
+	if !report.Ready() {
+		h.logger.With(r.Context()).Field("report", report).Error("Readiness check failed")
+		writeJSONResponse(w, report, http.StatusServiceUnavailable)
+		return
+	}
+
+	if report.Status == health.StatusDegraded {
+		h.logger.With(r.Context()).Field("report", report).Warning("Readiness check degraded")
+	}
+	writeJSONResponse(w, report, http.StatusOK)
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/libs/bufpool"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// writeCacheableJSONResponse writes the data with an ETag of its encoding and a Cache-Control header
+// allowing browsers and shared caches to keep it for maxAge, a request already holding the
+// representation gets 304 without a body
+func writeCacheableJSONResponse(w http.ResponseWriter, r *http.Request, data interface{}, maxAge time.Duration) {
+	buf := bufpool.Get()
+	defer bufpool.Put(buf)
+	if err := json.NewEncoder(buf).Encode(data); err != nil {
+		writeErrorResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writeCacheableResponse(w, r, buf.Bytes(), "application/json", maxAge)
+}
+
+// writeCacheableResponse writes the body of the content type with an ETag of its content and a
+// Cache-Control header allowing caches to keep it for maxAge
+func writeCacheableResponse(w http.ResponseWriter, r *http.Request, body []byte, contentType string, maxAge time.Duration) {
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(maxAge.Seconds())))
+
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
+
+// etagMatches reports whether the If-None-Match header lists the ETag, weak validators match as well
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+
+	return false
+}
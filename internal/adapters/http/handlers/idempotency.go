@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/repositories"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	idempotencyKeyHeader = "Idempotency-Key"
+	maxIdempotencyKeyLen = 255
+
+	// maxIdempotentBodyBytes is the largest body of the wrapped routes, the handlers apply their own limits
+	maxIdempotentBodyBytes = maxImportBytes
+)
+
+// replayedHeaders are the response headers of the handlers stored with the response, the CORS and
+// request ID headers are set anew for every request
+var replayedHeaders = []string{"Content-Type", "Content-Disposition", apiVersionHeader, "ETag", "Cache-Control", "Vary"}
+
+// Idempotency replays the stored response to retries of POST requests with the same Idempotency-Key,
+// so retried lookups and imports don't trigger duplicate AI calls
+type Idempotency struct {
+	store  repositories.IdempotencyRepository
+	ttl    time.Duration
+	logger logging.Logger
+}
+
+// NewIdempotency creates the idempotency middleware keeping responses for ttl
+func NewIdempotency(store repositories.IdempotencyRepository, ttl time.Duration, logger logging.Logger) *Idempotency {
+	return &Idempotency{
+		store:  store,
+		ttl:    ttl,
+		logger: logger,
+	}
+}
+
+// Wrap returns the handler answering retries of POST requests with the response of the first one.
+// Requests without the header and other methods reach the handler unchanged.
+func (i *Idempotency) Wrap(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		idempotencyKey := r.Header.Get(idempotencyKeyHeader)
+		if r.Method != http.MethodPost || idempotencyKey == "" {
+			next(w, r)
+			return
+		}
+		if len(idempotencyKey) > maxIdempotencyKeyLen {
+			writeErrorResponse(w, "Idempotency-Key must be at most 255 characters long", http.StatusBadRequest)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotentBodyBytes))
+		if err != nil {
+			writeBodyErrorResponse(w, err, "Failed to read request body")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := r.Context()
+		key := r.URL.Path + "\n" + idempotencyKey
+		fingerprint := requestFingerprint(r, body)
+		stored, err := i.store.Begin(ctx, key, fingerprint, i.ttl)
+		if err != nil {
+			// Without the store the request is processed as if it had no key
+			i.logger.With(ctx).Err(err).Warning("Failed to read idempotency key")
+			next(w, r)
+			return
+		}
+
+		switch {
+		case stored == nil:
+			i.process(w, r, key, fingerprint, next)
+		case stored.Fingerprint != fingerprint:
+			writeErrorResponse(w, "Idempotency-Key was already used with another request", http.StatusUnprocessableEntity)
+		case !stored.Completed:
+			writeErrorResponse(w, "A request with this Idempotency-Key is still being processed", http.StatusConflict)
+		default:
+			for name, value := range stored.Header {
+				w.Header().Set(name, value)
+			}
+			w.Header().Set("Idempotent-Replayed", "true")
+			w.WriteHeader(stored.StatusCode)
+			_, _ = w.Write(stored.Body)
+		}
+	}
+}
+
+// process runs the handler and stores its response, server errors release the key so the retry is processed again
+func (i *Idempotency) process(w http.ResponseWriter, r *http.Request, key, fingerprint string, next http.HandlerFunc) {
+	recorder := &responseRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+	next(recorder, r)
+
+	ctx := r.Context()
+	if recorder.statusCode >= http.StatusInternalServerError {
+		if err := i.store.Release(ctx, key); err != nil {
+			i.logger.With(ctx).Err(err).Warning("Failed to release idempotency key")
+		}
+		return
+	}
+
+	response := &entities.IdempotentResponse{
+		Fingerprint: fingerprint,
+		Completed:   true,
+		StatusCode:  recorder.statusCode,
+		Header:      make(map[string]string),
+		Body:        recorder.body.Bytes(),
+	}
+	for _, name := range replayedHeaders {
+		if value := w.Header().Get(name); value != "" {
+			response.Header[name] = value
+		}
+	}
+	if err := i.store.Complete(ctx, key, response, i.ttl); err != nil {
+		i.logger.With(ctx).Err(err).Warning("Failed to store idempotent response")
+	}
+}
+
+// requestFingerprint hashes the parts of the request that determine its response, the credentials
+// included so another account can't replay the response of a key
+func requestFingerprint(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	for _, part := range []string{
+		r.URL.RequestURI(),
+		r.Header.Get("Content-Type"),
+		r.Header.Get("Accept"),
+		r.Header.Get("Accept-Language"),
+		r.Header.Get(apiVersionHeader),
+		r.Header.Get("Authorization"),
+		r.Header.Get(tenantKeyHeader),
+		strconv.Itoa(len(body)),
+	} {
+		hash.Write([]byte(part + "\n"))
+	}
+	hash.Write(body)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder passes the response through while keeping a copy of its status and body
+type responseRecorder struct {
+	http.ResponseWriter
+	statusCode  int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(statusCode int) {
+	if !r.wroteHeader {
+		r.statusCode, r.wroteHeader = statusCode, true
+	}
+	r.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.wroteHeader = true
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
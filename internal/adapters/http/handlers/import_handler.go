@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/DeryabinSergey/germanarticlebot/internal/adapters/presenter"
+	"github.com/DeryabinSergey/germanarticlebot/internal/application/usecases"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxImportBytes bounds the size of an uploaded word list
+const maxImportBytes = 1 << 20
+
+// ImportHandler handles HTTP uploads of vocabulary lists
+type ImportHandler struct {
+	useCase   *usecases.ImportVocabularyUseCase
+	presenter *presenter.CSV
+	logger    logging.Logger
+	tracer    tracing.Tracer
+}
+
+// NewImportHandler creates a new import handler
+func NewImportHandler(
+	useCase *usecases.ImportVocabularyUseCase,
+	logger logging.Logger,
+	tracer tracing.Tracer,
+) *ImportHandler {
+	return &ImportHandler{
+		useCase:   useCase,
+		presenter: presenter.NewCSV(),
+		logger:    logger,
+		tracer:    tracer,
+	}
+}
+
+// HandleImportRequest accepts a plain text or CSV word list as the body or the "file" form field,
+// and responds with the compiled table as JSON, or as CSV when requested by the Accept header
+func (h *ImportHandler) HandleImportRequest(w http.ResponseWriter, r *http.Request) {
+	spanCtx, span := h.tracer.Start(r.Context(), "HTTP Import Handler")
+	defer span.End()
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportBytes)
+	var body io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			writeErrorResponse(w, "The word list must be sent in the \"file\" form field", http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		body = file
+	}
+
+	words, err := usecases.ParseWordList(body)
+	if err != nil {
+		h.logger.With(spanCtx).Err(err).Warning("Failed to parse word list")
+		writeErrorResponse(w, "Invalid word list", http.StatusBadRequest)
+		return
+	}
+	if len(words) == 0 {
+		writeErrorResponse(w, "The word list is empty", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.useCase.Execute(spanCtx, words, extractLanguageFromHeader(r.Header.Get("Accept-Language")))
+	if errors.Is(err, usecases.ErrTooManyWords) {
+		writeErrorResponse(w, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err != nil {
+		h.logger.With(spanCtx).Err(err).Error("Vocabulary import failed")
+		writeErrorResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if strings.Contains(r.Header.Get("Accept"), "text/csv") {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="vocabulary.csv"`)
+		w.WriteHeader(http.StatusOK)
+		if err := h.presenter.FormatImport(w, result); err != nil {
+			h.logger.With(spanCtx).Err(err).Error("Failed to write CSV response")
+		}
+		return
+	}
+
+	writeJSONResponse(w, map[string]interface{}{"success": true, "data": result}, http.StatusOK)
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"github.com/DeryabinSergey/germanarticlebot/internal/application/usecases"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+	"net/http"
+	"strings"
+)
+
+// jobsPath is the prefix of the job status URLs, followed by the job ID
+const jobsPath = "/jobs/"
+
+// JobHandler reports the state of background jobs to the clients that started them
+type JobHandler struct {
+	useCase *usecases.ProcessJobUseCase
+	logger  logging.Logger
+	tracer  tracing.Tracer
+}
+
+// NewJobHandler creates a new job status handler
+func NewJobHandler(useCase *usecases.ProcessJobUseCase, logger logging.Logger, tracer tracing.Tracer) *JobHandler {
+	return &JobHandler{
+		useCase: useCase,
+		logger:  logger,
+		tracer:  tracer,
+	}
+}
+
+// HandleJobRequest serves GET /jobs/{id} with the state, the progress and the result of the job.
+// Job IDs are random UUIDs, knowing one is what entitles a client to read the job.
+func (h *JobHandler) HandleJobRequest(w http.ResponseWriter, r *http.Request) {
+	spanCtx, span := h.tracer.Start(r.Context(), "HTTP Job Handler")
+	defer span.End()
+
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, jobsPath)
+	if id == "" || strings.Contains(id, "/") {
+		writeErrorResponse(w, "Job ID is required", http.StatusBadRequest)
+		return
+	}
+
+	status, ok, err := h.useCase.Status(spanCtx, id)
+	if err != nil {
+		writeErrorResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if !ok {
+		writeErrorResponse(w, "Job not found", http.StatusNotFound)
+		return
+	}
+
+	// The state changes until the job is done, so it must not be cached
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSONResponse(w, status, http.StatusOK)
+}
+
+// jobLocation is the status URL of the job
+func jobLocation(id string) string {
+	return jobsPath + id
+}
@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/application/usecases"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// MeHandler serves the profile of the authenticated user
+type MeHandler struct {
+	learning   *usecases.LearningStatsUseCase
+	deleteData *usecases.DeleteUserDataUseCase
+	export     *usecases.ExportUserDataUseCase
+	search     *usecases.SearchHistoryUseCase
+	logger     logging.Logger
+	tracer     tracing.Tracer
+}
+
+// NewMeHandler creates a new profile handler
+func NewMeHandler(
+	learning *usecases.LearningStatsUseCase,
+	deleteData *usecases.DeleteUserDataUseCase,
+	export *usecases.ExportUserDataUseCase,
+	search *usecases.SearchHistoryUseCase,
+	logger logging.Logger,
+	tracer tracing.Tracer,
+) *MeHandler {
+	return &MeHandler{
+		learning:   learning,
+		deleteData: deleteData,
+		export:     export,
+		search:     search,
+		logger:     logger,
+		tracer:     tracer,
+	}
+}
+
+// HandleStatsRequest returns the learning statistics of the profile shared with the bot, external
+// identities have to be linked to a Telegram account first
+func (h *MeHandler) HandleStatsRequest(w http.ResponseWriter, r *http.Request) {
+	spanCtx, span := h.tracer.Start(r.Context(), "HTTP Learning Stats Handler")
+	defer span.End()
+
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	identity := usecases.IdentityFromContext(spanCtx)
+	if identity == nil || identity.UserID == 0 {
+		writeErrorResponse(w, "Link your account with /link in the bot to see learning statistics", http.StatusForbidden)
+		return
+	}
+
+	stats, err := h.learning.Execute(spanCtx, identity.UserID)
+	if err != nil {
+		writeErrorResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// The statistics are personal and change with every lookup
+	w.Header().Set("Cache-Control", "private, no-store")
+	writeJSONResponse(w, stats, http.StatusOK)
+}
+
+// defaultSearchResults is the number of words found by searches without a limit
+const defaultSearchResults = 20
+
+// HandleSearchRequest searches the words the profile shared with the bot looked up or met in the quiz,
+// GET /me/search?q=haus&limit=20 matches the words and their translations with typos and without umlauts
+func (h *MeHandler) HandleSearchRequest(w http.ResponseWriter, r *http.Request) {
+	spanCtx, span := h.tracer.Start(r.Context(), "HTTP History Search Handler")
+	defer span.End()
+
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	identity := usecases.IdentityFromContext(spanCtx)
+	if identity == nil || identity.UserID == 0 {
+		writeErrorResponse(w, "Link your account with /link in the bot to search your words", http.StatusForbidden)
+		return
+	}
+
+	query := r.URL.Query()
+	limit := defaultSearchResults
+	if value := query.Get("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil || limit < 1 || limit > usecases.MaxSearchResults {
+			writeErrorResponse(w, fmt.Sprintf("Limit must be between 1 and %d", usecases.MaxSearchResults), http.StatusBadRequest)
+			return
+		}
+	}
+
+	matches, err := h.search.Execute(spanCtx, identity.UserID, query.Get("q"), limit)
+	if errors.Is(err, usecases.ErrSearchQueryTooShort) {
+		writeErrorResponse(w, fmt.Sprintf("Query must have at least %d letters", usecases.MinSearchQuery), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		writeErrorResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "private, no-store")
+	writeJSONResponse(w, map[string]interface{}{"words": matches}, http.StatusOK)
+}
+
+// HandleDeleteRequest erases everything stored about the profile shared with the bot. The token of the
+// request is revoked with the linked accounts, the app has to be linked again with /link.
+func (h *MeHandler) HandleDeleteRequest(w http.ResponseWriter, r *http.Request) {
+	spanCtx, span := h.tracer.Start(r.Context(), "HTTP Delete User Data Handler")
+	defer span.End()
+
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	identity := usecases.IdentityFromContext(spanCtx)
+	if identity == nil || identity.UserID == 0 {
+		writeErrorResponse(w, "Only the data of accounts linked with /link in the bot is stored", http.StatusForbidden)
+		return
+	}
+
+	if err := h.deleteData.Execute(spanCtx, identity.UserID); err != nil {
+		writeErrorResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSONResponse(w, map[string]interface{}{"success": true}, http.StatusOK)
+}
+
+// HandleExportRequest starts the export of everything stored about the profile shared with the bot and
+// serves the archive as a JSON file once it is compiled. Without "job" the export is started and the
+// response points to its download with the Location header, the download answers 202 until the job is done.
+func (h *MeHandler) HandleExportRequest(w http.ResponseWriter, r *http.Request) {
+	spanCtx, span := h.tracer.Start(r.Context(), "HTTP Data Export Handler")
+	defer span.End()
+
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	identity := usecases.IdentityFromContext(spanCtx)
+	if identity == nil || identity.UserID == 0 {
+		writeErrorResponse(w, "Only the data of accounts linked with /link in the bot is stored", http.StatusForbidden)
+		return
+	}
+	// The archive is personal and the state of the export changes until it is done
+	w.Header().Set("Cache-Control", "private, no-store")
+
+	id := r.URL.Query().Get("job")
+	if id == "" {
+		job, err := h.export.Submit(spanCtx, identity.UserID)
+		if err != nil {
+			writeErrorResponse(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Location", exportLocation(job.ID))
+		writeJSONResponse(w, map[string]interface{}{"success": true, "jobId": job.ID}, http.StatusAccepted)
+		return
+	}
+
+	status, export, err := h.export.Status(spanCtx, id, identity.UserID)
+	if errors.Is(err, usecases.ErrExportNotFound) {
+		writeErrorResponse(w, "Data export not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		writeErrorResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if export == nil {
+		w.Header().Set("Location", exportLocation(id))
+		writeJSONResponse(w, status, http.StatusAccepted)
+		return
+	}
+
+	w.Header().Set("Content-Disposition", `attachment; filename="german-article-data.json"`)
+	writeJSONResponse(w, export, http.StatusOK)
+}
+
+// exportLocation is the download URL of the data export of the job
+func exportLocation(id string) string {
+	return "/me/export?job=" + url.QueryEscape(id)
+}
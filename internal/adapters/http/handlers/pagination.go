@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// parsePageRequest reads the shared query parameters of list endpoints: limit bounded by maxLimit,
+// the pageToken of the previous page and orderBy as a field of orderFields with an optional
+// "asc" or "desc" direction, e.g. orderBy=createdAt%20desc
+func parsePageRequest(r *http.Request, defaultLimit, maxLimit int, orderFields []string) (entities.PageRequest, error) {
+	query := r.URL.Query()
+	page := entities.PageRequest{
+		Limit:     parseLimit(r, defaultLimit, maxLimit),
+		PageToken: query.Get("pageToken"),
+	}
+
+	orderBy := strings.Fields(query.Get("orderBy"))
+	if len(orderBy) == 0 {
+		return page, nil
+	}
+	if len(orderBy) > 2 || !slices.Contains(orderFields, orderBy[0]) {
+		return page, fmt.Errorf("orderBy must be one of %s with an optional asc or desc", strings.Join(orderFields, ", "))
+	}
+	page.OrderBy = orderBy[0]
+	if len(orderBy) == 2 {
+		switch strings.ToLower(orderBy[1]) {
+		case "asc":
+		case "desc":
+			page.Descending = true
+		default:
+			return page, fmt.Errorf("orderBy direction must be asc or desc")
+		}
+	}
+
+	return page, nil
+}
+
+// writePage writes the items of a page under the key with the token of the next page
+func writePage[T any](w http.ResponseWriter, key string, page entities.Page[T]) {
+	response := map[string]interface{}{key: page.Items}
+	if page.NextPageToken != "" {
+		response["nextPageToken"] = page.NextPageToken
+	}
+
+	writeJSONResponse(w, response, http.StatusOK)
+}
@@ -0,0 +1,16 @@
+package handlers
+
+import (
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"net/http"
+)
+
+// NewRequestContext returns the metadata of an HTTP request, the subject is added by the authentication
+func NewRequestContext(r *http.Request, requestID string) *entities.RequestContext {
+	return &entities.RequestContext{
+		Adapter:   entities.AdapterHTTP,
+		ClientIP:  clientIP(r),
+		Locale:    extractLanguageFromHeader(r.Header.Get("Accept-Language")),
+		RequestID: requestID,
+	}
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/libs/bufpool"
+	"net/http"
+	"strconv"
+)
+
+// writeJSONResponse encodes the data into a pooled buffer before writing the status, so a value that
+// can't be encoded is answered with 500 instead of a truncated body
+func writeJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	buf := bufpool.Get()
+	defer bufpool.Put(buf)
+	if err := json.NewEncoder(buf).Encode(data); err != nil {
+		if statusCode != http.StatusInternalServerError {
+			writeErrorResponse(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(buf.Bytes())
+}
+
+func writeErrorResponse(w http.ResponseWriter, message string, statusCode int) {
+	response := entities.NewErrorResponse(message)
+	writeJSONResponse(w, response, statusCode)
+}
+
+// writeBodyErrorResponse answers an unreadable request body, 413 when it exceeds the size limit
+func writeBodyErrorResponse(w http.ResponseWriter, err error, message string) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		writeErrorResponse(w, "Request body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	writeErrorResponse(w, message, http.StatusBadRequest)
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"bytes"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/services"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"net/http"
+	"strconv"
+)
+
+const (
+	signatureHeader      = "X-Signature"
+	signatureKeyIDHeader = "X-Signature-Key-Id"
+
+	// KeysPath publishes the public keys verifying the signatures as a JSON Web Key Set
+	KeysPath = "/.well-known/jwks.json"
+)
+
+// ResponseSigning signs the bodies of the wrapped routes, so consumers embedding the answers in their
+// own apps can verify them. The signature covers the exact bytes of the body.
+type ResponseSigning struct {
+	signer services.ResponseSigner
+	logger logging.Logger
+}
+
+// NewResponseSigning creates the signing middleware, responses pass unsigned without a signer
+func NewResponseSigning(signer services.ResponseSigner, logger logging.Logger) *ResponseSigning {
+	return &ResponseSigning{
+		signer: signer,
+		logger: logger,
+	}
+}
+
+// Wrap returns the handler whose responses carry the signature and the key ID headers. The response
+// is buffered until the handler returns since the headers precede the body.
+func (s *ResponseSigning) Wrap(next http.HandlerFunc) http.HandlerFunc {
+	if s.signer == nil {
+		return next
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		buffer := &bufferedResponse{header: make(http.Header), statusCode: http.StatusOK}
+		next(buffer, r)
+
+		header := w.Header()
+		for name, values := range buffer.header {
+			header[name] = values
+		}
+		if buffer.body.Len() > 0 {
+			signature, keyID, err := s.signer.Sign(r.Context(), buffer.body.Bytes())
+			if err != nil {
+				// Consumers reject unsigned responses themselves, failing the request wouldn't help them
+				s.logger.With(r.Context()).Err(err).Error("Failed to sign response")
+			} else {
+				header.Set(signatureHeader, signature)
+				header.Set(signatureKeyIDHeader, keyID)
+			}
+			header.Set("Content-Length", strconv.Itoa(buffer.body.Len()))
+		}
+		w.WriteHeader(buffer.statusCode)
+		_, _ = w.Write(buffer.body.Bytes())
+	}
+}
+
+// HandleKeys publishes the public keys, 404 when responses aren't signed or the keys are secret
+func (s *ResponseSigning) HandleKeys(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.signer == nil {
+		writeErrorResponse(w, "Responses are not signed", http.StatusNotFound)
+		return
+	}
+
+	keys, err := s.signer.PublicKeys(r.Context())
+	if err != nil {
+		s.logger.With(r.Context()).Err(err).Error("Failed to read verification keys")
+		writeErrorResponse(w, "Failed to read verification keys", http.StatusInternalServerError)
+		return
+	}
+	if len(keys) == 0 {
+		writeErrorResponse(w, "Responses are signed with a shared secret", http.StatusNotFound)
+		return
+	}
+
+	writeJSONResponse(w, struct {
+		Keys []entities.VerificationKey `json:"keys"`
+	}{keys}, http.StatusOK)
+}
+
+// bufferedResponse holds the response of the handler until it's signed
+type bufferedResponse struct {
+	header      http.Header
+	statusCode  int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (b *bufferedResponse) Header() http.Header {
+	return b.header
+}
+
+func (b *bufferedResponse) WriteHeader(statusCode int) {
+	if !b.wroteHeader {
+		b.statusCode, b.wroteHeader = statusCode, true
+	}
+}
+
+func (b *bufferedResponse) Write(data []byte) (int, error) {
+	b.wroteHeader = true
+	return b.body.Write(data)
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"encoding/json"
+	"github.com/DeryabinSergey/germanarticlebot/internal/application/usecases"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+	"github.com/DeryabinSergey/germanarticlebot/libs/bufpool"
+	"net/http"
+)
+
+// SSEHandler streams word lookups as Server-Sent Events
+type SSEHandler struct {
+	useCase *usecases.StreamArticleUseCase
+	logger  logging.Logger
+	tracer  tracing.Tracer
+}
+
+// NewSSEHandler creates a new Server-Sent Events handler
+func NewSSEHandler(
+	useCase *usecases.StreamArticleUseCase,
+	logger logging.Logger,
+	tracer tracing.Tracer,
+) *SSEHandler {
+	return &SSEHandler{
+		useCase: useCase,
+		logger:  logger,
+		tracer:  tracer,
+	}
+}
+
+// HandleArticleStream responds to GET requests with the article, translation, examples, error and done
+// events of the lookup, the event data is the JSON encoded stream event
+func (h *SSEHandler) HandleArticleStream(w http.ResponseWriter, r *http.Request) {
+	spanCtx, span := h.tracer.Start(r.Context(), "HTTP SSE Handler")
+	defer span.End()
+
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeErrorResponse(w, "Streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	word := r.URL.Query().Get("word")
+	if word == "" {
+		writeErrorResponse(w, "Word parameter is required", http.StatusBadRequest)
+		return
+	}
+	level, ok := parseLevel(r.URL.Query().Get("level"))
+	if !ok {
+		writeErrorResponse(w, invalidLevelMessage, http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	request := entities.NewArticleRequest(word, extractLanguageFromHeader(r.Header.Get("Accept-Language")))
+	request.Level = level
+	err := h.useCase.Execute(spanCtx, request, func(event entities.StreamEvent) error {
+		buf := bufpool.Get()
+		defer bufpool.Put(buf)
+		buf.WriteString("event: ")
+		buf.WriteString(string(event.Type))
+		buf.WriteString("\ndata: ")
+		// The encoder ends the data with a newline, the second one ends the event
+		if err := json.NewEncoder(buf).Encode(event); err != nil {
+			return err
+		}
+		buf.WriteByte('\n')
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return err
+		}
+		flusher.Flush()
+		return nil
+	})
+	if err != nil {
+		// The client has disconnected, so there is nobody to report the error to
+		h.logger.With(spanCtx).Err(err).Field("word", word).Warning("Failed to write event stream")
+	}
+}
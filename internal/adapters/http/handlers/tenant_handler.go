@@ -0,0 +1,260 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/DeryabinSergey/germanarticlebot/internal/application/usecases"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// tenantKeyHeader carries the API key of the tenant, it can be combined with the bearer token of a user
+	tenantKeyHeader = "X-API-Key"
+	// quotaRemainingHeader reports the requests the tenant has left on the UTC day
+	quotaRemainingHeader = "X-Quota-Remaining"
+)
+
+// Tenancy attaches the tenant of the API key to the request and counts the request against the daily
+// quota of the tenant
+type Tenancy struct {
+	tenants *usecases.TenantUseCase
+	logger  logging.Logger
+}
+
+// NewTenancy creates the tenancy middleware
+func NewTenancy(tenants *usecases.TenantUseCase, logger logging.Logger) *Tenancy {
+	return &Tenancy{
+		tenants: tenants,
+		logger:  logger,
+	}
+}
+
+// Wrap returns the handler of the request of the tenant. Requests without the X-API-Key header are of no
+// tenant, unknown keys get 401 and requests beyond the daily quota 429.
+func (t *Tenancy) Wrap(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(tenantKeyHeader) == "" {
+			next(w, r)
+			return
+		}
+
+		ctx := r.Context()
+		tenant, ok := t.authenticate(w, r)
+		if !ok {
+			return
+		}
+		remaining, err := t.tenants.Admit(ctx, tenant)
+		if err != nil {
+			retryAfter := math.Ceil(time.Until(t.tenants.QuotaReset()).Seconds())
+			w.Header().Set("Retry-After", strconv.Itoa(int(max(retryAfter, 1))))
+			w.Header().Set(quotaRemainingHeader, "0")
+			writeErrorResponse(w, "The daily request quota of the API key is used up", http.StatusTooManyRequests)
+			return
+		}
+		if remaining >= 0 {
+			w.Header().Set(quotaRemainingHeader, strconv.FormatInt(remaining, 10))
+		}
+
+		next(w, r.WithContext(usecases.WithTenant(ctx, tenant)))
+	}
+}
+
+// Require returns the handler of a route of tenants, requests without a valid API key get 401. The
+// requests aren't counted against the quota.
+func (t *Tenancy) Require(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(tenantKeyHeader) == "" {
+			writeErrorResponse(w, "The X-API-Key header with the API key of the tenant is required", http.StatusUnauthorized)
+			return
+		}
+
+		tenant, ok := t.authenticate(w, r)
+		if !ok {
+			return
+		}
+
+		next(w, r.WithContext(usecases.WithTenant(r.Context(), tenant)))
+	}
+}
+
+// authenticate returns the tenant of the API key of the request, unknown keys are answered with 401
+func (t *Tenancy) authenticate(w http.ResponseWriter, r *http.Request) (*entities.Tenant, bool) {
+	tenant := t.tenants.Authenticate(r.Context(), r.Header.Get(tenantKeyHeader))
+	if tenant == nil {
+		t.logger.With(r.Context()).Info("Rejected tenant API key")
+		writeErrorResponse(w, "Invalid API key", http.StatusUnauthorized)
+		return nil, false
+	}
+
+	return tenant, true
+}
+
+// TenantHandler serves the routes of the tenant of the API key
+type TenantHandler struct {
+	tenants *usecases.TenantUseCase
+	prompts *usecases.TenantPromptUseCase
+	logger  logging.Logger
+	tracer  tracing.Tracer
+}
+
+// NewTenantHandler creates a new tenant handler
+func NewTenantHandler(
+	tenants *usecases.TenantUseCase,
+	prompts *usecases.TenantPromptUseCase,
+	logger logging.Logger,
+	tracer tracing.Tracer,
+) *TenantHandler {
+	return &TenantHandler{
+		tenants: tenants,
+		prompts: prompts,
+		logger:  logger,
+		tracer:  tracer,
+	}
+}
+
+// HandleStatsRequest returns the usage of the tenant of the API key, tenants only see their own
+func (h *TenantHandler) HandleStatsRequest(w http.ResponseWriter, r *http.Request) {
+	spanCtx, span := h.tracer.Start(r.Context(), "HTTP Tenant Stats Handler")
+	defer span.End()
+
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	tenant := usecases.TenantFromContext(spanCtx)
+	if tenant == nil {
+		writeErrorResponse(w, "The X-API-Key header with the API key of the tenant is required", http.StatusUnauthorized)
+		return
+	}
+
+	stats, err := h.tenants.Stats(spanCtx, tenant.ID, parseLimit(r, defaultTopWords, maxTopWords))
+	if err != nil {
+		writeErrorResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "private, no-store")
+	writeJSONResponse(w, stats, http.StatusOK)
+}
+
+// promptRequest is the body of the prompt overrides of a tenant
+type promptRequest struct {
+	Tone           string   `json:"tone"`
+	ExampleDomains []string `json:"exampleDomains"`
+	AudienceAge    int      `json:"audienceAge"`
+}
+
+// HandlePromptRequest shows (GET), replaces (PUT) and removes (DELETE) the prompt overrides of the tenant
+// of the API key
+func (h *TenantHandler) HandlePromptRequest(w http.ResponseWriter, r *http.Request) {
+	spanCtx, span := h.tracer.Start(r.Context(), "HTTP Tenant Prompt Handler")
+	defer span.End()
+
+	tenant := usecases.TenantFromContext(spanCtx)
+	if tenant == nil {
+		writeErrorResponse(w, "The X-API-Key header with the API key of the tenant is required", http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		overrides, err := h.prompts.Get(spanCtx, tenant.ID)
+		if err != nil {
+			writeErrorResponse(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Cache-Control", "private, no-store")
+		writeJSONResponse(w, overrides, http.StatusOK)
+	case http.MethodPut:
+		overrides, ok := h.decodePrompt(w, r)
+		if !ok {
+			return
+		}
+		overrides.TenantID = tenant.ID
+		saved, err := h.prompts.Save(spanCtx, overrides)
+		if errors.Is(err, usecases.ErrInvalidPromptOverrides) {
+			writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			writeErrorResponse(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		writeJSONResponse(w, saved, http.StatusOK)
+	case http.MethodDelete:
+		if err := h.prompts.Delete(spanCtx, tenant.ID); err != nil {
+			writeErrorResponse(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		writeJSONResponse(w, map[string]interface{}{"success": true}, http.StatusOK)
+	default:
+		w.Header().Set("Allow", strings.Join([]string{http.MethodGet, http.MethodPut, http.MethodDelete}, ", "))
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// HandlePromptPreviewRequest renders the prompt of a lookup of the "word" query parameter with the prompt
+// overrides of the body without saving them, an empty body previews the saved overrides. Nothing is
+// generated, so previews aren't counted against the quota.
+func (h *TenantHandler) HandlePromptPreviewRequest(w http.ResponseWriter, r *http.Request) {
+	spanCtx, span := h.tracer.Start(r.Context(), "HTTP Tenant Prompt Preview Handler")
+	defer span.End()
+
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	tenant := usecases.TenantFromContext(spanCtx)
+	if tenant == nil {
+		writeErrorResponse(w, "The X-API-Key header with the API key of the tenant is required", http.StatusUnauthorized)
+		return
+	}
+
+	var overrides *entities.PromptOverrides
+	if r.ContentLength != 0 {
+		var ok bool
+		if overrides, ok = h.decodePrompt(w, r); !ok {
+			return
+		}
+		overrides.TenantID = tenant.ID
+	}
+
+	language := extractLanguageFromHeader(r.Header.Get("Accept-Language"))
+	preview, err := h.prompts.Preview(spanCtx, tenant, overrides, r.URL.Query().Get("word"), language)
+	if errors.Is(err, usecases.ErrInvalidPromptOverrides) {
+		writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		writeErrorResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSONResponse(w, preview, http.StatusOK)
+}
+
+// decodePrompt decodes the prompt overrides of the body, invalid bodies are answered with 400
+func (h *TenantHandler) decodePrompt(w http.ResponseWriter, r *http.Request) (*entities.PromptOverrides, bool) {
+	var request promptRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		h.logger.With(r.Context()).Err(err).Warning("Failed to decode prompt overrides")
+		writeBodyErrorResponse(w, err, "Invalid JSON format")
+		return nil, false
+	}
+
+	return &entities.PromptOverrides{
+		Tone:           request.Tone,
+		ExampleDomains: request.ExampleDomains,
+		AudienceAge:    request.AudienceAge,
+	}, true
+}
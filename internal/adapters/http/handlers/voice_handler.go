@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/DeryabinSergey/germanarticlebot/internal/adapters/presenter"
+	"github.com/DeryabinSergey/germanarticlebot/internal/application/usecases"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+	"net/http"
+	"time"
+)
+
+const (
+	getArticleIntent = "GetArticleIntent"
+	wordSlot         = "word"
+	// voiceLanguage is the language of the spoken sentences, so translations are requested in it too
+	voiceLanguage = "en"
+
+	welcomeSpeech  = "<speak>Name a German noun, and I'll tell you its article.</speak>"
+	repromptSpeech = "<speak>Which word should I look up?</speak>"
+	goodbyeSpeech  = "<speak>Tschüss!</speak>"
+
+	// maxAlexaRequestAge is the allowed clock skew of Alexa request timestamps
+	maxAlexaRequestAge = 150 * time.Second
+)
+
+// alexaRequest is the part of the Alexa skill request used by the handler
+type alexaRequest struct {
+	Session struct {
+		Application struct {
+			ApplicationID string `json:"applicationId"`
+		} `json:"application"`
+	} `json:"session"`
+	Request struct {
+		Type      string    `json:"type"`
+		Timestamp time.Time `json:"timestamp"`
+		Intent    struct {
+			Name  string `json:"name"`
+			Slots map[string]struct {
+				Value string `json:"value"`
+			} `json:"slots"`
+		} `json:"intent"`
+	} `json:"request"`
+}
+
+// alexaResponse is the Alexa skill response with SSML speech
+type alexaResponse struct {
+	Version  string `json:"version"`
+	Response struct {
+		OutputSpeech alexaSpeech  `json:"outputSpeech"`
+		Reprompt     *alexaPrompt `json:"reprompt,omitempty"`
+		EndSession   bool         `json:"shouldEndSession"`
+	} `json:"response"`
+}
+
+type alexaPrompt struct {
+	OutputSpeech alexaSpeech `json:"outputSpeech"`
+}
+
+type alexaSpeech struct {
+	Type string `json:"type"`
+	SSML string `json:"ssml"`
+}
+
+// googleRequest is the part of the Google Assistant (Actions Builder) webhook request used by the handler
+type googleRequest struct {
+	Intent struct {
+		Name   string `json:"name"`
+		Params map[string]struct {
+			Original string `json:"original"`
+			Resolved string `json:"resolved"`
+		} `json:"params"`
+	} `json:"intent"`
+	Session struct {
+		ID string `json:"id"`
+	} `json:"session"`
+}
+
+// googleResponse is the Google Assistant webhook response with SSML speech
+type googleResponse struct {
+	Session struct {
+		ID     string            `json:"id"`
+		Params map[string]string `json:"params"`
+	} `json:"session"`
+	Prompt struct {
+		Override    bool `json:"override"`
+		FirstSimple struct {
+			Speech string `json:"speech"`
+			Text   string `json:"text,omitempty"`
+		} `json:"firstSimple"`
+	} `json:"prompt"`
+}
+
+// VoiceHandler handles intent requests of the Alexa skill and the Google Assistant action
+type VoiceHandler struct {
+	useCase   *usecases.DetermineArticleUseCase
+	presenter *presenter.Voice
+	skillID   string
+	logger    logging.Logger
+	tracer    tracing.Tracer
+}
+
+// NewVoiceHandler creates a new voice assistant handler, a non-empty skill ID rejects Alexa requests of other skills
+func NewVoiceHandler(
+	useCase *usecases.DetermineArticleUseCase,
+	skillID string,
+	logger logging.Logger,
+	tracer tracing.Tracer,
+) *VoiceHandler {
+	return &VoiceHandler{
+		useCase:   useCase,
+		presenter: presenter.NewVoice(),
+		skillID:   skillID,
+		logger:    logger,
+		tracer:    tracer,
+	}
+}
+
+// HandleAlexaRequest answers launch, GetArticleIntent and the built-in help, stop and cancel intents
+func (h *VoiceHandler) HandleAlexaRequest(w http.ResponseWriter, r *http.Request) {
+	spanCtx, span := h.tracer.Start(r.Context(), "HTTP Alexa Handler")
+	defer span.End()
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var request alexaRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeBodyErrorResponse(w, err, "Invalid JSON format")
+		return
+	}
+	if h.skillID != "" && request.Session.Application.ApplicationID != h.skillID {
+		h.logger.With(spanCtx).Field("applicationId", request.Session.Application.ApplicationID).Warning("Alexa request of unknown skill")
+		writeErrorResponse(w, "Unknown skill", http.StatusForbidden)
+		return
+	}
+	if age := time.Since(request.Request.Timestamp); age > maxAlexaRequestAge || age < -maxAlexaRequestAge {
+		writeErrorResponse(w, "Request timestamp is out of range", http.StatusBadRequest)
+		return
+	}
+
+	var response alexaResponse
+	response.Version = "1.0"
+	speech, endSession := "", true
+	switch {
+	case request.Request.Type == "LaunchRequest",
+		request.Request.Type == "IntentRequest" && request.Request.Intent.Name == "AMAZON.HelpIntent":
+		speech, endSession = welcomeSpeech, false
+		response.Response.Reprompt = &alexaPrompt{OutputSpeech: alexaSpeech{Type: "SSML", SSML: repromptSpeech}}
+	case request.Request.Type == "IntentRequest" && request.Request.Intent.Name == getArticleIntent:
+		speech, _ = h.lookup(spanCtx, request.Request.Intent.Slots[wordSlot].Value)
+	default:
+		// Stop, cancel, session end and unknown intents
+		speech = goodbyeSpeech
+	}
+	response.Response.OutputSpeech = alexaSpeech{Type: "SSML", SSML: speech}
+	response.Response.EndSession = endSession
+
+	writeJSONResponse(w, response, http.StatusOK)
+}
+
+// HandleGoogleRequest answers the GetArticleIntent webhook calls of the Google Assistant action
+func (h *VoiceHandler) HandleGoogleRequest(w http.ResponseWriter, r *http.Request) {
+	spanCtx, span := h.tracer.Start(r.Context(), "HTTP Google Assistant Handler")
+	defer span.End()
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var request googleRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeBodyErrorResponse(w, err, "Invalid JSON format")
+		return
+	}
+
+	var response googleResponse
+	response.Session.ID = request.Session.ID
+	response.Session.Params = map[string]string{}
+	if request.Intent.Name != getArticleIntent {
+		response.Prompt.FirstSimple.Speech = welcomeSpeech
+		writeJSONResponse(w, response, http.StatusOK)
+		return
+	}
+
+	param := request.Intent.Params[wordSlot]
+	word := param.Resolved
+	if word == "" {
+		word = param.Original
+	}
+	response.Prompt.FirstSimple.Speech, response.Prompt.FirstSimple.Text = h.lookup(spanCtx, word)
+
+	writeJSONResponse(w, response, http.StatusOK)
+}
+
+// lookup returns the SSML and the plain text answer for the word
+func (h *VoiceHandler) lookup(ctx context.Context, word string) (speech, text string) {
+	if word == "" {
+		return repromptSpeech, ""
+	}
+
+	// The spoken answer has no examples, so they aren't generated
+	request := entities.NewArticleRequest(word, voiceLanguage)
+	request.ArticleOnly = true
+	response, err := h.useCase.Execute(ctx, request)
+	if err != nil {
+		h.logger.With(ctx).Err(err).Field("word", word).Error("Voice lookup failed")
+	}
+
+	return h.presenter.Format(response), h.presenter.FormatText(response)
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/application/usecases"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+	"github.com/gorilla/websocket"
+	"net/http"
+	"time"
+)
+
+const (
+	maxWebSocketMessageBytes = 4096
+	webSocketIdleTimeout     = 5 * time.Minute
+	webSocketWriteTimeout    = 10 * time.Second
+)
+
+// lookupMessage is a word lookup sent by the client, the ID is echoed in every event of the lookup
+type lookupMessage struct {
+	ID       string `json:"id,omitempty"`
+	Word     string `json:"word"`
+	Language string `json:"language,omitempty"`
+	Level    string `json:"level,omitempty"`
+}
+
+// lookupEvent is a stream event of the lookup sent to the client
+type lookupEvent struct {
+	ID string `json:"id,omitempty"`
+	entities.StreamEvent
+}
+
+// WebSocketHandler streams word lookups to web clients over WebSocket connections
+type WebSocketHandler struct {
+	useCase  *usecases.StreamArticleUseCase
+	upgrader websocket.Upgrader
+	logger   logging.Logger
+	tracer   tracing.Tracer
+}
+
+// NewWebSocketHandler creates a new WebSocket handler
+func NewWebSocketHandler(
+	useCase *usecases.StreamArticleUseCase,
+	cors *CORS,
+	logger logging.Logger,
+	tracer tracing.Tracer,
+) *WebSocketHandler {
+	return &WebSocketHandler{
+		useCase: useCase,
+		upgrader: websocket.Upgrader{
+			// Browsers connect from the same origins as to the article endpoint
+			CheckOrigin: cors.AllowsOrigin,
+		},
+		logger: logger,
+		tracer: tracer,
+	}
+}
+
+// HandleWebSocket upgrades the connection and answers lookup messages until the client disconnects,
+// lookups are processed one after another in the order they are received
+func (h *WebSocketHandler) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
+	spanCtx, span := h.tracer.Start(r.Context(), "WebSocket Handler")
+	defer span.End()
+
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// The upgrader has already responded with the error status
+		h.logger.With(spanCtx).Err(err).Warning("Failed to upgrade WebSocket connection")
+		return
+	}
+	defer conn.Close()
+
+	conn.SetReadLimit(maxWebSocketMessageBytes)
+	defaultLanguage := extractLanguageFromHeader(r.Header.Get("Accept-Language"))
+	for {
+		_ = conn.SetReadDeadline(time.Now().Add(webSocketIdleTimeout))
+
+		var message lookupMessage
+		if err := conn.ReadJSON(&message); err != nil {
+			if !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				h.logger.With(spanCtx).Err(err).Debug("WebSocket connection closed")
+			}
+			return
+		}
+
+		language := message.Language
+		if language == "" {
+			language = defaultLanguage
+		}
+		request := entities.NewArticleRequest(message.Word, language)
+		level, ok := parseLevel(message.Level)
+		request.Level = level
+		var err error
+		if ok {
+			err = h.lookup(spanCtx, conn, message.ID, request)
+		} else {
+			err = conn.WriteJSON(lookupEvent{ID: message.ID, StreamEvent: entities.StreamEvent{
+				Type:  entities.StreamEventError,
+				Error: invalidLevelMessage,
+			}})
+		}
+		if err != nil {
+			h.logger.With(spanCtx).Err(err).Warning("Failed to write WebSocket event")
+			return
+		}
+	}
+}
+
+// lookup streams the events of a single lookup to the connection
+func (h *WebSocketHandler) lookup(ctx context.Context, conn *websocket.Conn, id string, request *entities.ArticleRequest) error {
+	return h.useCase.Execute(ctx, request, func(event entities.StreamEvent) error {
+		_ = conn.SetWriteDeadline(time.Now().Add(webSocketWriteTimeout))
+		return conn.WriteJSON(lookupEvent{ID: id, StreamEvent: event})
+	})
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"github.com/DeryabinSergey/germanarticlebot/internal/application/usecases"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/jobs"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/secrets"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+	"net/http"
+	"strconv"
+)
+
+// WorkerHandler handles the deliveries of queued background jobs
+type WorkerHandler struct {
+	token   secrets.Source
+	useCase *usecases.ProcessJobUseCase
+	logger  logging.Logger
+	tracer  tracing.Tracer
+}
+
+// NewWorkerHandler creates a new worker handler, an empty token rejects all deliveries
+func NewWorkerHandler(
+	token secrets.Source,
+	useCase *usecases.ProcessJobUseCase,
+	logger logging.Logger,
+	tracer tracing.Tracer,
+) *WorkerHandler {
+	return &WorkerHandler{
+		token:   token,
+		useCase: useCase,
+		logger:  logger,
+		tracer:  tracer,
+	}
+}
+
+// HandleWorkerRequest processes a delivered job, a non-2xx status makes the queue retry the delivery
+func (h *WorkerHandler) HandleWorkerRequest(w http.ResponseWriter, r *http.Request) {
+	spanCtx, span := h.tracer.Start(r.Context(), "HTTP Worker Handler")
+	defer span.End()
+
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !h.authorize(spanCtx, r) {
+		h.logger.With(spanCtx).Warning("Unauthorized worker request")
+		writeErrorResponse(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var job entities.Job
+	if err := json.NewDecoder(r.Body).Decode(&job); err != nil {
+		h.logger.With(spanCtx).Err(err).Error("Failed to decode job")
+		// A malformed job never succeeds, so it is acknowledged to stop the retries
+		writeJSONResponse(w, map[string]string{"status": "dropped"}, http.StatusOK)
+		return
+	}
+	job.Attempt, _ = strconv.Atoi(r.Header.Get(jobs.RetryCountHeader))
+
+	if err := h.useCase.Execute(spanCtx, &job); err != nil {
+		if errors.Is(err, usecases.ErrInvalidJob) {
+			writeJSONResponse(w, map[string]string{"status": "dropped"}, http.StatusOK)
+			return
+		}
+		writeErrorResponse(w, "Job failed", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSONResponse(w, map[string]string{"status": "done"}, http.StatusOK)
+}
+
+// authorize checks the worker token header against the configured token
+func (h *WorkerHandler) authorize(ctx context.Context, r *http.Request) bool {
+	expected, err := h.token(ctx)
+	if err != nil {
+		h.logger.With(ctx).Err(err).Error("Failed to read worker token")
+		return false
+	}
+	if expected == "" {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(r.Header.Get(jobs.WorkerTokenHeader)), []byte(expected)) == 1
+}
@@ -0,0 +1,74 @@
+package mcp
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaOf generates the JSON Schema of the type from its json tags, fields without omitempty
+// are required and the description tag documents a field
+func schemaOf(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": schemaOf(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": schemaOf(t.Elem())}
+	case reflect.Struct:
+		return structSchema(t)
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+func structSchema(t reflect.Type) map[string]interface{} {
+	properties := make(map[string]interface{})
+	required := []string{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property := schemaOf(field.Type)
+		if description := field.Tag.Get("description"); description != "" {
+			property["description"] = description
+		}
+		properties[name] = property
+		if !strings.Contains(options, "omitempty") {
+			required = append(required, name)
+		}
+	}
+
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+}
@@ -0,0 +1,253 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/application/usecases"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+	"github.com/DeryabinSergey/germanarticlebot/libs/requestid"
+	"io"
+	"net/http"
+	"reflect"
+	"slices"
+)
+
+const (
+	serverName    = "german-article-bot"
+	serverVersion = "1.0.0"
+	toolName      = "lookup_german_article"
+
+	// JSON-RPC error codes
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+
+	maxMessageBytes = 1 << 20
+)
+
+// supportedVersions are the MCP protocol revisions the server speaks, the first one is preferred
+var supportedVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// lookupInput is the arguments of the lookup tool, its schema is published to the clients
+type lookupInput struct {
+	Word     string `json:"word" description:"German noun to look up, e.g. Haus"`
+	Language string `json:"language,omitempty" description:"ISO 639-1 code of the translation and error language, defaults to en"`
+}
+
+type rpcMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Server exposes the article lookup as a Model Context Protocol tool server
+type Server struct {
+	useCase *usecases.DetermineArticleUseCase
+	tools   []map[string]interface{}
+	logger  logging.Logger
+	tracer  tracing.Tracer
+}
+
+// NewServer creates a new MCP server, the tool schemas are generated from the input and response types
+func NewServer(
+	useCase *usecases.DetermineArticleUseCase,
+	logger logging.Logger,
+	tracer tracing.Tracer,
+) *Server {
+	return &Server{
+		useCase: useCase,
+		tools: []map[string]interface{}{{
+			"name":         toolName,
+			"title":        "German article lookup",
+			"description":  "Determines the article (der, die, das) of a German noun with its translation, plural and example sentences in all cases. Words with several meanings return one entry per meaning.",
+			"inputSchema":  schemaOf(reflect.TypeOf(lookupInput{})),
+			"outputSchema": schemaOf(reflect.TypeOf(entities.ArticleResponse{})),
+		}},
+		logger: logger,
+		tracer: tracer,
+	}
+}
+
+// ServeStdio reads newline delimited JSON-RPC messages from in and writes the responses to out
+// until in is closed or the context is cancelled
+func (s *Server) ServeStdio(ctx context.Context, in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxMessageBytes)
+	encoder := json.NewEncoder(out)
+
+	for scanner.Scan() {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		response := s.Handle(requestid.NewContext(ctx, requestid.New()), scanner.Bytes())
+		if response == nil {
+			continue
+		}
+		if err := encoder.Encode(response); err != nil {
+			return fmt.Errorf("failed to write MCP response: %w", err)
+		}
+	}
+
+	return scanner.Err()
+}
+
+// HandleHTTP serves the Streamable HTTP transport without server-initiated streams: every POST
+// carries one message and requests are answered with a JSON response
+func (s *Server) HandleHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxMessageBytes))
+	if err != nil {
+		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		return
+	}
+
+	response := s.Handle(r.Context(), body)
+	if response == nil {
+		// Notifications and responses are only acknowledged
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// Handle processes a single JSON-RPC message, the response is nil for notifications
+func (s *Server) Handle(ctx context.Context, data []byte) *rpcResponse {
+	spanCtx, span := s.tracer.Start(ctx, "MCP Handle")
+	defer span.End()
+
+	var message rpcMessage
+	if err := json.Unmarshal(data, &message); err != nil {
+		return errorResponse(json.RawMessage("null"), codeParseError, "Parse error")
+	}
+	if message.JSONRPC != "2.0" || message.Method == "" {
+		if message.ID == nil {
+			// Responses to server requests are not expected, so they are dropped
+			return nil
+		}
+		return errorResponse(message.ID, codeInvalidRequest, "Invalid request")
+	}
+	if message.ID == nil {
+		s.logger.With(spanCtx).Field("method", message.Method).Debug("MCP notification received")
+		return nil
+	}
+
+	result, rpcErr := s.dispatch(spanCtx, message)
+	if rpcErr != nil {
+		return &rpcResponse{JSONRPC: "2.0", ID: message.ID, Error: rpcErr}
+	}
+
+	return &rpcResponse{JSONRPC: "2.0", ID: message.ID, Result: result}
+}
+
+func (s *Server) dispatch(ctx context.Context, message rpcMessage) (interface{}, *rpcError) {
+	switch message.Method {
+	case "initialize":
+		var params struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		_ = json.Unmarshal(message.Params, &params)
+		version := supportedVersions[0]
+		if slices.Contains(supportedVersions, params.ProtocolVersion) {
+			version = params.ProtocolVersion
+		}
+
+		return map[string]interface{}{
+			"protocolVersion": version,
+			"capabilities":    map[string]interface{}{"tools": map[string]interface{}{"listChanged": false}},
+			"serverInfo":      map[string]interface{}{"name": serverName, "version": serverVersion},
+		}, nil
+
+	case "ping":
+		return map[string]interface{}{}, nil
+
+	case "tools/list":
+		return map[string]interface{}{"tools": s.tools}, nil
+
+	case "tools/call":
+		return s.callTool(ctx, message.Params)
+
+	default:
+		return nil, &rpcError{Code: codeMethodNotFound, Message: fmt.Sprintf("Method not found: %s", message.Method)}
+	}
+}
+
+// callTool runs the lookup, failed lookups are tool errors so the calling model can see the reason
+func (s *Server) callTool(ctx context.Context, data json.RawMessage) (interface{}, *rpcError) {
+	var params struct {
+		Name      string      `json:"name"`
+		Arguments lookupInput `json:"arguments"`
+	}
+	if err := json.Unmarshal(data, &params); err != nil {
+		return nil, &rpcError{Code: codeInvalidParams, Message: "Invalid tool call parameters"}
+	}
+	if params.Name != toolName {
+		return nil, &rpcError{Code: codeInvalidParams, Message: fmt.Sprintf("Unknown tool: %s", params.Name)}
+	}
+	if params.Arguments.Word == "" {
+		return nil, &rpcError{Code: codeInvalidParams, Message: "The word argument is required"}
+	}
+
+	request := entities.NewArticleRequest(params.Arguments.Word, params.Arguments.Language)
+	ctx = usecases.WithRequestContext(ctx, toolRequestContext(ctx, request.Language))
+	response, err := s.useCase.Execute(ctx, request)
+	if err != nil {
+		s.logger.With(ctx).Err(err).Field("word", params.Arguments.Word).Error("MCP tool call failed")
+	}
+
+	text, err := json.Marshal(response)
+	if err != nil {
+		return nil, &rpcError{Code: codeInternalError, Message: "Failed to encode the lookup result"}
+	}
+
+	return map[string]interface{}{
+		"content":           []map[string]interface{}{{"type": "text", "text": string(text)}},
+		"structuredContent": response,
+		"isError":           !response.Success,
+	}, nil
+}
+
+// toolRequestContext returns the request context of a tool call, calls over HTTP keep the client and subject of the request
+func toolRequestContext(ctx context.Context, language string) *entities.RequestContext {
+	request := &entities.RequestContext{Adapter: entities.AdapterMCP, Locale: language, RequestID: requestid.FromContext(ctx)}
+	if outer := usecases.RequestContextFromContext(ctx); outer != nil {
+		request.Subject = outer.Subject
+		request.ClientIP = outer.ClientIP
+	}
+
+	return request
+}
+
+func errorResponse(id json.RawMessage, code int, message string) *rpcResponse {
+	return &rpcResponse{JSONRPC: "2.0", ID: id, Error: &rpcError{Code: code, Message: message}}
+}
@@ -0,0 +1,224 @@
+package presenter
+
+import (
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"strings"
+	"unicode/utf8"
+)
+
+// ANSI escape sequences of the colorized table
+const (
+	ansiReset = "\033[0m"
+	ansiBold  = "\033[1m"
+	ansiDim   = "\033[2m"
+)
+
+// articleColors follows the usual learner colors of the genders
+var articleColors = map[string]string{
+	"der": "\033[34m",
+	"die": "\033[31m",
+	"das": "\033[32m",
+}
+
+var exampleHeader = []string{"Number", "Case", "Form", "Example", "Translation"}
+
+// Console renders article responses for terminals
+type Console struct {
+	color bool
+}
+
+// NewConsole creates a new console presenter, color enables ANSI colors in tables
+func NewConsole(color bool) *Console {
+	return &Console{color: color}
+}
+
+// FormatText formats the article response as one line per interpretation
+func (p *Console) FormatText(response *entities.ArticleResponse) string {
+	if !response.Success {
+		if response.IsForeignWord() {
+			return fmt.Sprintf("Error: %s\nThis looks like %s rather than German.", response.Error, LanguageName(response.DetectedLanguage))
+		}
+		if len(response.Suggestions) > 0 {
+			return fmt.Sprintf("Error: %s\nDid you mean %s?", response.Error, strings.Join(response.Suggestions, ", "))
+		}
+		return "Error: " + response.Error
+	}
+	if len(response.Data) == 0 {
+		return "No information found for this word."
+	}
+
+	lines := make([]string, 0, len(response.Data))
+	for _, info := range response.Data {
+		lines = append(lines, p.headline(info, info.WordWithArticle))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// FormatTable formats every interpretation as a headline and an aligned table of examples
+func (p *Console) FormatTable(response *entities.ArticleResponse) string {
+	if !response.Success || len(response.Data) == 0 {
+		return p.FormatText(response)
+	}
+
+	var result strings.Builder
+	for i, info := range response.Data {
+		if i > 0 {
+			result.WriteString("\n")
+		}
+		result.WriteString(p.headline(info, p.paint(articleColors[info.Article()]+ansiBold, info.WordWithArticle)) + "\n")
+
+		rows := exampleRows(info.Example)
+		if len(rows) == 0 {
+			continue
+		}
+		result.WriteString("\n")
+		p.writeTable(&result, rows)
+	}
+
+	return strings.TrimSuffix(result.String(), "\n")
+}
+
+// FormatMarkdown formats every interpretation as a heading and a Markdown table of examples
+func (p *Console) FormatMarkdown(response *entities.ArticleResponse) string {
+	if !response.Success {
+		return "**Error:** " + markdownEscape(response.Error)
+	}
+	if len(response.Data) == 0 {
+		return "No information found for this word."
+	}
+
+	var result strings.Builder
+	for i, info := range response.Data {
+		if i > 0 {
+			result.WriteString("\n")
+		}
+		result.WriteString("### " + markdownEscape(info.WordWithArticle) + "\n\n")
+		if info.Translation != "" {
+			result.WriteString("*" + markdownEscape(info.Translation) + "*\n\n")
+		}
+		if info.Plural != "" {
+			result.WriteString("Plural: " + markdownEscape(info.Plural) + "\n\n")
+		}
+		if info.Level != "" {
+			result.WriteString(fmt.Sprintf("Level: %s (frequency rank %d)\n\n", info.Level, info.FrequencyRank))
+		}
+
+		rows := exampleRows(info.Example)
+		if len(rows) == 0 {
+			continue
+		}
+		writeMarkdownRow(&result, exampleHeader)
+		result.WriteString("|" + strings.Repeat(" --- |", len(exampleHeader)) + "\n")
+		for _, row := range rows {
+			writeMarkdownRow(&result, row)
+		}
+		result.WriteString("\n")
+	}
+
+	return strings.TrimSuffix(result.String(), "\n")
+}
+
+// headline joins the word with its translation and plural
+func (p *Console) headline(info entities.ArticleInfo, word string) string {
+	line := word
+	if info.Translation != "" {
+		line += " — " + info.Translation
+	}
+	if info.Plural != "" {
+		line += fmt.Sprintf(" (Plural: %s)", info.Plural)
+	}
+	if info.Level != "" {
+		line += fmt.Sprintf(" [%s]", info.Level)
+	}
+
+	return line
+}
+
+// writeTable pads the cells to the widest value of the column, colors are applied after padding
+// so the escape sequences don't break the alignment
+func (p *Console) writeTable(result *strings.Builder, rows [][]string) {
+	widths := make([]int, len(exampleHeader))
+	for _, row := range append([][]string{exampleHeader}, rows...) {
+		for i, cell := range row {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+
+	writeRow := func(row []string, style string) {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = cell + strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell))
+			switch {
+			case style != "":
+				cells[i] = p.paint(style, cells[i])
+			case i == len(row)-1:
+				cells[i] = p.paint(ansiDim, cells[i])
+			}
+		}
+		result.WriteString(strings.TrimRight(strings.Join(cells, "  "), " ") + "\n")
+	}
+
+	writeRow(exampleHeader, ansiBold)
+	separator := make([]string, len(widths))
+	for i, width := range widths {
+		separator[i] = strings.Repeat("─", width)
+	}
+	writeRow(separator, ansiDim)
+	for _, row := range rows {
+		writeRow(row, "")
+	}
+}
+
+// paint wraps the value into the ANSI style when colors are enabled
+func (p *Console) paint(style, value string) string {
+	if !p.color || style == "" {
+		return value
+	}
+
+	return style + value + ansiReset
+}
+
+// exampleRows lists the examples with their number, case and form, skipping missing ones
+func exampleRows(examples entities.ExamplesInfo) [][]string {
+	var rows [][]string
+	for _, number := range []struct {
+		name string
+		info entities.ExampleInfo
+	}{{"Singular", examples.Singular}, {"Plural", examples.Plural}} {
+		for _, form := range []struct {
+			name string
+			info entities.TranslationsInfo
+		}{{"definite", number.info.Definite}, {"indefinite", number.info.Indefinite}} {
+			cases := [][3]string{
+				{"Nominative", form.info.NominativeExample, form.info.NominativeTranslation},
+				{"Accusative", form.info.AccusativeExample, form.info.AccusativeTranslation},
+				{"Dative", form.info.DativeExample, form.info.DativeTranslation},
+				{"Genitive", form.info.GenitiveExample, form.info.GenitiveTranslation},
+			}
+			for _, c := range cases {
+				if c[1] != "" {
+					rows = append(rows, []string{number.name, c[0], form.name, c[1], c[2]})
+				}
+			}
+		}
+	}
+
+	return rows
+}
+
+func writeMarkdownRow(result *strings.Builder, cells []string) {
+	escaped := make([]string, len(cells))
+	for i, cell := range cells {
+		escaped[i] = markdownEscape(cell)
+	}
+	result.WriteString("| " + strings.Join(escaped, " | ") + " |\n")
+}
+
+var markdownReplacer = strings.NewReplacer("\\", "\\\\", "|", "\\|", "*", "\\*", "_", "\\_", "\n", " ")
+
+// markdownEscape keeps AI-provided values from breaking the table or the emphasis
+func markdownEscape(value string) string {
+	return markdownReplacer.Replace(value)
+}
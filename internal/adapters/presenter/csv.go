@@ -0,0 +1,29 @@
+package presenter
+
+import (
+	"encoding/csv"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"io"
+)
+
+// CSV renders compiled vocabulary tables as CSV
+type CSV struct{}
+
+// NewCSV creates a new CSV presenter
+func NewCSV() *CSV {
+	return &CSV{}
+}
+
+// FormatImport writes the rows of the import followed by its failures with the error column set
+func (p *CSV) FormatImport(w io.Writer, result *entities.ImportResult) error {
+	writer := csv.NewWriter(w)
+	records := [][]string{{"word", "article", "word_with_article", "translation", "plural", "error"}}
+	for _, row := range result.Rows {
+		records = append(records, []string{row.Word, row.Article, row.WordWithArticle, row.Translation, row.Plural, ""})
+	}
+	for _, failure := range result.Failures {
+		records = append(records, []string{failure.Word, "", "", "", "", failure.Error})
+	}
+
+	return writer.WriteAll(records)
+}
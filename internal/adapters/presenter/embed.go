@@ -0,0 +1,120 @@
+package presenter
+
+import (
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"html/template"
+	"strings"
+)
+
+// embedTemplate is a self-contained card without scripts or external resources, so it can be framed by any page
+var embedTemplate = template.Must(template.New("embed").Parse(`<!DOCTYPE html>
+<html lang="de">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body{margin:0;font:15px/1.45 system-ui,-apple-system,"Segoe UI",Roboto,sans-serif;color:#1f2328;background:transparent}
+.card{box-sizing:border-box;max-width:420px;margin:8px;padding:14px 16px;border:1px solid #d0d7de;border-radius:10px;background:#fff}
+.entry+.entry{margin-top:12px;padding-top:12px;border-top:1px solid #eaeef2}
+.word{margin:0;font-size:20px;font-weight:600}
+.article{padding:1px 6px;border-radius:5px;color:#fff;background:#57606a}
+.der{background:#0969da}.die{background:#cf222e}.das{background:#1a7f37}
+.translation{margin:2px 0 0;color:#57606a}
+.plural,.example,.variants{margin:6px 0 0}
+.example span{display:block;color:#57606a;font-style:italic}
+.variants{padding-left:18px}
+.error{margin:0;color:#57606a}
+</style>
+</head>
+<body>
+<div class="card">
+{{- if .Error}}
+<p class="error">{{.Error}}</p>
+{{- end}}
+{{- range .Entries}}
+<div class="entry">
+<p class="word">{{if .Article}}<span class="article {{.Article}}">{{.Article}}</span> {{end}}{{.Noun}}</p>
+{{- if .Translation}}
+<p class="translation">{{.Translation}}</p>
+{{- end}}
+{{- if .Plural}}
+<p class="plural">Plural: {{.Plural}}</p>
+{{- end}}
+{{- if .Example}}
+<p class="example">{{.Example}}{{if .ExampleTranslation}}<span>{{.ExampleTranslation}}</span>{{end}}</p>
+{{- end}}
+</div>
+{{- end}}
+{{- if .Variants}}
+<ul class="variants">
+{{- range .Variants}}
+<li><span class="article {{.Article}}">{{.Article}}</span> {{.Meaning}}</li>
+{{- end}}
+</ul>
+{{- end}}
+</div>
+</body>
+</html>`))
+
+type embedEntry struct {
+	Article            string
+	Noun               string
+	Translation        string
+	Plural             string
+	Example            string
+	ExampleTranslation string
+}
+
+type embedCard struct {
+	Title    string
+	Error    string
+	Entries  []embedEntry
+	Variants []entities.GenderVariant
+}
+
+// Embed renders article responses as a standalone HTML card for iframes of third-party pages
+type Embed struct{}
+
+// NewEmbed creates a new embed presenter
+func NewEmbed() *Embed {
+	return &Embed{}
+}
+
+// Format formats the article response as an HTML document, the text is escaped by the template
+func (p *Embed) Format(response *entities.ArticleResponse) string {
+	card := embedCard{Title: "German article"}
+	switch {
+	case !response.Success:
+		card.Error = response.Error
+		if len(response.Suggestions) > 0 {
+			card.Error += " Did you mean: " + strings.Join(response.Suggestions, ", ") + "?"
+		}
+	case len(response.Data) == 0:
+		card.Error = "No information found for this word."
+	}
+
+	for _, info := range response.Data {
+		example := info.Example.Singular.Definite
+		card.Entries = append(card.Entries, embedEntry{
+			Article:            info.Article(),
+			Noun:               info.Noun(),
+			Translation:        info.Translation,
+			Plural:             info.Plural,
+			Example:            example.NominativeExample,
+			ExampleTranslation: example.NominativeTranslation,
+		})
+	}
+	if len(card.Entries) > 0 {
+		card.Title = response.Data[0].WordWithArticle
+	}
+	if len(response.GenderVariants) > 1 {
+		card.Variants = response.GenderVariants
+	}
+
+	var html strings.Builder
+	// The card is built from strings only, so executing the parsed template can't fail
+	_ = embedTemplate.Execute(&html, card)
+
+	return html.String()
+}
@@ -0,0 +1,218 @@
+package presenter
+
+import (
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"html"
+	"strings"
+)
+
+// sourceLabels name the sources of the answers in the footer of the Telegram messages
+var sourceLabels = map[entities.AnswerSource]string{
+	entities.SourceDictionary: "dictionary",
+	entities.SourceCache:      "cache",
+	entities.SourceGemini:     "Gemini",
+	entities.SourceMock:       "mock",
+	entities.SourceMixed:      "Gemini and dictionary",
+}
+
+// separator divides the interpretations of a message
+const separator = "\n\n──────────\n\n"
+
+// exampleCases lists the grammatical cases in message order with the labels of their definite and
+// indefinite examples, so the labels aren't concatenated for every message
+var exampleCases = []struct {
+	definite, indefinite string
+	sentences            func(t entities.TranslationsInfo) (example, translation string)
+}{
+	{"Nominative Definite", "Nominative Indefinite", func(t entities.TranslationsInfo) (string, string) {
+		return t.NominativeExample, t.NominativeTranslation
+	}},
+	{"Accusative Definite", "Accusative Indefinite", func(t entities.TranslationsInfo) (string, string) {
+		return t.AccusativeExample, t.AccusativeTranslation
+	}},
+	{"Dative Definite", "Dative Indefinite", func(t entities.TranslationsInfo) (string, string) {
+		return t.DativeExample, t.DativeTranslation
+	}},
+	{"Genitive Definite", "Genitive Indefinite", func(t entities.TranslationsInfo) (string, string) {
+		return t.GenitiveExample, t.GenitiveTranslation
+	}},
+}
+
+// Telegram renders article responses as Telegram HTML messages
+type Telegram struct{}
+
+// NewTelegram creates a new Telegram presenter
+func NewTelegram() *Telegram {
+	return &Telegram{}
+}
+
+// Format formats the article response for Telegram, all AI-provided values are HTML escaped
+func (p *Telegram) Format(response *entities.ArticleResponse) string {
+	if !response.Success {
+		message := "❌ <b>Error:</b> " + html.EscapeString(response.Error)
+		if len(response.Suggestions) > 0 {
+			message += "\n\n🔎 Did you mean <b>" + html.EscapeString(strings.Join(response.Suggestions, ", ")) + "</b>?"
+		}
+		return message
+	}
+
+	if len(response.Data) == 0 {
+		return "❌ No information found for this word."
+	}
+
+	var result strings.Builder
+	result.Grow(messageSize(response))
+	p.writeGenderVariants(&result, response)
+	for i, info := range response.Data {
+		if i > 0 {
+			result.WriteString(separator)
+		}
+
+		p.writeTitle(&result, info)
+		p.writeHints(&result, info)
+		result.WriteString("\n")
+
+		singular := info.Example.Singular != entities.ExampleInfo{}
+		if singular {
+			result.WriteString("📝 <b>Singular Examples:</b>\n")
+			p.writeExamples(&result, info.Example.Singular)
+		}
+
+		if (info.Example.Plural != entities.ExampleInfo{}) {
+			if singular {
+				result.WriteString("\n")
+			}
+			result.WriteString("📝 <b>Plural Examples:</b>\n")
+			p.writeExamples(&result, info.Example.Plural)
+		}
+	}
+	return p.withFooter(&result, response)
+}
+
+// messageSize estimates the length of the message of the answer, so its builder grows once. The escaped
+// values and the labels make the messages longer than the values, about twice for short answers.
+func messageSize(response *entities.ArticleResponse) int {
+	size := 256
+	for _, info := range response.Data {
+		size += 256 + 2*(len(info.WordWithArticle)+len(info.Translation)+len(info.Plural)+len(info.Mnemonic)+len(info.Etymology))
+		for _, t := range []entities.TranslationsInfo{
+			info.Example.Singular.Definite, info.Example.Singular.Indefinite, info.Example.Plural.Definite, info.Example.Plural.Indefinite,
+		} {
+			size += 64 + len(t.NominativeExample) + len(t.NominativeTranslation) + len(t.AccusativeExample) + len(t.AccusativeTranslation) +
+				len(t.DativeExample) + len(t.DativeTranslation) + len(t.GenitiveExample) + len(t.GenitiveTranslation)
+		}
+	}
+
+	return size
+}
+
+// withFooter returns the message of the builder with the explanation that the examples are missing from
+// a partial answer and a small italic line with the source of the answer and the day it was generated.
+// Degraded answers carry their localized notice, answers without a known source get no source line.
+func (p *Telegram) withFooter(result *strings.Builder, response *entities.ArticleResponse) string {
+	notice := ""
+	switch {
+	case response.Notice != "":
+		notice = html.EscapeString(response.Notice)
+	case response.Partial:
+		notice = "The examples couldn't be generated this time, please try again later."
+	}
+	label, ok := sourceLabels[response.Source]
+	if notice == "" && !ok {
+		return result.String()
+	}
+
+	text := strings.TrimRight(result.String(), "\n")
+	var footer strings.Builder
+	footer.Grow(len(text) + len(notice) + len(label) + 64)
+	footer.WriteString(text)
+	if notice != "" {
+		footer.WriteString("\n\n⚠️ <i>")
+		footer.WriteString(notice)
+		footer.WriteString("</i>")
+	}
+	if ok {
+		footer.WriteString("\n\n<i>Source: ")
+		footer.WriteString(label)
+		if response.GeneratedAt != nil {
+			var date [16]byte
+			footer.WriteString(", ")
+			footer.Write(response.GeneratedAt.AppendFormat(date[:0], "2 Jan 2006"))
+		}
+		footer.WriteString("</i>")
+	}
+
+	return footer.String()
+}
+
+// writeTitle writes the noun with its article and its translation
+func (p *Telegram) writeTitle(result *strings.Builder, info entities.ArticleInfo) {
+	result.WriteString("🇩🇪 <b>")
+	result.WriteString(html.EscapeString(info.WordWithArticle))
+	result.WriteString("</b>\n📖 <i>")
+	result.WriteString(html.EscapeString(info.Translation))
+	result.WriteString("</i>\n")
+}
+
+// writeHints writes the mnemonic and the etymology of the interpretation when they are present
+func (p *Telegram) writeHints(result *strings.Builder, info entities.ArticleInfo) {
+	if info.Mnemonic != "" {
+		result.WriteString("💡 ")
+		result.WriteString(html.EscapeString(info.Mnemonic))
+		result.WriteString("\n")
+	}
+	if info.Etymology != "" {
+		result.WriteString("📜 <i>")
+		result.WriteString(html.EscapeString(info.Etymology))
+		result.WriteString("</i>\n")
+	}
+}
+
+// writeGenderVariants warns that the meaning of the noun depends on its article
+func (p *Telegram) writeGenderVariants(result *strings.Builder, response *entities.ArticleResponse) {
+	if len(response.GenderVariants) == 0 || len(response.Data) == 0 {
+		return
+	}
+
+	noun := html.EscapeString(response.Data[0].Noun())
+	result.WriteString("⚠️ <b>The meaning depends on the article:</b>\n")
+	for _, variant := range response.GenderVariants {
+		result.WriteString("• <b>")
+		result.WriteString(html.EscapeString(variant.Article))
+		result.WriteString("</b> ")
+		result.WriteString(noun)
+		result.WriteString(" — ")
+		result.WriteString(html.EscapeString(variant.Meaning))
+		result.WriteString("\n")
+	}
+	result.WriteString("\n")
+}
+
+// writeExamples writes the definite and indefinite examples grouped by case
+func (p *Telegram) writeExamples(result *strings.Builder, info entities.ExampleInfo) {
+	var hasData bool
+	for _, c := range exampleCases {
+		if hasData {
+			result.WriteString("\n")
+			hasData = false
+		}
+		if example, translation := c.sentences(info.Definite); example != "" && translation != "" {
+			p.writeExample(result, c.definite, example, translation)
+			hasData = true
+		}
+		if example, translation := c.sentences(info.Indefinite); example != "" && translation != "" {
+			p.writeExample(result, c.indefinite, example, translation)
+			hasData = true
+		}
+	}
+}
+
+func (p *Telegram) writeExample(result *strings.Builder, label, example, translation string) {
+	result.WriteString("• <b>")
+	result.WriteString(label)
+	result.WriteString(":</b> ")
+	result.WriteString(html.EscapeString(example))
+	result.WriteString(" / <i>")
+	result.WriteString(html.EscapeString(translation))
+	result.WriteString("</i>\n")
+}
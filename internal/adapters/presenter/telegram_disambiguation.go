@@ -0,0 +1,34 @@
+package presenter
+
+import (
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"html"
+	"strings"
+)
+
+// FormatChooser formats the short question which interpretation of the word is meant,
+// answers with a single interpretation are formatted as the compact answer
+func (p *Telegram) FormatChooser(response *entities.ArticleResponse) string {
+	if !response.Success || len(response.Data) < 2 {
+		return p.FormatCompact(response)
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("🤔 <b>%s</b> has %d meanings:\n\n", html.EscapeString(response.Data[0].Noun()), len(response.Data)))
+	for i, info := range response.Data {
+		result.WriteString(fmt.Sprintf("%d. %s\n", i+1, html.EscapeString(p.MeaningLabel(info))))
+	}
+	result.WriteString("\nChoose one to see the details.")
+
+	return result.String()
+}
+
+// MeaningLabel returns the caption of the button choosing the interpretation
+func (p *Telegram) MeaningLabel(info entities.ArticleInfo) string {
+	if info.Translation == "" {
+		return info.WordWithArticle
+	}
+
+	return info.WordWithArticle + " — " + info.Translation
+}
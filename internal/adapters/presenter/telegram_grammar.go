@@ -0,0 +1,30 @@
+package presenter
+
+import (
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"html"
+	"strings"
+)
+
+// FormatGrammarAnswer formats the tutor's answer to a grammar question
+func (p *Telegram) FormatGrammarAnswer(answer *entities.GrammarAnswer) string {
+	if !answer.Success {
+		return fmt.Sprintf("❌ <b>Error:</b> %s", html.EscapeString(answer.Error))
+	}
+
+	var result strings.Builder
+	result.WriteString("🎓 " + html.EscapeString(answer.Answer) + "\n")
+	if len(answer.Examples) > 0 {
+		result.WriteString("\n")
+	}
+	for _, example := range answer.Examples {
+		result.WriteString(fmt.Sprintf("• <i>%s</i>", html.EscapeString(example.German)))
+		if example.Translation != "" {
+			result.WriteString(" — " + html.EscapeString(example.Translation))
+		}
+		result.WriteString("\n")
+	}
+
+	return strings.TrimSuffix(result.String(), "\n")
+}
@@ -0,0 +1,136 @@
+package presenter
+
+import (
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"html"
+	"strings"
+)
+
+// Section identifies a part of the answer shown on demand
+type Section string
+
+const (
+	SectionAccusative Section = "acc"
+	SectionDative     Section = "dat"
+	SectionGenitive   Section = "gen"
+	SectionPlural     Section = "pl"
+)
+
+const noExamples = "<i>No examples available.</i>\n"
+
+// Sections lists the on-demand sections in button order
+var Sections = []Section{SectionAccusative, SectionDative, SectionGenitive, SectionPlural}
+
+// Label returns the button caption of the section
+func (s Section) Label() string {
+	switch s {
+	case SectionAccusative:
+		return "Show Akkusativ"
+	case SectionDative:
+		return "Show Dativ"
+	case SectionGenitive:
+		return "Show Genitiv"
+	case SectionPlural:
+		return "Show Plural"
+	default:
+		return string(s)
+	}
+}
+
+// Valid reports whether the section is known
+func (s Section) Valid() bool {
+	for _, section := range Sections {
+		if s == section {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatCompact formats only the article, translation and plural of every interpretation
+func (p *Telegram) FormatCompact(response *entities.ArticleResponse) string {
+	return p.FormatSection(response, "")
+}
+
+// FormatSection formats the compact answer followed by the requested section of every interpretation
+func (p *Telegram) FormatSection(response *entities.ArticleResponse, section Section) string {
+	if !response.Success || len(response.Data) == 0 {
+		return p.Format(response)
+	}
+
+	var result strings.Builder
+	result.Grow(messageSize(response))
+	p.writeGenderVariants(&result, response)
+	for i, info := range response.Data {
+		if i > 0 {
+			result.WriteString(separator)
+		}
+
+		p.writeTitle(&result, info)
+		if info.Plural != "" {
+			result.WriteString("👥 <b>Plural:</b> ")
+			result.WriteString(html.EscapeString(info.Plural))
+			result.WriteString("\n")
+		}
+		p.writeHints(&result, info)
+
+		if section != "" {
+			result.WriteString("\n")
+			p.writeSection(&result, info, section)
+		}
+	}
+	return p.withFooter(&result, response)
+}
+
+func (p *Telegram) writeSection(result *strings.Builder, info entities.ArticleInfo, section Section) {
+	if section == SectionPlural {
+		result.WriteString("📝 <b>Plural Examples:</b>\n")
+		if (info.Example.Plural == entities.ExampleInfo{}) {
+			result.WriteString(noExamples)
+			return
+		}
+		p.writeExamples(result, info.Example.Plural)
+		return
+	}
+
+	var (
+		title                    string
+		definite, indefinite     string
+		definiteTr, indefiniteTr string
+		def, indef               = info.Example.Singular.Definite, info.Example.Singular.Indefinite
+	)
+	switch section {
+	case SectionAccusative:
+		title = "Akkusativ"
+		definite, definiteTr = def.AccusativeExample, def.AccusativeTranslation
+		indefinite, indefiniteTr = indef.AccusativeExample, indef.AccusativeTranslation
+	case SectionDative:
+		title = "Dativ"
+		definite, definiteTr = def.DativeExample, def.DativeTranslation
+		indefinite, indefiniteTr = indef.DativeExample, indef.DativeTranslation
+	case SectionGenitive:
+		title = "Genitiv"
+		definite, definiteTr = def.GenitiveExample, def.GenitiveTranslation
+		indefinite, indefiniteTr = indef.GenitiveExample, indef.GenitiveTranslation
+	}
+
+	result.WriteString("📝 <b>")
+	result.WriteString(title)
+	result.WriteString(":</b>\n")
+	written := p.writeCase(result, "Definite", definite, definiteTr)
+	if p.writeCase(result, "Indefinite", indefinite, indefiniteTr) {
+		written = true
+	}
+	if !written {
+		result.WriteString(noExamples)
+	}
+}
+
+func (p *Telegram) writeCase(result *strings.Builder, label, example, translation string) bool {
+	if example == "" || translation == "" {
+		return false
+	}
+
+	p.writeExample(result, label, example, translation)
+	return true
+}
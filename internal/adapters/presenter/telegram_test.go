@@ -0,0 +1,186 @@
+package presenter
+
+import (
+	"encoding/json"
+	"flag"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/libs/race"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files instead of comparing them")
+
+// renderers maps the golden file suffix to the presenter producing it
+var renderers = map[string]func(response *entities.ArticleResponse) string{
+	"console-markdown":  NewConsole(false).FormatMarkdown,
+	"console-table":     NewConsole(false).FormatTable,
+	"telegram":          NewTelegram().Format,
+	"telegram-chooser":  NewTelegram().FormatChooser,
+	"telegram-compact":  NewTelegram().FormatCompact,
+	"telegram-sections": renderTelegramSections,
+	"telegram-minimal":  renderTelegramProfile(entities.VerbosityMinimal),
+	"telegram-standard": renderTelegramProfile(entities.VerbosityStandard),
+	"voice":             NewVoice().Format,
+	"embed":             NewEmbed().Format,
+}
+
+// TestGolden renders every ArticleResponse fixture of testdata with every presenter and compares the output
+// with the golden files next to it, go test ./internal/adapters/presenter -update accepts intended changes
+func TestGolden(t *testing.T) {
+	fixtures, err := filepath.Glob(filepath.Join("testdata", "*.json"))
+	if err != nil {
+		t.Fatalf("Failed to list fixtures: %v", err)
+	}
+	if len(fixtures) == 0 {
+		t.Fatal("No fixtures found in testdata")
+	}
+
+	for _, fixture := range fixtures {
+		response := readFixture(t, fixture)
+		for name, render := range renderers {
+			golden := strings.TrimSuffix(fixture, ".json") + "." + name + ".golden"
+			t.Run(filepath.Base(golden), func(t *testing.T) {
+				checkGolden(t, golden, render(response))
+			})
+		}
+	}
+}
+
+// renderTelegramSections renders every on-demand section into one golden file
+func renderTelegramSections(response *entities.ArticleResponse) string {
+	p := NewTelegram()
+	parts := make([]string, 0, len(Sections))
+	for _, section := range Sections {
+		parts = append(parts, "=== "+string(section)+" ===\n"+p.FormatSection(response, section))
+	}
+
+	return strings.Join(parts, "\n")
+}
+
+// renderTelegramProfile renders the Telegram answer of the reduced verbosity profile
+func renderTelegramProfile(verbosity entities.Verbosity) func(response *entities.ArticleResponse) string {
+	return func(response *entities.ArticleResponse) string {
+		return NewTelegram().FormatProfile(response, verbosity)
+	}
+}
+
+func readFixture(t testing.TB, path string) *entities.ArticleResponse {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read fixture %s: %v", path, err)
+	}
+
+	var response entities.ArticleResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		t.Fatalf("Failed to parse fixture %s: %v", path, err)
+	}
+
+	return &response
+}
+
+// checkGolden compares the output with the golden file, or rewrites the file with -update
+func checkGolden(t *testing.T, golden, output string) {
+	t.Helper()
+	actual := output + "\n"
+	if *update {
+		if err := os.WriteFile(golden, []byte(actual), 0o644); err != nil {
+			t.Fatalf("Failed to write golden file %s: %v", golden, err)
+		}
+		return
+	}
+
+	expected, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("Failed to read golden file %s: %v", golden, err)
+	}
+	if string(expected) != actual {
+		t.Errorf("%s differs, run with -update to accept the changes\n%s", golden, diff(string(expected), actual))
+	}
+}
+
+// diff returns the differing lines of both texts
+func diff(expected, actual string) string {
+	expectedLines := strings.Split(expected, "\n")
+	actualLines := strings.Split(actual, "\n")
+
+	var out strings.Builder
+	for i := 0; i < max(len(expectedLines), len(actualLines)); i++ {
+		var e, a string
+		if i < len(expectedLines) {
+			e = expectedLines[i]
+		}
+		if i < len(actualLines) {
+			a = actualLines[i]
+		}
+		if e != a {
+			out.WriteString("  line " + strconv.Itoa(i+1) + "\n  - " + e + "\n  + " + a + "\n")
+		}
+	}
+
+	return out.String()
+}
+
+// benchmarkFixture is the answer formatted by the benchmarks, with examples of both numbers
+const benchmarkFixture = "testdata/full_singular_and_plural.json"
+
+// allocationTolerance is the allowed growth of the allocations per call over their baseline, 0.1 is 10%
+const allocationTolerance = 0.1
+
+func BenchmarkTelegramFormat(b *testing.B) {
+	response := readFixture(b, benchmarkFixture)
+	p := NewTelegram()
+	b.ReportAllocs()
+	for range b.N {
+		_ = p.Format(response)
+	}
+}
+
+func BenchmarkTelegramFormatCompact(b *testing.B) {
+	response := readFixture(b, benchmarkFixture)
+	p := NewTelegram()
+	b.ReportAllocs()
+	for range b.N {
+		_ = p.FormatCompact(response)
+	}
+}
+
+func BenchmarkTelegramFormatSection(b *testing.B) {
+	response := readFixture(b, benchmarkFixture)
+	p := NewTelegram()
+	b.ReportAllocs()
+	for range b.N {
+		_ = p.FormatSection(response, SectionDative)
+	}
+}
+
+// TestTelegramAllocations fails when formatting a message allocates noticeably more than the baseline, the
+// durations depend on the machine and are left to the benchmarks
+func TestTelegramAllocations(t *testing.T) {
+	if race.Enabled {
+		t.Skip("The race detector changes the allocations")
+	}
+	response := readFixture(t, benchmarkFixture)
+	p := NewTelegram()
+
+	tests := []struct {
+		name     string
+		baseline float64
+		format   func()
+	}{
+		{name: "Format", baseline: 1, format: func() { _ = p.Format(response) }},
+		{name: "FormatCompact", baseline: 1, format: func() { _ = p.FormatCompact(response) }},
+		{name: "FormatSection", baseline: 1, format: func() { _ = p.FormatSection(response, SectionDative) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if allocs := testing.AllocsPerRun(100, tt.format); allocs > tt.baseline*(1+allocationTolerance) {
+				t.Errorf("%.0f allocations per call, the baseline is %.0f", allocs, tt.baseline)
+			}
+		})
+	}
+}
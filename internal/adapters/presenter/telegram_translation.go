@@ -0,0 +1,54 @@
+package presenter
+
+import (
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"html"
+	"strings"
+)
+
+// FormatTranslation formats the line naming the German nouns of the translated word, followed by
+// the request to choose one when there are several
+func (p *Telegram) FormatTranslation(result *entities.TranslationResult) string {
+	nouns := make([]string, 0, len(result.Nouns))
+	for _, noun := range result.Nouns {
+		nouns = append(nouns, noun.Noun)
+	}
+
+	text := fmt.Sprintf("🔁 <b>%s</b> → %s", html.EscapeString(result.Word), html.EscapeString(strings.Join(nouns, ", ")))
+	if len(result.Nouns) > 1 {
+		text += "\n\nChoose one to see the details."
+	}
+
+	return text
+}
+
+// FormatForeignWord formats the question whether the German noun of a word of another language was meant
+func (p *Telegram) FormatForeignWord(word, language string) string {
+	return fmt.Sprintf("🌐 This looks like %s — did you want the German word for <b>%s</b>?",
+		html.EscapeString(LanguageName(language)), html.EscapeString(word))
+}
+
+// languageNames maps ISO 639-1 codes of the common languages to their English names
+var languageNames = map[string]string{
+	"en": "English",
+	"ru": "Russian",
+	"uk": "Ukrainian",
+	"fr": "French",
+	"es": "Spanish",
+	"it": "Italian",
+	"pt": "Portuguese",
+	"nl": "Dutch",
+	"pl": "Polish",
+	"tr": "Turkish",
+	"sv": "Swedish",
+}
+
+// LanguageName returns the English name of the language code, the upper-cased code if it's unknown
+func LanguageName(code string) string {
+	if name, ok := languageNames[strings.ToLower(code)]; ok {
+		return name
+	}
+
+	return strings.ToUpper(code)
+}
@@ -0,0 +1,43 @@
+package presenter
+
+import (
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"html"
+	"strings"
+)
+
+// FormatVerification formats the result of re-checking a reported answer
+func (p *Telegram) FormatVerification(verification *entities.Verification) string {
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("🔎 <b>Verification of %s</b>\n\n", html.EscapeString(verification.Word)))
+	p.writeSource(&result, "Original answer", verification.Original)
+	p.writeSource(&result, "Second opinion", verification.SecondOpinion)
+	p.writeSource(&result, "Dictionary", strings.Fields(verification.Dictionary))
+	result.WriteString("\n")
+
+	switch verification.Outcome {
+	case entities.VerificationConfirmed:
+		result.WriteString("✅ The original answer is confirmed.")
+	case entities.VerificationCorrected:
+		result.WriteString("⚠️ The original answer looks wrong, thank you for the report!")
+		if verification.CacheUpdated {
+			result.WriteString(" Send the word again to get the corrected answer.")
+		} else {
+			result.WriteString(" It will be reviewed.")
+		}
+	default:
+		result.WriteString("❔ The answer could not be verified automatically, it will be reviewed.")
+	}
+
+	return result.String()
+}
+
+func (p *Telegram) writeSource(result *strings.Builder, label string, articles []string) {
+	value := "—"
+	if len(articles) > 0 {
+		value = strings.Join(articles, " / ")
+	}
+
+	result.WriteString(fmt.Sprintf("<b>%s:</b> %s\n", label, html.EscapeString(value)))
+}
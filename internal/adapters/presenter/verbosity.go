@@ -0,0 +1,49 @@
+package presenter
+
+import (
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"slices"
+	"strings"
+)
+
+// ApplyVerbosity returns a copy of the response with only the parts shown by the profile, the cached
+// answer is kept intact. The full profile and failed responses are returned as they are.
+func ApplyVerbosity(response *entities.ArticleResponse, verbosity entities.Verbosity) *entities.ArticleResponse {
+	if response == nil || !response.Success || !verbosity.Reduced() {
+		return response
+	}
+
+	reduced := *response
+	reduced.Data = slices.Clone(response.Data)
+	for i := range reduced.Data {
+		info := &reduced.Data[i]
+		info.Plural = ""
+		info.Mnemonic = ""
+		info.Etymology = ""
+		info.Declension = nil
+
+		if verbosity == entities.VerbosityMinimal {
+			info.Example = entities.ExamplesInfo{}
+			continue
+		}
+
+		// The standard profile keeps the nominative and accusative examples of the singular
+		info.Example.Plural = entities.ExampleInfo{}
+		for _, examples := range []*entities.TranslationsInfo{&info.Example.Singular.Definite, &info.Example.Singular.Indefinite} {
+			examples.DativeExample, examples.DativeTranslation = "", ""
+			examples.GenitiveExample, examples.GenitiveTranslation = "", ""
+		}
+	}
+
+	return &reduced
+}
+
+// FormatProfile formats the answer of the profile, the reduced profiles show everything at once
+// while the full one is the compact answer with its sections on demand
+func (p *Telegram) FormatProfile(response *entities.ArticleResponse, verbosity entities.Verbosity) string {
+	if !verbosity.Reduced() {
+		return p.FormatCompact(response)
+	}
+
+	return strings.TrimRight(p.Format(ApplyVerbosity(response, verbosity)), "\n")
+}
@@ -0,0 +1,78 @@
+package presenter
+
+import (
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"html"
+	"strings"
+)
+
+// Voice renders article responses as SSML for voice assistants
+type Voice struct{}
+
+// NewVoice creates a new voice presenter
+func NewVoice() *Voice {
+	return &Voice{}
+}
+
+// Format formats the article response as SSML, German words are wrapped into German language tags
+func (p *Voice) Format(response *entities.ArticleResponse) string {
+	return "<speak>" + p.speech(response) + "</speak>"
+}
+
+// FormatText formats the article response as plain text for assistants showing the answer on a screen
+func (p *Voice) FormatText(response *entities.ArticleResponse) string {
+	if !response.Success {
+		return response.Error
+	}
+	if len(response.Data) == 0 {
+		return "No information found for this word."
+	}
+
+	lines := make([]string, 0, len(response.Data))
+	for _, info := range response.Data {
+		line := info.WordWithArticle
+		if info.Translation != "" {
+			line += " — " + info.Translation
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (p *Voice) speech(response *entities.ArticleResponse) string {
+	if !response.Success {
+		return "Sorry, I couldn't find the article of this word."
+	}
+	if len(response.Data) == 0 {
+		return "Sorry, I have no information about this word."
+	}
+
+	sentences := make([]string, 0, len(response.Data))
+	for i, info := range response.Data {
+		_, noun, _ := strings.Cut(strings.TrimSpace(info.WordWithArticle), " ")
+		gender, ok := entities.Gender(info.Article())
+
+		var sentence string
+		switch {
+		case !ok:
+			sentence = fmt.Sprintf("It is %s.", german(info.WordWithArticle))
+		case i == 0:
+			sentence = fmt.Sprintf("%s is %s: %s.", german(noun), gender, german(info.WordWithArticle))
+		default:
+			sentence = fmt.Sprintf("It can also be %s: %s.", gender, german(info.WordWithArticle))
+		}
+		if info.Translation != "" {
+			sentence += fmt.Sprintf(" It means %s.", html.EscapeString(info.Translation))
+		}
+		sentences = append(sentences, sentence)
+	}
+
+	return strings.Join(sentences, ` <break time="300ms"/> `)
+}
+
+// german marks the text to be pronounced in German
+func german(text string) string {
+	return `<lang xml:lang="de-DE">` + html.EscapeString(text) + `</lang>`
+}
@@ -0,0 +1,114 @@
+package telegram
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	tele "gopkg.in/telebot.v3"
+	"strings"
+)
+
+type languageKey struct{}
+
+// withLanguage returns the context of an update answered in the language
+func withLanguage(ctx context.Context, language string) context.Context {
+	return context.WithValue(ctx, languageKey{}, language)
+}
+
+// languageFromContext returns the answer language of the update, the default one outside of updates
+func languageFromContext(ctx context.Context) string {
+	if language, ok := ctx.Value(languageKey{}).(string); ok {
+		return language
+	}
+
+	return defaultLanguage
+}
+
+// AchievementNotifier announces the achievements awarded to users in their private chats with the bot
+type AchievementNotifier struct {
+	bot *tele.Bot
+}
+
+// NewAchievementNotifier creates a notifier sending with the bot
+func NewAchievementNotifier(bot *tele.Bot) *AchievementNotifier {
+	return &AchievementNotifier{bot: bot}
+}
+
+// NotifyAchievements sends the badges of the achievements to the user, in the language of the update
+// that earned them. The private chat of a user has the ID of the user.
+func (n *AchievementNotifier) NotifyAchievements(ctx context.Context, userID int64, achievements []entities.Achievement) error {
+	language := languageFromContext(ctx)
+	_, err := n.bot.Send(tele.ChatID(userID), localize(language, achievementAwarded)+"\n\n"+formatAchievements(language, achievements))
+	return err
+}
+
+// handleAchievements handles the /achievements command, it lists the badges of the sender
+func (h *BotHandler) handleAchievements(c tele.Context) error {
+	ctx := c.Get("invokeCtx").(context.Context)
+	spanCtx, span := h.tracer.Start(ctx, "Telegram Achievements Command")
+	defer span.End()
+
+	language := h.language(c)
+	achievements, err := h.achievements.List(spanCtx, c.Sender().ID)
+	if err != nil {
+		return h.reply(c, "Sorry, please try again.")
+	}
+	if len(achievements) == 0 {
+		return h.reply(c, localize(language, achievementsEmpty))
+	}
+
+	return h.reply(c, localize(language, achievementsTitle)+"\n\n"+formatAchievements(language, achievements))
+}
+
+// formatAchievements lists the achievements with their badges and localized titles
+func formatAchievements(language string, achievements []entities.Achievement) string {
+	lines := make([]string, 0, len(achievements))
+	for _, achievement := range achievements {
+		title := achievement.Title
+		if titles, ok := achievementTitles[achievement.ID]; ok {
+			title = localize(language, titles)
+		}
+		lines = append(lines, achievement.Badge+" "+title)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+var (
+	achievementsDescriptions = map[string]string{
+		"en": "Your achievement badges",
+		"ru": "Ваши награды",
+		"de": "Deine Abzeichen",
+	}
+
+	achievementsTitle = map[string]string{
+		"en": "Your achievements:",
+		"ru": "Ваши награды:",
+		"de": "Deine Abzeichen:",
+	}
+
+	achievementsEmpty = map[string]string{
+		"en": "No achievements yet. Look up words, keep your streak and try /quiz to earn badges!",
+		"ru": "Пока нет наград. Ищите слова, не прерывайте серию и пробуйте /quiz, чтобы их получить!",
+		"de": "Noch keine Abzeichen. Schlag Wörter nach, halte deine Serie und probier /quiz, um welche zu verdienen!",
+	}
+
+	achievementAwarded = map[string]string{
+		"en": "🎉 New achievement!",
+		"ru": "🎉 Новая награда!",
+		"de": "🎉 Neues Abzeichen!",
+	}
+
+	// achievementTitles localizes the titles of the achievements by their ID
+	achievementTitles = map[string]map[string]string{
+		"words_10":         {"en": "10 words looked up", "ru": "10 найденных слов", "de": "10 Wörter nachgeschlagen"},
+		"words_100":        {"en": "100 words looked up", "ru": "100 найденных слов", "de": "100 Wörter nachgeschlagen"},
+		"words_500":        {"en": "500 words looked up", "ru": "500 найденных слов", "de": "500 Wörter nachgeschlagen"},
+		"streak_3":         {"en": "3-day streak", "ru": "Серия 3 дня", "de": "3 Tage in Folge"},
+		"streak_10":        {"en": "10-day streak", "ru": "Серия 10 дней", "de": "10 Tage in Folge"},
+		"streak_30":        {"en": "30-day streak", "ru": "Серия 30 дней", "de": "30 Tage in Folge"},
+		"quiz_correct_50":  {"en": "50 correct quiz answers", "ru": "50 верных ответов в викторине", "de": "50 richtige Quiz-Antworten"},
+		"suffix_ung":       {"en": "Mastered all -ung nouns", "ru": "Все слова на -ung освоены", "de": "Alle Nomen auf -ung gemeistert"},
+		"suffix_heit_keit": {"en": "Mastered all -heit and -keit nouns", "ru": "Все слова на -heit и -keit освоены", "de": "Alle Nomen auf -heit und -keit gemeistert"},
+		"suffix_chen":      {"en": "Mastered all -chen nouns", "ru": "Все слова на -chen освоены", "de": "Alle Nomen auf -chen gemeistert"},
+	}
+)
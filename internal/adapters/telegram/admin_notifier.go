@@ -0,0 +1,23 @@
+package telegram
+
+import (
+	"context"
+	tele "gopkg.in/telebot.v3"
+)
+
+// AdminNotifier sends operator alerts to the admin chat of the bot
+type AdminNotifier struct {
+	bot    *tele.Bot
+	chatID int64
+}
+
+// NewAdminNotifier creates a notifier sending to the chat
+func NewAdminNotifier(bot *tele.Bot, chatID int64) *AdminNotifier {
+	return &AdminNotifier{bot: bot, chatID: chatID}
+}
+
+// NotifyAdmin sends the message to the admin chat
+func (n *AdminNotifier) NotifyAdmin(_ context.Context, message string) error {
+	_, err := n.bot.Send(tele.ChatID(n.chatID), message)
+	return err
+}
@@ -0,0 +1,59 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/application/usecases"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/libs/requestid"
+	tele "gopkg.in/telebot.v3"
+	"strings"
+)
+
+// handleAsk handles the /ask command, "/ask <question>" answers a free-form grammar question
+func (h *BotHandler) handleAsk(c tele.Context) error {
+	ctx := c.Get("invokeCtx").(context.Context)
+	spanCtx, span := h.tracer.Start(ctx, "Telegram Ask Command")
+	defer span.End()
+
+	language := h.language(c)
+	question := strings.TrimSpace(c.Message().Payload)
+	if question == "" {
+		return h.reply(c, localize(language, askUsage))
+	}
+
+	answer, err := h.ask.Execute(spanCtx, entities.NewGrammarQuestion(question, language))
+	if errors.Is(err, usecases.ErrTooManyQuestions) {
+		return h.reply(c, localize(language, askRateLimited))
+	}
+	if err != nil {
+		h.logger.With(spanCtx).Err(err).Error("Failed to answer grammar question")
+		return h.reply(c, fmt.Sprintf(
+			"Sorry, I encountered an error while processing your request. Please try again.\n\nRequest ID: %s",
+			requestid.FromContext(spanCtx),
+		))
+	}
+
+	return h.reply(c, h.presenter.FormatGrammarAnswer(answer), tele.ModeHTML)
+}
+
+var (
+	askDescriptions = map[string]string{
+		"en": "Ask a question about German grammar",
+		"ru": "Задать вопрос о немецкой грамматике",
+		"de": "Eine Frage zur deutschen Grammatik stellen",
+	}
+
+	askUsage = map[string]string{
+		"en": `Write your question after the command, e.g. "/ask When do I use the dative?"`,
+		"ru": `Напишите вопрос после команды, например "/ask Когда используется датив?"`,
+		"de": `Schreibe deine Frage nach dem Befehl, z. B. "/ask Wann benutze ich den Dativ?"`,
+	}
+
+	askRateLimited = map[string]string{
+		"en": "You have asked a lot of questions, please wait a minute before the next one.",
+		"ru": "Вы задали много вопросов, пожалуйста, подождите минуту перед следующим.",
+		"de": "Du hast viele Fragen gestellt, bitte warte eine Minute bis zur nächsten.",
+	}
+)
@@ -3,64 +3,240 @@ package telegram
 import (
 	"context"
 	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/adapters/presenter"
 	"github.com/DeryabinSergey/germanarticlebot/internal/application/usecases"
 	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/repositories"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/services"
 	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
 	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+	"github.com/DeryabinSergey/germanarticlebot/libs/requestid"
 	tele "gopkg.in/telebot.v3"
+	"net/http"
+	"strconv"
 	"strings"
 )
 
 // BotHandler handles Telegram bot interactions
 type BotHandler struct {
-	ctx     context.Context
-	bot     *tele.Bot
-	useCase *usecases.DetermineArticleUseCase
-	logger  logging.Logger
-	tracer  tracing.Tracer
+	// ctx is the context of the instance, the handlers of an update get the invoke context of its request
+	ctx          context.Context
+	updates      updateContexts
+	bot          *tele.Bot
+	presenter    *presenter.Telegram
+	useCase      *usecases.DetermineArticleUseCase
+	feedback     *usecases.SubmitFeedbackUseCase
+	verify       *usecases.VerifyArticleUseCase
+	followUp     *usecases.FollowUpUseCase
+	ask          *usecases.AskGrammarUseCase
+	translator   *usecases.TranslateWordUseCase
+	importer     *usecases.ImportVocabularyUseCase
+	accounts     *usecases.LinkAccountUseCase
+	quiz         *usecases.QuizUseCase
+	learning     *usecases.LearningStatsUseCase
+	leaderboard  *usecases.LeaderboardUseCase
+	achievements *usecases.AchievementsUseCase
+	deadLetters  *usecases.DeadLetterUseCase
+	membership   *usecases.ChatMembershipUseCase
+	broadcast    *usecases.BroadcastUseCase
+	deleteData   *usecases.DeleteUserDataUseCase
+	export       *usecases.ExportUserDataUseCase
+	reminders    *usecases.ReminderUseCase
+	timezones    *usecases.TimezoneUseCase
+	search       *usecases.SearchHistoryUseCase
+	jobs         services.JobQueue
+	features     services.FeatureFlags
+	stats        repositories.StatsRepository
+	preferences  repositories.PreferencesRepository
+	replies      repositories.ReplyRepository
+	groups       GroupSettings
+	profile      BotProfile
+	commands     []command
+	logger       logging.Logger
+	tracer       tracing.Tracer
 }
 
-// NewBotHandler creates a new Telegram bot handler
+// NewBotHandler creates a new Telegram bot handler, the client sends its Bot API requests and nil uses the
+// default client
 func NewBotHandler(
 	ctx context.Context,
 	token string,
 	useCase *usecases.DetermineArticleUseCase,
+	feedback *usecases.SubmitFeedbackUseCase,
+	verify *usecases.VerifyArticleUseCase,
+	followUp *usecases.FollowUpUseCase,
+	ask *usecases.AskGrammarUseCase,
+	translator *usecases.TranslateWordUseCase,
+	importer *usecases.ImportVocabularyUseCase,
+	accounts *usecases.LinkAccountUseCase,
+	quiz *usecases.QuizUseCase,
+	learning *usecases.LearningStatsUseCase,
+	leaderboard *usecases.LeaderboardUseCase,
+	achievements *usecases.AchievementsUseCase,
+	deadLetters *usecases.DeadLetterUseCase,
+	membership *usecases.ChatMembershipUseCase,
+	broadcast *usecases.BroadcastUseCase,
+	deleteData *usecases.DeleteUserDataUseCase,
+	export *usecases.ExportUserDataUseCase,
+	reminders *usecases.ReminderUseCase,
+	timezones *usecases.TimezoneUseCase,
+	search *usecases.SearchHistoryUseCase,
+	jobs services.JobQueue,
+	features services.FeatureFlags,
+	stats repositories.StatsRepository,
+	preferences repositories.PreferencesRepository,
+	replies repositories.ReplyRepository,
+	groups GroupSettings,
+	client *http.Client,
 	logger logging.Logger,
 	tracer tracing.Tracer,
 ) (*BotHandler, error) {
+	var handler *BotHandler
 	bot, err := tele.NewBot(tele.Settings{
 		Token:       token,
+		Client:      client,
 		Synchronous: true,
 		Poller:      &tele.Webhook{},
+		OnError: func(err error, c tele.Context) {
+			handler.onError(err, c)
+		},
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create bot: %w", err)
 	}
 
-	handler := &BotHandler{
-		ctx:     ctx,
-		bot:     bot,
-		useCase: useCase,
-		logger:  logger,
-		tracer:  tracer,
+	handler = &BotHandler{
+		ctx:          ctx,
+		bot:          bot,
+		presenter:    presenter.NewTelegram(),
+		useCase:      useCase,
+		feedback:     feedback,
+		verify:       verify,
+		followUp:     followUp,
+		ask:          ask,
+		translator:   translator,
+		importer:     importer,
+		accounts:     accounts,
+		quiz:         quiz,
+		learning:     learning,
+		leaderboard:  leaderboard,
+		achievements: achievements,
+		deadLetters:  deadLetters,
+		membership:   membership,
+		broadcast:    broadcast,
+		deleteData:   deleteData,
+		export:       export,
+		reminders:    reminders,
+		timezones:    timezones,
+		search:       search,
+		jobs:         jobs,
+		features:     features,
+		stats:        stats,
+		preferences:  preferences,
+		replies:      replies,
+		groups:       groups,
+		logger:       logger,
+		tracer:       tracer,
 	}
 
-	bot.Use(SetContextMiddleware(handler))
-	// Handle /start command
-	bot.Handle("/start", handler.handleStart)
+	bot.Use(SetContextMiddleware(handler), handler.rememberChat)
+	// Handle commands, they are published to the command menu by RegisterCommands
+	handler.handleCommand(command{name: "start", descriptions: startDescriptions, handler: handler.handleStart})
+	handler.handleCommand(command{name: "help", descriptions: helpDescriptions, handler: handler.handleHelp})
+	handler.handleCommand(command{name: "level", descriptions: levelDescriptions, handler: handler.handleLevel})
+	handler.handleCommand(command{name: "hints", descriptions: hintsDescriptions, handler: handler.handleHints})
+	handler.handleCommand(command{name: "verbosity", descriptions: verbosityDescriptions, handler: handler.handleVerbosity})
+	handler.handleCommand(command{name: "ask", descriptions: askDescriptions, handler: handler.handleAsk})
+	handler.handleCommand(command{name: "translate", descriptions: translateDescriptions, handler: handler.handleTranslate})
+	handler.handleCommand(command{name: "link", descriptions: linkDescriptions, handler: handler.handleLink})
+	handler.handleCommand(command{name: "quiz", descriptions: quizDescriptions, handler: handler.handleQuiz})
+	handler.handleCommand(command{name: "stats", descriptions: statsDescriptions, handler: handler.handleStats})
+	handler.handleCommand(command{name: "leaderboard", descriptions: leaderboardDescriptions, handler: handler.handleLeaderboard})
+	handler.handleCommand(command{name: "find", descriptions: findDescriptions, handler: handler.handleFind})
+	handler.handleCommand(command{name: "achievements", descriptions: achievementsDescriptions, handler: handler.handleAchievements})
+	handler.handleCommand(command{name: "remind", descriptions: remindDescriptions, handler: handler.handleRemind})
+	handler.handleCommand(command{name: "timezone", descriptions: timezoneDescriptions, handler: handler.handleTimezone})
+	handler.handleCommand(command{name: "announcements", descriptions: announcementsDescriptions, handler: handler.handleAnnouncements})
+	handler.handleCommand(command{name: "mydata", descriptions: exportDescriptions, handler: handler.handleMyData})
+	handler.handleCommand(command{name: "deletemydata", descriptions: deleteDataDescriptions, handler: handler.handleDeleteMyData})
 	// Handle text messages
 	bot.Handle(tele.OnText, handler.handleText)
+	// Handle edited text messages, their answers are looked up again
+	bot.Handle(tele.OnEdited, handler.handleEdited)
+	// Handle the bot being added to or removed from chats and blocked by users
+	bot.Handle(tele.OnMyChatMember, handler.handleMyChatMember)
+	// Handle word lists sent as documents
+	bot.Handle(tele.OnDocument, handler.handleDocument)
+	// Handle section buttons of the compact answer
+	bot.Handle(&tele.Btn{Unique: sectionUnique}, handler.handleSection)
+	// Handle rating buttons of the answer
+	bot.Handle(&tele.Btn{Unique: feedbackUnique}, handler.handleFeedback)
+	// Handle meaning buttons of words with several interpretations
+	bot.Handle(&tele.Btn{Unique: meaningUnique}, handler.handleMeaning)
+	// Handle did-you-mean buttons of misspelled words
+	bot.Handle(&tele.Btn{Unique: suggestionUnique}, handler.handleSuggestion)
+	// Handle translation buttons of words of another language
+	bot.Handle(&tele.Btn{Unique: translateUnique}, handler.handleForeignWord)
+	// Handle wrong article reports of the answer
+	bot.Handle(&tele.Btn{Unique: reportUnique}, handler.handleReport)
+	// Handle level buttons of the /level command
+	bot.Handle(&tele.Btn{Unique: levelUnique}, handler.handleLevelButton)
+	// Handle profile buttons of the /verbosity command
+	bot.Handle(&tele.Btn{Unique: verbosityUnique}, handler.handleVerbosityButton)
+	// Handle answer and next buttons of the /quiz command
+	bot.Handle(&tele.Btn{Unique: quizUnique}, handler.handleQuizButton)
+	// Handle snooze and stop buttons of the practice reminders
+	bot.Handle(&tele.Btn{Unique: reminderUnique}, handler.handleReminderButton)
+	// Handle suggestion buttons of the /timezone command
+	bot.Handle(&tele.Btn{Unique: timezoneUnique}, handler.handleTimezoneButton)
+	// Handle confirmation buttons of the /deletemydata command
+	bot.Handle(&tele.Btn{Unique: deleteDataUnique}, handler.handleDeleteDataButton)
 	return handler, nil
 }
 
-func (h *BotHandler) SetContext(ctx context.Context) {
-	h.ctx = ctx
+// BotProfile customizes the bot for a white-label deployment
+type BotProfile struct {
+	// Welcome replaces the welcome text of /start
+	Welcome string
+	// Language is used for users without a language, its command menu is shown to the other users
+	Language string
 }
 
+// SetProfile sets the customization of the bot, it's set before registering the commands
+func (h *BotHandler) SetProfile(profile BotProfile) {
+	h.profile = profile
+}
+
+// ID returns the ID of the bot, the part of the token before the colon
+func (h *BotHandler) ID() int64 {
+	id, _, _ := strings.Cut(h.bot.Token, ":")
+	botID, _ := strconv.ParseInt(id, 10, 64)
+	return botID
+}
+
+// SetContextMiddleware passes the invoke context to the handlers with the request context of the update,
+// updates with a sender are made for the sender's identity and language
 func SetContextMiddleware(h *BotHandler) tele.MiddlewareFunc {
 	return func(next tele.HandlerFunc) tele.HandlerFunc {
 		return func(c tele.Context) error {
-			c.Set("invokeCtx", h.ctx)
+			ctx, ok := h.updates.get(c.Update().ID)
+			if !ok {
+				ctx = h.ctx
+			}
+			request := &entities.RequestContext{Adapter: entities.AdapterTelegram, RequestID: requestid.FromContext(ctx)}
+			if chat := c.Chat(); chat != nil {
+				request.Subject = entities.HashSubject("chat:" + strconv.FormatInt(chat.ID, 10))
+			}
+			sender := c.Sender()
+			if sender != nil {
+				request.Locale = h.getUserLanguage(sender)
+			}
+			ctx = usecases.WithRequestContext(ctx, request)
+			if sender != nil {
+				ctx = usecases.WithIdentity(ctx, &entities.Identity{UserID: sender.ID, Provider: entities.IdentityProviderTelegram})
+				ctx = withLanguage(ctx, request.Locale)
+			}
+			c.Set("invokeCtx", ctx)
 			return next(c)
 		}
 	}
@@ -82,6 +258,9 @@ Just type a German word and I'll provide:
 • Examples in different grammatical cases
 
 Try sending me a word like "Haus" or "Katze"!`
+	if h.profile.Welcome != "" {
+		welcomeMessage = h.profile.Welcome
+	}
 
 	return c.Send(welcomeMessage)
 }
@@ -93,122 +272,77 @@ func (h *BotHandler) handleText(c tele.Context) error {
 	defer span.End()
 
 	word := strings.TrimSpace(c.Text())
-	if word == "" {
-		return c.Send("Please send me a German word to analyze.")
-	}
-
-	// Determine user language (simplified - could be enhanced)
-	language := h.getUserLanguage(c.Sender())
-	// Create request entity
-	request := entities.NewArticleRequest(word, language)
-
-	// Execute a use case
-	response, err := h.useCase.Execute(spanCtx, request)
-	if err != nil {
-		return c.Send("Sorry, I encountered an error while processing your request. Please try again.")
+	if msg := c.Message(); isGroup(msg) {
+		// In groups only messages addressed to the bot are answered
+		var addressed bool
+		if word, addressed = h.groupTarget(msg); !h.groups.Enabled || !addressed {
+			return nil
+		}
 	}
 
-	// Format and send response
-	message := h.formatResponse(response)
-	return c.Send(message, tele.ModeHTML)
-}
+	h.stats.RecordTelegramUser(spanCtx, c.Sender().ID)
 
-// getUserLanguage determines user's preferred language
-func (h *BotHandler) getUserLanguage(user *tele.User) string {
-	if user.LanguageCode != "" {
-		return user.LanguageCode
+	if word == "" {
+		return h.replyEditable(spanCtx, c, emptyWordMessage)
 	}
 
-	return "en"
-}
-
-// formatResponse formats the article response for Telegram
-func (h *BotHandler) formatResponse(response *entities.ArticleResponse) string {
-	if !response.Success {
-		return fmt.Sprintf("❌ <b>Error:</b> %s", response.Error)
+	// Questions like "plural?" refer to the last word of the chat
+	if followUp, ok := entities.ParseFollowUp(word); ok {
+		if answered, err := h.answerFollowUp(spanCtx, c, followUp); answered {
+			return err
+		}
 	}
 
-	if len(response.Data) == 0 {
-		return "❌ No information found for this word."
+	// Words in other scripts can't be German, they are translated instead (дом → das Haus)
+	if entities.CheckWord(entities.NormalizeWord(word)) == entities.InputNonLatin {
+		return h.translate(spanCtx, c, word)
 	}
 
-	var result strings.Builder
-	wr := func(result *strings.Builder, info entities.ExampleInfo) {
-		var hasData bool
-		if info.Definite != (entities.TranslationsInfo{}) && info.Definite.NominativeExample != "" && info.Definite.NominativeTranslation != "" {
-			result.WriteString(fmt.Sprintf("• <b>Nominative Definite:</b> %s / <i>%s</i>\n", info.Definite.NominativeExample, info.Definite.NominativeTranslation))
-			hasData = true
-		}
-		if info.Indefinite != (entities.TranslationsInfo{}) && info.Indefinite.NominativeExample != "" && info.Indefinite.NominativeTranslation != "" {
-			result.WriteString(fmt.Sprintf("• <b>Nominative Indefinite:</b> %s / <i>%s</i>\n", info.Indefinite.NominativeExample, info.Indefinite.NominativeTranslation))
-			hasData = true
-		}
-		if hasData {
-			result.WriteString("\n")
-			hasData = false
-		}
+	// Execute a use case with the sender's preferences, the answer is updated when the word is edited
+	text, opts := h.lookupAnswer(spanCtx, c, word)
+	return h.replyEditable(spanCtx, c, text, opts...)
+}
 
-		if info.Definite != (entities.TranslationsInfo{}) && info.Definite.AccusativeExample != "" && info.Definite.AccusativeTranslation != "" {
-			result.WriteString(fmt.Sprintf("• <b>Accusative Definite:</b> %s / <i>%s</i>\n", info.Definite.AccusativeExample, info.Definite.AccusativeTranslation))
-			hasData = true
-		}
-		if info.Indefinite != (entities.TranslationsInfo{}) && info.Indefinite.AccusativeExample != "" && info.Indefinite.AccusativeTranslation != "" {
-			result.WriteString(fmt.Sprintf("• <b>Accusative Indefinite:</b> %s / <i>%s</i>\n", info.Indefinite.AccusativeExample, info.Indefinite.AccusativeTranslation))
-			hasData = true
-		}
-		if hasData {
-			result.WriteString("\n")
-			hasData = false
+// answer formats the response of the word in the verbosity profile with its buttons, the markup is nil
+// for answers without buttons
+func (h *BotHandler) answer(word string, response *entities.ArticleResponse, verbosity entities.Verbosity) (string, *tele.ReplyMarkup) {
+	// Ask which meaning is meant first when the word has several interpretations
+	if response.Success && len(response.Data) > 1 {
+		if markup := h.chooserMarkup(word, response); markup != nil {
+			return h.presenter.FormatChooser(response), markup
 		}
+	}
 
-		if info.Definite != (entities.TranslationsInfo{}) && info.Definite.DativeExample != "" && info.Definite.DativeTranslation != "" {
-			result.WriteString(fmt.Sprintf("• <b>Dative Definite:</b> %s / <i>%s</i>\n", info.Definite.DativeExample, info.Definite.DativeTranslation))
-			hasData = true
-		}
-		if info.Indefinite != (entities.TranslationsInfo{}) && info.Indefinite.DativeExample != "" && info.Indefinite.DativeTranslation != "" {
-			result.WriteString(fmt.Sprintf("• <b>Dative Indefinite:</b> %s / <i>%s</i>\n", info.Indefinite.DativeExample, info.Indefinite.DativeTranslation))
-			hasData = true
-		}
-		if hasData {
-			result.WriteString("\n")
-			hasData = false
+	// Send the answer of the profile, the sections of the full one are expanded on demand by the buttons
+	if response.Success && len(response.Data) > 0 {
+		if text, markup := h.profileAnswer(word, allMeanings, response, verbosity); markup != nil {
+			return text, markup
 		}
+	}
 
-		if info.Definite != (entities.TranslationsInfo{}) && info.Definite.GenitiveExample != "" && info.Definite.GenitiveTranslation != "" {
-			result.WriteString(fmt.Sprintf("• <b>Genitive Definite:</b> %s / <i>%s</i>\n", info.Definite.GenitiveExample, info.Definite.GenitiveTranslation))
-			hasData = true
-		}
-		if info.Indefinite != (entities.TranslationsInfo{}) && info.Indefinite.GenitiveExample != "" && info.Indefinite.GenitiveTranslation != "" {
-			result.WriteString(fmt.Sprintf("• <b>Genitive Indefinite:</b> %s / <i>%s</i>\n", info.Indefinite.GenitiveExample, info.Indefinite.GenitiveTranslation))
-			hasData = true
-		}
+	// Offer the German noun of a word of another language instead of the not-a-noun error
+	if markup := h.foreignWordMarkup(word, response); markup != nil {
+		return h.presenter.FormatForeignWord(word, response.DetectedLanguage), markup
 	}
-	for i, info := range response.Data {
-		if i > 0 {
-			result.WriteString("\n\n" + strings.Repeat("─", 10) + "\n\n")
-		}
 
-		result.WriteString(fmt.Sprintf("🇩🇪 <b>%s</b>\n", info.WordWithArticle))
-		result.WriteString(fmt.Sprintf("📖 <i>%s</i>\n\n", info.Translation))
+	// Offer the suggestions of a misspelled word as buttons looking them up
+	if markup := h.suggestionMarkup(response); markup != nil {
+		return h.presenter.Format(response), markup
+	}
 
-		var hasData bool
-		if (info.Example.Singular != entities.ExampleInfo{}) {
-			result.WriteString("📝 <b>Singular Examples:</b>\n")
-			wr(&result, info.Example.Singular)
-			hasData = true
-		}
+	return h.presenter.Format(presenter.ApplyVerbosity(response, verbosity)), nil
+}
 
-		if (info.Example.Plural != entities.ExampleInfo{}) {
-			if hasData {
-				result.WriteString("\n")
-			}
-			result.WriteString("📝 <b>Plural Examples:</b>\n")
-			wr(&result, info.Example.Plural)
-			hasData = true
-		}
+// getUserLanguage determines user's preferred language
+func (h *BotHandler) getUserLanguage(user *tele.User) string {
+	if user.LanguageCode != "" {
+		return user.LanguageCode
+	}
+	if h.profile.Language != "" {
+		return h.profile.Language
 	}
 
-	return result.String()
+	return "en"
 }
 
 // GetBot returns the underlying bot instance
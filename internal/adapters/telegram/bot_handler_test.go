@@ -0,0 +1,111 @@
+package telegram
+
+import (
+	cloudlogging "cloud.google.com/go/logging"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/libs/logger"
+	tele "gopkg.in/telebot.v3"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type wordKey struct{}
+
+// TestConcurrentUpdates processes updates of concurrent webhook requests, some sharing an ID, and checks
+// that the handlers of every update get the invoke context of its request, run it with -race
+func TestConcurrentUpdates(t *testing.T) {
+	bot, err := tele.NewBot(tele.Settings{Offline: true, Synchronous: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := &BotHandler{ctx: context.Background(), bot: bot}
+	bot.Use(SetContextMiddleware(h))
+
+	var mismatches atomic.Int32
+	bot.Handle(tele.OnText, func(c tele.Context) error {
+		ctx := c.Get("invokeCtx").(context.Context)
+		if ctx.Value(wordKey{}) != c.Text() {
+			mismatches.Add(1)
+		}
+		return nil
+	})
+
+	var wg sync.WaitGroup
+	for i := range 200 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			text := fmt.Sprint("word", i)
+			update := tele.Update{ID: i % 50, Message: &tele.Message{Text: text, Chat: &tele.Chat{ID: 1}}}
+			if err := h.process(context.WithValue(context.Background(), wordKey{}, text), update); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := mismatches.Load(); n > 0 {
+		t.Fatalf("%d updates got the context of another update", n)
+	}
+}
+
+// TestEnterCanceled checks that an update waiting for another update with the same ID gives up when its
+// request ends
+func TestEnterCanceled(t *testing.T) {
+	var updates updateContexts
+	leave, err := updates.enter(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer leave()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := updates.enter(ctx, 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("enter returned %v while the update was busy, want the deadline error", err)
+	}
+
+	// Other IDs aren't held up by the busy one
+	leaveOther, err := updates.enter(context.Background(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaveOther()
+}
+
+// TestProcessDuplicateUpdate checks that a duplicate delivery giving up on the update processed concurrently
+// is skipped without an error, so it isn't dead-lettered
+func TestProcessDuplicateUpdate(t *testing.T) {
+	bot, err := tele.NewBot(tele.Settings{Offline: true, Synchronous: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := &BotHandler{ctx: context.Background(), bot: bot, logger: logger.NewSlog(io.Discard, cloudlogging.Error, false)}
+
+	var handled atomic.Int32
+	bot.Handle(tele.OnText, func(c tele.Context) error {
+		handled.Add(1)
+		return errors.New("handler failed")
+	})
+
+	leave, err := h.updates.enter(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer leave()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	update := tele.Update{ID: 1, Message: &tele.Message{Text: "Haus", Chat: &tele.Chat{ID: 1}}}
+	if err := h.process(ctx, update); err != nil {
+		t.Fatalf("process of a duplicate update returned %v, want nil", err)
+	}
+	if n := handled.Load(); n != 0 {
+		t.Fatalf("handlers ran %d times for a duplicate update, want 0", n)
+	}
+}
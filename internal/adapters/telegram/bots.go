@@ -0,0 +1,172 @@
+package telegram
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/application/usecases"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	tele "gopkg.in/telebot.v3"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// secretTokenHeader carries the secret token of the webhook in the updates Telegram sends
+const secretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// Bots serves the main bot and the white-label bots of the deployment, the updates are routed to the bot
+// by the bot ID of the webhook path
+type Bots struct {
+	primary *BotHandler
+	bots    map[int64]*BotHandler
+	ids     []int64
+	logger  logging.Logger
+}
+
+// NewBots creates the bots served with the main bot
+func NewBots(primary *BotHandler, logger logging.Logger) *Bots {
+	bots := &Bots{
+		primary: primary,
+		bots:    make(map[int64]*BotHandler),
+		logger:  logger,
+	}
+	bots.Add(primary)
+
+	return bots
+}
+
+// Add serves the white-label bot besides the main bot
+func (b *Bots) Add(bot *BotHandler) {
+	b.bots[bot.ID()] = bot
+	b.ids = append(b.ids, bot.ID())
+}
+
+// BotIDs returns the IDs of the bots, the main bot first
+func (b *Bots) BotIDs() []int64 {
+	return b.ids
+}
+
+// HandleWebhook processes the update of the bot of the /telegram/{botID} path. The update must carry the
+// secret token of the bot, it's set with the webhook by SetWebhook.
+func (b *Bots) HandleWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := strconv.ParseInt(strings.TrimPrefix(r.URL.Path, usecases.BotWebhookPath), 10, 64)
+	bot, ok := b.bots[id]
+	if err != nil || !ok {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+	secret := webhookSecret(bot.bot.Token)
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get(secretTokenHeader)), []byte(secret)) != 1 {
+		b.logger.With(ctx).Field("botId", id).Warning("Rejected Telegram update without the secret token of the bot")
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		return
+	}
+	var update tele.Update
+	if err := json.Unmarshal(body, &update); err != nil || update.ID <= 0 {
+		http.Error(w, "Invalid Telegram update", http.StatusBadRequest)
+		return
+	}
+
+	bot.ProcessUpdate(ctx, body, update)
+	w.WriteHeader(http.StatusOK)
+}
+
+// Webhook returns the webhook state of the bot as reported by Telegram
+func (b *Bots) Webhook(_ context.Context, botID int64) (*entities.BotWebhook, error) {
+	bot, ok := b.bots[botID]
+	if !ok {
+		return nil, usecases.ErrBotNotFound
+	}
+	info, err := bot.bot.Webhook()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook of bot %d: %w", botID, err)
+	}
+
+	webhook := &entities.BotWebhook{
+		BotID:          botID,
+		URL:            info.Listen,
+		PendingUpdates: info.PendingUpdates,
+		LastError:      info.ErrorMessage,
+	}
+	if info.ErrorUnixtime > 0 {
+		failedAt := time.Unix(info.ErrorUnixtime, 0).UTC()
+		webhook.LastErrorAt = &failedAt
+	}
+
+	return webhook, nil
+}
+
+// SetWebhook points the webhook of the bot to the URL with the secret token of the bot
+func (b *Bots) SetWebhook(_ context.Context, botID int64, url string) error {
+	bot, ok := b.bots[botID]
+	if !ok {
+		return usecases.ErrBotNotFound
+	}
+	webhook := &tele.Webhook{
+		SecretToken: webhookSecret(bot.bot.Token),
+		Endpoint:    &tele.WebhookEndpoint{PublicURL: url},
+	}
+	if err := bot.bot.SetWebhook(webhook); err != nil {
+		return fmt.Errorf("failed to set webhook of bot %d: %w", botID, err)
+	}
+
+	return nil
+}
+
+// HandleImportJob sends the table of the import job with the bot the list was sent to
+func (b *Bots) HandleImportJob(ctx context.Context, job *entities.Job) error {
+	return b.jobBot(job).HandleImportJob(ctx, job)
+}
+
+// HandleExportJob sends the archive of the export job with the bot it was requested from
+func (b *Bots) HandleExportJob(ctx context.Context, job *entities.Job) error {
+	return b.jobBot(job).HandleExportJob(ctx, job)
+}
+
+// jobBot returns the bot of the job, the main bot for jobs of no or of an unknown bot. The payload is
+// decoded again by the handler of the job.
+func (b *Bots) jobBot(job *entities.Job) *BotHandler {
+	var payload struct {
+		BotID int64 `json:"botId"`
+	}
+	if err := json.Unmarshal(job.Payload, &payload); err == nil {
+		if bot, ok := b.bots[payload.BotID]; ok {
+			return bot
+		}
+	}
+
+	return b.primary
+}
+
+// webhookSecret derives the secret token of the webhook of the bot from its token, only Telegram and
+// the deployment know it
+func webhookSecret(token string) string {
+	sum := sha256.Sum256([]byte("webhook:" + token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,168 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/application/usecases"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"golang.org/x/time/rate"
+	tele "gopkg.in/telebot.v3"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// broadcastMessagesPerSecond stays below the Telegram broadcast limit of about 30 messages per second
+	broadcastMessagesPerSecond = 20
+	// maxFloodRetries is how often an announcement is sent again after Telegram asked to slow down
+	maxFloodRetries = 3
+)
+
+// rememberChat makes the chats of the updates recipients of the announcements, it runs after SetContextMiddleware
+func (h *BotHandler) rememberChat(next tele.HandlerFunc) tele.HandlerFunc {
+	return func(c tele.Context) error {
+		chat, sender := c.Chat(), c.Sender()
+		if chat == nil || sender == nil || h.broadcast == nil {
+			return next(c)
+		}
+
+		known := &entities.KnownChat{ID: chat.ID, Language: h.getUserLanguage(sender), SeenAt: time.Now().UTC()}
+		switch chat.Type {
+		case tele.ChatPrivate:
+		case tele.ChatGroup, tele.ChatSuperGroup:
+			known.Group = true
+			if language, ok := h.groups.Languages[chat.ID]; ok {
+				known.Language = language
+			}
+		default:
+			return next(c)
+		}
+		h.broadcast.Remember(c.Get("invokeCtx").(context.Context), known)
+
+		return next(c)
+	}
+}
+
+// handleAnnouncements handles the /announcements command, "/announcements off" stops the announcements
+// of the chat and "/announcements on" resumes them. In groups only administrators can change it.
+func (h *BotHandler) handleAnnouncements(c tele.Context) error {
+	ctx := c.Get("invokeCtx").(context.Context)
+	spanCtx, span := h.tracer.Start(ctx, "Telegram Announcements Command")
+	defer span.End()
+
+	var optedOut bool
+	switch strings.ToLower(strings.TrimSpace(c.Message().Payload)) {
+	case "on":
+	case "off":
+		optedOut = true
+	default:
+		return h.reply(c, "Use /announcements off to stop announcements about the bot or /announcements on to get them again.")
+	}
+
+	if isGroup(c.Message()) {
+		member, err := h.bot.ChatMemberOf(c.Chat(), c.Sender())
+		if err != nil {
+			return err
+		}
+		if member.Role != tele.Creator && member.Role != tele.Administrator {
+			return h.reply(c, "Only group administrators can change the announcements of the group.")
+		}
+	}
+
+	if _, err := h.broadcast.SetOptOut(spanCtx, c.Chat().ID, optedOut); err != nil {
+		return h.reply(c, "Sorry, please try again.")
+	}
+	if optedOut {
+		return h.reply(c, "This chat won't get announcements anymore. Send /announcements on to get them again.")
+	}
+
+	return h.reply(c, "This chat gets announcements about the bot again.")
+}
+
+// HandleBroadcastJob sends the announcement to every known chat that didn't opt out, paced below the Telegram
+// limits. Chats that blocked or removed the bot are forgotten, other failures are counted and skipped. Every
+// chat is recorded in the job result, so a broadcast retried after an interruption continues with the chats
+// after the last one instead of announcing it again to the chats it already reached.
+func (h *BotHandler) HandleBroadcastJob(ctx context.Context, job *entities.Job) error {
+	spanCtx, span := h.tracer.Start(ctx, "Telegram Broadcast")
+	defer span.End()
+
+	var broadcast entities.Broadcast
+	if err := json.Unmarshal(job.Payload, &broadcast); err != nil {
+		return fmt.Errorf("%w: failed to decode broadcast: %v", usecases.ErrInvalidJob, err)
+	}
+
+	chats, err := h.broadcast.Recipients(spanCtx)
+	if err != nil {
+		return err
+	}
+
+	var opts []interface{}
+	if broadcast.HTML {
+		opts = append(opts, tele.ModeHTML)
+	}
+	limiter := rate.NewLimiter(broadcastMessagesPerSecond, 1)
+	var result entities.BroadcastResult
+	resumed := usecases.PreviousJobResult(spanCtx, &result)
+	// The recipients are ordered by ID, chats that joined since the earlier attempt are counted too
+	start := 0
+	if resumed {
+		start = sort.Search(len(chats), func(i int) bool { return chats[i].ID > result.LastChatID })
+		h.logger.With(spanCtx).Field("lastChatId", result.LastChatID).Field("sent", result.Sent).Info("Resuming broadcast")
+	}
+	result.Chats = result.Sent + result.Unreachable + result.Failed + len(chats) - start
+	done := result.Chats - (len(chats) - start)
+	for i, chat := range chats[start:] {
+		err := h.sendAnnouncement(spanCtx, limiter, chat.ID, broadcast.TextFor(chat.Language), opts...)
+		switch {
+		case err == nil:
+			result.Sent++
+		case spanCtx.Err() != nil:
+			return fmt.Errorf("broadcast interrupted after %d of %d chats: %w", done+i, result.Chats, err)
+		case permanentError(err):
+			result.Unreachable++
+			_ = h.broadcast.Forget(spanCtx, chat.ID)
+		default:
+			result.Failed++
+			h.logger.With(spanCtx).Err(err).Field("chatId", chat.ID).Warning("Failed to send announcement")
+		}
+		result.LastChatID = chat.ID
+		usecases.ReportJobCheckpoint(spanCtx, done+i+1, result.Chats, result)
+	}
+
+	h.logger.With(spanCtx).Field("sent", result.Sent).Field("unreachable", result.Unreachable).Field("failed", result.Failed).Info("Broadcast finished")
+	usecases.SetJobResult(spanCtx, result)
+
+	return nil
+}
+
+// sendAnnouncement sends the text paced by the limiter. When Telegram answers 429 the whole fan-out pauses
+// for the requested delay and the text is sent again.
+func (h *BotHandler) sendAnnouncement(ctx context.Context, limiter *rate.Limiter, chatID int64, text string, opts ...interface{}) error {
+	for attempt := 0; ; attempt++ {
+		if err := limiter.Wait(ctx); err != nil {
+			return err
+		}
+		_, err := h.bot.Send(&tele.Chat{ID: chatID}, text, opts...)
+		var flood tele.FloodError
+		if !errors.As(err, &flood) || attempt == maxFloodRetries {
+			return err
+		}
+
+		h.logger.With(ctx).Field("chatId", chatID).Field("retryAfter", flood.RetryAfter).Warning("Telegram rate limit hit, pausing the broadcast")
+		select {
+		case <-time.After(time.Duration(flood.RetryAfter) * time.Second):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+var announcementsDescriptions = map[string]string{
+	"en": "Turn announcements about the bot on or off",
+	"ru": "Включить или отключить объявления о боте",
+	"de": "Ankündigungen zum Bot ein- oder ausschalten",
+}
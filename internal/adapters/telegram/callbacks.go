@@ -0,0 +1,308 @@
+package telegram
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/adapters/presenter"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	tele "gopkg.in/telebot.v3"
+	"strconv"
+	"strings"
+)
+
+const (
+	sectionUnique    = "case"
+	feedbackUnique   = "fb"
+	reportUnique     = "report"
+	meaningUnique    = "meaning"
+	suggestionUnique = "suggest"
+	translateUnique  = "translate"
+	// Telegram limits callback data to 64 bytes, "\f" + unique + "|" + action + "|" are taken by the prefix
+	// and "|" + meaning by the suffix
+	maxCallbackWordBytes = 64 - len(sectionUnique) - 8
+	// allMeanings builds the buttons of an answer showing every interpretation
+	allMeanings = -1
+)
+
+// answerMarkup builds the inline buttons expanding, rating and reporting the answer, nil if the word doesn't fit into callback data.
+// The section buttons of a chosen meaning expand only that interpretation.
+func (h *BotHandler) answerMarkup(word string, meaning int, withFeedback bool) *tele.ReplyMarkup {
+	if len(word) > maxCallbackWordBytes || strings.Contains(word, "|") {
+		return nil
+	}
+
+	markup := &tele.ReplyMarkup{}
+	buttons := make([]tele.Btn, 0, len(presenter.Sections))
+	for _, section := range presenter.Sections {
+		data := []string{string(section), word}
+		if meaning != allMeanings {
+			data = append(data, strconv.Itoa(meaning))
+		}
+		buttons = append(buttons, markup.Data(section.Label(), sectionUnique, data...))
+	}
+	rows := markup.Split(2, buttons)
+	if withFeedback {
+		rows = append(rows, feedbackRows(markup, word)...)
+	}
+	markup.Inline(rows...)
+
+	return markup
+}
+
+// feedbackMarkup builds only the rating and report buttons of the answer, nil if the word doesn't fit into callback data
+func (h *BotHandler) feedbackMarkup(word string) *tele.ReplyMarkup {
+	if len(word) > maxCallbackWordBytes || strings.Contains(word, "|") {
+		return nil
+	}
+
+	markup := &tele.ReplyMarkup{}
+	markup.Inline(feedbackRows(markup, word)...)
+
+	return markup
+}
+
+func feedbackRows(markup *tele.ReplyMarkup, word string) []tele.Row {
+	return []tele.Row{markup.Row(
+		markup.Data("👍", feedbackUnique, string(entities.VerdictUp), word),
+		markup.Data("👎", feedbackUnique, string(entities.VerdictDown), word),
+	), markup.Row(
+		markup.Data("⚠️ Report wrong article", reportUnique, word),
+	)}
+}
+
+// profileAnswer formats the answer of the verbosity profile with its buttons, only the full profile
+// gets the section buttons since the reduced ones show all their parts at once
+func (h *BotHandler) profileAnswer(word string, meaning int, response *entities.ArticleResponse, verbosity entities.Verbosity) (string, *tele.ReplyMarkup) {
+	if !verbosity.Reduced() {
+		return h.presenter.FormatCompact(response), h.answerMarkup(word, meaning, true)
+	}
+
+	return h.presenter.FormatProfile(response, verbosity), h.feedbackMarkup(word)
+}
+
+// handleSection edits the answer to show the section requested by the button
+func (h *BotHandler) handleSection(c tele.Context) error {
+	ctx := c.Get("invokeCtx").(context.Context)
+	spanCtx, span := h.tracer.Start(ctx, "Telegram Section Callback")
+	defer span.End()
+
+	sectionData, word, ok := strings.Cut(c.Data(), "|")
+	section := presenter.Section(sectionData)
+	word, meaning, err := cutMeaning(word)
+	if !ok || word == "" || !section.Valid() || err != nil {
+		h.logger.With(spanCtx).Field("data", c.Data()).Warning("Invalid section callback data")
+		return c.Respond(&tele.CallbackResponse{Text: "This button is no longer supported."})
+	}
+
+	// The examples are generated on the first section, later ones are served from the cache
+	response, err := h.lookupExamples(spanCtx, c, word)
+	if err != nil {
+		h.logger.With(spanCtx).Err(err).Field("word", word).Error("Failed to process section callback")
+		return c.Respond(&tele.CallbackResponse{Text: lookupFailure(response, "Sorry, please try again."), ShowAlert: true})
+	}
+	if meaning != allMeanings {
+		if response, ok = response.Meaning(meaning); !ok {
+			return c.Respond(&tele.CallbackResponse{Text: "This button is no longer supported."})
+		}
+	}
+
+	// Keep the current buttons, so an already submitted rating can't be repeated
+	markup := c.Message().ReplyMarkup
+	if markup == nil {
+		markup = h.answerMarkup(word, meaning, true)
+	}
+	if err := c.Edit(h.presenter.FormatSection(response, section), markup, tele.ModeHTML); err != nil &&
+		!strings.Contains(err.Error(), "message is not modified") {
+		h.logger.With(spanCtx).Err(err).Field("word", word).Error("Failed to edit message with section")
+	}
+
+	return c.Respond()
+}
+
+// handleFeedback stores the rating of the answer and removes the rating buttons
+func (h *BotHandler) handleFeedback(c tele.Context) error {
+	ctx := c.Get("invokeCtx").(context.Context)
+	spanCtx, span := h.tracer.Start(ctx, "Telegram Feedback Callback")
+	defer span.End()
+
+	verdictData, word, ok := strings.Cut(c.Data(), "|")
+	verdict := entities.Verdict(verdictData)
+	if !ok || word == "" || !verdict.Valid() {
+		h.logger.With(spanCtx).Field("data", c.Data()).Warning("Invalid feedback callback data")
+		return c.Respond(&tele.CallbackResponse{Text: "This button is no longer supported."})
+	}
+
+	request := h.articleRequest(spanCtx, c, word)
+	if err := h.feedback.Execute(spanCtx, request, verdict, c.Sender().ID); err != nil {
+		return c.Respond(&tele.CallbackResponse{Text: "Sorry, please try again.", ShowAlert: true})
+	}
+
+	if _, err := h.bot.EditReplyMarkup(c.Message(), withoutFeedback(c.Message().ReplyMarkup)); err != nil {
+		h.logger.With(spanCtx).Err(err).Field("word", word).Warning("Failed to remove feedback buttons")
+	}
+
+	return c.Respond(&tele.CallbackResponse{Text: "Thanks for your feedback!"})
+}
+
+// handleReport re-checks the reported answer and replies with the verification result
+func (h *BotHandler) handleReport(c tele.Context) error {
+	ctx := c.Get("invokeCtx").(context.Context)
+	spanCtx, span := h.tracer.Start(ctx, "Telegram Report Callback")
+	defer span.End()
+
+	word := c.Data()
+	if word == "" {
+		return c.Respond(&tele.CallbackResponse{Text: "This button is no longer supported."})
+	}
+
+	request := h.articleRequest(spanCtx, c, word)
+	verification, err := h.verify.Execute(spanCtx, request, c.Sender().ID)
+	if err != nil {
+		h.logger.With(spanCtx).Err(err).Field("word", word).Error("Failed to verify reported answer")
+		return c.Respond(&tele.CallbackResponse{Text: "Sorry, please try again.", ShowAlert: true})
+	}
+
+	if _, err := h.bot.EditReplyMarkup(c.Message(), withoutFeedback(c.Message().ReplyMarkup)); err != nil {
+		h.logger.With(spanCtx).Err(err).Field("word", word).Warning("Failed to remove feedback buttons")
+	}
+
+	if err := c.Respond(); err != nil {
+		h.logger.With(spanCtx).Err(err).Warning("Failed to answer report callback")
+	}
+
+	return h.reply(c, h.presenter.FormatVerification(verification), tele.ModeHTML)
+}
+
+// chooserMarkup builds one button per interpretation of the word, nil if the word doesn't fit into callback data
+func (h *BotHandler) chooserMarkup(word string, response *entities.ArticleResponse) *tele.ReplyMarkup {
+	if len(word) > maxCallbackWordBytes || strings.Contains(word, "|") {
+		return nil
+	}
+
+	markup := &tele.ReplyMarkup{}
+	rows := make([]tele.Row, 0, len(response.Data))
+	for i, info := range response.Data {
+		rows = append(rows, markup.Row(markup.Data(h.presenter.MeaningLabel(info), meaningUnique, strconv.Itoa(i), word)))
+	}
+	markup.Inline(rows...)
+
+	return markup
+}
+
+// handleMeaning replaces the chooser with the compact answer of the chosen interpretation
+func (h *BotHandler) handleMeaning(c tele.Context) error {
+	ctx := c.Get("invokeCtx").(context.Context)
+	spanCtx, span := h.tracer.Start(ctx, "Telegram Meaning Callback")
+	defer span.End()
+
+	meaningData, word, ok := strings.Cut(c.Data(), "|")
+	meaning, err := strconv.Atoi(meaningData)
+	if !ok || word == "" || err != nil {
+		h.logger.With(spanCtx).Field("data", c.Data()).Warning("Invalid meaning callback data")
+		return c.Respond(&tele.CallbackResponse{Text: "This button is no longer supported."})
+	}
+
+	response, err := h.lookup(spanCtx, c, word)
+	if err != nil {
+		h.logger.With(spanCtx).Err(err).Field("word", word).Error("Failed to process meaning callback")
+		return c.Respond(&tele.CallbackResponse{Text: lookupFailure(response, "Sorry, please try again."), ShowAlert: true})
+	}
+	chosen, ok := response.Meaning(meaning)
+	if !ok {
+		return c.Respond(&tele.CallbackResponse{Text: "This button is no longer supported."})
+	}
+	h.remember(spanCtx, c, word, meaning, response)
+
+	opts := []interface{}{tele.ModeHTML}
+	text, markup := h.profileAnswer(word, meaning, chosen, h.userPreferences(spanCtx, c).Verbosity)
+	if markup != nil {
+		opts = append(opts, markup)
+	}
+	if err := c.Edit(text, opts...); err != nil &&
+		!strings.Contains(err.Error(), "message is not modified") {
+		h.logger.With(spanCtx).Err(err).Field("word", word).Error("Failed to edit message with meaning")
+	}
+
+	return c.Respond()
+}
+
+// suggestionMarkup builds a "Did you mean" button per suggestion that fits into callback data, nil without suggestions
+func (h *BotHandler) suggestionMarkup(response *entities.ArticleResponse) *tele.ReplyMarkup {
+	markup := &tele.ReplyMarkup{}
+	var rows []tele.Row
+	for _, suggestion := range response.Suggestions {
+		if len(suggestion) > maxCallbackWordBytes || strings.Contains(suggestion, "|") {
+			continue
+		}
+		rows = append(rows, markup.Row(markup.Data("🔎 Did you mean "+suggestion+"?", suggestionUnique, suggestion)))
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	markup.Inline(rows...)
+
+	return markup
+}
+
+// handleSuggestion replaces the failed answer with the answer of the suggested word
+func (h *BotHandler) handleSuggestion(c tele.Context) error {
+	ctx := c.Get("invokeCtx").(context.Context)
+	spanCtx, span := h.tracer.Start(ctx, "Telegram Suggestion Callback")
+	defer span.End()
+
+	word := c.Data()
+	if word == "" {
+		return c.Respond(&tele.CallbackResponse{Text: "This button is no longer supported."})
+	}
+
+	response, err := h.lookup(spanCtx, c, word)
+	if err != nil {
+		h.logger.With(spanCtx).Err(err).Field("word", word).Error("Failed to process suggestion callback")
+		return c.Respond(&tele.CallbackResponse{Text: lookupFailure(response, "Sorry, please try again."), ShowAlert: true})
+	}
+	h.remember(spanCtx, c, word, allMeanings, response)
+
+	opts := []interface{}{tele.ModeHTML}
+	text, markup := h.answer(word, response, h.userPreferences(spanCtx, c).Verbosity)
+	if markup != nil {
+		opts = append(opts, markup)
+	}
+	if err := c.Edit(text, opts...); err != nil {
+		h.logger.With(spanCtx).Err(err).Field("word", word).Error("Failed to edit message with suggestion")
+	}
+
+	return c.Respond()
+}
+
+// cutMeaning splits the optional meaning suffix of the callback word, allMeanings if there is none
+func cutMeaning(data string) (word string, meaning int, err error) {
+	word, suffix, ok := strings.Cut(data, "|")
+	if !ok {
+		return word, allMeanings, nil
+	}
+
+	meaning, err = strconv.Atoi(suffix)
+	return word, meaning, err
+}
+
+// withoutFeedback removes the rating and report rows from the buttons of the answer
+func withoutFeedback(markup *tele.ReplyMarkup) *tele.ReplyMarkup {
+	if markup == nil {
+		return nil
+	}
+
+	rows := make([][]tele.InlineButton, 0, len(markup.InlineKeyboard))
+	for _, row := range markup.InlineKeyboard {
+		if len(row) > 0 && (isCallback(row[0], feedbackUnique) || isCallback(row[0], reportUnique)) {
+			continue
+		}
+		rows = append(rows, row)
+	}
+
+	return &tele.ReplyMarkup{InlineKeyboard: rows}
+}
+
+// isCallback reports whether the button was created with the unique, buttons of received messages
+// only carry the encoded callback data
+func isCallback(button tele.InlineButton, unique string) bool {
+	return button.Unique == unique || strings.HasPrefix(button.Data, "\f"+unique+"|") || button.Data == "\f"+unique
+}
@@ -0,0 +1,80 @@
+package telegram
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	tele "gopkg.in/telebot.v3"
+)
+
+// handleMyChatMember handles the changes of the membership of the bot: groups adding the bot get an intro,
+// users blocking it and groups removing it are forgotten
+func (h *BotHandler) handleMyChatMember(c tele.Context) error {
+	ctx := c.Get("invokeCtx").(context.Context)
+	spanCtx, span := h.tracer.Start(ctx, "Telegram Chat Member Update")
+	defer span.End()
+
+	update := c.ChatMember()
+	if update == nil || update.Chat == nil || update.OldChatMember == nil || update.NewChatMember == nil {
+		return nil
+	}
+	// Promotions and restrictions don't change whether the bot is in the chat
+	joined := isChatMember(update.NewChatMember)
+	if isChatMember(update.OldChatMember) == joined {
+		return nil
+	}
+
+	var event entities.ChatMemberEvent
+	switch update.Chat.Type {
+	case tele.ChatPrivate:
+		event = entities.ChatMemberBlocked
+		if joined {
+			event = entities.ChatMemberUnblocked
+		}
+	case tele.ChatGroup, tele.ChatSuperGroup:
+		event = entities.ChatMemberRemovedFromGroup
+		if joined {
+			event = entities.ChatMemberAddedToGroup
+		}
+	default:
+		// The bot doesn't post to channels
+		return nil
+	}
+
+	if err := h.membership.Execute(spanCtx, update.Chat.ID, event); err != nil {
+		return err
+	}
+	if event != entities.ChatMemberAddedToGroup || !h.groups.Enabled {
+		return nil
+	}
+
+	language := h.getUserLanguage(update.Sender)
+	if configured, ok := h.groups.Languages[update.Chat.ID]; ok {
+		language = configured
+	}
+
+	return c.Send(localize(language, groupIntroMessages))
+}
+
+// isChatMember reports whether the bot can read and write in the chat with the membership
+func isChatMember(member *tele.ChatMember) bool {
+	switch member.Role {
+	case tele.Creator, tele.Administrator, tele.Member:
+		return true
+	case tele.Restricted:
+		return member.Member
+	default:
+		return false
+	}
+}
+
+var groupIntroMessages = map[string]string{
+	"en": `Hallo! I'm the German Article Bot. Mention me with a German noun, e.g. "@bot Katze", or reply to my message, and I'll answer with its article, translation and plural.
+
+Reply "@bot" to someone's message to look up its word. /help lists all commands.`,
+	"ru": `Hallo! Я German Article Bot. Упомяните меня с немецким существительным, например «@bot Katze», или ответьте на моё сообщение, и я пришлю артикль, перевод и форму множественного числа.
+
+Ответьте «@bot» на чужое сообщение, чтобы найти его слово. /help покажет все команды.`,
+	"de": `Hallo! Ich bin der German Article Bot. Erwähne mich mit einem deutschen Nomen, z. B. „@bot Katze“, oder antworte auf meine Nachricht, und ich nenne Artikel, Übersetzung und Plural.
+
+Antworte „@bot“ auf die Nachricht von jemandem, um ihr Wort nachzuschlagen. /help zeigt alle Befehle.`,
+}
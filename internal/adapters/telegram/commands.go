@@ -0,0 +1,118 @@
+package telegram
+
+import (
+	"context"
+	tele "gopkg.in/telebot.v3"
+	"slices"
+	"strings"
+)
+
+const defaultLanguage = "en"
+
+// supportedLanguages lists the languages of the localized bot texts, the first one is the default
+var supportedLanguages = []string{defaultLanguage, "ru", "de"}
+
+// command describes a bot command shown in the Telegram command menu
+type command struct {
+	name         string
+	descriptions map[string]string
+	handler      tele.HandlerFunc
+}
+
+// handleCommand registers the handler of the command and adds it to the command menu
+func (h *BotHandler) handleCommand(cmd command) {
+	h.commands = append(h.commands, cmd)
+	h.bot.Handle("/"+cmd.name, cmd.handler)
+}
+
+// RegisterCommands publishes the command menu to Telegram for every supported language
+func (h *BotHandler) RegisterCommands(ctx context.Context) error {
+	// The default list is in the language of the profile of the bot when its texts are localized
+	menuLanguage := defaultLanguage
+	if slices.Contains(supportedLanguages, h.profile.Language) {
+		menuLanguage = h.profile.Language
+	}
+	for _, language := range supportedLanguages {
+		commands := make([]tele.Command, 0, len(h.commands))
+		for _, cmd := range h.commands {
+			commands = append(commands, tele.Command{
+				Text:        cmd.name,
+				Description: localize(language, cmd.descriptions),
+			})
+		}
+
+		opts := []interface{}{commands}
+		// The default language list is shown to users of languages without a dedicated list
+		if language != menuLanguage {
+			opts = append(opts, language)
+		}
+		if err := h.bot.SetCommands(opts...); err != nil {
+			h.logger.With(ctx).Err(err).Field("language", language).Error("Failed to register Telegram commands")
+			return err
+		}
+	}
+
+	return nil
+}
+
+// handleHelp handles the /help command
+func (h *BotHandler) handleHelp(c tele.Context) error {
+	ctx := c.Get("invokeCtx").(context.Context)
+	_, span := h.tracer.Start(ctx, "Telegram Help Command")
+	defer span.End()
+
+	var commands strings.Builder
+	language := h.language(c)
+	for _, cmd := range h.commands {
+		commands.WriteString("/" + cmd.name + " — " + localize(language, cmd.descriptions) + "\n")
+	}
+
+	return h.reply(c, localize(language, helpMessages)+"\n\n"+commands.String())
+}
+
+// localize returns the text in the language or in the default language
+func localize(language string, texts map[string]string) string {
+	if text, ok := texts[strings.ToLower(language)]; ok {
+		return text
+	}
+
+	return texts[defaultLanguage]
+}
+
+var (
+	startDescriptions = map[string]string{
+		"en": "Welcome message",
+		"ru": "Приветствие",
+		"de": "Begrüßung",
+	}
+
+	helpDescriptions = map[string]string{
+		"en": "How to use the bot",
+		"ru": "Как пользоваться ботом",
+		"de": "So benutzt du den Bot",
+	}
+
+	helpMessages = map[string]string{
+		"en": `Send me a German noun, e.g. "Haus", and I'll reply with its article, translation and plural.
+
+Use the buttons under the answer to show examples in Akkusativ, Dativ, Genitiv and plural.
+
+In group chats mention me (@bot Katze) or reply to my message.
+
+Commands:`,
+		"ru": `Отправьте мне немецкое существительное, например «Haus», и я отвечу артиклем, переводом и формой множественного числа.
+
+Кнопки под ответом показывают примеры в Akkusativ, Dativ, Genitiv и во множественном числе.
+
+В групповых чатах упомяните меня (@bot Katze) или ответьте на моё сообщение.
+
+Команды:`,
+		"de": `Schick mir ein deutsches Nomen, z. B. „Haus“, und ich antworte mit Artikel, Übersetzung und Plural.
+
+Mit den Buttons unter der Antwort siehst du Beispiele im Akkusativ, Dativ, Genitiv und im Plural.
+
+In Gruppen erwähne mich (@bot Katze) oder antworte auf meine Nachricht.
+
+Befehle:`,
+	}
+)
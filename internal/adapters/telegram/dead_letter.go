@@ -0,0 +1,102 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	tele "gopkg.in/telebot.v3"
+)
+
+// updateFailure collects the first error of the handlers of an update
+type updateFailure struct {
+	err error
+}
+
+type updateFailureKey struct{}
+
+// permanentErrors can't be fixed by replaying the update, so these updates aren't dead-lettered
+var permanentErrors = []error{
+	tele.ErrBlockedByUser,
+	tele.ErrUserIsDeactivated,
+	tele.ErrKickedFromGroup,
+	tele.ErrKickedFromSuperGroup,
+	tele.ErrChatNotFound,
+}
+
+// ProcessUpdate processes the update of the webhook, an update failing in a handler is dead-lettered
+// with its raw body to be replayed after a fix
+func (h *BotHandler) ProcessUpdate(ctx context.Context, raw []byte, update tele.Update) {
+	err := h.process(ctx, update)
+	if err == nil || h.deadLetters == nil {
+		return
+	}
+	if permanentError(err) {
+		h.logger.With(ctx).Err(err).Field("updateId", update.ID).Warning("Telegram update failed permanently")
+		return
+	}
+
+	var userID int64
+	if sender := h.bot.NewContext(update).Sender(); sender != nil {
+		userID = sender.ID
+	}
+	h.deadLetters.Record(ctx, raw, update.ID, userID, err)
+}
+
+// permanentError reports whether the error means the bot can no longer write to the chat
+func permanentError(err error) bool {
+	for _, permanent := range permanentErrors {
+		if errors.Is(err, permanent) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ReplayUpdate processes a dead-lettered update again and returns the error of its processing
+func (h *BotHandler) ReplayUpdate(ctx context.Context, raw []byte) error {
+	var update tele.Update
+	if err := json.Unmarshal(raw, &update); err != nil {
+		return fmt.Errorf("failed to decode update: %w", err)
+	}
+
+	return h.process(ctx, update)
+}
+
+// process runs the handlers of the update and returns the first error they reported or their panic. A
+// duplicate delivery giving up on waiting for the update processed concurrently is skipped without an
+// error, the delivery being processed reports its own outcome.
+func (h *BotHandler) process(ctx context.Context, update tele.Update) (err error) {
+	failure := &updateFailure{}
+	leave, err := h.updates.enter(context.WithValue(ctx, updateFailureKey{}, failure), update.ID)
+	if err != nil {
+		h.logger.With(ctx).Err(err).Field("updateId", update.ID).Warning("Skipped duplicate Telegram update processed concurrently")
+		return nil
+	}
+	defer leave()
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("handler panicked: %v", recovered)
+		}
+	}()
+
+	h.bot.ProcessUpdate(update)
+
+	return failure.err
+}
+
+// onError logs the error of a handler and keeps it for the dead letter of the update
+func (h *BotHandler) onError(err error, c tele.Context) {
+	ctx := h.ctx
+	if c != nil {
+		if invokeCtx, ok := c.Get("invokeCtx").(context.Context); ok {
+			ctx = invokeCtx
+		}
+	}
+
+	h.logger.With(ctx).Err(err).Error("Failed to handle Telegram update")
+	if failure, ok := ctx.Value(updateFailureKey{}).(*updateFailure); ok && failure.err == nil {
+		failure.err = err
+	}
+}
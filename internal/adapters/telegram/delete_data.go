@@ -0,0 +1,98 @@
+package telegram
+
+import (
+	"context"
+	tele "gopkg.in/telebot.v3"
+)
+
+const (
+	deleteDataUnique  = "deletedata"
+	deleteDataConfirm = "confirm"
+	deleteDataCancel  = "cancel"
+)
+
+// handleDeleteMyData handles the /deletemydata command, the deletion is confirmed with a button
+func (h *BotHandler) handleDeleteMyData(c tele.Context) error {
+	ctx := c.Get("invokeCtx").(context.Context)
+	_, span := h.tracer.Start(ctx, "Telegram Delete My Data Command")
+	defer span.End()
+
+	language := h.language(c)
+	if isGroup(c.Message()) {
+		return h.reply(c, localize(language, deleteDataPrivateOnly))
+	}
+
+	markup := &tele.ReplyMarkup{}
+	markup.Inline(markup.Row(
+		markup.Data(localize(language, deleteDataConfirmLabels), deleteDataUnique, deleteDataConfirm),
+		markup.Data(localize(language, deleteDataCancelLabels), deleteDataUnique, deleteDataCancel),
+	))
+
+	return h.reply(c, localize(language, deleteDataQuestions), markup)
+}
+
+// handleDeleteDataButton erases the data of the sender once the deletion is confirmed
+func (h *BotHandler) handleDeleteDataButton(c tele.Context) error {
+	ctx := c.Get("invokeCtx").(context.Context)
+	spanCtx, span := h.tracer.Start(ctx, "Telegram Delete My Data Callback")
+	defer span.End()
+
+	language := h.language(c)
+	text := localize(language, deleteDataCanceled)
+	if c.Data() == deleteDataConfirm {
+		if err := h.deleteData.Execute(spanCtx, c.Sender().ID); err != nil {
+			return c.Respond(&tele.CallbackResponse{Text: "Sorry, please try again.", ShowAlert: true})
+		}
+		text = localize(language, deleteDataDone)
+	}
+
+	if err := c.Edit(text); err != nil {
+		h.logger.With(spanCtx).Err(err).Warning("Failed to edit data deletion message")
+	}
+
+	return c.Respond()
+}
+
+var (
+	deleteDataDescriptions = map[string]string{
+		"en": "Delete everything the bot stores about you",
+		"ru": "Удалить все данные, которые бот хранит о вас",
+		"de": "Alles löschen, was der Bot über dich speichert",
+	}
+
+	deleteDataQuestions = map[string]string{
+		"en": "Delete your level and settings, learning statistics, achievements, ratings, leaderboard scores, practice reminder and linked apps? This can't be undone.",
+		"ru": "Удалить ваш уровень и настройки, статистику обучения, достижения, оценки, очки в рейтинге, напоминание о практике и привязанные приложения? Это нельзя отменить.",
+		"de": "Dein Niveau und deine Einstellungen, Lernstatistik, Erfolge, Bewertungen, Ranglistenpunkte, Übungserinnerung und verknüpfte Apps löschen? Das lässt sich nicht rückgängig machen.",
+	}
+
+	deleteDataConfirmLabels = map[string]string{
+		"en": "Delete everything",
+		"ru": "Удалить всё",
+		"de": "Alles löschen",
+	}
+
+	deleteDataCancelLabels = map[string]string{
+		"en": "Cancel",
+		"ru": "Отмена",
+		"de": "Abbrechen",
+	}
+
+	deleteDataDone = map[string]string{
+		"en": "Your data was deleted. Words you look up from now on are counted again.",
+		"ru": "Ваши данные удалены. Слова, которые вы будете искать дальше, снова учитываются.",
+		"de": "Deine Daten wurden gelöscht. Wörter, die du ab jetzt nachschlägst, werden wieder gezählt.",
+	}
+
+	deleteDataCanceled = map[string]string{
+		"en": "Nothing was deleted.",
+		"ru": "Ничего не удалено.",
+		"de": "Es wurde nichts gelöscht.",
+	}
+
+	deleteDataPrivateOnly = map[string]string{
+		"en": "Please send /deletemydata to me in a private chat.",
+		"ru": "Пожалуйста, отправьте /deletemydata мне в личном чате.",
+		"de": "Bitte schick mir /deletemydata im privaten Chat.",
+	}
+)
@@ -0,0 +1,96 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/libs/requestid"
+	tele "gopkg.in/telebot.v3"
+	"strconv"
+	"strings"
+)
+
+const emptyWordMessage = "Please send me a German word to analyze."
+
+// handleEdited looks up the corrected word of an edited message again and edits the answer to it in place,
+// edits of messages without a remembered answer are ignored
+func (h *BotHandler) handleEdited(c tele.Context) error {
+	ctx := c.Get("invokeCtx").(context.Context)
+	spanCtx, span := h.tracer.Start(ctx, "Telegram Edited Message")
+	defer span.End()
+
+	msg := c.Message()
+	if msg == nil || msg.Chat == nil || msg.Text == "" {
+		return nil
+	}
+	replyID, ok, err := h.replies.Get(spanCtx, msg.Chat.ID, msg.ID)
+	if err != nil {
+		h.logger.With(spanCtx).Err(err).Field("messageId", msg.ID).Warning("Failed to read the answer of the edited message")
+		return nil
+	}
+	if !ok {
+		return nil
+	}
+
+	word := strings.TrimSpace(msg.Text)
+	if isGroup(msg) {
+		var addressed bool
+		if word, addressed = h.groupTarget(msg); !h.groups.Enabled || !addressed {
+			return nil
+		}
+	}
+
+	text, opts := emptyWordMessage, []interface{}(nil)
+	if word != "" {
+		text, opts = h.lookupAnswer(spanCtx, c, word)
+	}
+
+	_, err = h.bot.Edit(&tele.StoredMessage{MessageID: strconv.Itoa(replyID), ChatID: msg.Chat.ID}, text, opts...)
+	if errors.Is(err, tele.ErrSameMessageContent) || errors.Is(err, tele.ErrMessageNotModified) {
+		// The edit didn't change the word
+		return nil
+	}
+
+	return err
+}
+
+// lookupAnswer looks up the word with the sender's preferences and formats the answer with its send options
+func (h *BotHandler) lookupAnswer(ctx context.Context, c tele.Context, word string) (string, []interface{}) {
+	response, err := h.lookup(ctx, c, word)
+	if err != nil && response != nil && response.Degraded != "" {
+		return h.presenter.Format(response), []interface{}{tele.ModeHTML}
+	}
+	if err != nil {
+		return fmt.Sprintf(
+			"Sorry, I encountered an error while processing your request. Please try again.\n\nRequest ID: %s",
+			requestid.FromContext(ctx),
+		), nil
+	}
+	h.remember(ctx, c, word, allMeanings, response)
+
+	text, markup := h.answer(word, response, h.userPreferences(ctx, c).Verbosity)
+	return text, []interface{}{markup, tele.ModeHTML}
+}
+
+// replyEditable replies like reply and remembers the answer, so an edit of the message updates it
+func (h *BotHandler) replyEditable(ctx context.Context, c tele.Context, what interface{}, opts ...interface{}) error {
+	msg := c.Message()
+	var sent *tele.Message
+	var err error
+	if isGroup(msg) {
+		sent, err = h.bot.Reply(msg, what, opts...)
+	} else {
+		sent, err = h.bot.Send(c.Recipient(), what, opts...)
+	}
+	if err != nil {
+		return err
+	}
+
+	if msg != nil && msg.Chat != nil {
+		if err := h.replies.Save(ctx, msg.Chat.ID, msg.ID, sent.ID); err != nil {
+			h.logger.With(ctx).Err(err).Field("messageId", msg.ID).Warning("Failed to remember the answer of the message")
+		}
+	}
+
+	return nil
+}
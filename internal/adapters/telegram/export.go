@@ -0,0 +1,112 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/application/usecases"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	tele "gopkg.in/telebot.v3"
+)
+
+// JobTypeExport is the background job sending the data archive of a user to the private chat
+const JobTypeExport entities.JobType = "telegram.export"
+
+// exportJob is the payload of the JobTypeExport job
+type exportJob struct {
+	ChatID   int64  `json:"chatId"`
+	UserID   int64  `json:"userId"`
+	Language string `json:"language"`
+	// BotID is the bot sending the archive, the main bot when empty
+	BotID int64 `json:"botId,omitempty"`
+}
+
+// handleMyData handles the /mydata command, the archive is compiled and sent as a JSON document by the job
+func (h *BotHandler) handleMyData(c tele.Context) error {
+	ctx := c.Get("invokeCtx").(context.Context)
+	spanCtx, span := h.tracer.Start(ctx, "Telegram My Data Command")
+	defer span.End()
+
+	language := h.language(c)
+	// The archive is personal, it is only sent to the private chat
+	if isGroup(c.Message()) {
+		return h.reply(c, localize(language, exportPrivateOnly))
+	}
+
+	job, err := usecases.NewJob(spanCtx, JobTypeExport, exportJob{
+		ChatID:   c.Chat().ID,
+		UserID:   c.Sender().ID,
+		Language: language,
+		BotID:    h.ID(),
+	})
+	if err == nil {
+		err = h.jobs.Enqueue(spanCtx, job)
+	}
+	if err != nil {
+		h.logger.With(spanCtx).Err(err).Error("Failed to enqueue data export")
+		return h.reply(c, "Sorry, please try again.")
+	}
+
+	return h.reply(c, localize(language, exportStarted))
+}
+
+// HandleExportJob compiles the data archive of the user of the export job and sends it to the chat
+func (h *BotHandler) HandleExportJob(ctx context.Context, job *entities.Job) error {
+	spanCtx, span := h.tracer.Start(ctx, "Telegram Data Export")
+	defer span.End()
+
+	var payload exportJob
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return fmt.Errorf("%w: failed to decode export job: %v", usecases.ErrInvalidJob, err)
+	}
+
+	export, err := h.export.Execute(spanCtx, payload.UserID)
+	if err != nil {
+		return err
+	}
+	var archive bytes.Buffer
+	encoder := json.NewEncoder(&archive)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(export); err != nil {
+		return fmt.Errorf("failed to encode data archive: %w", err)
+	}
+
+	document := &tele.Document{
+		File:     tele.FromReader(&archive),
+		FileName: "german-article-data.json",
+		MIME:     "application/json",
+		Caption:  localize(payload.Language, exportCaptions),
+	}
+	if _, err := h.bot.Send(&tele.Chat{ID: payload.ChatID}, document); err != nil {
+		return fmt.Errorf("failed to send data archive: %w", err)
+	}
+
+	return nil
+}
+
+var (
+	exportDescriptions = map[string]string{
+		"en": "Download everything the bot stores about you",
+		"ru": "Скачать все данные, которые бот хранит о вас",
+		"de": "Alles herunterladen, was der Bot über dich speichert",
+	}
+
+	exportStarted = map[string]string{
+		"en": "⏳ Collecting your data, I'll send you the file when it's ready.",
+		"ru": "⏳ Собираю ваши данные, пришлю файл, когда он будет готов.",
+		"de": "⏳ Ich sammle deine Daten und schicke dir die Datei, sobald sie fertig ist.",
+	}
+
+	exportCaptions = map[string]string{
+		"en": "Everything the bot stores about you. /deletemydata deletes it.",
+		"ru": "Все данные, которые бот хранит о вас. /deletemydata удаляет их.",
+		"de": "Alles, was der Bot über dich speichert. /deletemydata löscht es.",
+	}
+
+	exportPrivateOnly = map[string]string{
+		"en": "Please send /mydata to me in a private chat.",
+		"ru": "Пожалуйста, отправьте /mydata мне в личном чате.",
+		"de": "Bitte schick mir /mydata im privaten Chat.",
+	}
+)
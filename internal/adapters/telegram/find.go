@@ -0,0 +1,88 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"github.com/DeryabinSergey/germanarticlebot/internal/application/usecases"
+	tele "gopkg.in/telebot.v3"
+	"html"
+	"strings"
+)
+
+// findResults limits the words listed by /find
+const findResults = 10
+
+// handleFind handles the /find command, "/find haus" lists the words the sender looked up or met in the quiz
+// matching the query in the word or its translation
+func (h *BotHandler) handleFind(c tele.Context) error {
+	ctx := c.Get("invokeCtx").(context.Context)
+	spanCtx, span := h.tracer.Start(ctx, "Telegram Find Command")
+	defer span.End()
+
+	language := h.language(c)
+	// The history of the sender isn't shown to the other members of a group
+	if isGroup(c.Message()) {
+		return h.reply(c, localize(language, findPrivateOnly))
+	}
+
+	matches, err := h.search.Execute(spanCtx, c.Sender().ID, c.Message().Payload, findResults)
+	if errors.Is(err, usecases.ErrSearchQueryTooShort) {
+		return h.reply(c, localize(language, findUsage))
+	}
+	if err != nil {
+		return h.reply(c, "Sorry, please try again.")
+	}
+	if len(matches) == 0 {
+		return h.reply(c, localize(language, findNothing))
+	}
+
+	var text strings.Builder
+	text.WriteString(localize(language, findFound))
+	for _, match := range matches {
+		text.WriteString("\n")
+		if match.Article != "" {
+			text.WriteString(match.Article + " ")
+		}
+		text.WriteString("<b>" + html.EscapeString(match.Word) + "</b>")
+		if match.Translation != "" {
+			text.WriteString(" — " + html.EscapeString(match.Translation))
+		}
+		if match.Mastered {
+			text.WriteString(" ✓")
+		}
+	}
+
+	return h.reply(c, text.String(), tele.ModeHTML)
+}
+
+var (
+	findDescriptions = map[string]string{
+		"en": "Search the words you looked up",
+		"ru": "Поиск по найденным вами словам",
+		"de": "Deine nachgeschlagenen Wörter durchsuchen",
+	}
+
+	findUsage = map[string]string{
+		"en": "Send /find with at least two letters of a word or its translation, like /find haus.",
+		"ru": "Отправьте /find и хотя бы две буквы слова или его перевода, например /find haus.",
+		"de": "Schick /find mit mindestens zwei Buchstaben eines Wortes oder seiner Übersetzung, zum Beispiel /find haus.",
+	}
+
+	findFound = map[string]string{
+		"en": "🔎 <b>Your words</b>",
+		"ru": "🔎 <b>Ваши слова</b>",
+		"de": "🔎 <b>Deine Wörter</b>",
+	}
+
+	findNothing = map[string]string{
+		"en": "🔎 None of your words match. Look up a word and it can be found here later.",
+		"ru": "🔎 Среди ваших слов совпадений нет. Найдите слово, и потом его можно будет найти здесь.",
+		"de": "🔎 Keins deiner Wörter passt. Schlag ein Wort nach, dann findest du es später hier.",
+	}
+
+	findPrivateOnly = map[string]string{
+		"en": "Please send /find to me in a private chat.",
+		"ru": "Пожалуйста, отправьте /find мне в личном чате.",
+		"de": "Bitte schick mir /find im privaten Chat.",
+	}
+)
@@ -0,0 +1,65 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/adapters/presenter"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/libs/requestid"
+	tele "gopkg.in/telebot.v3"
+	"html"
+)
+
+// followUpSections maps the follow-up questions to the sections of the answer
+var followUpSections = map[entities.FollowUp]presenter.Section{
+	entities.FollowUpPlural:     presenter.SectionPlural,
+	entities.FollowUpAccusative: presenter.SectionAccusative,
+	entities.FollowUpDative:     presenter.SectionDative,
+	entities.FollowUpGenitive:   presenter.SectionGenitive,
+}
+
+// remember makes the answered word the subject of the follow-up questions of the chat
+func (h *BotHandler) remember(ctx context.Context, c tele.Context, word string, meaning int, response *entities.ArticleResponse) {
+	if response.Success {
+		h.followUp.Remember(ctx, c.Chat().ID, h.articleRequest(ctx, c, word), meaning)
+	}
+}
+
+// answerFollowUp answers a follow-up question about the last word of the chat, false when the chat has no recent word
+func (h *BotHandler) answerFollowUp(ctx context.Context, c tele.Context, followUp entities.FollowUp) (bool, error) {
+	answer, err := h.followUp.Execute(ctx, c.Chat().ID, followUp)
+	if err != nil {
+		h.logger.With(ctx).Err(err).Field("followUp", followUp).Error("Failed to answer follow-up question")
+		return true, h.reply(c, fmt.Sprintf(
+			"Sorry, I encountered an error while processing your request. Please try again.\n\nRequest ID: %s",
+			requestid.FromContext(ctx),
+		))
+	}
+	if answer == nil {
+		return false, nil
+	}
+
+	response := answer.Response
+	if !response.Success {
+		return true, h.reply(c, h.presenter.Format(response), tele.ModeHTML)
+	}
+	if h.userPreferences(ctx, c).HideHints {
+		response = response.WithoutHints()
+	}
+
+	var text string
+	switch {
+	case followUp == entities.FollowUpAnotherMeaning && answer.Meaning < 0:
+		text = fmt.Sprintf("<b>%s</b> has only one meaning.\n\n", html.EscapeString(answer.Word)) + h.presenter.FormatCompact(response)
+	case followUp == entities.FollowUpAnotherMeaning:
+		text = h.presenter.FormatCompact(response)
+	default:
+		text = h.presenter.FormatSection(response, followUpSections[followUp])
+	}
+
+	if markup := h.answerMarkup(answer.Word, answer.Meaning, true); markup != nil {
+		return true, h.reply(c, text, markup, tele.ModeHTML)
+	}
+
+	return true, h.reply(c, text, tele.ModeHTML)
+}
@@ -0,0 +1,84 @@
+package telegram
+
+import (
+	tele "gopkg.in/telebot.v3"
+	"strings"
+	"unicode/utf16"
+)
+
+// GroupSettings configures the bot behavior in group chats
+type GroupSettings struct {
+	Enabled bool
+	// Languages maps a group chat ID to the answer language used instead of the sender's one
+	Languages map[int64]string
+}
+
+// isGroup reports whether the message was sent to a group chat
+func isGroup(msg *tele.Message) bool {
+	return msg != nil && msg.Chat != nil && msg.FromGroup()
+}
+
+// groupTarget returns the word addressed to the bot in a group message, ok is false if the bot isn't addressed.
+// The word is the message text without the mention, or the replied message text when only the mention is sent.
+func (h *BotHandler) groupTarget(msg *tele.Message) (word string, ok bool) {
+	me := h.bot.Me
+	if me == nil {
+		return "", false
+	}
+
+	text := msg.Text
+	mentioned := false
+	// Cut the mentions from the end so the UTF-16 offsets of the remaining entities stay valid
+	for i := len(msg.Entities) - 1; i >= 0; i-- {
+		entity := msg.Entities[i]
+		isMe := (entity.Type == tele.EntityMention && strings.EqualFold(msg.EntityText(entity), "@"+me.Username)) ||
+			(entity.Type == tele.EntityTMention && entity.User != nil && entity.User.ID == me.ID)
+		if !isMe {
+			continue
+		}
+		mentioned = true
+		text = cutUTF16(text, entity.Offset, entity.Length)
+	}
+	text = strings.TrimSpace(text)
+
+	repliedToMe := msg.ReplyTo != nil && msg.ReplyTo.Sender != nil && msg.ReplyTo.Sender.ID == me.ID
+	switch {
+	case mentioned && text == "" && msg.ReplyTo != nil && !repliedToMe:
+		// "@bot" in reply to someone's message looks up that message
+		return strings.TrimSpace(msg.ReplyTo.Text), true
+	case mentioned || repliedToMe:
+		return text, true
+	default:
+		return "", false
+	}
+}
+
+// language returns the configured language of the group or the sender's language
+func (h *BotHandler) language(c tele.Context) string {
+	if msg := c.Message(); isGroup(msg) {
+		if language, ok := h.groups.Languages[msg.Chat.ID]; ok {
+			return language
+		}
+	}
+
+	return h.getUserLanguage(c.Sender())
+}
+
+// reply sends the answer in-thread for group chats and as a plain message otherwise
+func (h *BotHandler) reply(c tele.Context, what interface{}, opts ...interface{}) error {
+	if isGroup(c.Message()) {
+		return c.Reply(what, opts...)
+	}
+
+	return c.Send(what, opts...)
+}
+
+// cutUTF16 removes length UTF-16 code units at offset, as Telegram entity offsets are measured in them
+func cutUTF16(text string, offset, length int) string {
+	units := utf16.Encode([]rune(text))
+	if offset < 0 || offset+length > len(units) {
+		return text
+	}
+
+	return string(utf16.Decode(append(units[:offset:offset], units[offset+length:]...)))
+}
@@ -0,0 +1,32 @@
+package telegram
+
+import (
+	"context"
+	tele "gopkg.in/telebot.v3"
+)
+
+// HealthChecker probes the Telegram Bot API status
+type HealthChecker struct {
+	bot *tele.Bot
+}
+
+// NewHealthChecker creates a new Telegram health checker
+func NewHealthChecker(bot *tele.Bot) *HealthChecker {
+	return &HealthChecker{bot: bot}
+}
+
+// Name returns the dependency name
+func (c *HealthChecker) Name() string {
+	return "telegram"
+}
+
+// Critical reports that the HTTP API keeps working without Telegram
+func (c *HealthChecker) Critical() bool {
+	return false
+}
+
+// Check calls getMe of the Telegram Bot API
+func (c *HealthChecker) Check(_ context.Context) error {
+	_, err := c.bot.Raw("getMe", nil)
+	return err
+}
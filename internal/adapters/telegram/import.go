@@ -0,0 +1,122 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/adapters/presenter"
+	"github.com/DeryabinSergey/germanarticlebot/internal/application/usecases"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	tele "gopkg.in/telebot.v3"
+	"strings"
+)
+
+const (
+	// JobTypeImport is the background job compiling a vocabulary list sent to the bot
+	JobTypeImport entities.JobType = "telegram.import"
+
+	// maxImportFileSize bounds the size of a word list sent as a document
+	maxImportFileSize = 1 << 20
+)
+
+// importJob is the payload of the JobTypeImport job
+type importJob struct {
+	ChatID   int64    `json:"chatId"`
+	Words    []string `json:"words"`
+	Language string   `json:"language"`
+	// BotID is the bot the list was sent to, it also sends the table
+	BotID int64 `json:"botId,omitempty"`
+}
+
+// handleDocument enqueues the import of a word list sent as a plain text or CSV document,
+// the compiled table is sent back as a CSV document by the job
+func (h *BotHandler) handleDocument(c tele.Context) error {
+	ctx := c.Get("invokeCtx").(context.Context)
+	spanCtx, span := h.tracer.Start(ctx, "Telegram Document Message")
+	defer span.End()
+
+	// Imports are personal, documents in groups are ignored
+	if isGroup(c.Message()) {
+		return nil
+	}
+
+	doc := c.Message().Document
+	if !strings.HasPrefix(doc.MIME, "text/") && !strings.HasSuffix(strings.ToLower(doc.FileName), ".csv") {
+		return c.Send("Please send the word list as a plain text or CSV file, one German noun per line.")
+	}
+	if doc.FileSize > maxImportFileSize {
+		return c.Send(fmt.Sprintf("The file is too large, at most %d KB are supported.", maxImportFileSize>>10))
+	}
+
+	file, err := h.bot.File(&doc.File)
+	if err != nil {
+		h.logger.With(spanCtx).Err(err).Error("Failed to download word list")
+		return c.Send("Sorry, I couldn't download the file. Please try again.")
+	}
+	defer file.Close()
+
+	words, err := usecases.ParseWordList(file)
+	if err != nil || len(words) == 0 {
+		return c.Send("I couldn't find any words in the file. Please send one German noun per line.")
+	}
+	if len(words) > h.importer.MaxWords() {
+		return c.Send(fmt.Sprintf("The list has %d words, at most %d are supported.", len(words), h.importer.MaxWords()))
+	}
+
+	// The lookups are rate limited, so they run in a job instead of blocking the webhook
+	job, err := usecases.NewJob(spanCtx, JobTypeImport, importJob{
+		ChatID:   c.Chat().ID,
+		Words:    words,
+		Language: h.language(c),
+		BotID:    h.ID(),
+	})
+	if err == nil {
+		err = h.jobs.Enqueue(spanCtx, job)
+	}
+	if err != nil {
+		h.logger.With(spanCtx).Err(err).Error("Failed to enqueue vocabulary import")
+		return c.Send("Sorry, I couldn't start the import. Please try again.")
+	}
+
+	return c.Send(fmt.Sprintf("⏳ Importing %d words, I'll send you the table when it's ready.", len(words)))
+}
+
+// HandleImportJob looks up the words of the import job and sends the compiled table to the chat
+func (h *BotHandler) HandleImportJob(ctx context.Context, job *entities.Job) error {
+	spanCtx, span := h.tracer.Start(ctx, "Telegram Vocabulary Import")
+	defer span.End()
+
+	var payload importJob
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return fmt.Errorf("%w: failed to decode import job: %v", usecases.ErrInvalidJob, err)
+	}
+	chat := &tele.Chat{ID: payload.ChatID}
+
+	result, err := h.importer.Execute(spanCtx, payload.Words, payload.Language)
+	if errors.Is(err, usecases.ErrTooManyWords) {
+		_, _ = h.bot.Send(chat, "The list is too long, please split it into smaller files.")
+		return fmt.Errorf("%w: %v", usecases.ErrInvalidJob, err)
+	}
+	if err != nil {
+		return err
+	}
+
+	var table bytes.Buffer
+	if err := presenter.NewCSV().FormatImport(&table, result); err != nil {
+		return fmt.Errorf("failed to render vocabulary table: %w", err)
+	}
+
+	document := &tele.Document{
+		File:     tele.FromReader(&table),
+		FileName: "vocabulary.csv",
+		MIME:     "text/csv",
+		Caption:  fmt.Sprintf("✅ %d words imported, %d failed.", len(payload.Words)-len(result.Failures), len(result.Failures)),
+	}
+	if _, err := h.bot.Send(chat, document); err != nil {
+		return fmt.Errorf("failed to send vocabulary table: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,219 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/application/usecases"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"golang.org/x/time/rate"
+	tele "gopkg.in/telebot.v3"
+	"html"
+	"strings"
+	"time"
+)
+
+const (
+	// leaderboardSize is the number of members shown on a leaderboard
+	leaderboardSize = 10
+	// summaryMessagesPerSecond stays below the Telegram broadcast limit of about 30 messages per second
+	summaryMessagesPerSecond = 20
+)
+
+var leaderboardMedals = []string{"🥇", "🥈", "🥉"}
+
+// handleLeaderboard handles the /leaderboard command, "/leaderboard join [name]" opts in with an optional
+// display name, "/leaderboard leave" opts out and "/leaderboard" shows the board of the chat
+func (h *BotHandler) handleLeaderboard(c tele.Context) error {
+	ctx := c.Get("invokeCtx").(context.Context)
+	spanCtx, span := h.tracer.Start(ctx, "Telegram Leaderboard Command")
+	defer span.End()
+
+	language := h.language(c)
+	action, name, _ := strings.Cut(strings.TrimSpace(c.Message().Payload), " ")
+	switch strings.ToLower(action) {
+	case "join":
+		member, err := h.leaderboard.Join(spanCtx, c.Sender().ID, name, h.getUserLanguage(c.Sender()))
+		if errors.Is(err, usecases.ErrInvalidDisplayName) {
+			return h.reply(c, err.Error())
+		}
+		if err != nil {
+			return h.reply(c, "Sorry, please try again.")
+		}
+		return h.reply(c, fmt.Sprintf(localize(language, leaderboardJoined), html.EscapeString(member.Name)), tele.ModeHTML)
+
+	case "leave":
+		if err := h.leaderboard.Leave(spanCtx, c.Sender().ID); err != nil {
+			return h.reply(c, "Sorry, please try again.")
+		}
+		return h.reply(c, localize(language, leaderboardLeft))
+
+	case "":
+		chatID := entities.LeaderboardGlobal
+		if isGroup(c.Message()) {
+			chatID = c.Chat().ID
+		}
+		board, err := h.leaderboard.Standings(spanCtx, chatID, entities.LeaderboardWeek(time.Now()))
+		if err != nil {
+			return h.reply(c, "Sorry, please try again.")
+		}
+
+		text := formatLeaderboard(language, localize(language, leaderboardTitle), board, c.Sender().ID)
+		if member, err := h.leaderboard.Member(spanCtx, c.Sender().ID); err == nil && member == nil {
+			text += "\n\n" + localize(language, leaderboardInvite)
+		}
+		return h.reply(c, text, tele.ModeHTML)
+
+	default:
+		return h.reply(c, localize(language, leaderboardUsage))
+	}
+}
+
+// HandleLeaderboardSummaryJob sends the final global leaderboard of the week to every member who scored in it
+// and the board of every group to the group. Members who blocked the bot are skipped.
+func (h *BotHandler) HandleLeaderboardSummaryJob(ctx context.Context, job *entities.Job) error {
+	spanCtx, span := h.tracer.Start(ctx, "Telegram Leaderboard Summary")
+	defer span.End()
+
+	var payload usecases.LeaderboardSummary
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return fmt.Errorf("%w: failed to decode leaderboard summary: %v", usecases.ErrInvalidJob, err)
+	}
+
+	global, err := h.leaderboard.Standings(spanCtx, entities.LeaderboardGlobal, payload.Week)
+	if err != nil {
+		return err
+	}
+	chats, err := h.leaderboard.Chats(spanCtx, payload.Week)
+	if err != nil {
+		return err
+	}
+
+	limiter := rate.NewLimiter(summaryMessagesPerSecond, 1)
+	send := func(chatID int64, text string) error {
+		if err := limiter.Wait(spanCtx); err != nil {
+			return err
+		}
+		if _, err := h.bot.Send(&tele.Chat{ID: chatID}, text, tele.ModeHTML); err != nil {
+			h.logger.With(spanCtx).Err(err).Field("chatId", chatID).Warning("Failed to send leaderboard summary")
+		}
+		return nil
+	}
+
+	for _, entry := range global.Entries {
+		language := defaultLanguage
+		if member, err := h.leaderboard.Member(spanCtx, entry.UserID); err == nil && member != nil {
+			language = member.Language
+		}
+		title := fmt.Sprintf(localize(language, leaderboardSummaryTitle), payload.Week)
+		// The private chat of a user has the ID of the user
+		if err := send(entry.UserID, formatLeaderboard(language, title, global, entry.UserID)); err != nil {
+			return err
+		}
+	}
+	for _, chatID := range chats {
+		board, err := h.leaderboard.Standings(spanCtx, chatID, payload.Week)
+		if err != nil {
+			return err
+		}
+		if len(board.Entries) == 0 {
+			continue
+		}
+		language := defaultLanguage
+		if groupLanguage, ok := h.groups.Languages[chatID]; ok {
+			language = groupLanguage
+		}
+		title := fmt.Sprintf(localize(language, leaderboardSummaryTitle), payload.Week)
+		if err := send(chatID, formatLeaderboard(language, title, board, 0)); err != nil {
+			return err
+		}
+	}
+
+	usecases.SetJobResult(spanCtx, map[string]int{"members": len(global.Entries), "groups": len(chats)})
+	return nil
+}
+
+// formatLeaderboard lists the top members of the board, the entry of the user is added when it's below them
+func formatLeaderboard(language, title string, board *entities.Leaderboard, userID int64) string {
+	var text strings.Builder
+	text.WriteString("<b>" + title + "</b>\n")
+	if len(board.Entries) == 0 {
+		text.WriteString("\n" + localize(language, leaderboardEmpty))
+		return text.String()
+	}
+
+	for i, entry := range board.Entries {
+		if i < leaderboardSize {
+			text.WriteString(formatLeaderboardEntry(entry, entry.UserID == userID))
+		}
+	}
+	if own := board.Entry(userID); own != nil && own.Rank > leaderboardSize {
+		text.WriteString("…\n" + formatLeaderboardEntry(*own, true))
+	}
+
+	return text.String()
+}
+
+func formatLeaderboardEntry(entry entities.LeaderboardEntry, own bool) string {
+	rank := fmt.Sprintf("%d.", entry.Rank)
+	if entry.Rank <= len(leaderboardMedals) {
+		rank = leaderboardMedals[entry.Rank-1]
+	}
+	name := html.EscapeString(entry.Name)
+	if own {
+		name = "<b>" + name + "</b>"
+	}
+
+	return fmt.Sprintf("%s %s — %d ✔ / %d\n", rank, name, entry.Correct, entry.Answers)
+}
+
+var (
+	leaderboardDescriptions = map[string]string{
+		"en": "Weekly quiz leaderboard, join to take part",
+		"ru": "Еженедельный рейтинг викторины, присоединяйтесь",
+		"de": "Wöchentliche Quiz-Bestenliste, mach mit",
+	}
+
+	leaderboardTitle = map[string]string{
+		"en": "🏆 Quiz leaderboard of the week",
+		"ru": "🏆 Рейтинг викторины за неделю",
+		"de": "🏆 Quiz-Bestenliste der Woche",
+	}
+
+	leaderboardSummaryTitle = map[string]string{
+		"en": "🏆 Final quiz leaderboard of %s",
+		"ru": "🏆 Итоговый рейтинг викторины за %s",
+		"de": "🏆 Endstand der Quiz-Bestenliste %s",
+	}
+
+	leaderboardEmpty = map[string]string{
+		"en": "No scores yet this week. Answer /quiz questions to get on the board!",
+		"ru": "На этой неделе пока нет результатов. Отвечайте на вопросы /quiz, чтобы попасть в рейтинг!",
+		"de": "Diese Woche gibt es noch keine Punkte. Beantworte /quiz-Fragen, um auf die Liste zu kommen!",
+	}
+
+	leaderboardInvite = map[string]string{
+		"en": "Send /leaderboard join to take part with a pseudonym, or /leaderboard join <name> to choose your own name.",
+		"ru": "Отправьте /leaderboard join, чтобы участвовать под псевдонимом, или /leaderboard join <имя>, чтобы выбрать своё имя.",
+		"de": "Schick /leaderboard join, um mit einem Pseudonym mitzumachen, oder /leaderboard join <Name> für einen eigenen Namen.",
+	}
+
+	leaderboardJoined = map[string]string{
+		"en": "You're on the leaderboard as <b>%s</b>. Your /quiz answers count from now on; /leaderboard leave removes you and your scores.",
+		"ru": "Вы в рейтинге под именем <b>%s</b>. Ответы в /quiz теперь засчитываются; /leaderboard leave удалит вас и ваши результаты.",
+		"de": "Du bist als <b>%s</b> auf der Bestenliste. Deine /quiz-Antworten zählen ab jetzt; /leaderboard leave entfernt dich und deine Punkte.",
+	}
+
+	leaderboardLeft = map[string]string{
+		"en": "You left the leaderboard, your scores are deleted.",
+		"ru": "Вы покинули рейтинг, ваши результаты удалены.",
+		"de": "Du hast die Bestenliste verlassen, deine Punkte sind gelöscht.",
+	}
+
+	leaderboardUsage = map[string]string{
+		"en": "Use /leaderboard, /leaderboard join [name] or /leaderboard leave.",
+		"ru": "Используйте /leaderboard, /leaderboard join [имя] или /leaderboard leave.",
+		"de": "Nutze /leaderboard, /leaderboard join [Name] oder /leaderboard leave.",
+	}
+)
@@ -0,0 +1,49 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	tele "gopkg.in/telebot.v3"
+)
+
+// handleLink handles the /link command, the one-time code links the web app to the sender's profile
+func (h *BotHandler) handleLink(c tele.Context) error {
+	ctx := c.Get("invokeCtx").(context.Context)
+	spanCtx, span := h.tracer.Start(ctx, "Telegram Link Command")
+	defer span.End()
+
+	language := h.language(c)
+	// Codes posted to a group could be redeemed by any member
+	if isGroup(c.Message()) {
+		return h.reply(c, localize(language, linkPrivateOnly))
+	}
+
+	code, err := h.accounts.CreateCode(spanCtx, c.Sender().ID)
+	if err != nil {
+		h.logger.With(spanCtx).Err(err).Error("Failed to create link code")
+		return h.reply(c, "Sorry, please try again.")
+	}
+
+	minutes := int(h.accounts.CodeTTL().Minutes())
+	return h.reply(c, fmt.Sprintf(localize(language, linkMessages), code, minutes), tele.ModeHTML)
+}
+
+var (
+	linkDescriptions = map[string]string{
+		"en": "Link the web app to your profile",
+		"ru": "Связать веб-приложение с вашим профилем",
+		"de": "Die Web-App mit deinem Profil verbinden",
+	}
+
+	linkMessages = map[string]string{
+		"en": "Your link code: <code>%s</code>\n\nEnter it in the web app within %d minutes, it shares your level, hint settings and question limit with the bot. The code works once; linking again signs out the previously linked app.",
+		"ru": "Ваш код: <code>%s</code>\n\nВведите его в веб-приложении в течение %d минут — оно будет использовать ваш уровень, настройки подсказок и лимит вопросов из бота. Код действует один раз; новая привязка отключает ранее привязанное приложение.",
+		"de": "Dein Code: <code>%s</code>\n\nGib ihn innerhalb von %d Minuten in der Web-App ein, sie übernimmt dann dein Niveau, deine Hinweis-Einstellungen und dein Fragenlimit aus dem Bot. Der Code gilt einmal; eine neue Verknüpfung meldet die zuvor verknüpfte App ab.",
+	}
+
+	linkPrivateOnly = map[string]string{
+		"en": "Please send /link to me in a private chat.",
+		"ru": "Пожалуйста, отправьте /link мне в личном чате.",
+		"de": "Bitte schick mir /link im privaten Chat.",
+	}
+)
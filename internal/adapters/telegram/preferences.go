@@ -0,0 +1,311 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	tele "gopkg.in/telebot.v3"
+	"strings"
+	"time"
+)
+
+const (
+	levelUnique     = "level"
+	levelOff        = "off"
+	verbosityUnique = "verbosity"
+)
+
+// userPreferences returns the sender's preferences, the defaults if they can't be read
+func (h *BotHandler) userPreferences(ctx context.Context, c tele.Context) *entities.UserPreferences {
+	preferences, err := h.preferences.Get(ctx, c.Sender().ID)
+	if err != nil {
+		h.logger.With(ctx).Err(err).Warning("Failed to read user preferences")
+		return &entities.UserPreferences{UserID: c.Sender().ID}
+	}
+
+	return preferences
+}
+
+// articleRequest creates the request of the word in the answer language with the sender's level
+func (h *BotHandler) articleRequest(ctx context.Context, c tele.Context, word string) *entities.ArticleRequest {
+	return newArticleRequest(h.language(c), word, h.userPreferences(ctx, c))
+}
+
+// lookup answers the word with the sender's preferences, hidden hints are removed from the answer. The full
+// profile gets the core answer first, its examples are generated on demand by lookupExamples.
+func (h *BotHandler) lookup(ctx context.Context, c tele.Context, word string) (*entities.ArticleResponse, error) {
+	return h.execute(ctx, c, word, false)
+}
+
+// lookupExamples answers the word with the example sentences of the sections, continuing the core answer
+func (h *BotHandler) lookupExamples(ctx context.Context, c tele.Context, word string) (*entities.ArticleResponse, error) {
+	return h.execute(ctx, c, word, true)
+}
+
+func (h *BotHandler) execute(ctx context.Context, c tele.Context, word string, examples bool) (*entities.ArticleResponse, error) {
+	preferences := h.userPreferences(ctx, c)
+	request := newArticleRequest(h.language(c), word, preferences)
+
+	var response *entities.ArticleResponse
+	var err error
+	switch {
+	case examples:
+		response, err = h.useCase.ExecuteExamples(ctx, request)
+	case !preferences.Verbosity.Reduced():
+		response, err = h.useCase.Execute(ctx, request.Core())
+	default:
+		response, err = h.useCase.Execute(ctx, request)
+	}
+	if err != nil || !preferences.HideHints {
+		return response, err
+	}
+
+	return response.WithoutHints(), nil
+}
+
+// lookupFailure returns the localized explanation of a lookup that failed in reduced mode or the fallback
+func lookupFailure(response *entities.ArticleResponse, fallback string) string {
+	if response == nil || response.Degraded == "" || response.Error == "" {
+		return fallback
+	}
+
+	return response.Error
+}
+
+func newArticleRequest(language, word string, preferences *entities.UserPreferences) *entities.ArticleRequest {
+	request := entities.NewArticleRequest(word, language)
+	request.Level = preferences.Level
+	request.Verbosity = preferences.Verbosity
+
+	return request
+}
+
+// handleHints handles the /hints command, "/hints on" and "/hints off" show or hide the gender hints
+// and "/hints" toggles them
+func (h *BotHandler) handleHints(c tele.Context) error {
+	ctx := c.Get("invokeCtx").(context.Context)
+	spanCtx, span := h.tracer.Start(ctx, "Telegram Hints Command")
+	defer span.End()
+
+	preferences := h.userPreferences(spanCtx, c)
+	switch strings.ToLower(strings.TrimSpace(c.Message().Payload)) {
+	case "":
+		preferences.HideHints = !preferences.HideHints
+	case "on":
+		preferences.HideHints = false
+	case "off":
+		preferences.HideHints = true
+	default:
+		return h.reply(c, "Use /hints on or /hints off.")
+	}
+
+	preferences.UpdatedAt = time.Now().UTC()
+	if err := h.preferences.Save(spanCtx, preferences); err != nil {
+		h.logger.With(spanCtx).Err(err).Error("Failed to save user preferences")
+		return h.reply(c, "Sorry, please try again.")
+	}
+
+	if preferences.HideHints {
+		return h.reply(c, "Memory hints and word origins are hidden now. Send /hints to show them again.")
+	}
+
+	return h.reply(c, "Answers include memory hints for the gender and the word origin now.")
+}
+
+// handleLevel handles the /level command, "/level B1" sets the level and "/level" shows the buttons
+func (h *BotHandler) handleLevel(c tele.Context) error {
+	ctx := c.Get("invokeCtx").(context.Context)
+	spanCtx, span := h.tracer.Start(ctx, "Telegram Level Command")
+	defer span.End()
+
+	if payload := strings.TrimSpace(c.Message().Payload); payload != "" {
+		text, err := h.saveLevel(spanCtx, c.Sender().ID, payload)
+		if err != nil {
+			return h.reply(c, "Sorry, please try again.")
+		}
+		return h.reply(c, text)
+	}
+
+	preferences, err := h.preferences.Get(spanCtx, c.Sender().ID)
+	if err != nil {
+		return h.reply(c, "Sorry, please try again.")
+	}
+
+	current := "not set, the examples are of any complexity"
+	if preferences.Level != "" {
+		current = string(preferences.Level)
+	}
+
+	return h.reply(c, fmt.Sprintf("Example sentences level: %s.\nChoose the level of the examples:", current), levelMarkup())
+}
+
+// handleLevelButton saves the level chosen with the buttons of the /level command
+func (h *BotHandler) handleLevelButton(c tele.Context) error {
+	ctx := c.Get("invokeCtx").(context.Context)
+	spanCtx, span := h.tracer.Start(ctx, "Telegram Level Callback")
+	defer span.End()
+
+	text, err := h.saveLevel(spanCtx, c.Sender().ID, c.Data())
+	if err != nil {
+		return c.Respond(&tele.CallbackResponse{Text: "Sorry, please try again.", ShowAlert: true})
+	}
+
+	if err := c.Edit(text); err != nil {
+		h.logger.With(spanCtx).Err(err).Warning("Failed to edit level message")
+	}
+
+	return c.Respond()
+}
+
+// saveLevel stores the level name, "off" clears it, and returns the confirmation for the user
+func (h *BotHandler) saveLevel(ctx context.Context, userID int64, name string) (string, error) {
+	var level entities.Level
+	if !strings.EqualFold(name, levelOff) {
+		parsed, ok := entities.ParseLevel(name)
+		if !ok {
+			return "Unknown level, choose one of A1, A2, B1, B2, C1, C2 or off.", nil
+		}
+		level = parsed
+	}
+
+	preferences, err := h.preferences.Get(ctx, userID)
+	if err != nil {
+		h.logger.With(ctx).Err(err).Error("Failed to read user preferences")
+		return "", err
+	}
+	preferences.Level = level
+	preferences.UpdatedAt = time.Now().UTC()
+	if err := h.preferences.Save(ctx, preferences); err != nil {
+		h.logger.With(ctx).Err(err).Error("Failed to save user preferences")
+		return "", err
+	}
+
+	if level == "" {
+		return "Example sentences are no longer adjusted to a level.", nil
+	}
+
+	return fmt.Sprintf("Example sentences will be written for level %s.", level), nil
+}
+
+// levelMarkup builds the buttons choosing the level of the example sentences
+func levelMarkup() *tele.ReplyMarkup {
+	markup := &tele.ReplyMarkup{}
+	buttons := make([]tele.Btn, 0, len(entities.Levels))
+	for _, level := range entities.Levels {
+		buttons = append(buttons, markup.Data(string(level), levelUnique, string(level)))
+	}
+	rows := markup.Split(3, buttons)
+	rows = append(rows, markup.Row(markup.Data("Any level", levelUnique, levelOff)))
+	markup.Inline(rows...)
+
+	return markup
+}
+
+// handleVerbosity handles the /verbosity command, "/verbosity minimal" sets the answer profile and
+// "/verbosity" shows the buttons
+func (h *BotHandler) handleVerbosity(c tele.Context) error {
+	ctx := c.Get("invokeCtx").(context.Context)
+	spanCtx, span := h.tracer.Start(ctx, "Telegram Verbosity Command")
+	defer span.End()
+
+	if payload := strings.TrimSpace(c.Message().Payload); payload != "" {
+		text, err := h.saveVerbosity(spanCtx, c.Sender().ID, payload)
+		if err != nil {
+			return h.reply(c, "Sorry, please try again.")
+		}
+		return h.reply(c, text)
+	}
+
+	verbosity := h.userPreferences(spanCtx, c).Verbosity
+	if verbosity == "" {
+		verbosity = entities.VerbosityFull
+	}
+
+	return h.reply(c, fmt.Sprintf("Answer profile: %s.\nChoose how detailed the answers are:", verbosity), verbosityMarkup())
+}
+
+// handleVerbosityButton saves the profile chosen with the buttons of the /verbosity command
+func (h *BotHandler) handleVerbosityButton(c tele.Context) error {
+	ctx := c.Get("invokeCtx").(context.Context)
+	spanCtx, span := h.tracer.Start(ctx, "Telegram Verbosity Callback")
+	defer span.End()
+
+	text, err := h.saveVerbosity(spanCtx, c.Sender().ID, c.Data())
+	if err != nil {
+		return c.Respond(&tele.CallbackResponse{Text: "Sorry, please try again.", ShowAlert: true})
+	}
+
+	if err := c.Edit(text); err != nil {
+		h.logger.With(spanCtx).Err(err).Warning("Failed to edit verbosity message")
+	}
+
+	return c.Respond()
+}
+
+// saveVerbosity stores the profile name and returns the confirmation for the user
+func (h *BotHandler) saveVerbosity(ctx context.Context, userID int64, name string) (string, error) {
+	verbosity, ok := entities.ParseVerbosity(name)
+	if !ok {
+		return "Unknown profile, choose one of minimal, standard or full.", nil
+	}
+
+	preferences, err := h.preferences.Get(ctx, userID)
+	if err != nil {
+		h.logger.With(ctx).Err(err).Error("Failed to read user preferences")
+		return "", err
+	}
+	preferences.Verbosity = verbosity
+	preferences.UpdatedAt = time.Now().UTC()
+	if err := h.preferences.Save(ctx, preferences); err != nil {
+		h.logger.With(ctx).Err(err).Error("Failed to save user preferences")
+		return "", err
+	}
+
+	return verbosityConfirmations[verbosity], nil
+}
+
+// verbosityMarkup builds the buttons choosing the answer profile
+func verbosityMarkup() *tele.ReplyMarkup {
+	markup := &tele.ReplyMarkup{}
+	buttons := make([]tele.Btn, 0, len(entities.Verbosities))
+	for _, verbosity := range entities.Verbosities {
+		buttons = append(buttons, markup.Data(verbosityLabels[verbosity], verbosityUnique, string(verbosity)))
+	}
+	markup.Inline(markup.Row(buttons...))
+
+	return markup
+}
+
+var (
+	verbosityLabels = map[entities.Verbosity]string{
+		entities.VerbosityMinimal:  "Minimal",
+		entities.VerbosityStandard: "Standard",
+		entities.VerbosityFull:     "Full",
+	}
+
+	verbosityConfirmations = map[entities.Verbosity]string{
+		entities.VerbosityMinimal:  "Answers show the article and the translation only.",
+		entities.VerbosityStandard: "Answers show the article, the translation and nominative and accusative examples.",
+		entities.VerbosityFull:     "Answers show all cases, the plural and the memory hints.",
+	}
+)
+
+var (
+	levelDescriptions = map[string]string{
+		"en": "Level of the example sentences (A1–C2)",
+		"ru": "Уровень примеров (A1–C2)",
+		"de": "Niveau der Beispielsätze (A1–C2)",
+	}
+
+	verbosityDescriptions = map[string]string{
+		"en": "How detailed the answers are: minimal, standard or full",
+		"ru": "Подробность ответов: minimal, standard или full",
+		"de": "Wie ausführlich die Antworten sind: minimal, standard oder full",
+	}
+
+	hintsDescriptions = map[string]string{
+		"en": "Show or hide memory hints and word origins",
+		"ru": "Показать или скрыть подсказки и происхождение слова",
+		"de": "Merkhilfen und Wortherkunft ein- oder ausblenden",
+	}
+)
@@ -0,0 +1,153 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/application/usecases"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	tele "gopkg.in/telebot.v3"
+	"html"
+	"strings"
+)
+
+const (
+	quizUnique = "quiz"
+	quizNext   = "next"
+)
+
+// handleQuiz handles the /quiz command, it asks the article of a dictionary noun of the sender's level
+func (h *BotHandler) handleQuiz(c tele.Context) error {
+	ctx := c.Get("invokeCtx").(context.Context)
+	spanCtx, span := h.tracer.Start(ctx, "Telegram Quiz Command")
+	defer span.End()
+
+	if !h.features.Enabled(spanCtx, entities.FeatureQuiz, c.Chat().ID) {
+		return h.reply(c, localize(h.language(c), quizNotAvailableYet))
+	}
+
+	text, markup, err := h.quizQuestion(spanCtx, c)
+	if err != nil {
+		return h.reply(c, localize(h.language(c), quizUnavailable))
+	}
+
+	return h.reply(c, text, markup, tele.ModeHTML)
+}
+
+// handleQuizButton checks the article chosen with the buttons of a quiz question, the next button asks another one
+func (h *BotHandler) handleQuizButton(c tele.Context) error {
+	ctx := c.Get("invokeCtx").(context.Context)
+	spanCtx, span := h.tracer.Start(ctx, "Telegram Quiz Callback")
+	defer span.End()
+
+	language := h.language(c)
+	if c.Data() == quizNext {
+		// Questions already asked can still be answered when the quiz is turned off
+		if !h.features.Enabled(spanCtx, entities.FeatureQuiz, c.Chat().ID) {
+			return c.Respond(&tele.CallbackResponse{Text: localize(language, quizNotAvailableYet), ShowAlert: true})
+		}
+		text, markup, err := h.quizQuestion(spanCtx, c)
+		if err != nil {
+			return c.Respond(&tele.CallbackResponse{Text: localize(language, quizUnavailable), ShowAlert: true})
+		}
+		if err := h.reply(c, text, markup, tele.ModeHTML); err != nil {
+			return err
+		}
+		return c.Respond()
+	}
+
+	answer, noun, ok := strings.Cut(c.Data(), "|")
+	if !ok || noun == "" {
+		h.logger.With(spanCtx).Field("data", c.Data()).Warning("Invalid quiz callback data")
+		return c.Respond(&tele.CallbackResponse{Text: "This button is no longer supported."})
+	}
+
+	result, err := h.quiz.Answer(spanCtx, noun, answer)
+	if errors.Is(err, usecases.ErrUnknownQuizNoun) {
+		return c.Respond(&tele.CallbackResponse{Text: "This button is no longer supported."})
+	}
+	if err != nil {
+		return c.Respond(&tele.CallbackResponse{Text: "Sorry, please try again.", ShowAlert: true})
+	}
+
+	chatID := entities.LeaderboardGlobal
+	if isGroup(c.Message()) {
+		chatID = c.Chat().ID
+	}
+	h.leaderboard.RecordAnswer(spanCtx, chatID, c.Sender().ID, result.Correct)
+
+	text := fmt.Sprintf(localize(language, quizCorrect), result.Article, html.EscapeString(result.Noun))
+	if !result.Correct {
+		text = fmt.Sprintf(localize(language, quizWrong), result.Article, html.EscapeString(result.Noun), html.EscapeString(result.Answer))
+	}
+
+	markup := &tele.ReplyMarkup{}
+	markup.Inline(markup.Row(markup.Data(localize(language, quizNextLabel), quizUnique, quizNext)))
+	if err := c.Edit(text, markup, tele.ModeHTML); err != nil {
+		h.logger.With(spanCtx).Err(err).Warning("Failed to edit quiz message")
+	}
+
+	return c.Respond()
+}
+
+// quizQuestion asks about a noun of the sender's level, the buttons carry the answer and the noun
+func (h *BotHandler) quizQuestion(ctx context.Context, c tele.Context) (string, *tele.ReplyMarkup, error) {
+	question, err := h.quiz.Execute(ctx, h.userPreferences(ctx, c).Level)
+	if err != nil {
+		h.logger.With(ctx).Err(err).Warning("Failed to pick quiz question")
+		return "", nil, err
+	}
+
+	markup := &tele.ReplyMarkup{}
+	buttons := make([]tele.Btn, 0, 3)
+	for _, article := range []string{"der", "die", "das"} {
+		buttons = append(buttons, markup.Data(article, quizUnique, article, question.Noun))
+	}
+	markup.Inline(markup.Row(buttons...))
+
+	return fmt.Sprintf(localize(h.language(c), quizQuestions), html.EscapeString(question.Noun)), markup, nil
+}
+
+var (
+	quizDescriptions = map[string]string{
+		"en": "Practice articles with a quiz",
+		"ru": "Тренировать артикли в викторине",
+		"de": "Artikel im Quiz üben",
+	}
+
+	quizQuestions = map[string]string{
+		"en": "Which article? <b>%s</b>",
+		"ru": "Какой артикль? <b>%s</b>",
+		"de": "Welcher Artikel? <b>%s</b>",
+	}
+
+	quizCorrect = map[string]string{
+		"en": "✔ Correct: <b>%s %s</b>",
+		"ru": "✔ Верно: <b>%s %s</b>",
+		"de": "✔ Richtig: <b>%s %s</b>",
+	}
+
+	quizWrong = map[string]string{
+		"en": "✘ It's <b>%s %s</b>, not %s",
+		"ru": "✘ Правильно <b>%s %s</b>, а не %s",
+		"de": "✘ Es heißt <b>%s %s</b>, nicht %s",
+	}
+
+	quizNextLabel = map[string]string{
+		"en": "Next question",
+		"ru": "Следующий вопрос",
+		"de": "Nächste Frage",
+	}
+
+	quizUnavailable = map[string]string{
+		"en": "Sorry, the quiz has no questions right now.",
+		"ru": "Извините, сейчас в викторине нет вопросов.",
+		"de": "Das Quiz hat gerade leider keine Fragen.",
+	}
+
+	quizNotAvailableYet = map[string]string{
+		"en": "The quiz isn't available in this chat yet.",
+		"ru": "Викторина пока недоступна в этом чате.",
+		"de": "Das Quiz ist in diesem Chat noch nicht verfügbar.",
+	}
+)
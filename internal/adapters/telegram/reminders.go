@@ -0,0 +1,258 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/application/usecases"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"golang.org/x/time/rate"
+	tele "gopkg.in/telebot.v3"
+	"strings"
+)
+
+const (
+	reminderUnique = "remind"
+	reminderSnooze = "snooze"
+	reminderStop   = "stop"
+)
+
+// handleRemind handles the /remind command: "/remind 19:00 Europe/Berlin" sets the daily reminder at the
+// local time of the time zone, the one of the user without it, "/remind off" stops it and "/remind" shows it
+func (h *BotHandler) handleRemind(c tele.Context) error {
+	ctx := c.Get("invokeCtx").(context.Context)
+	spanCtx, span := h.tracer.Start(ctx, "Telegram Remind Command")
+	defer span.End()
+
+	language := h.language(c)
+	// The reminders are sent to the private chat with the user
+	if isGroup(c.Message()) {
+		return h.reply(c, localize(language, reminderPrivateOnly))
+	}
+
+	args := strings.Fields(c.Message().Payload)
+	switch {
+	case len(args) == 0:
+		reminder, err := h.reminders.Get(spanCtx, c.Sender().ID)
+		if err != nil {
+			return h.reply(c, "Sorry, please try again.")
+		}
+		if reminder == nil {
+			return h.reply(c, localize(language, reminderUsage))
+		}
+		return h.reply(c, fmt.Sprintf(localize(language, reminderCurrent), reminder.Time, reminder.Timezone))
+	case len(args) == 1 && (strings.EqualFold(args[0], "off") || strings.EqualFold(args[0], "stop")):
+		if err := h.reminders.Stop(spanCtx, c.Sender().ID); err != nil {
+			return h.reply(c, "Sorry, please try again.")
+		}
+		return h.reply(c, localize(language, reminderStopped))
+	case len(args) > 2:
+		return h.reply(c, localize(language, reminderUsage))
+	}
+
+	reminder := &entities.Reminder{
+		UserID:   c.Sender().ID,
+		BotID:    h.ID(),
+		Time:     args[0],
+		Language: language,
+	}
+	if len(args) == 2 {
+		reminder.Timezone = args[1]
+	}
+	reminder, err := h.reminders.Set(spanCtx, reminder)
+	if errors.Is(err, usecases.ErrInvalidReminderTime) || errors.Is(err, usecases.ErrInvalidTimezone) {
+		return h.reply(c, localize(language, reminderUsage))
+	}
+	if err != nil {
+		return h.reply(c, "Sorry, please try again.")
+	}
+
+	if err := h.reply(c, fmt.Sprintf(localize(language, reminderSet), reminder.Time, reminder.Timezone)); err != nil {
+		return err
+	}
+	h.askTimezone(spanCtx, c)
+
+	return nil
+}
+
+// handleReminderButton snoozes or stops the reminder of the sender, the buttons are removed from the reminder
+func (h *BotHandler) handleReminderButton(c tele.Context) error {
+	ctx := c.Get("invokeCtx").(context.Context)
+	spanCtx, span := h.tracer.Start(ctx, "Telegram Reminder Callback")
+	defer span.End()
+
+	language := h.language(c)
+	var text string
+	switch c.Data() {
+	case reminderSnooze:
+		_, err := h.reminders.Snooze(spanCtx, c.Sender().ID)
+		if errors.Is(err, usecases.ErrReminderNotFound) {
+			return c.Respond(&tele.CallbackResponse{Text: localize(language, reminderStopped)})
+		}
+		if err != nil {
+			return c.Respond(&tele.CallbackResponse{Text: "Sorry, please try again.", ShowAlert: true})
+		}
+		text = localize(language, reminderSnoozed)
+	case reminderStop:
+		if err := h.reminders.Stop(spanCtx, c.Sender().ID); err != nil {
+			return c.Respond(&tele.CallbackResponse{Text: "Sorry, please try again.", ShowAlert: true})
+		}
+		text = localize(language, reminderStopped)
+	default:
+		return c.Respond(&tele.CallbackResponse{Text: "This button is no longer supported."})
+	}
+
+	if err := c.Edit(c.Message().Text + "\n\n" + text); err != nil {
+		h.logger.With(spanCtx).Err(err).Warning("Failed to edit reminder message")
+	}
+
+	return c.Respond()
+}
+
+// sendReminder sends the reminder paced by the limiter. Users with the quiz get a quiz prompt, the others
+// are asked to review the articles of a few nouns.
+func (h *BotHandler) sendReminder(ctx context.Context, limiter *rate.Limiter, reminder entities.Reminder) error {
+	language := reminder.Language
+	text := localize(language, reminderReview)
+	markup := &tele.ReplyMarkup{}
+	rows := []tele.Row{markup.Row(
+		markup.Data(localize(language, reminderSnoozeLabels), reminderUnique, reminderSnooze),
+		markup.Data(localize(language, reminderStopLabels), reminderUnique, reminderStop),
+	)}
+	// The private chat of a user has the ID of the user
+	if h.features.Enabled(ctx, entities.FeatureQuiz, reminder.UserID) {
+		text = localize(language, reminderQuiz)
+		rows = append([]tele.Row{markup.Row(markup.Data(localize(language, reminderQuizLabels), quizUnique, quizNext))}, rows...)
+	}
+	markup.Inline(rows...)
+
+	return h.sendAnnouncement(ctx, limiter, reminder.UserID, text, markup)
+}
+
+// HandleRemindersJob sends the reminders due at the time of the job with the bots they were set with,
+// paced below the Telegram limits. The reminders of users who blocked the bot are stopped, the others
+// are scheduled for their next day even if they failed, so a failure doesn't repeat with every job.
+func (b *Bots) HandleRemindersJob(ctx context.Context, job *entities.Job) error {
+	spanCtx, span := b.primary.tracer.Start(ctx, "Telegram Reminders")
+	defer span.End()
+
+	var payload usecases.RemindersSend
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return fmt.Errorf("%w: failed to decode reminders: %v", usecases.ErrInvalidJob, err)
+	}
+
+	reminders := b.primary.reminders
+	due, err := reminders.Due(spanCtx, payload.At)
+	if err != nil {
+		return err
+	}
+
+	limiter := rate.NewLimiter(broadcastMessagesPerSecond, 1)
+	sent, stopped, failed := 0, 0, 0
+	for i, reminder := range due {
+		bot, ok := b.bots[reminder.BotID]
+		if !ok {
+			bot = b.primary
+		}
+
+		err := bot.sendReminder(spanCtx, limiter, reminder)
+		switch {
+		case err == nil:
+			sent++
+		case spanCtx.Err() != nil:
+			return fmt.Errorf("reminders interrupted after %d of %d: %w", i, len(due), err)
+		case permanentError(err):
+			stopped++
+			_ = reminders.Stop(spanCtx, reminder.UserID)
+		default:
+			failed++
+			b.logger.With(spanCtx).Err(err).Field("userId", reminder.UserID).Warning("Failed to send reminder")
+		}
+		if !permanentError(err) {
+			if err := reminders.Sent(spanCtx, reminder); err != nil {
+				b.logger.With(spanCtx).Err(err).Field("userId", reminder.UserID).Error("Failed to schedule the next reminder")
+			}
+		}
+		usecases.ReportJobProgress(spanCtx, i+1, len(due))
+	}
+
+	b.logger.With(spanCtx).Field("sent", sent).Field("stopped", stopped).Field("failed", failed).Info("Reminders sent")
+	usecases.SetJobResult(spanCtx, map[string]int{"sent": sent, "stopped": stopped, "failed": failed})
+
+	return nil
+}
+
+var (
+	remindDescriptions = map[string]string{
+		"en": "Get a daily practice reminder",
+		"ru": "Ежедневное напоминание о практике",
+		"de": "Tägliche Übungserinnerung erhalten",
+	}
+
+	reminderUsage = map[string]string{
+		"en": "Send /remind 19:00 Europe/Berlin to get a daily practice reminder at 19:00 of your time zone, without a time zone your /timezone is used. /remind off stops it.",
+		"ru": "Отправьте /remind 19:00 Europe/Moscow, чтобы каждый день получать напоминание о практике в 19:00 вашего часового пояса, без него используется ваш /timezone. /remind off отключает его.",
+		"de": "Schick /remind 19:00 Europe/Berlin, um täglich um 19:00 deiner Zeitzone an das Üben erinnert zu werden, ohne Zeitzone gilt deine /timezone. /remind off beendet die Erinnerung.",
+	}
+
+	reminderSet = map[string]string{
+		"en": "⏰ I'll remind you to practice every day at %s (%s). /remind off stops it.",
+		"ru": "⏰ Буду напоминать о практике каждый день в %s (%s). /remind off отключает напоминание.",
+		"de": "⏰ Ich erinnere dich jeden Tag um %s (%s) ans Üben. /remind off beendet die Erinnerung.",
+	}
+
+	reminderCurrent = map[string]string{
+		"en": "⏰ Your practice reminder is set for %s (%s). /remind off stops it.",
+		"ru": "⏰ Напоминание о практике установлено на %s (%s). /remind off отключает его.",
+		"de": "⏰ Deine Übungserinnerung ist auf %s (%s) gestellt. /remind off beendet sie.",
+	}
+
+	reminderStopped = map[string]string{
+		"en": "Reminder stopped. /remind 19:00 sets it again.",
+		"ru": "Напоминание отключено. /remind 19:00 включает его снова.",
+		"de": "Erinnerung beendet. /remind 19:00 stellt sie wieder ein.",
+	}
+
+	reminderSnoozed = map[string]string{
+		"en": "😴 I'll remind you again in an hour.",
+		"ru": "😴 Напомню снова через час.",
+		"de": "😴 Ich erinnere dich in einer Stunde noch einmal.",
+	}
+
+	reminderPrivateOnly = map[string]string{
+		"en": "Please send /remind to me in a private chat.",
+		"ru": "Пожалуйста, отправьте /remind мне в личном чате.",
+		"de": "Bitte schick mir /remind im privaten Chat.",
+	}
+
+	reminderQuiz = map[string]string{
+		"en": "⏰ Time to practice! Ready for a quick article quiz?",
+		"ru": "⏰ Время практики! Готовы к короткой викторине по артиклям?",
+		"de": "⏰ Zeit zum Üben! Bereit für ein kurzes Artikel-Quiz?",
+	}
+
+	reminderReview = map[string]string{
+		"en": "⏰ Time to practice! Send me a few German nouns and check their articles.",
+		"ru": "⏰ Время практики! Пришлите мне несколько немецких существительных и проверьте их артикли.",
+		"de": "⏰ Zeit zum Üben! Schick mir ein paar deutsche Nomen und prüf ihre Artikel.",
+	}
+
+	reminderQuizLabels = map[string]string{
+		"en": "Start quiz",
+		"ru": "Начать викторину",
+		"de": "Quiz starten",
+	}
+
+	reminderSnoozeLabels = map[string]string{
+		"en": "Snooze 1 h",
+		"ru": "Отложить на 1 ч",
+		"de": "1 Std. später",
+	}
+
+	reminderStopLabels = map[string]string{
+		"en": "Stop",
+		"ru": "Отключить",
+		"de": "Beenden",
+	}
+)
@@ -0,0 +1,82 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	tele "gopkg.in/telebot.v3"
+	"html"
+	"strings"
+)
+
+// handleStats handles the /stats command, it shows the sender's streaks, quiz accuracy and most confused words
+func (h *BotHandler) handleStats(c tele.Context) error {
+	ctx := c.Get("invokeCtx").(context.Context)
+	spanCtx, span := h.tracer.Start(ctx, "Telegram Stats Command")
+	defer span.End()
+
+	language := h.language(c)
+	stats, err := h.learning.Execute(spanCtx, c.Sender().ID)
+	if err != nil {
+		return h.reply(c, "Sorry, please try again.")
+	}
+	if stats.ActiveDays == 0 {
+		return h.reply(c, localize(language, statsEmpty))
+	}
+
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf(localize(language, statsSummary),
+		stats.CurrentStreak, stats.LongestStreak, stats.ActiveDays, stats.WordsLookedUp, stats.QuizAnswers, stats.QuizCorrect))
+
+	if len(stats.Accuracy) > 0 {
+		text.WriteString("\n\n" + localize(language, statsAccuracy))
+		for _, accuracy := range stats.Accuracy {
+			text.WriteString(fmt.Sprintf("\n%s: %.0f%% (%d/%d)", accuracy.Article, accuracy.Rate*100, accuracy.Correct, accuracy.Answers))
+		}
+	}
+
+	if len(stats.ConfusedWords) > 0 {
+		text.WriteString("\n\n" + localize(language, statsConfused))
+		for _, word := range stats.ConfusedWords {
+			text.WriteString(fmt.Sprintf("\n%s %s ✘%d", word.Article, html.EscapeString(word.Noun), word.Mistakes))
+		}
+	}
+
+	if err := h.reply(c, text.String(), tele.ModeHTML); err != nil {
+		return err
+	}
+	h.askTimezone(spanCtx, c)
+
+	return nil
+}
+
+var (
+	statsDescriptions = map[string]string{
+		"en": "Your learning streaks and quiz accuracy",
+		"ru": "Ваши серии занятий и точность в викторине",
+		"de": "Deine Lernserien und Quiz-Trefferquote",
+	}
+
+	statsEmpty = map[string]string{
+		"en": "No activity yet. Look up a word or try /quiz to start your streak!",
+		"ru": "Пока нет активности. Найдите слово или попробуйте /quiz, чтобы начать серию!",
+		"de": "Noch keine Aktivität. Schlag ein Wort nach oder probier /quiz, um deine Serie zu starten!",
+	}
+
+	statsSummary = map[string]string{
+		"en": "🔥 Current streak: <b>%d</b> days\nLongest streak: %d days\nActive days: %d\nWords looked up: %d\nQuiz answers: %d, correct: %d",
+		"ru": "🔥 Текущая серия: <b>%d</b> дн.\nСамая длинная серия: %d дн.\nАктивных дней: %d\nНайдено слов: %d\nОтветов в викторине: %d, верных: %d",
+		"de": "🔥 Aktuelle Serie: <b>%d</b> Tage\nLängste Serie: %d Tage\nAktive Tage: %d\nNachgeschlagene Wörter: %d\nQuiz-Antworten: %d, richtig: %d",
+	}
+
+	statsAccuracy = map[string]string{
+		"en": "<b>Accuracy by article</b>",
+		"ru": "<b>Точность по артиклям</b>",
+		"de": "<b>Trefferquote pro Artikel</b>",
+	}
+
+	statsConfused = map[string]string{
+		"en": "<b>Most confused words</b>",
+		"ru": "<b>Чаще всего путаете</b>",
+		"de": "<b>Am häufigsten verwechselt</b>",
+	}
+)
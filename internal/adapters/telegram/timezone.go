@@ -0,0 +1,131 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/application/usecases"
+	tele "gopkg.in/telebot.v3"
+	"strings"
+)
+
+const timezoneUnique = "timezone"
+
+// timezoneSuggestions are the time zones offered for the Telegram language of the user, Telegram doesn't
+// share the time zone itself
+var timezoneSuggestions = map[string][]string{
+	"de": {"Europe/Berlin", "Europe/Vienna", "Europe/Zurich"},
+	"ru": {"Europe/Moscow", "Asia/Yekaterinburg", "Asia/Novosibirsk"},
+	"uk": {"Europe/Kyiv", "Europe/Berlin"},
+	"en": {"Europe/London", "America/New_York", "America/Los_Angeles"},
+	"":   {"Europe/London", "Europe/Berlin", "America/New_York"},
+}
+
+// handleTimezone handles the /timezone command, "/timezone Europe/Berlin" sets the time zone of the sender
+// and "/timezone" shows it with the zones suggested for the language of the sender
+func (h *BotHandler) handleTimezone(c tele.Context) error {
+	ctx := c.Get("invokeCtx").(context.Context)
+	spanCtx, span := h.tracer.Start(ctx, "Telegram Timezone Command")
+	defer span.End()
+
+	language := h.language(c)
+	if payload := strings.TrimSpace(c.Message().Payload); payload != "" {
+		_, err := h.timezones.Set(spanCtx, c.Sender().ID, payload)
+		if errors.Is(err, usecases.ErrInvalidTimezone) {
+			return h.reply(c, localize(language, timezoneUsage))
+		}
+		if err != nil {
+			return h.reply(c, "Sorry, please try again.")
+		}
+		return h.reply(c, fmt.Sprintf(localize(language, timezoneSet), payload))
+	}
+
+	current := h.timezones.Location(spanCtx, c.Sender().ID).String()
+
+	return h.reply(c, fmt.Sprintf(localize(language, timezoneCurrent), current), timezoneMarkup(c.Sender()))
+}
+
+// handleTimezoneButton saves the time zone chosen with the suggestion buttons, the buttons are removed
+func (h *BotHandler) handleTimezoneButton(c tele.Context) error {
+	ctx := c.Get("invokeCtx").(context.Context)
+	spanCtx, span := h.tracer.Start(ctx, "Telegram Timezone Callback")
+	defer span.End()
+
+	language := h.language(c)
+	timezone := c.Data()
+	_, err := h.timezones.Set(spanCtx, c.Sender().ID, timezone)
+	if errors.Is(err, usecases.ErrInvalidTimezone) {
+		return c.Respond(&tele.CallbackResponse{Text: "This button is no longer supported."})
+	}
+	if err != nil {
+		return c.Respond(&tele.CallbackResponse{Text: "Sorry, please try again.", ShowAlert: true})
+	}
+
+	if err := c.Edit(fmt.Sprintf(localize(language, timezoneSet), timezone)); err != nil {
+		h.logger.With(spanCtx).Err(err).Warning("Failed to edit time zone message")
+	}
+
+	return c.Respond()
+}
+
+// askTimezone asks the sender for the time zone with the suggestion buttons, once and only in private chats.
+// The days of the streaks and the reminders of users who don't answer stay UTC.
+func (h *BotHandler) askTimezone(ctx context.Context, c tele.Context) {
+	if isGroup(c.Message()) || !h.timezones.Ask(ctx, c.Sender().ID) {
+		return
+	}
+
+	if err := h.reply(c, localize(h.language(c), timezoneQuestion), timezoneMarkup(c.Sender())); err != nil {
+		h.logger.With(ctx).Err(err).Warning("Failed to ask for the time zone")
+	}
+}
+
+// timezoneMarkup suggests the time zones of the Telegram language of the user, followed by UTC
+func timezoneMarkup(user *tele.User) *tele.ReplyMarkup {
+	language, _, _ := strings.Cut(strings.ToLower(user.LanguageCode), "-")
+	suggestions, ok := timezoneSuggestions[language]
+	if !ok {
+		suggestions = timezoneSuggestions[""]
+	}
+
+	markup := &tele.ReplyMarkup{}
+	rows := make([]tele.Row, 0, len(suggestions)+1)
+	for _, timezone := range append(suggestions, "UTC") {
+		rows = append(rows, markup.Row(markup.Data(timezone, timezoneUnique, timezone)))
+	}
+	markup.Inline(rows...)
+
+	return markup
+}
+
+var (
+	timezoneDescriptions = map[string]string{
+		"en": "Set your time zone for streaks and reminders",
+		"ru": "Часовой пояс для серий и напоминаний",
+		"de": "Zeitzone für Lernserien und Erinnerungen",
+	}
+
+	timezoneQuestion = map[string]string{
+		"en": "🌍 Which time zone are you in? Your streak days and reminders follow it. Choose one or send /timezone Europe/Berlin.",
+		"ru": "🌍 В каком вы часовом поясе? По нему считаются дни серий и отправляются напоминания. Выберите его или отправьте /timezone Europe/Moscow.",
+		"de": "🌍 In welcher Zeitzone bist du? Deine Lerntage und Erinnerungen richten sich nach ihr. Wähl eine aus oder schick /timezone Europe/Berlin.",
+	}
+
+	timezoneCurrent = map[string]string{
+		"en": "🌍 Your time zone is %s. Choose another one or send /timezone Europe/Berlin.",
+		"ru": "🌍 Ваш часовой пояс: %s. Выберите другой или отправьте /timezone Europe/Moscow.",
+		"de": "🌍 Deine Zeitzone ist %s. Wähl eine andere aus oder schick /timezone Europe/Berlin.",
+	}
+
+	timezoneSet = map[string]string{
+		"en": "🌍 Your time zone is %s now, your streak days and reminders follow it.",
+		"ru": "🌍 Теперь ваш часовой пояс: %s, по нему считаются дни серий и отправляются напоминания.",
+		"de": "🌍 Deine Zeitzone ist jetzt %s, deine Lerntage und Erinnerungen richten sich nach ihr.",
+	}
+
+	timezoneUsage = map[string]string{
+		"en": "Send /timezone with an IANA time zone, like /timezone Europe/Berlin.",
+		"ru": "Отправьте /timezone с часовым поясом IANA, например /timezone Europe/Moscow.",
+		"de": "Schick /timezone mit einer IANA-Zeitzone, zum Beispiel /timezone Europe/Berlin.",
+	}
+)
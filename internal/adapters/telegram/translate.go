@@ -0,0 +1,133 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/libs/requestid"
+	tele "gopkg.in/telebot.v3"
+	"html"
+	"strings"
+)
+
+// handleTranslate handles the /translate command, "/translate <word>" looks up the German nouns of a word
+// of the user's language
+func (h *BotHandler) handleTranslate(c tele.Context) error {
+	ctx := c.Get("invokeCtx").(context.Context)
+	spanCtx, span := h.tracer.Start(ctx, "Telegram Translate Command")
+	defer span.End()
+
+	word := strings.TrimSpace(c.Message().Payload)
+	if word == "" {
+		return h.reply(c, localize(h.language(c), translateUsage))
+	}
+
+	return h.translate(spanCtx, c, word)
+}
+
+// translate answers the German nouns of the word, a single noun gets the regular answer and
+// several ones a button each
+func (h *BotHandler) translate(ctx context.Context, c tele.Context, word string) error {
+	preferences := h.userPreferences(ctx, c)
+	request := entities.NewTranslationRequest(word, h.language(c))
+	request.Level = preferences.Level
+
+	result, err := h.translator.Execute(ctx, request)
+	if err != nil {
+		h.logger.With(ctx).Err(err).Field("word", word).Error("Failed to translate word")
+		return h.reply(c, fmt.Sprintf(
+			"Sorry, I encountered an error while processing your request. Please try again.\n\nRequest ID: %s",
+			requestid.FromContext(ctx),
+		))
+	}
+
+	switch len(result.Nouns) {
+	case 0:
+		return h.reply(c, fmt.Sprintf(localize(h.language(c), translationNotFound), html.EscapeString(word)), tele.ModeHTML)
+	case 1:
+		noun := result.Nouns[0]
+		response := noun.Response
+		if preferences.HideHints {
+			response = response.WithoutHints()
+		}
+		h.remember(ctx, c, noun.Noun, allMeanings, response)
+
+		text, markup := h.answer(noun.Noun, response, preferences.Verbosity)
+		text = h.presenter.FormatTranslation(result) + "\n\n" + text
+		if markup != nil {
+			return h.reply(c, text, markup, tele.ModeHTML)
+		}
+		return h.reply(c, text, tele.ModeHTML)
+	default:
+		return h.reply(c, h.presenter.FormatTranslation(result), h.translationMarkup(result), tele.ModeHTML)
+	}
+}
+
+// translationMarkup builds a button per German noun of the translated word, they look up the noun in place like suggestions
+func (h *BotHandler) translationMarkup(result *entities.TranslationResult) *tele.ReplyMarkup {
+	markup := &tele.ReplyMarkup{}
+	rows := make([]tele.Row, 0, len(result.Nouns))
+	for _, noun := range result.Nouns {
+		if len(noun.Noun) > maxCallbackWordBytes || strings.Contains(noun.Noun, "|") {
+			continue
+		}
+		rows = append(rows, markup.Row(markup.Data(h.presenter.MeaningLabel(noun.Response.Data[0]), suggestionUnique, noun.Noun)))
+	}
+	markup.Inline(rows...)
+
+	return markup
+}
+
+// foreignWordMarkup builds the button translating a word of another language, nil if the word is German
+// or doesn't fit into callback data
+func (h *BotHandler) foreignWordMarkup(word string, response *entities.ArticleResponse) *tele.ReplyMarkup {
+	if !response.IsForeignWord() || len(word) > maxCallbackWordBytes || strings.Contains(word, "|") {
+		return nil
+	}
+
+	markup := &tele.ReplyMarkup{}
+	markup.Inline(markup.Row(markup.Data("🔁 German word for "+word, translateUnique, word)))
+
+	return markup
+}
+
+// handleForeignWord answers the German nouns of the word of another language offered by the button
+func (h *BotHandler) handleForeignWord(c tele.Context) error {
+	ctx := c.Get("invokeCtx").(context.Context)
+	spanCtx, span := h.tracer.Start(ctx, "Telegram Translate Callback")
+	defer span.End()
+
+	word := c.Data()
+	if word == "" {
+		return c.Respond(&tele.CallbackResponse{Text: "This button is no longer supported."})
+	}
+
+	if _, err := h.bot.EditReplyMarkup(c.Message(), nil); err != nil {
+		h.logger.With(spanCtx).Err(err).Field("word", word).Warning("Failed to remove translate button")
+	}
+	if err := c.Respond(); err != nil {
+		h.logger.With(spanCtx).Err(err).Warning("Failed to answer translate callback")
+	}
+
+	return h.translate(spanCtx, c, word)
+}
+
+var (
+	translateDescriptions = map[string]string{
+		"en": "Find the German noun for a word of your language",
+		"ru": "Найти немецкое существительное по слову на вашем языке",
+		"de": "Das deutsche Nomen zu einem Wort deiner Sprache finden",
+	}
+
+	translateUsage = map[string]string{
+		"en": `Write the word after the command, e.g. "/translate house"`,
+		"ru": `Напишите слово после команды, например "/translate дом"`,
+		"de": `Schreibe das Wort nach dem Befehl, z. B. "/translate house"`,
+	}
+
+	translationNotFound = map[string]string{
+		"en": "I couldn't find a German noun for <b>%s</b>.",
+		"ru": "Не удалось найти немецкое существительное для <b>%s</b>.",
+		"de": "Ich habe kein deutsches Nomen für <b>%s</b> gefunden.",
+	}
+)
@@ -0,0 +1,65 @@
+package telegram
+
+import (
+	"context"
+	"sync"
+)
+
+// updateContexts passes the invoke contexts of the updates to their handlers. The Bot API library builds
+// the context of the handlers itself, so the invoke contexts are found by the ID of the update. A warm
+// instance processes the updates of concurrent webhook requests at the same time, the updates sharing an
+// ID, like a replay racing the webhook retry, are processed one after the other.
+type updateContexts struct {
+	mu     sync.Mutex
+	active map[int]*activeUpdate
+}
+
+type activeUpdate struct {
+	ctx  context.Context
+	done chan struct{}
+}
+
+// enter makes the context the one of the handlers of the update until leave is called, waiting for the
+// processing of another update with the same ID. It gives up with the error of the context when the
+// context ends first, the update isn't processed then.
+func (u *updateContexts) enter(ctx context.Context, id int) (leave func(), err error) {
+	for {
+		u.mu.Lock()
+		busy, ok := u.active[id]
+		if !ok {
+			if u.active == nil {
+				u.active = make(map[int]*activeUpdate)
+			}
+			update := &activeUpdate{ctx: ctx, done: make(chan struct{})}
+			u.active[id] = update
+			u.mu.Unlock()
+
+			return func() {
+				u.mu.Lock()
+				delete(u.active, id)
+				u.mu.Unlock()
+				close(update.done)
+			}, nil
+		}
+		u.mu.Unlock()
+
+		select {
+		case <-busy.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// get returns the context of the update being processed, ok is false for other updates
+func (u *updateContexts) get(id int) (ctx context.Context, ok bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	update, ok := u.active[id]
+	if !ok {
+		return nil, false
+	}
+
+	return update.ctx, true
+}
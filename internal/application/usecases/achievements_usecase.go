@@ -0,0 +1,209 @@
+package usecases
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/repositories"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/services"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+	"strings"
+)
+
+// LearningEventHandler receives the learning events of users, it runs synchronously with the recorded activity
+type LearningEventHandler func(ctx context.Context, event entities.LearningEvent)
+
+// achievementProgress is what the rules see of a user when an event arrives
+type achievementProgress struct {
+	activity *entities.UserActivity
+	stats    *entities.LearningStats
+	mastered map[string]bool
+	nouns    func() []entities.DictionaryEntry
+}
+
+// achievementRule awards the achievement once check passes after one of the events
+type achievementRule struct {
+	achievement entities.Achievement
+	events      []entities.LearningEventType
+	check       func(progress *achievementProgress) bool
+}
+
+// achievementRules are evaluated in order, so the announcement lists smaller milestones first
+var achievementRules = []achievementRule{
+	wordsRule("words_10", "📖", "10 words looked up", 10),
+	wordsRule("words_100", "📚", "100 words looked up", 100),
+	wordsRule("words_500", "🎓", "500 words looked up", 500),
+	streakRule("streak_3", "🔥", "3-day streak", 3),
+	streakRule("streak_10", "⚡", "10-day streak", 10),
+	streakRule("streak_30", "🏆", "30-day streak", 30),
+	{
+		achievement: entities.Achievement{ID: "quiz_correct_50", Badge: "🎯", Title: "50 correct quiz answers"},
+		events:      []entities.LearningEventType{entities.LearningEventQuizAnswer},
+		check:       func(p *achievementProgress) bool { return p.stats.QuizCorrect >= 50 },
+	},
+	suffixRule("suffix_ung", "🧩", "Mastered all -ung nouns", "ung"),
+	suffixRule("suffix_heit_keit", "🧠", "Mastered all -heit and -keit nouns", "heit", "keit"),
+	suffixRule("suffix_chen", "🐣", "Mastered all -chen nouns", "chen"),
+}
+
+// AchievementsUseCase is the rules engine awarding achievements on the learning events of users
+type AchievementsUseCase struct {
+	achievements repositories.AchievementRepository
+	activity     repositories.ActivityRepository
+	dictionary   services.DictionaryService
+	notifier     services.AchievementNotifier
+	logger       logging.Logger
+	tracer       tracing.Tracer
+}
+
+// NewAchievementsUseCase creates a new achievements use case instance
+func NewAchievementsUseCase(
+	achievements repositories.AchievementRepository,
+	activity repositories.ActivityRepository,
+	dictionary services.DictionaryService,
+	logger logging.Logger,
+	tracer tracing.Tracer,
+) *AchievementsUseCase {
+	return &AchievementsUseCase{
+		achievements: achievements,
+		activity:     activity,
+		dictionary:   dictionary,
+		logger:       logger,
+		tracer:       tracer,
+	}
+}
+
+// SetNotifier sets the notifier announcing newly awarded achievements
+func (uc *AchievementsUseCase) SetNotifier(notifier services.AchievementNotifier) {
+	uc.notifier = notifier
+}
+
+// Handle evaluates the rules triggered by the event and awards the achievements whose rules pass,
+// failures are logged since the event was already recorded
+func (uc *AchievementsUseCase) Handle(ctx context.Context, event entities.LearningEvent) {
+	spanCtx, span := uc.tracer.Start(ctx, "Evaluate Achievements")
+	defer span.End()
+
+	activity, err := uc.activity.Activity(spanCtx, event.UserID)
+	if err != nil {
+		uc.logger.With(spanCtx).Err(err).Warning("Failed to read learning activity")
+		return
+	}
+	progress := &achievementProgress{
+		activity: activity,
+		stats:    summarizeActivity(activity, event.At),
+		mastered: make(map[string]bool, len(activity.Mastered)),
+		nouns:    uc.nouns(spanCtx),
+	}
+	for _, noun := range activity.Mastered {
+		progress.mastered[strings.ToLower(noun)] = true
+	}
+
+	var awarded []entities.Achievement
+	for _, rule := range achievementRules {
+		if !rule.triggeredBy(event.Type) || !rule.check(progress) {
+			continue
+		}
+		achievement := rule.achievement
+		achievement.AwardedAt = event.At.UTC()
+		added, err := uc.achievements.Award(spanCtx, event.UserID, achievement)
+		if err != nil {
+			uc.logger.With(spanCtx).Err(err).Warning("Failed to award achievement")
+			continue
+		}
+		if added {
+			awarded = append(awarded, achievement)
+		}
+	}
+
+	if len(awarded) == 0 || uc.notifier == nil {
+		return
+	}
+	if err := uc.notifier.NotifyAchievements(spanCtx, event.UserID, awarded); err != nil {
+		uc.logger.With(spanCtx).Err(err).Warning("Failed to announce achievements")
+	}
+}
+
+// List returns the achievements of the user, oldest first
+func (uc *AchievementsUseCase) List(ctx context.Context, userID int64) ([]entities.Achievement, error) {
+	spanCtx, span := uc.tracer.Start(ctx, "List Achievements")
+	defer span.End()
+
+	achievements, err := uc.achievements.List(spanCtx, userID)
+	if err != nil {
+		uc.logger.With(spanCtx).Err(err).Error("Failed to list achievements")
+		return nil, err
+	}
+
+	return achievements, nil
+}
+
+// nouns returns a loader of the dictionary nouns, they are read once per event and only when a rule needs them
+func (uc *AchievementsUseCase) nouns(ctx context.Context) func() []entities.DictionaryEntry {
+	var (
+		entries []entities.DictionaryEntry
+		loaded  bool
+	)
+	return func() []entities.DictionaryEntry {
+		if !loaded {
+			loaded = true
+			var err error
+			if entries, err = uc.dictionary.Entries(ctx); err != nil {
+				uc.logger.With(ctx).Err(err).Warning("Failed to read dictionary")
+			}
+		}
+		return entries
+	}
+}
+
+func (r achievementRule) triggeredBy(event entities.LearningEventType) bool {
+	for _, trigger := range r.events {
+		if trigger == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+// wordsRule awards looking up count distinct words
+func wordsRule(id, badge, title string, count int) achievementRule {
+	return achievementRule{
+		achievement: entities.Achievement{ID: id, Badge: badge, Title: title},
+		events:      []entities.LearningEventType{entities.LearningEventLookup},
+		check:       func(p *achievementProgress) bool { return p.activity.Words >= count },
+	}
+}
+
+// streakRule awards a current streak of days active days
+func streakRule(id, badge, title string, days int) achievementRule {
+	return achievementRule{
+		achievement: entities.Achievement{ID: id, Badge: badge, Title: title},
+		events:      []entities.LearningEventType{entities.LearningEventLookup, entities.LearningEventQuizAnswer},
+		check:       func(p *achievementProgress) bool { return p.stats.CurrentStreak >= days },
+	}
+}
+
+// suffixRule awards answering the last quiz question about every dictionary noun with one of the suffixes right
+func suffixRule(id, badge, title string, suffixes ...string) achievementRule {
+	return achievementRule{
+		achievement: entities.Achievement{ID: id, Badge: badge, Title: title},
+		events:      []entities.LearningEventType{entities.LearningEventQuizAnswer},
+		check: func(p *achievementProgress) bool {
+			var found bool
+			for _, entry := range p.nouns() {
+				noun := strings.ToLower(entry.Noun)
+				for _, suffix := range suffixes {
+					if !strings.HasSuffix(noun, suffix) {
+						continue
+					}
+					if !p.mastered[noun] {
+						return false
+					}
+					found = true
+				}
+			}
+			return found
+		},
+	}
+}
@@ -0,0 +1,55 @@
+package usecases
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/repositories"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+)
+
+// AdminDashboardUseCase collects usage metrics for the operator dashboard
+type AdminDashboardUseCase struct {
+	stats      repositories.StatsRepository
+	quotaLimit int64
+	budget     *BudgetGuard
+	logger     logging.Logger
+	tracer     tracing.Tracer
+}
+
+// NewAdminDashboardUseCase creates a new admin dashboard use case instance
+func NewAdminDashboardUseCase(
+	stats repositories.StatsRepository,
+	quotaLimit int64,
+	budget *BudgetGuard,
+	logger logging.Logger,
+	tracer tracing.Tracer,
+) *AdminDashboardUseCase {
+	return &AdminDashboardUseCase{
+		stats:      stats,
+		quotaLimit: quotaLimit,
+		budget:     budget,
+		logger:     logger,
+		tracer:     tracer,
+	}
+}
+
+// Execute returns the dashboard snapshot with the given number of top words
+func (uc *AdminDashboardUseCase) Execute(ctx context.Context, topWords int) (*entities.DashboardStats, error) {
+	spanCtx, span := uc.tracer.Start(ctx, "Admin Dashboard")
+	defer span.End()
+
+	stats, err := uc.stats.Snapshot(spanCtx, topWords)
+	if err != nil {
+		uc.logger.With(spanCtx).Err(err).Error("Failed to build dashboard snapshot")
+		return nil, err
+	}
+
+	if uc.quotaLimit > 0 {
+		stats.Quota.Limit = uc.quotaLimit
+		stats.Quota.Remaining = max(uc.quotaLimit-stats.Quota.Used, 0)
+	}
+	uc.budget.Apply(&stats.Spend)
+
+	return stats, nil
+}
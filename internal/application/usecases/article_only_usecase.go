@@ -0,0 +1,69 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/services"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ErrNoArticle is returned for words without a definite article, like verbs and rejected input
+var ErrNoArticle = errors.New("the word has no article")
+
+// ArticleOnlyUseCase answers just the article of a noun for autocomplete-style integrations. The
+// dictionary answers most nouns in memory; the others are looked up as core lookups, which come from
+// the cache once the word was looked up by anyone.
+type ArticleOnlyUseCase struct {
+	dictionary services.DictionaryService
+	lookup     *DetermineArticleUseCase
+	logger     logging.Logger
+	tracer     tracing.Tracer
+}
+
+// NewArticleOnlyUseCase creates a new article-only use case instance
+func NewArticleOnlyUseCase(
+	dictionary services.DictionaryService,
+	lookup *DetermineArticleUseCase,
+	logger logging.Logger,
+	tracer tracing.Tracer,
+) *ArticleOnlyUseCase {
+	return &ArticleOnlyUseCase{
+		dictionary: dictionary,
+		lookup:     lookup,
+		logger:     logger,
+		tracer:     tracer,
+	}
+}
+
+// Execute returns the article of the word, the first one for nouns with several genders. Words missing
+// from the dictionary and the cache are looked up by the AI.
+func (uc *ArticleOnlyUseCase) Execute(ctx context.Context, word string) (*entities.ArticleOnlyAnswer, error) {
+	spanCtx, span := uc.tracer.Start(ctx, "Article Only")
+	defer span.End()
+
+	request := entities.NewArticleRequest(word, "en").Core()
+	article, found, err := uc.dictionary.LookupArticle(spanCtx, request.Word)
+	if err != nil {
+		uc.logger.With(spanCtx).Err(err).Field("word", request.Word).Warning("Failed to look up the dictionary article")
+	}
+	if answer, ok := entities.NewArticleOnlyAnswer(article); found && ok {
+		span.SetAttributes(attribute.String("article.source", "dictionary"))
+		return answer, nil
+	}
+
+	span.SetAttributes(attribute.String("article.source", "lookup"))
+	response, err := uc.lookup.Execute(spanCtx, request)
+	if err != nil {
+		return nil, err
+	}
+	articles := response.Articles()
+	if len(articles) == 0 {
+		return nil, ErrNoArticle
+	}
+	answer, _ := entities.NewArticleOnlyAnswer(articles[0])
+
+	return answer, nil
+}
@@ -0,0 +1,103 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/repositories"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/services"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+	"golang.org/x/time/rate"
+	"sync"
+	"time"
+)
+
+// maxAskClients bounds the number of rate limiters kept in memory, idle ones are dropped beyond it
+const maxAskClients = 10000
+
+// ErrTooManyQuestions is returned when the client exceeds its grammar question rate limit
+var ErrTooManyQuestions = errors.New("too many grammar questions, please wait a minute")
+
+// AskGrammarUseCase forwards free-form grammar questions to the AI tutor, rate limited per client
+type AskGrammarUseCase struct {
+	tutor     services.TutorService
+	stats     repositories.StatsRepository
+	perMinute int
+	mu        sync.Mutex
+	limiters  map[string]*rate.Limiter
+	logger    logging.Logger
+	tracer    tracing.Tracer
+}
+
+// NewAskGrammarUseCase creates a new grammar question use case allowing perMinute questions per client
+func NewAskGrammarUseCase(
+	tutor services.TutorService,
+	stats repositories.StatsRepository,
+	perMinute int,
+	logger logging.Logger,
+	tracer tracing.Tracer,
+) *AskGrammarUseCase {
+	return &AskGrammarUseCase{
+		tutor:     tutor,
+		stats:     stats,
+		perMinute: perMinute,
+		limiters:  make(map[string]*rate.Limiter),
+		logger:    logger,
+		tracer:    tracer,
+	}
+}
+
+// Execute answers the question of the client of the request context, ErrTooManyQuestions when its rate limit is exceeded
+func (uc *AskGrammarUseCase) Execute(ctx context.Context, question *entities.GrammarQuestion) (*entities.GrammarAnswer, error) {
+	spanCtx, span := uc.tracer.Start(ctx, "Ask Grammar Question")
+	defer span.End()
+
+	if !question.IsValid() {
+		return entities.NewGrammarErrorAnswer(fmt.Sprintf(
+			"The question must not be empty or longer than %d characters", entities.MaxGrammarQuestionLength,
+		)), nil
+	}
+	// Linked accounts share the subject and so the rate limit of the profile with the bot
+	clientID := RequestContextFromContext(spanCtx).ClientKey()
+	if !uc.allow(clientID) {
+		logRequest(spanCtx, uc.logger).Field("client", clientID).Warning("Grammar question rate limit exceeded")
+		return nil, ErrTooManyQuestions
+	}
+
+	logRequest(spanCtx, uc.logger).Field("language", question.Language).Info("Processing grammar question")
+	answer, err := uc.tutor.AnswerGrammarQuestion(spanCtx, question)
+	uc.stats.RecordAICall(spanCtx, err != nil)
+	if err != nil {
+		return entities.NewGrammarErrorAnswer("Failed to process request"), err
+	}
+
+	return answer, nil
+}
+
+// allow takes a token of the client's limiter, a full bucket is refilled within a minute
+func (uc *AskGrammarUseCase) allow(clientID string) bool {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	limiter, ok := uc.limiters[clientID]
+	if !ok {
+		if len(uc.limiters) >= maxAskClients {
+			uc.dropIdle()
+		}
+		limiter = rate.NewLimiter(rate.Every(time.Minute/time.Duration(uc.perMinute)), uc.perMinute)
+		uc.limiters[clientID] = limiter
+	}
+
+	return limiter.Allow()
+}
+
+// dropIdle removes the limiters of clients that haven't asked for long enough to refill their bucket
+func (uc *AskGrammarUseCase) dropIdle() {
+	for clientID, limiter := range uc.limiters {
+		if limiter.Tokens() >= float64(limiter.Burst()) {
+			delete(uc.limiters, clientID)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/services"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+	"time"
+)
+
+// JobTypeAsyncLookup is the background job of a lookup posting its result to a callback URL
+const JobTypeAsyncLookup entities.JobType = "article.async"
+
+// ErrInvalidCallback is returned for callback URLs results can't be delivered to
+var ErrInvalidCallback = errors.New("invalid callback URL")
+
+// AsyncLookupUseCase processes lookups in the background for integrators that can't wait for the AI,
+// the result is posted to their callback URL
+type AsyncLookupUseCase struct {
+	lookup   *DetermineArticleUseCase
+	jobs     services.JobQueue
+	webhooks services.WebhookSender
+	tenants  *TenantUseCase
+	logger   logging.Logger
+	tracer   tracing.Tracer
+}
+
+// NewAsyncLookupUseCase creates a new async lookup use case instance
+func NewAsyncLookupUseCase(
+	lookup *DetermineArticleUseCase,
+	jobs services.JobQueue,
+	webhooks services.WebhookSender,
+	logger logging.Logger,
+	tracer tracing.Tracer,
+) *AsyncLookupUseCase {
+	return &AsyncLookupUseCase{
+		lookup:   lookup,
+		jobs:     jobs,
+		webhooks: webhooks,
+		logger:   logger,
+		tracer:   tracer,
+	}
+}
+
+// SetTenants looks up the words of the jobs for the tenants that submitted them, nil looks them up for no tenant
+func (uc *AsyncLookupUseCase) SetTenants(tenants *TenantUseCase) {
+	uc.tenants = tenants
+}
+
+// Submit enqueues the lookup and returns its job, errors wrapping ErrInvalidCallback are caused by the callback URL
+func (uc *AsyncLookupUseCase) Submit(ctx context.Context, lookup entities.AsyncLookup) (*entities.Job, error) {
+	spanCtx, span := uc.tracer.Start(ctx, "Submit Async Lookup")
+	defer span.End()
+
+	if err := uc.webhooks.Validate(lookup.CallbackURL); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCallback, err)
+	}
+
+	lookup.Tenant = ""
+	if tenant := TenantFromContext(spanCtx); tenant != nil {
+		lookup.Tenant = tenant.ID
+	}
+	job, err := NewJob(spanCtx, JobTypeAsyncLookup, lookup)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.jobs.Enqueue(spanCtx, job); err != nil {
+		uc.logger.With(spanCtx).Err(err).Error("Failed to enqueue async lookup")
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// Handle looks up the word of the job and posts the result to the callback URL. Failed lookups are
+// delivered as error answers, failed deliveries are retried unless the receiver rejected them.
+func (uc *AsyncLookupUseCase) Handle(ctx context.Context, job *entities.Job) error {
+	spanCtx, span := uc.tracer.Start(ctx, "Async Lookup")
+	defer span.End()
+
+	var lookup entities.AsyncLookup
+	if err := json.Unmarshal(job.Payload, &lookup); err != nil {
+		return fmt.Errorf("%w: failed to decode async lookup: %v", ErrInvalidJob, err)
+	}
+
+	// The job runs outside of the request, so the tenant is restored from the payload
+	if lookup.Tenant != "" && uc.tenants != nil {
+		if tenant := uc.tenants.Load(spanCtx, lookup.Tenant); tenant != nil {
+			spanCtx = WithTenant(spanCtx, tenant)
+		}
+	}
+
+	request := entities.NewArticleRequest(lookup.Word, lookup.Language)
+	request.Level = lookup.Level
+	response, err := uc.lookup.Execute(spanCtx, request)
+	if err != nil {
+		uc.logger.With(spanCtx).Field("jobId", job.ID).Err(err).Warning("Async lookup failed")
+	}
+
+	result := entities.AsyncLookupResult{
+		JobID:       job.ID,
+		Word:        lookup.Word,
+		Language:    lookup.Language,
+		Level:       lookup.Level,
+		Result:      response,
+		CompletedAt: time.Now().UTC(),
+	}
+	// The result can be polled as well, when the callback is down
+	SetJobResult(spanCtx, result)
+
+	payload, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("%w: failed to encode async lookup result: %v", ErrInvalidJob, err)
+	}
+
+	err = uc.webhooks.Send(spanCtx, lookup.CallbackURL, payload)
+	if errors.Is(err, services.ErrWebhookRejected) {
+		return fmt.Errorf("%w: %v", ErrInvalidJob, err)
+	}
+
+	return err
+}
@@ -0,0 +1,76 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/repositories"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+	"github.com/google/uuid"
+	"time"
+)
+
+// AuditLogUseCase records the admin operations in the append-only audit log
+type AuditLogUseCase struct {
+	entries repositories.AuditRepository
+	logger  logging.Logger
+	tracer  tracing.Tracer
+}
+
+// NewAuditLogUseCase creates a new audit log use case instance
+func NewAuditLogUseCase(
+	entries repositories.AuditRepository,
+	logger logging.Logger,
+	tracer tracing.Tracer,
+) *AuditLogUseCase {
+	return &AuditLogUseCase{
+		entries: entries,
+		logger:  logger,
+		tracer:  tracer,
+	}
+}
+
+// Record appends the action of the actor with its payload. The operation already happened, so a failure
+// to store it is logged with the payload rather than returned.
+func (uc *AuditLogUseCase) Record(ctx context.Context, action entities.AuditAction, actor, clientIP string, payload interface{}) {
+	spanCtx, span := uc.tracer.Start(ctx, "Record Audit Entry")
+	defer span.End()
+
+	entry := &entities.AuditEntry{
+		ID:       uuid.NewString(),
+		Action:   action,
+		Actor:    actor,
+		ClientIP: clientIP,
+		At:       time.Now().UTC(),
+	}
+	if payload != nil {
+		encoded, err := json.Marshal(payload)
+		if err != nil {
+			uc.logger.With(spanCtx).Err(err).Field("action", action).Warning("Failed to encode audit payload")
+		}
+		entry.Payload = encoded
+	}
+
+	// The log entry keeps the trail in Cloud Logging too, the memory storage loses the stored one on restart
+	log := uc.logger.With(spanCtx).Field("action", action).Field("actor", actor).Field("clientIp", clientIP).Field("payload", string(entry.Payload))
+	if err := uc.entries.Append(spanCtx, entry); err != nil {
+		log.Err(err).Error("Failed to append audit entry")
+		return
+	}
+	log.Field("auditId", entry.ID).Info("Admin action audited")
+}
+
+// List returns at most limit entries of the action, of every action when it's empty, newest first
+func (uc *AuditLogUseCase) List(ctx context.Context, action entities.AuditAction, limit int) ([]*entities.AuditEntry, error) {
+	spanCtx, span := uc.tracer.Start(ctx, "List Audit Entries")
+	defer span.End()
+
+	entries, err := uc.entries.List(spanCtx, action, limit)
+	if err != nil {
+		uc.logger.With(spanCtx).Err(err).Error("Failed to list audit entries")
+		return nil, err
+	}
+
+	return entries, nil
+}
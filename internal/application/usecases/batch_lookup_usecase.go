@@ -0,0 +1,106 @@
+package usecases
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+	"sync"
+)
+
+// BatchProgress is called after every processed word with the number of done and total words
+type BatchProgress func(done, total int)
+
+// BatchLookupUseCase resolves large local word lists with a bounded number of parallel lookups
+type BatchLookupUseCase struct {
+	lookup *DetermineArticleUseCase
+	logger logging.Logger
+	tracer tracing.Tracer
+}
+
+// NewBatchLookupUseCase creates a new batch lookup use case instance
+func NewBatchLookupUseCase(lookup *DetermineArticleUseCase, logger logging.Logger, tracer tracing.Tracer) *BatchLookupUseCase {
+	return &BatchLookupUseCase{
+		lookup: lookup,
+		logger: logger,
+		tracer: tracer,
+	}
+}
+
+// Execute looks up the words with at most concurrency parallel lookups, the rows and failures
+// keep the order of the list. A cancelled context stops the batch and returns the words done so far.
+func (uc *BatchLookupUseCase) Execute(
+	ctx context.Context,
+	words []string,
+	language string,
+	concurrency int,
+	progress BatchProgress,
+) (*entities.ImportResult, error) {
+	spanCtx, span := uc.tracer.Start(ctx, "Batch Lookup")
+	defer span.End()
+
+	concurrency = max(concurrency, 1)
+	uc.logger.With(spanCtx).Field("words", len(words)).Field("language", language).Field("concurrency", concurrency).Info("Processing word batch")
+
+	type outcome struct {
+		rows    []entities.ImportRow
+		failure *entities.ImportFailure
+		done    bool
+	}
+	outcomes := make([]outcome, len(words))
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		done int
+	)
+	indexes := make(chan int)
+	for range min(concurrency, len(words)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				rows, failure := lookupRows(spanCtx, uc.lookup, words[i], language)
+				outcomes[i] = outcome{rows: rows, failure: failure, done: true}
+
+				mu.Lock()
+				done++
+				if progress != nil {
+					progress(done, len(words))
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+feed:
+	for i := range words {
+		select {
+		case indexes <- i:
+		case <-spanCtx.Done():
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	result := &entities.ImportResult{
+		Language: language,
+		Rows:     make([]entities.ImportRow, 0, len(words)),
+		Failures: []entities.ImportFailure{},
+	}
+	for _, o := range outcomes {
+		if !o.done {
+			continue
+		}
+		if o.failure != nil {
+			result.Failures = append(result.Failures, *o.failure)
+			continue
+		}
+		result.Rows = append(result.Rows, o.rows...)
+	}
+
+	uc.logger.With(spanCtx).Field("done", done).Field("rows", len(result.Rows)).Field("failures", len(result.Failures)).Info("Word batch processed")
+
+	return result, spanCtx.Err()
+}
@@ -0,0 +1,110 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/services"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// BotWebhookPath is the path of the webhooks of the bots, followed by the bot ID
+const BotWebhookPath = "/telegram/"
+
+var (
+	// ErrBotNotFound is returned for IDs of no bot of the deployment
+	ErrBotNotFound = errors.New("bot not found")
+	// ErrBotsUnavailable is returned when no Telegram bot is configured
+	ErrBotsUnavailable = errors.New("managing webhooks requires the Telegram bot")
+	// ErrWebhookURLMissing is returned when setting webhooks without the public URL of the deployment
+	ErrWebhookURLMissing = errors.New("setting webhooks requires TELEGRAM_WEBHOOK_URL")
+)
+
+// BotWebhooksUseCase shows and sets the webhooks of the main bot and of the white-label bots, every bot
+// gets the updates at its own path so they are routed to the bot they were sent to
+type BotWebhooksUseCase struct {
+	bots       services.BotWebhooks
+	webhookURL string
+	logger     logging.Logger
+	tracer     tracing.Tracer
+}
+
+// NewBotWebhooksUseCase creates a new bot webhooks use case, the webhooks are set below the public URL
+// of the deployment
+func NewBotWebhooksUseCase(webhookURL string, logger logging.Logger, tracer tracing.Tracer) *BotWebhooksUseCase {
+	return &BotWebhooksUseCase{
+		webhookURL: strings.TrimSuffix(webhookURL, "/"),
+		logger:     logger,
+		tracer:     tracer,
+	}
+}
+
+// SetBots sets the bots whose webhooks are managed
+func (uc *BotWebhooksUseCase) SetBots(bots services.BotWebhooks) {
+	uc.bots = bots
+}
+
+// List returns the webhook state of every bot, the main bot first
+func (uc *BotWebhooksUseCase) List(ctx context.Context) ([]entities.BotWebhook, error) {
+	spanCtx, span := uc.tracer.Start(ctx, "List Bot Webhooks")
+	defer span.End()
+
+	if uc.bots == nil {
+		return nil, ErrBotsUnavailable
+	}
+
+	webhooks := make([]entities.BotWebhook, 0, len(uc.bots.BotIDs()))
+	for _, id := range uc.bots.BotIDs() {
+		webhook, err := uc.bots.Webhook(spanCtx, id)
+		if err != nil {
+			uc.logger.With(spanCtx).Err(err).Field("botId", id).Error("Failed to get bot webhook")
+			return nil, err
+		}
+		webhooks = append(webhooks, *webhook)
+	}
+
+	return webhooks, nil
+}
+
+// Set points the webhook of the bot, or of every bot for a zero ID, to its path of the deployment and
+// returns the new webhook states
+func (uc *BotWebhooksUseCase) Set(ctx context.Context, botID int64) ([]entities.BotWebhook, error) {
+	spanCtx, span := uc.tracer.Start(ctx, "Set Bot Webhooks")
+	defer span.End()
+
+	if uc.bots == nil {
+		return nil, ErrBotsUnavailable
+	}
+	if uc.webhookURL == "" {
+		return nil, ErrWebhookURLMissing
+	}
+	ids := uc.bots.BotIDs()
+	if botID != 0 {
+		if !slices.Contains(ids, botID) {
+			return nil, ErrBotNotFound
+		}
+		ids = []int64{botID}
+	}
+
+	webhooks := make([]entities.BotWebhook, 0, len(ids))
+	for _, id := range ids {
+		url := uc.webhookURL + BotWebhookPath + strconv.FormatInt(id, 10)
+		if err := uc.bots.SetWebhook(spanCtx, id, url); err != nil {
+			uc.logger.With(spanCtx).Err(err).Field("botId", id).Error("Failed to set bot webhook")
+			return nil, err
+		}
+		webhook, err := uc.bots.Webhook(spanCtx, id)
+		if err != nil {
+			uc.logger.With(spanCtx).Err(err).Field("botId", id).Error("Failed to get bot webhook")
+			return nil, err
+		}
+		webhooks = append(webhooks, *webhook)
+		uc.logger.With(spanCtx).Field("botId", id).Field("url", url).Info("Bot webhook set")
+	}
+
+	return webhooks, nil
+}
@@ -0,0 +1,120 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/repositories"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/services"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+	"strings"
+	"unicode/utf8"
+)
+
+// JobTypeBroadcast is the background job sending an announcement to the known chats
+const JobTypeBroadcast entities.JobType = "telegram.broadcast"
+
+// maxBroadcastLength is the Telegram limit of a message text
+const maxBroadcastLength = 4096
+
+// ErrInvalidBroadcast is returned for announcements without a text or with texts over the Telegram limit
+var ErrInvalidBroadcast = fmt.Errorf("the text is required and the texts must be at most %d characters", maxBroadcastLength)
+
+// BroadcastUseCase keeps the chats the bot has talked in and sends announcements to them in a background job
+type BroadcastUseCase struct {
+	chats  repositories.ChatRepository
+	jobs   services.JobQueue
+	logger logging.Logger
+	tracer tracing.Tracer
+}
+
+// NewBroadcastUseCase creates a new broadcast use case instance
+func NewBroadcastUseCase(
+	chats repositories.ChatRepository,
+	jobs services.JobQueue,
+	logger logging.Logger,
+	tracer tracing.Tracer,
+) *BroadcastUseCase {
+	return &BroadcastUseCase{
+		chats:  chats,
+		jobs:   jobs,
+		logger: logger,
+		tracer: tracer,
+	}
+}
+
+// Remember makes the chat a recipient of the announcements, failures are logged since the update goes on
+func (uc *BroadcastUseCase) Remember(ctx context.Context, chat *entities.KnownChat) {
+	if err := uc.chats.Touch(ctx, chat); err != nil {
+		uc.logger.With(ctx).Err(err).Field("chatId", chat.ID).Warning("Failed to remember chat")
+	}
+}
+
+// SetOptOut stops or resumes the announcements of the chat, false if the chat is unknown
+func (uc *BroadcastUseCase) SetOptOut(ctx context.Context, chatID int64, optedOut bool) (bool, error) {
+	spanCtx, span := uc.tracer.Start(ctx, "Set Announcements Opt-Out")
+	defer span.End()
+
+	known, err := uc.chats.SetOptOut(spanCtx, chatID, optedOut)
+	if err != nil {
+		uc.logger.With(spanCtx).Err(err).Field("chatId", chatID).Error("Failed to save announcements opt-out")
+		return false, err
+	}
+
+	return known, nil
+}
+
+// Forget removes the chat from the recipients, for chats the bot can no longer write to
+func (uc *BroadcastUseCase) Forget(ctx context.Context, chatID int64) error {
+	if err := uc.chats.Delete(ctx, chatID); err != nil {
+		uc.logger.With(ctx).Err(err).Field("chatId", chatID).Error("Failed to forget chat")
+		return err
+	}
+
+	return nil
+}
+
+// Recipients returns the known chats that didn't opt out, ordered by ID
+func (uc *BroadcastUseCase) Recipients(ctx context.Context) ([]entities.KnownChat, error) {
+	chats, err := uc.chats.List(ctx)
+	if err != nil {
+		uc.logger.With(ctx).Err(err).Error("Failed to list chats")
+		return nil, err
+	}
+
+	recipients := chats[:0]
+	for _, chat := range chats {
+		if !chat.OptedOut {
+			recipients = append(recipients, chat)
+		}
+	}
+
+	return recipients, nil
+}
+
+// Submit validates the announcement and enqueues its broadcast
+func (uc *BroadcastUseCase) Submit(ctx context.Context, broadcast entities.Broadcast) (*entities.Job, error) {
+	spanCtx, span := uc.tracer.Start(ctx, "Submit Broadcast")
+	defer span.End()
+
+	if strings.TrimSpace(broadcast.Text) == "" || utf8.RuneCountInString(broadcast.Text) > maxBroadcastLength {
+		return nil, ErrInvalidBroadcast
+	}
+	for _, text := range broadcast.Translations {
+		if utf8.RuneCountInString(text) > maxBroadcastLength {
+			return nil, ErrInvalidBroadcast
+		}
+	}
+
+	job, err := NewJob(spanCtx, JobTypeBroadcast, broadcast)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.jobs.Enqueue(spanCtx, job); err != nil {
+		uc.logger.With(spanCtx).Err(err).Error("Failed to enqueue broadcast")
+		return nil, err
+	}
+
+	return job, nil
+}
@@ -0,0 +1,117 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/repositories"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/services"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BudgetGuard reports whether the monthly AI spend cap is hit, lookups are then answered from
+// the cache and the dictionary only
+type BudgetGuard struct {
+	stats    repositories.StatsRepository
+	spendCap float64
+	logger   logging.Logger
+	notifier services.AdminNotifier
+
+	mu       sync.Mutex
+	notified string
+}
+
+// NewBudgetGuard creates a new budget guard, a zero cap disables it
+func NewBudgetGuard(stats repositories.StatsRepository, spendCap float64, logger logging.Logger) *BudgetGuard {
+	return &BudgetGuard{
+		stats:    stats,
+		spendCap: spendCap,
+		logger:   logger,
+	}
+}
+
+// SetNotifier sets the notifier alerted once a month when the cap is hit
+func (g *BudgetGuard) SetNotifier(notifier services.AdminNotifier) {
+	g.notifier = notifier
+}
+
+// Exceeded reports whether the spend of the month reached the cap, the spend is read from the
+// stats and errors keep the AI enabled
+func (g *BudgetGuard) Exceeded(ctx context.Context) bool {
+	if g == nil || g.spendCap <= 0 {
+		return false
+	}
+
+	spent, err := g.stats.MonthlySpend(ctx)
+	if err != nil {
+		g.logger.With(ctx).Err(err).Warning("Failed to read the monthly AI spend")
+		return false
+	}
+	if spent < g.spendCap {
+		return false
+	}
+
+	g.notify(ctx, spent)
+	return true
+}
+
+// Apply completes the spend of the dashboard snapshot with the cap
+func (g *BudgetGuard) Apply(spend *entities.SpendStats) {
+	if g == nil || g.spendCap <= 0 {
+		return
+	}
+
+	spend.Cap = g.spendCap
+	spend.Remaining = max(g.spendCap-spend.Spent, 0)
+	spend.Exceeded = spend.Spent >= g.spendCap
+}
+
+// notify alerts the admin the first time the cap is hit in a month
+func (g *BudgetGuard) notify(ctx context.Context, spent float64) {
+	month := time.Now().UTC().Format("2006-01")
+
+	g.mu.Lock()
+	if g.notified == month {
+		g.mu.Unlock()
+		return
+	}
+	g.notified = month
+	g.mu.Unlock()
+
+	g.logger.With(ctx).Field("spent", spent).Field("cap", g.spendCap).Critical("Monthly AI spend cap is hit, lookups are answered from the cache and the dictionary")
+	if g.notifier == nil {
+		return
+	}
+
+	message := fmt.Sprintf("⚠️ The AI spend of %s reached $%.2f of the $%.2f cap. Lookups are answered from the cache and the dictionary until the next month.", month, spent, g.spendCap)
+	if err := g.notifier.NotifyAdmin(ctx, message); err != nil {
+		g.logger.With(ctx).Err(err).Error("Failed to notify the admin about the spend cap")
+	}
+}
+
+// dictionaryAnswer answers the word with its dictionary article only, used while the AI can't be. The
+// notice explains the missing examples, words missing from the dictionary get the explanation as the error.
+func dictionaryAnswer(ctx context.Context, dictionary services.DictionaryService, request *entities.ArticleRequest, degradation entities.Degradation) *entities.ArticleResponse {
+	article, found, err := dictionary.LookupArticle(ctx, request.Word)
+	if err != nil || !found {
+		response := entities.NewErrorResponse(degradation.Unavailable(request.Language))
+		response.Degraded = degradation
+		return response
+	}
+
+	// Answers of a spent budget hold until the next month, the others are partial, so the next request asks the AI again
+	word := strings.TrimSpace(request.Word)
+	response := &entities.ArticleResponse{
+		Success:  true,
+		Data:     []entities.ArticleInfo{{WordWithArticle: article + " " + word}},
+		Partial:  degradation != entities.DegradationBudgetExceeded,
+		Degraded: degradation,
+		Notice:   degradation.Notice(request.Language),
+	}
+	response.Stamp(entities.SourceDictionary, time.Now())
+
+	return response
+}
@@ -0,0 +1,72 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/repositories"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+)
+
+// ChatMembershipUseCase records the chats the bot joins and loses, and forgets what is stored for a chat
+// the bot can no longer write to
+type ChatMembershipUseCase struct {
+	preferences repositories.PreferencesRepository
+	leaderboard repositories.LeaderboardRepository
+	chats       repositories.ChatRepository
+	stats       repositories.StatsRepository
+	logger      logging.Logger
+	tracer      tracing.Tracer
+}
+
+// NewChatMembershipUseCase creates a new chat membership use case instance
+func NewChatMembershipUseCase(
+	preferences repositories.PreferencesRepository,
+	leaderboard repositories.LeaderboardRepository,
+	chats repositories.ChatRepository,
+	stats repositories.StatsRepository,
+	logger logging.Logger,
+	tracer tracing.Tracer,
+) *ChatMembershipUseCase {
+	return &ChatMembershipUseCase{
+		preferences: preferences,
+		leaderboard: leaderboard,
+		chats:       chats,
+		stats:       stats,
+		logger:      logger,
+		tracer:      tracer,
+	}
+}
+
+// Execute counts the event of the chat in the churn statistics. A user blocking the bot loses the
+// preferences and the leaderboard membership, so the weekly summaries stop; a group removing the bot
+// loses its leaderboard scores. Both stop getting announcements.
+func (uc *ChatMembershipUseCase) Execute(ctx context.Context, chatID int64, event entities.ChatMemberEvent) error {
+	spanCtx, span := uc.tracer.Start(ctx, "Chat Membership Event")
+	defer span.End()
+
+	uc.stats.RecordChatMemberEvent(spanCtx, event)
+	uc.logger.With(spanCtx).Field("chatId", chatID).Field("event", event).Info("Chat membership of the bot changed")
+
+	switch event {
+	case entities.ChatMemberBlocked:
+		// The chat ID of a private chat is the user ID
+		err := errors.Join(
+			uc.preferences.Delete(spanCtx, chatID),
+			uc.leaderboard.DeleteMember(spanCtx, chatID),
+			uc.chats.Delete(spanCtx, chatID),
+		)
+		if err != nil {
+			uc.logger.With(spanCtx).Err(err).Field("chatId", chatID).Error("Failed to clean up the data of a user who blocked the bot")
+			return err
+		}
+	case entities.ChatMemberRemovedFromGroup:
+		if err := errors.Join(uc.leaderboard.DeleteChat(spanCtx, chatID), uc.chats.Delete(spanCtx, chatID)); err != nil {
+			uc.logger.With(spanCtx).Err(err).Field("chatId", chatID).Error("Failed to clean up the data of a group that removed the bot")
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,31 @@
+package usecases
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/repositories"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+)
+
+// curatedEntries reads the nouns verified by the operators, their answers win over the AI answers.
+// A nil repository disables them.
+type curatedEntries struct {
+	entries repositories.DictionaryRepository
+	logger  logging.Logger
+}
+
+// get returns the curated entry of the word, nil for other words. A failed read is logged and the
+// lookup continues without the entry.
+func (c curatedEntries) get(ctx context.Context, word string) *entities.CuratedEntry {
+	if c.entries == nil {
+		return nil
+	}
+
+	entry, err := c.entries.Get(ctx, word)
+	if err != nil {
+		c.logger.With(ctx).Err(err).Field("word", word).Warning("Failed to read curated dictionary entry")
+		return nil
+	}
+
+	return entry
+}
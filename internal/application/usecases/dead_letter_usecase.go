@@ -0,0 +1,148 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/repositories"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/services"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+	"github.com/google/uuid"
+	"time"
+)
+
+// maxReplayedLetters limits the dead letters replayed by one request
+const maxReplayedLetters = 100
+
+var (
+	// ErrDeadLetterNotFound is returned when replaying an unknown or already replayed dead letter
+	ErrDeadLetterNotFound = errors.New("dead letter not found")
+	// ErrReplayUnavailable is returned when no bot is configured to replay the updates
+	ErrReplayUnavailable = errors.New("replaying updates requires the Telegram bot")
+)
+
+// DeadLetterUseCase keeps the Telegram updates whose processing failed and replays them after a fix
+type DeadLetterUseCase struct {
+	letters  repositories.DeadLetterRepository
+	replayer services.UpdateReplayer
+	logger   logging.Logger
+	tracer   tracing.Tracer
+}
+
+// NewDeadLetterUseCase creates a new dead letter use case instance
+func NewDeadLetterUseCase(
+	letters repositories.DeadLetterRepository,
+	logger logging.Logger,
+	tracer tracing.Tracer,
+) *DeadLetterUseCase {
+	return &DeadLetterUseCase{
+		letters: letters,
+		logger:  logger,
+		tracer:  tracer,
+	}
+}
+
+// SetReplayer sets the bot processing the replayed updates
+func (uc *DeadLetterUseCase) SetReplayer(replayer services.UpdateReplayer) {
+	uc.replayer = replayer
+}
+
+// Record keeps the raw update of the user with the error of its processing
+func (uc *DeadLetterUseCase) Record(ctx context.Context, update []byte, updateID int, userID int64, cause error) {
+	spanCtx, span := uc.tracer.Start(ctx, "Record Dead Letter")
+	defer span.End()
+
+	letter := &entities.DeadLetter{
+		ID:       uuid.NewString(),
+		UpdateID: updateID,
+		Update:   update,
+		UserID:   userID,
+		Error:    cause.Error(),
+		FailedAt: time.Now().UTC(),
+	}
+	if err := uc.letters.Save(spanCtx, letter); err != nil {
+		uc.logger.With(spanCtx).Err(err).Field("updateId", updateID).Error("Failed to store dead letter, the update is lost")
+		return
+	}
+
+	uc.logger.With(spanCtx).Err(cause).Field("updateId", updateID).Field("deadLetter", letter.ID).Warning("Telegram update dead-lettered")
+}
+
+// List returns at most limit dead letters, oldest first
+func (uc *DeadLetterUseCase) List(ctx context.Context, limit int) ([]*entities.DeadLetter, error) {
+	spanCtx, span := uc.tracer.Start(ctx, "List Dead Letters")
+	defer span.End()
+
+	letters, err := uc.letters.List(spanCtx, limit)
+	if err != nil {
+		uc.logger.With(spanCtx).Err(err).Error("Failed to list dead letters")
+		return nil, err
+	}
+
+	return letters, nil
+}
+
+// Replay processes the dead letter with the ID again, or the oldest ones without an ID. Replayed letters
+// are removed, the failing ones are kept with the new error.
+func (uc *DeadLetterUseCase) Replay(ctx context.Context, id string) (*entities.DeadLetterReplay, error) {
+	spanCtx, span := uc.tracer.Start(ctx, "Replay Dead Letters")
+	defer span.End()
+
+	if uc.replayer == nil {
+		return nil, ErrReplayUnavailable
+	}
+
+	var letters []*entities.DeadLetter
+	if id == "" {
+		var err error
+		if letters, err = uc.letters.List(spanCtx, maxReplayedLetters); err != nil {
+			uc.logger.With(spanCtx).Err(err).Error("Failed to list dead letters")
+			return nil, err
+		}
+	} else {
+		letter, ok, err := uc.letters.Get(spanCtx, id)
+		if err != nil {
+			uc.logger.With(spanCtx).Err(err).Field("deadLetter", id).Error("Failed to read dead letter")
+			return nil, err
+		}
+		if !ok {
+			return nil, ErrDeadLetterNotFound
+		}
+		letters = append(letters, letter)
+	}
+
+	replay := &entities.DeadLetterReplay{}
+	for _, letter := range letters {
+		if err := uc.replay(spanCtx, letter); err != nil {
+			replay.Failed++
+			continue
+		}
+		replay.Replayed++
+	}
+
+	uc.logger.With(spanCtx).Field("replayed", replay.Replayed).Field("failed", replay.Failed).Info("Replayed dead letters")
+
+	return replay, nil
+}
+
+// replay processes the update of the letter, removing it on success and keeping the new error otherwise
+func (uc *DeadLetterUseCase) replay(ctx context.Context, letter *entities.DeadLetter) error {
+	if cause := uc.replayer.ReplayUpdate(ctx, letter.Update); cause != nil {
+		letter.Attempts++
+		letter.Error = cause.Error()
+		if err := uc.letters.Save(ctx, letter); err != nil {
+			uc.logger.With(ctx).Err(err).Field("deadLetter", letter.ID).Error("Failed to update dead letter")
+		}
+		uc.logger.With(ctx).Err(cause).Field("deadLetter", letter.ID).Field("attempts", letter.Attempts).Warning("Replayed update failed again")
+		return cause
+	}
+
+	if err := uc.letters.Delete(ctx, letter.ID); err != nil {
+		uc.logger.With(ctx).Err(err).Field("deadLetter", letter.ID).Error("Failed to remove replayed dead letter")
+		return fmt.Errorf("failed to remove replayed dead letter: %w", err)
+	}
+
+	return nil
+}
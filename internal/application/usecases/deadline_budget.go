@@ -0,0 +1,76 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrDeadlineExceeded is returned when the AI stage of a lookup ran out of its share of the request
+// deadline and the dictionary has no article to answer with instead
+var ErrDeadlineExceeded = errors.New("deadline budget of the lookup exceeded")
+
+// DeadlineBudget splits the deadline of the inbound request across the stages of a lookup: the cache
+// and the dictionary get short fixed shares and the AI gets the rest, minus the reserve formatting and
+// sending the answer. Requests without a deadline only limit the cache and the dictionary.
+type DeadlineBudget struct {
+	cache      time.Duration
+	dictionary time.Duration
+	reserve    time.Duration
+}
+
+// NewDeadlineBudget creates a new deadline budget, a zero share doesn't limit its stage
+func NewDeadlineBudget(cache, dictionary, reserve time.Duration) *DeadlineBudget {
+	return &DeadlineBudget{
+		cache:      cache,
+		dictionary: dictionary,
+		reserve:    reserve,
+	}
+}
+
+// Cache returns the context of the cache stage
+func (b *DeadlineBudget) Cache(ctx context.Context) (context.Context, context.CancelFunc) {
+	if b == nil {
+		return context.WithCancel(ctx)
+	}
+
+	return withShare(ctx, b.cache)
+}
+
+// Dictionary returns the context of the dictionary and frequency list stage
+func (b *DeadlineBudget) Dictionary(ctx context.Context) (context.Context, context.CancelFunc) {
+	if b == nil {
+		return context.WithCancel(ctx)
+	}
+
+	return withShare(ctx, b.dictionary)
+}
+
+// AI returns the context of the AI stage ending the reserve before the request deadline, ok is false
+// when the remaining time doesn't even cover the reserve
+func (b *DeadlineBudget) AI(ctx context.Context) (aiCtx context.Context, cancel context.CancelFunc, ok bool) {
+	deadline, limited := ctx.Deadline()
+	if b == nil || !limited {
+		aiCtx, cancel = context.WithCancel(ctx)
+		return aiCtx, cancel, true
+	}
+
+	aiDeadline := deadline.Add(-b.reserve)
+	aiCtx, cancel = context.WithDeadline(ctx, aiDeadline)
+	return aiCtx, cancel, time.Until(aiDeadline) > 0
+}
+
+// stageExhausted reports whether the stage context ran out of its share while the request is still
+// alive, so the lookup may still answer with what it has
+func stageExhausted(ctx, stageCtx context.Context) bool {
+	return errors.Is(stageCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil
+}
+
+// withShare limits the context to the share, the earlier request deadline stays in place
+func withShare(ctx context.Context, share time.Duration) (context.Context, context.CancelFunc) {
+	if share <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, share)
+}
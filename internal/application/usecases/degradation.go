@@ -0,0 +1,145 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/services"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"sync"
+	"time"
+)
+
+// ErrAIUnavailable is returned when the AI is down and the dictionary has no article to answer with instead
+var ErrAIUnavailable = errors.New("the AI is temporarily unavailable")
+
+// aiOutage returns the error of an AI call as far as it tells about the health of the AI. Answers that were
+// blocked, cut off or stopped for recitation are failures of the word, not of the AI, so they are nil.
+func aiOutage(err error) error {
+	if errors.Is(err, services.ErrAnswerBlocked) || errors.Is(err, services.ErrAnswerTruncated) || errors.Is(err, services.ErrAnswerRecitation) {
+		return nil
+	}
+
+	return err
+}
+
+// DegradationState is the state of a dependency of the lookups
+type DegradationState string
+
+const (
+	// DegradationHealthy lets every lookup use the dependency
+	DegradationHealthy DegradationState = "healthy"
+	// DegradationDown answers the lookups without the dependency until the cooldown passed
+	DegradationDown DegradationState = "down"
+	// DegradationProbing lets one lookup try the dependency, its outcome decides the next state
+	DegradationProbing DegradationState = "probing"
+)
+
+// Degradation is the state machine of a dependency of the lookups. Threshold consecutive failures take
+// it down, so lookups stop waiting for it and are answered in reduced mode. After the cooldown one lookup
+// probes it: a success makes it healthy again and a failure takes it down for another cooldown.
+type Degradation struct {
+	name      string
+	threshold int
+	cooldown  time.Duration
+	logger    logging.Logger
+
+	mu       sync.Mutex
+	state    DegradationState
+	failures int
+	since    time.Time
+}
+
+// NewDegradation creates a new state machine of the named dependency, a zero threshold never takes it down
+func NewDegradation(name string, threshold int, cooldown time.Duration, logger logging.Logger) *Degradation {
+	return &Degradation{
+		name:      name,
+		threshold: threshold,
+		cooldown:  cooldown,
+		logger:    logger,
+		state:     DegradationHealthy,
+	}
+}
+
+// Allow reports whether the lookup may use the dependency, a down dependency past its cooldown is
+// probed by the first lookup asking
+func (d *Degradation) Allow(ctx context.Context) bool {
+	if d == nil {
+		return true
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	switch d.state {
+	case DegradationDown:
+		if time.Since(d.since) < d.cooldown {
+			return false
+		}
+		d.transition(ctx, DegradationProbing)
+		return true
+	case DegradationProbing:
+		// Other lookups wait for the outcome of the probe
+		return false
+	default:
+		return true
+	}
+}
+
+// Record moves the state machine with the outcome of a call to the dependency. Calls canceled by
+// their own request say nothing about the dependency, a canceled probe is repeated after the cooldown.
+func (d *Degradation) Record(ctx context.Context, err error) {
+	if d == nil {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err != nil && ctx.Err() != nil {
+		if d.state == DegradationProbing {
+			d.transition(ctx, DegradationDown)
+		}
+		return
+	}
+
+	if err == nil {
+		d.failures = 0
+		if d.state != DegradationHealthy {
+			d.transition(ctx, DegradationHealthy)
+		}
+		return
+	}
+
+	d.failures++
+	if d.state == DegradationProbing || (d.threshold > 0 && d.failures >= d.threshold && d.state == DegradationHealthy) {
+		d.transition(ctx, DegradationDown)
+	}
+}
+
+// State returns the current state of the dependency
+func (d *Degradation) State() DegradationState {
+	if d == nil {
+		return DegradationHealthy
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.state
+}
+
+// transition moves to the state, the caller holds the lock
+func (d *Degradation) transition(ctx context.Context, state DegradationState) {
+	entry := d.logger.With(ctx).Field("dependency", d.name).Field("from", string(d.state)).Field("to", string(state))
+	switch state {
+	case DegradationDown:
+		entry.Field("failures", d.failures).Field("cooldown", d.cooldown.String()).Error("Dependency is down, lookups are answered in reduced mode")
+	case DegradationHealthy:
+		entry.Info("Dependency recovered")
+	default:
+		entry.Info("Probing dependency")
+	}
+
+	d.state = state
+	d.since = time.Now()
+}
@@ -0,0 +1,95 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/repositories"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+)
+
+// DeleteUserDataUseCase erases everything stored about a user on request of the user
+type DeleteUserDataUseCase struct {
+	preferences   repositories.PreferencesRepository
+	activity      repositories.ActivityRepository
+	feedback      repositories.FeedbackRepository
+	achievements  repositories.AchievementRepository
+	accounts      repositories.AccountRepository
+	leaderboard   repositories.LeaderboardRepository
+	chats         repositories.ChatRepository
+	conversations repositories.ConversationRepository
+	reminders     repositories.ReminderRepository
+	deadLetters   repositories.DeadLetterRepository
+	jobs          repositories.JobRepository
+	stats         repositories.StatsRepository
+	logger        logging.Logger
+	tracer        tracing.Tracer
+}
+
+// NewDeleteUserDataUseCase creates a new user data deletion use case instance
+func NewDeleteUserDataUseCase(
+	preferences repositories.PreferencesRepository,
+	activity repositories.ActivityRepository,
+	feedback repositories.FeedbackRepository,
+	achievements repositories.AchievementRepository,
+	accounts repositories.AccountRepository,
+	leaderboard repositories.LeaderboardRepository,
+	chats repositories.ChatRepository,
+	conversations repositories.ConversationRepository,
+	reminders repositories.ReminderRepository,
+	deadLetters repositories.DeadLetterRepository,
+	jobs repositories.JobRepository,
+	stats repositories.StatsRepository,
+	logger logging.Logger,
+	tracer tracing.Tracer,
+) *DeleteUserDataUseCase {
+	return &DeleteUserDataUseCase{
+		preferences:   preferences,
+		activity:      activity,
+		feedback:      feedback,
+		achievements:  achievements,
+		accounts:      accounts,
+		leaderboard:   leaderboard,
+		chats:         chats,
+		conversations: conversations,
+		reminders:     reminders,
+		deadLetters:   deadLetters,
+		jobs:          jobs,
+		stats:         stats,
+		logger:        logger,
+		tracer:        tracer,
+	}
+}
+
+// Execute deletes the preferences, the learning history, the ratings, the achievements, the leaderboard
+// scores, the conversation, the practice reminder, the failed updates kept for replaying, the linked
+// accounts, the data exports and the activity statistics of the user. The private chat with the user is
+// forgotten too, its ID is the user ID and so is the ID of its conversation. Every repository is cleaned
+// even if another one fails, so a retry finishes the deletion.
+func (uc *DeleteUserDataUseCase) Execute(ctx context.Context, userID int64) error {
+	spanCtx, span := uc.tracer.Start(ctx, "Delete User Data")
+	defer span.End()
+
+	err := errors.Join(
+		uc.preferences.Delete(spanCtx, userID),
+		uc.activity.Delete(spanCtx, userID),
+		uc.feedback.DeleteUser(spanCtx, userID),
+		uc.achievements.Delete(spanCtx, userID),
+		uc.accounts.DeleteUser(spanCtx, userID),
+		uc.leaderboard.DeleteMember(spanCtx, userID),
+		uc.chats.Delete(spanCtx, userID),
+		uc.conversations.Delete(spanCtx, userID),
+		uc.reminders.Delete(spanCtx, userID),
+		uc.deadLetters.DeleteUser(spanCtx, userID),
+		uc.jobs.DeleteUser(spanCtx, userID),
+		uc.stats.DeleteUser(spanCtx, userID),
+	)
+	if err != nil {
+		uc.logger.With(spanCtx).Err(err).Field("userId", userID).Error("Failed to delete the data of a user")
+		return err
+	}
+
+	uc.logger.With(spanCtx).Field("userId", userID).Info("Deleted the data of a user on request")
+
+	return nil
+}
@@ -2,58 +2,292 @@ package usecases
 
 import (
 	"context"
+	"fmt"
 	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/repositories"
 	"github.com/DeryabinSergey/germanarticlebot/internal/domain/services"
 	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
 	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"time"
 )
 
 // DetermineArticleUseCase handles the business logic for determining German articles
 type DetermineArticleUseCase struct {
-	aiService services.AIService
-	logger    logging.Logger
-	tracer    tracing.Tracer
+	aiService  services.AIService
+	annotator  responseAnnotator
+	cache      repositories.CacheRepository
+	cacheTTL   time.Duration
+	negative   negativeCache
+	curated    curatedEntries
+	moderation exampleModeration
+	stats      repositories.StatsRepository
+	activity   repositories.ActivityRepository
+	onEvent    LearningEventHandler
+	timezones  *TimezoneUseCase
+	budget     *BudgetGuard
+	deadline   *DeadlineBudget
+	ai         *Degradation
+	logger     logging.Logger
+	tracer     tracing.Tracer
 }
 
 // NewDetermineArticleUseCase creates a new use case instance
 func NewDetermineArticleUseCase(
 	aiService services.AIService,
+	frequency services.FrequencyService,
+	dictionary services.DictionaryService,
+	cache repositories.CacheRepository,
+	cacheTTL time.Duration,
+	stats repositories.StatsRepository,
+	budget *BudgetGuard,
 	logger logging.Logger,
 	tracer tracing.Tracer,
 ) *DetermineArticleUseCase {
 	return &DetermineArticleUseCase{
-		aiService: aiService,
-		logger:    logger,
-		tracer:    tracer,
+		aiService:  aiService,
+		annotator:  responseAnnotator{frequency: frequency, dictionary: dictionary, logger: logger},
+		cache:      cache,
+		cacheTTL:   cacheTTL,
+		negative:   negativeCache{cache: cache, logger: logger},
+		curated:    curatedEntries{logger: logger},
+		moderation: exampleModeration{logger: logger},
+		stats:      stats,
+		budget:     budget,
+		logger:     logger,
+		tracer:     tracer,
 	}
 }
 
+// SetNegativeCacheTTL keeps answers rejecting a word and AI failures for ttl, zero disables it
+func (uc *DetermineArticleUseCase) SetNegativeCacheTTL(ttl time.Duration) {
+	uc.negative.ttl = ttl
+}
+
+// SetCuratedEntries answers the nouns verified by the operators from their entries, nil disables them
+func (uc *DetermineArticleUseCase) SetCuratedEntries(entries repositories.DictionaryRepository) {
+	uc.curated.entries = entries
+}
+
+// SetDeadlineBudget splits the request deadline across the stages of the lookups, nil leaves them unlimited
+func (uc *DetermineArticleUseCase) SetDeadlineBudget(deadline *DeadlineBudget) {
+	uc.deadline = deadline
+}
+
+// SetModerator screens the generated examples, regenerating or dropping objectionable ones, nil disables it
+func (uc *DetermineArticleUseCase) SetModerator(moderator services.ContentModerator) {
+	uc.moderation.moderator = moderator
+}
+
+// SetDegradation answers from the dictionary while the state machine reports the AI down, nil disables it
+func (uc *DetermineArticleUseCase) SetDegradation(ai *Degradation) {
+	uc.ai = ai
+}
+
+// SetActivity records the successful lookups of users into the learning activity, nil disables it
+func (uc *DetermineArticleUseCase) SetActivity(activity repositories.ActivityRepository) {
+	uc.activity = activity
+}
+
+// SetEventHandler publishes the recorded lookups of users to the handler
+func (uc *DetermineArticleUseCase) SetEventHandler(handler LearningEventHandler) {
+	uc.onEvent = handler
+}
+
+// SetTimezones records the lookups of users on the days of their time zones, nil records UTC days
+func (uc *DetermineArticleUseCase) SetTimezones(timezones *TimezoneUseCase) {
+	uc.timezones = timezones
+}
+
 // Execute processes the article determination request
-func (uc *DetermineArticleUseCase) Execute(ctx context.Context, request *entities.ArticleRequest) (*entities.ArticleResponse, error) {
+func (uc *DetermineArticleUseCase) Execute(ctx context.Context, request *entities.ArticleRequest) (response *entities.ArticleResponse, err error) {
 	spanCtx, span := uc.tracer.Start(ctx, "Process Article Request")
 	defer span.End()
+	request = uc.annotator.spelling(spanCtx, tenantRequest(spanCtx, request))
+	defer func() {
+		if err == nil && response != nil && response.Success {
+			uc.recordActivity(spanCtx, request.Word, response)
+		}
+	}()
 
 	// Validate request
 	if !request.IsValid() {
-		uc.logger.Warning(spanCtx, map[string]interface{}{
-			"message":  "Invalid article request",
-			"word":     request.Word,
-			"language": request.Language,
-		})
+		uc.logger.With(spanCtx).Field("word", request.Word).Field("language", request.Language).Warning("Invalid article request")
 		return entities.NewErrorResponse("Word cannot be empty"), nil
 	}
 
-	uc.logger.Info(spanCtx, map[string]interface{}{
-		"message":  "Processing article request",
-		"word":     request.Word,
-		"language": request.Language,
-	})
+	// Input that can't be a German word is rejected before it reaches the cache and the AI
+	if problem := entities.CheckWord(request.Word); problem != "" {
+		uc.logger.With(spanCtx).Field("word", request.Word).Field("problem", problem).Sampled().Info("Rejected article request")
+		return entities.NewErrorResponse(problem.Explanation(request.Language)), nil
+	}
+
+	// Every lookup passes here, so the entry is sampled
+	logRequest(spanCtx, uc.logger).Field("word", request.Word).Field("language", request.Language).Sampled().Info("Processing article request")
+	uc.stats.RecordLookup(spanCtx, request.Word, request.Language, requestAdapter(spanCtx))
+
+	cacheCtx, cancelCache := uc.deadline.Cache(spanCtx)
+	cached, ok := uc.cached(cacheCtx, request)
+	cancelCache()
+	uc.stats.RecordCacheLookup(spanCtx, ok)
+	span.SetAttributes(attribute.Bool("cache.hit", ok))
+	if ok {
+		return cached, nil
+	}
+
+	// Curated nouns are answered without the AI unless the examples are asked for
+	curated := uc.curated.get(spanCtx, request.Word)
+	if response, ok := curated.Answer(request); ok {
+		span.SetAttributes(attribute.Bool("curated", true))
+		uc.annotator.annotate(spanCtx, response)
+		if err := uc.cache.Set(spanCtx, request.CacheKey(), response, uc.cacheTTL); err != nil {
+			uc.logger.With(spanCtx).Err(err).Warning("Failed to write article cache")
+		}
+		return response, nil
+	}
+
+	// Uncached words get the dictionary article only while the AI budget is spent
+	if uc.budget.Exceeded(spanCtx) {
+		span.SetAttributes(attribute.Bool("budget.exceeded", true))
+		return uc.degradedAnswer(spanCtx, request, entities.DegradationBudgetExceeded, nil)
+	}
+
+	if uc.negative.failedRecently(spanCtx, request) {
+		span.SetAttributes(attribute.Bool("cache.negative", true))
+		return uc.degradedAnswer(spanCtx, request, entities.DegradationAIUnavailable, fmt.Errorf("%w: %w", ErrAIUnavailable, ErrRecentlyFailed))
+	}
+
+	// Call AI service to determine article within what is left of the request deadline
+	aiCtx, cancelAI, ok := uc.deadline.AI(spanCtx)
+	defer cancelAI()
+	if !ok {
+		span.SetAttributes(attribute.Bool("deadline.exceeded", true))
+		return uc.degradedAnswer(spanCtx, request, entities.DegradationDeadlineExceeded, ErrDeadlineExceeded)
+	}
 
-	// Call AI service to determine article
-	response, err := uc.aiService.GenerateArticleInfo(spanCtx, request)
+	// While the AI is down uncached words are answered from the dictionary without waiting for it
+	if !uc.ai.Allow(spanCtx) {
+		span.SetAttributes(attribute.Bool("ai.degraded", true))
+		return uc.degradedAnswer(spanCtx, request, entities.DegradationAIUnavailable, ErrAIUnavailable)
+	}
+	response, err = uc.aiService.GenerateArticleInfo(aiCtx, request)
+	uc.stats.RecordAICall(spanCtx, err != nil)
+	uc.ai.Record(spanCtx, aiOutage(err))
+	if err != nil && stageExhausted(spanCtx, aiCtx) {
+		span.SetAttributes(attribute.Bool("deadline.exceeded", true))
+		uc.logger.With(spanCtx).Err(err).Field("word", request.Word).Warning("AI ran out of the deadline budget")
+		return uc.degradedAnswer(spanCtx, request, entities.DegradationDeadlineExceeded, ErrDeadlineExceeded)
+	}
 	if err != nil {
-		return entities.NewErrorResponse("Failed to process request"), err
+		uc.negative.rememberFailure(spanCtx, request)
+		return uc.degradedAnswer(spanCtx, request, entities.DegradationAIUnavailable, fmt.Errorf("%w: %w", ErrAIUnavailable, err))
+	}
+
+	uc.moderation.screen(aiCtx, response, func(ctx context.Context) (*entities.ArticleResponse, error) {
+		regenerated, err := uc.aiService.GenerateArticleInfo(ctx, request)
+		uc.stats.RecordAICall(spanCtx, err != nil)
+		return regenerated, err
+	})
+
+	// A curated noun the AI rejects is answered from its entry, the answers of the others are corrected by it
+	if !response.Success && curated != nil {
+		response = curated.Response(request.Language)
+	}
+	curated.Correct(response, request.Language)
+
+	// Complete successful answers are cached for the cache TTL and rejections for the negative TTL,
+	// partial answers are retried on the next request
+	dictionaryCtx, cancelDictionary := uc.deadline.Dictionary(spanCtx)
+	defer cancelDictionary()
+	if response.Success {
+		uc.annotator.annotate(dictionaryCtx, response)
+		if !response.Partial {
+			if err := uc.cache.Set(spanCtx, request.CacheKey(), response, uc.cacheTTL); err != nil {
+				uc.logger.With(spanCtx).Err(err).Warning("Failed to write article cache")
+			}
+		}
+	} else {
+		uc.annotator.suggest(dictionaryCtx, request, response)
+		uc.negative.rememberAnswer(spanCtx, request, response)
+	}
+
+	return response, nil
+}
+
+// degradedAnswer answers a lookup the AI can't answer with the dictionary article and the localized notice
+// of the degradation. Words missing from the dictionary get the localized explanation and the cause.
+func (uc *DetermineArticleUseCase) degradedAnswer(ctx context.Context, request *entities.ArticleRequest, degradation entities.Degradation, cause error) (*entities.ArticleResponse, error) {
+	dictionaryCtx, cancel := uc.deadline.Dictionary(ctx)
+	defer cancel()
+
+	response := dictionaryAnswer(dictionaryCtx, uc.annotator.dictionary, request, degradation)
+	if !response.Success {
+		return response, cause
 	}
 
 	return response, nil
 }
+
+// ExecuteExamples answers the examples stage of a lookup staged into a core request and the examples
+// generated on demand. The core answer is looked up first, usually from the cache, and its interpretations
+// are kept by the full answer, so the meanings chosen by the user stay in place.
+func (uc *DetermineArticleUseCase) ExecuteExamples(ctx context.Context, request *entities.ArticleRequest) (*entities.ArticleResponse, error) {
+	spanCtx, span := uc.tracer.Start(ctx, "Process Examples Request")
+	defer span.End()
+
+	core, err := uc.Execute(spanCtx, request.Core())
+	if err != nil || !core.Success || core.HasExamples() {
+		return core, err
+	}
+
+	return uc.Execute(spanCtx, request.Examples(core.Interpretations()))
+}
+
+// cached returns the cached answer of the request, a core request is answered by the cached full answer too
+func (uc *DetermineArticleUseCase) cached(ctx context.Context, request *entities.ArticleRequest) (*entities.ArticleResponse, bool) {
+	keys := []string{request.CacheKey()}
+	if request.ArticleOnly {
+		keys = append(keys, request.Examples(nil).CacheKey())
+	}
+
+	for _, key := range keys {
+		cached, ok, err := uc.cache.Get(ctx, key)
+		if err != nil {
+			uc.logger.With(ctx).Err(err).Warning("Failed to read article cache")
+			continue
+		}
+		if ok {
+			return cached.FromCache(), true
+		}
+	}
+
+	return nil, false
+}
+
+// recordActivity counts the looked up word with the article and the translation of its first noun for the
+// user of the request, anonymous requests aren't tracked
+func (uc *DetermineArticleUseCase) recordActivity(ctx context.Context, word string, response *entities.ArticleResponse) {
+	identity := IdentityFromContext(ctx)
+	if uc.activity == nil || identity == nil || identity.UserID == 0 {
+		return
+	}
+
+	now := time.Now().UTC()
+	if uc.timezones != nil {
+		now = uc.timezones.Now(ctx, identity.UserID)
+	}
+	lookup := entities.LookedUpWord{Word: word}
+	for _, info := range response.Data {
+		if article := info.Article(); article != "" {
+			lookup.Article, lookup.Translation = article, info.Translation
+			break
+		}
+	}
+	if err := uc.activity.RecordLookup(ctx, identity.UserID, lookup, now); err != nil {
+		uc.logger.With(ctx).Err(err).Warning("Failed to record lookup activity")
+		return
+	}
+	if uc.onEvent != nil {
+		uc.onEvent(ctx, entities.LearningEvent{Type: entities.LearningEventLookup, UserID: identity.UserID, Word: word, At: now})
+	}
+}
@@ -0,0 +1,79 @@
+package usecases
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/services"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"strings"
+)
+
+// exampleModeration screens the example sentences of the AI answers, which are read by learners including
+// minors. A nil moderator disables it.
+type exampleModeration struct {
+	moderator services.ContentModerator
+	logger    logging.Logger
+}
+
+// screen replaces objectionable examples with the examples of the same interpretation in a regenerated
+// answer. Examples still objectionable after that are dropped and the answer becomes partial, so it isn't
+// cached and the next lookup tries again. The interpretations are kept as they are, they may have been
+// streamed already.
+func (m exampleModeration) screen(
+	ctx context.Context,
+	response *entities.ArticleResponse,
+	regenerate func(ctx context.Context) (*entities.ArticleResponse, error),
+) {
+	flagged := m.flagged(ctx, response)
+	if len(flagged) == 0 {
+		return
+	}
+
+	replacements := make(map[string]entities.ExamplesInfo)
+	regenerated, err := regenerate(ctx)
+	if err != nil {
+		m.logger.With(ctx).Err(err).Warning("Failed to regenerate moderated examples")
+	} else {
+		rejected := m.flagged(ctx, regenerated)
+		for i, info := range regenerated.Data {
+			if _, ok := rejected[i]; !ok && info.Example != (entities.ExamplesInfo{}) {
+				replacements[strings.ToLower(info.WordWithArticle)] = info.Example
+			}
+		}
+	}
+
+	for i := range flagged {
+		examples, ok := replacements[strings.ToLower(response.Data[i].WordWithArticle)]
+		if !ok {
+			response.Partial = true
+		}
+		response.Data[i].Example = examples
+	}
+}
+
+// flagged returns the indexes of the interpretations with objectionable examples and their categories
+func (m exampleModeration) flagged(ctx context.Context, response *entities.ArticleResponse) map[int]entities.ContentCategory {
+	if m.moderator == nil || response == nil || !response.Success {
+		return nil
+	}
+
+	flagged := make(map[int]entities.ContentCategory)
+	for i, info := range response.Data {
+		// The examples are about the noun, so the noun and its translation can't be objectionable themselves
+		allowed := []string{info.Noun(), strings.TrimPrefix(info.Plural, "die "), info.Translation}
+		for _, sentence := range info.Example.Sentences() {
+			category, err := m.moderator.Screen(ctx, sentence, allowed)
+			if err != nil {
+				m.logger.With(ctx).Err(err).Field("word", info.WordWithArticle).Warning("Failed to screen example")
+				break
+			}
+			if category != "" {
+				m.logger.With(ctx).Field("word", info.WordWithArticle).Field("category", string(category)).Warning("Objectionable example generated")
+				flagged[i] = category
+				break
+			}
+		}
+	}
+
+	return flagged
+}
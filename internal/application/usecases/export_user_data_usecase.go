@@ -0,0 +1,187 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/repositories"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/services"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+	"time"
+)
+
+// JobTypeDataExport is the background job compiling the data archive of a user of the web API
+const JobTypeDataExport entities.JobType = "user.export"
+
+// ErrExportNotFound is returned for unknown and expired exports and for the exports of other users
+var ErrExportNotFound = errors.New("data export not found")
+
+// DataExport is the payload of the JobTypeDataExport job
+type DataExport struct {
+	UserID int64 `json:"userId"`
+}
+
+// ExportUserDataUseCase compiles everything stored about a user into a machine-readable archive
+type ExportUserDataUseCase struct {
+	preferences   repositories.PreferencesRepository
+	activity      repositories.ActivityRepository
+	feedback      repositories.FeedbackRepository
+	achievements  repositories.AchievementRepository
+	accounts      repositories.AccountRepository
+	leaderboard   repositories.LeaderboardRepository
+	chats         repositories.ChatRepository
+	conversations repositories.ConversationRepository
+	reminders     repositories.ReminderRepository
+	deadLetters   repositories.DeadLetterRepository
+	jobs          services.JobQueue
+	store         repositories.JobRepository
+	logger        logging.Logger
+	tracer        tracing.Tracer
+}
+
+// NewExportUserDataUseCase creates a new user data export use case instance, the archives of the jobs
+// are read back from the job states of the store
+func NewExportUserDataUseCase(
+	preferences repositories.PreferencesRepository,
+	activity repositories.ActivityRepository,
+	feedback repositories.FeedbackRepository,
+	achievements repositories.AchievementRepository,
+	accounts repositories.AccountRepository,
+	leaderboard repositories.LeaderboardRepository,
+	chats repositories.ChatRepository,
+	conversations repositories.ConversationRepository,
+	reminders repositories.ReminderRepository,
+	deadLetters repositories.DeadLetterRepository,
+	jobs services.JobQueue,
+	store repositories.JobRepository,
+	logger logging.Logger,
+	tracer tracing.Tracer,
+) *ExportUserDataUseCase {
+	return &ExportUserDataUseCase{
+		preferences:   preferences,
+		activity:      activity,
+		feedback:      feedback,
+		achievements:  achievements,
+		accounts:      accounts,
+		leaderboard:   leaderboard,
+		chats:         chats,
+		conversations: conversations,
+		reminders:     reminders,
+		deadLetters:   deadLetters,
+		jobs:          jobs,
+		store:         store,
+		logger:        logger,
+		tracer:        tracer,
+	}
+}
+
+// Execute compiles the archive of the user. The private chat with the user and its conversation are included,
+// their ID is the user ID.
+func (uc *ExportUserDataUseCase) Execute(ctx context.Context, userID int64) (*entities.UserDataExport, error) {
+	spanCtx, span := uc.tracer.Start(ctx, "Export User Data")
+	defer span.End()
+
+	export := &entities.UserDataExport{UserID: userID, ExportedAt: time.Now().UTC()}
+	var err error
+	if export.Preferences, err = uc.preferences.Get(spanCtx, userID); err != nil {
+		return nil, fmt.Errorf("failed to read preferences: %w", err)
+	}
+	if export.Activity, err = uc.activity.Activity(spanCtx, userID); err != nil {
+		return nil, fmt.Errorf("failed to read learning activity: %w", err)
+	}
+	if export.Feedback, err = uc.feedback.ListUser(spanCtx, userID); err != nil {
+		return nil, fmt.Errorf("failed to read feedback: %w", err)
+	}
+	if export.Achievements, err = uc.achievements.List(spanCtx, userID); err != nil {
+		return nil, fmt.Errorf("failed to read achievements: %w", err)
+	}
+	if export.Leaderboard, err = uc.leaderboard.Member(spanCtx, userID); err != nil {
+		return nil, fmt.Errorf("failed to read leaderboard membership: %w", err)
+	}
+	if export.Chat, _, err = uc.chats.Get(spanCtx, userID); err != nil {
+		return nil, fmt.Errorf("failed to read chat: %w", err)
+	}
+	if export.Conversation, _, err = uc.conversations.Get(spanCtx, userID); err != nil {
+		return nil, fmt.Errorf("failed to read conversation: %w", err)
+	}
+	if export.Reminder, err = uc.reminders.Get(spanCtx, userID); err != nil {
+		return nil, fmt.Errorf("failed to read reminder: %w", err)
+	}
+	if export.LinkedIdentities, err = uc.accounts.Identities(spanCtx, userID); err != nil {
+		return nil, fmt.Errorf("failed to read linked identities: %w", err)
+	}
+	if export.DeadLetters, err = uc.deadLetters.ListUser(spanCtx, userID); err != nil {
+		return nil, fmt.Errorf("failed to read dead letters: %w", err)
+	}
+
+	uc.logger.With(spanCtx).Field("userId", userID).Info("Exported the data of a user on request")
+
+	return export, nil
+}
+
+// Submit enqueues the export of the user and returns its job
+func (uc *ExportUserDataUseCase) Submit(ctx context.Context, userID int64) (*entities.Job, error) {
+	spanCtx, span := uc.tracer.Start(ctx, "Submit Data Export")
+	defer span.End()
+
+	job, err := NewJob(spanCtx, JobTypeDataExport, DataExport{UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+	// The archive in the result of the job is deleted with the data of the user
+	job.UserID = userID
+	if err := uc.jobs.Enqueue(spanCtx, job); err != nil {
+		uc.logger.With(spanCtx).Err(err).Error("Failed to enqueue data export")
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// Handle compiles the archive of the export job into the result of the job
+func (uc *ExportUserDataUseCase) Handle(ctx context.Context, job *entities.Job) error {
+	var payload DataExport
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return fmt.Errorf("%w: failed to decode data export: %v", ErrInvalidJob, err)
+	}
+
+	export, err := uc.Execute(ctx, payload.UserID)
+	if err != nil {
+		return err
+	}
+	SetJobResult(ctx, export)
+
+	return nil
+}
+
+// Status returns the state of the export job of the user, with the archive once the job is done
+func (uc *ExportUserDataUseCase) Status(ctx context.Context, id string, userID int64) (*entities.JobStatus, *entities.UserDataExport, error) {
+	spanCtx, span := uc.tracer.Start(ctx, "Data Export Status")
+	defer span.End()
+
+	status, ok, err := uc.store.Get(spanCtx, id)
+	if err != nil {
+		uc.logger.With(spanCtx).Err(err).Field("jobId", id).Error("Failed to read data export status")
+		return nil, nil, err
+	}
+	if !ok || status.Type != JobTypeDataExport {
+		return nil, nil, ErrExportNotFound
+	}
+	if status.State != entities.JobDone {
+		return status, nil, nil
+	}
+
+	var export entities.UserDataExport
+	if err := json.Unmarshal(status.Result, &export); err != nil {
+		return nil, nil, fmt.Errorf("invalid data export %s: %w", id, err)
+	}
+	// Pending exports can't be told apart, the archive is only served to its user
+	if export.UserID != userID {
+		return nil, nil, ErrExportNotFound
+	}
+
+	return status, &export, nil
+}
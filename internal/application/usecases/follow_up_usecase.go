@@ -0,0 +1,94 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/repositories"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+	"time"
+)
+
+// FollowUpUseCase answers follow-up questions like "plural?" about the last word of a chat
+type FollowUpUseCase struct {
+	lookup        *DetermineArticleUseCase
+	conversations repositories.ConversationRepository
+	ttl           time.Duration
+	logger        logging.Logger
+	tracer        tracing.Tracer
+}
+
+// NewFollowUpUseCase creates a new follow-up use case, conversations are forgotten after the ttl
+func NewFollowUpUseCase(
+	lookup *DetermineArticleUseCase,
+	conversations repositories.ConversationRepository,
+	ttl time.Duration,
+	logger logging.Logger,
+	tracer tracing.Tracer,
+) *FollowUpUseCase {
+	return &FollowUpUseCase{
+		lookup:        lookup,
+		conversations: conversations,
+		ttl:           ttl,
+		logger:        logger,
+		tracer:        tracer,
+	}
+}
+
+// Remember makes the answered word the subject of the next follow-up questions of the chat
+func (uc *FollowUpUseCase) Remember(ctx context.Context, chatID int64, request *entities.ArticleRequest, meaning int) {
+	conversation := &entities.Conversation{
+		ChatID:    chatID,
+		Word:      request.Word,
+		Language:  request.Language,
+		Level:     request.Level,
+		Meaning:   meaning,
+		UpdatedAt: time.Now().UTC(),
+	}
+	if err := uc.conversations.Save(ctx, conversation, uc.ttl); err != nil {
+		uc.logger.With(ctx).Err(err).Warning("Failed to save conversation")
+	}
+}
+
+// Execute answers the follow-up about the last word of the chat, nil when there is no recent word.
+// Another meaning moves to the next interpretation and makes it the subject of later questions.
+func (uc *FollowUpUseCase) Execute(ctx context.Context, chatID int64, followUp entities.FollowUp) (*entities.FollowUpAnswer, error) {
+	spanCtx, span := uc.tracer.Start(ctx, "Follow Up Question")
+	defer span.End()
+
+	conversation, ok, err := uc.conversations.Get(spanCtx, chatID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read conversation: %w", err)
+	}
+	if !ok {
+		return nil, nil
+	}
+
+	uc.logger.With(spanCtx).Field("word", conversation.Word).Field("followUp", followUp).Info("Processing follow-up question")
+
+	request := entities.NewArticleRequest(conversation.Word, conversation.Language)
+	request.Level = conversation.Level
+	// The questions are about the sections of the answer, so its examples are needed
+	response, err := uc.lookup.ExecuteExamples(spanCtx, request)
+	if err != nil || !response.Success {
+		return &entities.FollowUpAnswer{FollowUp: followUp, Word: conversation.Word, Meaning: -1, Response: response}, err
+	}
+
+	answer := &entities.FollowUpAnswer{FollowUp: followUp, Word: conversation.Word, Meaning: conversation.Meaning, Response: response}
+	if followUp == entities.FollowUpAnotherMeaning {
+		if len(response.Data) < 2 {
+			answer.Meaning = -1
+			return answer, nil
+		}
+		answer.Meaning = (max(conversation.Meaning, -1) + 1) % len(response.Data)
+		uc.Remember(spanCtx, chatID, request, answer.Meaning)
+	}
+	if meaning, ok := response.Meaning(answer.Meaning); ok {
+		answer.Response = meaning
+	} else {
+		answer.Meaning = -1
+	}
+
+	return answer, nil
+}
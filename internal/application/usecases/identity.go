@@ -0,0 +1,26 @@
+package usecases
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+)
+
+type identityKey struct{}
+
+// WithIdentity returns the context of a request authenticated as the identity, the request context
+// gets the subject of the identity
+func WithIdentity(ctx context.Context, identity *entities.Identity) context.Context {
+	if request := RequestContextFromContext(ctx); request != nil && identity != nil {
+		withSubject := *request
+		withSubject.Subject = entities.HashSubject(identity.ClientID())
+		ctx = WithRequestContext(ctx, &withSubject)
+	}
+
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// IdentityFromContext returns the identity of the authenticated request, nil for anonymous requests
+func IdentityFromContext(ctx context.Context) *entities.Identity {
+	identity, _ := ctx.Value(identityKey{}).(*entities.Identity)
+	return identity
+}
@@ -0,0 +1,276 @@
+package usecases
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/repositories"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Formats of the dictionary imports
+const (
+	DictionaryFormatCSV   = "csv"
+	DictionaryFormatJSONL = "jsonl"
+)
+
+const (
+	// MaxDictionaryImport limits the entries of a single dictionary import
+	MaxDictionaryImport = 10000
+	// translationColumnPrefix starts the CSV columns of the translations, like translation_en
+	translationColumnPrefix = "translation_"
+)
+
+// ErrInvalidDictionaryFile is returned for dictionary files that can't be parsed
+var ErrInvalidDictionaryFile = errors.New("invalid dictionary file")
+
+// languageCode matches the ISO 639-1 codes of the translations
+var languageCode = regexp.MustCompile(`^[a-z]{2}$`)
+
+// ImportDictionaryUseCase imports the curated nouns of the operators into the dictionary. The cached
+// answers of the imported nouns are purged, so their next lookups are answered by the entries.
+type ImportDictionaryUseCase struct {
+	entries repositories.DictionaryRepository
+	purge   *PurgeCacheUseCase
+	logger  logging.Logger
+	tracer  tracing.Tracer
+}
+
+// NewImportDictionaryUseCase creates a new dictionary import use case instance
+func NewImportDictionaryUseCase(
+	entries repositories.DictionaryRepository,
+	purge *PurgeCacheUseCase,
+	logger logging.Logger,
+	tracer tracing.Tracer,
+) *ImportDictionaryUseCase {
+	return &ImportDictionaryUseCase{
+		entries: entries,
+		purge:   purge,
+		logger:  logger,
+		tracer:  tracer,
+	}
+}
+
+// Execute validates the rows and saves the valid ones, the invalid ones are reported and skipped.
+// A dry run only validates them.
+func (uc *ImportDictionaryUseCase) Execute(ctx context.Context, rows []entities.DictionaryImportRow, dryRun bool) (*entities.DictionaryImportResult, error) {
+	spanCtx, span := uc.tracer.Start(ctx, "Import Dictionary")
+	defer span.End()
+
+	if len(rows) > MaxDictionaryImport {
+		return nil, fmt.Errorf("%w: %d, at most %d are allowed", ErrTooManyWords, len(rows), MaxDictionaryImport)
+	}
+
+	result := &entities.DictionaryImportResult{
+		DryRun:  dryRun,
+		Entries: make([]entities.CuratedEntry, 0, len(rows)),
+		Errors:  []entities.DictionaryImportError{},
+	}
+	now := time.Now().UTC()
+	lines := make(map[string]int)
+	for _, row := range rows {
+		entry, problem := validateCuratedEntry(row)
+		if problem == "" && lines[entry.Key()] > 0 {
+			problem = fmt.Sprintf("duplicate of line %d", lines[entry.Key()])
+		}
+		if problem != "" {
+			result.Errors = append(result.Errors, entities.DictionaryImportError{Line: row.Line, Word: row.Word, Error: problem})
+			continue
+		}
+		lines[entry.Key()] = row.Line
+		entry.ImportedAt = now
+		result.Entries = append(result.Entries, entry)
+	}
+	if dryRun || len(result.Entries) == 0 {
+		return result, nil
+	}
+
+	if err := uc.entries.Save(spanCtx, result.Entries); err != nil {
+		uc.logger.With(spanCtx).Err(err).Field("entries", len(result.Entries)).Error("Failed to save dictionary entries")
+		return nil, err
+	}
+	result.Imported = len(result.Entries)
+
+	words := make([]string, len(result.Entries))
+	for i, entry := range result.Entries {
+		words[i] = entry.Word
+	}
+	if _, err := uc.purge.Execute(spanCtx, words, ""); err != nil {
+		uc.logger.With(spanCtx).Err(err).Warning("Imported dictionary entries may be answered from the cache until it expires")
+	}
+	uc.logger.With(spanCtx).Field("imported", result.Imported).Field("errors", len(result.Errors)).Info("Dictionary entries imported")
+
+	return result, nil
+}
+
+// validateCuratedEntry normalizes the row into an entry, the problem explains why an invalid row is rejected
+func validateCuratedEntry(row entities.DictionaryImportRow) (entities.CuratedEntry, string) {
+	word := entities.NormalizeWord(row.Word)
+	if word == "" {
+		return entities.CuratedEntry{}, "the word is required"
+	}
+	if problem := entities.CheckWord(word); problem != "" {
+		return entities.CuratedEntry{}, problem.Explanation("en")
+	}
+	if first, _ := utf8.DecodeRuneInString(word); !unicode.IsUpper(first) {
+		return entities.CuratedEntry{}, "the word must be a noun starting with a capital letter"
+	}
+
+	article, ok := entities.ArticleOfGender(row.Gender)
+	if !ok {
+		return entities.CuratedEntry{}, "the gender must be der, die, das, masculine, feminine or neuter"
+	}
+	entry := entities.CuratedEntry{Word: word, Article: article}
+
+	// The plural is given with or without its article, "-" marks nouns without one
+	if plural := strings.TrimSpace(row.Plural); plural != "" && plural != "-" {
+		if article, noun, ok := strings.Cut(plural, " "); ok && strings.EqualFold(article, "die") {
+			plural = strings.TrimSpace(noun)
+		}
+		if problem := entities.CheckWord(plural); problem != "" {
+			return entities.CuratedEntry{}, "plural: " + problem.Explanation("en")
+		}
+		entry.Plural = "die " + plural
+	}
+
+	for language, translation := range row.Translations {
+		language = strings.ToLower(strings.TrimSpace(language))
+		if !languageCode.MatchString(language) {
+			return entities.CuratedEntry{}, fmt.Sprintf("the translation language %q must be an ISO 639-1 code, like en", language)
+		}
+		if translation = strings.TrimSpace(translation); translation == "" {
+			continue
+		}
+		if entry.Translations == nil {
+			entry.Translations = make(map[string]string)
+		}
+		entry.Translations[language] = translation
+	}
+
+	return entry, ""
+}
+
+// ParseDictionaryFile reads the rows of a CSV or JSON Lines dictionary file, an empty format is detected
+// from the first character. CSV files start with a header of the word, gender and plural columns and
+// a translation_<language> column per language, JSON Lines files hold an object with these fields and
+// a translations object per line. Empty lines are skipped, as well as "#" comments in CSV files.
+func ParseDictionaryFile(r io.Reader, format string) ([]entities.DictionaryImportRow, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dictionary file: %w", err)
+	}
+	if format == "" {
+		format = DictionaryFormatCSV
+		if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+			format = DictionaryFormatJSONL
+		}
+	}
+
+	switch format {
+	case DictionaryFormatCSV:
+		return parseDictionaryCSV(data)
+	case DictionaryFormatJSONL:
+		return parseDictionaryJSONL(data)
+	default:
+		return nil, fmt.Errorf("%w: unknown format %q", ErrInvalidDictionaryFile, format)
+	}
+}
+
+func parseDictionaryCSV(data []byte) ([]entities.DictionaryImportRow, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = detectDelimiter(data)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDictionaryFile, err)
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if name != "word" && name != "gender" && name != "plural" && !strings.HasPrefix(name, translationColumnPrefix) {
+			return nil, fmt.Errorf("%w: unknown column %q", ErrInvalidDictionaryFile, name)
+		}
+		columns[name] = i
+	}
+	if _, ok := columns["word"]; !ok {
+		return nil, fmt.Errorf("%w: the header has no word column", ErrInvalidDictionaryFile)
+	}
+	if _, ok := columns["gender"]; !ok {
+		return nil, fmt.Errorf("%w: the header has no gender column", ErrInvalidDictionaryFile)
+	}
+
+	var rows []entities.DictionaryImportRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidDictionaryFile, err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		row := entities.DictionaryImportRow{Line: line, Word: field("word"), Gender: field("gender"), Plural: field("plural")}
+		for name := range columns {
+			if language, ok := strings.CutPrefix(name, translationColumnPrefix); ok && field(name) != "" {
+				if row.Translations == nil {
+					row.Translations = make(map[string]string)
+				}
+				row.Translations[language] = field(name)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func parseDictionaryJSONL(data []byte) ([]entities.DictionaryImportRow, error) {
+	var rows []entities.DictionaryImportRow
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+
+		var row entities.DictionaryImportRow
+		decoder := json.NewDecoder(bytes.NewReader(text))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&row); err != nil {
+			return nil, fmt.Errorf("%w: line %d: %w", ErrInvalidDictionaryFile, line, err)
+		}
+		row.Line = line
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDictionaryFile, err)
+	}
+
+	return rows, nil
+}
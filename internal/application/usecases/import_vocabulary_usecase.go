@@ -0,0 +1,163 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+	"golang.org/x/time/rate"
+	"io"
+	"strings"
+)
+
+// ErrTooManyWords is returned when an imported list exceeds the configured size
+var ErrTooManyWords = errors.New("too many words in the list")
+
+// ImportVocabularyUseCase resolves lists of nouns, AI calls of all imports share one rate limit
+type ImportVocabularyUseCase struct {
+	lookup   *DetermineArticleUseCase
+	limiter  *rate.Limiter
+	maxWords int
+	logger   logging.Logger
+	tracer   tracing.Tracer
+}
+
+// NewImportVocabularyUseCase creates a new import use case doing at most ratePerSecond lookups
+func NewImportVocabularyUseCase(
+	lookup *DetermineArticleUseCase,
+	ratePerSecond float64,
+	maxWords int,
+	logger logging.Logger,
+	tracer tracing.Tracer,
+) *ImportVocabularyUseCase {
+	return &ImportVocabularyUseCase{
+		lookup:   lookup,
+		limiter:  rate.NewLimiter(rate.Limit(ratePerSecond), 1),
+		maxWords: maxWords,
+		logger:   logger,
+		tracer:   tracer,
+	}
+}
+
+// MaxWords returns the maximum number of words in a single import
+func (uc *ImportVocabularyUseCase) MaxWords() int {
+	return uc.maxWords
+}
+
+// Execute looks up every word and compiles the table, failed words don't stop the import
+func (uc *ImportVocabularyUseCase) Execute(ctx context.Context, words []string, language string) (*entities.ImportResult, error) {
+	spanCtx, span := uc.tracer.Start(ctx, "Import Vocabulary")
+	defer span.End()
+
+	if len(words) > uc.maxWords {
+		return nil, fmt.Errorf("%w: %d, at most %d are allowed", ErrTooManyWords, len(words), uc.maxWords)
+	}
+
+	uc.logger.With(spanCtx).Field("words", len(words)).Field("language", language).Info("Importing vocabulary list")
+
+	result := &entities.ImportResult{
+		Language: language,
+		Rows:     make([]entities.ImportRow, 0, len(words)),
+		Failures: []entities.ImportFailure{},
+	}
+	for i, word := range words {
+		if err := uc.limiter.Wait(spanCtx); err != nil {
+			return nil, fmt.Errorf("import interrupted: %w", err)
+		}
+
+		rows, failure := lookupRows(spanCtx, uc.lookup, word, language)
+		ReportJobProgress(spanCtx, i+1, len(words))
+		if failure != nil {
+			result.Failures = append(result.Failures, *failure)
+			continue
+		}
+		result.Rows = append(result.Rows, rows...)
+	}
+
+	uc.logger.With(spanCtx).Field("rows", len(result.Rows)).Field("failures", len(result.Failures)).Info("Vocabulary list imported")
+
+	return result, nil
+}
+
+// lookupRows resolves the word into one row per interpretation or the failure of the lookup
+func lookupRows(ctx context.Context, lookup *DetermineArticleUseCase, word, language string) ([]entities.ImportRow, *entities.ImportFailure) {
+	response, err := lookup.Execute(ctx, entities.NewArticleRequest(word, language))
+	if err != nil {
+		return nil, &entities.ImportFailure{Word: word, Error: "Failed to process request"}
+	}
+	if !response.Success || len(response.Data) == 0 {
+		return nil, &entities.ImportFailure{Word: word, Error: response.Error}
+	}
+
+	rows := make([]entities.ImportRow, 0, len(response.Data))
+	for _, info := range response.Data {
+		rows = append(rows, entities.ImportRow{
+			Word:            word,
+			Article:         info.Article(),
+			WordWithArticle: info.WordWithArticle,
+			Translation:     info.Translation,
+			Plural:          info.Plural,
+		})
+	}
+
+	return rows, nil
+}
+
+// ParseWordList reads words from plain text or CSV, one word per line or in the first column.
+// The comma, semicolon or tab delimiter is detected from the first line.
+// Empty lines, "#" comments, a "word" header and case-insensitive duplicates are skipped.
+func ParseWordList(r io.Reader) ([]string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read word list: %w", err)
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = detectDelimiter(data)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	var (
+		words []string
+		seen  = make(map[string]bool)
+	)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse word list: %w", err)
+		}
+
+		word := strings.TrimSpace(record[0])
+		key := strings.ToLower(word)
+		if word == "" || key == "word" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		words = append(words, word)
+	}
+
+	return words, nil
+}
+
+// detectDelimiter returns the most frequent of the supported delimiters in the first line
+func detectDelimiter(data []byte) rune {
+	line, _, _ := bytes.Cut(data, []byte("\n"))
+
+	delimiter, count := ',', bytes.Count(line, []byte(","))
+	for _, candidate := range []rune{';', '\t'} {
+		if n := bytes.Count(line, []byte(string(candidate))); n > count {
+			delimiter, count = candidate, n
+		}
+	}
+
+	return delimiter
+}
@@ -0,0 +1,50 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/services"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+)
+
+// ErrSessionRenewal is returned for requests authenticated with a session token, renewing them would
+// make the sessions as long-lived as the credentials they replace
+var ErrSessionRenewal = errors.New("session tokens can't be renewed, authenticate with the API token or the ID token")
+
+// IssueSessionUseCase exchanges the credentials of a request for a short-lived session token
+type IssueSessionUseCase struct {
+	issuer services.SessionIssuer
+	logger logging.Logger
+	tracer tracing.Tracer
+}
+
+// NewIssueSessionUseCase creates a new session token use case
+func NewIssueSessionUseCase(issuer services.SessionIssuer, logger logging.Logger, tracer tracing.Tracer) *IssueSessionUseCase {
+	return &IssueSessionUseCase{
+		issuer: issuer,
+		logger: logger,
+		tracer: tracer,
+	}
+}
+
+// Execute issues a session token of the identity, ErrSessionRenewal for identities of session tokens
+func (uc *IssueSessionUseCase) Execute(ctx context.Context, identity *entities.Identity) (*entities.SessionToken, error) {
+	spanCtx, span := uc.tracer.Start(ctx, "Issue Session Token")
+	defer span.End()
+
+	if identity.Session {
+		return nil, ErrSessionRenewal
+	}
+
+	token, err := uc.issuer.Issue(spanCtx, identity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue session token: %w", err)
+	}
+
+	uc.logger.With(spanCtx).Field("provider", identity.Provider).Info("Session token issued")
+
+	return token, nil
+}
@@ -0,0 +1,147 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/repositories"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/services"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"sync"
+	"time"
+)
+
+type jobTrackerKey struct{}
+
+// jobTracker saves the state changes of a job to the job store
+type jobTracker struct {
+	mu     sync.Mutex
+	store  repositories.JobRepository
+	ttl    time.Duration
+	status entities.JobStatus
+	logger logging.Logger
+}
+
+// update applies the change to the status and saves it, failures are logged since the job itself goes on
+func (t *jobTracker) update(ctx context.Context, change func(status *entities.JobStatus)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	change(&t.status)
+	t.status.UpdatedAt = time.Now().UTC()
+	if err := t.store.Save(ctx, &t.status, t.ttl); err != nil {
+		t.logger.With(ctx).Field("jobId", t.status.ID).Err(err).Warning("Failed to save job status")
+	}
+}
+
+// ReportJobProgress records the number of processed items of the running job, it does nothing outside of tracked jobs
+func ReportJobProgress(ctx context.Context, done, total int) {
+	if tracker, ok := ctx.Value(jobTrackerKey{}).(*jobTracker); ok {
+		tracker.update(ctx, func(status *entities.JobStatus) {
+			status.Progress = &entities.JobProgress{Done: done, Total: total}
+		})
+	}
+}
+
+// SetJobResult records the JSON encoded outcome of the running job, it does nothing outside of tracked jobs
+func SetJobResult(ctx context.Context, result interface{}) {
+	tracker, ok := ctx.Value(jobTrackerKey{}).(*jobTracker)
+	if !ok {
+		return
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		tracker.logger.With(ctx).Field("jobId", tracker.status.ID).Err(err).Warning("Failed to encode job result")
+		return
+	}
+	tracker.update(ctx, func(status *entities.JobStatus) {
+		status.Result = data
+	})
+}
+
+// ReportJobCheckpoint records the processed items and the partial outcome of the running job in one save, a
+// retried job reads the outcome back with PreviousJobResult to continue where the earlier attempt stopped
+func ReportJobCheckpoint(ctx context.Context, done, total int, result interface{}) {
+	tracker, ok := ctx.Value(jobTrackerKey{}).(*jobTracker)
+	if !ok {
+		return
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		tracker.logger.With(ctx).Field("jobId", tracker.status.ID).Err(err).Warning("Failed to encode job result")
+		return
+	}
+	tracker.update(ctx, func(status *entities.JobStatus) {
+		status.Progress = &entities.JobProgress{Done: done, Total: total}
+		status.Result = data
+	})
+}
+
+// PreviousJobResult decodes the outcome recorded by an earlier attempt of the running job into result, false
+// for first attempts, jobs without a recorded outcome and outside of tracked jobs
+func PreviousJobResult(ctx context.Context, result interface{}) bool {
+	tracker, ok := ctx.Value(jobTrackerKey{}).(*jobTracker)
+	if !ok {
+		return false
+	}
+
+	tracker.mu.Lock()
+	data := tracker.status.Result
+	tracker.mu.Unlock()
+	if len(data) == 0 {
+		return false
+	}
+	if err := json.Unmarshal(data, result); err != nil {
+		tracker.logger.With(ctx).Field("jobId", tracker.status.ID).Err(err).Warning("Failed to decode job result")
+		return false
+	}
+
+	return true
+}
+
+// TrackedJobQueue records jobs as queued in the job store before handing them to the queue
+type TrackedJobQueue struct {
+	queue  services.JobQueue
+	store  repositories.JobRepository
+	ttl    time.Duration
+	logger logging.Logger
+}
+
+// NewTrackedJobQueue creates a queue keeping the states of the jobs for ttl
+func NewTrackedJobQueue(queue services.JobQueue, store repositories.JobRepository, ttl time.Duration, logger logging.Logger) *TrackedJobQueue {
+	return &TrackedJobQueue{
+		queue:  queue,
+		store:  store,
+		ttl:    ttl,
+		logger: logger,
+	}
+}
+
+// Enqueue saves the job as queued and enqueues it, a job the queue refused is saved as failed
+func (q *TrackedJobQueue) Enqueue(ctx context.Context, job *entities.Job) error {
+	tracker := &jobTracker{
+		store: q.store,
+		ttl:   q.ttl,
+		status: entities.JobStatus{
+			ID:        job.ID,
+			Type:      job.Type,
+			UserID:    job.UserID,
+			CreatedAt: job.CreatedAt,
+		},
+		logger: q.logger,
+	}
+	tracker.update(ctx, func(status *entities.JobStatus) {
+		status.State = entities.JobQueued
+	})
+
+	err := q.queue.Enqueue(ctx, job)
+	if err != nil {
+		tracker.update(ctx, func(status *entities.JobStatus) {
+			status.State, status.Error = entities.JobFailed, err.Error()
+		})
+	}
+
+	return err
+}
@@ -0,0 +1,245 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/repositories"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/services"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+	"math/rand/v2"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// JobTypeLeaderboardSummary is the background job broadcasting the leaderboards of a finished week
+const JobTypeLeaderboardSummary entities.JobType = "leaderboard.summary"
+
+const (
+	minDisplayNameLength = 2
+	maxDisplayNameLength = 20
+)
+
+// ErrInvalidDisplayName is returned for display names of the wrong length or with unsupported characters
+var ErrInvalidDisplayName = fmt.Errorf("the display name must be %d to %d letters, digits, spaces, - or _", minDisplayNameLength, maxDisplayNameLength)
+
+// ErrInvalidWeek is returned for weeks that aren't ISO weeks like 2026-W42
+var ErrInvalidWeek = errors.New("the week must look like 2026-W42")
+
+// The pseudonyms are German animals with a matching adjective, so members don't have to reveal their Telegram names
+var (
+	pseudonymAdjectives = []string{"Flinker", "Kluger", "Mutiger", "Stiller", "Wilder", "Frecher", "Schlauer", "Fleißiger", "Munterer", "Tapferer"}
+	pseudonymAnimals    = []string{"Fuchs", "Bär", "Adler", "Dachs", "Igel", "Luchs", "Wolf", "Hase", "Biber", "Falke"}
+)
+
+// LeaderboardSummary is the payload of the JobTypeLeaderboardSummary job
+type LeaderboardSummary struct {
+	Week string `json:"week"`
+}
+
+// LeaderboardUseCase ranks the opted-in users by their weekly quiz scores, across all chats and within groups
+type LeaderboardUseCase struct {
+	leaderboard repositories.LeaderboardRepository
+	jobs        services.JobQueue
+	logger      logging.Logger
+	tracer      tracing.Tracer
+}
+
+// NewLeaderboardUseCase creates a new leaderboard use case instance
+func NewLeaderboardUseCase(
+	leaderboard repositories.LeaderboardRepository,
+	jobs services.JobQueue,
+	logger logging.Logger,
+	tracer tracing.Tracer,
+) *LeaderboardUseCase {
+	return &LeaderboardUseCase{
+		leaderboard: leaderboard,
+		jobs:        jobs,
+		logger:      logger,
+		tracer:      tracer,
+	}
+}
+
+// Join adds the user to the leaderboard with the display name, an empty name keeps the current name
+// of a member and gives new members a random pseudonym. The weekly summary is sent in the language.
+func (uc *LeaderboardUseCase) Join(ctx context.Context, userID int64, name, language string) (*entities.LeaderboardMember, error) {
+	spanCtx, span := uc.tracer.Start(ctx, "Join Leaderboard")
+	defer span.End()
+
+	name = strings.Join(strings.Fields(name), " ")
+	if name != "" && !validDisplayName(name) {
+		return nil, ErrInvalidDisplayName
+	}
+
+	member, err := uc.leaderboard.Member(spanCtx, userID)
+	if err != nil {
+		uc.logger.With(spanCtx).Err(err).Error("Failed to read leaderboard member")
+		return nil, err
+	}
+	if member == nil {
+		member = &entities.LeaderboardMember{UserID: userID, Name: pseudonym(), JoinedAt: time.Now().UTC()}
+	}
+	if name != "" {
+		member.Name = name
+	}
+	member.Language = language
+
+	if err := uc.leaderboard.SaveMember(spanCtx, member); err != nil {
+		uc.logger.With(spanCtx).Err(err).Error("Failed to save leaderboard member")
+		return nil, err
+	}
+
+	return member, nil
+}
+
+// Leave removes the user and all scores of the user from the leaderboards
+func (uc *LeaderboardUseCase) Leave(ctx context.Context, userID int64) error {
+	spanCtx, span := uc.tracer.Start(ctx, "Leave Leaderboard")
+	defer span.End()
+
+	if err := uc.leaderboard.DeleteMember(spanCtx, userID); err != nil {
+		uc.logger.With(spanCtx).Err(err).Error("Failed to delete leaderboard member")
+		return err
+	}
+
+	return nil
+}
+
+// Member returns the membership of the user, nil for users who didn't opt in
+func (uc *LeaderboardUseCase) Member(ctx context.Context, userID int64) (*entities.LeaderboardMember, error) {
+	return uc.leaderboard.Member(ctx, userID)
+}
+
+// RecordAnswer counts the quiz answer of a member for the current week on the global leaderboard and,
+// for answers in a group, on the leaderboard of the group. Answers of other users aren't stored.
+func (uc *LeaderboardUseCase) RecordAnswer(ctx context.Context, chatID int64, userID int64, correct bool) {
+	spanCtx, span := uc.tracer.Start(ctx, "Record Leaderboard Answer")
+	defer span.End()
+
+	member, err := uc.leaderboard.Member(spanCtx, userID)
+	if err != nil || member == nil {
+		return
+	}
+
+	week := entities.LeaderboardWeek(time.Now())
+	chats := []int64{entities.LeaderboardGlobal}
+	if chatID != entities.LeaderboardGlobal {
+		chats = append(chats, chatID)
+	}
+	for _, chat := range chats {
+		if err := uc.leaderboard.RecordScore(spanCtx, week, chat, userID, correct); err != nil {
+			uc.logger.With(spanCtx).Err(err).Warning("Failed to record leaderboard score")
+		}
+	}
+}
+
+// Standings returns the full ranking of the week in the chat, LeaderboardGlobal for the ranking across all chats
+func (uc *LeaderboardUseCase) Standings(ctx context.Context, chatID int64, week string) (*entities.Leaderboard, error) {
+	spanCtx, span := uc.tracer.Start(ctx, "Leaderboard Standings")
+	defer span.End()
+
+	scores, err := uc.leaderboard.Scores(spanCtx, week, chatID)
+	if err != nil {
+		uc.logger.With(spanCtx).Err(err).Error("Failed to read leaderboard scores")
+		return nil, err
+	}
+
+	board := &entities.Leaderboard{Week: week, ChatID: chatID, Entries: []entities.LeaderboardEntry{}}
+	for _, score := range scores {
+		member, err := uc.leaderboard.Member(spanCtx, score.UserID)
+		if err != nil || member == nil || score.Correct == 0 {
+			continue
+		}
+		board.Entries = append(board.Entries, entities.LeaderboardEntry{
+			UserID:  score.UserID,
+			Name:    member.Name,
+			Answers: score.Answers,
+			Correct: score.Correct,
+		})
+	}
+
+	// More correct answers rank higher, then fewer answers for them, members with the same score share the rank
+	sort.Slice(board.Entries, func(i, j int) bool {
+		a, b := board.Entries[i], board.Entries[j]
+		if a.Correct != b.Correct {
+			return a.Correct > b.Correct
+		}
+		if a.Answers != b.Answers {
+			return a.Answers < b.Answers
+		}
+		return a.Name < b.Name
+	})
+	for i := range board.Entries {
+		board.Entries[i].Rank = i + 1
+		if previous := i - 1; previous >= 0 && board.Entries[previous].Correct == board.Entries[i].Correct &&
+			board.Entries[previous].Answers == board.Entries[i].Answers {
+			board.Entries[i].Rank = board.Entries[previous].Rank
+		}
+	}
+
+	return board, nil
+}
+
+// Chats returns the group chats with leaderboard scores in the week
+func (uc *LeaderboardUseCase) Chats(ctx context.Context, week string) ([]int64, error) {
+	return uc.leaderboard.Chats(ctx, week)
+}
+
+// SubmitSummary enqueues the broadcast of the leaderboards of the week, an empty week is the previous one
+func (uc *LeaderboardUseCase) SubmitSummary(ctx context.Context, week string) (*entities.Job, error) {
+	spanCtx, span := uc.tracer.Start(ctx, "Submit Leaderboard Summary")
+	defer span.End()
+
+	if week == "" {
+		week = entities.LeaderboardWeek(time.Now().AddDate(0, 0, -7))
+	}
+	if !validWeek(week) {
+		return nil, ErrInvalidWeek
+	}
+
+	job, err := NewJob(spanCtx, JobTypeLeaderboardSummary, LeaderboardSummary{Week: week})
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.jobs.Enqueue(spanCtx, job); err != nil {
+		uc.logger.With(spanCtx).Err(err).Error("Failed to enqueue leaderboard summary")
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// validDisplayName reports whether the trimmed name has a supported length and characters
+func validDisplayName(name string) bool {
+	if length := utf8.RuneCountInString(name); length < minDisplayNameLength || length > maxDisplayNameLength {
+		return false
+	}
+
+	return strings.IndexFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != ' ' && r != '-' && r != '_'
+	}) < 0
+}
+
+// validWeek reports whether the week is an ISO week like 2026-W42
+func validWeek(week string) bool {
+	var year, number int
+	if _, err := fmt.Sscanf(week, "%4d-W%2d", &year, &number); err != nil {
+		return false
+	}
+
+	return len(week) == len("2026-W42") && number >= 1 && number <= 53
+}
+
+// pseudonym returns a random display name like "Kluger Fuchs 42"
+func pseudonym() string {
+	return fmt.Sprintf("%s %s %d",
+		pseudonymAdjectives[rand.IntN(len(pseudonymAdjectives))],
+		pseudonymAnimals[rand.IntN(len(pseudonymAnimals))],
+		10+rand.IntN(90),
+	)
+}
@@ -0,0 +1,137 @@
+package usecases
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/repositories"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+	"sort"
+	"time"
+)
+
+const (
+	// maxConfusedWords limits the most confused words of the statistics
+	maxConfusedWords = 5
+	// recentActivityDays is the number of the last days of the statistics
+	recentActivityDays = 7
+)
+
+// quizArticles orders the accuracy of the statistics
+var quizArticles = []string{"der", "die", "das"}
+
+// LearningStatsUseCase summarizes the learning activity of a user into streaks and accuracy
+type LearningStatsUseCase struct {
+	activity  repositories.ActivityRepository
+	timezones *TimezoneUseCase
+	logger    logging.Logger
+	tracer    tracing.Tracer
+}
+
+// NewLearningStatsUseCase creates a new learning statistics use case instance
+func NewLearningStatsUseCase(
+	activity repositories.ActivityRepository,
+	logger logging.Logger,
+	tracer tracing.Tracer,
+) *LearningStatsUseCase {
+	return &LearningStatsUseCase{
+		activity: activity,
+		logger:   logger,
+		tracer:   tracer,
+	}
+}
+
+// SetTimezones counts the days of the statistics in the time zones of the users, nil counts UTC days
+func (uc *LearningStatsUseCase) SetTimezones(timezones *TimezoneUseCase) {
+	uc.timezones = timezones
+}
+
+// Execute returns the learning statistics of the user as of today
+func (uc *LearningStatsUseCase) Execute(ctx context.Context, userID int64) (*entities.LearningStats, error) {
+	spanCtx, span := uc.tracer.Start(ctx, "Learning Stats")
+	defer span.End()
+
+	activity, err := uc.activity.Activity(spanCtx, userID)
+	if err != nil {
+		uc.logger.With(spanCtx).Err(err).Error("Failed to read learning activity")
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if uc.timezones != nil {
+		now = uc.timezones.Now(spanCtx, userID)
+	}
+
+	return summarizeActivity(activity, now), nil
+}
+
+// summarizeActivity computes the statistics of the activity, streaks count days up to now in its time zone
+func summarizeActivity(activity *entities.UserActivity, now time.Time) *entities.LearningStats {
+	stats := &entities.LearningStats{
+		ActiveDays:    len(activity.Days),
+		WordsLookedUp: activity.Words,
+		Accuracy:      []entities.ArticleAccuracy{},
+		ConfusedWords: []entities.ConfusedWord{},
+		RecentDays:    []entities.DailyActivity{},
+	}
+
+	var (
+		streak   int
+		previous time.Time
+	)
+	for _, day := range activity.Days {
+		stats.Lookups += day.Lookups
+		stats.QuizAnswers += day.QuizAnswers
+		stats.QuizCorrect += day.QuizCorrect
+
+		date, err := time.Parse(entities.ActivityDayLayout, day.Day)
+		if err != nil {
+			continue
+		}
+		if !previous.IsZero() && date.Sub(previous) == 24*time.Hour {
+			streak++
+		} else {
+			streak = 1
+		}
+		previous = date
+		stats.LongestStreak = max(stats.LongestStreak, streak)
+	}
+
+	// The streak is still alive when the last active day is today or yesterday
+	if !previous.IsZero() {
+		stats.LastActiveDay = previous.Format(entities.ActivityDayLayout)
+		today, _ := time.Parse(entities.ActivityDayLayout, now.Format(entities.ActivityDayLayout))
+		if today.Sub(previous) <= 24*time.Hour {
+			stats.CurrentStreak = streak
+		}
+	}
+
+	if len(activity.Days) > recentActivityDays {
+		stats.RecentDays = append(stats.RecentDays, activity.Days[len(activity.Days)-recentActivityDays:]...)
+	} else {
+		stats.RecentDays = append(stats.RecentDays, activity.Days...)
+	}
+
+	for _, article := range quizArticles {
+		for _, accuracy := range activity.Articles {
+			if accuracy.Article != article || accuracy.Answers == 0 {
+				continue
+			}
+			accuracy.Rate = float64(accuracy.Correct) / float64(accuracy.Answers)
+			stats.Accuracy = append(stats.Accuracy, accuracy)
+		}
+	}
+
+	stats.ConfusedWords = append(stats.ConfusedWords, activity.Confused...)
+	sort.Slice(stats.ConfusedWords, func(i, j int) bool {
+		if stats.ConfusedWords[i].Mistakes != stats.ConfusedWords[j].Mistakes {
+			return stats.ConfusedWords[i].Mistakes > stats.ConfusedWords[j].Mistakes
+		}
+		return stats.ConfusedWords[i].Noun < stats.ConfusedWords[j].Noun
+	})
+	if len(stats.ConfusedWords) > maxConfusedWords {
+		stats.ConfusedWords = stats.ConfusedWords[:maxConfusedWords]
+	}
+
+	return stats
+}
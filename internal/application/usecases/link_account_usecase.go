@@ -0,0 +1,175 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/repositories"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/services"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+	"strings"
+	"time"
+)
+
+const (
+	// linkCodeAlphabet leaves out the characters users confuse when typing the code
+	linkCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	linkCodeLength   = 8
+	apiTokenBytes    = 32
+)
+
+// ErrInvalidLinkCode is returned for unknown, used and expired link codes
+var ErrInvalidLinkCode = errors.New("the link code is invalid or expired, send /link to the bot for a new one")
+
+// LinkAccountUseCase links Telegram accounts to the web API: the bot hands out one-time codes which
+// are exchanged for API tokens of the same profile. Requests are authenticated with the API tokens or
+// with the ID tokens of the external identity providers.
+type LinkAccountUseCase struct {
+	accounts  repositories.AccountRepository
+	verifiers []services.IdentityVerifier
+	codeTTL   time.Duration
+	logger    logging.Logger
+	tracer    tracing.Tracer
+}
+
+// NewLinkAccountUseCase creates a new account linking use case with codes valid for codeTTL
+func NewLinkAccountUseCase(
+	accounts repositories.AccountRepository,
+	codeTTL time.Duration,
+	logger logging.Logger,
+	tracer tracing.Tracer,
+) *LinkAccountUseCase {
+	return &LinkAccountUseCase{
+		accounts: accounts,
+		codeTTL:  codeTTL,
+		logger:   logger,
+		tracer:   tracer,
+	}
+}
+
+// SetVerifiers accepts the ID tokens of the external identity providers
+func (uc *LinkAccountUseCase) SetVerifiers(verifiers ...services.IdentityVerifier) {
+	uc.verifiers = verifiers
+}
+
+// CodeTTL returns how long the link codes are valid
+func (uc *LinkAccountUseCase) CodeTTL() time.Duration {
+	return uc.codeTTL
+}
+
+// CreateCode returns a new one-time link code of the user, the previous code of the user is no longer valid
+func (uc *LinkAccountUseCase) CreateCode(ctx context.Context, userID int64) (string, error) {
+	spanCtx, span := uc.tracer.Start(ctx, "Create Link Code")
+	defer span.End()
+
+	random := make([]byte, linkCodeLength)
+	if _, err := rand.Read(random); err != nil {
+		return "", fmt.Errorf("failed to generate link code: %w", err)
+	}
+	code := make([]byte, linkCodeLength)
+	for i, b := range random {
+		// The alphabet has 32 characters, so the low bits of a random byte are uniform
+		code[i] = linkCodeAlphabet[int(b)%len(linkCodeAlphabet)]
+	}
+
+	if err := uc.accounts.SaveLinkCode(spanCtx, string(code), userID, uc.codeTTL); err != nil {
+		return "", fmt.Errorf("failed to save link code: %w", err)
+	}
+
+	return string(code), nil
+}
+
+// Link exchanges the code for an API token of the user who created it, the previous token of the user
+// is revoked. An external identity is linked to the user as well, so its ID tokens resolve to the
+// profile. ErrInvalidLinkCode is returned for unknown, used and expired codes.
+func (uc *LinkAccountUseCase) Link(ctx context.Context, code string, external *entities.Identity) (*entities.LinkedAccount, error) {
+	spanCtx, span := uc.tracer.Start(ctx, "Link Account")
+	defer span.End()
+
+	userID, ok, err := uc.accounts.TakeLinkCode(spanCtx, normalizeLinkCode(code))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read link code: %w", err)
+	}
+	if !ok {
+		return nil, ErrInvalidLinkCode
+	}
+
+	random := make([]byte, apiTokenBytes)
+	if _, err := rand.Read(random); err != nil {
+		return nil, fmt.Errorf("failed to generate API token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(random)
+	if err := uc.accounts.SaveToken(spanCtx, hashToken(token), userID); err != nil {
+		return nil, fmt.Errorf("failed to save API token: %w", err)
+	}
+
+	if external != nil && external.Subject != "" {
+		if err := uc.accounts.SaveIdentity(spanCtx, external.Provider, external.Subject, userID); err != nil {
+			return nil, fmt.Errorf("failed to link %s identity: %w", external.Provider, err)
+		}
+	}
+
+	uc.logger.With(spanCtx).Field("userId", userID).Info("Telegram account linked")
+
+	return &entities.LinkedAccount{
+		Token:     token,
+		UserID:    userID,
+		CreatedAt: time.Now().UTC(),
+	}, nil
+}
+
+// Authenticate returns the identity of the API token or the ID token, nil for unknown API tokens.
+// Invalid ID tokens return an error wrapping ErrInvalidIDToken.
+func (uc *LinkAccountUseCase) Authenticate(ctx context.Context, token string) (*entities.Identity, error) {
+	for _, verifier := range uc.verifiers {
+		identity, err := verifier.Verify(ctx, token)
+		if err != nil {
+			return nil, err
+		}
+		if identity == nil {
+			continue
+		}
+
+		// External identities linked to the bot share its profile
+		userID, ok, err := uc.accounts.FindIdentity(ctx, identity.Provider, identity.Subject)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read linked identity: %w", err)
+		}
+		if ok {
+			identity.UserID = userID
+		}
+		return identity, nil
+	}
+
+	userID, ok, err := uc.accounts.FindToken(ctx, hashToken(token))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read API token: %w", err)
+	}
+	if !ok {
+		return nil, nil
+	}
+
+	return &entities.Identity{UserID: userID, Provider: entities.IdentityProviderLink}, nil
+}
+
+// normalizeLinkCode accepts the code in any case and with the spaces and dashes users add when typing it
+func normalizeLinkCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(strings.TrimSpace(code)))
+}
+
+// hashToken is the stored form of the API tokens, so a leaked store doesn't leak usable tokens
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
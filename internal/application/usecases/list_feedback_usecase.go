@@ -0,0 +1,43 @@
+package usecases
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/repositories"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+)
+
+// ListFeedbackUseCase lists user ratings for prompt tuning
+type ListFeedbackUseCase struct {
+	feedback repositories.FeedbackRepository
+	logger   logging.Logger
+	tracer   tracing.Tracer
+}
+
+// NewListFeedbackUseCase creates a new list feedback use case instance
+func NewListFeedbackUseCase(
+	feedback repositories.FeedbackRepository,
+	logger logging.Logger,
+	tracer tracing.Tracer,
+) *ListFeedbackUseCase {
+	return &ListFeedbackUseCase{
+		feedback: feedback,
+		logger:   logger,
+		tracer:   tracer,
+	}
+}
+
+// Execute returns a page of the feedback matching the filter, newest first unless the filter orders it otherwise
+func (uc *ListFeedbackUseCase) Execute(ctx context.Context, filter entities.FeedbackFilter) (entities.Page[*entities.Feedback], error) {
+	spanCtx, span := uc.tracer.Start(ctx, "List Feedback")
+	defer span.End()
+
+	feedback, err := uc.feedback.List(spanCtx, filter)
+	if err != nil {
+		uc.logger.With(spanCtx).Err(err).Error("Failed to list feedback")
+		return entities.Page[*entities.Feedback]{}, err
+	}
+
+	return feedback, nil
+}
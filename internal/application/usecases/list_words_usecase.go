@@ -0,0 +1,43 @@
+package usecases
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/repositories"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+)
+
+// ListWordsUseCase lists the looked-up words for the operator dashboard
+type ListWordsUseCase struct {
+	stats  repositories.StatsRepository
+	logger logging.Logger
+	tracer tracing.Tracer
+}
+
+// NewListWordsUseCase creates a new list words use case instance
+func NewListWordsUseCase(
+	stats repositories.StatsRepository,
+	logger logging.Logger,
+	tracer tracing.Tracer,
+) *ListWordsUseCase {
+	return &ListWordsUseCase{
+		stats:  stats,
+		logger: logger,
+		tracer: tracer,
+	}
+}
+
+// Execute returns a page of the words matching the filter, most looked-up first unless the filter orders them otherwise
+func (uc *ListWordsUseCase) Execute(ctx context.Context, filter entities.WordStatFilter) (entities.Page[entities.WordStat], error) {
+	spanCtx, span := uc.tracer.Start(ctx, "List Words")
+	defer span.End()
+
+	words, err := uc.stats.ListWords(spanCtx, filter)
+	if err != nil {
+		uc.logger.With(spanCtx).Err(err).Error("Failed to list words")
+		return entities.Page[entities.WordStat]{}, err
+	}
+
+	return words, nil
+}
@@ -0,0 +1,60 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/repositories"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"time"
+)
+
+// ErrRecentlyFailed is returned for words the AI failed on within the negative cache TTL, they are
+// not sent to the AI again until it passes
+var ErrRecentlyFailed = errors.New("the AI failed on this word moments ago")
+
+// failureKeySuffix marks the cache entries of failed AI calls, it continues the key of the request
+// so purging the word removes them as well
+const failureKeySuffix = "|failed"
+
+// negativeCache remembers failed lookups for a short TTL, so spam, repeated typos and words the AI
+// keeps failing on don't reach the AI on every request. A zero TTL disables it.
+type negativeCache struct {
+	cache  repositories.CacheRepository
+	ttl    time.Duration
+	logger logging.Logger
+}
+
+// rememberAnswer keeps an answer rejecting the word, e.g. as not a German noun
+func (c negativeCache) rememberAnswer(ctx context.Context, request *entities.ArticleRequest, response *entities.ArticleResponse) {
+	c.set(ctx, request.CacheKey(), response)
+}
+
+// rememberFailure keeps the failure of the AI call for the word
+func (c negativeCache) rememberFailure(ctx context.Context, request *entities.ArticleRequest) {
+	c.set(ctx, request.CacheKey()+failureKeySuffix, entities.NewErrorResponse("Failed to process request"))
+}
+
+// failedRecently reports whether the AI failed on the word within the TTL
+func (c negativeCache) failedRecently(ctx context.Context, request *entities.ArticleRequest) bool {
+	if c.ttl <= 0 {
+		return false
+	}
+
+	_, ok, err := c.cache.Get(ctx, request.CacheKey()+failureKeySuffix)
+	if err != nil {
+		c.logger.With(ctx).Err(err).Warning("Failed to read negative cache")
+	}
+
+	return ok
+}
+
+func (c negativeCache) set(ctx context.Context, key string, response *entities.ArticleResponse) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	if err := c.cache.Set(ctx, key, response, c.ttl); err != nil {
+		c.logger.With(ctx).Err(err).Warning("Failed to write negative cache")
+	}
+}
@@ -0,0 +1,160 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/repositories"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+	"github.com/DeryabinSergey/germanarticlebot/libs/requestid"
+	"github.com/google/uuid"
+	"sync"
+	"time"
+)
+
+// ErrInvalidJob is returned for jobs that can never succeed, so they must not be retried
+var ErrInvalidJob = errors.New("invalid job")
+
+// JobHandler processes the jobs of a single type
+type JobHandler func(ctx context.Context, job *entities.Job) error
+
+// NewJob creates a job of the type with the JSON encoded payload, correlated with the current request
+func NewJob(ctx context.Context, jobType entities.JobType, payload interface{}) (*entities.Job, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s job payload: %w", jobType, err)
+	}
+
+	return &entities.Job{
+		ID:        uuid.NewString(),
+		Type:      jobType,
+		Payload:   data,
+		RequestID: requestid.FromContext(ctx),
+		CreatedAt: time.Now().UTC(),
+	}, nil
+}
+
+// ProcessJobUseCase dispatches background jobs to the handlers registered for their type
+type ProcessJobUseCase struct {
+	mu       sync.RWMutex
+	handlers map[entities.JobType]JobHandler
+	store    repositories.JobRepository
+	storeTTL time.Duration
+	logger   logging.Logger
+	tracer   tracing.Tracer
+}
+
+// NewProcessJobUseCase creates a new process job use case without handlers
+func NewProcessJobUseCase(logger logging.Logger, tracer tracing.Tracer) *ProcessJobUseCase {
+	return &ProcessJobUseCase{
+		handlers: make(map[entities.JobType]JobHandler),
+		logger:   logger,
+		tracer:   tracer,
+	}
+}
+
+// Register sets the handler of the job type, replacing a previously registered one
+func (uc *ProcessJobUseCase) Register(jobType entities.JobType, handler JobHandler) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	uc.handlers[jobType] = handler
+}
+
+// SetStore keeps the states of the processed jobs in the store for ttl, so clients can poll them
+func (uc *ProcessJobUseCase) SetStore(store repositories.JobRepository, ttl time.Duration) {
+	uc.store = store
+	uc.storeTTL = ttl
+}
+
+// Status returns the state of the job, false if it is unknown or has expired
+func (uc *ProcessJobUseCase) Status(ctx context.Context, id string) (*entities.JobStatus, bool, error) {
+	spanCtx, span := uc.tracer.Start(ctx, "Job Status")
+	defer span.End()
+
+	if uc.store == nil {
+		return nil, false, nil
+	}
+
+	status, ok, err := uc.store.Get(spanCtx, id)
+	if err != nil {
+		uc.logger.With(spanCtx).Field("jobId", id).Err(err).Error("Failed to read job status")
+		return nil, false, err
+	}
+
+	return status, ok, nil
+}
+
+// Execute runs the handler of the job, errors wrapping ErrInvalidJob are permanent
+func (uc *ProcessJobUseCase) Execute(ctx context.Context, job *entities.Job) error {
+	spanCtx, span := uc.tracer.Start(ctx, "Process Job")
+	defer span.End()
+
+	uc.mu.RLock()
+	handler, ok := uc.handlers[job.Type]
+	uc.mu.RUnlock()
+
+	tracker := uc.track(spanCtx, job)
+	if tracker != nil {
+		spanCtx = context.WithValue(spanCtx, jobTrackerKey{}, tracker)
+	}
+	if !ok {
+		err := fmt.Errorf("%w: no handler for job type %q", ErrInvalidJob, job.Type)
+		uc.finish(spanCtx, tracker, err)
+		return err
+	}
+
+	started := time.Now()
+	err := handler(spanCtx, job)
+	uc.finish(spanCtx, tracker, err)
+	entry := uc.logger.With(spanCtx).
+		Field("jobId", job.ID).
+		Field("jobType", job.Type).
+		Field("attempt", job.Attempt).
+		Field("createdBy", job.RequestID).
+		Field("duration", time.Since(started).String())
+	if err != nil {
+		entry.Err(err).Error("Job failed")
+		return err
+	}
+	entry.Info("Job processed")
+
+	return nil
+}
+
+// track marks the job as running in the store, nil without a store
+func (uc *ProcessJobUseCase) track(ctx context.Context, job *entities.Job) *jobTracker {
+	if uc.store == nil {
+		return nil
+	}
+
+	// Earlier attempts keep their progress and result until they are replaced
+	status, ok, err := uc.store.Get(ctx, job.ID)
+	if err != nil || !ok {
+		status = &entities.JobStatus{ID: job.ID, Type: job.Type, UserID: job.UserID, CreatedAt: job.CreatedAt}
+	}
+	tracker := &jobTracker{store: uc.store, ttl: uc.storeTTL, status: *status, logger: uc.logger}
+	tracker.update(ctx, func(status *entities.JobStatus) {
+		status.State, status.Error = entities.JobRunning, ""
+		status.Attempts = job.Attempt + 1
+	})
+
+	return tracker
+}
+
+// finish marks the job as done or failed, a failed job may still be retried by the queue
+func (uc *ProcessJobUseCase) finish(ctx context.Context, tracker *jobTracker, err error) {
+	if tracker == nil {
+		return
+	}
+
+	tracker.update(ctx, func(status *entities.JobStatus) {
+		if err != nil {
+			status.State, status.Error = entities.JobFailed, err.Error()
+			return
+		}
+		status.State = entities.JobDone
+	})
+}
@@ -0,0 +1,65 @@
+package usecases
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/repositories"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+)
+
+// PurgeCacheUseCase evicts cached answers, so wrong ones are generated again on the next lookup
+type PurgeCacheUseCase struct {
+	cache  repositories.CacheRepository
+	logger logging.Logger
+	tracer tracing.Tracer
+}
+
+// NewPurgeCacheUseCase creates a new purge cache use case instance
+func NewPurgeCacheUseCase(
+	cache repositories.CacheRepository,
+	logger logging.Logger,
+	tracer tracing.Tracer,
+) *PurgeCacheUseCase {
+	return &PurgeCacheUseCase{
+		cache:  cache,
+		logger: logger,
+		tracer: tracer,
+	}
+}
+
+// Execute evicts the answers of the words at every level, an empty language evicts all languages
+func (uc *PurgeCacheUseCase) Execute(ctx context.Context, words []string, language string) (int, error) {
+	spanCtx, span := uc.tracer.Start(ctx, "Purge Cache")
+	defer span.End()
+
+	removed := 0
+	for _, word := range words {
+		count, err := uc.cache.Delete(spanCtx, entities.WordCacheKey(word, language))
+		if err != nil {
+			uc.logger.With(spanCtx).Field("word", word).Err(err).Error("Failed to purge cached answers")
+			return removed, err
+		}
+		removed += count
+	}
+
+	uc.logger.With(spanCtx).Field("words", len(words)).Field("language", language).Field("removed", removed).Info("Purged cached answers")
+
+	return removed, nil
+}
+
+// PurgeAll evicts every cached answer
+func (uc *PurgeCacheUseCase) PurgeAll(ctx context.Context) (int, error) {
+	spanCtx, span := uc.tracer.Start(ctx, "Purge Cache")
+	defer span.End()
+
+	removed, err := uc.cache.Clear(spanCtx)
+	if err != nil {
+		uc.logger.With(spanCtx).Err(err).Error("Failed to clear the cache")
+		return 0, err
+	}
+
+	uc.logger.With(spanCtx).Field("removed", removed).Warning("Cleared the article cache")
+
+	return removed, nil
+}
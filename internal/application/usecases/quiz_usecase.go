@@ -0,0 +1,134 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/repositories"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/services"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+	"math/rand/v2"
+	"strings"
+	"time"
+)
+
+// ErrUnknownQuizNoun is returned for answers to nouns missing from the dictionary
+var ErrUnknownQuizNoun = errors.New("the noun is not in the dictionary")
+
+// QuizUseCase asks articles of dictionary nouns, answers are checked without AI calls
+type QuizUseCase struct {
+	dictionary services.DictionaryService
+	frequency  services.FrequencyService
+	activity   repositories.ActivityRepository
+	onEvent    LearningEventHandler
+	timezones  *TimezoneUseCase
+	logger     logging.Logger
+	tracer     tracing.Tracer
+}
+
+// NewQuizUseCase creates a new quiz use case instance
+func NewQuizUseCase(
+	dictionary services.DictionaryService,
+	frequency services.FrequencyService,
+	logger logging.Logger,
+	tracer tracing.Tracer,
+) *QuizUseCase {
+	return &QuizUseCase{
+		dictionary: dictionary,
+		frequency:  frequency,
+		logger:     logger,
+		tracer:     tracer,
+	}
+}
+
+// SetActivity records the answers of users into the learning activity, nil disables it
+func (uc *QuizUseCase) SetActivity(activity repositories.ActivityRepository) {
+	uc.activity = activity
+}
+
+// SetEventHandler publishes the recorded answers of users to the handler
+func (uc *QuizUseCase) SetEventHandler(handler LearningEventHandler) {
+	uc.onEvent = handler
+}
+
+// SetTimezones records the answers of users on the days of their time zones, nil records UTC days
+func (uc *QuizUseCase) SetTimezones(timezones *TimezoneUseCase) {
+	uc.timezones = timezones
+}
+
+// Execute returns a question about a random dictionary noun of the level, an empty level
+// picks from all nouns
+func (uc *QuizUseCase) Execute(ctx context.Context, level entities.Level) (*entities.QuizQuestion, error) {
+	spanCtx, span := uc.tracer.Start(ctx, "Quiz Question")
+	defer span.End()
+
+	entries, err := uc.dictionary.Entries(spanCtx)
+	if err != nil {
+		uc.logger.With(spanCtx).Err(err).Error("Failed to read dictionary")
+		return nil, err
+	}
+
+	questions := make([]entities.QuizQuestion, 0, len(entries))
+	for _, entry := range entries {
+		question := entities.QuizQuestion{Noun: entry.Noun, Article: entry.Article}
+		if rank, found, err := uc.frequency.Rank(spanCtx, entry.Noun); err == nil && found {
+			question.Level = entities.LevelForRank(rank)
+		}
+		if level == "" || question.Level == level {
+			questions = append(questions, question)
+		}
+	}
+	if len(questions) == 0 {
+		if level != "" {
+			return nil, fmt.Errorf("the dictionary has no nouns of level %s", level)
+		}
+		return nil, errors.New("the dictionary is empty")
+	}
+
+	return &questions[rand.IntN(len(questions))], nil
+}
+
+// Answer checks the answer to the question about the noun and records it for the user of the context
+func (uc *QuizUseCase) Answer(ctx context.Context, noun string, answer string) (*entities.QuizAnswer, error) {
+	spanCtx, span := uc.tracer.Start(ctx, "Quiz Answer")
+	defer span.End()
+
+	article, found, err := uc.dictionary.LookupArticle(spanCtx, noun)
+	if err != nil {
+		uc.logger.With(spanCtx).Err(err).Error("Failed to look up quiz noun")
+		return nil, err
+	}
+	if !found {
+		return nil, ErrUnknownQuizNoun
+	}
+
+	question := entities.QuizQuestion{Noun: noun, Article: article}
+	result := &entities.QuizAnswer{
+		Noun:       noun,
+		Article:    article,
+		Answer:     strings.ToLower(strings.TrimSpace(answer)),
+		Correct:    question.Check(answer),
+		AnsweredAt: time.Now().UTC(),
+	}
+
+	if identity := IdentityFromContext(spanCtx); uc.activity != nil && identity != nil && identity.UserID != 0 {
+		if uc.timezones != nil {
+			result.AnsweredAt = uc.timezones.Now(spanCtx, identity.UserID)
+		}
+		if err := uc.activity.RecordQuizAnswer(spanCtx, identity.UserID, result); err != nil {
+			uc.logger.With(spanCtx).Err(err).Warning("Failed to record quiz activity")
+		} else if uc.onEvent != nil {
+			uc.onEvent(spanCtx, entities.LearningEvent{
+				Type:    entities.LearningEventQuizAnswer,
+				UserID:  identity.UserID,
+				Word:    noun,
+				Correct: result.Correct,
+				At:      result.AnsweredAt,
+			})
+		}
+	}
+
+	return result, nil
+}
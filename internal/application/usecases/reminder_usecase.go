@@ -0,0 +1,176 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/repositories"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/services"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+	"time"
+)
+
+// JobTypeReminders is the background job sending the due practice reminders
+const JobTypeReminders entities.JobType = "reminders.send"
+
+const (
+	// ReminderSnooze is how long the snooze button of a reminder postpones it
+	ReminderSnooze = time.Hour
+	// reminderBatch limits the reminders sent by a job, the rest are sent by the next one
+	reminderBatch = 1000
+)
+
+var (
+	// ErrInvalidReminderTime is returned for reminder times other than HH:MM
+	ErrInvalidReminderTime = errors.New("the reminder time must be HH:MM, like 19:00")
+	// ErrInvalidTimezone is returned for unknown time zones
+	ErrInvalidTimezone = errors.New("the time zone must be an IANA time zone, like Europe/Berlin")
+	// ErrReminderNotFound is returned for users without a reminder
+	ErrReminderNotFound = errors.New("reminder not found")
+)
+
+// RemindersSend is the payload of the JobTypeReminders job
+type RemindersSend struct {
+	// At is the time the reminders are due at, the time the job was submitted
+	At time.Time `json:"at"`
+}
+
+// ReminderUseCase schedules the daily practice reminders of the users. The due reminders are sent by
+// a job submitted every few minutes by a scheduler, so a reminder arrives within that interval.
+type ReminderUseCase struct {
+	reminders repositories.ReminderRepository
+	timezones *TimezoneUseCase
+	jobs      services.JobQueue
+	logger    logging.Logger
+	tracer    tracing.Tracer
+}
+
+// NewReminderUseCase creates a new reminder use case instance
+func NewReminderUseCase(
+	reminders repositories.ReminderRepository,
+	timezones *TimezoneUseCase,
+	jobs services.JobQueue,
+	logger logging.Logger,
+	tracer tracing.Tracer,
+) *ReminderUseCase {
+	return &ReminderUseCase{
+		reminders: reminders,
+		timezones: timezones,
+		jobs:      jobs,
+		logger:    logger,
+		tracer:    tracer,
+	}
+}
+
+// Get returns the reminder of the user, nil if none is set
+func (uc *ReminderUseCase) Get(ctx context.Context, userID int64) (*entities.Reminder, error) {
+	spanCtx, span := uc.tracer.Start(ctx, "Get Reminder")
+	defer span.End()
+
+	reminder, err := uc.reminders.Get(spanCtx, userID)
+	if err != nil {
+		uc.logger.With(spanCtx).Err(err).Field("userId", userID).Error("Failed to get reminder")
+		return nil, err
+	}
+
+	return reminder, nil
+}
+
+// Set validates and saves the reminder of the user at its local time, replacing the former one. An
+// empty time zone is the one of the user, a given one becomes the time zone of the user. The first
+// reminder is sent at the next occurrence of the time.
+func (uc *ReminderUseCase) Set(ctx context.Context, reminder *entities.Reminder) (*entities.Reminder, error) {
+	spanCtx, span := uc.tracer.Start(ctx, "Set Reminder")
+	defer span.End()
+
+	clock, err := time.Parse("15:04", reminder.Time)
+	if err != nil {
+		return nil, ErrInvalidReminderTime
+	}
+	if reminder.Timezone == "" {
+		reminder.Timezone = uc.timezones.Location(spanCtx, reminder.UserID).String()
+	} else if _, err := uc.timezones.Set(spanCtx, reminder.UserID, reminder.Timezone); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	reminder.Time = clock.Format("15:04")
+	reminder.CreatedAt = now
+	reminder.NextAt = reminder.Next(now)
+	if err := uc.reminders.Save(spanCtx, reminder); err != nil {
+		uc.logger.With(spanCtx).Err(err).Field("userId", reminder.UserID).Error("Failed to save reminder")
+		return nil, err
+	}
+	uc.logger.With(spanCtx).Field("userId", reminder.UserID).Field("time", reminder.Time).Field("timezone", reminder.Timezone).Info("Reminder set")
+
+	return reminder, nil
+}
+
+// Stop removes the reminder of the user, users without one are ignored
+func (uc *ReminderUseCase) Stop(ctx context.Context, userID int64) error {
+	spanCtx, span := uc.tracer.Start(ctx, "Stop Reminder")
+	defer span.End()
+
+	if err := uc.reminders.Delete(spanCtx, userID); err != nil {
+		uc.logger.With(spanCtx).Err(err).Field("userId", userID).Error("Failed to delete reminder")
+		return err
+	}
+
+	return nil
+}
+
+// Snooze sends the reminder of the user again after ReminderSnooze, the next day's reminder is kept
+// when it's due earlier
+func (uc *ReminderUseCase) Snooze(ctx context.Context, userID int64) (*entities.Reminder, error) {
+	spanCtx, span := uc.tracer.Start(ctx, "Snooze Reminder")
+	defer span.End()
+
+	reminder, err := uc.reminders.Get(spanCtx, userID)
+	if err != nil {
+		uc.logger.With(spanCtx).Err(err).Field("userId", userID).Error("Failed to get reminder")
+		return nil, err
+	}
+	if reminder == nil {
+		return nil, ErrReminderNotFound
+	}
+
+	if snoozed := time.Now().UTC().Add(ReminderSnooze); snoozed.Before(reminder.NextAt) {
+		reminder.NextAt = snoozed
+	}
+	if err := uc.reminders.Save(spanCtx, reminder); err != nil {
+		uc.logger.With(spanCtx).Err(err).Field("userId", userID).Error("Failed to save reminder")
+		return nil, err
+	}
+
+	return reminder, nil
+}
+
+// Submit enqueues the job sending the reminders due now
+func (uc *ReminderUseCase) Submit(ctx context.Context) (*entities.Job, error) {
+	spanCtx, span := uc.tracer.Start(ctx, "Submit Reminders")
+	defer span.End()
+
+	job, err := NewJob(spanCtx, JobTypeReminders, RemindersSend{At: time.Now().UTC()})
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.jobs.Enqueue(spanCtx, job); err != nil {
+		uc.logger.With(spanCtx).Err(err).Error("Failed to enqueue reminders")
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// Due returns the reminders due at the time, the longest due first
+func (uc *ReminderUseCase) Due(ctx context.Context, at time.Time) ([]entities.Reminder, error) {
+	return uc.reminders.Due(ctx, at, reminderBatch)
+}
+
+// Sent schedules the reminder for its next day, it's also called for reminders that failed to be sent
+// so they aren't sent again by every job
+func (uc *ReminderUseCase) Sent(ctx context.Context, reminder entities.Reminder) error {
+	reminder.NextAt = reminder.Next(time.Now().UTC())
+	return uc.reminders.Save(ctx, &reminder)
+}
@@ -0,0 +1,43 @@
+package usecases
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/libs/logger"
+)
+
+type requestContextKey struct{}
+
+// WithRequestContext returns the context of a request with its metadata, set by the adapters
+func WithRequestContext(ctx context.Context, request *entities.RequestContext) context.Context {
+	return context.WithValue(ctx, requestContextKey{}, request)
+}
+
+// RequestContextFromContext returns the metadata of the request, nil outside of a request like in background jobs
+func RequestContextFromContext(ctx context.Context) *entities.RequestContext {
+	request, _ := ctx.Value(requestContextKey{}).(*entities.RequestContext)
+	return request
+}
+
+// requestAdapter returns the adapter of the request, empty outside of a request
+func requestAdapter(ctx context.Context) entities.Adapter {
+	if request := RequestContextFromContext(ctx); request != nil {
+		return request.Adapter
+	}
+
+	return ""
+}
+
+// logRequest returns the log entry of the context with the metadata of its request
+func logRequest(ctx context.Context, l logging.Logger) *logger.Entry {
+	entry := l.With(ctx)
+	if request := RequestContextFromContext(ctx); request != nil {
+		entry = entry.Field("adapter", request.Adapter).Field("subject", request.Subject).Field("locale", request.Locale)
+		if request.Tenant != "" {
+			entry = entry.Field("tenant", request.Tenant)
+		}
+	}
+
+	return entry
+}
@@ -0,0 +1,132 @@
+package usecases
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/services"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"slices"
+	"strings"
+)
+
+// maxSuggestions is the number of did-you-mean suggestions of a failed lookup
+const maxSuggestions = 3
+
+// responseAnnotator adds the reference data of the dictionary and the frequency list to AI answers
+type responseAnnotator struct {
+	frequency  services.FrequencyService
+	dictionary services.DictionaryService
+	logger     logging.Logger
+}
+
+// annotate flags answers with several interpretations, lists the gender variants and sets
+// the frequency rank and level of every interpretation found in the frequency list
+func (a responseAnnotator) annotate(ctx context.Context, response *entities.ArticleResponse) {
+	response.Disambiguation = len(response.Data) > 1
+	response.GenderVariants = a.genderVariants(ctx, response)
+
+	for i, info := range response.Data {
+		rank, found, err := a.frequency.Rank(ctx, info.Noun())
+		if err != nil {
+			a.logger.With(ctx).Err(err).Field("word", info.WordWithArticle).Warning("Failed to look up noun frequency")
+			continue
+		}
+		if found {
+			response.Data[i].FrequencyRank = rank
+			response.Data[i].Level = entities.LevelForRank(rank)
+		}
+	}
+}
+
+// genderVariants joins the interpretations of different articles with the known variants of the
+// dictionary, nil unless the meaning of the noun depends on at least two articles
+func (a responseAnnotator) genderVariants(ctx context.Context, response *entities.ArticleResponse) []entities.GenderVariant {
+	var variants []entities.GenderVariant
+	var nouns []string
+	for _, info := range response.Data {
+		article := info.Article()
+		if article == "" {
+			continue
+		}
+		if noun := strings.ToLower(info.Noun()); !slices.Contains(nouns, noun) {
+			nouns = append(nouns, noun)
+		}
+
+		// The AI translations are in the answer language, so they win over the dictionary glosses
+		index := slices.IndexFunc(variants, func(v entities.GenderVariant) bool { return v.Article == article })
+		switch {
+		case index < 0:
+			variants = append(variants, entities.GenderVariant{Article: article, Meaning: info.Translation})
+		case info.Translation != "" && !strings.Contains(variants[index].Meaning, info.Translation):
+			variants[index].Meaning = strings.TrimPrefix(variants[index].Meaning+", "+info.Translation, ", ")
+		}
+	}
+
+	for _, noun := range nouns {
+		known, err := a.dictionary.Variants(ctx, noun)
+		if err != nil {
+			a.logger.With(ctx).Err(err).Field("word", noun).Warning("Failed to look up gender variants")
+			continue
+		}
+		for _, variant := range known {
+			if !slices.ContainsFunc(variants, func(v entities.GenderVariant) bool { return v.Article == variant.Article }) {
+				variants = append(variants, variant)
+			}
+		}
+	}
+
+	if len(variants) < 2 {
+		return nil
+	}
+
+	return variants
+}
+
+// spelling returns the request for the umlaut spelling of an ASCII word, "Haeuser" for "Häuser", when the
+// dictionary knows the restored noun and not the typed one, so Duett or Silhouette stay as typed
+func (a responseAnnotator) spelling(ctx context.Context, request *entities.ArticleRequest) *entities.ArticleRequest {
+	restored, ok := entities.UmlautSpelling(request.Word)
+	if !ok {
+		return request
+	}
+	if _, found, err := a.dictionary.LookupArticle(ctx, restored); err != nil || !found {
+		return request
+	}
+	if _, found, err := a.dictionary.LookupArticle(ctx, request.Word); err != nil || found {
+		return request
+	}
+
+	corrected := *request
+	corrected.Word = restored
+	return &corrected
+}
+
+// suggest completes the suggestions of a failed lookup, the AI suggestions come first and the closest
+// dictionary nouns are used when the AI has none and the word isn't one of another language
+func (a responseAnnotator) suggest(ctx context.Context, request *entities.ArticleRequest, response *entities.ArticleResponse) {
+	word := strings.TrimSpace(request.Word)
+	suggestions := make([]string, 0, maxSuggestions)
+	for _, suggestion := range response.Suggestions {
+		suggestion = strings.TrimSpace(suggestion)
+		if suggestion == "" || strings.EqualFold(suggestion, word) ||
+			slices.ContainsFunc(suggestions, func(s string) bool { return strings.EqualFold(s, suggestion) }) {
+			continue
+		}
+		if suggestions = append(suggestions, suggestion); len(suggestions) == maxSuggestions {
+			break
+		}
+	}
+
+	if len(suggestions) == 0 && !response.IsForeignWord() {
+		known, err := a.dictionary.Suggest(ctx, word, maxSuggestions)
+		if err != nil {
+			a.logger.With(ctx).Err(err).Field("word", word).Warning("Failed to look up suggestions")
+		}
+		suggestions = append(suggestions, known...)
+	}
+
+	response.Suggestions = nil
+	if len(suggestions) > 0 {
+		response.Suggestions = suggestions
+	}
+}
@@ -0,0 +1,112 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/repositories"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/services"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+	"time"
+)
+
+// JobTypeRetentionSweep is the background job purging the user data older than the retention period
+const JobTypeRetentionSweep entities.JobType = "retention.sweep"
+
+// ErrRetentionDisabled is returned when sweeping without a retention period
+var ErrRetentionDisabled = errors.New("data retention is disabled, set DATA_RETENTION to sweep")
+
+// RetentionSweepUseCase purges the ratings and the failed Telegram updates older than the retention period
+// and the learning activity of the users inactive for longer
+type RetentionSweepUseCase struct {
+	feedback    repositories.FeedbackRepository
+	activity    repositories.ActivityRepository
+	deadLetters repositories.DeadLetterRepository
+	jobs        services.JobQueue
+	retention   time.Duration
+	logger      logging.Logger
+	tracer      tracing.Tracer
+}
+
+// NewRetentionSweepUseCase creates a new retention sweep use case, a zero retention keeps the data
+func NewRetentionSweepUseCase(
+	feedback repositories.FeedbackRepository,
+	activity repositories.ActivityRepository,
+	deadLetters repositories.DeadLetterRepository,
+	jobs services.JobQueue,
+	retention time.Duration,
+	logger logging.Logger,
+	tracer tracing.Tracer,
+) *RetentionSweepUseCase {
+	return &RetentionSweepUseCase{
+		feedback:    feedback,
+		activity:    activity,
+		deadLetters: deadLetters,
+		jobs:        jobs,
+		retention:   retention,
+		logger:      logger,
+		tracer:      tracer,
+	}
+}
+
+// Submit enqueues a sweep of the data older than the retention period and returns its job. The cutoff
+// is fixed when submitting, so a retried sweep purges the same records.
+func (uc *RetentionSweepUseCase) Submit(ctx context.Context) (*entities.Job, error) {
+	spanCtx, span := uc.tracer.Start(ctx, "Submit Retention Sweep")
+	defer span.End()
+
+	if uc.retention <= 0 {
+		return nil, ErrRetentionDisabled
+	}
+
+	job, err := NewJob(spanCtx, JobTypeRetentionSweep, entities.RetentionSweep{Before: time.Now().UTC().Add(-uc.retention)})
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.jobs.Enqueue(spanCtx, job); err != nil {
+		uc.logger.With(spanCtx).Err(err).Error("Failed to enqueue retention sweep")
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// Handle purges the ratings created, the updates failed and the activity of the users last active before
+// the cutoff of the sweep
+func (uc *RetentionSweepUseCase) Handle(ctx context.Context, job *entities.Job) error {
+	spanCtx, span := uc.tracer.Start(ctx, "Retention Sweep")
+	defer span.End()
+
+	var sweep entities.RetentionSweep
+	if err := json.Unmarshal(job.Payload, &sweep); err != nil {
+		return fmt.Errorf("%w: failed to decode retention sweep: %v", ErrInvalidJob, err)
+	}
+	if sweep.Before.IsZero() {
+		return fmt.Errorf("%w: retention sweep without a cutoff", ErrInvalidJob)
+	}
+
+	var (
+		result entities.RetentionSweepResult
+		err    error
+	)
+	if result.Feedback, err = uc.feedback.DeleteBefore(spanCtx, sweep.Before); err != nil {
+		return fmt.Errorf("failed to purge old feedback: %w", err)
+	}
+	ReportJobProgress(spanCtx, 1, 3)
+	if result.Activity, err = uc.activity.DeleteInactive(spanCtx, sweep.Before); err != nil {
+		return fmt.Errorf("failed to purge inactive users: %w", err)
+	}
+	ReportJobProgress(spanCtx, 2, 3)
+	if result.DeadLetters, err = uc.deadLetters.DeleteBefore(spanCtx, sweep.Before); err != nil {
+		return fmt.Errorf("failed to purge old dead letters: %w", err)
+	}
+	ReportJobProgress(spanCtx, 3, 3)
+
+	uc.logger.With(spanCtx).Field("before", sweep.Before).Field("feedback", result.Feedback).Field("activity", result.Activity).Field("deadLetters", result.DeadLetters).Info("Retention sweep finished")
+	SetJobResult(spanCtx, result)
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/repositories"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// MinSearchQuery is the shortest query the history is searched for
+	MinSearchQuery = 2
+	// MaxSearchResults limits the words found by a search
+	MaxSearchResults = 50
+)
+
+// ErrSearchQueryTooShort is returned for queries shorter than MinSearchQuery
+var ErrSearchQueryTooShort = errors.New("the search query must have at least 2 letters")
+
+// SearchHistoryUseCase finds the words a user looked up or met in the quiz by the word or its translation,
+// tolerating typos and missing umlauts
+type SearchHistoryUseCase struct {
+	activity repositories.ActivityRepository
+	logger   logging.Logger
+	tracer   tracing.Tracer
+}
+
+// NewSearchHistoryUseCase creates a new history search use case instance
+func NewSearchHistoryUseCase(
+	activity repositories.ActivityRepository,
+	logger logging.Logger,
+	tracer tracing.Tracer,
+) *SearchHistoryUseCase {
+	return &SearchHistoryUseCase{
+		activity: activity,
+		logger:   logger,
+		tracer:   tracer,
+	}
+}
+
+// Execute returns at most limit words of the user matching the query, the best matches first
+func (uc *SearchHistoryUseCase) Execute(ctx context.Context, userID int64, query string, limit int) ([]entities.WordMatch, error) {
+	spanCtx, span := uc.tracer.Start(ctx, "Search History")
+	defer span.End()
+
+	query = strings.TrimSpace(query)
+	if utf8.RuneCountInString(query) < MinSearchQuery {
+		return nil, ErrSearchQueryTooShort
+	}
+
+	matches, err := uc.activity.SearchWords(spanCtx, userID, query, min(max(limit, 1), MaxSearchResults))
+	if err != nil {
+		uc.logger.With(spanCtx).Err(err).Field("userId", userID).Error("Failed to search the history")
+		return nil, err
+	}
+	if matches == nil {
+		matches = []entities.WordMatch{}
+	}
+
+	return matches, nil
+}
@@ -0,0 +1,221 @@
+package usecases
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/repositories"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/services"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"time"
+)
+
+// StreamArticleUseCase determines German articles emitting the parts of the answer progressively
+type StreamArticleUseCase struct {
+	aiService  services.AIService
+	annotator  responseAnnotator
+	cache      repositories.CacheRepository
+	cacheTTL   time.Duration
+	negative   negativeCache
+	curated    curatedEntries
+	moderation exampleModeration
+	stats      repositories.StatsRepository
+	budget     *BudgetGuard
+	ai         *Degradation
+	logger     logging.Logger
+	tracer     tracing.Tracer
+}
+
+// NewStreamArticleUseCase creates a new stream article use case instance
+func NewStreamArticleUseCase(
+	aiService services.AIService,
+	frequency services.FrequencyService,
+	dictionary services.DictionaryService,
+	cache repositories.CacheRepository,
+	cacheTTL time.Duration,
+	stats repositories.StatsRepository,
+	budget *BudgetGuard,
+	logger logging.Logger,
+	tracer tracing.Tracer,
+) *StreamArticleUseCase {
+	return &StreamArticleUseCase{
+		aiService:  aiService,
+		annotator:  responseAnnotator{frequency: frequency, dictionary: dictionary, logger: logger},
+		cache:      cache,
+		cacheTTL:   cacheTTL,
+		negative:   negativeCache{cache: cache, logger: logger},
+		curated:    curatedEntries{logger: logger},
+		moderation: exampleModeration{logger: logger},
+		stats:      stats,
+		budget:     budget,
+		logger:     logger,
+		tracer:     tracer,
+	}
+}
+
+// SetNegativeCacheTTL keeps answers rejecting a word and AI failures for ttl, zero disables it
+func (uc *StreamArticleUseCase) SetNegativeCacheTTL(ttl time.Duration) {
+	uc.negative.ttl = ttl
+}
+
+// SetCuratedEntries answers the nouns verified by the operators from their entries, nil disables them
+func (uc *StreamArticleUseCase) SetCuratedEntries(entries repositories.DictionaryRepository) {
+	uc.curated.entries = entries
+}
+
+// SetModerator screens the generated examples, regenerating or dropping objectionable ones, nil disables it
+func (uc *StreamArticleUseCase) SetModerator(moderator services.ContentModerator) {
+	uc.moderation.moderator = moderator
+}
+
+// SetDegradation answers from the dictionary while the state machine reports the AI down, nil disables it
+func (uc *StreamArticleUseCase) SetDegradation(ai *Degradation) {
+	uc.ai = ai
+}
+
+// Execute emits article and translation events first, then the examples of every interpretation
+// and a final done event. Cached answers are emitted at once, and AI
+// services without streaming support are emitted after the answer is generated.
+func (uc *StreamArticleUseCase) Execute(ctx context.Context, request *entities.ArticleRequest, emit func(entities.StreamEvent) error) error {
+	spanCtx, span := uc.tracer.Start(ctx, "Stream Article Request")
+	defer span.End()
+	request = uc.annotator.spelling(spanCtx, tenantRequest(spanCtx, request))
+
+	if !request.IsValid() {
+		return emitResponse(entities.NewErrorResponse("Word cannot be empty"), emit, true)
+	}
+
+	// Input that can't be a German word is rejected before it reaches the cache and the AI
+	if problem := entities.CheckWord(request.Word); problem != "" {
+		uc.logger.With(spanCtx).Field("word", request.Word).Field("problem", problem).Sampled().Info("Rejected article request")
+		return emitResponse(entities.NewErrorResponse(problem.Explanation(request.Language)), emit, true)
+	}
+
+	logRequest(spanCtx, uc.logger).Field("word", request.Word).Field("language", request.Language).Sampled().Info("Streaming article request")
+	uc.stats.RecordLookup(spanCtx, request.Word, request.Language, requestAdapter(spanCtx))
+
+	cached, ok, err := uc.cache.Get(spanCtx, request.CacheKey())
+	if err != nil {
+		uc.logger.With(spanCtx).Err(err).Warning("Failed to read article cache")
+	}
+	uc.stats.RecordCacheLookup(spanCtx, ok)
+	span.SetAttributes(attribute.Bool("cache.hit", ok))
+	if ok {
+		return emitResponse(cached.FromCache(), emit, true)
+	}
+
+	curated := uc.curated.get(spanCtx, request.Word)
+	if response, ok := curated.Answer(request); ok {
+		span.SetAttributes(attribute.Bool("curated", true))
+		uc.annotator.annotate(spanCtx, response)
+		return emitResponse(response, emit, true)
+	}
+
+	// Uncached words get the dictionary article only while the AI budget is spent
+	if uc.budget.Exceeded(spanCtx) {
+		span.SetAttributes(attribute.Bool("budget.exceeded", true))
+		return emitResponse(dictionaryAnswer(spanCtx, uc.annotator.dictionary, request, entities.DegradationBudgetExceeded), emit, true)
+	}
+
+	if uc.negative.failedRecently(spanCtx, request) {
+		span.SetAttributes(attribute.Bool("cache.negative", true))
+		return emitResponse(dictionaryAnswer(spanCtx, uc.annotator.dictionary, request, entities.DegradationAIUnavailable), emit, true)
+	}
+
+	// While the AI is down uncached words are answered from the dictionary without waiting for it
+	if !uc.ai.Allow(spanCtx) {
+		span.SetAttributes(attribute.Bool("ai.degraded", true))
+		return emitResponse(dictionaryAnswer(spanCtx, uc.annotator.dictionary, request, entities.DegradationAIUnavailable), emit, true)
+	}
+
+	var (
+		response *entities.ArticleResponse
+		streamed bool
+	)
+	// The answers of curated nouns are corrected before they are emitted, so they aren't streamed
+	if streaming, ok := uc.aiService.(services.StreamingAIService); ok && curated == nil {
+		response, err = streaming.StreamArticleInfo(spanCtx, request, emit)
+		streamed = true
+	} else {
+		response, err = uc.aiService.GenerateArticleInfo(spanCtx, request)
+	}
+	uc.stats.RecordAICall(spanCtx, err != nil)
+	uc.ai.Record(spanCtx, aiOutage(err))
+	if err != nil {
+		uc.negative.rememberFailure(spanCtx, request)
+		return emitResponse(dictionaryAnswer(spanCtx, uc.annotator.dictionary, request, entities.DegradationAIUnavailable), emit, true)
+	}
+
+	// The examples are emitted after the answer is complete, so objectionable ones never reach the client
+	uc.moderation.screen(spanCtx, response, func(ctx context.Context) (*entities.ArticleResponse, error) {
+		regenerated, err := uc.aiService.GenerateArticleInfo(ctx, request)
+		uc.stats.RecordAICall(ctx, err != nil)
+		return regenerated, err
+	})
+
+	if !response.Success && curated != nil {
+		response = curated.Response(request.Language)
+	}
+	curated.Correct(response, request.Language)
+
+	// Complete successful answers are cached for the cache TTL and rejections for the negative TTL,
+	// partial answers are retried on the next request
+	if response.Success {
+		uc.annotator.annotate(spanCtx, response)
+		if !response.Partial {
+			if err := uc.cache.Set(spanCtx, request.CacheKey(), response, uc.cacheTTL); err != nil {
+				uc.logger.With(spanCtx).Err(err).Warning("Failed to write article cache")
+			}
+		}
+	} else {
+		uc.annotator.suggest(spanCtx, request, response)
+		uc.negative.rememberAnswer(spanCtx, request, response)
+	}
+
+	return emitResponse(response, emit, !streamed)
+}
+
+// emitResponse emits the events of the complete answer, article and translation events
+// are skipped when they were already streamed
+func emitResponse(response *entities.ArticleResponse, emit func(entities.StreamEvent) error, withPartial bool) error {
+	if !response.Success {
+		if err := emit(entities.StreamEvent{Type: entities.StreamEventError, Error: response.Error, Suggestions: response.Suggestions, DetectedLanguage: response.DetectedLanguage}); err != nil {
+			return err
+		}
+	}
+
+	for i, info := range response.Data {
+		events := []entities.StreamEvent{
+			{Type: entities.StreamEventArticle, Index: i, Value: info.WordWithArticle},
+			{Type: entities.StreamEventTranslation, Index: i, Value: info.Translation},
+		}
+		if !withPartial {
+			events = nil
+		}
+		examples := info.Example
+		events = append(events, entities.StreamEvent{
+			Type:          entities.StreamEventExamples,
+			Index:         i,
+			Plural:        info.Plural,
+			Examples:      &examples,
+			FrequencyRank: info.FrequencyRank,
+			Level:         info.Level,
+		})
+
+		for _, event := range events {
+			if err := emit(event); err != nil {
+				return err
+			}
+		}
+	}
+
+	return emit(entities.StreamEvent{
+		Type:        entities.StreamEventDone,
+		Partial:     response.Partial,
+		Degraded:    response.Degraded,
+		Notice:      response.Notice,
+		Source:      response.Source,
+		GeneratedAt: response.GeneratedAt,
+	})
+}
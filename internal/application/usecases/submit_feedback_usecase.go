@@ -0,0 +1,75 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/repositories"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+	"github.com/google/uuid"
+	"time"
+)
+
+// SubmitFeedbackUseCase stores user ratings of answers together with the rated answer
+type SubmitFeedbackUseCase struct {
+	feedback repositories.FeedbackRepository
+	cache    repositories.CacheRepository
+	logger   logging.Logger
+	tracer   tracing.Tracer
+}
+
+// NewSubmitFeedbackUseCase creates a new submit feedback use case instance
+func NewSubmitFeedbackUseCase(
+	feedback repositories.FeedbackRepository,
+	cache repositories.CacheRepository,
+	logger logging.Logger,
+	tracer tracing.Tracer,
+) *SubmitFeedbackUseCase {
+	return &SubmitFeedbackUseCase{
+		feedback: feedback,
+		cache:    cache,
+		logger:   logger,
+		tracer:   tracer,
+	}
+}
+
+// Execute stores the verdict, the rated answer is taken from the cache without a new AI call.
+// A downvoted answer is evicted from the cache, so the next lookup generates it again.
+func (uc *SubmitFeedbackUseCase) Execute(ctx context.Context, request *entities.ArticleRequest, verdict entities.Verdict, userID int64) error {
+	spanCtx, span := uc.tracer.Start(ctx, "Submit Feedback")
+	defer span.End()
+
+	if !request.IsValid() || !verdict.Valid() {
+		return fmt.Errorf("invalid feedback for word %q with verdict %q", request.Word, verdict)
+	}
+
+	answer, _, err := uc.cache.Get(spanCtx, request.CacheKey())
+	if err != nil {
+		uc.logger.With(spanCtx).Err(err).Warning("Failed to read rated answer from cache")
+	}
+
+	feedback := &entities.Feedback{
+		ID:        uuid.NewString(),
+		Word:      request.Word,
+		Language:  request.Language,
+		Verdict:   verdict,
+		UserID:    userID,
+		Answer:    answer,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := uc.feedback.Save(spanCtx, feedback); err != nil {
+		uc.logger.With(spanCtx).Err(err).Field("word", request.Word).Error("Failed to save feedback")
+		return err
+	}
+
+	if verdict == entities.VerdictDown && answer != nil {
+		if _, err := uc.cache.Delete(spanCtx, request.CacheKey()); err != nil {
+			uc.logger.With(spanCtx).Field("word", request.Word).Err(err).Warning("Failed to evict downvoted answer")
+		}
+	}
+
+	uc.logger.With(spanCtx).Field("word", request.Word).Field("language", request.Language).Field("verdict", verdict).Info("Feedback submitted")
+
+	return nil
+}
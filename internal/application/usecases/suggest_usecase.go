@@ -0,0 +1,115 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/repositories"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/services"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// MinSuggestPrefix is the shortest prefix suggestions are made for
+	MinSuggestPrefix = 2
+	// MaxSuggestions limits the suggestions of a prefix
+	MaxSuggestions = 20
+)
+
+// ErrSuggestPrefixTooShort is returned for prefixes shorter than MinSuggestPrefix
+var ErrSuggestPrefixTooShort = errors.New("the prefix must have at least 2 letters")
+
+// SuggestUseCase completes partial words to known nouns with their articles without AI calls. Dictionary
+// nouns come first; the looked-up words of the prefix fill the rest when their answers are cached.
+type SuggestUseCase struct {
+	dictionary services.DictionaryService
+	stats      repositories.StatsRepository
+	lookup     *DetermineArticleUseCase
+	logger     logging.Logger
+	tracer     tracing.Tracer
+}
+
+// NewSuggestUseCase creates a new suggest use case instance
+func NewSuggestUseCase(
+	dictionary services.DictionaryService,
+	stats repositories.StatsRepository,
+	lookup *DetermineArticleUseCase,
+	logger logging.Logger,
+	tracer tracing.Tracer,
+) *SuggestUseCase {
+	return &SuggestUseCase{
+		dictionary: dictionary,
+		stats:      stats,
+		lookup:     lookup,
+		logger:     logger,
+		tracer:     tracer,
+	}
+}
+
+// Execute returns at most limit nouns starting with the prefix. The looked-up words are checked in the
+// cache of the language, most looked-up first.
+func (uc *SuggestUseCase) Execute(ctx context.Context, prefix, language string, limit int) ([]entities.WordSuggestion, error) {
+	spanCtx, span := uc.tracer.Start(ctx, "Suggest Words")
+	defer span.End()
+
+	prefix = strings.TrimSpace(prefix)
+	if utf8.RuneCountInString(prefix) < MinSuggestPrefix {
+		return nil, ErrSuggestPrefixTooShort
+	}
+	limit = min(max(limit, 1), MaxSuggestions)
+
+	suggestions := make([]entities.WordSuggestion, 0, limit)
+	seen := make(map[string]bool)
+	entries, err := uc.dictionary.Complete(spanCtx, prefix, limit)
+	if err != nil {
+		uc.logger.With(spanCtx).Err(err).Field("prefix", prefix).Warning("Failed to complete the prefix in the dictionary")
+	}
+	for _, entry := range entries {
+		seen[strings.ToLower(entry.Noun)] = true
+		suggestions = append(suggestions, entities.WordSuggestion{Noun: entry.Noun, Article: entry.Article})
+	}
+	if len(suggestions) >= limit {
+		return suggestions, nil
+	}
+
+	words, err := uc.stats.ListWords(spanCtx, entities.WordStatFilter{Prefix: prefix, Page: entities.PageRequest{Limit: limit}})
+	if err != nil {
+		uc.logger.With(spanCtx).Err(err).Field("prefix", prefix).Warning("Failed to list the looked-up words of the prefix")
+		return suggestions, nil
+	}
+	for _, word := range words.Items {
+		if len(suggestions) >= limit {
+			break
+		}
+		if seen[strings.ToLower(word.Word)] {
+			continue
+		}
+		// The answer may be of another form of the word, like the singular of a plural
+		suggestion, ok := uc.cachedSuggestion(spanCtx, word.Word, language)
+		if key := strings.ToLower(suggestion.Noun); ok && !seen[key] && strings.HasPrefix(key, strings.ToLower(prefix)) {
+			seen[key] = true
+			suggestions = append(suggestions, suggestion)
+		}
+	}
+
+	return suggestions, nil
+}
+
+// cachedSuggestion returns the first noun of the cached answer of the word, ok is false for words that
+// aren't cached or have no article
+func (uc *SuggestUseCase) cachedSuggestion(ctx context.Context, word, language string) (entities.WordSuggestion, bool) {
+	cached, ok := uc.lookup.cached(ctx, entities.NewArticleRequest(word, language).Core())
+	if !ok || !cached.Success {
+		return entities.WordSuggestion{}, false
+	}
+	for _, info := range cached.Data {
+		if article := info.Article(); article != "" {
+			return entities.WordSuggestion{Noun: info.Noun(), Article: article}, true
+		}
+	}
+
+	return entities.WordSuggestion{}, false
+}
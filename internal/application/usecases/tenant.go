@@ -0,0 +1,46 @@
+package usecases
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+)
+
+type tenantKey struct{}
+
+// WithTenant returns the context of a request of the tenant, the request context gets the ID of the tenant
+func WithTenant(ctx context.Context, tenant *entities.Tenant) context.Context {
+	if request := RequestContextFromContext(ctx); request != nil && tenant != nil {
+		withTenant := *request
+		withTenant.Tenant = tenant.ID
+		ctx = WithRequestContext(ctx, &withTenant)
+	}
+
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant of the request, nil for requests of no tenant
+func TenantFromContext(ctx context.Context) *entities.Tenant {
+	tenant, _ := ctx.Value(tenantKey{}).(*entities.Tenant)
+	return tenant
+}
+
+// tenantRequest returns the request customized for the tenant of the context: the example topics of the
+// configuration or the example domains, the tone and the audience of its prompt overrides
+func tenantRequest(ctx context.Context, request *entities.ArticleRequest) *entities.ArticleRequest {
+	tenant := TenantFromContext(ctx)
+	if tenant == nil || len(request.ExampleTopics) > 0 {
+		return request
+	}
+
+	customized := *request
+	customized.ExampleTopics = tenant.ExampleTopics
+	if prompt := tenant.Prompt; prompt != nil {
+		if len(prompt.ExampleDomains) > 0 {
+			customized.ExampleTopics = prompt.ExampleDomains
+		}
+		customized.Tone = prompt.Tone
+		customized.AudienceAge = prompt.AudienceAge
+	}
+
+	return &customized
+}
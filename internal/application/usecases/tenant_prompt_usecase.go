@@ -0,0 +1,176 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/repositories"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/services"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	maxPromptToneLength    = 32
+	maxExampleDomains      = 5
+	maxExampleDomainLength = 64
+	minAudienceAge         = 6
+	maxAudienceAge         = 99
+
+	// previewWord is the word of the previews that don't name one
+	previewWord = "Haus"
+)
+
+// ErrInvalidPromptOverrides is returned for prompt overrides failing the validation
+var ErrInvalidPromptOverrides = errors.New("invalid prompt overrides")
+
+// promptFragmentPattern matches the words of the fragments of the prompt, they are inserted into the
+// instructions of the AI, so they can't carry punctuation forming instructions of their own
+var promptFragmentPattern = regexp.MustCompile(`^[\p{L}\p{N}]+(?:[ -][\p{L}\p{N}]+)*$`)
+
+// TenantPromptUseCase lets the tenants customize the fragments of the prompt of their lookups: the tone,
+// the example domains and the age of the audience of the example sentences
+type TenantPromptUseCase struct {
+	prompts  repositories.PromptRepository
+	tenants  *TenantUseCase
+	renderer services.PromptRenderer
+	logger   logging.Logger
+	tracer   tracing.Tracer
+}
+
+// NewTenantPromptUseCase creates a new tenant prompt use case
+func NewTenantPromptUseCase(
+	prompts repositories.PromptRepository,
+	tenants *TenantUseCase,
+	renderer services.PromptRenderer,
+	logger logging.Logger,
+	tracer tracing.Tracer,
+) *TenantPromptUseCase {
+	return &TenantPromptUseCase{
+		prompts:  prompts,
+		tenants:  tenants,
+		renderer: renderer,
+		logger:   logger,
+		tracer:   tracer,
+	}
+}
+
+// Get returns the prompt overrides of the tenant, empty ones when it kept the defaults
+func (uc *TenantPromptUseCase) Get(ctx context.Context, tenantID string) (*entities.PromptOverrides, error) {
+	spanCtx, span := uc.tracer.Start(ctx, "Get Tenant Prompt")
+	defer span.End()
+
+	overrides, err := uc.prompts.Get(spanCtx, tenantID)
+	if err != nil {
+		uc.logger.With(spanCtx).Err(err).Field("tenant", tenantID).Error("Failed to get prompt overrides")
+		return nil, err
+	}
+	if overrides == nil {
+		return &entities.PromptOverrides{TenantID: tenantID}, nil
+	}
+
+	return overrides, nil
+}
+
+// Save validates and stores the prompt overrides of the tenant. The lookups of the instance use them
+// right away, the other instances within a minute. The answers cached with the former overrides stay
+// cached apart from the new ones until they expire.
+func (uc *TenantPromptUseCase) Save(ctx context.Context, overrides *entities.PromptOverrides) (*entities.PromptOverrides, error) {
+	spanCtx, span := uc.tracer.Start(ctx, "Save Tenant Prompt")
+	defer span.End()
+
+	normalized, err := normalizePromptOverrides(overrides)
+	if err != nil {
+		return nil, err
+	}
+	updatedAt := time.Now().UTC()
+	normalized.UpdatedAt = &updatedAt
+	if err := uc.prompts.Save(spanCtx, normalized); err != nil {
+		uc.logger.With(spanCtx).Err(err).Field("tenant", normalized.TenantID).Error("Failed to save prompt overrides")
+		return nil, err
+	}
+	uc.tenants.rememberPrompt(normalized.TenantID, normalized)
+	uc.logger.With(spanCtx).Field("tenant", normalized.TenantID).Info("Prompt overrides saved")
+
+	return normalized, nil
+}
+
+// Delete removes the prompt overrides of the tenant, its lookups use the defaults again
+func (uc *TenantPromptUseCase) Delete(ctx context.Context, tenantID string) error {
+	spanCtx, span := uc.tracer.Start(ctx, "Delete Tenant Prompt")
+	defer span.End()
+
+	if err := uc.prompts.Delete(spanCtx, tenantID); err != nil {
+		uc.logger.With(spanCtx).Err(err).Field("tenant", tenantID).Error("Failed to delete prompt overrides")
+		return err
+	}
+	uc.tenants.rememberPrompt(tenantID, nil)
+	uc.logger.With(spanCtx).Field("tenant", tenantID).Info("Prompt overrides deleted")
+
+	return nil
+}
+
+// Preview renders the prompt of a lookup of the word with the overrides, validated like saved ones but
+// not saved. Nil overrides preview the saved ones.
+func (uc *TenantPromptUseCase) Preview(
+	ctx context.Context,
+	tenant *entities.Tenant,
+	overrides *entities.PromptOverrides,
+	word, language string,
+) (*entities.PromptPreview, error) {
+	spanCtx, span := uc.tracer.Start(ctx, "Preview Tenant Prompt")
+	defer span.End()
+
+	previewed := *tenant
+	if overrides != nil {
+		normalized, err := normalizePromptOverrides(overrides)
+		if err != nil {
+			return nil, err
+		}
+		previewed.Prompt = normalized
+	}
+	if strings.TrimSpace(word) == "" {
+		word = previewWord
+	}
+
+	request := tenantRequest(WithTenant(spanCtx, &previewed), entities.NewArticleRequest(word, language))
+	prompt, err := uc.renderer.RenderPrompt(spanCtx, request)
+	if err != nil {
+		uc.logger.With(spanCtx).Err(err).Field("tenant", tenant.ID).Error("Failed to render prompt preview")
+		return nil, err
+	}
+
+	return &entities.PromptPreview{Word: request.Word, Language: request.Language, Prompt: prompt}, nil
+}
+
+// normalizePromptOverrides returns the overrides with trimmed fragments, or ErrInvalidPromptOverrides
+// naming the first invalid one
+func normalizePromptOverrides(overrides *entities.PromptOverrides) (*entities.PromptOverrides, error) {
+	normalized := &entities.PromptOverrides{
+		TenantID:    overrides.TenantID,
+		Tone:        strings.TrimSpace(overrides.Tone),
+		AudienceAge: overrides.AudienceAge,
+	}
+	if normalized.Tone != "" && (len(normalized.Tone) > maxPromptToneLength || !promptFragmentPattern.MatchString(normalized.Tone)) {
+		return nil, fmt.Errorf("%w: tone must be at most %d characters of letters, digits, spaces and hyphens", ErrInvalidPromptOverrides, maxPromptToneLength)
+	}
+	if len(overrides.ExampleDomains) > maxExampleDomains {
+		return nil, fmt.Errorf("%w: at most %d example domains are supported", ErrInvalidPromptOverrides, maxExampleDomains)
+	}
+	for _, domain := range overrides.ExampleDomains {
+		domain = strings.TrimSpace(domain)
+		if len(domain) > maxExampleDomainLength || !promptFragmentPattern.MatchString(domain) {
+			return nil, fmt.Errorf("%w: example domains must be at most %d characters of letters, digits, spaces and hyphens", ErrInvalidPromptOverrides, maxExampleDomainLength)
+		}
+		normalized.ExampleDomains = append(normalized.ExampleDomains, domain)
+	}
+	if normalized.AudienceAge != 0 && (normalized.AudienceAge < minAudienceAge || normalized.AudienceAge > maxAudienceAge) {
+		return nil, fmt.Errorf("%w: audienceAge must be between %d and %d, or 0 for any age", ErrInvalidPromptOverrides, minAudienceAge, maxAudienceAge)
+	}
+
+	return normalized, nil
+}
@@ -0,0 +1,279 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/repositories"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	tenantQuotaDateLayout = "2006-01-02"
+	// promptCacheTTL bounds how long an instance keeps using prompt overrides replaced on another instance
+	promptCacheTTL = time.Minute
+)
+
+var (
+	// ErrTenantNotFound is returned for IDs of no configured tenant
+	ErrTenantNotFound = errors.New("tenant not found")
+	// ErrTenantQuotaExceeded is returned once the tenant used up its requests of the UTC day
+	ErrTenantQuotaExceeded = errors.New("the daily request quota of the tenant is used up")
+)
+
+// TenantUseCase serves the tenants of the API: it resolves them by their API keys, counts their requests
+// against their daily quotas and keeps their usage statistics apart from each other. The quotas and the
+// statistics are counted by every instance on its own.
+type TenantUseCase struct {
+	tenants map[string]*entities.Tenant
+	keys    map[string]*entities.Tenant
+	stats   map[string]repositories.StatsRepository
+	logger  logging.Logger
+	tracer  tracing.Tracer
+
+	mu        sync.Mutex
+	quotaDate string
+	requests  map[string]int64
+	now       func() time.Time
+
+	prompts      repositories.PromptRepository
+	promptsMu    sync.Mutex
+	promptsCache map[string]cachedPrompt
+}
+
+// cachedPrompt are the prompt overrides of a tenant as loaded at loadedAt, nil for the defaults
+type cachedPrompt struct {
+	overrides *entities.PromptOverrides
+	loadedAt  time.Time
+}
+
+// NewTenantUseCase creates a new tenant use case of the tenants with their API keys mapped to the tenant IDs,
+// the statistics of every tenant are kept by a repository of newStats
+func NewTenantUseCase(
+	tenants []entities.Tenant,
+	keys map[string]string,
+	newStats func() repositories.StatsRepository,
+	logger logging.Logger,
+	tracer tracing.Tracer,
+) *TenantUseCase {
+	uc := &TenantUseCase{
+		tenants:      make(map[string]*entities.Tenant, len(tenants)),
+		keys:         make(map[string]*entities.Tenant, len(keys)),
+		stats:        make(map[string]repositories.StatsRepository, len(tenants)),
+		logger:       logger,
+		tracer:       tracer,
+		requests:     make(map[string]int64),
+		now:          func() time.Time { return time.Now().UTC() },
+		promptsCache: make(map[string]cachedPrompt),
+	}
+	for _, tenant := range tenants {
+		uc.tenants[tenant.ID] = &tenant
+		uc.stats[tenant.ID] = newStats()
+	}
+	// Only the hashes of the keys are kept in memory, like the API tokens of linked accounts
+	for key, id := range keys {
+		if tenant, ok := uc.tenants[id]; ok {
+			uc.keys[hashToken(key)] = tenant
+		}
+	}
+
+	return uc
+}
+
+// SetPrompts sets the repository of the prompt overrides, the tenants keep the defaults without it
+func (uc *TenantUseCase) SetPrompts(prompts repositories.PromptRepository) {
+	uc.prompts = prompts
+}
+
+// Authenticate returns the tenant of the API key with its prompt overrides, nil for unknown keys
+func (uc *TenantUseCase) Authenticate(ctx context.Context, key string) *entities.Tenant {
+	if key == "" {
+		return nil
+	}
+	tenant, ok := uc.keys[hashToken(key)]
+	if !ok {
+		return nil
+	}
+
+	return uc.customized(ctx, tenant)
+}
+
+// Load returns the tenant of the ID with its prompt overrides, nil for IDs of no configured tenant
+func (uc *TenantUseCase) Load(ctx context.Context, id string) *entities.Tenant {
+	tenant, ok := uc.tenants[id]
+	if !ok {
+		return nil
+	}
+
+	return uc.customized(ctx, tenant)
+}
+
+// Admit counts the request of the tenant and returns the requests left on the UTC day, -1 for tenants
+// without a quota. Requests beyond the quota return ErrTenantQuotaExceeded and aren't counted.
+func (uc *TenantUseCase) Admit(ctx context.Context, tenant *entities.Tenant) (int64, error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	uc.rollQuota()
+	if tenant.DailyQuota <= 0 {
+		uc.requests[tenant.ID]++
+		return -1, nil
+	}
+	if uc.requests[tenant.ID] >= tenant.DailyQuota {
+		uc.logger.With(ctx).Field("tenant", tenant.ID).Field("quota", tenant.DailyQuota).Sampled().Warning("Tenant request quota is used up")
+		return 0, ErrTenantQuotaExceeded
+	}
+	uc.requests[tenant.ID]++
+
+	return tenant.DailyQuota - uc.requests[tenant.ID], nil
+}
+
+// QuotaReset returns when the daily quotas start over, at the next UTC midnight
+func (uc *TenantUseCase) QuotaReset() time.Time {
+	now := uc.now()
+	return time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+}
+
+// Stats returns the usage of the tenant with the given number of top words
+func (uc *TenantUseCase) Stats(ctx context.Context, id string, topWords int) (*entities.TenantStats, error) {
+	spanCtx, span := uc.tracer.Start(ctx, "Tenant Stats")
+	defer span.End()
+
+	tenant, ok := uc.tenants[id]
+	if !ok {
+		return nil, ErrTenantNotFound
+	}
+	usage, err := uc.stats[id].Snapshot(spanCtx, topWords)
+	if err != nil {
+		uc.logger.With(spanCtx).Err(err).Field("tenant", id).Error("Failed to build tenant stats snapshot")
+		return nil, err
+	}
+
+	uc.mu.Lock()
+	uc.rollQuota()
+	quota := entities.QuotaStats{Date: uc.quotaDate, Used: uc.requests[id]}
+	uc.mu.Unlock()
+	if tenant.DailyQuota > 0 {
+		quota.Limit = tenant.DailyQuota
+		quota.Remaining = max(tenant.DailyQuota-quota.Used, 0)
+	}
+
+	return &entities.TenantStats{Tenant: *tenant, Quota: quota, Usage: usage}, nil
+}
+
+// List returns the usage of every tenant ordered by ID
+func (uc *TenantUseCase) List(ctx context.Context, topWords int) ([]entities.TenantStats, error) {
+	ids := make([]string, 0, len(uc.tenants))
+	for id := range uc.tenants {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	list := make([]entities.TenantStats, 0, len(ids))
+	for _, id := range ids {
+		stats, err := uc.Stats(ctx, id, topWords)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, *stats)
+	}
+
+	return list, nil
+}
+
+// TrackStats returns the stats repository recording the usage into stats and into the statistics of the
+// tenant of the request
+func (uc *TenantUseCase) TrackStats(stats repositories.StatsRepository) repositories.StatsRepository {
+	if len(uc.tenants) == 0 {
+		return stats
+	}
+
+	return &tenantStats{StatsRepository: stats, tenants: uc}
+}
+
+// customized returns the tenant with its prompt overrides. They are cached for promptCacheTTL, a failed
+// load keeps the overrides loaded before so the lookups of the tenant don't fail with the storage.
+func (uc *TenantUseCase) customized(ctx context.Context, tenant *entities.Tenant) *entities.Tenant {
+	if uc.prompts == nil {
+		return tenant
+	}
+
+	uc.promptsMu.Lock()
+	cached, ok := uc.promptsCache[tenant.ID]
+	uc.promptsMu.Unlock()
+	if !ok || uc.now().Sub(cached.loadedAt) >= promptCacheTTL {
+		overrides, err := uc.prompts.Get(ctx, tenant.ID)
+		if err != nil {
+			uc.logger.With(ctx).Err(err).Field("tenant", tenant.ID).Sampled().Warning("Failed to load prompt overrides, using the ones loaded before")
+		} else {
+			cached = uc.rememberPrompt(tenant.ID, overrides)
+		}
+	}
+	if cached.overrides == nil {
+		return tenant
+	}
+
+	customized := *tenant
+	customized.Prompt = cached.overrides
+
+	return &customized
+}
+
+// rememberPrompt caches the prompt overrides of the tenant, nil for the defaults
+func (uc *TenantUseCase) rememberPrompt(id string, overrides *entities.PromptOverrides) cachedPrompt {
+	cached := cachedPrompt{overrides: overrides, loadedAt: uc.now()}
+	uc.promptsMu.Lock()
+	uc.promptsCache[id] = cached
+	uc.promptsMu.Unlock()
+
+	return cached
+}
+
+// rollQuota starts the counts of a new UTC day, the caller holds the lock
+func (uc *TenantUseCase) rollQuota() {
+	if today := uc.now().Format(tenantQuotaDateLayout); uc.quotaDate != today {
+		uc.quotaDate = today
+		clear(uc.requests)
+	}
+}
+
+// tenantStats records the usage into the statistics of the instance and into those of the tenant of the
+// request, the other methods read the statistics of the instance
+type tenantStats struct {
+	repositories.StatsRepository
+	tenants *TenantUseCase
+}
+
+func (s *tenantStats) RecordLookup(ctx context.Context, word, language string, adapter entities.Adapter) {
+	s.StatsRepository.RecordLookup(ctx, word, language, adapter)
+	if stats := s.of(ctx); stats != nil {
+		stats.RecordLookup(ctx, word, language, adapter)
+	}
+}
+
+func (s *tenantStats) RecordCacheLookup(ctx context.Context, hit bool) {
+	s.StatsRepository.RecordCacheLookup(ctx, hit)
+	if stats := s.of(ctx); stats != nil {
+		stats.RecordCacheLookup(ctx, hit)
+	}
+}
+
+func (s *tenantStats) RecordAICall(ctx context.Context, failed bool) {
+	s.StatsRepository.RecordAICall(ctx, failed)
+	if stats := s.of(ctx); stats != nil {
+		stats.RecordAICall(ctx, failed)
+	}
+}
+
+// of returns the statistics of the tenant of the request, nil for requests of no tenant
+func (s *tenantStats) of(ctx context.Context) repositories.StatsRepository {
+	if tenant := TenantFromContext(ctx); tenant != nil {
+		return s.tenants.stats[tenant.ID]
+	}
+
+	return nil
+}
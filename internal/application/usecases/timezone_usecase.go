@@ -0,0 +1,114 @@
+package usecases
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/repositories"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+	"time"
+)
+
+// TimezoneUseCase keeps the time zones of the users, their days start at midnight of their zone. Users
+// without a time zone live in UTC.
+type TimezoneUseCase struct {
+	preferences repositories.PreferencesRepository
+	reminders   repositories.ReminderRepository
+	logger      logging.Logger
+	tracer      tracing.Tracer
+}
+
+// NewTimezoneUseCase creates a new time zone use case instance
+func NewTimezoneUseCase(
+	preferences repositories.PreferencesRepository,
+	reminders repositories.ReminderRepository,
+	logger logging.Logger,
+	tracer tracing.Tracer,
+) *TimezoneUseCase {
+	return &TimezoneUseCase{
+		preferences: preferences,
+		reminders:   reminders,
+		logger:      logger,
+		tracer:      tracer,
+	}
+}
+
+// Location returns the time zone of the user, UTC if it can't be read
+func (uc *TimezoneUseCase) Location(ctx context.Context, userID int64) *time.Location {
+	preferences, err := uc.preferences.Get(ctx, userID)
+	if err != nil {
+		uc.logger.With(ctx).Err(err).Field("userId", userID).Warning("Failed to read the time zone of the user")
+		return time.UTC
+	}
+
+	return preferences.Location()
+}
+
+// Now returns the current time in the time zone of the user
+func (uc *TimezoneUseCase) Now(ctx context.Context, userID int64) time.Time {
+	return time.Now().In(uc.Location(ctx, userID))
+}
+
+// Set validates and saves the time zone of the user. The reminder of the user is moved to the new zone,
+// keeping its local time.
+func (uc *TimezoneUseCase) Set(ctx context.Context, userID int64, timezone string) (*entities.UserPreferences, error) {
+	spanCtx, span := uc.tracer.Start(ctx, "Set Timezone")
+	defer span.End()
+
+	if !entities.ValidTimezone(timezone) {
+		return nil, ErrInvalidTimezone
+	}
+	preferences, err := uc.preferences.Get(spanCtx, userID)
+	if err != nil {
+		uc.logger.With(spanCtx).Err(err).Field("userId", userID).Error("Failed to read user preferences")
+		return nil, err
+	}
+	preferences.Timezone = timezone
+	preferences.TimezoneAsked = true
+	preferences.UpdatedAt = time.Now().UTC()
+	if err := uc.preferences.Save(spanCtx, preferences); err != nil {
+		uc.logger.With(spanCtx).Err(err).Field("userId", userID).Error("Failed to save user preferences")
+		return nil, err
+	}
+
+	reminder, err := uc.reminders.Get(spanCtx, userID)
+	if err != nil {
+		uc.logger.With(spanCtx).Err(err).Field("userId", userID).Error("Failed to get reminder")
+		return nil, err
+	}
+	if reminder != nil && reminder.Timezone != timezone {
+		reminder.Timezone = timezone
+		reminder.NextAt = reminder.Next(time.Now().UTC())
+		if err := uc.reminders.Save(spanCtx, reminder); err != nil {
+			uc.logger.With(spanCtx).Err(err).Field("userId", userID).Error("Failed to save reminder")
+			return nil, err
+		}
+	}
+	uc.logger.With(spanCtx).Field("userId", userID).Field("timezone", timezone).Info("Time zone set")
+
+	return preferences, nil
+}
+
+// Ask reports whether the user should be asked for the time zone, it's true once for users without one
+func (uc *TimezoneUseCase) Ask(ctx context.Context, userID int64) bool {
+	spanCtx, span := uc.tracer.Start(ctx, "Ask Timezone")
+	defer span.End()
+
+	preferences, err := uc.preferences.Get(spanCtx, userID)
+	if err != nil {
+		uc.logger.With(spanCtx).Err(err).Field("userId", userID).Warning("Failed to read user preferences")
+		return false
+	}
+	if preferences.Timezone != "" || preferences.TimezoneAsked {
+		return false
+	}
+
+	preferences.TimezoneAsked = true
+	preferences.UpdatedAt = time.Now().UTC()
+	if err := uc.preferences.Save(spanCtx, preferences); err != nil {
+		uc.logger.With(spanCtx).Err(err).Field("userId", userID).Warning("Failed to save user preferences")
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,80 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/repositories"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/services"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+	"slices"
+)
+
+// TranslateWordUseCase looks up the German nouns translating a word of the user's language
+type TranslateWordUseCase struct {
+	translator services.TranslationService
+	lookup     *DetermineArticleUseCase
+	stats      repositories.StatsRepository
+	logger     logging.Logger
+	tracer     tracing.Tracer
+}
+
+// NewTranslateWordUseCase creates a new translate word use case instance
+func NewTranslateWordUseCase(
+	translator services.TranslationService,
+	lookup *DetermineArticleUseCase,
+	stats repositories.StatsRepository,
+	logger logging.Logger,
+	tracer tracing.Tracer,
+) *TranslateWordUseCase {
+	return &TranslateWordUseCase{
+		translator: translator,
+		lookup:     lookup,
+		stats:      stats,
+		logger:     logger,
+		tracer:     tracer,
+	}
+}
+
+// Execute finds the German nouns of the word and runs the article lookup of every one of them,
+// nouns the lookup doesn't confirm are left out
+func (uc *TranslateWordUseCase) Execute(ctx context.Context, request *entities.TranslationRequest) (*entities.TranslationResult, error) {
+	spanCtx, span := uc.tracer.Start(ctx, "Translate Word")
+	defer span.End()
+
+	if !request.IsValid() {
+		return nil, fmt.Errorf("invalid translation request for word %q", request.Word)
+	}
+
+	uc.logger.With(spanCtx).Field("word", request.Word).Field("language", request.Language).Info("Processing translation request")
+	nouns, err := uc.translator.FindGermanNouns(spanCtx, request)
+	uc.stats.RecordAICall(spanCtx, err != nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find German nouns: %w", err)
+	}
+
+	result := &entities.TranslationResult{Word: request.Word}
+	var seen []string
+	for _, noun := range nouns {
+		articleRequest := entities.NewArticleRequest(noun, request.Language)
+		articleRequest.Level = request.Level
+		if articleRequest.Word == "" || slices.Contains(seen, articleRequest.Word) {
+			continue
+		}
+		seen = append(seen, articleRequest.Word)
+
+		response, err := uc.lookup.Execute(spanCtx, articleRequest)
+		if err != nil {
+			return nil, err
+		}
+		if response.Success && len(response.Data) > 0 {
+			result.Nouns = append(result.Nouns, entities.TranslatedNoun{Noun: articleRequest.Word, Response: response})
+		}
+		if len(result.Nouns) == entities.MaxTranslatedNouns {
+			break
+		}
+	}
+
+	return result, nil
+}
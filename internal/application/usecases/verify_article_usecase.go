@@ -0,0 +1,130 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/repositories"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/services"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+	"slices"
+	"time"
+)
+
+// VerifyArticleUseCase re-checks a reported answer against a second AI model and the dictionary
+type VerifyArticleUseCase struct {
+	verifier   services.AIService
+	dictionary services.DictionaryService
+	cache      repositories.CacheRepository
+	cacheTTL   time.Duration
+	logger     logging.Logger
+	tracer     tracing.Tracer
+}
+
+// NewVerifyArticleUseCase creates a new verify article use case instance
+func NewVerifyArticleUseCase(
+	verifier services.AIService,
+	dictionary services.DictionaryService,
+	cache repositories.CacheRepository,
+	cacheTTL time.Duration,
+	logger logging.Logger,
+	tracer tracing.Tracer,
+) *VerifyArticleUseCase {
+	return &VerifyArticleUseCase{
+		verifier:   verifier,
+		dictionary: dictionary,
+		cache:      cache,
+		cacheTTL:   cacheTTL,
+		logger:     logger,
+		tracer:     tracer,
+	}
+}
+
+// Execute compares the cached answer with the second opinion and the dictionary, a confirmed
+// correction replaces the cached answer and every mismatch is logged as a discrepancy record
+func (uc *VerifyArticleUseCase) Execute(ctx context.Context, request *entities.ArticleRequest, userID int64) (*entities.Verification, error) {
+	spanCtx, span := uc.tracer.Start(ctx, "Verify Article")
+	defer span.End()
+
+	if !request.IsValid() {
+		return nil, fmt.Errorf("invalid verification request for word %q", request.Word)
+	}
+
+	verification := &entities.Verification{
+		Word:      request.Word,
+		Language:  request.Language,
+		UserID:    userID,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	// Staged lookups may have answered only the core stage of the word so far
+	var original *entities.ArticleResponse
+	for _, key := range []string{request.CacheKey(), request.Core().CacheKey()} {
+		cached, ok, err := uc.cache.Get(spanCtx, key)
+		if err != nil {
+			uc.logger.With(spanCtx).Err(err).Warning("Failed to read reported answer from cache")
+		}
+		if ok {
+			original = cached
+			break
+		}
+	}
+	verification.Original = original.Articles()
+
+	secondOpinion, err := uc.verifier.GenerateArticleInfo(spanCtx, request)
+	if err != nil {
+		uc.logger.With(spanCtx).Err(err).Field("word", request.Word).Warning("Failed to get second opinion")
+	}
+	verification.SecondOpinion = secondOpinion.Articles()
+
+	article, found, err := uc.dictionary.LookupArticle(spanCtx, request.Word)
+	if err != nil {
+		uc.logger.With(spanCtx).Err(err).Field("word", request.Word).Warning("Failed to look up dictionary")
+	}
+	if found {
+		verification.Dictionary = article
+	}
+
+	verification.Outcome = outcome(verification)
+	// The second opinion replaces the answer only when the dictionary doesn't contradict it
+	if verification.Outcome == entities.VerificationCorrected && len(verification.SecondOpinion) > 0 &&
+		(verification.Dictionary == "" || slices.Contains(verification.SecondOpinion, verification.Dictionary)) {
+		// The full answer replaces the core one too, the core stage is answered by full answers as well
+		for _, key := range []string{request.CacheKey(), request.Core().CacheKey()} {
+			if err := uc.cache.Set(spanCtx, key, secondOpinion, uc.cacheTTL); err != nil {
+				uc.logger.With(spanCtx).Err(err).Warning("Failed to write corrected answer to cache")
+				continue
+			}
+			verification.CacheUpdated = true
+		}
+	}
+
+	if verification.Outcome == entities.VerificationConfirmed {
+		uc.logger.With(spanCtx).Field("verification", verification).Info("Reported article confirmed")
+	} else {
+		uc.logger.With(spanCtx).Field("discrepancy", verification).Warning("Article discrepancy reported")
+	}
+
+	return verification, nil
+}
+
+// outcome compares the original articles with the dictionary, or with the second opinion
+// when the word is not in the dictionary
+func outcome(v *entities.Verification) entities.VerificationOutcome {
+	expected := v.SecondOpinion
+	if v.Dictionary != "" {
+		expected = []string{v.Dictionary}
+	}
+	if len(v.Original) == 0 || len(expected) == 0 {
+		return entities.VerificationInconclusive
+	}
+
+	for _, article := range expected {
+		if !slices.Contains(v.Original, article) {
+			return entities.VerificationCorrected
+		}
+	}
+
+	return entities.VerificationConfirmed
+}
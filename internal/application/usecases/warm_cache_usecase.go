@@ -0,0 +1,102 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/services"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+	"golang.org/x/time/rate"
+)
+
+// JobTypeCacheWarmup is the background job looking up the most frequent nouns
+const JobTypeCacheWarmup entities.JobType = "cache.warmup"
+
+// WarmCacheUseCase fills the article cache with the answers of the most frequent nouns, so the
+// common lookups don't wait for the AI
+type WarmCacheUseCase struct {
+	lookup    *DetermineArticleUseCase
+	frequency services.FrequencyService
+	jobs      services.JobQueue
+	limiter   *rate.Limiter
+	logger    logging.Logger
+	tracer    tracing.Tracer
+}
+
+// NewWarmCacheUseCase creates a new cache warmup use case doing at most ratePerSecond lookups
+func NewWarmCacheUseCase(
+	lookup *DetermineArticleUseCase,
+	frequency services.FrequencyService,
+	jobs services.JobQueue,
+	ratePerSecond float64,
+	logger logging.Logger,
+	tracer tracing.Tracer,
+) *WarmCacheUseCase {
+	return &WarmCacheUseCase{
+		lookup:    lookup,
+		frequency: frequency,
+		jobs:      jobs,
+		limiter:   rate.NewLimiter(rate.Limit(ratePerSecond), 1),
+		logger:    logger,
+		tracer:    tracer,
+	}
+}
+
+// Submit enqueues the warmup and returns its job
+func (uc *WarmCacheUseCase) Submit(ctx context.Context, warmup entities.CacheWarmup) (*entities.Job, error) {
+	spanCtx, span := uc.tracer.Start(ctx, "Submit Cache Warmup")
+	defer span.End()
+
+	job, err := NewJob(spanCtx, JobTypeCacheWarmup, warmup)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.jobs.Enqueue(spanCtx, job); err != nil {
+		uc.logger.With(spanCtx).Err(err).Error("Failed to enqueue cache warmup")
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// Handle looks up the nouns of the warmup in every language, cached answers don't reach the AI
+// so a retried warmup only repeats the failed lookups
+func (uc *WarmCacheUseCase) Handle(ctx context.Context, job *entities.Job) error {
+	spanCtx, span := uc.tracer.Start(ctx, "Cache Warmup")
+	defer span.End()
+
+	var warmup entities.CacheWarmup
+	if err := json.Unmarshal(job.Payload, &warmup); err != nil {
+		return fmt.Errorf("%w: failed to decode cache warmup: %v", ErrInvalidJob, err)
+	}
+
+	nouns, err := uc.frequency.Top(spanCtx, warmup.Words)
+	if err != nil {
+		return fmt.Errorf("failed to read the frequency list: %w", err)
+	}
+
+	var result entities.CacheWarmupResult
+	total := len(nouns) * len(warmup.Languages)
+	for _, noun := range nouns {
+		for _, language := range warmup.Languages {
+			if err := uc.limiter.Wait(spanCtx); err != nil {
+				return fmt.Errorf("cache warmup interrupted: %w", err)
+			}
+
+			response, err := uc.lookup.Execute(spanCtx, entities.NewArticleRequest(noun, language))
+			result.Lookups++
+			if err != nil || !response.Success {
+				result.Failures++
+				uc.logger.With(spanCtx).Field("word", noun).Field("language", language).Err(err).Warning("Failed to warm up the cache")
+			}
+			ReportJobProgress(spanCtx, result.Lookups, total)
+		}
+	}
+
+	uc.logger.With(spanCtx).Field("lookups", result.Lookups).Field("failures", result.Failures).Info("Cache warmup finished")
+	SetJobResult(spanCtx, result)
+
+	return nil
+}
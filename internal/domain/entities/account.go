@@ -0,0 +1,47 @@
+package entities
+
+import (
+	"strconv"
+	"time"
+)
+
+const (
+	// IdentityProviderLink authenticates requests with the API token of a linked Telegram account
+	IdentityProviderLink = "link"
+	// IdentityProviderGoogle authenticates requests with Google Sign-In ID tokens
+	IdentityProviderGoogle = "google"
+	// IdentityProviderFirebase authenticates requests with Firebase Auth ID tokens
+	IdentityProviderFirebase = "firebase"
+	// IdentityProviderTelegram marks updates of the Telegram bot, the user is the sender of the update
+	IdentityProviderTelegram = "telegram"
+)
+
+// Identity is the user profile an API request is made for, the profile is shared by the bot and the web API
+type Identity struct {
+	// UserID is the Telegram user ID of the profile, zero for external identities not linked to the bot
+	UserID int64 `json:"userId,omitempty"`
+	// Provider names how the request was authenticated
+	Provider string `json:"provider"`
+	// Subject is the user ID at the external identity provider
+	Subject string `json:"subject,omitempty"`
+	// Email is the verified email address of the external identity
+	Email string `json:"email,omitempty"`
+	// Session reports whether the request was authenticated with a session token of the backend
+	Session bool `json:"session,omitempty"`
+}
+
+// ClientID keys the rate limits of the profile, the bot uses the same key so the quota is shared
+func (i Identity) ClientID() string {
+	if i.UserID == 0 && i.Subject != "" {
+		return i.Provider + ":" + i.Subject
+	}
+
+	return "telegram:" + strconv.FormatInt(i.UserID, 10)
+}
+
+// LinkedAccount is the API token issued for a Telegram account, only its hash is stored
+type LinkedAccount struct {
+	Token     string    `json:"token"`
+	UserID    int64     `json:"userId"`
+	CreatedAt time.Time `json:"createdAt"`
+}
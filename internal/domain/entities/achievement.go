@@ -0,0 +1,34 @@
+package entities
+
+import "time"
+
+// LearningEventType names what a user did in a learning event
+type LearningEventType string
+
+const (
+	// LearningEventLookup is a successful lookup of a word
+	LearningEventLookup LearningEventType = "lookup"
+	// LearningEventQuizAnswer is an answer to a quiz question
+	LearningEventQuizAnswer LearningEventType = "quiz_answer"
+)
+
+// LearningEvent is published after the learning activity of a user was recorded
+type LearningEvent struct {
+	Type   LearningEventType
+	UserID int64
+	// Word is the looked up word or the noun of the quiz question
+	Word string
+	// Correct reports whether the quiz answer was right, false for lookups
+	Correct bool
+	At      time.Time
+}
+
+// Achievement is a badge awarded to a user for a learning milestone
+type Achievement struct {
+	ID string `json:"id"`
+	// Badge is the emoji shown with the achievement
+	Badge string `json:"badge"`
+	// Title is the English name of the achievement, the bot shows localized names
+	Title     string    `json:"title"`
+	AwardedAt time.Time `json:"awardedAt,omitempty"`
+}
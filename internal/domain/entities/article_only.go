@@ -0,0 +1,31 @@
+package entities
+
+// genders maps the definite articles to the grammatical genders of their nouns
+var genders = map[string]string{
+	"der": "masculine",
+	"die": "feminine",
+	"das": "neuter",
+}
+
+// Gender returns the grammatical gender of the definite article, ok is false for other words
+func Gender(article string) (gender string, ok bool) {
+	gender, ok = genders[article]
+	return gender, ok
+}
+
+// ArticleOnlyAnswer is the definite article of a noun with its grammatical gender, the whole answer of
+// the article-only lookups
+type ArticleOnlyAnswer struct {
+	Article string `json:"article"`
+	Gender  string `json:"gender"`
+}
+
+// NewArticleOnlyAnswer returns the answer of the lower-cased article, ok is false for other words
+func NewArticleOnlyAnswer(article string) (answer *ArticleOnlyAnswer, ok bool) {
+	gender, ok := Gender(article)
+	if !ok {
+		return nil, false
+	}
+
+	return &ArticleOnlyAnswer{Article: article, Gender: gender}, true
+}
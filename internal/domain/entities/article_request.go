@@ -1,18 +1,37 @@
 package entities
 
+import (
+	"strconv"
+	"strings"
+)
+
 // ArticleRequest represents a request to determine German article
 type ArticleRequest struct {
 	Word     string
 	Language string
+	// Level is the CEFR level of the example sentences, empty leaves the complexity to the AI
+	Level Level
+	// ArticleOnly skips the example sentences, the answer is quicker and cheaper
+	ArticleOnly bool
+	// Verbosity is the answer profile, empty asks for the full answer
+	Verbosity Verbosity
+	// Interpretations are the words with articles of the core answer, the examples stage keeps them in their order
+	Interpretations []string
+	// ExampleTopics are the topics the example sentences are taken from, set for the lookups of tenants
+	ExampleTopics []string
+	// Tone is the tone of the example sentences, set for the lookups of tenants customizing it
+	Tone string
+	// AudienceAge is the age of the learners the example sentences are written for, zero for any age
+	AudienceAge int
 }
 
-// NewArticleRequest creates a new article request
+// NewArticleRequest creates a new article request for the normalized word
 func NewArticleRequest(word, language string) *ArticleRequest {
 	if language == "" {
 		language = "en" // default to English
 	}
 	return &ArticleRequest{
-		Word:     word,
+		Word:     NormalizeWord(word),
 		Language: language,
 	}
 }
@@ -20,4 +39,72 @@ func NewArticleRequest(word, language string) *ArticleRequest {
 // IsValid checks if the request is valid
 func (r *ArticleRequest) IsValid() bool {
 	return r.Word != ""
-}
\ No newline at end of file
+}
+
+// Kind returns the complexity of the request
+func (r *ArticleRequest) Kind() RequestKind {
+	if r.ArticleOnly || r.Verbosity == VerbosityMinimal {
+		return RequestKindArticle
+	}
+
+	return RequestKindFull
+}
+
+// Core returns the request of the core stage of a staged lookup: the articles, translations and hints
+// without the example sentences
+func (r *ArticleRequest) Core() *ArticleRequest {
+	core := *r
+	core.ArticleOnly = true
+	core.Interpretations = nil
+
+	return &core
+}
+
+// Examples returns the request of the examples stage of a staged lookup, the full answer continuing the
+// interpretations of the core answer. Its answer is cached like the answer of a single full lookup.
+func (r *ArticleRequest) Examples(interpretations []string) *ArticleRequest {
+	examples := *r
+	examples.ArticleOnly = false
+	examples.Interpretations = interpretations
+
+	return &examples
+}
+
+// WordCacheKey returns the cache key shared by the responses of the word in the language, the keys
+// of all levels and lookup kinds continue it. An empty language covers every language of the word.
+func WordCacheKey(word, language string) string {
+	key := strings.ToLower(strings.TrimSpace(NormalizeWord(word)))
+	if language != "" {
+		key += "|" + strings.ToLower(language)
+	}
+
+	return key
+}
+
+// CacheKey returns the key identifying responses to equivalent requests
+func (r *ArticleRequest) CacheKey() string {
+	key := strings.ToLower(strings.TrimSpace(r.Word)) + "|" + strings.ToLower(r.Language)
+	if r.Level != "" {
+		key += "|" + string(r.Level)
+	}
+	if r.ArticleOnly {
+		key += "|" + string(RequestKindArticle)
+	}
+	if r.Verbosity.Reduced() {
+		key += "|" + string(r.Verbosity)
+	}
+	// Answers without examples are the same for every topic, tone and audience
+	if r.Kind() == RequestKindFull {
+		if len(r.ExampleTopics) > 0 {
+			key += "|topics:" + strings.ToLower(strings.Join(r.ExampleTopics, ","))
+		}
+		if r.Tone != "" {
+			key += "|tone:" + strings.ToLower(r.Tone)
+		}
+		if r.AudienceAge > 0 {
+			key += "|age:" + strconv.Itoa(r.AudienceAge)
+		}
+	}
+
+	return key
+}
@@ -1,10 +1,71 @@
 package entities
 
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// AnswerSource names where an answer came from
+type AnswerSource string
+
+const (
+	// SourceDictionary answers come from the curated entries or the embedded word list
+	SourceDictionary AnswerSource = "dictionary"
+	// SourceCache answers were generated before, GeneratedAt tells when
+	SourceCache  AnswerSource = "cache"
+	SourceGemini AnswerSource = "gemini"
+	// SourceMock answers are the fixtures of the mock AI provider of local development
+	SourceMock AnswerSource = "mock"
+	// SourceMixed answers were generated by the AI and corrected by the curated dictionary
+	SourceMixed AnswerSource = "mixed"
+)
+
 // ArticleResponse represents the response with German article information
 type ArticleResponse struct {
 	Success bool          `json:"success"`
 	Error   string        `json:"error,omitempty"`
 	Data    []ArticleInfo `json:"data,omitempty"`
+	// Disambiguation is set when the word has several interpretations the user should choose from
+	Disambiguation bool `json:"disambiguation,omitempty"`
+	// GenderVariants lists the meanings of every article of nouns whose meaning depends on the gender
+	GenderVariants []GenderVariant `json:"genderVariants,omitempty"`
+	// Suggestions lists the closest valid nouns when the word looks misspelled
+	Suggestions []string `json:"suggestions,omitempty"`
+	// Partial is set when only the articles and translations could be read from a malformed AI answer
+	Partial bool `json:"partial,omitempty"`
+	// DetectedLanguage is the ISO 639-1 code of the language of a word that failed as a German noun
+	DetectedLanguage string `json:"detectedLanguage,omitempty"`
+	// Degraded is set when the answer comes from the dictionary because the AI couldn't be used
+	Degraded Degradation `json:"degraded,omitempty"`
+	// Notice explains a degraded answer in the language of the request
+	Notice string `json:"notice,omitempty"`
+	// Source is where the answer came from, GeneratedAt is when it was generated, cached answers keep the
+	// time of their generation. Answers cached before they were recorded have no GeneratedAt.
+	Source      AnswerSource `json:"source,omitempty"`
+	GeneratedAt *time.Time   `json:"generatedAt,omitempty"`
+}
+
+// Stamp records the source of the answer generated at the time
+func (r *ArticleResponse) Stamp(source AnswerSource, at time.Time) {
+	at = at.UTC()
+	r.Source, r.GeneratedAt = source, &at
+}
+
+// FromCache returns a copy of the cached answer with the cache as its source, the cached answer may be
+// shared with other requests, so it isn't modified
+func (r *ArticleResponse) FromCache() *ArticleResponse {
+	cached := *r
+	cached.Source = SourceCache
+
+	return &cached
+}
+
+// GenderVariant is the meaning of a noun with the given article
+type GenderVariant struct {
+	Article string `json:"article"`
+	Meaning string `json:"meaning"`
 }
 
 type ExamplesInfo struct {
@@ -12,6 +73,25 @@ type ExamplesInfo struct {
 	Plural   ExampleInfo `json:"plural,omitempty"`
 }
 
+// Sentences returns the example sentences and their translations, the missing ones are left out
+func (e ExamplesInfo) Sentences() []string {
+	var sentences []string
+	for _, translations := range []TranslationsInfo{e.Singular.Definite, e.Singular.Indefinite, e.Plural.Definite, e.Plural.Indefinite} {
+		for _, sentence := range []string{
+			translations.NominativeExample, translations.NominativeTranslation,
+			translations.AccusativeExample, translations.AccusativeTranslation,
+			translations.DativeExample, translations.DativeTranslation,
+			translations.GenitiveExample, translations.GenitiveTranslation,
+		} {
+			if sentence != "" {
+				sentences = append(sentences, sentence)
+			}
+		}
+	}
+
+	return sentences
+}
+
 type ExampleInfo struct {
 	Definite   TranslationsInfo `json:"definite,omitempty"`
 	Indefinite TranslationsInfo `json:"indefinite,omitempty"`
@@ -32,7 +112,128 @@ type TranslationsInfo struct {
 type ArticleInfo struct {
 	WordWithArticle string       `json:"wordWithArticle"`
 	Translation     string       `json:"translation"`
+	Plural          string       `json:"plural,omitempty"`
+	Mnemonic        string       `json:"mnemonic,omitempty"`
+	Etymology       string       `json:"etymology,omitempty"`
 	Example         ExamplesInfo `json:"example,omitempty"`
+	FrequencyRank   int          `json:"frequencyRank,omitempty"`
+	Level           Level        `json:"level,omitempty"`
+	// WordType, Confidence and Declension belong to the v2 API schema, v1 clients don't get them
+	WordType   string      `json:"wordType,omitempty"`
+	Confidence float64     `json:"confidence,omitempty"`
+	Declension *Declension `json:"declension,omitempty"`
+}
+
+// Declension is the declension table of a noun with the definite article
+type Declension struct {
+	Singular CaseForms `json:"singular"`
+	Plural   CaseForms `json:"plural,omitempty"`
+}
+
+// CaseForms are the forms of a noun with the definite article in the four cases
+type CaseForms struct {
+	Nominative string `json:"nominative,omitempty"`
+	Accusative string `json:"accusative,omitempty"`
+	Dative     string `json:"dative,omitempty"`
+	Genitive   string `json:"genitive,omitempty"`
+}
+
+// Article returns the lower-cased article of the word, empty if the value doesn't start with one
+func (i ArticleInfo) Article() string {
+	article, _, _ := strings.Cut(strings.TrimSpace(i.WordWithArticle), " ")
+	switch article = strings.ToLower(article); article {
+	case "der", "die", "das":
+		return article
+	}
+
+	return ""
+}
+
+// Noun returns the word without its article
+func (i ArticleInfo) Noun() string {
+	word := strings.TrimSpace(i.WordWithArticle)
+	if i.Article() == "" {
+		return word
+	}
+
+	_, noun, _ := strings.Cut(word, " ")
+	return strings.TrimSpace(noun)
+}
+
+// Articles returns the distinct articles of all interpretations in the response
+func (r *ArticleResponse) Articles() []string {
+	if r == nil || !r.Success {
+		return nil
+	}
+
+	var articles []string
+	for _, info := range r.Data {
+		if article := info.Article(); article != "" && !slices.Contains(articles, article) {
+			articles = append(articles, article)
+		}
+	}
+
+	return articles
+}
+
+// Interpretations returns the words with articles and their translations in the order of the answer,
+// e.g. "der See (lake)"
+func (r *ArticleResponse) Interpretations() []string {
+	if r == nil || !r.Success {
+		return nil
+	}
+
+	interpretations := make([]string, 0, len(r.Data))
+	for _, info := range r.Data {
+		interpretations = append(interpretations, fmt.Sprintf("%s (%s)", info.WordWithArticle, info.Translation))
+	}
+
+	return interpretations
+}
+
+// HasExamples reports whether any interpretation has example sentences, answers of the core stage have none
+func (r *ArticleResponse) HasExamples() bool {
+	if r == nil {
+		return false
+	}
+
+	for _, info := range r.Data {
+		if info.Example != (ExamplesInfo{}) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsForeignWord reports whether the lookup failed because the word belongs to another language than German
+func (r *ArticleResponse) IsForeignWord() bool {
+	return r != nil && !r.Success && r.DetectedLanguage != "" && !strings.EqualFold(r.DetectedLanguage, "de")
+}
+
+// Meaning returns a response with only the interpretation at the index and the gender variants
+// of the word, false if there is none
+func (r *ArticleResponse) Meaning(index int) (*ArticleResponse, bool) {
+	if r == nil || !r.Success || index < 0 || index >= len(r.Data) {
+		return nil, false
+	}
+
+	meaning := NewSuccessResponse([]ArticleInfo{r.Data[index]})
+	meaning.GenderVariants = r.GenderVariants
+
+	return meaning, true
+}
+
+// WithoutHints returns a copy of the response without mnemonics and etymologies, the cached answer is kept intact
+func (r *ArticleResponse) WithoutHints() *ArticleResponse {
+	stripped := *r
+	stripped.Data = slices.Clone(r.Data)
+	for i := range stripped.Data {
+		stripped.Data[i].Mnemonic = ""
+		stripped.Data[i].Etymology = ""
+	}
+
+	return &stripped
 }
 
 // NewSuccessResponse creates a successful response
@@ -0,0 +1,23 @@
+package entities
+
+import "time"
+
+// AsyncLookup is a lookup processed in the background, its result is posted to the callback URL
+type AsyncLookup struct {
+	Word        string `json:"word"`
+	Language    string `json:"language"`
+	Level       Level  `json:"level,omitempty"`
+	CallbackURL string `json:"callbackUrl"`
+	// Tenant is the ID of the tenant that submitted the lookup, set by the use case
+	Tenant string `json:"tenant,omitempty"`
+}
+
+// AsyncLookupResult is the payload posted to the callback URL of an async lookup
+type AsyncLookupResult struct {
+	JobID       string           `json:"jobId"`
+	Word        string           `json:"word"`
+	Language    string           `json:"language"`
+	Level       Level            `json:"level,omitempty"`
+	Result      *ArticleResponse `json:"result"`
+	CompletedAt time.Time        `json:"completedAt"`
+}
@@ -0,0 +1,33 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditAction names an admin operation recorded in the audit log
+type AuditAction string
+
+const (
+	AuditActionCacheDelete        AuditAction = "cache.delete"
+	AuditActionCachePurge         AuditAction = "cache.purge"
+	AuditActionCachePrewarm       AuditAction = "cache.prewarm"
+	AuditActionLeaderboardSummary AuditAction = "leaderboard.summary"
+	AuditActionBroadcast          AuditAction = "telegram.broadcast"
+	AuditActionDeadLetterReplay   AuditAction = "deadLetters.replay"
+	AuditActionRetentionSweep     AuditAction = "retention.sweep"
+	AuditActionBotWebhooks        AuditAction = "telegram.webhooks"
+	AuditActionDictionaryImport   AuditAction = "dictionary.import"
+)
+
+// AuditEntry records who ran an admin operation, when and with which parameters. Entries are only
+// appended, never changed or removed.
+type AuditEntry struct {
+	ID     string      `json:"id"`
+	Action AuditAction `json:"action"`
+	// Actor names the operator, the admin token is shared, so it's the one the request claims
+	Actor    string          `json:"actor"`
+	ClientIP string          `json:"clientIp,omitempty"`
+	Payload  json.RawMessage `json:"payload,omitempty"`
+	At       time.Time       `json:"at"`
+}
@@ -0,0 +1,14 @@
+package entities
+
+import "time"
+
+// BotWebhook is the state of the webhook of a Telegram bot served by the deployment
+type BotWebhook struct {
+	BotID int64 `json:"botId"`
+	// URL is the endpoint Telegram delivers the updates of the bot to, empty while no webhook is set
+	URL            string `json:"url"`
+	PendingUpdates int    `json:"pendingUpdates"`
+	// LastError is the error of the most recent delivery Telegram failed
+	LastError   string     `json:"lastError,omitempty"`
+	LastErrorAt *time.Time `json:"lastErrorAt,omitempty"`
+}
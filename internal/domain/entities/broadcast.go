@@ -0,0 +1,43 @@
+package entities
+
+import "time"
+
+// Broadcast is an announcement sent to every known chat that didn't opt out of announcements
+type Broadcast struct {
+	// Text is sent to chats without a translation in their language
+	Text string `json:"text"`
+	// Translations maps a language to the text sent to the chats of the language
+	Translations map[string]string `json:"translations,omitempty"`
+	// HTML sends the texts with Telegram HTML formatting instead of as plain text
+	HTML bool `json:"html,omitempty"`
+}
+
+// TextFor returns the text of the broadcast in the language
+func (b Broadcast) TextFor(language string) string {
+	if text, ok := b.Translations[language]; ok && text != "" {
+		return text
+	}
+
+	return b.Text
+}
+
+// BroadcastResult counts the outcome of a broadcast
+type BroadcastResult struct {
+	Chats int `json:"chats"`
+	Sent  int `json:"sent"`
+	// Unreachable chats blocked or removed the bot and are forgotten
+	Unreachable int `json:"unreachable"`
+	Failed      int `json:"failed"`
+	// LastChatID is the last chat the broadcast got to, a retried broadcast continues with the chats after it
+	LastChatID int64 `json:"lastChatId,omitempty"`
+}
+
+// KnownChat is a Telegram chat the bot has talked in, the recipient of the announcements
+type KnownChat struct {
+	ID       int64  `json:"id"`
+	Group    bool   `json:"group"`
+	Language string `json:"language"`
+	// OptedOut chats don't get announcements
+	OptedOut bool      `json:"optedOut"`
+	SeenAt   time.Time `json:"seenAt"`
+}
@@ -0,0 +1,14 @@
+package entities
+
+// CacheWarmup looks up the most frequent nouns ahead of the users, so their lookups are answered from the cache
+type CacheWarmup struct {
+	// Words is the number of nouns of the frequency list, the most frequent first
+	Words     int      `json:"words"`
+	Languages []string `json:"languages"`
+}
+
+// CacheWarmupResult counts the lookups of a warmup, failed ones are retried by the next warmup or user
+type CacheWarmupResult struct {
+	Lookups  int `json:"lookups"`
+	Failures int `json:"failures"`
+}
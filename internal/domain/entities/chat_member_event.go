@@ -0,0 +1,15 @@
+package entities
+
+// ChatMemberEvent names a change of the membership of the bot in a chat
+type ChatMemberEvent string
+
+const (
+	// ChatMemberAddedToGroup is the bot added to a group chat
+	ChatMemberAddedToGroup ChatMemberEvent = "added_to_group"
+	// ChatMemberRemovedFromGroup is the bot removed from a group chat or the group deleted
+	ChatMemberRemovedFromGroup ChatMemberEvent = "removed_from_group"
+	// ChatMemberBlocked is the bot blocked by the user of a private chat
+	ChatMemberBlocked ChatMemberEvent = "blocked"
+	// ChatMemberUnblocked is the bot unblocked or restarted by the user of a private chat
+	ChatMemberUnblocked ChatMemberEvent = "unblocked"
+)
@@ -0,0 +1,11 @@
+package entities
+
+// ContentCategory names the kind of objectionable content found in a generated text
+type ContentCategory string
+
+const (
+	ContentProfanity ContentCategory = "profanity"
+	ContentViolence  ContentCategory = "violence"
+	// ContentPolitics covers politically charged names and topics, not the political vocabulary of everyday life
+	ContentPolitics ContentCategory = "politics"
+)
@@ -0,0 +1,73 @@
+package entities
+
+import (
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Conversation is the last word answered in a chat, follow-up questions refer to it
+type Conversation struct {
+	ChatID   int64
+	Word     string
+	Language string
+	Level    Level
+	// Meaning is the index of the shown interpretation, negative when the answer shows all of them
+	Meaning   int
+	UpdatedAt time.Time
+}
+
+// FollowUp is a question about the last word of the conversation
+type FollowUp string
+
+const (
+	FollowUpPlural         FollowUp = "plural"
+	FollowUpAccusative     FollowUp = "accusative"
+	FollowUpDative         FollowUp = "dative"
+	FollowUpGenitive       FollowUp = "genitive"
+	FollowUpAnotherMeaning FollowUp = "another_meaning"
+)
+
+// followUpKeywords lists the English, German and Russian phrasings of every follow-up in matching order,
+// so "another meaning in dative" asks for the meaning
+var followUpKeywords = []struct {
+	followUp FollowUp
+	keywords []string
+}{
+	{FollowUpAnotherMeaning, []string{"another meaning", "other meaning", "next meaning", "andere bedeutung", "другое значение", "ещё значение", "еще значение"}},
+	{FollowUpDative, []string{"dativ", "датив", "дательн"}},
+	{FollowUpAccusative, []string{"accusativ", "akkusativ", "аккузатив", "винительн"}},
+	{FollowUpGenitive, []string{"genitiv", "генитив", "родительн"}},
+	{FollowUpPlural, []string{"plural", "mehrzahl", "множествен"}},
+}
+
+// ParseFollowUp recognizes follow-up questions like "plural?", "example in dative?" or "another meaning".
+// A single capitalized word is a noun to look up (der Plural, der Dativ), not a question.
+func ParseFollowUp(text string) (FollowUp, bool) {
+	text = strings.TrimSpace(text)
+	first, _ := utf8.DecodeRuneInString(text)
+	if !strings.HasSuffix(text, "?") && !strings.Contains(text, " ") && !unicode.IsLower(first) {
+		return "", false
+	}
+
+	lower := strings.ToLower(text)
+	for _, candidate := range followUpKeywords {
+		for _, keyword := range candidate.keywords {
+			if strings.Contains(lower, keyword) {
+				return candidate.followUp, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// FollowUpAnswer is the answer to a follow-up question about the last word of the conversation
+type FollowUpAnswer struct {
+	FollowUp FollowUp
+	Word     string
+	// Meaning is the index of the interpretation in Response, negative when Response holds all of them
+	Meaning  int
+	Response *ArticleResponse
+}
@@ -0,0 +1,139 @@
+package entities
+
+import (
+	"strings"
+	"time"
+)
+
+// genderArticles maps the spellings of the genders accepted by the dictionary imports to their articles
+var genderArticles = map[string]string{
+	"der": "der", "masculine": "der", "m": "der",
+	"die": "die", "feminine": "die", "f": "die",
+	"das": "das", "neuter": "das", "n": "das",
+}
+
+// ArticleOfGender returns the definite article of the gender, given as an article, a gender name or its
+// first letter, ok is false for other values
+func ArticleOfGender(gender string) (article string, ok bool) {
+	article, ok = genderArticles[strings.ToLower(strings.TrimSpace(gender))]
+	return article, ok
+}
+
+// CuratedEntry is a noun verified by the operators and imported into the dictionary, its article, plural
+// and translations win over the AI answers
+type CuratedEntry struct {
+	Word    string `json:"word"`
+	Article string `json:"article"`
+	// Plural is the plural with its article, like "die Häuser", empty for nouns without one
+	Plural string `json:"plural,omitempty"`
+	// Translations are keyed by the ISO 639-1 code of their language
+	Translations map[string]string `json:"translations,omitempty"`
+	ImportedAt   time.Time         `json:"importedAt"`
+}
+
+// CuratedKey returns the key of the curated entry of the word, the lookups are case-insensitive
+func CuratedKey(word string) string {
+	return strings.ToLower(strings.TrimSpace(word))
+}
+
+// Key returns the key of the entry
+func (e *CuratedEntry) Key() string {
+	return CuratedKey(e.Word)
+}
+
+// Info returns the interpretation of the entry with its translation into the language, empty when the
+// entry has none
+func (e *CuratedEntry) Info(language string) ArticleInfo {
+	return ArticleInfo{
+		WordWithArticle: e.Article + " " + e.Word,
+		Translation:     e.Translations[strings.ToLower(language)],
+		Plural:          e.Plural,
+	}
+}
+
+// Response returns the answer of the entry in the language
+func (e *CuratedEntry) Response(language string) *ArticleResponse {
+	response := &ArticleResponse{Success: true, Data: []ArticleInfo{e.Info(language)}}
+	response.Stamp(SourceDictionary, time.Now())
+
+	return response
+}
+
+// Answer returns the answer of the request without example sentences, ok is false for a nil entry, for
+// requests of the full answer and for languages the entry has no translation into
+func (e *CuratedEntry) Answer(request *ArticleRequest) (response *ArticleResponse, ok bool) {
+	if e == nil || request.Kind() != RequestKindArticle || e.Translations[strings.ToLower(request.Language)] == "" {
+		return nil, false
+	}
+
+	return e.Response(request.Language), true
+}
+
+// Correct overrides the interpretations of the noun of the entry in an AI answer with the article, the plural
+// and the translation of the entry. The examples, the mnemonic and the declension of an interpretation with
+// another article were written for the wrong gender, so they are dropped. Answers without the noun get the
+// entry as their first interpretation. Changed answers become mixed, a nil entry leaves the answer as it is.
+func (e *CuratedEntry) Correct(response *ArticleResponse, language string) {
+	if e == nil || response == nil || !response.Success {
+		return
+	}
+
+	curated := e.Info(language)
+	found, changed := false, false
+	for i, info := range response.Data {
+		if !strings.EqualFold(info.Noun(), e.Word) {
+			continue
+		}
+		found = true
+		original := info
+		if info.Article() != e.Article {
+			info.Example = ExamplesInfo{}
+			info.Mnemonic = ""
+			info.Declension = nil
+		}
+		info.WordWithArticle = curated.WordWithArticle
+		if curated.Plural != "" {
+			info.Plural = curated.Plural
+		}
+		if curated.Translation != "" {
+			info.Translation = curated.Translation
+		}
+		response.Data[i] = info
+		changed = changed || info.WordWithArticle != original.WordWithArticle || info.Plural != original.Plural ||
+			info.Translation != original.Translation
+	}
+	if !found {
+		response.Data = append([]ArticleInfo{curated}, response.Data...)
+		changed = true
+	}
+	if changed {
+		response.Source = SourceMixed
+	}
+}
+
+// DictionaryImportRow is an entry of an imported dictionary file before its validation
+type DictionaryImportRow struct {
+	// Line is the line of the entry in the file, the header of a CSV file is line 1
+	Line         int               `json:"line"`
+	Word         string            `json:"word"`
+	Gender       string            `json:"gender"`
+	Plural       string            `json:"plural,omitempty"`
+	Translations map[string]string `json:"translations,omitempty"`
+}
+
+// DictionaryImportError is an entry of an imported dictionary file that failed the validation
+type DictionaryImportError struct {
+	Line  int    `json:"line"`
+	Word  string `json:"word,omitempty"`
+	Error string `json:"error"`
+}
+
+// DictionaryImportResult is the outcome of a dictionary import, a dry run validates the entries without
+// saving them
+type DictionaryImportResult struct {
+	DryRun bool `json:"dryRun"`
+	// Imported is the number of saved entries, zero for a dry run
+	Imported int                     `json:"imported"`
+	Entries  []CuratedEntry          `json:"entries"`
+	Errors   []DictionaryImportError `json:"errors"`
+}
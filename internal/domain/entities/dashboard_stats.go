@@ -0,0 +1,79 @@
+package entities
+
+import "time"
+
+// DashboardStats is a point-in-time snapshot of the bot usage metrics
+type DashboardStats struct {
+	GeneratedAt time.Time  `json:"generatedAt"`
+	Since       time.Time  `json:"since"`
+	TopWords    []WordStat `json:"topWords"`
+	// LookupsByAdapter counts the lookups of each entry point, background lookups aren't counted
+	LookupsByAdapter map[Adapter]int64 `json:"lookupsByAdapter"`
+	Cache            CacheStats        `json:"cache"`
+	AI               AIStats           `json:"ai"`
+	Telegram         TelegramStats     `json:"telegram"`
+	Quota            QuotaStats        `json:"quota"`
+	Spend            SpendStats        `json:"spend"`
+}
+
+// WordStat holds the lookup counter of a single word
+type WordStat struct {
+	Word    string `json:"word"`
+	Lookups int64  `json:"lookups"`
+}
+
+// WordStatOrderFields are the fields word lists can be ordered by, most looked-up first by default
+var WordStatOrderFields = []string{"lookups", "word"}
+
+// WordStatFilter narrows the listed words, zero values match everything
+type WordStatFilter struct {
+	Prefix string
+	Page   PageRequest
+}
+
+// CacheStats holds cache hit/miss counters
+type CacheStats struct {
+	Hits    int64   `json:"hits"`
+	Misses  int64   `json:"misses"`
+	HitRate float64 `json:"hitRate"`
+}
+
+// AIStats holds AI service call counters
+type AIStats struct {
+	Calls     int64   `json:"calls"`
+	Errors    int64   `json:"errors"`
+	ErrorRate float64 `json:"errorRate"`
+}
+
+// TelegramStats holds Telegram user activity counters
+type TelegramStats struct {
+	ActiveUsers24h int        `json:"activeUsers24h"`
+	ActiveUsers7d  int        `json:"activeUsers7d"`
+	Churn          ChurnStats `json:"churn"`
+}
+
+// ChurnStats counts the chats the bot joined and lost
+type ChurnStats struct {
+	GroupsAdded   int64 `json:"groupsAdded"`
+	GroupsRemoved int64 `json:"groupsRemoved"`
+	Blocked       int64 `json:"blocked"`
+	Unblocked     int64 `json:"unblocked"`
+}
+
+// QuotaStats holds the daily AI quota usage
+type QuotaStats struct {
+	Date      string `json:"date"`
+	Used      int64  `json:"used"`
+	Limit     int64  `json:"limit,omitempty"`
+	Remaining int64  `json:"remaining,omitempty"`
+}
+
+// SpendStats holds the estimated AI spend of the month in USD
+type SpendStats struct {
+	Month     string  `json:"month"`
+	Spent     float64 `json:"spent"`
+	Cap       float64 `json:"cap,omitempty"`
+	Remaining float64 `json:"remaining,omitempty"`
+	// Exceeded is set when lookups are answered from the cache and the dictionary only
+	Exceeded bool `json:"exceeded,omitempty"`
+}
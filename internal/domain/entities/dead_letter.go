@@ -0,0 +1,26 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// DeadLetter is a Telegram update whose processing failed, kept with its raw body so it can be replayed
+// after a fix instead of losing the message of the user
+type DeadLetter struct {
+	ID       string          `json:"id"`
+	UpdateID int             `json:"updateId"`
+	Update   json.RawMessage `json:"update"`
+	// UserID is the sender of the update, 0 for updates without one, so the letter is deleted with the user data
+	UserID int64  `json:"userId,omitempty"`
+	Error  string `json:"error"`
+	// Attempts counts the failed replays of the update
+	Attempts int       `json:"attempts"`
+	FailedAt time.Time `json:"failedAt"`
+}
+
+// DeadLetterReplay is the outcome of replaying dead-lettered updates, successfully replayed ones are removed
+type DeadLetterReplay struct {
+	Replayed int `json:"replayed"`
+	Failed   int `json:"failed"`
+}
@@ -0,0 +1,71 @@
+package entities
+
+import "strings"
+
+// Degradation names why a lookup was answered without the AI
+type Degradation string
+
+const (
+	// DegradationAIUnavailable is set while the AI keeps failing, the article comes from the dictionary
+	DegradationAIUnavailable Degradation = "ai_unavailable"
+	// DegradationDeadlineExceeded is set when the AI ran out of the request deadline
+	DegradationDeadlineExceeded Degradation = "deadline_exceeded"
+	// DegradationBudgetExceeded is set while the monthly AI spend cap is hit
+	DegradationBudgetExceeded Degradation = "budget_exceeded"
+)
+
+// degradationNotices explain the missing examples of dictionary answers
+var degradationNotices = map[Degradation]map[string]string{
+	DegradationAIUnavailable: {
+		"en": "Examples are temporarily unavailable, the article comes from the dictionary.",
+		"ru": "Примеры временно недоступны, артикль взят из словаря.",
+		"de": "Beispiele sind vorübergehend nicht verfügbar, der Artikel stammt aus dem Wörterbuch.",
+	},
+	DegradationDeadlineExceeded: {
+		"en": "The examples took too long this time, the article comes from the dictionary.",
+		"ru": "Примеры не успели сгенерироваться, артикль взят из словаря.",
+		"de": "Die Beispiele haben diesmal zu lange gedauert, der Artikel stammt aus dem Wörterbuch.",
+	},
+	DegradationBudgetExceeded: {
+		"en": "The bot is in reduced mode this month, the article comes from the dictionary.",
+		"ru": "В этом месяце бот работает в ограниченном режиме, артикль взят из словаря.",
+		"de": "Der Bot läuft diesen Monat eingeschränkt, der Artikel stammt aus dem Wörterbuch.",
+	},
+}
+
+// degradationErrors explain why words missing from the dictionary can't be answered
+var degradationErrors = map[Degradation]map[string]string{
+	DegradationAIUnavailable: {
+		"en": "The article service is temporarily unavailable and the word isn't in the dictionary. Please try again in a few minutes.",
+		"ru": "Сервис артиклей временно недоступен, а слова нет в словаре. Пожалуйста, попробуйте через несколько минут.",
+		"de": "Der Artikeldienst ist vorübergehend nicht verfügbar und das Wort steht nicht im Wörterbuch. Bitte versuche es in ein paar Minuten erneut.",
+	},
+	DegradationDeadlineExceeded: {
+		"en": "The answer took too long and the word isn't in the dictionary. Please try again.",
+		"ru": "Ответ занял слишком много времени, а слова нет в словаре. Пожалуйста, попробуйте ещё раз.",
+		"de": "Die Antwort hat zu lange gedauert und das Wort steht nicht im Wörterbuch. Bitte versuche es erneut.",
+	},
+	DegradationBudgetExceeded: {
+		"en": "The bot is in reduced mode this month and only answers the articles of dictionary words. Please try again later.",
+		"ru": "В этом месяце бот работает в ограниченном режиме и отвечает только артиклями слов из словаря. Пожалуйста, попробуйте позже.",
+		"de": "Der Bot läuft diesen Monat eingeschränkt und beantwortet nur die Artikel von Wörterbuchwörtern. Bitte versuche es später erneut.",
+	},
+}
+
+// Notice returns the explanation of a dictionary answer in the language or in English
+func (d Degradation) Notice(language string) string {
+	return localizedDegradation(degradationNotices[d], language)
+}
+
+// Unavailable returns the explanation of a word missing from the dictionary in the language or in English
+func (d Degradation) Unavailable(language string) string {
+	return localizedDegradation(degradationErrors[d], language)
+}
+
+func localizedDegradation(texts map[string]string, language string) string {
+	if text, ok := texts[strings.ToLower(language)]; ok {
+		return text
+	}
+
+	return texts["en"]
+}
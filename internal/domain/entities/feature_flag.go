@@ -0,0 +1,42 @@
+package entities
+
+import (
+	"hash/fnv"
+	"slices"
+	"strconv"
+)
+
+// Feature names a feature rolled out gradually with a feature flag
+type Feature string
+
+const (
+	// FeatureQuiz is the /quiz article practice of the bot
+	FeatureQuiz Feature = "quiz"
+)
+
+// FeatureFlag is the rollout of a feature: chats listed in Chats always get it, chats in DisabledChats
+// never do, and the others get it when it's Enabled or their chat falls into the Percentage
+type FeatureFlag struct {
+	Feature       Feature `json:"feature"`
+	Enabled       bool    `json:"enabled"`
+	Percentage    int     `json:"percentage,omitempty"`
+	Chats         []int64 `json:"chats,omitempty"`
+	DisabledChats []int64 `json:"disabledChats,omitempty"`
+}
+
+// EnabledFor reports whether the chat gets the feature, a chat stays in or out of a percentage
+// rollout as long as the percentage doesn't change
+func (f FeatureFlag) EnabledFor(chatID int64) bool {
+	switch {
+	case slices.Contains(f.DisabledChats, chatID):
+		return false
+	case f.Enabled || slices.Contains(f.Chats, chatID):
+		return true
+	case f.Percentage <= 0:
+		return false
+	}
+
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(string(f.Feature) + ":" + strconv.FormatInt(chatID, 10)))
+	return int(hash.Sum32()%100) < f.Percentage
+}
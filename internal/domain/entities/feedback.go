@@ -0,0 +1,38 @@
+package entities
+
+import "time"
+
+// Verdict is the user rating of an answer
+type Verdict string
+
+const (
+	VerdictUp   Verdict = "up"
+	VerdictDown Verdict = "down"
+)
+
+// Valid reports whether the verdict is known
+func (v Verdict) Valid() bool {
+	return v == VerdictUp || v == VerdictDown
+}
+
+// Feedback represents a user rating of the answer to a word lookup
+type Feedback struct {
+	ID        string           `json:"id"`
+	Word      string           `json:"word"`
+	Language  string           `json:"language"`
+	Verdict   Verdict          `json:"verdict"`
+	UserID    int64            `json:"userId,omitempty"`
+	Answer    *ArticleResponse `json:"answer,omitempty"`
+	CreatedAt time.Time        `json:"createdAt"`
+}
+
+// FeedbackOrderFields are the fields feedback lists can be ordered by, newest first by default
+var FeedbackOrderFields = []string{"createdAt", "word"}
+
+// FeedbackFilter narrows the listed feedback, zero values match everything
+type FeedbackFilter struct {
+	Verdict  Verdict
+	Word     string
+	Language string
+	Page     PageRequest
+}
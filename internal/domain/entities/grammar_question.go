@@ -0,0 +1,53 @@
+package entities
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxGrammarQuestionLength bounds the length of a grammar question in characters
+const MaxGrammarQuestionLength = 500
+
+// GrammarQuestion is a free-form question about German grammar
+type GrammarQuestion struct {
+	Question string
+	Language string
+}
+
+// NewGrammarQuestion creates a new grammar question answered in the language
+func NewGrammarQuestion(question, language string) *GrammarQuestion {
+	if language == "" {
+		language = "en" // default to English
+	}
+	return &GrammarQuestion{
+		Question: strings.TrimSpace(question),
+		Language: language,
+	}
+}
+
+// IsValid checks that the question is neither empty nor longer than MaxGrammarQuestionLength
+func (q *GrammarQuestion) IsValid() bool {
+	return q.Question != "" && utf8.RuneCountInString(q.Question) <= MaxGrammarQuestionLength
+}
+
+// GrammarAnswer is the tutor's answer to a grammar question
+type GrammarAnswer struct {
+	Success  bool             `json:"success"`
+	Error    string           `json:"error,omitempty"`
+	Answer   string           `json:"answer,omitempty"`
+	Examples []GrammarExample `json:"examples,omitempty"`
+}
+
+// GrammarExample is a German sentence illustrating the answer
+type GrammarExample struct {
+	German      string `json:"german"`
+	Translation string `json:"translation"`
+}
+
+// NewGrammarErrorAnswer creates an answer reporting the error
+func NewGrammarErrorAnswer(err string) *GrammarAnswer {
+	return &GrammarAnswer{
+		Success: false,
+		Error:   err,
+	}
+}
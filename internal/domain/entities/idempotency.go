@@ -0,0 +1,13 @@
+package entities
+
+// IdempotentResponse is the response of a request sent with an idempotency key, it is replayed to the
+// retries of the request instead of processing them again
+type IdempotentResponse struct {
+	// Fingerprint identifies the request the key was first used with
+	Fingerprint string
+	// Completed is false while the first request is still processed
+	Completed  bool
+	StatusCode int
+	Header     map[string]string
+	Body       []byte
+}
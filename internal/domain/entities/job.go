@@ -0,0 +1,54 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// JobType identifies the handler of a background job
+type JobType string
+
+// Job is a unit of long-running work processed outside the request that created it
+type Job struct {
+	ID        string          `json:"id"`
+	Type      JobType         `json:"type"`
+	Payload   json.RawMessage `json:"payload"`
+	RequestID string          `json:"requestId,omitempty"`
+	// UserID is the user the job works for, its state is deleted with the data of the user
+	UserID    int64     `json:"userId,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	// Attempt is the zero-based delivery attempt, set by the worker
+	Attempt int `json:"-"`
+}
+
+// JobState is the processing state of a background job
+type JobState string
+
+const (
+	JobQueued  JobState = "queued"
+	JobRunning JobState = "running"
+	JobDone    JobState = "done"
+	JobFailed  JobState = "failed"
+)
+
+// JobStatus is the progress and outcome of a background job, polled by the clients that started it
+type JobStatus struct {
+	ID    string   `json:"id"`
+	Type  JobType  `json:"type"`
+	State JobState `json:"state"`
+	// Progress is reported by jobs processing a list, like vocabulary imports
+	Progress *JobProgress `json:"progress,omitempty"`
+	// Result is the outcome of a done job, jobs delivering it elsewhere may leave it empty
+	Result    json.RawMessage `json:"result,omitempty"`
+	Error     string          `json:"error,omitempty"`
+	Attempts  int             `json:"attempts"`
+	UserID    int64           `json:"userId,omitempty"`
+	CreatedAt time.Time       `json:"createdAt"`
+	UpdatedAt time.Time       `json:"updatedAt"`
+}
+
+// JobProgress counts the processed items of a job
+type JobProgress struct {
+	Done  int `json:"done"`
+	Total int `json:"total"`
+}
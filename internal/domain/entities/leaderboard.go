@@ -0,0 +1,57 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+)
+
+// LeaderboardGlobal is the chat ID of the leaderboard across all chats
+const LeaderboardGlobal int64 = 0
+
+// LeaderboardWeek returns the ISO week of t, like "2026-W42", the leaderboards start over every Monday
+func LeaderboardWeek(t time.Time) string {
+	year, week := t.UTC().ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
+
+// LeaderboardMember is a user who opted in to the quiz leaderboard, only the display name is ever shown
+type LeaderboardMember struct {
+	UserID   int64     `json:"userId"`
+	Name     string    `json:"name"`
+	Language string    `json:"language"`
+	JoinedAt time.Time `json:"joinedAt"`
+}
+
+// LeaderboardScore counts the quiz answers of a member in a week
+type LeaderboardScore struct {
+	UserID  int64 `json:"userId"`
+	Answers int   `json:"answers"`
+	Correct int   `json:"correct"`
+}
+
+// LeaderboardEntry is a ranked member of a leaderboard, ranked by correct answers and then by accuracy
+type LeaderboardEntry struct {
+	Rank    int    `json:"rank"`
+	UserID  int64  `json:"-"`
+	Name    string `json:"name"`
+	Answers int    `json:"answers"`
+	Correct int    `json:"correct"`
+}
+
+// Leaderboard is the ranking of a week in a chat, LeaderboardGlobal for the ranking across all chats
+type Leaderboard struct {
+	Week    string             `json:"week"`
+	ChatID  int64              `json:"chatId"`
+	Entries []LeaderboardEntry `json:"entries"`
+}
+
+// Entry returns the entry of the user, nil for users not on the leaderboard
+func (l *Leaderboard) Entry(userID int64) *LeaderboardEntry {
+	for i := range l.Entries {
+		if l.Entries[i].UserID == userID {
+			return &l.Entries[i]
+		}
+	}
+
+	return nil
+}
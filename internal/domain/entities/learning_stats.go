@@ -0,0 +1,71 @@
+package entities
+
+import "time"
+
+// ActivityDayLayout formats the days of the learning activity, local to the time zone of the user
+const ActivityDayLayout = "2006-01-02"
+
+// QuizAnswer is the answer of a user to a quiz question
+type QuizAnswer struct {
+	Noun       string    `json:"noun"`
+	Article    string    `json:"article"`
+	Answer     string    `json:"answer"`
+	Correct    bool      `json:"correct"`
+	AnsweredAt time.Time `json:"answeredAt"`
+}
+
+// DailyActivity counts the activity of a user on a day of their time zone, a word looked up several times a day counts once
+type DailyActivity struct {
+	Day         string `json:"day"`
+	Lookups     int    `json:"lookups"`
+	QuizAnswers int    `json:"quizAnswers"`
+	QuizCorrect int    `json:"quizCorrect"`
+}
+
+// ArticleAccuracy counts the quiz answers to nouns of an article
+type ArticleAccuracy struct {
+	Article string  `json:"article"`
+	Answers int     `json:"answers"`
+	Correct int     `json:"correct"`
+	Rate    float64 `json:"rate"`
+}
+
+// ConfusedWord is a noun the user answered wrong in the quiz
+type ConfusedWord struct {
+	Noun     string `json:"noun"`
+	Article  string `json:"article"`
+	Mistakes int    `json:"mistakes"`
+}
+
+// UserActivity is the stored learning activity of a user
+type UserActivity struct {
+	UserID int64 `json:"userId"`
+	// Days are the days with any activity, oldest first
+	Days []DailyActivity `json:"days"`
+	// Words counts the distinct words ever looked up
+	Words int `json:"words"`
+	// LookedUp maps the words ever looked up to the last day they were counted on
+	LookedUp map[string]string `json:"lookedUp,omitempty"`
+	// Glossary holds the article and the translation of the last answer of the looked up words
+	Glossary []LookedUpWord    `json:"glossary,omitempty"`
+	Articles []ArticleAccuracy `json:"articles"`
+	Confused []ConfusedWord    `json:"confused"`
+	// Mastered are the nouns whose last quiz answer was correct
+	Mastered []string `json:"mastered"`
+}
+
+// LearningStats summarizes the learning activity of a user
+type LearningStats struct {
+	// CurrentStreak counts the consecutive active days up to today, a streak lasts until a day passes without activity
+	CurrentStreak int               `json:"currentStreak"`
+	LongestStreak int               `json:"longestStreak"`
+	ActiveDays    int               `json:"activeDays"`
+	LastActiveDay string            `json:"lastActiveDay,omitempty"`
+	WordsLookedUp int               `json:"wordsLookedUp"`
+	Lookups       int               `json:"lookups"`
+	QuizAnswers   int               `json:"quizAnswers"`
+	QuizCorrect   int               `json:"quizCorrect"`
+	Accuracy      []ArticleAccuracy `json:"accuracy"`
+	ConfusedWords []ConfusedWord    `json:"confusedWords"`
+	RecentDays    []DailyActivity   `json:"recentDays"`
+}
@@ -0,0 +1,61 @@
+package entities
+
+import "strings"
+
+// Level is a CEFR-like difficulty level derived from the noun frequency
+type Level string
+
+const (
+	LevelA1 Level = "A1"
+	LevelA2 Level = "A2"
+	LevelB1 Level = "B1"
+	LevelB2 Level = "B2"
+	LevelC1 Level = "C1"
+	LevelC2 Level = "C2"
+)
+
+// Levels lists the levels from the easiest to the hardest
+var Levels = []Level{LevelA1, LevelA2, LevelB1, LevelB2, LevelC1, LevelC2}
+
+// levelRanks is the highest frequency rank of each level, rarer nouns are C2
+var levelRanks = []struct {
+	level Level
+	rank  int
+}{
+	{LevelA1, 1000},
+	{LevelA2, 2000},
+	{LevelB1, 4000},
+	{LevelB2, 8000},
+	{LevelC1, 16000},
+}
+
+// ParseLevel returns the level of the case-insensitive name
+func ParseLevel(name string) (Level, bool) {
+	level := Level(strings.ToUpper(strings.TrimSpace(name)))
+	return level, level.Valid()
+}
+
+// Valid reports whether the level is one of the supported levels
+func (l Level) Valid() bool {
+	for _, level := range Levels {
+		if l == level {
+			return true
+		}
+	}
+
+	return false
+}
+
+// LevelForRank returns the level of the frequency rank, empty for an unknown rank
+func LevelForRank(rank int) Level {
+	if rank <= 0 {
+		return ""
+	}
+	for _, threshold := range levelRanks {
+		if rank <= threshold.rank {
+			return threshold.level
+		}
+	}
+
+	return LevelC2
+}
@@ -0,0 +1,53 @@
+package entities
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+// ErrInvalidPageToken is returned for page tokens that weren't issued for the list and its order
+var ErrInvalidPageToken = errors.New("invalid page token")
+
+// PageRequest selects a page of a list, an empty OrderBy keeps the default order of the list
+type PageRequest struct {
+	Limit      int
+	PageToken  string
+	OrderBy    string
+	Descending bool
+}
+
+// Page is one page of a list, NextPageToken is empty on the last page
+type Page[T any] struct {
+	Items         []T    `json:"items"`
+	NextPageToken string `json:"nextPageToken,omitempty"`
+}
+
+// PageCursor is the position after the last item of a page: the order it was issued for, the sort key
+// and the ID of the item
+type PageCursor struct {
+	OrderBy    string `json:"o"`
+	Descending bool   `json:"d,omitempty"`
+	Key        string `json:"k"`
+	ID         string `json:"i"`
+}
+
+// EncodePageToken returns the opaque token of the cursor
+func EncodePageToken(cursor PageCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodePageToken reads the cursor of a token issued by EncodePageToken
+func DecodePageToken(token string) (PageCursor, error) {
+	var cursor PageCursor
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return cursor, ErrInvalidPageToken
+	}
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return cursor, ErrInvalidPageToken
+	}
+
+	return cursor, nil
+}
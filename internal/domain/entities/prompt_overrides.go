@@ -0,0 +1,23 @@
+package entities
+
+import "time"
+
+// PromptOverrides are the prompt fragments a tenant customizes for the example sentences of its lookups
+type PromptOverrides struct {
+	TenantID string `json:"tenantId"`
+	// Tone of the example sentences like "playful" or "business"
+	Tone string `json:"tone,omitempty"`
+	// ExampleDomains replace the example topics of the configuration of the tenant
+	ExampleDomains []string `json:"exampleDomains,omitempty"`
+	// AudienceAge is the age of the learners the example sentences are written for, zero for any age
+	AudienceAge int `json:"audienceAge,omitempty"`
+	// UpdatedAt is when the overrides were saved, nil for the defaults
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+}
+
+// PromptPreview is the prompt of a lookup of a tenant rendered with its overrides, nothing is generated
+type PromptPreview struct {
+	Word     string `json:"word"`
+	Language string `json:"language"`
+	Prompt   string `json:"prompt"`
+}
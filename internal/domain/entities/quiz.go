@@ -0,0 +1,21 @@
+package entities
+
+import "strings"
+
+// DictionaryEntry is a noun of the reference dictionary with its article
+type DictionaryEntry struct {
+	Noun    string `json:"noun"`
+	Article string `json:"article"`
+}
+
+// QuizQuestion asks for the article of a dictionary noun
+type QuizQuestion struct {
+	Noun    string `json:"noun"`
+	Article string `json:"-"`
+	Level   Level  `json:"level,omitempty"`
+}
+
+// Check reports whether the answer is the article of the noun, ignoring case and surrounding spaces
+func (q *QuizQuestion) Check(answer string) bool {
+	return strings.EqualFold(strings.TrimSpace(answer), q.Article)
+}
@@ -0,0 +1,39 @@
+package entities
+
+import "time"
+
+// Reminder is the daily practice reminder of a user, sent to the private chat with the bot
+type Reminder struct {
+	UserID int64 `json:"userId"`
+	// BotID is the bot the reminder was set with, it's sent by the same bot
+	BotID int64 `json:"botId,omitempty"`
+	// Time is the local time of the reminder, like 19:00
+	Time string `json:"time"`
+	// Timezone is the IANA time zone of the local time, like Europe/Berlin
+	Timezone string `json:"timezone"`
+	Language string `json:"language"`
+	// NextAt is the next time the reminder is due, moved by sending and snoozing it
+	NextAt    time.Time `json:"nextAt"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Location returns the time zone of the reminder, UTC for unknown zones
+func (r *Reminder) Location() *time.Location {
+	return TimezoneLocation(r.Timezone)
+}
+
+// Next returns the first time of the day of the reminder after the time, in the time zone of the reminder
+func (r *Reminder) Next(after time.Time) time.Time {
+	clock, err := time.Parse("15:04", r.Time)
+	if err != nil {
+		return after.Add(24 * time.Hour)
+	}
+
+	local := after.In(r.Location())
+	next := time.Date(local.Year(), local.Month(), local.Day(), clock.Hour(), clock.Minute(), 0, 0, local.Location())
+	if !next.After(after) {
+		next = time.Date(local.Year(), local.Month(), local.Day()+1, clock.Hour(), clock.Minute(), 0, 0, local.Location())
+	}
+
+	return next.UTC()
+}
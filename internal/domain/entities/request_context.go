@@ -0,0 +1,62 @@
+package entities
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// Adapter names the entry point a request came through
+type Adapter string
+
+const (
+	AdapterHTTP     Adapter = "http"
+	AdapterTelegram Adapter = "telegram"
+	AdapterConsole  Adapter = "console"
+	AdapterMCP      Adapter = "mcp"
+)
+
+// RequestContext is the metadata of a request every adapter provides the same way, so logging, analytics,
+// quotas and caching key off it instead of each adapter improvising
+type RequestContext struct {
+	Adapter Adapter `json:"adapter"`
+	// Subject is the hash of the requesting user, or of the chat of updates without a sender, empty for
+	// anonymous requests. Raw user and chat IDs stay out of logs and keys.
+	Subject string `json:"subject,omitempty"`
+	// ClientIP is the address of HTTP clients
+	ClientIP string `json:"clientIp,omitempty"`
+	// Locale is the ISO 639-1 code of the language answers are given in
+	Locale    string `json:"locale,omitempty"`
+	RequestID string `json:"requestId,omitempty"`
+	// Tenant is the ID of the tenant whose API key the request carries, empty for requests of no tenant
+	Tenant string `json:"tenant,omitempty"`
+}
+
+// HashSubject returns the subject of a client ID like "telegram:42", the same user hashes to the same
+// subject whichever adapter it came through
+func HashSubject(clientID string) string {
+	sum := sha256.Sum256([]byte(clientID))
+	return hex.EncodeToString(sum[:8])
+}
+
+// ClientKey returns the key of the per-client quotas: the subject, the client IP of anonymous HTTP
+// requests, or the adapter when neither is known. The clients of a tenant are kept apart from the others.
+func (r *RequestContext) ClientKey() string {
+	if r == nil {
+		return ""
+	}
+
+	var key string
+	switch {
+	case r.Subject != "":
+		key = "subject:" + r.Subject
+	case r.ClientIP != "":
+		key = "ip:" + r.ClientIP
+	default:
+		key = string(r.Adapter)
+	}
+	if r.Tenant != "" {
+		key = "tenant:" + r.Tenant + "|" + key
+	}
+
+	return key
+}
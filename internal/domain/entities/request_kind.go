@@ -0,0 +1,27 @@
+package entities
+
+// RequestKind classifies AI requests by their complexity, every kind may be answered by its own model
+type RequestKind string
+
+const (
+	// RequestKindArticle is a lookup of the article and translation without example sentences
+	RequestKindArticle RequestKind = "article"
+	// RequestKindFull is a lookup with the 16 example sentences of every interpretation
+	RequestKindFull        RequestKind = "full"
+	RequestKindTranslation RequestKind = "translation"
+	RequestKindGrammar     RequestKind = "grammar"
+)
+
+// RequestKinds lists every request kind
+var RequestKinds = []RequestKind{RequestKindArticle, RequestKindFull, RequestKindTranslation, RequestKindGrammar}
+
+// Valid reports whether the kind is known
+func (k RequestKind) Valid() bool {
+	for _, kind := range RequestKinds {
+		if k == kind {
+			return true
+		}
+	}
+
+	return false
+}
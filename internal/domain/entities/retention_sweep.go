@@ -0,0 +1,17 @@
+package entities
+
+import "time"
+
+// RetentionSweep purges the stored user data older than the retention period
+type RetentionSweep struct {
+	// Before is the cutoff, ratings created and users last active before it are purged
+	Before time.Time `json:"before"`
+}
+
+// RetentionSweepResult counts the records purged by a sweep
+type RetentionSweepResult struct {
+	Feedback int `json:"feedback"`
+	Activity int `json:"activity"`
+	// DeadLetters are the failed Telegram updates, they hold the messages and names of the senders
+	DeadLetters int `json:"deadLetters"`
+}
@@ -0,0 +1,11 @@
+package entities
+
+import "time"
+
+// SessionToken is a short-lived bearer token issued by the backend, so browsers don't hold long-lived credentials
+type SessionToken struct {
+	AccessToken string    `json:"accessToken"`
+	TokenType   string    `json:"tokenType"`
+	ExpiresIn   int       `json:"expiresIn"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+}
@@ -0,0 +1,37 @@
+package entities
+
+import "time"
+
+// StreamEventType identifies the part of the answer carried by a stream event
+type StreamEventType string
+
+const (
+	StreamEventArticle     StreamEventType = "article"
+	StreamEventTranslation StreamEventType = "translation"
+	StreamEventExamples    StreamEventType = "examples"
+	StreamEventError       StreamEventType = "error"
+	StreamEventDone        StreamEventType = "done"
+)
+
+// StreamEvent is a partial result of a streamed lookup, Index is the interpretation it belongs to
+type StreamEvent struct {
+	Type          StreamEventType `json:"type"`
+	Index         int             `json:"index"`
+	Value         string          `json:"value,omitempty"`
+	Plural        string          `json:"plural,omitempty"`
+	Examples      *ExamplesInfo   `json:"examples,omitempty"`
+	FrequencyRank int             `json:"frequencyRank,omitempty"`
+	Level         Level           `json:"level,omitempty"`
+	Error         string          `json:"error,omitempty"`
+	Suggestions   []string        `json:"suggestions,omitempty"`
+	// DetectedLanguage is set on error events of words of another language
+	DetectedLanguage string `json:"detectedLanguage,omitempty"`
+	// Partial is set on the done event of answers without their examples
+	Partial bool `json:"partial,omitempty"`
+	// Degraded and Notice are set on the done event of answers from the dictionary
+	Degraded Degradation `json:"degraded,omitempty"`
+	Notice   string      `json:"notice,omitempty"`
+	// Source and GeneratedAt are set on the done event, like on the answer
+	Source      AnswerSource `json:"source,omitempty"`
+	GeneratedAt *time.Time   `json:"generatedAt,omitempty"`
+}
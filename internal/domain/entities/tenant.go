@@ -0,0 +1,22 @@
+package entities
+
+// Tenant is an organization like a language school using the API with its own API keys, quota and
+// customization of the answers
+type Tenant struct {
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+	// DailyQuota bounds the API requests of the tenant per UTC day, zero leaves them unlimited
+	DailyQuota int64 `json:"dailyQuota,omitempty"`
+	// ExampleTopics are the topics the example sentences of the lookups of the tenant are taken from
+	ExampleTopics []string `json:"exampleTopics,omitempty"`
+	// Prompt are the prompt fragments customized by the tenant, nil when it kept the defaults
+	Prompt *PromptOverrides `json:"prompt,omitempty"`
+}
+
+// TenantStats is the usage of a tenant, counted apart from the other tenants
+type TenantStats struct {
+	Tenant Tenant `json:"tenant"`
+	// Quota counts the API requests of the tenant on the current UTC day
+	Quota QuotaStats      `json:"quota"`
+	Usage *DashboardStats `json:"usage"`
+}
@@ -0,0 +1,24 @@
+package entities
+
+import "time"
+
+// ValidTimezone reports whether the name is an IANA time zone. The local zone of the instance isn't the
+// one of a user, so it's not valid.
+func ValidTimezone(name string) bool {
+	if name == "" || name == "Local" {
+		return false
+	}
+	_, err := time.LoadLocation(name)
+
+	return err == nil
+}
+
+// TimezoneLocation returns the IANA time zone of the name, UTC for empty and unknown names
+func TimezoneLocation(name string) *time.Location {
+	if !ValidTimezone(name) {
+		return time.UTC
+	}
+	location, _ := time.LoadLocation(name)
+
+	return location
+}
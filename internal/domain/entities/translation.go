@@ -0,0 +1,43 @@
+package entities
+
+import "strings"
+
+// MaxTranslatedNouns bounds the number of German nouns looked up for a translated word
+const MaxTranslatedNouns = 3
+
+// TranslationRequest asks for the German nouns translating a word of the user's language
+type TranslationRequest struct {
+	Word string
+	// Language is the language of the user, the word is usually written in it and the answers are given in it
+	Language string
+	Level    Level
+}
+
+// NewTranslationRequest creates a new translation request, the word is trimmed but not normalized
+// like a German noun
+func NewTranslationRequest(word, language string) *TranslationRequest {
+	if language == "" {
+		language = "en" // default to English
+	}
+	return &TranslationRequest{
+		Word:     strings.TrimSpace(word),
+		Language: language,
+	}
+}
+
+// IsValid checks if the request is valid
+func (r *TranslationRequest) IsValid() bool {
+	return r.Word != ""
+}
+
+// TranslationResult lists the German nouns translating the word with their answers, most common first
+type TranslationResult struct {
+	Word  string
+	Nouns []TranslatedNoun
+}
+
+// TranslatedNoun is a German noun translating the word with its answer
+type TranslatedNoun struct {
+	Noun     string
+	Response *ArticleResponse
+}
@@ -0,0 +1,27 @@
+package entities
+
+import "time"
+
+// UserDataExport is the machine-readable archive of everything stored about a user
+type UserDataExport struct {
+	UserID      int64            `json:"userId"`
+	ExportedAt  time.Time        `json:"exportedAt"`
+	Preferences *UserPreferences `json:"preferences"`
+	Activity    *UserActivity    `json:"activity"`
+	Feedback    []*Feedback      `json:"feedback"`
+	// Achievements are the awarded achievements, oldest first
+	Achievements []Achievement `json:"achievements"`
+	// Leaderboard is the membership of the weekly quiz leaderboard, nil for users who didn't join
+	Leaderboard *LeaderboardMember `json:"leaderboard,omitempty"`
+	// Chat is the private chat with the bot, nil if the user never talked to the bot directly
+	Chat *KnownChat `json:"chat,omitempty"`
+	// Conversation is the follow-up word of the private chat, nil if there is none or it has expired
+	Conversation *Conversation `json:"conversation,omitempty"`
+	// Reminder is the daily practice reminder, nil if the user didn't set one
+	Reminder *Reminder `json:"reminder,omitempty"`
+	// LinkedIdentities are the external identities of the web app linked to the user
+	LinkedIdentities []Identity `json:"linkedIdentities"`
+	// DeadLetters are the messages of the user whose processing failed, kept with their raw Telegram
+	// updates until they are replayed or purged by the retention sweep
+	DeadLetters []*DeadLetter `json:"deadLetters,omitempty"`
+}
@@ -0,0 +1,21 @@
+package entities
+
+import "time"
+
+// UserPreferences holds the settings a user has chosen in the bot
+type UserPreferences struct {
+	UserID    int64     `json:"userId"`
+	Level     Level     `json:"level,omitempty"`
+	HideHints bool      `json:"hideHints,omitempty"`
+	Verbosity Verbosity `json:"verbosity,omitempty"`
+	// Timezone is the IANA time zone of the user, the days of the streaks and the reminders are local to it
+	Timezone string `json:"timezone,omitempty"`
+	// TimezoneAsked reports whether the user was asked for the time zone, they are asked only once
+	TimezoneAsked bool      `json:"timezoneAsked,omitempty"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+// Location returns the time zone of the user, UTC without one
+func (p *UserPreferences) Location() *time.Location {
+	return TimezoneLocation(p.Timezone)
+}
@@ -0,0 +1,40 @@
+package entities
+
+import "strings"
+
+// Verbosity is the answer profile of a lookup, the prompt asks only for the parts the profile shows
+type Verbosity string
+
+const (
+	// VerbosityMinimal answers with the article and the translation
+	VerbosityMinimal Verbosity = "minimal"
+	// VerbosityStandard adds nominative and accusative examples in the singular
+	VerbosityStandard Verbosity = "standard"
+	// VerbosityFull answers with the examples of all cases, the plural and the hints, it's the default
+	VerbosityFull Verbosity = "full"
+)
+
+// Verbosities lists the profiles from the shortest to the longest answer
+var Verbosities = []Verbosity{VerbosityMinimal, VerbosityStandard, VerbosityFull}
+
+// ParseVerbosity returns the profile of the case-insensitive name
+func ParseVerbosity(name string) (Verbosity, bool) {
+	verbosity := Verbosity(strings.ToLower(strings.TrimSpace(name)))
+	return verbosity, verbosity.Valid()
+}
+
+// Valid reports whether the verbosity is one of the supported profiles
+func (v Verbosity) Valid() bool {
+	for _, verbosity := range Verbosities {
+		if v == verbosity {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Reduced reports whether the profile leaves out parts of the full answer, the empty profile is the full one
+func (v Verbosity) Reduced() bool {
+	return v == VerbosityMinimal || v == VerbosityStandard
+}
@@ -0,0 +1,26 @@
+package entities
+
+import "time"
+
+// VerificationOutcome is the result of re-checking a reported answer
+type VerificationOutcome string
+
+const (
+	VerificationConfirmed    VerificationOutcome = "confirmed"
+	VerificationCorrected    VerificationOutcome = "corrected"
+	VerificationInconclusive VerificationOutcome = "inconclusive"
+)
+
+// Verification is the record of a "wrong article" report checked against independent sources
+type Verification struct {
+	Word          string              `json:"word"`
+	Language      string              `json:"language"`
+	UserID        int64               `json:"userId,omitempty"`
+	Outcome       VerificationOutcome `json:"outcome"`
+	Original      []string            `json:"original,omitempty"`
+	SecondOpinion []string            `json:"secondOpinion,omitempty"`
+	Dictionary    string              `json:"dictionary,omitempty"`
+	// CacheUpdated reports whether the cached answer was replaced with the second opinion
+	CacheUpdated bool      `json:"cacheUpdated"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
@@ -0,0 +1,12 @@
+package entities
+
+// VerificationKey is a public key verifying response signatures, in the JSON Web Key format
+type VerificationKey struct {
+	KeyType   string `json:"kty"`
+	Curve     string `json:"crv"`
+	KeyID     string `json:"kid"`
+	Algorithm string `json:"alg"`
+	Use       string `json:"use"`
+	// X is the base64url encoded public key
+	X string `json:"x"`
+}
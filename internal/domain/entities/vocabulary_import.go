@@ -0,0 +1,23 @@
+package entities
+
+// ImportRow is a successfully resolved interpretation of an imported word
+type ImportRow struct {
+	Word            string `json:"word"`
+	Article         string `json:"article"`
+	WordWithArticle string `json:"wordWithArticle"`
+	Translation     string `json:"translation"`
+	Plural          string `json:"plural,omitempty"`
+}
+
+// ImportFailure is an imported word that couldn't be resolved
+type ImportFailure struct {
+	Word  string `json:"word"`
+	Error string `json:"error"`
+}
+
+// ImportResult is the compiled table of an imported vocabulary list
+type ImportResult struct {
+	Language string          `json:"language"`
+	Rows     []ImportRow     `json:"rows"`
+	Failures []ImportFailure `json:"failures"`
+}
@@ -0,0 +1,96 @@
+package entities
+
+import (
+	"strings"
+	"unicode"
+)
+
+// transliterations maps the ASCII spellings of umlauts to the letters they stand for
+var transliterations = map[string]rune{"ae": 'ä', "oe": 'ö', "ue": 'ü'}
+
+// genuineVowelPairs are word parts where ae, oe or ue are separate vowels, not umlauts
+var genuineVowelPairs = []string{"aero", "israel", "koeff", "maestro", "michael", "poe", "raphael"}
+
+// NormalizeWord prepares user input for prompting and caching: surrounding spaces and punctuation
+// are trimmed, inner spaces collapsed and all-lower and all-upper words capitalized like nouns.
+// ß and ss stay as typed since they tell words apart (Maße, Masse), lowering turns the capital ẞ
+// into ß. ASCII umlaut spellings are kept too, many nouns are spelled with ae, oe or ue (Duett,
+// Silhouette, Koexistenz), so UmlautSpelling only proposes the restored form.
+func NormalizeWord(word string) string {
+	word = strings.Join(strings.Fields(word), " ")
+	trimmed := strings.TrimFunc(word, func(r rune) bool {
+		return unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r)
+	})
+	// Input of only emoji or punctuation is kept for CheckWord to reject it
+	if trimmed == "" {
+		return word
+	}
+	word = trimmed
+
+	if lower := strings.ToLower(word); lower == word || strings.ToUpper(word) == word {
+		runes := []rune(lower)
+		runes[0] = unicode.ToUpper(runes[0])
+		word = string(runes)
+	}
+
+	return word
+}
+
+// UmlautSpelling returns the word with ae, oe and ue of ASCII words replaced by umlauts, "Haeuser"
+// becomes "Häuser", except in diphthongs and combinations where the vowels are pronounced separately
+// (Feuer, Quelle, Duell, Poesie). The heuristic can't tell Duett from a transliteration, so the
+// restored form is only a candidate to check against the dictionary, ok is false when there is none.
+func UmlautSpelling(word string) (restored string, ok bool) {
+	lower := strings.ToLower(word)
+	if strings.ContainsAny(lower, "äöüß") {
+		return word, false
+	}
+	for _, r := range lower {
+		if r > unicode.MaxASCII {
+			return word, false
+		}
+	}
+	for _, part := range genuineVowelPairs {
+		if strings.Contains(lower, part) {
+			return word, false
+		}
+	}
+
+	runes := []rune(word)
+	result := make([]rune, 0, len(runes))
+	for i := 0; i < len(runes); i++ {
+		if i+1 < len(runes) {
+			pair := strings.ToLower(string(runes[i : i+2]))
+			if umlaut, ok := transliterations[pair]; ok && isTransliteration(lower, i) {
+				if unicode.IsUpper(runes[i]) {
+					umlaut = unicode.ToUpper(umlaut)
+				}
+				result = append(result, umlaut)
+				i++
+				continue
+			}
+		}
+		result = append(result, runes[i])
+	}
+	restored = string(result)
+
+	return restored, restored != word
+}
+
+// isTransliteration reports whether the vowel pair at the index of the lower-cased ASCII word
+// stands for an umlaut
+func isTransliteration(lower string, i int) bool {
+	// Vowel pairs at the word end are separate vowels (Aloe, Oboe, Vitae)
+	if i+2 == len(lower) || lower[i+2] == ' ' || lower[i+2] == '-' {
+		return false
+	}
+	if lower[i:i+2] != "ue" {
+		return true
+	}
+	// au-e, eu-e and qu-e are diphthongs and the qu sound (Mauer, Feuer, Quelle), -uell is a suffix (Duell)
+	if i > 0 && strings.ContainsRune("aeq", rune(lower[i-1])) {
+		return false
+	}
+
+	return !strings.HasPrefix(lower[i+2:], "ll")
+}
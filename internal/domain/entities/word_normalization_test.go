@@ -0,0 +1,57 @@
+package entities
+
+import "testing"
+
+func TestNormalizeWord(t *testing.T) {
+	tests := []struct {
+		name string
+		word string
+		want string
+	}{
+		{name: "noun", word: "Haus", want: "Haus"},
+		{name: "lower case", word: "haus", want: "Haus"},
+		{name: "upper case", word: "HAUS", want: "Haus"},
+		{name: "mixed case is kept", word: "iPhone", want: "iPhone"},
+		{name: "surrounding punctuation", word: " «Haus»?! ", want: "Haus"},
+		{name: "inner spaces", word: "der   Tisch", want: "der Tisch"},
+		{name: "capital eszett", word: "STRAẞE", want: "Straße"},
+		{name: "eszett", word: "straße", want: "Straße"},
+		{name: "double s is kept", word: "masse", want: "Masse"},
+		{name: "ascii umlaut is kept", word: "haeuser", want: "Haeuser"},
+		{name: "emoji only", word: "🏠", want: "🏠"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeWord(tt.word); got != tt.want {
+				t.Errorf("NormalizeWord(%q) = %q, want %q", tt.word, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUmlautSpelling(t *testing.T) {
+	tests := []struct {
+		word   string
+		want   string
+		wantOK bool
+	}{
+		{word: "Haeuser", want: "Häuser", wantOK: true},
+		{word: "Aerger", want: "Ärger", wantOK: true},
+		{word: "Feuer", want: "Feuer"},
+		{word: "Quelle", want: "Quelle"},
+		{word: "Duell", want: "Duell"},
+		{word: "Aloe", want: "Aloe"},
+		{word: "Aerosol", want: "Aerosol"},
+		{word: "Häuser", want: "Häuser"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.word, func(t *testing.T) {
+			got, ok := UmlautSpelling(tt.word)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("UmlautSpelling(%q) = %q, %t, want %q, %t", tt.word, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
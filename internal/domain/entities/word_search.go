@@ -0,0 +1,105 @@
+package entities
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// LookedUpWord is a word looked up by a user with the article and the translation of its answer
+type LookedUpWord struct {
+	Word        string `json:"word"`
+	Article     string `json:"article,omitempty"`
+	Translation string `json:"translation,omitempty"`
+}
+
+// WordMatch is a word of the history of a user found by a search, the best matches have the highest score
+type WordMatch struct {
+	Word        string `json:"word"`
+	Article     string `json:"article,omitempty"`
+	Translation string `json:"translation,omitempty"`
+	// LastLookup is the last day the word was looked up, empty for words only met in the quiz
+	LastLookup string `json:"lastLookup,omitempty"`
+	// Mastered reports whether the last quiz answer to the word was correct
+	Mastered bool `json:"mastered,omitempty"`
+	Score    int  `json:"score"`
+}
+
+// translationPenalty ranks matches of the translation below equal matches of the German word
+const translationPenalty = 5
+
+// MatchScore rates how well the text matches the search query, 0 for no match. Exact matches rate
+// highest, followed by prefixes, substrings and words within a small edit distance; case and umlaut
+// spellings don't matter.
+func MatchScore(query, text string) int {
+	query, text = foldWord(query), foldWord(text)
+	switch {
+	case query == "" || text == "":
+		return 0
+	case text == query:
+		return 100
+	case strings.HasPrefix(text, query):
+		return 80
+	case strings.Contains(text, query):
+		return 60
+	}
+
+	length := utf8.RuneCountInString(query)
+	maxDistance := 1
+	if length >= 6 {
+		maxDistance = 2
+	}
+	if distance := EditDistance(query, text); distance <= maxDistance {
+		return 40 - 10*distance
+	}
+	// A typo in the beginning of a longer word, as typed so far
+	if prefix := []rune(text); len(prefix) > length {
+		if distance := EditDistance(query, string(prefix[:length])); distance <= maxDistance {
+			return 30 - 10*distance
+		}
+	}
+
+	return 0
+}
+
+// TranslationScore rates how well the translation or one of its words matches the search query, below
+// the same match of the German word
+func TranslationScore(query, translation string) int {
+	score := MatchScore(query, translation)
+	for _, word := range strings.FieldsFunc(translation, func(r rune) bool { return !unicode.IsLetter(r) }) {
+		score = max(score, MatchScore(query, word))
+	}
+
+	return max(score-translationPenalty, 0)
+}
+
+// EditDistance returns the Levenshtein distance of the words counted in runes
+func EditDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(rb)]
+}
+
+// umlautFolding spells umlauts and ß with their base letters, so searches typed without them match
+var umlautFolding = strings.NewReplacer("ä", "a", "ö", "o", "ü", "u", "ß", "ss")
+
+func foldWord(word string) string {
+	return umlautFolding.Replace(strings.ToLower(strings.TrimSpace(word)))
+}
@@ -0,0 +1,7 @@
+package entities
+
+// WordSuggestion is a known noun with its article suggested for a partial word
+type WordSuggestion struct {
+	Noun    string `json:"noun"`
+	Article string `json:"article"`
+}
@@ -0,0 +1,69 @@
+package entities
+
+import (
+	"strings"
+	"unicode"
+)
+
+// InputProblem describes why the input can't be a German word
+type InputProblem string
+
+const (
+	InputNoLetters InputProblem = "no_letters"
+	InputURL       InputProblem = "url"
+	InputNonLatin  InputProblem = "non_latin"
+)
+
+// inputExplanations holds the localized explanations of the problems, English is the default
+var inputExplanations = map[InputProblem]map[string]string{
+	InputNoLetters: {
+		"en": "Please send a German word, emoji and punctuation alone can't be looked up.",
+		"ru": "Пожалуйста, отправьте немецкое слово, эмодзи и знаки препинания не распознаются.",
+		"de": "Bitte sende ein deutsches Wort, Emojis und Satzzeichen allein können nicht nachgeschlagen werden.",
+	},
+	InputURL: {
+		"en": "Links can't be looked up, please send a single German word.",
+		"ru": "Ссылки не распознаются, пожалуйста, отправьте одно немецкое слово.",
+		"de": "Links können nicht nachgeschlagen werden, bitte sende ein einzelnes deutsches Wort.",
+	},
+	InputNonLatin: {
+		"en": "German words are written in Latin letters, please send the word in German.",
+		"ru": "Немецкие слова пишутся латиницей, пожалуйста, отправьте слово на немецком.",
+		"de": "Deutsche Wörter werden mit lateinischen Buchstaben geschrieben, bitte sende das Wort auf Deutsch.",
+	},
+}
+
+// CheckWord returns the problem of input that can't be a German word, an empty problem for
+// plausible words. Mixed scripts like a Cyrillic "а" in "Hаus" are rejected as non-Latin.
+func CheckWord(word string) InputProblem {
+	lower := strings.ToLower(word)
+	if strings.Contains(lower, "://") || strings.HasPrefix(lower, "www.") {
+		return InputURL
+	}
+
+	var letters bool
+	for _, r := range word {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		if !unicode.Is(unicode.Latin, r) {
+			return InputNonLatin
+		}
+		letters = true
+	}
+	if !letters {
+		return InputNoLetters
+	}
+
+	return ""
+}
+
+// Explanation returns the explanation of the problem in the language or in English
+func (p InputProblem) Explanation(language string) string {
+	texts := inputExplanations[p]
+	if text, ok := texts[strings.ToLower(language)]; ok {
+		return text
+	}
+
+	return texts["en"]
+}
@@ -0,0 +1,38 @@
+package entities
+
+import "testing"
+
+func TestCheckWord(t *testing.T) {
+	tests := []struct {
+		name string
+		word string
+		want InputProblem
+	}{
+		{name: "noun", word: "Haus", want: ""},
+		{name: "umlaut and eszett", word: "Straße", want: ""},
+		{name: "compound with hyphen", word: "E-Mail", want: ""},
+		{name: "phrase", word: "der Tisch", want: ""},
+		{name: "cyrillic", word: "Дом", want: InputNonLatin},
+		{name: "cjk", word: "家", want: InputNonLatin},
+		{name: "japanese kana", word: "ハウス", want: InputNonLatin},
+		{name: "mixed script", word: "Hаus", want: InputNonLatin},
+		{name: "greek letter in latin word", word: "Hαus", want: InputNonLatin},
+		{name: "url with scheme", word: "https://example.com/Haus", want: InputURL},
+		{name: "url in upper case", word: "HTTP://EXAMPLE.COM", want: InputURL},
+		{name: "url without scheme", word: "www.example.com", want: InputURL},
+		{name: "emoji only", word: "🏠", want: InputNoLetters},
+		{name: "several emoji", word: "🐱🐶", want: InputNoLetters},
+		{name: "punctuation only", word: "?!", want: InputNoLetters},
+		{name: "digits only", word: "2025", want: InputNoLetters},
+		{name: "empty", word: "", want: InputNoLetters},
+		{name: "word with emoji", word: "Haus🏠", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CheckWord(tt.word); got != tt.want {
+				t.Errorf("CheckWord(%q) = %q, want %q", tt.word, got, tt.want)
+			}
+		})
+	}
+}
@@ -3,14 +3,44 @@ package domain
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/adapters/http/handlers"
+	"github.com/DeryabinSergey/germanarticlebot/internal/application/usecases"
 	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/container"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/jobs"
+	"github.com/DeryabinSergey/germanarticlebot/libs/requestid"
 	"gopkg.in/telebot.v3"
 	"io"
 	"net/http"
 	"strings"
+	"sync"
 )
 
+var (
+	containerMu  sync.Mutex
+	appContainer *container.Container
+)
+
+// getContainer returns the container shared by the invocations of a warm instance,
+// a failed initialization is retried on the next invocation
+func getContainer() (*container.Container, error) {
+	containerMu.Lock()
+	defer containerMu.Unlock()
+
+	if appContainer != nil {
+		return appContainer, nil
+	}
+
+	c, err := container.NewContainer(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	appContainer = c
+
+	return appContainer, nil
+}
+
 // Invoke is the main entry point for Google Cloud Functions
 func Invoke(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -20,38 +50,53 @@ func Invoke(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Correlate logs, spans and responses of this invocation
+	requestID := requestid.Resolve(r.Header.Get(requestid.Header))
+	ctx = requestid.NewContext(ctx, requestID)
+	w.Header().Set(requestid.Header, requestID)
+	ctx = usecases.WithRequestContext(ctx, handlers.NewRequestContext(r, requestID))
+
 	// Initialize container if not already done
-	appContainer, err := container.NewContainer(ctx)
+	appContainer, err := getContainer()
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Failed to initialize application: %v", err), http.StatusInternalServerError)
 		return
 	}
-	if appContainer == nil {
-		http.Error(w, fmt.Sprintf("Failed to initialize application: %v", err), http.StatusInternalServerError)
-		return
-	}
+	// The instance may be frozen right after the response, so buffered telemetry is sent now
 	defer func(ctx context.Context) {
-		_ = appContainer.Tracer.Close(ctx)
-		_ = appContainer.Logger.Close(ctx)
-	}(ctx)
+		_ = appContainer.Tracer.Flush(ctx)
+		_ = appContainer.Logger.Flush(ctx)
+	}(context.WithoutCancel(ctx))
 
 	spanCtx, span := appContainer.Tracer.Start(ctx, "Application Invoke")
 	defer span.End()
-	r = r.WithContext(spanCtx)
 
 	// Route based on path and content type
 	path := r.URL.Path
 	contentType := r.Header.Get("Content-Type")
 
+	// Slow requests are cut off, and the AI calls of abandoned ones are canceled with the request context
+	if timeout := appContainer.Config.HTTPRequestTimeout; timeout > 0 && !longRunning(path) {
+		var cancel context.CancelFunc
+		spanCtx, cancel = context.WithTimeout(spanCtx, timeout)
+		defer cancel()
+	}
+	r = r.WithContext(spanCtx)
+	if !ownBodyLimit(path) {
+		r.Body = http.MaxBytesReader(w, r.Body, appContainer.Config.HTTPMaxBodyBytes)
+	}
+
 	switch {
 	case path == "/" && r.Method == http.MethodPost && strings.Contains(contentType, "application/json"):
 		// Check if it's a Telegram webhook
 		body, err := io.ReadAll(r.Body)
 		if err != nil {
-			appContainer.Logger.Error(spanCtx, map[string]interface{}{
-				"message": "Failed to read request body",
-				"error":   err.Error(),
-			})
+			appContainer.Logger.With(spanCtx).Err(err).Error("Failed to read request body")
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+				return
+			}
 			http.Error(w, "Failed to read request body", http.StatusBadRequest)
 			return
 		}
@@ -59,8 +104,7 @@ func Invoke(w http.ResponseWriter, r *http.Request) {
 		// Try to parse as Telegram update
 		var telegramUpdate telebot.Update
 		if err := json.Unmarshal(body, &telegramUpdate); err == nil && telegramUpdate.ID > 0 && appContainer.TelegramBot != nil {
-			appContainer.TelegramBot.SetContext(spanCtx)
-			appContainer.TelegramBot.GetBot().ProcessUpdate(telegramUpdate)
+			appContainer.TelegramBot.ProcessUpdate(spanCtx, body, telegramUpdate)
 			w.WriteHeader(http.StatusOK)
 			return
 		}
@@ -68,19 +112,144 @@ func Invoke(w http.ResponseWriter, r *http.Request) {
 		// If not Telegram, treat as API request
 		// Reset body reader
 		r.Body = io.NopCloser(strings.NewReader(string(body)))
-		appContainer.HTTPHandler.HandleArticleRequest(w, r)
+		appContainer.CORS.Wrap(handlers.ArticleCORSPolicy, appContainer.Signing.Wrap(appContainer.Tenancy.Wrap(appContainer.Authentication.Wrap(appContainer.Idempotency.Wrap(appContainer.HTTPHandler.HandleArticleRequest)))))(w, r)
+
+	case path == "/" || path == "/article" || path == "/v1/article" || path == "/v2/article":
+		// Handle API requests, the handler negotiates the schema version
+		appContainer.CORS.Wrap(handlers.ArticleCORSPolicy, appContainer.Signing.Wrap(appContainer.Tenancy.Wrap(appContainer.Authentication.Wrap(appContainer.Idempotency.Wrap(appContainer.HTTPHandler.HandleArticleRequest)))))(w, r)
+
+	case strings.HasPrefix(path, "/v1/words/") || strings.HasPrefix(path, "/v2/words/"):
+		// Canonical cacheable lookups keyed on the URL
+		appContainer.CORS.Wrap(handlers.WordCORSPolicy, appContainer.Signing.Wrap(appContainer.Tenancy.Wrap(appContainer.HTTPHandler.HandleWordRequest)))(w, r)
+
+	case path == "/v1/article-only":
+		// Just the article and the gender of nouns, answered from the dictionary and the cache where possible
+		appContainer.CORS.Wrap(handlers.WordCORSPolicy, appContainer.Signing.Wrap(appContainer.Tenancy.Wrap(appContainer.HTTPHandler.HandleArticleOnlyRequest)))(w, r)
+
+	case path == "/v1/suggest":
+		// Known nouns with their articles completing partial words, without AI calls, so tenant quotas don't apply
+		appContainer.CORS.Wrap(handlers.WordCORSPolicy, appContainer.HTTPHandler.HandleSuggestRequest)(w, r)
+
+	case strings.HasPrefix(path, usecases.BotWebhookPath):
+		// Telegram updates of the main bot and of the white-label bots, routed by the bot ID of the path
+		if appContainer.TelegramBots == nil {
+			http.Error(w, "Not found", http.StatusNotFound)
+			return
+		}
+		appContainer.TelegramBots.HandleWebhook(w, r)
+
+	case path == "/embed":
+		// HTML cards of lookups for iframes of third-party pages
+		appContainer.EmbedHandler.HandleEmbedRequest(w, r)
 
-	case path == "/" || path == "/article":
-		// Handle API requests
-		appContainer.HTTPHandler.HandleArticleRequest(w, r)
+	case path == "/graphql":
+		// GraphQL queries selecting the fields of lookups, looked-up words and stats
+		appContainer.CORS.Wrap(handlers.GraphQLCORSPolicy, appContainer.Signing.Wrap(appContainer.Tenancy.Wrap(appContainer.GraphQL.HandleHTTP)))(w, r)
 
-	case path == "/health":
-		// Health check endpoint
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		_ = json.NewEncoder(w).Encode(map[string]string{"status": "healthy"})
+	case path == handlers.KeysPath:
+		// Public keys verifying the signed responses
+		appContainer.Signing.HandleKeys(w, r)
+
+	case path == "/article/async":
+		// Lookups answered by a signed callback
+		appContainer.CORS.Wrap(handlers.ArticleCORSPolicy, appContainer.Tenancy.Wrap(appContainer.Idempotency.Wrap(appContainer.AsyncHandler.HandleAsyncRequest)))(w, r)
+
+	case path == "/article/stream":
+		// Stream lookups as Server-Sent Events
+		appContainer.CORS.Wrap(handlers.StreamCORSPolicy, appContainer.Tenancy.Wrap(appContainer.SSEHandler.HandleArticleStream))(w, r)
+
+	case path == "/voice/alexa":
+		// Alexa skill requests
+		appContainer.VoiceHandler.HandleAlexaRequest(w, r)
+
+	case path == "/voice/google":
+		// Google Assistant action webhook
+		appContainer.VoiceHandler.HandleGoogleRequest(w, r)
+
+	case path == "/mcp":
+		// Model Context Protocol tool server over Streamable HTTP
+		appContainer.MCPServer.HandleHTTP(w, r)
+
+	case path == "/ws":
+		// Stream lookups to web clients over WebSocket
+		appContainer.WebSocket.HandleWebSocket(w, r)
+
+	case path == "/import":
+		// Handle vocabulary list imports
+		appContainer.Tenancy.Wrap(appContainer.Idempotency.Wrap(appContainer.ImportHandler.HandleImportRequest))(w, r)
+
+	case path == "/ask":
+		// Handle free-form grammar questions
+		appContainer.Tenancy.Wrap(appContainer.Authentication.Wrap(appContainer.AskHandler.HandleAskRequest))(w, r)
+
+	case path == "/auth/token":
+		// Exchange API tokens and ID tokens for short-lived session tokens
+		appContainer.CORS.Wrap(handlers.AuthCORSPolicy, appContainer.Authentication.Require(appContainer.AuthHandler.HandleTokenRequest))(w, r)
+
+	case path == "/auth/link":
+		// Exchange the link codes of the bot for API tokens, linking the ID token of the request
+		appContainer.CORS.Wrap(handlers.AuthCORSPolicy, appContainer.Authentication.Wrap(appContainer.AuthHandler.HandleLinkRequest))(w, r)
+
+	case path == "/me/stats":
+		// Show the learning statistics of the authenticated profile
+		appContainer.CORS.Wrap(handlers.MeCORSPolicy, appContainer.Authentication.Require(appContainer.MeHandler.HandleStatsRequest))(w, r)
+
+	case path == "/me/export":
+		// Export everything stored about the authenticated profile as a JSON file
+		appContainer.CORS.Wrap(handlers.MeCORSPolicy, appContainer.Authentication.Require(appContainer.MeHandler.HandleExportRequest))(w, r)
+
+	case path == "/me/search":
+		// Search the looked-up words of the authenticated profile
+		appContainer.CORS.Wrap(handlers.MeCORSPolicy, appContainer.Authentication.Require(appContainer.MeHandler.HandleSearchRequest))(w, r)
+
+	case path == "/me/delete":
+		// Erase everything stored about the authenticated profile
+		appContainer.CORS.Wrap(handlers.MeCORSPolicy, appContainer.Authentication.Require(appContainer.MeHandler.HandleDeleteRequest))(w, r)
+
+	case path == "/tenant/stats":
+		// Show the usage of the tenant of the API key
+		appContainer.CORS.Wrap(handlers.TenantCORSPolicy, appContainer.Tenancy.Require(appContainer.TenantHandler.HandleStatsRequest))(w, r)
+
+	case path == "/tenant/prompt":
+		// Customize the prompt fragments of the lookups of the tenant of the API key
+		appContainer.CORS.Wrap(handlers.TenantCORSPolicy, appContainer.Tenancy.Require(appContainer.TenantHandler.HandlePromptRequest))(w, r)
+
+	case path == "/tenant/prompt/preview":
+		// Render the prompt of a lookup with unsaved prompt overrides
+		appContainer.CORS.Wrap(handlers.TenantCORSPolicy, appContainer.Tenancy.Require(appContainer.TenantHandler.HandlePromptPreviewRequest))(w, r)
+
+	case strings.HasPrefix(path, "/jobs/"):
+		// Poll the state of background jobs
+		appContainer.JobHandler.HandleJobRequest(w, r)
+
+	case path == jobs.WorkerPath:
+		// Handle background jobs delivered by the queue
+		appContainer.WorkerHandler.HandleWorkerRequest(w, r)
+
+	case path == "/admin" || strings.HasPrefix(path, "/admin/"):
+		// Handle operator dashboard requests
+		appContainer.AdminHandler.HandleAdminRequest(w, r)
+
+	case path == "/healthz":
+		// Liveness probe
+		appContainer.HealthHandler.HandleLiveness(w, r)
+
+	case path == "/readyz" || path == "/health":
+		// Readiness probe with dependency checks
+		appContainer.HealthHandler.HandleReadiness(w, r)
 
 	default:
 		http.Error(w, "Not found", http.StatusNotFound)
 	}
 }
+
+// longRunning reports whether the route streams or processes background jobs, these have their own
+// idle timeouts and queue deadlines instead of the request timeout
+func longRunning(path string) bool {
+	return path == "/ws" || path == "/article/stream" || path == "/mcp" || path == jobs.WorkerPath
+}
+
+// ownBodyLimit reports whether the handler of the route limits the request body itself
+func ownBodyLimit(path string) bool {
+	return path == "/import" || path == "/ask" || path == "/mcp" || path == "/admin/dictionary/import"
+}
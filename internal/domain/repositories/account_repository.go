@@ -0,0 +1,28 @@
+package repositories
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"time"
+)
+
+// AccountRepository defines the storage of the one-time link codes, the API tokens of linked accounts
+// and the external identities linked to them
+type AccountRepository interface {
+	// SaveLinkCode stores the one-time code of the user for ttl
+	SaveLinkCode(ctx context.Context, code string, userID int64, ttl time.Duration) error
+	// TakeLinkCode returns the user of the code and deletes it, false for unknown and expired codes
+	TakeLinkCode(ctx context.Context, code string) (int64, bool, error)
+	// SaveToken binds the token hash to the user, replacing the previous token of the user
+	SaveToken(ctx context.Context, tokenHash string, userID int64) error
+	// FindToken returns the user of the token hash, false for unknown tokens
+	FindToken(ctx context.Context, tokenHash string) (int64, bool, error)
+	// SaveIdentity binds the external identity of the provider to the user
+	SaveIdentity(ctx context.Context, provider, subject string, userID int64) error
+	// FindIdentity returns the user of the external identity, false for identities not linked to the bot
+	FindIdentity(ctx context.Context, provider, subject string) (int64, bool, error)
+	// Identities returns the external identities linked to the user ordered by provider and subject
+	Identities(ctx context.Context, userID int64) ([]entities.Identity, error)
+	// DeleteUser removes the link code, the token and the external identities of the user
+	DeleteUser(ctx context.Context, userID int64) error
+}
@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+)
+
+// AchievementRepository defines the storage of the achievements awarded to users
+type AchievementRepository interface {
+	// Award stores the achievement of the user, false if the user already has it
+	Award(ctx context.Context, userID int64, achievement entities.Achievement) (bool, error)
+	// List returns the achievements of the user, oldest first
+	List(ctx context.Context, userID int64) ([]entities.Achievement, error)
+	// Delete removes the achievements of the user
+	Delete(ctx context.Context, userID int64) error
+}
@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"time"
+)
+
+// ActivityRepository defines the storage of the learning activity of users
+type ActivityRepository interface {
+	// RecordLookup counts the word for the day of at in its time zone, the one of the user, once per word and day,
+	// and keeps the article and the translation of its answer
+	RecordLookup(ctx context.Context, userID int64, word entities.LookedUpWord, at time.Time) error
+	// RecordQuizAnswer counts the answer for its day in the time zone of the answer
+	RecordQuizAnswer(ctx context.Context, userID int64, answer *entities.QuizAnswer) error
+	// SearchWords returns at most limit words looked up or met in the quiz by the user matching the query in
+	// the word or its translation, the best matches first
+	SearchWords(ctx context.Context, userID int64, query string, limit int) ([]entities.WordMatch, error)
+	// Activity returns the activity of the user, empty if none was recorded
+	Activity(ctx context.Context, userID int64) (*entities.UserActivity, error)
+	// Delete removes the activity of the user, unknown users are ignored
+	Delete(ctx context.Context, userID int64) error
+	// DeleteInactive removes the activity of the users last active before the time and returns their number
+	DeleteInactive(ctx context.Context, before time.Time) (int, error)
+}
@@ -0,0 +1,13 @@
+package repositories
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+)
+
+// AuditRepository defines the append-only storage of the admin audit log
+type AuditRepository interface {
+	Append(ctx context.Context, entry *entities.AuditEntry) error
+	// List returns at most limit entries of the action, of every action when it's empty, newest first
+	List(ctx context.Context, action entities.AuditAction, limit int) ([]*entities.AuditEntry, error)
+}
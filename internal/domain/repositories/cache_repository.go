@@ -0,0 +1,18 @@
+package repositories
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"time"
+)
+
+// CacheRepository defines the storage of generated article responses
+type CacheRepository interface {
+	Get(ctx context.Context, key string) (*entities.ArticleResponse, bool, error)
+	Set(ctx context.Context, key string, response *entities.ArticleResponse, ttl time.Duration) error
+	// Delete removes the entry of the key and of its variants, whose keys continue the key after a "|",
+	// and returns the number of removed entries
+	Delete(ctx context.Context, key string) (int, error)
+	// Clear removes every entry and returns their number
+	Clear(ctx context.Context) (int, error)
+}
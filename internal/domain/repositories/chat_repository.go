@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+)
+
+// ChatRepository defines the storage of the chats the bot has talked in
+type ChatRepository interface {
+	// Touch adds the chat or updates its language and the time it was seen, the opt-out is kept
+	Touch(ctx context.Context, chat *entities.KnownChat) error
+	// SetOptOut sets whether the chat gets announcements, false if the chat is unknown
+	SetOptOut(ctx context.Context, chatID int64, optedOut bool) (bool, error)
+	// Get returns the chat, false if it is unknown
+	Get(ctx context.Context, chatID int64) (*entities.KnownChat, bool, error)
+	// Delete forgets the chat, unknown chats are ignored
+	Delete(ctx context.Context, chatID int64) error
+	// List returns all known chats ordered by ID
+	List(ctx context.Context) ([]entities.KnownChat, error)
+}
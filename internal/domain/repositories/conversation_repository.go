@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"time"
+)
+
+// ConversationRepository defines the short-lived storage of the last word of every chat
+type ConversationRepository interface {
+	// Get returns the conversation of the chat, false if there is none or it has expired
+	Get(ctx context.Context, chatID int64) (*entities.Conversation, bool, error)
+	Save(ctx context.Context, conversation *entities.Conversation, ttl time.Duration) error
+	// Delete forgets the conversation of the chat
+	Delete(ctx context.Context, chatID int64) error
+}
@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"time"
+)
+
+// DeadLetterRepository defines the storage of the Telegram updates whose processing failed
+type DeadLetterRepository interface {
+	// Save stores a copy of the dead letter, replacing the previous one with the same ID
+	Save(ctx context.Context, letter *entities.DeadLetter) error
+	Get(ctx context.Context, id string) (*entities.DeadLetter, bool, error)
+	// List returns at most limit dead letters, oldest first
+	List(ctx context.Context, limit int) ([]*entities.DeadLetter, error)
+	Delete(ctx context.Context, id string) error
+	// ListUser returns the dead letters of the user, oldest first
+	ListUser(ctx context.Context, userID int64) ([]*entities.DeadLetter, error)
+	// DeleteUser removes the dead letters of the user
+	DeleteUser(ctx context.Context, userID int64) error
+	// DeleteBefore removes the dead letters that failed before the time and returns their number
+	DeleteBefore(ctx context.Context, before time.Time) (int, error)
+}
@@ -0,0 +1,15 @@
+package repositories
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+)
+
+// DictionaryRepository defines the storage of the curated dictionary entries imported by the operators,
+// one per noun
+type DictionaryRepository interface {
+	// Get returns the entry of the noun, nil for nouns without one. The lookup is case-insensitive.
+	Get(ctx context.Context, word string) (*entities.CuratedEntry, error)
+	// Save replaces the entries of their nouns
+	Save(ctx context.Context, entries []entities.CuratedEntry) error
+}
@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"time"
+)
+
+// FeedbackRepository defines the storage of user ratings of answers
+type FeedbackRepository interface {
+	Save(ctx context.Context, feedback *entities.Feedback) error
+	// List returns a page of the matching feedback, newest first unless the page orders it otherwise
+	List(ctx context.Context, filter entities.FeedbackFilter) (entities.Page[*entities.Feedback], error)
+	// ListUser returns the ratings of the user, oldest first
+	ListUser(ctx context.Context, userID int64) ([]*entities.Feedback, error)
+	// DeleteUser removes the ratings of the user
+	DeleteUser(ctx context.Context, userID int64) error
+	// DeleteBefore removes the ratings created before the time and returns their number
+	DeleteBefore(ctx context.Context, before time.Time) (int, error)
+}
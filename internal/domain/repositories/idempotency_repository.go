@@ -0,0 +1,18 @@
+package repositories
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"time"
+)
+
+// IdempotencyRepository defines the storage of the responses of requests with idempotency keys
+type IdempotencyRepository interface {
+	// Begin reserves the key for the request with the fingerprint and returns nil, or the record of
+	// an earlier request with the key
+	Begin(ctx context.Context, key, fingerprint string, ttl time.Duration) (*entities.IdempotentResponse, error)
+	// Complete stores the response of the request holding the key
+	Complete(ctx context.Context, key string, response *entities.IdempotentResponse, ttl time.Duration) error
+	// Release frees the key of a failed request, so a retry is processed again
+	Release(ctx context.Context, key string) error
+}
@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"time"
+)
+
+// JobRepository defines the storage of the states of background jobs
+type JobRepository interface {
+	// Save stores a copy of the status, replacing the previous one of the job
+	Save(ctx context.Context, status *entities.JobStatus, ttl time.Duration) error
+	Get(ctx context.Context, id string) (*entities.JobStatus, bool, error)
+	// DeleteUser removes the states of the jobs working for the user
+	DeleteUser(ctx context.Context, userID int64) error
+}
@@ -0,0 +1,24 @@
+package repositories
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+)
+
+// LeaderboardRepository defines the storage of the leaderboard members and their weekly quiz scores
+type LeaderboardRepository interface {
+	// SaveMember adds the member or updates the display name of the member
+	SaveMember(ctx context.Context, member *entities.LeaderboardMember) error
+	// DeleteMember removes the member together with all scores of the member
+	DeleteMember(ctx context.Context, userID int64) error
+	// Member returns the member of the user, nil for users who didn't opt in
+	Member(ctx context.Context, userID int64) (*entities.LeaderboardMember, error)
+	// RecordScore counts the answer of the member in the week of the chat
+	RecordScore(ctx context.Context, week string, chatID int64, userID int64, correct bool) error
+	// Scores returns the scores of the week in the chat in no particular order
+	Scores(ctx context.Context, week string, chatID int64) ([]entities.LeaderboardScore, error)
+	// DeleteChat removes the scores of the group chat in all weeks
+	DeleteChat(ctx context.Context, chatID int64) error
+	// Chats returns the group chats with scores in the week, without LeaderboardGlobal
+	Chats(ctx context.Context, week string) ([]int64, error)
+}
@@ -0,0 +1,15 @@
+package repositories
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+)
+
+// PreferencesRepository defines the storage of user settings
+type PreferencesRepository interface {
+	// Get returns the preferences of the user, default preferences if none were saved
+	Get(ctx context.Context, userID int64) (*entities.UserPreferences, error)
+	Save(ctx context.Context, preferences *entities.UserPreferences) error
+	// Delete removes the saved preferences of the user, the user gets default preferences again
+	Delete(ctx context.Context, userID int64) error
+}
@@ -0,0 +1,15 @@
+package repositories
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+)
+
+// PromptRepository defines the storage of the prompt overrides of the tenants
+type PromptRepository interface {
+	// Get returns the prompt overrides of the tenant, nil if none were saved
+	Get(ctx context.Context, tenantID string) (*entities.PromptOverrides, error)
+	Save(ctx context.Context, overrides *entities.PromptOverrides) error
+	// Delete removes the overrides of the tenant, its lookups use the configured example topics again
+	Delete(ctx context.Context, tenantID string) error
+}
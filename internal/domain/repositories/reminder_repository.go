@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"time"
+)
+
+// ReminderRepository defines the storage of the daily practice reminders, one per user
+type ReminderRepository interface {
+	// Get returns the reminder of the user, nil if none is set
+	Get(ctx context.Context, userID int64) (*entities.Reminder, error)
+	// Save replaces the reminder of the user
+	Save(ctx context.Context, reminder *entities.Reminder) error
+	// Delete removes the reminder of the user, unknown users are ignored
+	Delete(ctx context.Context, userID int64) error
+	// Due returns at most limit reminders due at the time, the longest due first
+	Due(ctx context.Context, now time.Time, limit int) ([]entities.Reminder, error)
+}
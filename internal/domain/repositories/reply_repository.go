@@ -0,0 +1,14 @@
+package repositories
+
+import (
+	"context"
+)
+
+// ReplyRepository maps the messages of the users to the answers of the bot, so an edited message updates
+// its answer instead of getting a new one
+type ReplyRepository interface {
+	// Get returns the message ID of the answer to the message of the chat, false if the message wasn't
+	// answered or its answer was forgotten
+	Get(ctx context.Context, chatID int64, messageID int) (int, bool, error)
+	Save(ctx context.Context, chatID int64, messageID, replyID int) error
+}
@@ -0,0 +1,24 @@
+package repositories
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+)
+
+// StatsRepository defines the storage for usage metrics shown on the admin dashboard
+type StatsRepository interface {
+	// RecordLookup counts the lookup of the word through the adapter, empty for background lookups
+	RecordLookup(ctx context.Context, word, language string, adapter entities.Adapter)
+	RecordCacheLookup(ctx context.Context, hit bool)
+	RecordAICall(ctx context.Context, failed bool)
+	// MonthlySpend returns the estimated AI spend of the current month in USD
+	MonthlySpend(ctx context.Context) (float64, error)
+	RecordTelegramUser(ctx context.Context, userID int64)
+	// DeleteUser forgets when the Telegram user was last seen, the user is no longer counted as active
+	DeleteUser(ctx context.Context, userID int64) error
+	// RecordChatMemberEvent counts the change of the membership of the bot in a chat
+	RecordChatMemberEvent(ctx context.Context, event entities.ChatMemberEvent)
+	Snapshot(ctx context.Context, topWords int) (*entities.DashboardStats, error)
+	// ListWords returns a page of the looked-up words, most looked-up first unless the page orders them otherwise
+	ListWords(ctx context.Context, filter entities.WordStatFilter) (entities.Page[entities.WordStat], error)
+}
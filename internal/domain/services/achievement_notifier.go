@@ -0,0 +1,11 @@
+package services
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+)
+
+// AchievementNotifier defines the interface for announcing newly awarded achievements to the user
+type AchievementNotifier interface {
+	NotifyAchievements(ctx context.Context, userID int64, achievements []entities.Achievement) error
+}
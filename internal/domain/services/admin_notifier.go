@@ -0,0 +1,8 @@
+package services
+
+import "context"
+
+// AdminNotifier defines the interface for alerting the operators of the bot
+type AdminNotifier interface {
+	NotifyAdmin(ctx context.Context, message string) error
+}
@@ -2,10 +2,21 @@ package services
 
 import (
 	"context"
+	"errors"
 	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
 )
 
+// Errors of AI answers that were generated but can't be used, the AI itself is working
+var (
+	// ErrAnswerBlocked is returned when the safety filters blocked the prompt or the answer
+	ErrAnswerBlocked = errors.New("the AI answer was blocked by the safety filters")
+	// ErrAnswerTruncated is returned when the answer hit the output token limit and couldn't be recovered
+	ErrAnswerTruncated = errors.New("the AI answer was cut off at the token limit")
+	// ErrAnswerRecitation is returned when the answer was stopped for reciting its training data
+	ErrAnswerRecitation = errors.New("the AI answer was stopped for recitation")
+)
+
 // AIService defines the interface for AI-powered article determination
 type AIService interface {
 	GenerateArticleInfo(ctx context.Context, request *entities.ArticleRequest) (*entities.ArticleResponse, error)
-}
\ No newline at end of file
+}
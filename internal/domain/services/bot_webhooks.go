@@ -0,0 +1,16 @@
+package services
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+)
+
+// BotWebhooks defines the interface for managing the webhooks of the Telegram bots of the deployment
+type BotWebhooks interface {
+	// BotIDs returns the IDs of the bots, the main bot first
+	BotIDs() []int64
+	// Webhook returns the webhook state of the bot as reported by Telegram
+	Webhook(ctx context.Context, botID int64) (*entities.BotWebhook, error)
+	// SetWebhook points the webhook of the bot to the URL, the updates are sent with the secret of the bot
+	SetWebhook(ctx context.Context, botID int64, url string) error
+}
@@ -0,0 +1,13 @@
+package services
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+)
+
+// ContentModerator defines the interface for screening the generated texts shown to the learners
+type ContentModerator interface {
+	// Screen returns the category of the objectionable content of the text, empty for acceptable texts.
+	// Words sharing the stem of an allowed word are not screened, like the looked-up noun in its examples.
+	Screen(ctx context.Context, text string, allowed []string) (entities.ContentCategory, error)
+}
@@ -0,0 +1,21 @@
+package services
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+)
+
+// DictionaryService defines the interface for looking up articles in a reference dictionary
+type DictionaryService interface {
+	// LookupArticle returns the article of the noun, found is false for unknown words
+	LookupArticle(ctx context.Context, word string) (article string, found bool, err error)
+	// Entries returns all nouns of the dictionary
+	Entries(ctx context.Context) ([]entities.DictionaryEntry, error)
+	// Variants returns the meanings of every article of a noun whose meaning depends on the gender,
+	// nil for nouns with a single gender
+	Variants(ctx context.Context, word string) ([]entities.GenderVariant, error)
+	// Suggest returns at most limit nouns closest to the misspelled word, the closest first
+	Suggest(ctx context.Context, word string, limit int) ([]string, error)
+	// Complete returns at most limit nouns starting with the prefix, in alphabetical order
+	Complete(ctx context.Context, prefix string, limit int) ([]entities.DictionaryEntry, error)
+}
@@ -0,0 +1,12 @@
+package services
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+)
+
+// FeatureFlags decides which chats get the features being rolled out
+type FeatureFlags interface {
+	// Enabled reports whether the feature is on for the chat, unknown features are off
+	Enabled(ctx context.Context, feature entities.Feature, chatID int64) bool
+}
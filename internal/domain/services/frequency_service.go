@@ -0,0 +1,11 @@
+package services
+
+import "context"
+
+// FrequencyService defines the interface for looking up the corpus frequency of nouns
+type FrequencyService interface {
+	// Rank returns the frequency rank of the noun, found is false for nouns outside the list
+	Rank(ctx context.Context, noun string) (rank int, found bool, err error)
+	// Top returns at most limit nouns of the list, the most frequent first
+	Top(ctx context.Context, limit int) ([]string, error)
+}
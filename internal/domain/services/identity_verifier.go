@@ -0,0 +1,16 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+)
+
+// ErrInvalidIDToken is returned for ID and session tokens with a bad signature, audience or expiry
+var ErrInvalidIDToken = errors.New("invalid ID token")
+
+// IdentityVerifier verifies the ID tokens of an external identity provider
+type IdentityVerifier interface {
+	// Verify returns the identity of the token, nil if the token was issued by another provider
+	Verify(ctx context.Context, token string) (*entities.Identity, error)
+}
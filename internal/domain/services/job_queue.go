@@ -0,0 +1,11 @@
+package services
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+)
+
+// JobQueue defines the interface for enqueuing background jobs
+type JobQueue interface {
+	Enqueue(ctx context.Context, job *entities.Job) error
+}
@@ -0,0 +1,11 @@
+package services
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+)
+
+// PromptRenderer defines the interface for rendering the prompt of a lookup without generating its answer
+type PromptRenderer interface {
+	RenderPrompt(ctx context.Context, request *entities.ArticleRequest) (string, error)
+}
@@ -0,0 +1,14 @@
+package services
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+)
+
+// ResponseSigner signs response bodies, so consumers embedding the answers can verify where they come from
+type ResponseSigner interface {
+	// Sign returns the signature of the body prefixed with its algorithm and the ID of the signing key
+	Sign(ctx context.Context, body []byte) (signature, keyID string, err error)
+	// PublicKeys returns the keys verifying the signatures, none for shared secrets
+	PublicKeys(ctx context.Context) ([]entities.VerificationKey, error)
+}
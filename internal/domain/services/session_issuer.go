@@ -0,0 +1,12 @@
+package services
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+)
+
+// SessionIssuer issues the session tokens of the backend, they are verified as an IdentityVerifier
+type SessionIssuer interface {
+	// Issue returns a session token of the identity
+	Issue(ctx context.Context, identity *entities.Identity) (*entities.SessionToken, error)
+}
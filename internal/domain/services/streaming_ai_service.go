@@ -0,0 +1,13 @@
+package services
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+)
+
+// StreamingAIService is implemented by AI services able to emit parts of the answer as they are generated
+type StreamingAIService interface {
+	AIService
+	// StreamArticleInfo emits article and translation events while generating and returns the complete answer
+	StreamArticleInfo(ctx context.Context, request *entities.ArticleRequest, emit func(entities.StreamEvent) error) (*entities.ArticleResponse, error)
+}
@@ -0,0 +1,12 @@
+package services
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+)
+
+// TranslationService finds the German nouns translating a word of another language
+type TranslationService interface {
+	// FindGermanNouns returns the German nouns without article, most common first, none if the word can't be translated to a noun
+	FindGermanNouns(ctx context.Context, request *entities.TranslationRequest) ([]string, error)
+}
@@ -0,0 +1,11 @@
+package services
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+)
+
+// TutorService answers free-form German grammar questions
+type TutorService interface {
+	AnswerGrammarQuestion(ctx context.Context, question *entities.GrammarQuestion) (*entities.GrammarAnswer, error)
+}
@@ -0,0 +1,9 @@
+package services
+
+import "context"
+
+// UpdateReplayer defines the interface for processing a dead-lettered Telegram update again
+type UpdateReplayer interface {
+	// ReplayUpdate processes the raw update and returns the error of its processing
+	ReplayUpdate(ctx context.Context, update []byte) error
+}
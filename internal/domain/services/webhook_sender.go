@@ -0,0 +1,17 @@
+package services
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrWebhookRejected is returned when the receiver answered a delivery with a client error, so retrying is useless
+var ErrWebhookRejected = errors.New("webhook rejected")
+
+// WebhookSender delivers signed JSON payloads to the callback URLs of integrators
+type WebhookSender interface {
+	// Validate checks that payloads may be delivered to the URL
+	Validate(url string) error
+	// Send posts the payload to the URL
+	Send(ctx context.Context, url string, payload []byte) error
+}
@@ -0,0 +1,94 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/services"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/genai"
+	"strings"
+)
+
+// answerFailure returns the error of the finish reason of a candidate without a usable answer, nil for
+// the reasons that don't explain it, like a regular stop
+func answerFailure(reason genai.FinishReason) error {
+	switch reason {
+	case genai.FinishReasonSafety, genai.FinishReasonBlocklist, genai.FinishReasonProhibitedContent, genai.FinishReasonSPII:
+		return services.ErrAnswerBlocked
+	case genai.FinishReasonMaxTokens:
+		return services.ErrAnswerTruncated
+	case genai.FinishReasonRecitation:
+		return services.ErrAnswerRecitation
+	default:
+		return nil
+	}
+}
+
+// promptBlocked returns the error of a prompt blocked before any candidate was generated, nil for
+// prompts that were answered
+func promptBlocked(resp *genai.GenerateContentResponse) error {
+	if resp.PromptFeedback == nil || resp.PromptFeedback.BlockReason == "" {
+		return nil
+	}
+
+	return fmt.Errorf("%w: prompt blocked for %s", services.ErrAnswerBlocked, resp.PromptFeedback.BlockReason)
+}
+
+// diagnose logs why Gemini gave no usable answer and returns the error of its failure class, nil when the
+// prompt feedback and the finish reasons don't explain it, like for malformed JSON of a completed answer
+func (s *GeminiService) diagnose(ctx context.Context, span trace.Span, model string, resp *genai.GenerateContentResponse) error {
+	failure := promptBlocked(resp)
+	for _, candidate := range resp.Candidates {
+		if failure == nil {
+			failure = answerFailure(candidate.FinishReason)
+		}
+	}
+	s.logDiagnostics(ctx, span, model, resp, failure)
+	if failure != nil {
+		span.RecordError(failure)
+	}
+
+	return failure
+}
+
+// logDiagnostics logs the prompt feedback, the finish reasons and the safety ratings of the candidates,
+// the finish reasons are recorded on the span as well
+func (s *GeminiService) logDiagnostics(ctx context.Context, span trace.Span, model string, resp *genai.GenerateContentResponse, failure error) {
+	entry := s.logger.With(ctx).Err(failure).Field("model", model)
+	if feedback := resp.PromptFeedback; feedback != nil && feedback.BlockReason != "" {
+		entry.Field("blockReason", string(feedback.BlockReason)).
+			Field("blockReasonMessage", feedback.BlockReasonMessage).
+			Field("promptSafetyRatings", safetyRatings(feedback.SafetyRatings))
+		span.SetAttributes(attribute.String("ai.prompt.block_reason", string(feedback.BlockReason)))
+	}
+
+	reasons := make([]string, 0, len(resp.Candidates))
+	candidates := make([]map[string]interface{}, 0, len(resp.Candidates))
+	for _, candidate := range resp.Candidates {
+		reasons = append(reasons, string(candidate.FinishReason))
+		candidates = append(candidates, map[string]interface{}{
+			"finishReason":  string(candidate.FinishReason),
+			"finishMessage": candidate.FinishMessage,
+			"safetyRatings": safetyRatings(candidate.SafetyRatings),
+		})
+	}
+	span.SetAttributes(attribute.StringSlice("ai.finish_reasons", reasons))
+
+	entry.Field("candidates", candidates).Warning("Gemini gave no usable answer")
+}
+
+// safetyRatings describes the ratings like "HARM_CATEGORY_HARASSMENT=MEDIUM (blocked)", the blocked
+// categories are marked
+func safetyRatings(ratings []*genai.SafetyRating) string {
+	described := make([]string, 0, len(ratings))
+	for _, rating := range ratings {
+		description := fmt.Sprintf("%s=%s", rating.Category, rating.Probability)
+		if rating.Blocked {
+			description += " (blocked)"
+		}
+		described = append(described, description)
+	}
+
+	return strings.Join(described, ", ")
+}
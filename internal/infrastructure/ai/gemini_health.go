@@ -0,0 +1,32 @@
+package ai
+
+import (
+	"context"
+	"google.golang.org/genai"
+)
+
+// GeminiHealthChecker probes Gemini reachability with a cheap model list call
+type GeminiHealthChecker struct {
+	client *genai.Client
+}
+
+// NewGeminiHealthChecker creates a new Gemini health checker
+func NewGeminiHealthChecker(client *genai.Client) *GeminiHealthChecker {
+	return &GeminiHealthChecker{client: client}
+}
+
+// Name returns the dependency name
+func (c *GeminiHealthChecker) Name() string {
+	return "gemini"
+}
+
+// Critical reports that no answer can be generated without Gemini
+func (c *GeminiHealthChecker) Critical() bool {
+	return true
+}
+
+// Check lists a single model
+func (c *GeminiHealthChecker) Check(ctx context.Context) error {
+	_, err := c.client.Models.List(ctx, &genai.ListModelsConfig{PageSize: 1})
+	return err
+}
@@ -0,0 +1,105 @@
+package ai
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// extractJSON returns the first complete JSON object of the model output without trailing commas. The
+// braces are balanced outside of strings, so a Markdown code fence, text around the object or a second
+// object are left out, and braces in prose before the answer are skipped as they don't form valid JSON.
+// The search goes on after the end of an invalid object, never inside it, so a malformed answer isn't
+// replaced by one of its nested objects. Output without a valid object returns its first complete object,
+// or the cut-off rest of a truncated one, for the logs and the repair.
+func extractJSON(text string) string {
+	var fallback string
+	for start := strings.IndexByte(text, '{'); start >= 0; {
+		end := objectEnd(text[start:])
+		if end < 0 {
+			if fallback == "" {
+				fallback = strings.TrimSpace(text[start:])
+			}
+			break
+		}
+
+		candidate := withoutTrailingCommas(text[start : start+end])
+		if json.Valid([]byte(candidate)) {
+			return candidate
+		}
+		if fallback == "" {
+			fallback = candidate
+		}
+
+		next := strings.IndexByte(text[start+end:], '{')
+		if next < 0 {
+			break
+		}
+		start += end + next
+	}
+
+	return fallback
+}
+
+// objectEnd returns the length of the object the text starts with up to its closing brace, -1 when the
+// text ends before the object is closed
+func objectEnd(text string) int {
+	var depth int
+	var inString, escaped bool
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case escaped:
+			escaped = false
+		case inString && c == '\\':
+			escaped = true
+		case c == '"':
+			inString = !inString
+		case inString:
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+
+	return -1
+}
+
+// withoutTrailingCommas removes the commas the models leave before closing brackets, commas in strings
+// like ", }" of an example sentence are kept
+func withoutTrailingCommas(text string) string {
+	// result stays nil, and the text is returned as it is, until a comma is dropped
+	var result []byte
+	var inString, escaped bool
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case escaped:
+			escaped = false
+		case inString && c == '\\':
+			escaped = true
+		case c == '"':
+			inString = !inString
+		case inString:
+		case c == ',':
+			next := strings.TrimLeft(text[i+1:], " \t\r\n")
+			if next != "" && (next[0] == '}' || next[0] == ']') {
+				if result == nil {
+					result = append(make([]byte, 0, len(text)), text[:i]...)
+				}
+				continue
+			}
+		}
+		if result != nil {
+			result = append(result, c)
+		}
+	}
+	if result == nil {
+		return text
+	}
+
+	return string(result)
+}
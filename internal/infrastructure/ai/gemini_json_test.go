@@ -0,0 +1,58 @@
+package ai
+
+import (
+	cloudlogging "cloud.google.com/go/logging"
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/libs/logger"
+	"io"
+	"testing"
+)
+
+func TestExtractJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "plain object", text: `{"error":false}`, want: `{"error":false}`},
+		{name: "code fence", text: "```json\n{\"error\":false}\n```", want: `{"error":false}`},
+		{name: "text around", text: `Here it is: {"error":false} Hope it helps!`, want: `{"error":false}`},
+		{name: "second object", text: `{"error":false} {"error":true}`, want: `{"error":false}`},
+		{name: "braces in prose", text: `Use {word} as placeholder: {"error":false}`, want: `{"error":false}`},
+		{name: "braces in strings", text: `{"error":false,"data":[{"mnemonic":"} is not { a brace"}]}`, want: `{"error":false,"data":[{"mnemonic":"} is not { a brace"}]}`},
+		{name: "trailing commas", text: `{"error":false,"data":[{"article":"der",},],}`, want: `{"error":false,"data":[{"article":"der"}]}`},
+		{name: "trailing comma before whitespace", text: "{\"error\":false,\n}", want: "{\"error\":false\n}"},
+		{name: "comma in string", text: `{"example":"Ja, }","list":"a, ]"}`, want: `{"example":"Ja, }","list":"a, ]"}`},
+		{name: "escaped quote", text: `{"example":"Er sagt \", }\""}`, want: `{"example":"Er sagt \", }\""}`},
+		{
+			name: "malformed answer with a valid nested object",
+			text: `{"error": false "data": [{"article":"der","wordWithArticle":"der Tisch"}]}`,
+			want: `{"error": false "data": [{"article":"der","wordWithArticle":"der Tisch"}]}`,
+		},
+		{
+			name: "malformed answer before a valid one",
+			text: `{"error": false "data": [{"article":"der"}]} {"error":false}`,
+			want: `{"error":false}`,
+		},
+		{name: "truncated", text: "```json\n{\"error\":false,\"data\":[{\"article\":\"d", want: `{"error":false,"data":[{"article":"d`},
+		{name: "no object", text: "Sorry, I can't help with that.", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extractJSON(tt.text); got != tt.want {
+				t.Errorf("extractJSON(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParseTextMalformedAnswer checks that a malformed answer isn't taken for an empty success, so it
+// reaches the salvage and the repair
+func TestParseTextMalformedAnswer(t *testing.T) {
+	s := NewGeminiService(nil, nil, logger.NewSlog(io.Discard, cloudlogging.Error, false), nil)
+	response, ok := s.parseText(context.Background(), `{"error": false "data": [{"article":"der","wordWithArticle":"der Tisch"}]}`)
+	if ok {
+		t.Errorf("parseText accepted the malformed answer as %+v", response)
+	}
+}
@@ -0,0 +1,177 @@
+package ai
+
+import (
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/libs/bufpool"
+	"html/template"
+	"strings"
+)
+
+const (
+	// promptHints asks for the plural and the hints of the full answers
+	promptHints = `      "plural": "plural form with article in German (e.g. \"die Häuser\"), empty if the noun has no plural",
+      "mnemonic": "one short sentence in {{.Language}} with a rule or memory hook for the gender of this noun, e.g. that nouns ending in -ung are always feminine",
+      "etymology": "one short sentence in {{.Language}} about the origin of the word",
+`
+	// promptFields asks for the fields of every answer
+	promptFields = `      "wordType": "noun, compound noun, nominalized verb or nominalized adjective",
+      "confidence": a number (not a string) between 0 and 1 telling how sure you are about the article,
+`
+	// promptStandardExamples asks for the nominative and accusative examples in the singular
+	promptStandardExamples = `	  "example": {
+		"singular": {
+			"definite": {
+				"nominativeExample": "simple example using the word \"{{.Word}}\" in singular nominative definite case",
+				"nominativeTranslation": "translation of the singular nominative definite example in {{.Language}}",
+				"accusativeExample": "simple example using the word \"{{.Word}}\" in singular accusative definite case",
+				"accusativeTranslation": "translation of the singular accusative definite example in {{.Language}}"
+			},
+			"indefinite": {
+				"nominativeExample": "simple example using the word \"{{.Word}}\" in singular nominative indefinite case",
+				"nominativeTranslation": "translation of the singular nominative indefinite example in {{.Language}}",
+				"accusativeExample": "simple example using the word \"{{.Word}}\" in singular accusative indefinite case",
+				"accusativeTranslation": "translation of the singular accusative indefinite example in {{.Language}}"
+			}
+		}
+	  }
+`
+	// promptFullExamples asks for the declension and the examples of all cases
+	promptFullExamples = `	  "declension": {
+		"singular": {"nominative": "definite article + singular nominative form", "accusative": "...", "dative": "...", "genitive": "..."},
+		"plural": {"nominative": "definite article + plural nominative form, all four cases empty if the noun has no plural", "accusative": "...", "dative": "...", "genitive": "..."}
+	  },
+	  "example": {
+		"singular": {
+			"definite": {
+				"nominativeExample": "simple example using the word \"{{.Word}}\" in singular nominative definite case",
+				"nominativeTranslation": "translation of the singular nominative definite example in {{.Language}}",
+				"accusativeExample": "simple example using the word \"{{.Word}}\" in singular accusative definite case",
+				"accusativeTranslation": "translation of the singular accusative definite example in {{.Language}}",
+				"dativeExample": "simple example using the word \"{{.Word}}\" in singular dative definite case",
+				"dativeTranslation": "translation of the singular dative definite example in {{.Language}}",
+				"genitiveExample": "simple example using the word \"{{.Word}}\" in singular genitive definite case",
+				"genitiveTranslation": "translation of the singular genitive definite example in {{.Language}}"
+			},
+			"indefinite": {
+				"nominativeExample": "simple example using the word \"{{.Word}}\" in singular nominative indefinite case",
+				"nominativeTranslation": "translation of the singular nominative indefinite example in {{.Language}}",
+				"accusativeExample": "simple example using the word \"{{.Word}}\" in singular accusative indefinite case",
+				"accusativeTranslation": "translation of the singular accusative indefinite example in {{.Language}}",
+				"dativeExample": "simple example using the word \"{{.Word}}\" in singular dative indefinite case",
+				"dativeTranslation": "translation of the singular dative indefinite example in {{.Language}}",
+				"genitiveExample": "simple example using the word \"{{.Word}}\" in singular genitive indefinite case",
+				"genitiveTranslation": "translation of the singular genitive indefinite example in {{.Language}}"
+			},
+		},
+		"plural": {
+			"definite": {
+				"nominativeExample": "simple example using the word \"{{.Word}}\" in plural nominative definite case",
+				"nominativeTranslation": "translation of the plural nominative definite example in {{.Language}}",
+				"accusativeExample": "simple example using the word \"{{.Word}}\" in plural accusative definite case",
+				"accusativeTranslation": "translation of the plural accusative definite example in {{.Language}}",
+				"dativeExample": "simple example using the word \"{{.Word}}\" in plural dative definite case",
+				"dativeTranslation": "translation of the plural dative definite example in {{.Language}}",
+				"genitiveExample": "simple example using the word \"{{.Word}}\" in plural genitive definite case",
+				"genitiveTranslation": "translation of the plural genitive definite example in {{.Language}}"
+			},
+			"indefinite": {
+				"nominativeExample": "simple example using the word \"{{.Word}}\" in plural nominative indefinite case",
+				"nominativeTranslation": "translation of the plural nominative indefinite example in {{.Language}}",
+				"accusativeExample": "simple example using the word \"{{.Word}}\" in plural accusative indefinite case",
+				"accusativeTranslation": "translation of the plural accusative indefinite example in {{.Language}}",
+				"dativeExample": "simple example using the word \"{{.Word}}\" in plural dative indefinite case",
+				"dativeTranslation": "translation of the plural dative indefinite example in {{.Language}}",
+				"genitiveExample": "simple example using the word \"{{.Word}}\" in plural genitive indefinite case",
+				"genitiveTranslation": "translation of the plural genitive indefinite example in {{.Language}}"
+			},
+		},
+	  }
+`
+)
+
+// promptKey identifies a version of the article prompt, the kind of the request decides about the example
+// sentences and its verbosity about the parts of the answer
+type promptKey struct {
+	kind      entities.RequestKind
+	verbosity entities.Verbosity
+}
+
+// promptKeyOf returns the key of the prompt version of the request, the empty verbosity is the full one
+func promptKeyOf(request *entities.ArticleRequest) promptKey {
+	verbosity := request.Verbosity
+	if !verbosity.Reduced() {
+		verbosity = entities.VerbosityFull
+	}
+
+	return promptKey{kind: request.Kind(), verbosity: verbosity}
+}
+
+// promptVersions are the fields of the answer every version of the article prompt asks for, the instructions
+// around them are shared
+var promptVersions = map[promptKey]string{
+	{entities.RequestKindArticle, entities.VerbosityMinimal}:  promptFields,
+	{entities.RequestKindArticle, entities.VerbosityStandard}: promptFields,
+	{entities.RequestKindArticle, entities.VerbosityFull}:     promptHints + promptFields,
+	{entities.RequestKindFull, entities.VerbosityStandard}:    promptFields + promptStandardExamples,
+	{entities.RequestKindFull, entities.VerbosityFull}:        promptHints + promptFields + promptFullExamples,
+}
+
+// prompts are the parsed versions of the article prompt
+type prompts map[promptKey]*template.Template
+
+// parsePrompts parses the article prompt with the fields of every version
+func parsePrompts(source string, versions map[promptKey]string) (prompts, error) {
+	parsed := make(prompts, len(versions))
+	for key, fields := range versions {
+		tmpl, err := template.New("prompt").Parse(source)
+		if err == nil {
+			_, err = tmpl.New("fields").Parse(fields)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s %s prompt template: %w", key.kind, key.verbosity, err)
+		}
+		parsed[key] = tmpl
+	}
+
+	return parsed, nil
+}
+
+// mustParsePrompts parses the built-in prompt versions, they are constants, so a failure is a bug
+func mustParsePrompts() prompts {
+	parsed, err := parsePrompts(prompt, promptVersions)
+	if err != nil {
+		panic(err)
+	}
+
+	return parsed
+}
+
+// render renders the prompt version of the request, the soft prompt follows an answer blocked by the safety filters
+func (p prompts) render(request *entities.ArticleRequest, soft bool) (string, error) {
+	key := promptKeyOf(request)
+	tmpl, ok := p[key]
+	if !ok {
+		return "", fmt.Errorf("no %s %s prompt template", key.kind, key.verbosity)
+	}
+
+	buf := bufpool.Get()
+	defer bufpool.Put(buf)
+	if err := tmpl.Execute(buf, map[string]interface{}{
+		"Word":        request.Word,
+		"Language":    request.Language,
+		"Level":       string(request.Level),
+		"LevelGuide":  levelGuides[request.Level],
+		"ArticleOnly": request.Kind() == entities.RequestKindArticle,
+		// The examples stage continues the interpretations of the core answer
+		"Interpretations": strings.Join(request.Interpretations, "; "),
+		"ExampleTopics":   strings.Join(request.ExampleTopics, ", "),
+		"Tone":            request.Tone,
+		"AudienceAge":     request.AudienceAge,
+		"Soft":            soft,
+	}); err != nil {
+		return "", fmt.Errorf("failed to execute prompt template: %w", err)
+	}
+
+	return buf.String(), nil
+}
@@ -0,0 +1,70 @@
+package ai
+
+import (
+	"context"
+	"flag"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files instead of comparing them")
+
+// promptRequests maps the golden file name to the request whose article prompt it holds, one per prompt
+// version and one with every customization of the example sentences
+var promptRequests = map[string]*entities.ArticleRequest{
+	"full":                  {Word: "Haus", Language: "en"},
+	"standard":              {Word: "Haus", Language: "en", Verbosity: entities.VerbosityStandard},
+	"minimal":               {Word: "Haus", Language: "en", Verbosity: entities.VerbosityMinimal},
+	"article-only":          {Word: "Haus", Language: "en", ArticleOnly: true},
+	"standard-article-only": {Word: "Haus", Language: "en", Verbosity: entities.VerbosityStandard, ArticleOnly: true},
+	"customized": {
+		Word: "Bank", Language: "ru", Level: entities.LevelB1, Interpretations: []string{"die Bank", "die Bank"},
+		ExampleTopics: []string{"finance", "parks & gardens"}, Tone: "kids' playful", AudienceAge: 10,
+	},
+}
+
+// TestPromptGolden renders the article prompt of every version and compares it with its golden file in
+// testdata, go test ./internal/infrastructure/ai -update accepts intended prompt changes
+func TestPromptGolden(t *testing.T) {
+	prompts := NewPromptRenderer()
+	for name, request := range promptRequests {
+		t.Run(name, func(t *testing.T) {
+			prompt, err := prompts.RenderPrompt(context.Background(), request)
+			if err != nil {
+				t.Fatalf("Failed to render prompt: %v", err)
+			}
+
+			golden := filepath.Join("testdata", "prompt."+name+".golden")
+			actual := prompt + "\n"
+			if *update {
+				if err := os.WriteFile(golden, []byte(actual), 0o644); err != nil {
+					t.Fatalf("Failed to write golden file %s: %v", golden, err)
+				}
+				return
+			}
+
+			expected, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("Failed to read golden file %s: %v", golden, err)
+			}
+			if string(expected) != actual {
+				t.Errorf("%s differs, run with -update to accept the changes", golden)
+			}
+		})
+	}
+}
+
+// TestPromptVersions checks that every request kind and verbosity has a prompt version
+func TestPromptVersions(t *testing.T) {
+	prompts := mustParsePrompts()
+	for _, articleOnly := range []bool{false, true} {
+		for _, verbosity := range append([]entities.Verbosity{""}, entities.Verbosities...) {
+			request := &entities.ArticleRequest{Word: "Haus", Language: "en", ArticleOnly: articleOnly, Verbosity: verbosity}
+			if _, ok := prompts[promptKeyOf(request)]; !ok {
+				t.Errorf("No prompt version for article only %t and verbosity %q", articleOnly, verbosity)
+			}
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package ai
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/libs/bufpool"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/genai"
+	"text/template"
+)
+
+const repairPrompt = `The following text was supposed to be a JSON object but it can't be parsed. Fix this JSON to match the schema below and respond with the fixed JSON object only, without any explanation or Markdown.
+
+Schema:
+{
+  "error": false/true,
+  "errorMessage": "string",
+  "suggestions": ["string"],
+  "detectedLanguage": "string",
+  "data": [
+    {
+      "wordWithArticle": "string",
+      "translation": "string",
+      "plural": "string",
+      "mnemonic": "string",
+      "etymology": "string",
+      "example": {
+        "singular": {"definite": {EXAMPLES}, "indefinite": {EXAMPLES}},
+        "plural": {"definite": {EXAMPLES}, "indefinite": {EXAMPLES}}
+      }
+    }
+  ]
+}
+where EXAMPLES are the string fields nominativeExample, nominativeTranslation, accusativeExample, accusativeTranslation, dativeExample, dativeTranslation, genitiveExample and genitiveTranslation.
+
+Keep every value of the text as it is, only fix the syntax and complete the cut-off structure.
+
+The text is:
+{{.}}`
+
+var repairTemplate = template.Must(template.New("repair").Parse(repairPrompt))
+
+// SetRepairAttempts sets how often malformed answers are sent back to the model to be fixed, zero disables the repair
+func (s *GeminiService) SetRepairAttempts(attempts int) {
+	s.repairAttempts = attempts
+}
+
+// recoverText tries to turn a malformed answer into a response, first by letting the model repair
+// the JSON and then by salvaging the articles when lenient parsing is enabled
+func (s *GeminiService) recoverText(ctx context.Context, span trace.Span, model, text string) (*entities.ArticleResponse, bool) {
+	malformed := text
+	for attempt := 1; attempt <= s.repairAttempts; attempt++ {
+		span.SetAttributes(attribute.Int("ai.repair.attempts", attempt))
+		repaired, err := s.repair(ctx, model, malformed)
+		if err != nil {
+			s.logger.With(ctx).Err(err).Field("attempt", attempt).Field("model", model).Error("Failed to repair JSON with Gemini")
+			break
+		}
+
+		if response, ok := s.parseText(ctx, repaired); ok {
+			s.logger.With(ctx).Field("attempt", attempt).Field("model", model).Warning("Repaired malformed JSON answer")
+			return response, true
+		}
+		malformed = repaired
+	}
+
+	if !s.lenient {
+		return nil, false
+	}
+
+	// The article and translation come first, so they usually survive broken examples of the original answer
+	data := salvageArticles(text)
+	if len(data) == 0 {
+		return nil, false
+	}
+	s.logger.With(ctx).Field("interpretations", len(data)).Warning("Salvaged a partial answer from malformed JSON")
+	span.SetAttributes(attribute.Bool("ai.partial", true))
+	response := entities.NewSuccessResponse(data)
+	response.Partial = true
+	return response, true
+}
+
+// repair asks the model to fix the malformed JSON
+func (s *GeminiService) repair(ctx context.Context, model, malformed string) (string, error) {
+	buf := bufpool.Get()
+	defer bufpool.Put(buf)
+	if err := repairTemplate.Execute(buf, malformed); err != nil {
+		return "", err
+	}
+
+	resp, err := s.client.Models.GenerateContent(ctx, model, []*genai.Content{{
+		Parts: []*genai.Part{{Text: buf.String()}},
+		Role:  genai.RoleUser,
+	}}, s.generateConfig())
+	if err != nil {
+		return "", err
+	}
+
+	return resp.Text(), nil
+}
@@ -0,0 +1,125 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/genai"
+	"sort"
+)
+
+// safetyCategories are the harm categories of the safety thresholds by their configuration names
+var safetyCategories = map[string]genai.HarmCategory{
+	"harassment":        genai.HarmCategoryHarassment,
+	"hate_speech":       genai.HarmCategoryHateSpeech,
+	"sexually_explicit": genai.HarmCategorySexuallyExplicit,
+	"dangerous_content": genai.HarmCategoryDangerousContent,
+}
+
+// safetyThresholds are the safety thresholds by their configuration names, a threshold names the lowest
+// probability of harm blocked, none blocks nothing and off disables the filter
+var safetyThresholds = map[string]genai.HarmBlockThreshold{
+	"low":    genai.HarmBlockThresholdBlockLowAndAbove,
+	"medium": genai.HarmBlockThresholdBlockMediumAndAbove,
+	"high":   genai.HarmBlockThresholdBlockOnlyHigh,
+	"none":   genai.HarmBlockThresholdBlockNone,
+	"off":    genai.HarmBlockThresholdOff,
+}
+
+// defaultSafetySettings block the answers of every harm category already at a low probability, the answers
+// are read by learners including minors
+var defaultSafetySettings = []*genai.SafetySetting{
+	{Category: genai.HarmCategoryHarassment, Threshold: genai.HarmBlockThresholdBlockLowAndAbove},
+	{Category: genai.HarmCategoryHateSpeech, Threshold: genai.HarmBlockThresholdBlockLowAndAbove},
+	{Category: genai.HarmCategorySexuallyExplicit, Threshold: genai.HarmBlockThresholdBlockLowAndAbove},
+	{Category: genai.HarmCategoryDangerousContent, Threshold: genai.HarmBlockThresholdBlockLowAndAbove},
+}
+
+// SafetySettings returns the Gemini safety settings of the thresholds keyed by harm category, like
+// "harassment": "medium". Categories without a threshold keep the Gemini default.
+func SafetySettings(thresholds map[string]string) ([]*genai.SafetySetting, error) {
+	names := make([]string, 0, len(thresholds))
+	for name := range thresholds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	settings := make([]*genai.SafetySetting, 0, len(names))
+	for _, name := range names {
+		category, ok := safetyCategories[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown harm category %q", name)
+		}
+		threshold, ok := safetyThresholds[thresholds[name]]
+		if !ok {
+			return nil, fmt.Errorf("unknown safety threshold %q of %s", thresholds[name], name)
+		}
+		settings = append(settings, &genai.SafetySetting{Category: category, Threshold: threshold})
+	}
+
+	return settings, nil
+}
+
+// SetSafetySettings replaces the safety settings of the Gemini calls
+func (s *GeminiService) SetSafetySettings(settings []*genai.SafetySetting) {
+	s.safety = settings
+}
+
+// SetSystemInstruction sets the system instruction of the Gemini calls, empty sends none
+func (s *GeminiService) SetSystemInstruction(instruction string) {
+	s.systemInstruction = instruction
+}
+
+// generateConfig returns the configuration of the Gemini calls
+func (s *GeminiService) generateConfig() *genai.GenerateContentConfig {
+	config := &genai.GenerateContentConfig{SafetySettings: s.safety}
+	if s.systemInstruction != "" {
+		config.SystemInstruction = genai.NewContentFromText(s.systemInstruction, genai.RoleUser)
+	}
+
+	return config
+}
+
+// blockedBySafety reports whether the safety filters stopped every candidate of the answer before it had any text
+func blockedBySafety(resp *genai.GenerateContentResponse) bool {
+	blocked := false
+	for _, candidate := range resp.Candidates {
+		if candidate.Content != nil {
+			for _, part := range candidate.Content.Parts {
+				if part.Text != "" {
+					return false
+				}
+			}
+		}
+		blocked = blocked || candidate.FinishReason == genai.FinishReasonSafety
+	}
+
+	return blocked
+}
+
+// regenerateSoftly asks again for an answer blocked by the safety filters, with a prompt steering the
+// example sentences to neutral everyday topics
+func (s *GeminiService) regenerateSoftly(
+	ctx context.Context,
+	span trace.Span,
+	model string,
+	request *entities.ArticleRequest,
+) (*entities.ArticleResponse, error) {
+	span.SetAttributes(attribute.Bool("ai.safety.regenerated", true))
+	s.logger.With(ctx).Field("word", request.Word).Field("model", model).Warning("Answer blocked by the safety filters, regenerating with a softer prompt")
+
+	contents, err := s.buildContents(ctx, request, true)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Models.GenerateContent(ctx, model, contents, s.generateConfig())
+	if err != nil {
+		s.logger.With(ctx).Err(err).Field("word", request.Word).Field("model", model).Error("Failed to regenerate blocked answer with Gemini")
+		return nil, err
+	}
+	setUsageAttributes(span, resp.UsageMetadata)
+
+	return s.parseGeminiResponse(ctx, span, model, resp)
+}
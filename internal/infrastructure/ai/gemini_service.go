@@ -1,22 +1,23 @@
 package ai
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
 	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
 	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/genai"
-	"html/template"
-	"regexp"
 	"strings"
+	"time"
 )
 
 const (
-	modelName = "gemini-2.0-flash"
-	prompt    = `You are a German language assistant. I will provide you with a German noun (Nomen), and you need to determine the correct article (der, die, das).
+	// DefaultModel is the model answering regular lookups
+	DefaultModel = "gemini-2.0-flash"
+	prompt       = `You are a German language assistant. I will provide you with a German noun (Nomen), and you need to determine the correct article (der, die, das).
 
 The word is: "{{.Word}}"
 определённый артикль — definite article
@@ -25,136 +26,218 @@ Respond in JSON format with EXACTLY this structure:
 {
   "error": false/true,
   "errorMessage": "Only if there's an error, explain what's wrong in {{.Language}} language",
+  "suggestions": ["Only if there's an error and the input looks like a misspelled German noun, up to 3 closest valid nouns without article"],
+  "detectedLanguage": "Only if there's an error and the input is a word of another language than German, the ISO 639-1 code of that language, e.g. \"en\"",
   "data": [
     {
       "wordWithArticle": "article + word in German",
       "translation": "translation in {{.Language}}",
-	  "example": {
-		"singular": {
-			"definite": {
-				"nominativeExample": "simple example using the word \"{{.Word}}\" in singular nominative definite case",
-				"nominativeTranslation": "translation of the singular nominative definite example in {{.Language}}",
-				"accusativeExample": "simple example using the word \"{{.Word}}\" in singular accusative definite case",
-				"accusativeTranslation": "translation of the singular accusative definite example in {{.Language}}",
-				"dativeExample": "simple example using the word \"{{.Word}}\" in singular dative definite case",
-				"dativeTranslation": "translation of the singular dative definite example in {{.Language}}",
-				"genitiveExample": "simple example using the word \"{{.Word}}\" in singular genitive definite case",
-				"genitiveTranslation": "translation of the singular genitive definite example in {{.Language}}"
-			},
-			"indefinite": {
-				"nominativeExample": "simple example using the word \"{{.Word}}\" in singular nominative indefinite case",
-				"nominativeTranslation": "translation of the singular nominative indefinite example in {{.Language}}",
-				"accusativeExample": "simple example using the word \"{{.Word}}\" in singular accusative indefinite case",
-				"accusativeTranslation": "translation of the singular accusative indefinite example in {{.Language}}",
-				"dativeExample": "simple example using the word \"{{.Word}}\" in singular dative indefinite case",
-				"dativeTranslation": "translation of the singular dative indefinite example in {{.Language}}",
-				"genitiveExample": "simple example using the word \"{{.Word}}\" in singular genitive indefinite case",
-				"genitiveTranslation": "translation of the singular genitive indefinite example in {{.Language}}"
-			},
-		},
-		"plural": {
-			"definite": {
-				"nominativeExample": "simple example using the word \"{{.Word}}\" in plural nominative definite case",
-				"nominativeTranslation": "translation of the plural nominative definite example in {{.Language}}",
-				"accusativeExample": "simple example using the word \"{{.Word}}\" in plural accusative definite case",
-				"accusativeTranslation": "translation of the plural accusative definite example in {{.Language}}",
-				"dativeExample": "simple example using the word \"{{.Word}}\" in plural dative definite case",
-				"dativeTranslation": "translation of the plural dative definite example in {{.Language}}",
-				"genitiveExample": "simple example using the word \"{{.Word}}\" in plural genitive definite case",
-				"genitiveTranslation": "translation of the plural genitive definite example in {{.Language}}"
-			},
-			"indefinite": {
-				"nominativeExample": "simple example using the word \"{{.Word}}\" in plural nominative indefinite case",
-				"nominativeTranslation": "translation of the plural nominative indefinite example in {{.Language}}",
-				"accusativeExample": "simple example using the word \"{{.Word}}\" in plural accusative indefinite case",
-				"accusativeTranslation": "translation of the plural accusative indefinite example in {{.Language}}",
-				"dativeExample": "simple example using the word \"{{.Word}}\" in plural dative indefinite case",
-				"dativeTranslation": "translation of the plural dative indefinite example in {{.Language}}",
-				"genitiveExample": "simple example using the word \"{{.Word}}\" in plural genitive indefinite case",
-				"genitiveTranslation": "translation of the plural genitive indefinite example in {{.Language}}"
-			},
-		},
-	  }
-    }
+{{template "fields" .}}    }
   ]
 }
 
 If the input is not a German noun or contains multiple words that aren't a compound noun, set "error" to true and provide an appropriate error message.
 If there are multiple possible interpretations, include each as a separate object in the data array.
-Ensure ALL field values are properly escaped for JSON.`
+{{with .Interpretations}}The articles are already determined, answer with exactly these interpretations in this order: {{.}}.
+{{end}}{{if .ArticleOnly}}Leave out the example sentences.
+{{else if .Level}}Write every example sentence for a learner at CEFR level {{.Level}}: {{.LevelGuide}}.
+{{end}}{{if and .ExampleTopics (not .ArticleOnly)}}Take the example sentences from these topics where the word fits them: {{.ExampleTopics}}.
+{{end}}{{if and .Tone (not .ArticleOnly)}}Write the example sentences in a {{.Tone}} tone.
+{{end}}{{if and .AudienceAge (not .ArticleOnly)}}Write the example sentences for learners aged {{.AudienceAge}}, with situations and words suited to that age.
+{{end}}{{if not .ArticleOnly}}The example sentences are read by learners of all ages including children: keep them free of profanity, violence, crime and political topics.
+{{end}}{{if .Soft}}A previous answer to this request was blocked by the safety filters. Keep every value neutral and factual and write the example sentences about everyday situations at home, at school or in nature.
+{{end}}Ensure ALL field values are properly escaped for JSON.`
 )
 
+// levelGuides describes the expected complexity of the example sentences of every level
+var levelGuides = map[entities.Level]string{
+	entities.LevelA1: "very short main clauses in the present tense with the most common everyday words",
+	entities.LevelA2: "short sentences about everyday topics, simple connectors like und, aber, weil and the perfect tense",
+	entities.LevelB1: "sentences of medium length with common subordinate clauses and everyday vocabulary",
+	entities.LevelB2: "complex sentences with varied subordinate clauses, the passive voice and abstract vocabulary",
+	entities.LevelC1: "sophisticated sentences with nominal style, Konjunktiv and idiomatic expressions",
+	entities.LevelC2: "near-native sentences with precise, nuanced and literary vocabulary",
+}
+
 // GeminiService implements AIService using Google Gemini
 type GeminiService struct {
-	client *genai.Client
-	logger logging.Logger
-	tracer tracing.Tracer
+	client            *genai.Client
+	router            ModelRouter
+	prompts           prompts
+	lenient           bool
+	repairAttempts    int
+	safety            []*genai.SafetySetting
+	systemInstruction string
+	logger            logging.Logger
+	tracer            tracing.Tracer
 }
 
-// NewGeminiService creates a new Gemini AI service
-func NewGeminiService(client *genai.Client, logger logging.Logger, tracer tracing.Tracer) *GeminiService {
+// NewGeminiService creates a new Gemini AI service answering with the models of the router
+func NewGeminiService(client *genai.Client, router ModelRouter, logger logging.Logger, tracer tracing.Tracer) *GeminiService {
 	return &GeminiService{
-		client: client,
-		logger: logger,
-		tracer: tracer,
+		client:  client,
+		router:  router,
+		prompts: mustParsePrompts(),
+		safety:  defaultSafetySettings,
+		logger:  logger,
+		tracer:  tracer,
 	}
 }
 
+// SetLenientParsing enables salvaging the articles and translations of answers that aren't valid JSON,
+// they are returned as partial answers
+func (s *GeminiService) SetLenientParsing(lenient bool) {
+	s.lenient = lenient
+}
+
 // GenerateArticleInfo generates article information using Gemini AI
 func (s *GeminiService) GenerateArticleInfo(ctx context.Context, request *entities.ArticleRequest) (*entities.ArticleResponse, error) {
-	tmpl, err := template.New("prompt").Parse(prompt)
+	model := s.router.Model(request.Kind())
+	ctx, span := s.tracer.Start(ctx, "Gemini Generate Article", trace.WithAttributes(requestAttributes(model, request)...))
+	defer span.End()
+
+	contents, err := s.buildContents(ctx, request, false)
 	if err != nil {
-		s.logger.Error(ctx, map[string]interface{}{
-			"message":  "Failed to parse prompt template",
-			"error":    err.Error(),
-			"word":     request.Word,
-			"language": request.Language,
-		})
 		return nil, err
 	}
 
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, map[string]string{
-		"Word":     request.Word,
-		"Language": request.Language,
-	}); err != nil {
-		s.logger.Error(ctx, map[string]interface{}{
-			"message":  "Failed to execute prompt template",
-			"error":    err.Error(),
-			"word":     request.Word,
-			"language": request.Language,
-		})
+	resp, err := s.client.Models.GenerateContent(ctx, model, contents, s.generateConfig())
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "generate content failed")
+		s.logger.With(ctx).Err(err).Field("model", model).Field("word", request.Word).Field("language", request.Language).Error("Failed to generate content with Gemini")
 		return nil, err
 	}
+	setUsageAttributes(span, resp.UsageMetadata)
 
-	contents := []*genai.Content{{
-		Parts: []*genai.Part{{Text: buf.String()}},
-		Role:  genai.RoleUser,
-	}}
+	var response *entities.ArticleResponse
+	if blockedBySafety(resp) {
+		response, err = s.regenerateSoftly(ctx, span, model, request)
+	} else {
+		response, err = s.parseGeminiResponse(ctx, span, model, resp)
+	}
+	if response != nil {
+		response.Stamp(entities.SourceGemini, time.Now())
+	}
+
+	return response, err
+}
 
-	resp, err := s.client.Models.GenerateContent(ctx, modelName, contents, nil)
+// StreamArticleInfo streams the answer with Gemini, article and translation events are emitted
+// as soon as their values are complete in the partially generated JSON
+func (s *GeminiService) StreamArticleInfo(
+	ctx context.Context,
+	request *entities.ArticleRequest,
+	emit func(entities.StreamEvent) error,
+) (*entities.ArticleResponse, error) {
+	model := s.router.Model(request.Kind())
+	ctx, span := s.tracer.Start(ctx, "Gemini Stream Article", trace.WithAttributes(requestAttributes(model, request)...))
+	defer span.End()
+
+	contents, err := s.buildContents(ctx, request, false)
 	if err != nil {
-		s.logger.Error(ctx, map[string]interface{}{
-			"message":  "Failed to generate content with Gemini",
-			"error":    err.Error(),
-			"word":     request.Word,
-			"language": request.Language,
-		})
 		return nil, err
 	}
 
-	return s.parseGeminiResponse(ctx, resp)
+	var (
+		text    strings.Builder
+		scanner = newPartialScanner()
+		blocked bool
+		// final is the last chunk, it carries the finish reasons and the prompt feedback of the stream
+		final *genai.GenerateContentResponse
+	)
+	for resp, err := range s.client.Models.GenerateContentStream(ctx, model, contents, s.generateConfig()) {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "stream content failed")
+			s.logger.With(ctx).Err(err).Field("model", model).Field("word", request.Word).Field("language", request.Language).Error("Failed to stream content with Gemini")
+			return nil, err
+		}
+
+		// The usage of the stream is reported with its last chunk
+		setUsageAttributes(span, resp.UsageMetadata)
+		blocked = blocked || blockedBySafety(resp)
+		final = resp
+		text.WriteString(resp.Text())
+		for _, event := range scanner.scan(text.String()) {
+			if err := emit(event); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// An answer blocked before any text is regenerated without streaming, nothing has been emitted yet
+	if blocked && text.Len() == 0 {
+		response, err := s.regenerateSoftly(ctx, span, model, request)
+		if response != nil {
+			response.Stamp(entities.SourceGemini, time.Now())
+		}
+		return response, err
+	}
+
+	response, ok := s.parseText(ctx, text.String())
+	if !ok {
+		response, ok = s.recoverText(ctx, span, model, text.String())
+	}
+	if !ok && final != nil {
+		if err := s.diagnose(ctx, span, model, final); err != nil {
+			return nil, err
+		}
+	}
+	if !ok {
+		response = entities.NewErrorResponse("Failed to parse AI response")
+	}
+	response.Stamp(entities.SourceGemini, time.Now())
+
+	return response, nil
 }
 
-func (s *GeminiService) parseGeminiResponse(ctx context.Context, resp *genai.GenerateContentResponse) (*entities.ArticleResponse, error) {
+// buildContents renders the prompt for the request, the soft prompt follows an answer blocked by the safety filters
+func (s *GeminiService) buildContents(ctx context.Context, request *entities.ArticleRequest, soft bool) ([]*genai.Content, error) {
+	text, err := s.prompts.render(request, soft)
+	if err != nil {
+		s.logger.With(ctx).Err(err).Field("word", request.Word).Field("language", request.Language).Error("Failed to render prompt template")
+		return nil, err
+	}
+
+	return []*genai.Content{{
+		Parts: []*genai.Part{{Text: text}},
+		Role:  genai.RoleUser,
+	}}, nil
+}
+
+// PromptRenderer renders the prompts of the Gemini lookups without calling Gemini, for previews
+type PromptRenderer struct {
+	prompts prompts
+}
+
+// NewPromptRenderer creates a new prompt renderer
+func NewPromptRenderer() *PromptRenderer {
+	return &PromptRenderer{prompts: mustParsePrompts()}
+}
+
+// RenderPrompt returns the prompt Gemini gets for the request
+func (r *PromptRenderer) RenderPrompt(_ context.Context, request *entities.ArticleRequest) (string, error) {
+	return r.prompts.render(request, false)
+}
+
+func (s *GeminiService) parseGeminiResponse(
+	ctx context.Context,
+	span trace.Span,
+	model string,
+	resp *genai.GenerateContentResponse,
+) (*entities.ArticleResponse, error) {
 	if len(resp.Candidates) == 0 {
-		s.logger.Warning(ctx, "No candidates in Gemini response")
+		if err := s.diagnose(ctx, span, model, resp); err != nil {
+			return nil, err
+		}
 		return entities.NewErrorResponse("No response from AI service"), nil
 	}
 
+	var malformed string
 	for i, candidate := range resp.Candidates {
 		if candidate.Content == nil || len(candidate.Content.Parts) == 0 {
-			s.logger.Warning(ctx, fmt.Sprintf("Candidate %d has no content parts", i))
+			s.logger.With(ctx).Warning(fmt.Sprintf("Candidate %d has no content parts", i))
 			continue
 		}
 
@@ -166,40 +249,56 @@ func (s *GeminiService) parseGeminiResponse(ctx context.Context, resp *genai.Gen
 		}
 
 		if textResponse == "" {
-			s.logger.Warning(ctx, fmt.Sprintf("Candidate %d has no text content", i))
+			s.logger.With(ctx).Warning(fmt.Sprintf("Candidate %d has no text content", i))
 			continue
 		}
 
-		// Clean the response (remove Markdown formatting if present)
-		re := regexp.MustCompile(`(?s)\{.*}`)
-		match := re.FindString(textResponse)
-		textResponse = strings.TrimSpace(match)
-		// Remove trailing commas before closing brackets
-		reValidate := regexp.MustCompile(`,(\s*[}\]])`)
-		textResponse = reValidate.ReplaceAllString(textResponse, "$1")
-
-		// Parse the AI response format
-		var aiResponse struct {
-			Error        bool                   `json:"error"`
-			ErrorMessage string                 `json:"errorMessage"`
-			Data         []entities.ArticleInfo `json:"data"`
+		if response, ok := s.parseText(ctx, textResponse); ok {
+			return response, nil
 		}
-
-		if err := json.Unmarshal([]byte(textResponse), &aiResponse); err != nil {
-			s.logger.Error(ctx, map[string]interface{}{
-				"message":  "Failed to parse JSON response",
-				"response": textResponse,
-				"error":    err.Error(),
-			})
-			continue
+		if malformed == "" {
+			malformed = textResponse
 		}
+	}
 
-		if aiResponse.Error {
-			return entities.NewErrorResponse(aiResponse.ErrorMessage), nil
+	// Only the first malformed candidate is repaired, so a broken answer costs a bounded number of calls
+	if malformed != "" {
+		if response, ok := s.recoverText(ctx, span, model, malformed); ok {
+			return response, nil
 		}
-
-		return entities.NewSuccessResponse(aiResponse.Data), nil
 	}
 
+	if err := s.diagnose(ctx, span, model, resp); err != nil {
+		return nil, err
+	}
 	return entities.NewErrorResponse("Failed to parse AI response"), nil
 }
+
+// parseText parses the JSON answer from the model output, ok is false if it can't be parsed
+func (s *GeminiService) parseText(ctx context.Context, textResponse string) (*entities.ArticleResponse, bool) {
+	// Clean the response (remove Markdown formatting and trailing commas if present)
+	textResponse = extractJSON(textResponse)
+
+	// Parse the AI response format
+	var aiResponse struct {
+		Error            bool                   `json:"error"`
+		ErrorMessage     string                 `json:"errorMessage"`
+		Suggestions      []string               `json:"suggestions"`
+		DetectedLanguage string                 `json:"detectedLanguage"`
+		Data             []entities.ArticleInfo `json:"data"`
+	}
+
+	if err := json.Unmarshal([]byte(textResponse), &aiResponse); err != nil {
+		s.logger.With(ctx).Field("response", textResponse).Err(err).Error("Failed to parse JSON response")
+		return nil, false
+	}
+
+	if aiResponse.Error {
+		response := entities.NewErrorResponse(aiResponse.ErrorMessage)
+		response.Suggestions = aiResponse.Suggestions
+		response.DetectedLanguage = aiResponse.DetectedLanguage
+		return response, true
+	}
+
+	return entities.NewSuccessResponse(aiResponse.Data), true
+}
@@ -0,0 +1,89 @@
+package ai
+
+import (
+	cloudlogging "cloud.google.com/go/logging"
+	"context"
+	"encoding/json"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/libs/logger"
+	"github.com/DeryabinSergey/germanarticlebot/libs/race"
+	"io"
+	"testing"
+)
+
+// allocationTolerance is the allowed growth of the allocations per call over their baseline, 0.1 is 10%
+const allocationTolerance = 0.1
+
+// benchmarkAnswer returns the model output of the mock "Haus" answer, in the Markdown code block the models
+// wrap their JSON in
+func benchmarkAnswer(tb testing.TB) string {
+	tb.Helper()
+	mock, err := NewMockAIService(logger.NewSlog(io.Discard, cloudlogging.Error, false))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	response, err := mock.GenerateArticleInfo(context.Background(), &entities.ArticleRequest{Word: "Haus", Language: "en"})
+	if err != nil {
+		tb.Fatal(err)
+	}
+	data, err := json.Marshal(map[string]any{"error": false, "data": response.Data})
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	return "```json\n" + string(data) + "\n```"
+}
+
+func BenchmarkParseText(b *testing.B) {
+	ctx := context.Background()
+	answer := benchmarkAnswer(b)
+	s := NewGeminiService(nil, nil, logger.NewSlog(io.Discard, cloudlogging.Error, false), nil)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		if _, ok := s.parseText(ctx, answer); !ok {
+			b.Fatal("the answer can't be parsed")
+		}
+	}
+}
+
+func BenchmarkRenderPrompt(b *testing.B) {
+	ctx := context.Background()
+	r := NewPromptRenderer()
+	request := &entities.ArticleRequest{Word: "Haus", Language: "en", Level: entities.LevelB1}
+	b.ReportAllocs()
+	for range b.N {
+		if _, err := r.RenderPrompt(ctx, request); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// TestAllocations fails when parsing an answer or rendering a prompt allocates noticeably more than the
+// baseline, the durations depend on the machine and are left to the benchmarks
+func TestAllocations(t *testing.T) {
+	if race.Enabled {
+		t.Skip("The race detector changes the allocations")
+	}
+	ctx := context.Background()
+	answer := benchmarkAnswer(t)
+	s := NewGeminiService(nil, nil, logger.NewSlog(io.Discard, cloudlogging.Error, false), nil)
+	r := NewPromptRenderer()
+	request := &entities.ArticleRequest{Word: "Haus", Language: "en", Level: entities.LevelB1}
+
+	tests := []struct {
+		name     string
+		baseline float64
+		run      func()
+	}{
+		{name: "parseText", baseline: 15, run: func() { _, _ = s.parseText(ctx, answer) }},
+		{name: "RenderPrompt", baseline: 308, run: func() { _, _ = r.RenderPrompt(ctx, request) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if allocs := testing.AllocsPerRun(100, tt.run); allocs > tt.baseline*(1+allocationTolerance) {
+				t.Errorf("%.0f allocations per call, the baseline is %.0f", allocs, tt.baseline)
+			}
+		})
+	}
+}
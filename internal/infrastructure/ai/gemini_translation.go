@@ -0,0 +1,58 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/libs/bufpool"
+	"google.golang.org/genai"
+	"text/template"
+)
+
+const translationPrompt = `You are a German language assistant. I will provide you with a word, usually in {{.Language}} language, and you need to find the German nouns (Nomen) translating it.
+
+The word is: "{{.Word}}"
+
+Respond in JSON format with EXACTLY this structure:
+{
+  "nouns": ["German noun without article, the most common translation first"]
+}
+
+List at most {{.Max}} nouns. If the word can't be translated to a German noun, respond with an empty list.
+Ensure ALL field values are properly escaped for JSON.`
+
+var translationTemplate = template.Must(template.New("translation").Parse(translationPrompt))
+
+// FindGermanNouns asks the model for the German nouns translating the word
+func (s *GeminiService) FindGermanNouns(ctx context.Context, request *entities.TranslationRequest) ([]string, error) {
+	buf := bufpool.Get()
+	defer bufpool.Put(buf)
+	if err := translationTemplate.Execute(buf, map[string]interface{}{
+		"Word":     request.Word,
+		"Language": request.Language,
+		"Max":      entities.MaxTranslatedNouns,
+	}); err != nil {
+		return nil, err
+	}
+
+	model := s.router.Model(entities.RequestKindTranslation)
+	resp, err := s.client.Models.GenerateContent(ctx, model, []*genai.Content{{
+		Parts: []*genai.Part{{Text: buf.String()}},
+		Role:  genai.RoleUser,
+	}}, s.generateConfig())
+	if err != nil {
+		s.logger.With(ctx).Err(err).Field("model", model).Field("word", request.Word).Field("language", request.Language).Error("Failed to translate word with Gemini")
+		return nil, err
+	}
+
+	text := extractJSON(resp.Text())
+	var translation struct {
+		Nouns []string `json:"nouns"`
+	}
+	if err := json.Unmarshal([]byte(text), &translation); err != nil {
+		s.logger.With(ctx).Field("response", text).Err(err).Error("Failed to parse translation response")
+		return nil, nil
+	}
+
+	return translation.Nouns, nil
+}
@@ -0,0 +1,103 @@
+package ai
+
+import (
+	"cloud.google.com/go/auth/credentials"
+	"cloud.google.com/go/auth/httptransport"
+	"context"
+	"fmt"
+	"golang.org/x/net/http2"
+	"io"
+	"net"
+	"net/http"
+	"time"
+)
+
+const (
+	// VertexBaseURL is the endpoint of the global location of Vertex AI
+	VertexBaseURL      = "https://aiplatform.googleapis.com/"
+	cloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+	// Idle HTTP/2 connections are pinged after this long without frames, so connections broken while
+	// the instance was frozen are replaced before a lookup fails on them
+	http2ReadIdleTimeout = 30 * time.Second
+	http2PingTimeout     = 10 * time.Second
+	warmUpTimeout        = 10 * time.Second
+)
+
+// TransportOptions tunes the connection pool of the Gemini client
+type TransportOptions struct {
+	// MaxIdleConns is the number of idle connections kept open to the API between lookups
+	MaxIdleConns int
+	// IdleConnTimeout closes connections idle for longer
+	IdleConnTimeout time.Duration
+	// ConnectTimeout limits the TCP dial and the TLS handshake of a new connection
+	ConnectTimeout time.Duration
+}
+
+// NewTransport creates the pooled HTTP/2 transport of the Gemini client. All lookups of an instance go
+// to one host, so the whole pool is kept for it instead of the two idle connections of the default transport.
+func NewTransport(options TransportOptions) (*http.Transport, error) {
+	dialer := &net.Dialer{Timeout: options.ConnectTimeout, KeepAlive: 30 * time.Second}
+	transport := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          options.MaxIdleConns,
+		MaxIdleConnsPerHost:   options.MaxIdleConns,
+		IdleConnTimeout:       options.IdleConnTimeout,
+		TLSHandshakeTimeout:   options.ConnectTimeout,
+		ExpectContinueTimeout: time.Second,
+	}
+
+	h2, err := http2.ConfigureTransports(transport)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure HTTP/2: %w", err)
+	}
+	h2.ReadIdleTimeout = http2ReadIdleTimeout
+	h2.PingTimeout = http2PingTimeout
+
+	return transport, nil
+}
+
+// NewVertexHTTPClient creates the HTTP client of the Vertex AI backend on the transport, authenticated
+// with the application default credentials like the default client of genai
+func NewVertexHTTPClient(ctx context.Context, transport http.RoundTripper) (*http.Client, error) {
+	creds, err := credentials.DetectDefault(&credentials.DetectOptions{Scopes: []string{cloudPlatformScope}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find default credentials: %w", err)
+	}
+	quotaProjectID, err := creds.QuotaProjectID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quota project ID: %w", err)
+	}
+
+	client, err := httptransport.NewClient(&httptransport.Options{
+		Credentials:      creds,
+		Headers:          http.Header{"X-Goog-User-Project": []string{quotaProjectID}},
+		BaseRoundTripper: transport,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP client: %w", err)
+	}
+
+	return client, nil
+}
+
+// WarmUp opens a connection to the API and fetches the access token ahead of the first lookup, so the
+// first lookup of a cold instance doesn't pay for the TLS handshake and the token. Any HTTP response
+// means the connection is pooled.
+func WarmUp(ctx context.Context, client *http.Client, baseURL string) error {
+	ctx, cancel := context.WithTimeout(ctx, warmUpTimeout)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodHead, baseURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create warm-up request: %w", err)
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to warm up connection: %w", err)
+	}
+	_, _ = io.Copy(io.Discard, response.Body)
+
+	return response.Body.Close()
+}
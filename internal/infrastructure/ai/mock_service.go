@@ -0,0 +1,98 @@
+package ai
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"strings"
+	"time"
+)
+
+//go:embed fixtures/responses.json
+var fixtures embed.FS
+
+// mockTranslations maps English and Russian words to the German nouns of the fixtures
+var mockTranslations = map[string][]string{
+	"house": {"Haus"},
+	"дом":   {"Haus"},
+	"cat":   {"Katze"},
+	"кошка": {"Katze"},
+	"lake":  {"See"},
+	"sea":   {"See"},
+	"озеро": {"See"},
+	"море":  {"See"},
+}
+
+// MockAIService implements AIService with canned responses, for development without Gemini
+type MockAIService struct {
+	responses map[string]entities.ArticleResponse
+	logger    logging.Logger
+}
+
+// NewMockAIService creates a new mock AI service from the embedded fixtures
+func NewMockAIService(logger logging.Logger) (*MockAIService, error) {
+	data, err := fixtures.ReadFile("fixtures/responses.json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mock fixtures: %w", err)
+	}
+
+	var responses map[string]entities.ArticleResponse
+	if err := json.Unmarshal(data, &responses); err != nil {
+		return nil, fmt.Errorf("failed to parse mock fixtures: %w", err)
+	}
+
+	return &MockAIService{
+		responses: responses,
+		logger:    logger,
+	}, nil
+}
+
+// GenerateArticleInfo returns the fixture of the word or an error response for unknown words
+func (s *MockAIService) GenerateArticleInfo(ctx context.Context, request *entities.ArticleRequest) (*entities.ArticleResponse, error) {
+	response, ok := s.responses[strings.ToLower(strings.TrimSpace(request.Word))]
+	if !ok {
+		s.logger.With(ctx).Field("word", request.Word).Debug("No mock fixture for word")
+		response := entities.NewErrorResponse(fmt.Sprintf("No mock fixture for %q", request.Word))
+		response.Stamp(entities.SourceMock, time.Now())
+		// Words with a mock translation are the English ones
+		if _, ok := mockTranslations[strings.ToLower(strings.TrimSpace(request.Word))]; ok {
+			response.DetectedLanguage = "en"
+		}
+		return response, nil
+	}
+
+	// Copy so callers can't modify the fixture
+	data := make([]entities.ArticleInfo, len(response.Data))
+	copy(data, response.Data)
+	if request.ArticleOnly || request.Verbosity == entities.VerbosityMinimal {
+		for i := range data {
+			data[i].Example = entities.ExamplesInfo{}
+		}
+	}
+	response.Data = data
+	response.Stamp(entities.SourceMock, time.Now())
+
+	return &response, nil
+}
+
+// AnswerGrammarQuestion returns the same canned answer to every question
+func (s *MockAIService) AnswerGrammarQuestion(ctx context.Context, question *entities.GrammarQuestion) (*entities.GrammarAnswer, error) {
+	s.logger.With(ctx).Field("question", question.Question).Debug("Answering grammar question with mock tutor")
+
+	return &entities.GrammarAnswer{
+		Success: true,
+		Answer:  "The prepositions aus, bei, mit, nach, seit, von and zu are always followed by the dative case.",
+		Examples: []entities.GrammarExample{
+			{German: "Ich fahre mit dem Bus.", Translation: "I take the bus."},
+			{German: "Sie kommt aus der Schweiz.", Translation: "She comes from Switzerland."},
+		},
+	}, nil
+}
+
+// FindGermanNouns returns the nouns of the word known to the mock, none for other words
+func (s *MockAIService) FindGermanNouns(_ context.Context, request *entities.TranslationRequest) ([]string, error) {
+	return mockTranslations[strings.ToLower(request.Word)], nil
+}
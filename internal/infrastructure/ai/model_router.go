@@ -0,0 +1,33 @@
+package ai
+
+import "github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+
+// ModelRouter picks the model answering a kind of request
+type ModelRouter interface {
+	Model(kind entities.RequestKind) string
+}
+
+// KindRouter routes every request kind to its configured model, kinds without one go to the default model
+type KindRouter struct {
+	defaultModel string
+	models       map[entities.RequestKind]string
+}
+
+// NewKindRouter creates a router with the default model and the models of request kinds
+func NewKindRouter(defaultModel string, models map[entities.RequestKind]string) *KindRouter {
+	return &KindRouter{defaultModel: defaultModel, models: models}
+}
+
+// FixedModel creates a router answering every request with the model
+func FixedModel(model string) *KindRouter {
+	return NewKindRouter(model, nil)
+}
+
+// Model returns the model of the request kind
+func (r *KindRouter) Model(kind entities.RequestKind) string {
+	if model := r.models[kind]; model != "" {
+		return model
+	}
+
+	return r.defaultModel
+}
@@ -0,0 +1,71 @@
+package ai
+
+import (
+	"encoding/json"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"regexp"
+)
+
+// partialFields are the string fields of the answer emitted while the JSON is still incomplete,
+// a value matches only once its closing quote has been generated
+var partialFields = []struct {
+	event   entities.StreamEventType
+	pattern *regexp.Regexp
+}{
+	{entities.StreamEventArticle, regexp.MustCompile(`"wordWithArticle"\s*:\s*("(?:[^"\\]|\\.)*")`)},
+	{entities.StreamEventTranslation, regexp.MustCompile(`"translation"\s*:\s*("(?:[^"\\]|\\.)*")`)},
+}
+
+// partialScanner finds newly completed fields in the growing model output
+type partialScanner struct {
+	emitted map[entities.StreamEventType]int
+}
+
+func newPartialScanner() *partialScanner {
+	return &partialScanner{emitted: make(map[entities.StreamEventType]int)}
+}
+
+// scan returns the events of the fields completed since the previous call
+func (p *partialScanner) scan(text string) []entities.StreamEvent {
+	var events []entities.StreamEvent
+	for _, field := range partialFields {
+		matches := field.pattern.FindAllStringSubmatch(text, -1)
+		for i := p.emitted[field.event]; i < len(matches); i++ {
+			var value string
+			if err := json.Unmarshal([]byte(matches[i][1]), &value); err != nil {
+				continue
+			}
+			events = append(events, entities.StreamEvent{Type: field.event, Index: i, Value: value})
+		}
+		p.emitted[field.event] = len(matches)
+	}
+
+	return events
+}
+
+// salvageArticles reads the articles and translations of a truncated or malformed answer, the n-th
+// translation belongs to the n-th word. Nothing is salvaged from error answers.
+func salvageArticles(text string) []entities.ArticleInfo {
+	if errorAnswer.MatchString(text) {
+		return nil
+	}
+
+	var data []entities.ArticleInfo
+	words := partialFields[0].pattern.FindAllStringSubmatch(text, -1)
+	translations := partialFields[1].pattern.FindAllStringSubmatch(text, -1)
+	for i, match := range words {
+		var info entities.ArticleInfo
+		if err := json.Unmarshal([]byte(match[1]), &info.WordWithArticle); err != nil || info.Article() == "" {
+			continue
+		}
+		if i < len(translations) {
+			_ = json.Unmarshal([]byte(translations[i][1]), &info.Translation)
+		}
+		data = append(data, info)
+	}
+
+	return data
+}
+
+// errorAnswer matches answers rejecting the input
+var errorAnswer = regexp.MustCompile(`"error"\s*:\s*true`)
@@ -0,0 +1,32 @@
+package ai
+
+import (
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/genai"
+	"unicode/utf8"
+)
+
+// requestAttributes describes the AI call of the request, the word itself isn't recorded
+func requestAttributes(model string, request *entities.ArticleRequest) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("ai.model", model),
+		attribute.String("ai.request.kind", string(request.Kind())),
+		attribute.Int("word.length", utf8.RuneCountInString(request.Word)),
+		attribute.String("language", request.Language),
+	}
+}
+
+// setUsageAttributes records the token counts of the AI call, nothing is set without usage metadata
+func setUsageAttributes(span trace.Span, usage *genai.GenerateContentResponseUsageMetadata) {
+	if usage == nil {
+		return
+	}
+
+	span.SetAttributes(
+		attribute.Int("ai.tokens.prompt", int(usage.PromptTokenCount)),
+		attribute.Int("ai.tokens.candidates", int(usage.CandidatesTokenCount)),
+		attribute.Int("ai.tokens.total", int(usage.TotalTokenCount)),
+	)
+}
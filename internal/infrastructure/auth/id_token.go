@@ -0,0 +1,145 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/services"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	googleKeysURL   = "https://www.googleapis.com/oauth2/v3/certs"
+	firebaseKeysURL = "https://www.googleapis.com/service_accounts/v1/jwk/securetoken@system.gserviceaccount.com"
+
+	// clockSkew is the difference to the clock of the issuer tolerated for the expiry and issue times
+	clockSkew = time.Minute
+)
+
+// IDTokenVerifier implements IdentityVerifier for the RS256 ID tokens of Google Sign-In and Firebase Auth
+type IDTokenVerifier struct {
+	provider  string
+	issuers   []string
+	audiences []string
+	keys      *keySet
+}
+
+// NewGoogleVerifier verifies Google Sign-In ID tokens issued to the OAuth client IDs
+func NewGoogleVerifier(clientIDs []string) *IDTokenVerifier {
+	return &IDTokenVerifier{
+		provider:  entities.IdentityProviderGoogle,
+		issuers:   []string{"accounts.google.com", "https://accounts.google.com"},
+		audiences: clientIDs,
+		keys:      newKeySet(googleKeysURL),
+	}
+}
+
+// NewFirebaseVerifier verifies Firebase Auth ID tokens of the Firebase project
+func NewFirebaseVerifier(projectID string) *IDTokenVerifier {
+	return &IDTokenVerifier{
+		provider:  entities.IdentityProviderFirebase,
+		issuers:   []string{"https://securetoken.google.com/" + projectID},
+		audiences: []string{projectID},
+		keys:      newKeySet(firebaseKeysURL),
+	}
+}
+
+// Verify returns the identity of the token, nil if another issuer signed it. Tokens of the issuer
+// failing the checks return an error wrapping ErrInvalidIDToken.
+func (v *IDTokenVerifier) Verify(ctx context.Context, token string) (*entities.Identity, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, nil
+	}
+
+	var claims struct {
+		Issuer    string          `json:"iss"`
+		Audience  json.RawMessage `json:"aud"`
+		Subject   string          `json:"sub"`
+		Email     string          `json:"email"`
+		Verified  bool            `json:"email_verified"`
+		ExpiresAt int64           `json:"exp"`
+		IssuedAt  int64           `json:"iat"`
+	}
+	if err := decodeSegment(parts[1], &claims); err != nil || !slices.Contains(v.issuers, claims.Issuer) {
+		return nil, nil
+	}
+
+	var header struct {
+		Algorithm string `json:"alg"`
+		KeyID     string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil || header.Algorithm != "RS256" {
+		return nil, fmt.Errorf("%w: unsupported signing algorithm", services.ErrInvalidIDToken)
+	}
+	key, err := v.keys.key(ctx, header.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return nil, fmt.Errorf("%w: unknown signing key %q", services.ErrInvalidIDToken, header.KeyID)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed signature", services.ErrInvalidIDToken)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return nil, fmt.Errorf("%w: bad signature", services.ErrInvalidIDToken)
+	}
+
+	now := time.Now()
+	switch {
+	case !v.audienceAllowed(claims.Audience):
+		return nil, fmt.Errorf("%w: token was issued to another audience", services.ErrInvalidIDToken)
+	case now.After(time.Unix(claims.ExpiresAt, 0).Add(clockSkew)):
+		return nil, fmt.Errorf("%w: token expired", services.ErrInvalidIDToken)
+	case now.Add(clockSkew).Before(time.Unix(claims.IssuedAt, 0)):
+		return nil, fmt.Errorf("%w: token issued in the future", services.ErrInvalidIDToken)
+	case claims.Subject == "":
+		return nil, fmt.Errorf("%w: token has no subject", services.ErrInvalidIDToken)
+	}
+
+	identity := &entities.Identity{Provider: v.provider, Subject: claims.Subject}
+	if claims.Verified {
+		identity.Email = claims.Email
+	}
+
+	return identity, nil
+}
+
+// audienceAllowed accepts the audience as a string or a list, one of the values must be configured
+func (v *IDTokenVerifier) audienceAllowed(raw json.RawMessage) bool {
+	var audiences []string
+	if err := json.Unmarshal(raw, &audiences); err != nil {
+		var audience string
+		if err := json.Unmarshal(raw, &audience); err != nil {
+			return false
+		}
+		audiences = []string{audience}
+	}
+
+	for _, audience := range audiences {
+		if slices.Contains(v.audiences, audience) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
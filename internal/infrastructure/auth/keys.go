@@ -0,0 +1,123 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	keysTimeout = 10 * time.Second
+	// defaultKeysMaxAge applies when the key endpoint sends no max-age
+	defaultKeysMaxAge = time.Hour
+	// minKeysRefresh bounds the refreshes for unknown key IDs, so forged tokens can't flood the endpoint
+	minKeysRefresh = time.Minute
+)
+
+// keySet fetches the RSA public keys of a JSON Web Key Set endpoint and caches them as long as the
+// endpoint allows, unknown key IDs trigger a refresh since providers rotate their keys
+type keySet struct {
+	url         string
+	client      *http.Client
+	mu          sync.Mutex
+	keys        map[string]*rsa.PublicKey
+	expiresAt   time.Time
+	refreshedAt time.Time
+}
+
+func newKeySet(url string) *keySet {
+	return &keySet{
+		url:    url,
+		client: &http.Client{Timeout: keysTimeout},
+	}
+}
+
+// key returns the public key with the ID, nil if the endpoint doesn't list it
+func (s *keySet) key(ctx context.Context, keyID string) (*rsa.PublicKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	key, ok := s.keys[keyID]
+	if ok && now.Before(s.expiresAt) {
+		return key, nil
+	}
+	if !ok && now.Before(s.expiresAt) && now.Sub(s.refreshedAt) < minKeysRefresh {
+		return nil, nil
+	}
+
+	if err := s.refresh(ctx, now); err != nil {
+		return nil, err
+	}
+
+	return s.keys[keyID], nil
+}
+
+func (s *keySet) refresh(ctx context.Context, now time.Time) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return err
+	}
+	response, err := s.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to fetch signing keys: %w", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch signing keys: status %d", response.StatusCode)
+	}
+
+	var set struct {
+		Keys []struct {
+			KeyType string `json:"kty"`
+			KeyID   string `json:"kid"`
+			N       string `json:"n"`
+			E       string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&set); err != nil {
+		return fmt.Errorf("failed to decode signing keys: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, item := range set.Keys {
+		if item.KeyType != "RSA" {
+			continue
+		}
+		n, errN := base64.RawURLEncoding.DecodeString(item.N)
+		e, errE := base64.RawURLEncoding.DecodeString(item.E)
+		if errN != nil || errE != nil {
+			continue
+		}
+		keys[item.KeyID] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	}
+
+	s.keys = keys
+	s.refreshedAt = now
+	s.expiresAt = now.Add(maxAge(response.Header.Get("Cache-Control")))
+
+	return nil
+}
+
+// maxAge reads the max-age directive of the Cache-Control header
+func maxAge(cacheControl string) time.Duration {
+	for _, directive := range strings.Split(cacheControl, ",") {
+		value, ok := strings.CutPrefix(strings.TrimSpace(directive), "max-age=")
+		if !ok {
+			continue
+		}
+		if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+
+	return defaultKeysMaxAge
+}
@@ -0,0 +1,120 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/services"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/secrets"
+	"strings"
+	"time"
+)
+
+// sessionHeader is the encoded JOSE header of every session token
+var sessionHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+type sessionClaims struct {
+	Issuer    string `json:"iss"`
+	Subject   string `json:"sub,omitempty"`
+	UserID    int64  `json:"uid,omitempty"`
+	Provider  string `json:"prv"`
+	Email     string `json:"email,omitempty"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// SessionTokens implements SessionIssuer and IdentityVerifier with HS256 JSON Web Tokens. The key is
+// read from its source for every token, so a rotated key is picked up and invalidates the tokens
+// signed with the old one.
+type SessionTokens struct {
+	issuer string
+	key    secrets.Source
+	ttl    time.Duration
+}
+
+// NewSessionTokens creates the session tokens of the issuer valid for ttl
+func NewSessionTokens(issuer string, key secrets.Source, ttl time.Duration) *SessionTokens {
+	return &SessionTokens{
+		issuer: issuer,
+		key:    key,
+		ttl:    ttl,
+	}
+}
+
+// Issue returns a session token carrying the identity
+func (s *SessionTokens) Issue(ctx context.Context, identity *entities.Identity) (*entities.SessionToken, error) {
+	key, err := s.key(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session signing key: %w", err)
+	}
+
+	now := time.Now().UTC()
+	expiresAt := now.Add(s.ttl)
+	claims, err := json.Marshal(sessionClaims{
+		Issuer:    s.issuer,
+		Subject:   identity.Subject,
+		UserID:    identity.UserID,
+		Provider:  identity.Provider,
+		Email:     identity.Email,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	input := sessionHeader + "." + base64.RawURLEncoding.EncodeToString(claims)
+	return &entities.SessionToken{
+		AccessToken: input + "." + base64.RawURLEncoding.EncodeToString(sign([]byte(key), input)),
+		TokenType:   "Bearer",
+		ExpiresIn:   int(s.ttl.Seconds()),
+		ExpiresAt:   expiresAt,
+	}, nil
+}
+
+// Verify returns the identity of the session token, nil for tokens of other issuers. Expired and
+// forged tokens return an error wrapping ErrInvalidIDToken.
+func (s *SessionTokens) Verify(ctx context.Context, token string) (*entities.Identity, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, nil
+	}
+
+	var claims sessionClaims
+	if err := decodeSegment(parts[1], &claims); err != nil || claims.Issuer != s.issuer {
+		return nil, nil
+	}
+	if parts[0] != sessionHeader {
+		return nil, fmt.Errorf("%w: unsupported session token header", services.ErrInvalidIDToken)
+	}
+
+	key, err := s.key(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session signing key: %w", err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(signature, sign([]byte(key), parts[0]+"."+parts[1])) {
+		return nil, fmt.Errorf("%w: bad session token signature", services.ErrInvalidIDToken)
+	}
+	if time.Now().After(time.Unix(claims.ExpiresAt, 0)) {
+		return nil, fmt.Errorf("%w: session token expired", services.ErrInvalidIDToken)
+	}
+
+	return &entities.Identity{
+		UserID:   claims.UserID,
+		Provider: claims.Provider,
+		Subject:  claims.Subject,
+		Email:    claims.Email,
+		Session:  true,
+	}, nil
+}
+
+func sign(key []byte, input string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(input))
+	return mac.Sum(nil)
+}
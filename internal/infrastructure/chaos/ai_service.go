@@ -0,0 +1,51 @@
+package chaos
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/services"
+)
+
+// AIService injects faults into the calls of another AI service
+type AIService struct {
+	services.AIService
+	injector *Injector
+}
+
+// StreamingAIService injects faults into the calls of another streaming AI service
+type StreamingAIService struct {
+	AIService
+	streaming services.StreamingAIService
+}
+
+// WrapAIService returns the AI service with the faults injected, streaming services stay streaming
+func WrapAIService(ai services.AIService, injector *Injector) services.AIService {
+	wrapped := AIService{AIService: ai, injector: injector}
+	if streaming, ok := ai.(services.StreamingAIService); ok {
+		return &StreamingAIService{AIService: wrapped, streaming: streaming}
+	}
+
+	return &wrapped
+}
+
+// GenerateArticleInfo generates the answer unless an injected fault fails the call
+func (s *AIService) GenerateArticleInfo(ctx context.Context, request *entities.ArticleRequest) (*entities.ArticleResponse, error) {
+	if err := s.injector.Inject(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.AIService.GenerateArticleInfo(ctx, request)
+}
+
+// StreamArticleInfo streams the answer unless an injected fault fails the call
+func (s *StreamingAIService) StreamArticleInfo(
+	ctx context.Context,
+	request *entities.ArticleRequest,
+	emit func(entities.StreamEvent) error,
+) (*entities.ArticleResponse, error) {
+	if err := s.injector.Inject(ctx); err != nil {
+		return nil, err
+	}
+
+	return s.streaming.StreamArticleInfo(ctx, request, emit)
+}
@@ -0,0 +1,27 @@
+package chaos
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/repositories"
+)
+
+// CacheRepository injects faults into the reads of another cache, the writes pass through
+type CacheRepository struct {
+	repositories.CacheRepository
+	injector *Injector
+}
+
+// NewCacheRepository creates a new cache injecting faults into the reads of the cache
+func NewCacheRepository(cache repositories.CacheRepository, injector *Injector) *CacheRepository {
+	return &CacheRepository{CacheRepository: cache, injector: injector}
+}
+
+// Get reads the cached answer unless an injected fault fails the read
+func (r *CacheRepository) Get(ctx context.Context, key string) (*entities.ArticleResponse, bool, error) {
+	if err := r.injector.Inject(ctx); err != nil {
+		return nil, false, err
+	}
+
+	return r.CacheRepository.Get(ctx, key)
+}
@@ -0,0 +1,96 @@
+package chaos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Targets of the fault injection
+const (
+	TargetAI       = "ai"
+	TargetCache    = "cache"
+	TargetTelegram = "telegram"
+)
+
+// ErrInjected is returned by the calls failed on purpose
+var ErrInjected = errors.New("injected fault")
+
+// Faults are the shares of the calls of a target that are delayed and that fail
+type Faults struct {
+	FailureRate float64
+	DelayRate   float64
+	Delay       time.Duration
+}
+
+// ParseFaults parses the failure and delay rates of the configuration by target, rates are percentages
+// like 10%. Targets without rates get no faults.
+func ParseFaults(failureRates, delayRates map[string]string, delay time.Duration) (map[string]Faults, error) {
+	faults := make(map[string]Faults)
+	for target, rate := range failureRates {
+		value, err := parseRate(target, rate)
+		if err != nil {
+			return nil, err
+		}
+		entry := faults[target]
+		entry.FailureRate = value
+		faults[target] = entry
+	}
+	for target, rate := range delayRates {
+		value, err := parseRate(target, rate)
+		if err != nil {
+			return nil, err
+		}
+		entry := faults[target]
+		entry.DelayRate, entry.Delay = value, delay
+		faults[target] = entry
+	}
+
+	return faults, nil
+}
+
+func parseRate(target, rate string) (float64, error) {
+	percentage, err := strconv.Atoi(strings.TrimSuffix(rate, "%"))
+	if !strings.HasSuffix(rate, "%") || err != nil || percentage < 0 || percentage > 100 {
+		return 0, fmt.Errorf("the fault rate of %s must be a percentage like 10%%, got %q", target, rate)
+	}
+
+	return float64(percentage) / 100, nil
+}
+
+// Injector injects the faults into the calls of a target
+type Injector struct {
+	target string
+	faults Faults
+	logger logging.Logger
+}
+
+// NewInjector creates a new fault injector of the target
+func NewInjector(target string, faults Faults, logger logging.Logger) *Injector {
+	return &Injector{target: target, faults: faults, logger: logger}
+}
+
+// Inject delays the call and fails it at the rates of the faults, a delay ends early with the context
+func (i *Injector) Inject(ctx context.Context) error {
+	if i.faults.DelayRate > 0 && rand.Float64() < i.faults.DelayRate {
+		i.logger.With(ctx).Field("target", i.target).Field("delay", i.faults.Delay.String()).Debug("Injected delay")
+		timer := time.NewTimer(i.faults.Delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if i.faults.FailureRate > 0 && rand.Float64() < i.faults.FailureRate {
+		i.logger.With(ctx).Field("target", i.target).Debug("Injected failure")
+		return fmt.Errorf("%w: %s", ErrInjected, i.target)
+	}
+
+	return nil
+}
@@ -0,0 +1,35 @@
+package chaos
+
+import (
+	"net/http"
+	"path"
+	"strings"
+)
+
+// Transport injects faults into the requests of the Telegram Bot API sending or editing messages, the other
+// methods like getMe pass through, so the bot still starts
+type Transport struct {
+	base     http.RoundTripper
+	injector *Injector
+}
+
+// NewTransport creates a new transport injecting faults into the sends of the base transport
+func NewTransport(base http.RoundTripper, injector *Injector) *Transport {
+	return &Transport{base: base, injector: injector}
+}
+
+// RoundTrip sends the request unless an injected fault fails it
+func (t *Transport) RoundTrip(request *http.Request) (*http.Response, error) {
+	// The Bot API methods end the path, like /bot<token>/sendMessage
+	method := path.Base(request.URL.Path)
+	if strings.HasPrefix(method, "send") || strings.HasPrefix(method, "editMessage") {
+		if err := t.injector.Inject(request.Context()); err != nil {
+			if request.Body != nil {
+				_ = request.Body.Close()
+			}
+			return nil, err
+		}
+	}
+
+	return t.base.RoundTrip(request)
+}
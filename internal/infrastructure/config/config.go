@@ -1,32 +1,1139 @@
 package config
 
 import (
+	"cloud.google.com/go/logging"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"net/url"
 	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	AIProviderGemini = "gemini"
+	AIProviderMock   = "mock"
+
+	TraceExporterCloudTrace = "cloudtrace"
+	TraceExporterOTLP       = "otlp"
+	TraceExporterStdout     = "stdout"
+	TraceExporterNone       = "none"
+
+	LogFormatJSON = "json"
+	LogFormatText = "text"
+
+	JobsBackendLocal      = "local"
+	JobsBackendCloudTasks = "cloudtasks"
+
+	StorageMemory    = "memory"
+	StorageFirestore = "firestore"
+	StorageRedis     = "redis"
+	StorageSQLite    = "sqlite"
+	StoragePostgres  = "postgres"
+
+	ResponseSigningHMAC    = "hmac-sha256"
+	ResponseSigningEd25519 = "ed25519"
+
+	minAdminTokenLength = 16
+	minSessionKeyLength = 32
+	maxLogLevel         = 800 // logging.Emergency
+	maxRepairAttempts   = 3
+	// maxTelegramMessageLength is the longest text of a Telegram message
+	maxTelegramMessageLength = 4096
+	// maxExampleTopicLength bounds the topics of the tenants, they are part of every prompt of the tenant
+	maxExampleTopicLength = 64
+)
+
+var telegramTokenPattern = regexp.MustCompile(`^\d+:[A-Za-z0-9_-]+$`)
+
+// languagePattern matches the two-letter language codes of Telegram users
+var languagePattern = regexp.MustCompile(`^[a-z]{2}$`)
+
+// tenantIDPattern matches the IDs of the tenants, they are part of keys and logs
+var tenantIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
+// rolloutPattern matches the rollouts of the feature flags: on, off or a percentage from 0% to 100%
+var rolloutPattern = regexp.MustCompile(`^(?i:on|off|100%|[1-9]?[0-9]%)$`)
+
+// chaosTargetPattern and chaosRatePattern match the targets of the fault injection and their rates
+var (
+	chaosTargetPattern = regexp.MustCompile(`^(ai|cache|telegram)$`)
+	chaosRatePattern   = regexp.MustCompile(`^(100|[1-9]?[0-9])%$`)
+)
+
+// safetyCategoryPattern and safetyThresholdPattern match the Gemini harm categories and their thresholds
+var (
+	safetyCategoryPattern  = regexp.MustCompile(`^(harassment|hate_speech|sexually_explicit|dangerous_content)$`)
+	safetyThresholdPattern = regexp.MustCompile(`^(low|medium|high|none|off)$`)
 )
 
 // Config holds application configuration
 type Config struct {
-	ProjectID       string
-	ApplicationName string
-	TelegramToken   string
-	GCPEnabled      bool
-	LogLevel        int
+	// Required
+	ProjectID       string `json:"projectId" yaml:"projectId"`
+	ApplicationName string `json:"applicationName" yaml:"applicationName"`
+
+	// Optional
+	TelegramToken string        `json:"telegramToken" yaml:"telegramToken"`
+	AdminToken    string        `json:"adminToken" yaml:"adminToken"`
+	AIProvider    string        `json:"aiProvider" yaml:"aiProvider"`
+	AIDailyQuota  int64         `json:"aiDailyQuota" yaml:"aiDailyQuota"`
+	CacheTTL      time.Duration `json:"cacheTtl" yaml:"cacheTtl"`
+	CacheSize     int           `json:"cacheSize" yaml:"cacheSize"`
+	// How long answers rejecting a word and AI failures are cached, so repeated input doesn't reach the AI, zero disables it
+	NegativeCacheTTL time.Duration `json:"negativeCacheTtl" yaml:"negativeCacheTtl"`
+	// Requests are canceled after the timeout, streams and background jobs excepted
+	HTTPRequestTimeout time.Duration `json:"httpRequestTimeout" yaml:"httpRequestTimeout"`
+	// Request bodies of routes without their own limit are rejected beyond this size
+	HTTPMaxBodyBytes int64 `json:"httpMaxBodyBytes" yaml:"httpMaxBodyBytes"`
+	// Shares of the request deadline of the lookup stages, the AI gets the rest minus the reserve for the answer
+	DeadlineCacheBudget      time.Duration `json:"deadlineCacheBudget" yaml:"deadlineCacheBudget"`
+	DeadlineDictionaryBudget time.Duration `json:"deadlineDictionaryBudget" yaml:"deadlineDictionaryBudget"`
+	DeadlineReserve          time.Duration `json:"deadlineReserve" yaml:"deadlineReserve"`
+	// Consecutive AI failures after which lookups are answered from the dictionary, the AI is probed again
+	// after the cooldown; a zero threshold keeps asking the AI
+	AIDegradationThreshold int           `json:"aiDegradationThreshold" yaml:"aiDegradationThreshold"`
+	AIDegradationCooldown  time.Duration `json:"aiDegradationCooldown" yaml:"aiDegradationCooldown"`
+	// How long the responses of POST requests with an Idempotency-Key are replayed to retries
+	IdempotencyTTL time.Duration `json:"idempotencyTtl" yaml:"idempotencyTtl"`
+	// How long the states of background jobs can be polled at /jobs/{id}
+	JobStatusTTL time.Duration `json:"jobStatusTtl" yaml:"jobStatusTtl"`
+	// How long the codes of /link can be exchanged for API tokens
+	AccountLinkCodeTTL time.Duration `json:"accountLinkCodeTtl" yaml:"accountLinkCodeTtl"`
+	// Google Sign-In ID tokens issued to these OAuth client IDs authenticate API requests
+	GoogleClientIDs []string `json:"googleClientIds" yaml:"googleClientIds"`
+	// Firebase Auth ID tokens of this project authenticate API requests when set
+	FirebaseProjectID string `json:"firebaseProjectId" yaml:"firebaseProjectId"`
+	// Session tokens of POST /auth/token are signed with the key and valid for the TTL, disabled without a key
+	SessionSigningKey string        `json:"sessionSigningKey" yaml:"sessionSigningKey"`
+	SessionTokenTTL   time.Duration `json:"sessionTokenTtl" yaml:"sessionTokenTtl"`
+	// Origins of browser clients allowed to call the API, "*" allows every origin
+	CORSAllowedOrigins []string `json:"corsAllowedOrigins" yaml:"corsAllowedOrigins"`
+	// Browsers may send cookies and the Authorization header with cross-origin requests, not with "*"
+	CORSAllowCredentials bool `json:"corsAllowCredentials" yaml:"corsAllowCredentials"`
+	// How long browsers may cache the preflight responses
+	CORSMaxAge time.Duration `json:"corsMaxAge" yaml:"corsMaxAge"`
+	// How long clients and CDNs may cache successful GET answers of the article endpoint, zero disables it
+	HTTPCacheMaxAge time.Duration `json:"httpCacheMaxAge" yaml:"httpCacheMaxAge"`
+	FollowUpTTL     time.Duration `json:"followUpTtl" yaml:"followUpTtl"`
+	GCPEnabled      bool          `json:"gcpEnabled" yaml:"gcpEnabled"`
+	LogLevel        int           `json:"logLevel" yaml:"logLevel"`
+
+	// Local logs are written by slog in this format, GCP logs always go to Cloud Logging
+	LogFormat string `json:"logFormat" yaml:"logFormat"`
+	// Share of the entries below Warning kept on high-volume paths, between 0 and 1
+	LogSampleRate float64 `json:"logSampleRate" yaml:"logSampleRate"`
+
+	// Exporter of the spans, Cloud Trace on GCP and OTLP otherwise when it's empty
+	TraceExporter string `json:"traceExporter" yaml:"traceExporter"`
+
+	// Monthly AI spend cap in USD, lookups degrade to the cache and the dictionary when it's hit
+	AIMonthlySpendCap float64 `json:"aiMonthlySpendCap" yaml:"aiMonthlySpendCap"`
+	// Estimated cost of an AI call in USD, summed up to the monthly spend
+	AICostPerCall float64 `json:"aiCostPerCall" yaml:"aiCostPerCall"`
+	// Chat receiving operator alerts like the hit spend cap
+	TelegramAdminChatID int64 `json:"telegramAdminChatId" yaml:"telegramAdminChatId"`
+
+	// Salvage the articles and translations of malformed AI answers as partial answers
+	AILenientParsing bool `json:"aiLenientParsing" yaml:"aiLenientParsing"`
+	// Malformed AI answers are sent back to the model to be fixed this many times before giving up
+	AIRepairAttempts int `json:"aiRepairAttempts" yaml:"aiRepairAttempts"`
+
+	// Models of the request kinds, lookups without examples go to a cheaper model than full lookups
+	AIArticleModel     string `json:"aiArticleModel" yaml:"aiArticleModel"`
+	AIFullModel        string `json:"aiFullModel" yaml:"aiFullModel"`
+	AITranslationModel string `json:"aiTranslationModel" yaml:"aiTranslationModel"`
+	AIGrammarModel     string `json:"aiGrammarModel" yaml:"aiGrammarModel"`
+
+	// Model giving the second opinion on reported answers, should differ from the answering model
+	AIVerificationModel string `json:"aiVerificationModel" yaml:"aiVerificationModel"`
+
+	// Gemini safety thresholds of the harm categories, the lowest probability of harm blocked: low, medium,
+	// high, none to block nothing or off to disable the filter
+	AISafetyThresholds map[string]string `json:"aiSafetyThresholds" yaml:"aiSafetyThresholds"`
+	// System instruction of every Gemini call
+	AISystemInstruction string `json:"aiSystemInstruction" yaml:"aiSystemInstruction"`
+
+	// Connection pool of the Gemini client, kept by warm instances between invocations
+	AIMaxIdleConns    int           `json:"aiMaxIdleConns" yaml:"aiMaxIdleConns"`
+	AIIdleConnTimeout time.Duration `json:"aiIdleConnTimeout" yaml:"aiIdleConnTimeout"`
+	AIConnectTimeout  time.Duration `json:"aiConnectTimeout" yaml:"aiConnectTimeout"`
+
+	// Vocabulary list imports
+	ImportMaxWords  int     `json:"importMaxWords" yaml:"importMaxWords"`
+	ImportRateLimit float64 `json:"importRateLimit" yaml:"importRateLimit"`
+
+	// Grammar questions of /ask allowed per client and minute
+	AskRateLimit int `json:"askRateLimit" yaml:"askRateLimit"`
+
+	// Background jobs, Cloud Tasks deliver them to the worker endpoint of the deployed function
+	JobsBackend      string `json:"jobsBackend" yaml:"jobsBackend"`
+	TasksQueue       string `json:"tasksQueue" yaml:"tasksQueue"`
+	TasksWorkerURL   string `json:"tasksWorkerUrl" yaml:"tasksWorkerUrl"`
+	TasksWorkerToken string `json:"tasksWorkerToken" yaml:"tasksWorkerToken"`
+
+	// Backend of the cache, preferences, feedback, job states and learning activity, memory keeps them per instance
+	Storage          string `json:"storage" yaml:"storage"`
+	RedisURL         string `json:"redisUrl" yaml:"redisUrl"`
+	SQLitePath       string `json:"sqlitePath" yaml:"sqlitePath"`
+	PostgresURL      string `json:"postgresUrl" yaml:"postgresUrl"`
+	PostgresMaxConns int    `json:"postgresMaxConns" yaml:"postgresMaxConns"`
+	// Instances apply the missing migrations of the storage schema when they start, otherwise cmd/migrate applies
+	// them and instances refuse to start on a schema missing migrations
+	StorageMigrate bool `json:"storageMigrate" yaml:"storageMigrate"`
+	// Ratings and dead letters older than the retention and the activity of users inactive for longer are
+	// purged by the retention sweep, zero keeps them
+	DataRetention time.Duration `json:"dataRetention" yaml:"dataRetention"`
+
+	// Tenants of the API like language schools, resolved by their API keys, set in the CONFIG_FILE only
+	Tenants []TenantConfig `json:"tenants" yaml:"tenants"`
+
+	// Results of async lookups are posted to the callbacks signed with this secret, async lookups are disabled without it
+	WebhookSigningSecret string `json:"webhookSigningSecret" yaml:"webhookSigningSecret"`
+	// Callbacks to private and loopback addresses are allowed, for local development only
+	WebhookAllowPrivate bool `json:"webhookAllowPrivate" yaml:"webhookAllowPrivate"`
+
+	// JSON responses of the lookup routes are signed with the algorithm when set, Ed25519 keys are the base64 seed
+	ResponseSigningAlgorithm string `json:"responseSigningAlgorithm" yaml:"responseSigningAlgorithm"`
+	ResponseSigningKey       string `json:"responseSigningKey" yaml:"responseSigningKey"`
+	// Sent with the signatures, derived from the key when empty
+	ResponseSigningKeyID string `json:"responseSigningKeyId" yaml:"responseSigningKeyId"`
+
+	// Alexa requests of other skills are rejected when set
+	AlexaSkillID string `json:"alexaSkillId" yaml:"alexaSkillId"`
+
+	// Group chats get answers only when the bot is mentioned or replied to
+	TelegramGroupsEnabled  bool             `json:"telegramGroupsEnabled" yaml:"telegramGroupsEnabled"`
+	TelegramGroupLanguages map[int64]string `json:"telegramGroupLanguages" yaml:"telegramGroupLanguages"`
+	// Public URL of the deployment, the webhook of every bot is set to <url>/telegram/<bot id> by the admin API
+	TelegramWebhookURL string `json:"telegramWebhookUrl" yaml:"telegramWebhookUrl"`
+	// White-label bots served besides the main bot with their own welcome text and default language, set in
+	// the CONFIG_FILE only
+	TelegramBots []TelegramBotConfig `json:"telegramBots" yaml:"telegramBots"`
+
+	// Rollouts of the features by name, "on", "off" or a percentage of the chats like "25%"
+	FeatureFlags map[string]string `json:"featureFlags" yaml:"featureFlags"`
+	// Firestore collection whose documents override the rollouts, refreshed after FeatureFlagsRefresh
+	FeatureFlagsCollection string        `json:"featureFlagsCollection" yaml:"featureFlagsCollection"`
+	FeatureFlagsRefresh    time.Duration `json:"featureFlagsRefresh" yaml:"featureFlagsRefresh"`
+
+	// Fault injection for resilience tests in staging: shares of the AI calls, cache reads and Telegram sends
+	// that fail or are delayed by ChaosDelay, like "ai": "10%". Nothing is injected unless it's enabled.
+	ChaosEnabled      bool              `json:"chaosEnabled" yaml:"chaosEnabled"`
+	ChaosFailureRates map[string]string `json:"chaosFailureRates" yaml:"chaosFailureRates"`
+	ChaosDelayRates   map[string]string `json:"chaosDelayRates" yaml:"chaosDelayRates"`
+	ChaosDelay        time.Duration     `json:"chaosDelay" yaml:"chaosDelay"`
+
+	// Secret Manager references used instead of the raw values
+	TelegramTokenSecret      string        `json:"telegramTokenSecret" yaml:"telegramTokenSecret"`
+	AdminTokenSecret         string        `json:"adminTokenSecret" yaml:"adminTokenSecret"`
+	ResponseSigningKeySecret string        `json:"responseSigningKeySecret" yaml:"responseSigningKeySecret"`
+	SessionSigningKeySecret  string        `json:"sessionSigningKeySecret" yaml:"sessionSigningKeySecret"`
+	SecretsCacheTTL          time.Duration `json:"secretsCacheTtl" yaml:"secretsCacheTtl"`
+}
+
+// TelegramBotConfig is a white-label bot sharing the lookups and the data of the main bot
+type TelegramBotConfig struct {
+	Token string `json:"token" yaml:"token"`
+	// Replaces the welcome text of /start
+	Welcome string `json:"welcome" yaml:"welcome"`
+	// Language of the users without one, like "de"
+	Language string `json:"language" yaml:"language"`
+}
+
+// TenantConfig is a tenant of the API with its own API keys, quota and example topics
+type TenantConfig struct {
+	ID   string `json:"id" yaml:"id"`
+	Name string `json:"name" yaml:"name"`
+	// Requests of the keys are made for the tenant, several keys allow rotating them
+	APIKeys []string `json:"apiKeys" yaml:"apiKeys"`
+	// API requests of the tenant per UTC day, zero leaves them unlimited
+	DailyQuota int64 `json:"dailyQuota" yaml:"dailyQuota"`
+	// Topics the example sentences of the lookups of the tenant are taken from
+	ExampleTopics []string `json:"exampleTopics" yaml:"exampleTopics"`
+}
+
+// SecretGetter resolves secret values by name
+type SecretGetter interface {
+	Get(ctx context.Context, name string) (string, error)
 }
 
-// LoadConfig loads configuration from environment variables
-func LoadConfig() *Config {
+// Defaults returns the configuration used when neither the file nor the environment sets a value
+func Defaults() *Config {
 	return &Config{
-		ProjectID:       getEnv("PROJECT_ID", "german-article-bot"),
-		ApplicationName: getEnv("APPLICATION_NAME", "article-bot"),
-		TelegramToken:   getEnv("TELEGRAM_BOT_TOKEN", ""),
-		GCPEnabled:      getEnv("GCP_ENABLED", "true") == "true",
-		LogLevel:        100, // Default log level
+		ProjectID:             "german-article-bot",
+		ApplicationName:       "article-bot",
+		AIProvider:            AIProviderGemini,
+		AIVerificationModel:   "gemini-2.5-flash",
+		AIArticleModel:        "gemini-2.0-flash-lite",
+		AIFullModel:           "gemini-2.0-flash",
+		AITranslationModel:    "gemini-2.0-flash",
+		AIGrammarModel:        "gemini-2.0-flash",
+		TelegramGroupsEnabled: true,
+		CacheTTL:              24 * time.Hour,
+		CacheSize:             10000,
+		NegativeCacheTTL:      2 * time.Minute,
+		HTTPCacheMaxAge:       time.Hour,
+		HTTPRequestTimeout:    30 * time.Second,
+		HTTPMaxBodyBytes:      256 << 10,
+		IdempotencyTTL:        24 * time.Hour,
+		JobStatusTTL:          24 * time.Hour,
+		AccountLinkCodeTTL:    10 * time.Minute,
+		SessionTokenTTL:       15 * time.Minute,
+		CORSAllowedOrigins:    []string{"*"},
+		CORSMaxAge:            24 * time.Hour,
+		FollowUpTTL:           10 * time.Minute,
+		GCPEnabled:            true,
+		LogLevel:              100, // Default log level
+		LogFormat:             LogFormatJSON,
+		LogSampleRate:         1,
+		SecretsCacheTTL:       5 * time.Minute,
+		ImportMaxWords:        200,
+		ImportRateLimit:       2,
+		AskRateLimit:          5,
+		AICostPerCall:         0.0005,
+		AILenientParsing:      true,
+		AIRepairAttempts:      1,
+		AIMaxIdleConns:        16,
+		AIIdleConnTimeout:     90 * time.Second,
+		AIConnectTimeout:      5 * time.Second,
+		JobsBackend:           JobsBackendLocal,
+		Storage:               StorageMemory,
+		SQLitePath:            "article-bot.db",
+		PostgresMaxConns:      10,
+		StorageMigrate:        true,
+		DataRetention:         365 * 24 * time.Hour,
+
+		// Shares of the request deadline of the lookup stages
+		DeadlineCacheBudget:      50 * time.Millisecond,
+		DeadlineDictionaryBudget: 200 * time.Millisecond,
+		DeadlineReserve:          500 * time.Millisecond,
+
+		// The answers are read by learners including minors
+		AISafetyThresholds: map[string]string{
+			"harassment":        "low",
+			"hate_speech":       "low",
+			"sexually_explicit": "low",
+			"dangerous_content": "low",
+		},
+		AISystemInstruction: "You help people of all ages, including children, to learn German. " +
+			"Keep every answer suitable for them: no profanity, violence, crime or political topics.",
+
+		// Reduced mode of lookups while the AI keeps failing
+		AIDegradationThreshold: 5,
+		AIDegradationCooldown:  30 * time.Second,
+
+		// Features being rolled out, FEATURE_FLAGS adds to them
+		FeatureFlags:        map[string]string{"quiz": "on"},
+		FeatureFlagsRefresh: time.Minute,
+
+		ChaosDelay: 2 * time.Second,
+	}
+}
+
+// LoadConfig loads configuration from defaults, the optional CONFIG_FILE and environment variables,
+// in increasing order of precedence, and validates the result
+func LoadConfig() (*Config, error) {
+	cfg := Defaults()
+
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := cfg.loadEnv(); err != nil {
+		return nil, err
+	}
+
+	// The trace exporter follows GCP_ENABLED unless it's set explicitly
+	if cfg.TraceExporter == "" {
+		cfg.TraceExporter = TraceExporterOTLP
+		if cfg.GCPEnabled {
+			cfg.TraceExporter = TraceExporterCloudTrace
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// Validate checks all fields and returns every problem found at once
+func (c *Config) Validate() error {
+	var errs []error
+
+	if strings.TrimSpace(c.ProjectID) == "" {
+		errs = append(errs, errors.New("PROJECT_ID is required"))
+	}
+	if strings.TrimSpace(c.ApplicationName) == "" {
+		errs = append(errs, errors.New("APPLICATION_NAME is required"))
+	}
+	if c.TelegramToken != "" && !telegramTokenPattern.MatchString(c.TelegramToken) {
+		errs = append(errs, errors.New("TELEGRAM_BOT_TOKEN has an invalid format, expected <bot id>:<secret>"))
+	}
+	if c.AdminToken != "" && len(c.AdminToken) < minAdminTokenLength {
+		errs = append(errs, fmt.Errorf("ADMIN_TOKEN must be at least %d characters long", minAdminTokenLength))
+	}
+	if c.AIProvider != AIProviderGemini && c.AIProvider != AIProviderMock {
+		errs = append(errs, fmt.Errorf("AI_PROVIDER must be %q or %q, got %q", AIProviderGemini, AIProviderMock, c.AIProvider))
+	}
+	if c.AIProvider == AIProviderGemini {
+		for key, model := range map[string]string{
+			"AI_VERIFICATION_MODEL": c.AIVerificationModel,
+			"AI_ARTICLE_MODEL":      c.AIArticleModel,
+			"AI_FULL_MODEL":         c.AIFullModel,
+			"AI_TRANSLATION_MODEL":  c.AITranslationModel,
+			"AI_GRAMMAR_MODEL":      c.AIGrammarModel,
+		} {
+			if strings.TrimSpace(model) == "" {
+				errs = append(errs, fmt.Errorf("%s is required for the gemini provider", key))
+			}
+		}
+	}
+	if c.AIMaxIdleConns < 0 {
+		errs = append(errs, errors.New("AI_MAX_IDLE_CONNS must not be negative"))
+	}
+	if c.AIIdleConnTimeout < 0 {
+		errs = append(errs, errors.New("AI_IDLE_CONN_TIMEOUT must not be negative"))
+	}
+	if c.AIConnectTimeout < 0 {
+		errs = append(errs, errors.New("AI_CONNECT_TIMEOUT must not be negative"))
+	}
+	if c.AIDailyQuota < 0 {
+		errs = append(errs, errors.New("AI_DAILY_QUOTA must not be negative"))
+	}
+	if c.AIMonthlySpendCap < 0 {
+		errs = append(errs, errors.New("AI_MONTHLY_SPEND_CAP must not be negative"))
+	}
+	if c.AICostPerCall < 0 {
+		errs = append(errs, errors.New("AI_COST_PER_CALL must not be negative"))
+	}
+	if c.AIRepairAttempts < 0 || c.AIRepairAttempts > maxRepairAttempts {
+		errs = append(errs, fmt.Errorf("AI_REPAIR_ATTEMPTS must be between 0 and %d", maxRepairAttempts))
+	}
+	for category, threshold := range c.AISafetyThresholds {
+		if !safetyCategoryPattern.MatchString(category) {
+			errs = append(errs, fmt.Errorf("AI_SAFETY_THRESHOLDS category must be harassment, hate_speech, sexually_explicit or dangerous_content, got %q", category))
+		}
+		if !safetyThresholdPattern.MatchString(threshold) {
+			errs = append(errs, fmt.Errorf("AI_SAFETY_THRESHOLDS threshold of %s must be low, medium, high, none or off, got %q", category, threshold))
+		}
+	}
+	if c.CacheTTL <= 0 {
+		errs = append(errs, errors.New("CACHE_TTL must be positive"))
+	}
+	if c.CacheSize < 0 {
+		errs = append(errs, errors.New("CACHE_SIZE must not be negative"))
+	}
+	if c.NegativeCacheTTL < 0 {
+		errs = append(errs, errors.New("NEGATIVE_CACHE_TTL must not be negative"))
+	}
+	if c.HTTPCacheMaxAge < 0 {
+		errs = append(errs, errors.New("HTTP_CACHE_MAX_AGE must not be negative"))
+	}
+	if c.HTTPRequestTimeout < 0 {
+		errs = append(errs, errors.New("HTTP_REQUEST_TIMEOUT must not be negative"))
+	}
+	if c.DeadlineCacheBudget < 0 || c.DeadlineDictionaryBudget < 0 || c.DeadlineReserve < 0 {
+		errs = append(errs, errors.New("DEADLINE_CACHE_BUDGET, DEADLINE_DICTIONARY_BUDGET and DEADLINE_RESERVE must not be negative"))
+	}
+	if c.AIDegradationThreshold < 0 {
+		errs = append(errs, errors.New("AI_DEGRADATION_THRESHOLD must not be negative"))
+	}
+	if c.AIDegradationThreshold > 0 && c.AIDegradationCooldown <= 0 {
+		errs = append(errs, errors.New("AI_DEGRADATION_COOLDOWN must be positive"))
+	}
+	if c.HTTPMaxBodyBytes <= 0 {
+		errs = append(errs, errors.New("HTTP_MAX_BODY_BYTES must be positive"))
+	}
+	if c.WebhookSigningSecret != "" && len(c.WebhookSigningSecret) < minAdminTokenLength {
+		errs = append(errs, fmt.Errorf("WEBHOOK_SIGNING_SECRET must be at least %d characters long", minAdminTokenLength))
+	}
+	switch c.ResponseSigningAlgorithm {
+	case "":
+	case ResponseSigningHMAC, ResponseSigningEd25519:
+		if c.ResponseSigningKey == "" && c.ResponseSigningKeySecret == "" {
+			errs = append(errs, errors.New("RESPONSE_SIGNING_ALGORITHM requires RESPONSE_SIGNING_KEY or RESPONSE_SIGNING_KEY_SECRET"))
+		}
+		if c.ResponseSigningAlgorithm == ResponseSigningHMAC && c.ResponseSigningKey != "" && len(c.ResponseSigningKey) < minAdminTokenLength {
+			errs = append(errs, fmt.Errorf("RESPONSE_SIGNING_KEY must be at least %d characters long", minAdminTokenLength))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("RESPONSE_SIGNING_ALGORITHM must be %q or %q, got %q",
+			ResponseSigningHMAC, ResponseSigningEd25519, c.ResponseSigningAlgorithm))
+	}
+	if c.IdempotencyTTL <= 0 {
+		errs = append(errs, errors.New("IDEMPOTENCY_TTL must be positive"))
+	}
+	if c.JobStatusTTL <= 0 {
+		errs = append(errs, errors.New("JOB_STATUS_TTL must be positive"))
+	}
+	for _, origin := range c.CORSAllowedOrigins {
+		if origin == "*" {
+			if c.CORSAllowCredentials {
+				errs = append(errs, errors.New("CORS_ALLOW_CREDENTIALS requires explicit CORS_ALLOWED_ORIGINS instead of *"))
+			}
+			continue
+		}
+		if u, err := url.Parse(origin); err != nil || u.Scheme == "" || u.Host == "" || u.Path != "" {
+			errs = append(errs, fmt.Errorf("CORS_ALLOWED_ORIGINS must contain * or origins like https://example.com, got %q", origin))
+		}
+	}
+	if c.CORSMaxAge < 0 {
+		errs = append(errs, errors.New("CORS_MAX_AGE must not be negative"))
+	}
+	if c.AccountLinkCodeTTL <= 0 {
+		errs = append(errs, errors.New("ACCOUNT_LINK_CODE_TTL must be positive"))
+	}
+	for _, clientID := range c.GoogleClientIDs {
+		if !strings.HasSuffix(clientID, ".apps.googleusercontent.com") {
+			errs = append(errs, fmt.Errorf("GOOGLE_CLIENT_IDS must contain OAuth client IDs ending in .apps.googleusercontent.com, got %q", clientID))
+		}
+	}
+	if c.SessionSigningKey != "" && len(c.SessionSigningKey) < minSessionKeyLength {
+		errs = append(errs, fmt.Errorf("SESSION_SIGNING_KEY must be at least %d characters long", minSessionKeyLength))
+	}
+	if c.SessionTokenTTL <= 0 {
+		errs = append(errs, errors.New("SESSION_TOKEN_TTL must be positive"))
+	}
+	if c.FollowUpTTL <= 0 {
+		errs = append(errs, errors.New("FOLLOW_UP_TTL must be positive"))
+	}
+	for feature, rollout := range c.FeatureFlags {
+		if !rolloutPattern.MatchString(rollout) {
+			errs = append(errs, fmt.Errorf("FEATURE_FLAGS rollout of %s must be on, off or a percentage like 25%%, got %q", feature, rollout))
+		}
+	}
+	if c.FeatureFlagsRefresh <= 0 {
+		errs = append(errs, errors.New("FEATURE_FLAGS_REFRESH must be positive"))
+	}
+	if c.LogLevel < 0 || c.LogLevel > maxLogLevel {
+		errs = append(errs, fmt.Errorf("LOG_LEVEL must be between 0 and %d", maxLogLevel))
+	}
+	if c.LogFormat != LogFormatJSON && c.LogFormat != LogFormatText {
+		errs = append(errs, fmt.Errorf("LOG_FORMAT must be %q or %q, got %q", LogFormatJSON, LogFormatText, c.LogFormat))
+	}
+	if c.LogSampleRate < 0 || c.LogSampleRate > 1 {
+		errs = append(errs, errors.New("LOG_SAMPLE_RATE must be between 0 and 1"))
+	}
+	switch c.TraceExporter {
+	case TraceExporterCloudTrace, TraceExporterOTLP, TraceExporterStdout, TraceExporterNone:
+	default:
+		errs = append(errs, fmt.Errorf("TRACE_EXPORTER must be one of %q, %q, %q or %q, got %q",
+			TraceExporterCloudTrace, TraceExporterOTLP, TraceExporterStdout, TraceExporterNone, c.TraceExporter))
+	}
+	if c.ImportMaxWords <= 0 {
+		errs = append(errs, errors.New("IMPORT_MAX_WORDS must be positive"))
+	}
+	if c.ImportRateLimit <= 0 {
+		errs = append(errs, errors.New("IMPORT_RATE_LIMIT must be positive"))
+	}
+	if c.AskRateLimit <= 0 {
+		errs = append(errs, errors.New("ASK_RATE_LIMIT must be positive"))
+	}
+	switch c.JobsBackend {
+	case JobsBackendLocal:
+	case JobsBackendCloudTasks:
+		if !strings.HasPrefix(c.TasksQueue, "projects/") || !strings.Contains(c.TasksQueue, "/queues/") {
+			errs = append(errs, errors.New("TASKS_QUEUE must be a queue resource like projects/<project>/locations/<location>/queues/<queue>"))
+		}
+		if !strings.HasPrefix(c.TasksWorkerURL, "https://") {
+			errs = append(errs, errors.New("TASKS_WORKER_URL must be the https URL of the deployed function"))
+		}
+		if len(c.TasksWorkerToken) < minAdminTokenLength {
+			errs = append(errs, fmt.Errorf("TASKS_WORKER_TOKEN must be at least %d characters long", minAdminTokenLength))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("JOBS_BACKEND must be %q or %q, got %q", JobsBackendLocal, JobsBackendCloudTasks, c.JobsBackend))
+	}
+	switch c.Storage {
+	case StorageMemory, StorageFirestore:
+	case StorageRedis:
+		if !strings.HasPrefix(c.RedisURL, "redis://") && !strings.HasPrefix(c.RedisURL, "rediss://") {
+			errs = append(errs, errors.New("REDIS_URL must be a URL like redis://<host>:<port>/<db>"))
+		}
+	case StorageSQLite:
+		if c.SQLitePath == "" {
+			errs = append(errs, errors.New("SQLITE_PATH is required"))
+		}
+	case StoragePostgres:
+		if !strings.HasPrefix(c.PostgresURL, "postgres://") && !strings.HasPrefix(c.PostgresURL, "postgresql://") {
+			errs = append(errs, errors.New("POSTGRES_URL must be a URL like postgres://<user>:<password>@<host>:5432/<database>"))
+		}
+		if c.PostgresMaxConns <= 0 {
+			errs = append(errs, errors.New("POSTGRES_MAX_CONNS must be positive"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("STORAGE must be %q, %q, %q, %q or %q, got %q", StorageMemory, StorageFirestore, StorageRedis, StorageSQLite, StoragePostgres, c.Storage))
+	}
+	if c.DataRetention < 0 {
+		errs = append(errs, errors.New("DATA_RETENTION must not be negative"))
+	}
+	for key, rates := range map[string]map[string]string{"CHAOS_FAILURE_RATES": c.ChaosFailureRates, "CHAOS_DELAY_RATES": c.ChaosDelayRates} {
+		for target, rate := range rates {
+			if !chaosTargetPattern.MatchString(target) {
+				errs = append(errs, fmt.Errorf("%s target must be ai, cache or telegram, got %q", key, target))
+			}
+			if !chaosRatePattern.MatchString(rate) {
+				errs = append(errs, fmt.Errorf("%s rate of %s must be a percentage like 10%%, got %q", key, target, rate))
+			}
+		}
+	}
+	if c.ChaosDelay < 0 {
+		errs = append(errs, errors.New("CHAOS_DELAY must not be negative"))
+	}
+	if c.TelegramWebhookURL != "" && !strings.HasPrefix(c.TelegramWebhookURL, "https://") {
+		errs = append(errs, errors.New("TELEGRAM_WEBHOOK_URL must be an https:// URL, Telegram only delivers updates over HTTPS"))
 	}
+	errs = append(errs, c.validateTelegramBots()...)
+	errs = append(errs, c.validateTenants()...)
+	if c.TelegramToken != "" && c.TelegramTokenSecret != "" {
+		errs = append(errs, errors.New("TELEGRAM_BOT_TOKEN and TELEGRAM_BOT_TOKEN_SECRET are mutually exclusive"))
+	}
+	if c.AdminToken != "" && c.AdminTokenSecret != "" {
+		errs = append(errs, errors.New("ADMIN_TOKEN and ADMIN_TOKEN_SECRET are mutually exclusive"))
+	}
+	if c.SessionSigningKey != "" && c.SessionSigningKeySecret != "" {
+		errs = append(errs, errors.New("SESSION_SIGNING_KEY and SESSION_SIGNING_KEY_SECRET are mutually exclusive"))
+	}
+	if c.ResponseSigningKey != "" && c.ResponseSigningKeySecret != "" {
+		errs = append(errs, errors.New("RESPONSE_SIGNING_KEY and RESPONSE_SIGNING_KEY_SECRET are mutually exclusive"))
+	}
+	if c.SecretsCacheTTL < 0 {
+		errs = append(errs, errors.New("SECRETS_CACHE_TTL must not be negative"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateTelegramBots checks the white-label bots, each needs its own token and they are served with the main bot
+func (c *Config) validateTelegramBots() []error {
+	if len(c.TelegramBots) == 0 {
+		return nil
+	}
+
+	var errs []error
+	if c.TelegramToken == "" && c.TelegramTokenSecret == "" {
+		errs = append(errs, errors.New("telegramBots require the main bot, set TELEGRAM_BOT_TOKEN or TELEGRAM_BOT_TOKEN_SECRET"))
+	}
+	ids := make(map[string]bool, len(c.TelegramBots)+1)
+	if id, _, ok := strings.Cut(c.TelegramToken, ":"); ok {
+		ids[id] = true
+	}
+	for i, bot := range c.TelegramBots {
+		if !telegramTokenPattern.MatchString(bot.Token) {
+			errs = append(errs, fmt.Errorf("telegramBots[%d].token has an invalid format, expected <bot id>:<secret>", i))
+			continue
+		}
+		id, _, _ := strings.Cut(bot.Token, ":")
+		if ids[id] {
+			errs = append(errs, fmt.Errorf("telegramBots[%d] is bot %s, which is already served", i, id))
+		}
+		ids[id] = true
+		if len(bot.Welcome) > maxTelegramMessageLength {
+			errs = append(errs, fmt.Errorf("welcome of bot %s must be at most %d characters long", id, maxTelegramMessageLength))
+		}
+		if bot.Language != "" && !languagePattern.MatchString(bot.Language) {
+			errs = append(errs, fmt.Errorf("language of bot %s must be a two-letter code like de, got %q", id, bot.Language))
+		}
+	}
+
+	return errs
+}
+
+// validateTenants checks the tenants, their IDs and API keys must be unique
+func (c *Config) validateTenants() []error {
+	var errs []error
+	ids := make(map[string]bool, len(c.Tenants))
+	keys := make(map[string]string)
+	for i, tenant := range c.Tenants {
+		if !tenantIDPattern.MatchString(tenant.ID) {
+			errs = append(errs, fmt.Errorf("tenants[%d].id must be a lowercase identifier like school-berlin, got %q", i, tenant.ID))
+		}
+		if ids[tenant.ID] {
+			errs = append(errs, fmt.Errorf("tenants[%d].id %q is used by another tenant", i, tenant.ID))
+		}
+		ids[tenant.ID] = true
+		if len(tenant.APIKeys) == 0 {
+			errs = append(errs, fmt.Errorf("tenant %s must have at least one API key", tenant.ID))
+		}
+		for _, key := range tenant.APIKeys {
+			if len(key) < minAdminTokenLength {
+				errs = append(errs, fmt.Errorf("API keys of tenant %s must be at least %d characters long", tenant.ID, minAdminTokenLength))
+			}
+			if other, ok := keys[key]; ok {
+				errs = append(errs, fmt.Errorf("tenants %s and %s share an API key", other, tenant.ID))
+			}
+			keys[key] = tenant.ID
+		}
+		if tenant.DailyQuota < 0 {
+			errs = append(errs, fmt.Errorf("dailyQuota of tenant %s must not be negative", tenant.ID))
+		}
+		for _, topic := range tenant.ExampleTopics {
+			if strings.TrimSpace(topic) == "" || len(topic) > maxExampleTopicLength {
+				errs = append(errs, fmt.Errorf("exampleTopics of tenant %s must be non-empty and at most %d characters long", tenant.ID, maxExampleTopicLength))
+				break
+			}
+		}
+	}
+
+	return errs
+}
+
+// HasSecretReferences reports whether any value has to be read from the secrets provider
+func (c *Config) HasSecretReferences() bool {
+	return c.TelegramTokenSecret != "" || c.AdminTokenSecret != "" || c.ResponseSigningKeySecret != "" || c.SessionSigningKeySecret != ""
+}
+
+// ResolveSecrets reads the referenced secrets and validates the resulting configuration
+func (c *Config) ResolveSecrets(ctx context.Context, getter SecretGetter) error {
+	references := []struct {
+		name  string
+		field *string
+	}{
+		{c.TelegramTokenSecret, &c.TelegramToken},
+		{c.AdminTokenSecret, &c.AdminToken},
+		{c.ResponseSigningKeySecret, &c.ResponseSigningKey},
+		{c.SessionSigningKeySecret, &c.SessionSigningKey},
+	}
+
+	var errs []error
+	for _, ref := range references {
+		if ref.name == "" {
+			continue
+		}
+		value, err := getter.Get(ctx, ref.name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		*ref.field = value
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("failed to resolve secrets: %w", err)
+	}
+
+	// Secret values replace the references for validation purposes only
+	resolved := *c
+	resolved.TelegramTokenSecret, resolved.AdminTokenSecret = "", ""
+	resolved.ResponseSigningKeySecret, resolved.SessionSigningKeySecret = "", ""
+	if err := resolved.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return nil
+}
+
+// Diagnostics returns the effective configuration with secrets masked, for startup logging
+func (c *Config) Diagnostics() map[string]interface{} {
+	return map[string]interface{}{
+		"projectId":                c.ProjectID,
+		"applicationName":          c.ApplicationName,
+		"telegramToken":            mask(c.TelegramToken),
+		"telegramGroupsEnabled":    c.TelegramGroupsEnabled,
+		"telegramGroupLanguages":   c.TelegramGroupLanguages,
+		"telegramWebhookUrl":       c.TelegramWebhookURL,
+		"telegramBots":             c.telegramBotDiagnostics(),
+		"featureFlags":             c.FeatureFlags,
+		"featureFlagsCollection":   c.FeatureFlagsCollection,
+		"featureFlagsRefresh":      c.FeatureFlagsRefresh.String(),
+		"adminToken":               mask(c.AdminToken),
+		"aiProvider":               c.AIProvider,
+		"aiDailyQuota":             c.AIDailyQuota,
+		"aiVerificationModel":      c.AIVerificationModel,
+		"aiLenientParsing":         c.AILenientParsing,
+		"aiRepairAttempts":         c.AIRepairAttempts,
+		"aiSafetyThresholds":       c.AISafetyThresholds,
+		"aiSystemInstruction":      c.AISystemInstruction,
+		"aiArticleModel":           c.AIArticleModel,
+		"aiFullModel":              c.AIFullModel,
+		"aiTranslationModel":       c.AITranslationModel,
+		"aiGrammarModel":           c.AIGrammarModel,
+		"aiMonthlySpendCap":        c.AIMonthlySpendCap,
+		"aiCostPerCall":            c.AICostPerCall,
+		"aiMaxIdleConns":           c.AIMaxIdleConns,
+		"aiIdleConnTimeout":        c.AIIdleConnTimeout.String(),
+		"aiConnectTimeout":         c.AIConnectTimeout.String(),
+		"telegramAdminChatId":      c.TelegramAdminChatID,
+		"cacheTtl":                 c.CacheTTL.String(),
+		"cacheSize":                c.CacheSize,
+		"negativeCacheTtl":         c.NegativeCacheTTL.String(),
+		"httpCacheMaxAge":          c.HTTPCacheMaxAge.String(),
+		"httpRequestTimeout":       c.HTTPRequestTimeout.String(),
+		"httpMaxBodyBytes":         c.HTTPMaxBodyBytes,
+		"deadlineCacheBudget":      c.DeadlineCacheBudget.String(),
+		"deadlineDictionaryBudget": c.DeadlineDictionaryBudget.String(),
+		"deadlineReserve":          c.DeadlineReserve.String(),
+		"aiDegradationThreshold":   c.AIDegradationThreshold,
+		"aiDegradationCooldown":    c.AIDegradationCooldown.String(),
+		"idempotencyTtl":           c.IdempotencyTTL.String(),
+		"jobStatusTtl":             c.JobStatusTTL.String(),
+		"accountLinkCodeTtl":       c.AccountLinkCodeTTL.String(),
+		"googleClientIds":          c.GoogleClientIDs,
+		"firebaseProjectId":        c.FirebaseProjectID,
+		"sessionSigningKey":        mask(c.SessionSigningKey),
+		"sessionSigningSecret":     c.SessionSigningKeySecret,
+		"sessionTokenTtl":          c.SessionTokenTTL.String(),
+		"corsAllowedOrigins":       c.CORSAllowedOrigins,
+		"corsAllowCredentials":     c.CORSAllowCredentials,
+		"corsMaxAge":               c.CORSMaxAge.String(),
+		"followUpTtl":              c.FollowUpTTL.String(),
+		"gcpEnabled":               c.GCPEnabled,
+		"logLevel":                 c.LogLevel,
+		"logFormat":                c.LogFormat,
+		"logSampleRate":            c.LogSampleRate,
+		"traceExporter":            c.TraceExporter,
+		"importMaxWords":           c.ImportMaxWords,
+		"importRateLimit":          c.ImportRateLimit,
+		"askRateLimit":             c.AskRateLimit,
+		"jobsBackend":              c.JobsBackend,
+		"alexaSkillId":             c.AlexaSkillID,
+		"tasksQueue":               c.TasksQueue,
+		"tasksWorkerUrl":           c.TasksWorkerURL,
+		"tasksWorkerToken":         mask(c.TasksWorkerToken),
+		"storage":                  c.Storage,
+		"redisUrl":                 mask(c.RedisURL),
+		"sqlitePath":               c.SQLitePath,
+		"postgresUrl":              mask(c.PostgresURL),
+		"postgresMaxConns":         c.PostgresMaxConns,
+		"storageMigrate":           c.StorageMigrate,
+		"dataRetention":            c.DataRetention.String(),
+		"tenants":                  c.tenantDiagnostics(),
+		"webhookSigningSecret":     mask(c.WebhookSigningSecret),
+		"webhookAllowPrivate":      c.WebhookAllowPrivate,
+		"responseSigningAlgorithm": c.ResponseSigningAlgorithm,
+		"responseSigningKey":       mask(c.ResponseSigningKey),
+		"responseSigningKeyId":     c.ResponseSigningKeyID,
+		"responseSigningSecret":    c.ResponseSigningKeySecret,
+		"telegramSecret":           c.TelegramTokenSecret,
+		"adminSecret":              c.AdminTokenSecret,
+		"secretsCacheTtl":          c.SecretsCacheTTL.String(),
+		"chaosEnabled":             c.ChaosEnabled,
+		"chaosFailureRates":        c.ChaosFailureRates,
+		"chaosDelayRates":          c.ChaosDelayRates,
+		"chaosDelay":               c.ChaosDelay.String(),
+	}
+}
+
+// telegramBotDiagnostics returns the white-label bots with their IDs instead of their tokens
+func (c *Config) telegramBotDiagnostics() []map[string]interface{} {
+	bots := make([]map[string]interface{}, 0, len(c.TelegramBots))
+	for _, bot := range c.TelegramBots {
+		id, _, _ := strings.Cut(bot.Token, ":")
+		bots = append(bots, map[string]interface{}{
+			"id":       id,
+			"welcome":  bot.Welcome != "",
+			"language": bot.Language,
+		})
+	}
+
+	return bots
+}
+
+// tenantDiagnostics returns the tenants with the number of their API keys instead of the keys
+func (c *Config) tenantDiagnostics() []map[string]interface{} {
+	tenants := make([]map[string]interface{}, 0, len(c.Tenants))
+	for _, tenant := range c.Tenants {
+		tenants = append(tenants, map[string]interface{}{
+			"id":            tenant.ID,
+			"name":          tenant.Name,
+			"apiKeys":       len(tenant.APIKeys),
+			"dailyQuota":    tenant.DailyQuota,
+			"exampleTopics": tenant.ExampleTopics,
+		})
+	}
+
+	return tenants
+}
+
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, c)
+	case ".json":
+		err = json.Unmarshal(data, c)
+	default:
+		return fmt.Errorf("unsupported config file format %q, expected .yaml, .yml or .json", filepath.Ext(path))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+func (c *Config) loadEnv() error {
+	var errs []error
+
+	setString(&c.ProjectID, "PROJECT_ID")
+	setString(&c.ApplicationName, "APPLICATION_NAME")
+	setString(&c.TelegramToken, "TELEGRAM_BOT_TOKEN")
+	setString(&c.AdminToken, "ADMIN_TOKEN")
+	setString(&c.AIProvider, "AI_PROVIDER")
+	setString(&c.AIVerificationModel, "AI_VERIFICATION_MODEL")
+	setString(&c.AIArticleModel, "AI_ARTICLE_MODEL")
+	setString(&c.AIFullModel, "AI_FULL_MODEL")
+	setString(&c.AITranslationModel, "AI_TRANSLATION_MODEL")
+	setString(&c.AIGrammarModel, "AI_GRAMMAR_MODEL")
+	setString(&c.TelegramTokenSecret, "TELEGRAM_BOT_TOKEN_SECRET")
+	setString(&c.TelegramWebhookURL, "TELEGRAM_WEBHOOK_URL")
+	setString(&c.AdminTokenSecret, "ADMIN_TOKEN_SECRET")
+	setString(&c.JobsBackend, "JOBS_BACKEND")
+	setString(&c.AlexaSkillID, "ALEXA_SKILL_ID")
+	setString(&c.TasksQueue, "TASKS_QUEUE")
+	setString(&c.TasksWorkerURL, "TASKS_WORKER_URL")
+	setString(&c.TasksWorkerToken, "TASKS_WORKER_TOKEN")
+	setString(&c.Storage, "STORAGE")
+	setString(&c.RedisURL, "REDIS_URL")
+	setString(&c.SQLitePath, "SQLITE_PATH")
+	setString(&c.PostgresURL, "POSTGRES_URL")
+	errs = append(errs, setInt(&c.PostgresMaxConns, "POSTGRES_MAX_CONNS"))
+	errs = append(errs, setBool(&c.StorageMigrate, "STORAGE_MIGRATE"))
+	errs = append(errs, setDuration(&c.DataRetention, "DATA_RETENTION"))
+	errs = append(errs, setBool(&c.ChaosEnabled, "CHAOS_ENABLED"))
+	errs = append(errs, setPairs(&c.ChaosFailureRates, "CHAOS_FAILURE_RATES", "<target>=<rate>"))
+	errs = append(errs, setPairs(&c.ChaosDelayRates, "CHAOS_DELAY_RATES", "<target>=<rate>"))
+	errs = append(errs, setDuration(&c.ChaosDelay, "CHAOS_DELAY"))
+	setString(&c.WebhookSigningSecret, "WEBHOOK_SIGNING_SECRET")
+	setString(&c.ResponseSigningAlgorithm, "RESPONSE_SIGNING_ALGORITHM")
+	setString(&c.ResponseSigningKey, "RESPONSE_SIGNING_KEY")
+	setString(&c.ResponseSigningKeyID, "RESPONSE_SIGNING_KEY_ID")
+	setString(&c.ResponseSigningKeySecret, "RESPONSE_SIGNING_KEY_SECRET")
+	errs = append(errs, setBool(&c.WebhookAllowPrivate, "WEBHOOK_ALLOW_PRIVATE"))
+	errs = append(errs, setDuration(&c.SecretsCacheTTL, "SECRETS_CACHE_TTL"))
+	errs = append(errs, setInt64(&c.AIDailyQuota, "AI_DAILY_QUOTA"))
+	errs = append(errs, setFloat(&c.AIMonthlySpendCap, "AI_MONTHLY_SPEND_CAP"))
+	errs = append(errs, setFloat(&c.AICostPerCall, "AI_COST_PER_CALL"))
+	errs = append(errs, setInt64(&c.TelegramAdminChatID, "TELEGRAM_ADMIN_CHAT_ID"))
+	errs = append(errs, setDuration(&c.CacheTTL, "CACHE_TTL"))
+	errs = append(errs, setInt(&c.CacheSize, "CACHE_SIZE"))
+	errs = append(errs, setDuration(&c.NegativeCacheTTL, "NEGATIVE_CACHE_TTL"))
+	errs = append(errs, setDuration(&c.HTTPCacheMaxAge, "HTTP_CACHE_MAX_AGE"))
+	errs = append(errs, setDuration(&c.HTTPRequestTimeout, "HTTP_REQUEST_TIMEOUT"))
+	errs = append(errs, setInt64(&c.HTTPMaxBodyBytes, "HTTP_MAX_BODY_BYTES"))
+	errs = append(errs, setDuration(&c.DeadlineCacheBudget, "DEADLINE_CACHE_BUDGET"))
+	errs = append(errs, setDuration(&c.DeadlineDictionaryBudget, "DEADLINE_DICTIONARY_BUDGET"))
+	errs = append(errs, setDuration(&c.DeadlineReserve, "DEADLINE_RESERVE"))
+	errs = append(errs, setInt(&c.AIDegradationThreshold, "AI_DEGRADATION_THRESHOLD"))
+	errs = append(errs, setDuration(&c.AIDegradationCooldown, "AI_DEGRADATION_COOLDOWN"))
+	errs = append(errs, setDuration(&c.IdempotencyTTL, "IDEMPOTENCY_TTL"))
+	errs = append(errs, setDuration(&c.JobStatusTTL, "JOB_STATUS_TTL"))
+	errs = append(errs, setDuration(&c.AccountLinkCodeTTL, "ACCOUNT_LINK_CODE_TTL"))
+	setList(&c.CORSAllowedOrigins, "CORS_ALLOWED_ORIGINS")
+	setList(&c.GoogleClientIDs, "GOOGLE_CLIENT_IDS")
+	setString(&c.FirebaseProjectID, "FIREBASE_PROJECT_ID")
+	setString(&c.SessionSigningKey, "SESSION_SIGNING_KEY")
+	setString(&c.SessionSigningKeySecret, "SESSION_SIGNING_KEY_SECRET")
+	errs = append(errs, setDuration(&c.SessionTokenTTL, "SESSION_TOKEN_TTL"))
+	errs = append(errs, setBool(&c.CORSAllowCredentials, "CORS_ALLOW_CREDENTIALS"))
+	errs = append(errs, setDuration(&c.CORSMaxAge, "CORS_MAX_AGE"))
+	errs = append(errs, setDuration(&c.FollowUpTTL, "FOLLOW_UP_TTL"))
+	errs = append(errs, setBool(&c.GCPEnabled, "GCP_ENABLED"))
+	errs = append(errs, setBool(&c.AILenientParsing, "AI_LENIENT_PARSING"))
+	errs = append(errs, setInt(&c.AIRepairAttempts, "AI_REPAIR_ATTEMPTS"))
+	errs = append(errs, setPairs(&c.AISafetyThresholds, "AI_SAFETY_THRESHOLDS", "<category>=<threshold>"))
+	setString(&c.AISystemInstruction, "AI_SYSTEM_INSTRUCTION")
+	errs = append(errs, setInt(&c.AIMaxIdleConns, "AI_MAX_IDLE_CONNS"))
+	errs = append(errs, setDuration(&c.AIIdleConnTimeout, "AI_IDLE_CONN_TIMEOUT"))
+	errs = append(errs, setDuration(&c.AIConnectTimeout, "AI_CONNECT_TIMEOUT"))
+	errs = append(errs, setBool(&c.TelegramGroupsEnabled, "TELEGRAM_GROUPS_ENABLED"))
+	errs = append(errs, setLanguageMap(&c.TelegramGroupLanguages, "TELEGRAM_GROUP_LANGUAGES"))
+	errs = append(errs, setPairs(&c.FeatureFlags, "FEATURE_FLAGS", "<feature>=<rollout>"))
+	setString(&c.FeatureFlagsCollection, "FEATURE_FLAGS_COLLECTION")
+	errs = append(errs, setDuration(&c.FeatureFlagsRefresh, "FEATURE_FLAGS_REFRESH"))
+	errs = append(errs, setLogLevel(&c.LogLevel, "LOG_LEVEL"))
+	setString(&c.LogFormat, "LOG_FORMAT")
+	setString(&c.TraceExporter, "TRACE_EXPORTER")
+	errs = append(errs, setFloat(&c.LogSampleRate, "LOG_SAMPLE_RATE"))
+	errs = append(errs, setInt(&c.ImportMaxWords, "IMPORT_MAX_WORDS"))
+	errs = append(errs, setFloat(&c.ImportRateLimit, "IMPORT_RATE_LIMIT"))
+	errs = append(errs, setInt(&c.AskRateLimit, "ASK_RATE_LIMIT"))
+
+	return errors.Join(errs...)
 }
 
-func getEnv(key, defaultValue string) string {
+func setString(field *string, key string) {
 	if value := os.Getenv(key); value != "" {
-		return value
+		*field = value
+	}
+}
+
+// setList parses a comma separated list, empty items are dropped
+func setList(field *[]string, key string) {
+	value := os.Getenv(key)
+	if value == "" {
+		return
+	}
+
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
 	}
-	return defaultValue
+	*field = items
+}
+
+func setInt64(field *int64, key string) error {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%s must be an integer, got %q", key, value)
+	}
+	*field = parsed
+
+	return nil
+}
+
+func setInt(field *int, key string) error {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("%s must be an integer, got %q", key, value)
+	}
+	*field = parsed
+
+	return nil
+}
+
+func setFloat(field *float64, key string) error {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fmt.Errorf("%s must be a number, got %q", key, value)
+	}
+	*field = parsed
+
+	return nil
+}
+
+func setBool(field *bool, key string) error {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return fmt.Errorf("%s must be a boolean, got %q", key, value)
+	}
+	*field = parsed
+
+	return nil
+}
+
+func setDuration(field *time.Duration, key string) error {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("%s must be a duration like 5m, got %q", key, value)
+	}
+	*field = parsed
+
+	return nil
+}
+
+// setLogLevel parses a numeric severity or a severity name like "debug" or "warning"
+func setLogLevel(field *int, key string) error {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+
+	if parsed, err := strconv.Atoi(value); err == nil {
+		*field = parsed
+		return nil
+	}
+
+	// Unknown names are parsed as the Default severity
+	severity := logging.ParseSeverity(value)
+	if severity == logging.Default && !strings.EqualFold(value, "default") {
+		return fmt.Errorf("%s must be a severity like info or warning or its number, got %q", key, value)
+	}
+	*field = int(severity)
+
+	return nil
+}
+
+// setLanguageMap parses "<chat id>:<language>" pairs separated by commas
+func setLanguageMap(field *map[int64]string, key string) error {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+
+	languages := make(map[int64]string)
+	for _, pair := range strings.Split(value, ",") {
+		id, language, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		chatID, err := strconv.ParseInt(id, 10, 64)
+		if !ok || err != nil || language == "" {
+			return fmt.Errorf("%s must be a list of <chat id>:<language> pairs, got %q", key, pair)
+		}
+		languages[chatID] = language
+	}
+	*field = languages
+
+	return nil
+}
+
+// setPairs adds the <name>=<value> pairs of the variable to the map, like the rollouts of features, the
+// other names keep their values. The format names the pairs in the error.
+func setPairs(field *map[string]string, key, format string) error {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+
+	pairs := make(map[string]string, len(*field))
+	for name, value := range *field {
+		pairs[name] = value
+	}
+	for _, pair := range strings.Split(value, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return fmt.Errorf("%s must be a list of %s pairs, got %q", key, format, pair)
+		}
+		pairs[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+	*field = pairs
+
+	return nil
+}
+
+func mask(secret string) string {
+	if secret == "" {
+		return ""
+	}
+
+	return "***"
 }
@@ -0,0 +1,54 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/repositories"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/services"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/chaos"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/config"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"net/http"
+	"time"
+)
+
+// telegramClientTimeout is the timeout of the Bot API requests, the one of the default telebot client
+const telegramClientTimeout = time.Minute
+
+// faultInjection holds the dependencies of the lookups with the faults of the configuration injected
+type faultInjection struct {
+	ai    services.AIService
+	cache repositories.CacheRepository
+	// telegram sends the requests of the bots, nil for the default client
+	telegram *http.Client
+}
+
+// injectFaults wraps the AI service, the cache and the Telegram client of the targets with fault rates,
+// they are returned as they are unless the fault injection is enabled
+func injectFaults(ctx context.Context, cfg *config.Config, ai services.AIService, cache repositories.CacheRepository, l logging.Logger) (faultInjection, error) {
+	injection := faultInjection{ai: ai, cache: cache}
+	if !cfg.ChaosEnabled {
+		return injection, nil
+	}
+
+	faults, err := chaos.ParseFaults(cfg.ChaosFailureRates, cfg.ChaosDelayRates, cfg.ChaosDelay)
+	if err != nil {
+		return injection, fmt.Errorf("invalid fault injection: %w", err)
+	}
+	if target, ok := faults[chaos.TargetAI]; ok {
+		injection.ai = chaos.WrapAIService(ai, chaos.NewInjector(chaos.TargetAI, target, l))
+	}
+	if target, ok := faults[chaos.TargetCache]; ok {
+		injection.cache = chaos.NewCacheRepository(cache, chaos.NewInjector(chaos.TargetCache, target, l))
+	}
+	if target, ok := faults[chaos.TargetTelegram]; ok {
+		injection.telegram = &http.Client{
+			Timeout:   telegramClientTimeout,
+			Transport: chaos.NewTransport(http.DefaultTransport, chaos.NewInjector(chaos.TargetTelegram, target, l)),
+		}
+	}
+	l.With(ctx).Field("failureRates", cfg.ChaosFailureRates).Field("delayRates", cfg.ChaosDelayRates).
+		Field("delay", cfg.ChaosDelay.String()).Warning("Fault injection is enabled")
+
+	return injection, nil
+}
@@ -1,97 +1,540 @@
 package container
 
 import (
+	cloudlogging "cloud.google.com/go/logging"
 	"context"
 	"fmt"
 	"github.com/DeryabinSergey/germanarticlebot/internal/adapters/console"
+	"github.com/DeryabinSergey/germanarticlebot/internal/adapters/graphql"
 	"github.com/DeryabinSergey/germanarticlebot/internal/adapters/http/handlers"
+	"github.com/DeryabinSergey/germanarticlebot/internal/adapters/mcp"
 	"github.com/DeryabinSergey/germanarticlebot/internal/adapters/telegram"
 	"github.com/DeryabinSergey/germanarticlebot/internal/application/usecases"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/repositories"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/services"
 	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/ai"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/auth"
 	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/config"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/dictionary"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/featureflags"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/frequency"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/health"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/jobs"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/moderation"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/secrets"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/signing"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/webhook"
 	"github.com/DeryabinSergey/germanarticlebot/libs/logger"
 	"github.com/DeryabinSergey/germanarticlebot/libs/tracer"
 	"google.golang.org/genai"
+	"os"
+	"time"
 )
 
+// maxFeedbackEntries bounds the feedback kept in memory and Redis
+const maxFeedbackEntries = 10000
+
+// maxIdempotencyKeys bounds the in-memory responses kept for retried requests
+const maxIdempotencyKeys = 10000
+
+// maxJobStatuses bounds the job states kept in memory for polling clients
+const maxJobStatuses = 10000
+
+// maxLinkCodes bounds the in-memory link codes waiting to be exchanged for API tokens
+const maxLinkCodes = 10000
+
+// maxActivityUsers bounds the users whose learning activity is kept in memory
+const maxActivityUsers = 10000
+
+// maxReplies bounds the answers kept in memory to be updated when their message is edited
+const maxReplies = 10000
+
+// replyTTL is how long the storage backends keep the answers to be updated when their message is edited
+const replyTTL = 7 * 24 * time.Hour
+
+// maxDeadLetters bounds the failed Telegram updates kept in memory for a replay
+const maxDeadLetters = 1000
+
 // Container holds all application dependencies
 type Container struct {
 	Config         *config.Config
-	Logger         *logger.Log
+	Logger         logging.Logger
 	Tracer         *tracer.Tracer
+	Secrets        secrets.Provider
 	GeminiClient   *genai.Client
-	AIService      *ai.GeminiService
+	AIService      services.AIService
+	Verifier       services.AIService
+	Dictionary     services.DictionaryService
+	Stats          repositories.StatsRepository
+	Cache          repositories.CacheRepository
+	Feedback       repositories.FeedbackRepository
 	UseCase        *usecases.DetermineArticleUseCase
+	DashboardCase  *usecases.AdminDashboardUseCase
+	CORS           *handlers.CORS
+	Idempotency    *handlers.Idempotency
+	Signing        *handlers.ResponseSigning
+	Authentication *handlers.Authentication
+	Tenancy        *handlers.Tenancy
+	TenantHandler  *handlers.TenantHandler
+	AuthHandler    *handlers.AuthHandler
+	MeHandler      *handlers.MeHandler
 	HTTPHandler    *handlers.ArticleHandler
+	AdminHandler   *handlers.AdminHandler
+	WorkerHandler  *handlers.WorkerHandler
+	Jobs           services.JobQueue
+	JobsCase       *usecases.ProcessJobUseCase
+	JobHandler     *handlers.JobHandler
+	ImportHandler  *handlers.ImportHandler
+	AsyncHandler   *handlers.AsyncHandler
+	EmbedHandler   *handlers.EmbedHandler
+	AskHandler     *handlers.AskHandler
+	WebSocket      *handlers.WebSocketHandler
+	SSEHandler     *handlers.SSEHandler
+	VoiceHandler   *handlers.VoiceHandler
+	Health         *health.Service
+	HealthHandler  *handlers.HealthHandler
 	TelegramBot    *telegram.BotHandler
+	TelegramBots   *telegram.Bots
 	ConsoleHandler *console.Handler
+	MCPServer      *mcp.Server
+	GraphQL        *graphql.Server
 }
 
 // NewContainer creates and initializes the dependency injection container
 func NewContainer(ctx context.Context) (*Container, error) {
-	cfg := config.LoadConfig()
-
-	// Initialize logger
-	l, err := logger.Init(ctx, cfg.ProjectID, cfg.ApplicationName, cfg.GCPEnabled, 100)
+	cfg, err := config.LoadConfig()
 	if err != nil {
-		return nil, fmt.Errorf("failed to initialize logger: %w", err)
+		return nil, err
+	}
+
+	// Initialize logger, entries go to Cloud Logging on GCP and to slog on stdout otherwise
+	var l logging.Logger
+	if cfg.GCPEnabled {
+		cloudLogger, err := logger.Init(ctx, cfg.ProjectID, cfg.ApplicationName, true, cloudlogging.Severity(cfg.LogLevel))
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize logger: %w", err)
+		}
+		cloudLogger.SetSampleRate(cfg.LogSampleRate)
+		l = cloudLogger
+	} else {
+		slogLogger := logger.NewSlog(os.Stdout, cloudlogging.Severity(cfg.LogLevel), cfg.LogFormat == config.LogFormatJSON)
+		slogLogger.SetSampleRate(cfg.LogSampleRate)
+		l = slogLogger
+	}
+
+	// Initialize secrets provider (only if any value is referenced by secret name)
+	var secretsProvider secrets.Provider
+	adminToken := secrets.StaticSource(cfg.AdminToken)
+	signingKey := secrets.StaticSource(cfg.ResponseSigningKey)
+	sessionKey := secrets.StaticSource(cfg.SessionSigningKey)
+	if cfg.HasSecretReferences() {
+		sm, err := secrets.NewGoogleSecretManager(ctx, cfg.ProjectID)
+		if err != nil {
+			l.With(ctx).Err(err).Critical("Failed to initialize secrets provider")
+			return nil, fmt.Errorf("failed to initialize secrets provider: %w", err)
+		}
+		secretsProvider = secrets.NewCachingProvider(sm, cfg.SecretsCacheTTL)
+
+		if err := cfg.ResolveSecrets(ctx, secretsProvider); err != nil {
+			l.With(ctx).Err(err).Critical("Failed to resolve secrets")
+			return nil, err
+		}
+		// The admin token is read on every request so a rotated version is picked up after the cache TTL
+		if cfg.AdminTokenSecret != "" {
+			adminToken = secrets.ProviderSource(secretsProvider, cfg.AdminTokenSecret)
+		}
+		if cfg.ResponseSigningKeySecret != "" {
+			signingKey = secrets.ProviderSource(secretsProvider, cfg.ResponseSigningKeySecret)
+		}
+		if cfg.SessionSigningKeySecret != "" {
+			sessionKey = secrets.ProviderSource(secretsProvider, cfg.SessionSigningKeySecret)
+		}
 	}
 
+	l.With(ctx).Field("config", cfg.Diagnostics()).Notice("Configuration loaded")
+
 	// Initialize tracer
-	tr, err := tracer.Init(ctx, cfg.ProjectID, cfg.ApplicationName, cfg.GCPEnabled)
+	tr, err := tracer.Init(ctx, cfg.ProjectID, cfg.ApplicationName, cfg.TraceExporter)
 	if err != nil {
-		l.Critical(ctx, map[string]interface{}{
-			"message": "failed to initialize tracer",
-			"error":   err.Error(),
-		})
+		l.With(ctx).Err(err).Critical("Failed to initialize tracer")
 		return nil, fmt.Errorf("failed to initialize tracer: %w", err)
 	}
 
-	// Initialize Gemini client
-	geminiClient, err := genai.NewClient(ctx, &genai.ClientConfig{
-		HTTPOptions: genai.HTTPOptions{APIVersion: "v1"},
-		Backend:     genai.BackendVertexAI,
-		Project:     cfg.ProjectID,
-		Location:    "global",
-	})
-	if err != nil {
-		l.Critical(ctx, map[string]interface{}{
-			"message": "failed to create Gemini client",
-			"error":   err.Error(),
+	// Initialize AI service
+	var (
+		geminiClient *genai.Client
+		aiService    services.AIService
+		verifier     services.AIService
+		tutor        services.TutorService
+		translator   services.TranslationService
+	)
+	healthService := health.NewService(0)
+	switch cfg.AIProvider {
+	case config.AIProviderMock:
+		mockService, err := ai.NewMockAIService(l)
+		if err != nil {
+			l.With(ctx).Err(err).Critical("Failed to create mock AI service")
+			return nil, fmt.Errorf("failed to create mock AI service: %w", err)
+		}
+		aiService, tutor, translator = mockService, mockService, mockService
+		// Fixtures are the only source of answers, so they give the second opinion as well
+		verifier = aiService
+		l.With(ctx).Warning("AI provider is mocked, answers come from fixtures")
+
+	default:
+		// The client lives in the container, so warm instances reuse its pooled connections
+		transport, err := ai.NewTransport(ai.TransportOptions{
+			MaxIdleConns:    cfg.AIMaxIdleConns,
+			IdleConnTimeout: cfg.AIIdleConnTimeout,
+			ConnectTimeout:  cfg.AIConnectTimeout,
+		})
+		if err != nil {
+			l.With(ctx).Err(err).Critical("Failed to create Gemini transport")
+			return nil, fmt.Errorf("failed to create Gemini transport: %w", err)
+		}
+		httpClient, err := ai.NewVertexHTTPClient(ctx, transport)
+		if err != nil {
+			l.With(ctx).Err(err).Critical("Failed to create Gemini HTTP client")
+			return nil, fmt.Errorf("failed to create Gemini HTTP client: %w", err)
+		}
+		geminiClient, err = genai.NewClient(ctx, &genai.ClientConfig{
+			HTTPOptions: genai.HTTPOptions{APIVersion: "v1", BaseURL: ai.VertexBaseURL},
+			Backend:     genai.BackendVertexAI,
+			Project:     cfg.ProjectID,
+			Location:    "global",
+			HTTPClient:  httpClient,
+		})
+		if err != nil {
+			l.With(ctx).Err(err).Critical("Failed to create Gemini client")
+			return nil, fmt.Errorf("failed to create Gemini client: %w", err)
+		}
+		router := ai.NewKindRouter(ai.DefaultModel, map[entities.RequestKind]string{
+			entities.RequestKindArticle:     cfg.AIArticleModel,
+			entities.RequestKindFull:        cfg.AIFullModel,
+			entities.RequestKindTranslation: cfg.AITranslationModel,
+			entities.RequestKindGrammar:     cfg.AIGrammarModel,
 		})
-		return nil, fmt.Errorf("failed to create Gemini client: %w", err)
+		safety, err := ai.SafetySettings(cfg.AISafetyThresholds)
+		if err != nil {
+			l.With(ctx).Err(err).Critical("Invalid Gemini safety thresholds")
+			return nil, fmt.Errorf("invalid Gemini safety thresholds: %w", err)
+		}
+		geminiService := ai.NewGeminiService(geminiClient, router, l, tr)
+		geminiService.SetLenientParsing(cfg.AILenientParsing)
+		geminiService.SetRepairAttempts(cfg.AIRepairAttempts)
+		geminiService.SetSafetySettings(safety)
+		geminiService.SetSystemInstruction(cfg.AISystemInstruction)
+		aiService, tutor, translator = geminiService, geminiService, geminiService
+		verifierService := ai.NewGeminiService(geminiClient, ai.FixedModel(cfg.AIVerificationModel), l, tr)
+		verifierService.SetSafetySettings(safety)
+		verifierService.SetSystemInstruction(cfg.AISystemInstruction)
+		verifier = verifierService
+		healthService.Register(ai.NewGeminiHealthChecker(geminiClient))
+		// The first lookup of a cold instance reuses the connection and the access token of the warm-up
+		go func() {
+			if err := ai.WarmUp(context.WithoutCancel(ctx), httpClient, ai.VertexBaseURL); err != nil {
+				l.With(ctx).Err(err).Warning("Failed to warm up Gemini connection")
+			}
+		}()
+	}
+
+	embedded, err := dictionary.NewEmbeddedDictionary()
+	if err != nil {
+		l.With(ctx).Err(err).Critical("Failed to load dictionary")
+		return nil, fmt.Errorf("failed to load dictionary: %w", err)
+	}
+
+	frequencyList, err := frequency.NewEmbeddedFrequencyList()
+	if err != nil {
+		l.With(ctx).Err(err).Critical("Failed to load frequency list")
+		return nil, fmt.Errorf("failed to load frequency list: %w", err)
+	}
+
+	moderator, err := moderation.NewEmbeddedBlocklist()
+	if err != nil {
+		l.With(ctx).Err(err).Critical("Failed to load moderation blocklist")
+		return nil, fmt.Errorf("failed to load moderation blocklist: %w", err)
 	}
 
 	// Initialize services
-	aiService := ai.NewGeminiService(geminiClient, l, tr)
-	useCase := usecases.NewDetermineArticleUseCase(aiService, l, tr)
+	store, err := openStorage(ctx, cfg, l)
+	if err != nil {
+		l.With(ctx).Field("storage", cfg.Storage).Err(err).Critical("Failed to open storage")
+		return nil, fmt.Errorf("failed to open storage: %w", err)
+	}
+	if store.health != nil {
+		healthService.Register(store.health)
+	}
+	stats := store.stats
+	budget := usecases.NewBudgetGuard(stats, cfg.AIMonthlySpendCap, l)
+	// Lookups of tenants are counted into their own statistics as well
+	tenantsCase := newTenantUseCase(cfg, l, tr)
+	lookupStats := tenantsCase.TrackStats(stats)
+	// Staging deployments exercise the retries and the degradation with injected faults
+	faults, err := injectFaults(ctx, cfg, aiService, store.cache, l)
+	if err != nil {
+		l.With(ctx).Err(err).Critical("Failed to set up fault injection")
+		return nil, err
+	}
+	aiService = faults.ai
+	cache := faults.cache
+	// The curated nouns imported by the operators win over the embedded word list and the AI answers
+	dict := dictionary.NewCuratedDictionary(embedded, store.dictionary)
+	useCase := usecases.NewDetermineArticleUseCase(aiService, frequencyList, dict, cache, cfg.CacheTTL, lookupStats, budget, l, tr)
+	useCase.SetNegativeCacheTTL(cfg.NegativeCacheTTL)
+	useCase.SetCuratedEntries(store.dictionary)
+	useCase.SetModerator(moderator)
+	useCase.SetDeadlineBudget(usecases.NewDeadlineBudget(cfg.DeadlineCacheBudget, cfg.DeadlineDictionaryBudget, cfg.DeadlineReserve))
+	aiDegradation := usecases.NewDegradation("ai", cfg.AIDegradationThreshold, cfg.AIDegradationCooldown, l)
+	useCase.SetDegradation(aiDegradation)
+	activity := store.activity
+	useCase.SetActivity(activity)
+	learningCase := usecases.NewLearningStatsUseCase(activity, l, tr)
+	searchCase := usecases.NewSearchHistoryUseCase(activity, l, tr)
+	achievements := store.achievements
+	achievementsCase := usecases.NewAchievementsUseCase(achievements, activity, dict, l, tr)
+	useCase.SetEventHandler(achievementsCase.Handle)
+	dashboardCase := usecases.NewAdminDashboardUseCase(stats, cfg.AIDailyQuota, budget, l, tr)
+	feedback := store.feedback
+	preferences := store.preferences
+	timezoneCase := usecases.NewTimezoneUseCase(preferences, store.reminders, l, tr)
+	useCase.SetTimezones(timezoneCase)
+	learningCase.SetTimezones(timezoneCase)
+	submitFeedbackCase := usecases.NewSubmitFeedbackUseCase(feedback, cache, l, tr)
+	listFeedbackCase := usecases.NewListFeedbackUseCase(feedback, l, tr)
+	listWordsCase := usecases.NewListWordsUseCase(stats, l, tr)
+	purgeCase := usecases.NewPurgeCacheUseCase(cache, l, tr)
+	importDictionaryCase := usecases.NewImportDictionaryUseCase(store.dictionary, purgeCase, l, tr)
+	verifyCase := usecases.NewVerifyArticleUseCase(verifier, dict, cache, cfg.CacheTTL, l, tr)
+	conversations := store.conversations
+	followUpCase := usecases.NewFollowUpUseCase(useCase, conversations, cfg.FollowUpTTL, l, tr)
+	askCase := usecases.NewAskGrammarUseCase(tutor, lookupStats, cfg.AskRateLimit, l, tr)
+	accounts := store.accounts
+	linkCase := usecases.NewLinkAccountUseCase(accounts, cfg.AccountLinkCodeTTL, l, tr)
+	var verifiers []services.IdentityVerifier
+	var sessionCase *usecases.IssueSessionUseCase
+	if cfg.SessionSigningKey != "" {
+		sessions := auth.NewSessionTokens(cfg.ApplicationName, sessionKey, cfg.SessionTokenTTL)
+		sessionCase = usecases.NewIssueSessionUseCase(sessions, l, tr)
+		verifiers = append(verifiers, sessions)
+	}
+	if len(cfg.GoogleClientIDs) > 0 {
+		verifiers = append(verifiers, auth.NewGoogleVerifier(cfg.GoogleClientIDs))
+	}
+	if cfg.FirebaseProjectID != "" {
+		verifiers = append(verifiers, auth.NewFirebaseVerifier(cfg.FirebaseProjectID))
+	}
+	linkCase.SetVerifiers(verifiers...)
+	translateCase := usecases.NewTranslateWordUseCase(translator, useCase, lookupStats, l, tr)
+	streamCase := usecases.NewStreamArticleUseCase(aiService, frequencyList, dict, cache, cfg.CacheTTL, lookupStats, budget, l, tr)
+	streamCase.SetNegativeCacheTTL(cfg.NegativeCacheTTL)
+	streamCase.SetCuratedEntries(store.dictionary)
+	streamCase.SetModerator(moderator)
+	streamCase.SetDegradation(aiDegradation)
+	importCase := usecases.NewImportVocabularyUseCase(useCase, cfg.ImportRateLimit, cfg.ImportMaxWords, l, tr)
+
+	// Initialize background jobs, handlers are registered by the adapters processing them
+	jobsCase := usecases.NewProcessJobUseCase(l, tr)
+	var jobQueue services.JobQueue
+	switch cfg.JobsBackend {
+	case config.JobsBackendCloudTasks:
+		jobQueue, err = jobs.NewCloudTasksQueue(ctx, cfg.TasksQueue, cfg.TasksWorkerURL, cfg.TasksWorkerToken)
+		if err != nil {
+			l.With(ctx).Err(err).Critical("Failed to create job queue")
+			return nil, fmt.Errorf("failed to create job queue: %w", err)
+		}
+	default:
+		jobQueue = jobs.NewLocalQueue(jobsCase.Execute, l)
+	}
+	// Job states are kept for clients polling them, every enqueued job starts as queued
+	jobStore := store.jobs
+	jobsCase.SetStore(jobStore, cfg.JobStatusTTL)
+	jobQueue = usecases.NewTrackedJobQueue(jobQueue, jobStore, cfg.JobStatusTTL, l)
+
+	// Cache warmups share the lookup rate of imports, both run the AI in the background
+	warmCase := usecases.NewWarmCacheUseCase(useCase, frequencyList, jobQueue, cfg.ImportRateLimit, l, tr)
+	leaderboard := store.leaderboard
+	leaderboardCase := usecases.NewLeaderboardUseCase(leaderboard, jobQueue, l, tr)
+	chats := store.chats
+	broadcastCase := usecases.NewBroadcastUseCase(chats, jobQueue, l, tr)
+	membershipCase := usecases.NewChatMembershipUseCase(preferences, leaderboard, chats, stats, l, tr)
+	remindersCase := usecases.NewReminderUseCase(store.reminders, timezoneCase, jobQueue, l, tr)
+	// Dead letters hold the raw updates with the messages and names of the users, so they are deleted and purged with the user data
+	deadLetters := store.deadLetters
+	deleteDataCase := usecases.NewDeleteUserDataUseCase(preferences, activity, feedback, achievements, accounts, leaderboard, chats, conversations, store.reminders, deadLetters, jobStore, stats, l, tr)
+	exportCase := usecases.NewExportUserDataUseCase(preferences, activity, feedback, achievements, accounts, leaderboard, chats, conversations, store.reminders, deadLetters, jobQueue, jobStore, l, tr)
+	retentionCase := usecases.NewRetentionSweepUseCase(feedback, activity, deadLetters, jobQueue, cfg.DataRetention, l, tr)
+	jobsCase.Register(usecases.JobTypeCacheWarmup, warmCase.Handle)
+	jobsCase.Register(usecases.JobTypeRetentionSweep, retentionCase.Handle)
+	jobsCase.Register(usecases.JobTypeDataExport, exportCase.Handle)
+
+	// Async lookups post their results to the callbacks of integrators, signed with the webhook secret
+	var asyncCase *usecases.AsyncLookupUseCase
+	if cfg.WebhookSigningSecret != "" {
+		asyncCase = usecases.NewAsyncLookupUseCase(useCase, jobQueue, webhook.NewSender(cfg.WebhookSigningSecret, cfg.WebhookAllowPrivate), l, tr)
+		asyncCase.SetTenants(tenantsCase)
+		jobsCase.Register(usecases.JobTypeAsyncLookup, asyncCase.Handle)
+	}
 
 	// Initialize handlers
+	cors := handlers.NewCORS(cfg.CORSAllowedOrigins, cfg.CORSAllowCredentials, cfg.CORSMaxAge)
+	idempotency := handlers.NewIdempotency(store.idempotency, cfg.IdempotencyTTL, l)
+	var signer services.ResponseSigner
+	if cfg.ResponseSigningAlgorithm != "" {
+		// The secret was resolved into the key at startup, so a malformed key fails here and not on the first response
+		if err := signing.ValidateKey(cfg.ResponseSigningAlgorithm, cfg.ResponseSigningKey); err != nil {
+			l.With(ctx).Err(err).Critical("Invalid response signing key")
+			return nil, fmt.Errorf("invalid response signing key: %w", err)
+		}
+		signer = signing.NewSigner(cfg.ResponseSigningAlgorithm, signingKey, cfg.ResponseSigningKeyID)
+	}
+	responseSigning := handlers.NewResponseSigning(signer, l)
 	httpHandler := handlers.NewArticleHandler(useCase, l, tr)
-	consoleHandler := console.NewConsoleHandler(useCase, l, tr)
+	httpHandler.SetArticleOnly(usecases.NewArticleOnlyUseCase(dict, useCase, l, tr))
+	httpHandler.SetSuggest(usecases.NewSuggestUseCase(dict, stats, useCase, l, tr))
+	httpHandler.SetCacheMaxAge(cfg.HTTPCacheMaxAge)
+	httpHandler.SetPreferences(preferences)
+	authentication := handlers.NewAuthentication(linkCase, l)
+	tenancy := handlers.NewTenancy(tenantsCase, l)
+	tenantsCase.SetPrompts(store.prompts)
+	promptCase := usecases.NewTenantPromptUseCase(store.prompts, tenantsCase, ai.NewPromptRenderer(), l, tr)
+	tenantHandler := handlers.NewTenantHandler(tenantsCase, promptCase, l, tr)
+	authHandler := handlers.NewAuthHandler(linkCase, sessionCase, l, tr)
+	meHandler := handlers.NewMeHandler(learningCase, deleteDataCase, exportCase, searchCase, l, tr)
+	auditCase := usecases.NewAuditLogUseCase(store.audit, l, tr)
+	deadLetterCase := usecases.NewDeadLetterUseCase(deadLetters, l, tr)
+	botWebhooksCase := usecases.NewBotWebhooksUseCase(cfg.TelegramWebhookURL, l, tr)
+	adminHandler := handlers.NewAdminHandler(adminToken, dashboardCase, listFeedbackCase, listWordsCase, warmCase, purgeCase, leaderboardCase, deadLetterCase, broadcastCase, auditCase, retentionCase, tenantsCase, botWebhooksCase, remindersCase, importDictionaryCase, l, tr)
+	importHandler := handlers.NewImportHandler(importCase, l, tr)
+	asyncHandler := handlers.NewAsyncHandler(asyncCase, l, tr)
+	embedHandler := handlers.NewEmbedHandler(useCase, l, tr)
+	embedHandler.SetCacheMaxAge(cfg.HTTPCacheMaxAge)
+	askHandler := handlers.NewAskHandler(askCase, l, tr)
+	webSocketHandler := handlers.NewWebSocketHandler(streamCase, cors, l, tr)
+	sseHandler := handlers.NewSSEHandler(streamCase, l, tr)
+	voiceHandler := handlers.NewVoiceHandler(useCase, cfg.AlexaSkillID, l, tr)
+	jobHandler := handlers.NewJobHandler(jobsCase, l, tr)
+	workerHandler := handlers.NewWorkerHandler(secrets.StaticSource(cfg.TasksWorkerToken), jobsCase, l, tr)
+	healthHandler := handlers.NewHealthHandler(healthService, l)
+	quizCase := usecases.NewQuizUseCase(dict, frequencyList, l, tr)
+	quizCase.SetActivity(activity)
+	quizCase.SetEventHandler(achievementsCase.Handle)
+	quizCase.SetTimezones(timezoneCase)
+	batchCase := usecases.NewBatchLookupUseCase(useCase, l, tr)
+	consoleHandler := console.NewConsoleHandler(useCase, importCase, batchCase, quizCase, l, tr)
+	mcpServer := mcp.NewServer(useCase, l, tr)
+	graphQLServer := graphql.NewServer(useCase, listWordsCase, dashboardCase, adminToken, l, tr)
+
+	// Initialize feature flags, the rollouts of the configuration are overridden by the Firestore collection
+	rollouts, err := featureflags.ParseRollouts(cfg.FeatureFlags)
+	if err != nil {
+		return nil, err
+	}
+	var flagSource featureflags.Source
+	if cfg.FeatureFlagsCollection != "" {
+		source, err := featureflags.NewFirestoreSource(ctx, cfg.ProjectID, cfg.FeatureFlagsCollection)
+		if err != nil {
+			// Don't fail completely, the rollouts of the configuration still apply
+			l.With(ctx).Err(err).Error("Failed to initialize remote feature flags")
+		} else {
+			flagSource = source
+		}
+	}
+	features := featureflags.New(rollouts, flagSource, cfg.FeatureFlagsRefresh, l)
+	if err := features.Refresh(ctx); err != nil {
+		l.With(ctx).Err(err).Warning("Failed to load remote feature flags, using the configured rollouts until the next refresh")
+	}
 
 	// Initialize Telegram bot (only if token is provided)
 	var telegramBot *telegram.BotHandler
+	var telegramBots *telegram.Bots
+	replies := store.replies
+	newBot := func(token string, deadLetters *usecases.DeadLetterUseCase) (*telegram.BotHandler, error) {
+		return telegram.NewBotHandler(ctx, token, useCase, submitFeedbackCase, verifyCase, followUpCase, askCase, translateCase, importCase, linkCase, quizCase, learningCase, leaderboardCase, achievementsCase, deadLetters, membershipCase, broadcastCase, deleteDataCase, exportCase, remindersCase, timezoneCase, searchCase, jobQueue, features, stats, preferences, replies, telegram.GroupSettings{
+			Enabled:   cfg.TelegramGroupsEnabled,
+			Languages: cfg.TelegramGroupLanguages,
+		}, faults.telegram, l, tr)
+	}
 	if cfg.TelegramToken != "" {
-		telegramBot, err = telegram.NewBotHandler(ctx, cfg.TelegramToken, useCase, l, tr)
+		telegramBot, err = newBot(cfg.TelegramToken, deadLetterCase)
 		if err != nil {
-			l.Error(ctx, map[string]interface{}{
-				"message": "failed to initialize Telegram bot",
-				"error":   err.Error(),
-			})
+			l.With(ctx).Err(err).Error("Failed to initialize Telegram bot")
 			// Don't fail completely if Telegram bot fails to initialize
+		} else {
+			healthService.Register(telegram.NewHealthChecker(telegramBot.GetBot()))
+			telegramBots = telegram.NewBots(telegramBot, l)
+			// The updates of the white-label bots aren't dead-lettered, the replays are processed by the main bot
+			for _, botCfg := range cfg.TelegramBots {
+				bot, err := newBot(botCfg.Token, nil)
+				if err != nil {
+					l.With(ctx).Err(err).Error("Failed to initialize white-label Telegram bot")
+					continue
+				}
+				bot.SetProfile(telegram.BotProfile{Welcome: botCfg.Welcome, Language: botCfg.Language})
+				telegramBots.Add(bot)
+				_ = bot.RegisterCommands(ctx)
+			}
+			botWebhooksCase.SetBots(telegramBots)
+			jobsCase.Register(telegram.JobTypeImport, telegramBots.HandleImportJob)
+			jobsCase.Register(usecases.JobTypeLeaderboardSummary, telegramBot.HandleLeaderboardSummaryJob)
+			jobsCase.Register(usecases.JobTypeBroadcast, telegramBot.HandleBroadcastJob)
+			jobsCase.Register(telegram.JobTypeExport, telegramBots.HandleExportJob)
+			jobsCase.Register(usecases.JobTypeReminders, telegramBots.HandleRemindersJob)
+			achievementsCase.SetNotifier(telegram.NewAchievementNotifier(telegramBot.GetBot()))
+			deadLetterCase.SetReplayer(telegramBot)
+			if cfg.TelegramAdminChatID != 0 {
+				budget.SetNotifier(telegram.NewAdminNotifier(telegramBot.GetBot(), cfg.TelegramAdminChatID))
+			}
+			// A failed registration only hides the command menu, the commands keep working
+			_ = telegramBot.RegisterCommands(ctx)
 		}
+	} else {
+		l.With(ctx).Warning("Telegram bot is disabled: TELEGRAM_BOT_TOKEN is not set")
 	}
 
 	return &Container{
 		Config:         cfg,
 		Logger:         l,
 		Tracer:         tr,
+		Secrets:        secretsProvider,
 		GeminiClient:   geminiClient,
 		AIService:      aiService,
+		Verifier:       verifier,
+		Dictionary:     dict,
+		Stats:          stats,
+		Cache:          cache,
+		Feedback:       feedback,
 		UseCase:        useCase,
+		DashboardCase:  dashboardCase,
+		CORS:           cors,
+		Idempotency:    idempotency,
+		Signing:        responseSigning,
+		Authentication: authentication,
+		Tenancy:        tenancy,
+		TenantHandler:  tenantHandler,
+		AuthHandler:    authHandler,
+		MeHandler:      meHandler,
 		HTTPHandler:    httpHandler,
+		AdminHandler:   adminHandler,
+		WorkerHandler:  workerHandler,
+		Jobs:           jobQueue,
+		JobsCase:       jobsCase,
+		JobHandler:     jobHandler,
+		ImportHandler:  importHandler,
+		AsyncHandler:   asyncHandler,
+		EmbedHandler:   embedHandler,
+		AskHandler:     askHandler,
+		WebSocket:      webSocketHandler,
+		SSEHandler:     sseHandler,
+		VoiceHandler:   voiceHandler,
+		Health:         healthService,
+		HealthHandler:  healthHandler,
 		TelegramBot:    telegramBot,
+		TelegramBots:   telegramBots,
 		ConsoleHandler: consoleHandler,
+		MCPServer:      mcpServer,
+		GraphQL:        graphQLServer,
 	}, nil
 }
@@ -0,0 +1,235 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/repositories"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/config"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/health"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/storage/firestore"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/storage/memory"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/storage/migration"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/storage/postgres"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/storage/redis"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/storage/sqlite"
+)
+
+// storage holds the repositories kept by the configured storage backend
+type storage struct {
+	cache         repositories.CacheRepository
+	preferences   repositories.PreferencesRepository
+	feedback      repositories.FeedbackRepository
+	jobs          repositories.JobRepository
+	activity      repositories.ActivityRepository
+	prompts       repositories.PromptRepository
+	reminders     repositories.ReminderRepository
+	dictionary    repositories.DictionaryRepository
+	idempotency   repositories.IdempotencyRepository
+	accounts      repositories.AccountRepository
+	leaderboard   repositories.LeaderboardRepository
+	achievements  repositories.AchievementRepository
+	deadLetters   repositories.DeadLetterRepository
+	chats         repositories.ChatRepository
+	audit         repositories.AuditRepository
+	stats         repositories.StatsRepository
+	conversations repositories.ConversationRepository
+	replies       repositories.ReplyRepository
+	// health probes the backend, nil for the in-memory one
+	health health.Checker
+}
+
+// openStorage creates the repositories of the configured storage backend. Its schema is migrated first,
+// unless the migrations are left to cmd/migrate, then a schema missing migrations fails the startup.
+func openStorage(ctx context.Context, cfg *config.Config, l logging.Logger) (*storage, error) {
+	switch cfg.Storage {
+	case config.StorageFirestore:
+		client, err := firestore.NewClient(ctx, cfg.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+		if err := prepareSchema(ctx, client, cfg.StorageMigrate, l); err != nil {
+			return nil, err
+		}
+		return &storage{
+			cache:         firestore.NewCacheRepository(client),
+			preferences:   firestore.NewPreferencesRepository(client),
+			feedback:      firestore.NewFeedbackRepository(client),
+			jobs:          firestore.NewJobRepository(client),
+			activity:      firestore.NewActivityRepository(client),
+			prompts:       firestore.NewPromptRepository(client),
+			reminders:     firestore.NewReminderRepository(client),
+			dictionary:    firestore.NewDictionaryRepository(client),
+			idempotency:   firestore.NewIdempotencyRepository(client),
+			accounts:      firestore.NewAccountRepository(client),
+			leaderboard:   firestore.NewLeaderboardRepository(client),
+			achievements:  firestore.NewAchievementRepository(client),
+			deadLetters:   firestore.NewDeadLetterRepository(client),
+			chats:         firestore.NewChatRepository(client),
+			audit:         firestore.NewAuditRepository(client),
+			stats:         firestore.NewStatsRepository(client, cfg.AICostPerCall, l),
+			conversations: firestore.NewConversationRepository(client),
+			replies:       firestore.NewReplyRepository(client, replyTTL),
+			health:        client,
+		}, nil
+	case config.StorageRedis:
+		client, err := redis.NewClient(cfg.RedisURL)
+		if err != nil {
+			return nil, err
+		}
+		if err := client.Check(ctx); err != nil {
+			return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+		}
+		return &storage{
+			cache:         redis.NewCacheRepository(client),
+			preferences:   redis.NewPreferencesRepository(client),
+			feedback:      redis.NewFeedbackRepository(client, maxFeedbackEntries),
+			jobs:          redis.NewJobRepository(client),
+			activity:      redis.NewActivityRepository(client),
+			prompts:       redis.NewPromptRepository(client),
+			reminders:     redis.NewReminderRepository(client),
+			dictionary:    redis.NewDictionaryRepository(client),
+			idempotency:   redis.NewIdempotencyRepository(client),
+			accounts:      redis.NewAccountRepository(client),
+			leaderboard:   redis.NewLeaderboardRepository(client),
+			achievements:  redis.NewAchievementRepository(client),
+			deadLetters:   redis.NewDeadLetterRepository(client),
+			chats:         redis.NewChatRepository(client),
+			audit:         redis.NewAuditRepository(client),
+			stats:         redis.NewStatsRepository(client, cfg.AICostPerCall, l),
+			conversations: redis.NewConversationRepository(client),
+			replies:       redis.NewReplyRepository(client, replyTTL),
+			health:        client,
+		}, nil
+	case config.StorageSQLite:
+		client, err := sqlite.NewClient(ctx, cfg.SQLitePath)
+		if err != nil {
+			return nil, err
+		}
+		if err := prepareSchema(ctx, client, cfg.StorageMigrate, l); err != nil {
+			return nil, err
+		}
+		if err := client.RemoveExpired(ctx); err != nil {
+			return nil, err
+		}
+		return &storage{
+			cache:         sqlite.NewCacheRepository(client),
+			preferences:   sqlite.NewPreferencesRepository(client),
+			feedback:      sqlite.NewFeedbackRepository(client),
+			jobs:          sqlite.NewJobRepository(client),
+			activity:      sqlite.NewActivityRepository(client),
+			prompts:       sqlite.NewPromptRepository(client),
+			reminders:     sqlite.NewReminderRepository(client),
+			dictionary:    sqlite.NewDictionaryRepository(client),
+			idempotency:   sqlite.NewIdempotencyRepository(client),
+			accounts:      sqlite.NewAccountRepository(client),
+			leaderboard:   sqlite.NewLeaderboardRepository(client),
+			achievements:  sqlite.NewAchievementRepository(client),
+			deadLetters:   sqlite.NewDeadLetterRepository(client),
+			chats:         sqlite.NewChatRepository(client),
+			audit:         sqlite.NewAuditRepository(client),
+			stats:         sqlite.NewStatsRepository(client, cfg.AICostPerCall, l),
+			conversations: sqlite.NewConversationRepository(client),
+			replies:       sqlite.NewReplyRepository(client, replyTTL),
+			health:        client,
+		}, nil
+	case config.StoragePostgres:
+		client, err := postgres.NewClient(ctx, cfg.PostgresURL, cfg.PostgresMaxConns)
+		if err != nil {
+			return nil, err
+		}
+		if err := prepareSchema(ctx, client, cfg.StorageMigrate, l); err != nil {
+			return nil, err
+		}
+		if err := client.RemoveExpired(ctx); err != nil {
+			return nil, err
+		}
+		return &storage{
+			cache:         postgres.NewCacheRepository(client),
+			preferences:   postgres.NewPreferencesRepository(client),
+			feedback:      postgres.NewFeedbackRepository(client),
+			jobs:          postgres.NewJobRepository(client),
+			activity:      postgres.NewActivityRepository(client),
+			prompts:       postgres.NewPromptRepository(client),
+			reminders:     postgres.NewReminderRepository(client),
+			dictionary:    postgres.NewDictionaryRepository(client),
+			idempotency:   postgres.NewIdempotencyRepository(client),
+			accounts:      postgres.NewAccountRepository(client),
+			leaderboard:   postgres.NewLeaderboardRepository(client),
+			achievements:  postgres.NewAchievementRepository(client),
+			deadLetters:   postgres.NewDeadLetterRepository(client),
+			chats:         postgres.NewChatRepository(client),
+			audit:         postgres.NewAuditRepository(client),
+			stats:         postgres.NewStatsRepository(client, cfg.AICostPerCall, l),
+			conversations: postgres.NewConversationRepository(client),
+			replies:       postgres.NewReplyRepository(client, replyTTL),
+			health:        client,
+		}, nil
+	default:
+		return &storage{
+			cache:         memory.NewCacheRepository(cfg.CacheSize),
+			preferences:   memory.NewPreferencesRepository(),
+			feedback:      memory.NewFeedbackRepository(maxFeedbackEntries),
+			jobs:          memory.NewJobRepository(maxJobStatuses),
+			activity:      memory.NewActivityRepository(maxActivityUsers),
+			prompts:       memory.NewPromptRepository(),
+			reminders:     memory.NewReminderRepository(),
+			dictionary:    memory.NewDictionaryRepository(),
+			idempotency:   memory.NewIdempotencyRepository(maxIdempotencyKeys),
+			accounts:      memory.NewAccountRepository(maxLinkCodes),
+			leaderboard:   memory.NewLeaderboardRepository(),
+			achievements:  memory.NewAchievementRepository(),
+			deadLetters:   memory.NewDeadLetterRepository(maxDeadLetters),
+			chats:         memory.NewChatRepository(),
+			audit:         memory.NewAuditRepository(),
+			stats:         newMemoryStats(cfg),
+			conversations: memory.NewConversationRepository(),
+			replies:       memory.NewReplyRepository(maxReplies),
+		}, nil
+	}
+}
+
+// newMemoryStats creates the statistics of the instance, adding the configured cost to the spend of every AI call
+func newMemoryStats(cfg *config.Config) *memory.StatsRepository {
+	stats := memory.NewStatsRepository()
+	stats.SetCallCost(cfg.AICostPerCall)
+	return stats
+}
+
+// prepareSchema migrates the schema of the backend, or checks that it's current
+func prepareSchema(ctx context.Context, migrator migration.Migrator, apply bool, l logging.Logger) error {
+	status, err := migration.Prepare(ctx, migrator, apply)
+	if err != nil {
+		return err
+	}
+	l.With(ctx).Field("backend", status.Backend).Field("version", status.Version).Info("Storage schema is current")
+
+	return nil
+}
+
+// NewMigrator connects to the configured storage backend to migrate its schema, it's nil for the backends
+// without one
+func NewMigrator(ctx context.Context, cfg *config.Config) (migration.Migrator, error) {
+	switch cfg.Storage {
+	case config.StorageFirestore:
+		client, err := firestore.NewClient(ctx, cfg.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+		return client, nil
+	case config.StorageSQLite:
+		client, err := sqlite.NewClient(ctx, cfg.SQLitePath)
+		if err != nil {
+			return nil, err
+		}
+		return client, nil
+	case config.StoragePostgres:
+		client, err := postgres.NewClient(ctx, cfg.PostgresURL, cfg.PostgresMaxConns)
+		if err != nil {
+			return nil, err
+		}
+		return client, nil
+	default:
+		return nil, nil
+	}
+}
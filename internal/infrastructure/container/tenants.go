@@ -0,0 +1,32 @@
+package container
+
+import (
+	"github.com/DeryabinSergey/germanarticlebot/internal/application/usecases"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/repositories"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/config"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/tracing"
+)
+
+// newTenantUseCase creates the tenants of the configuration, their statistics are kept in memory of the
+// instance like those of the dashboard
+func newTenantUseCase(cfg *config.Config, l logging.Logger, tr tracing.Tracer) *usecases.TenantUseCase {
+	tenants := make([]entities.Tenant, 0, len(cfg.Tenants))
+	keys := make(map[string]string)
+	for _, tenant := range cfg.Tenants {
+		tenants = append(tenants, entities.Tenant{
+			ID:            tenant.ID,
+			Name:          tenant.Name,
+			DailyQuota:    tenant.DailyQuota,
+			ExampleTopics: tenant.ExampleTopics,
+		})
+		for _, key := range tenant.APIKeys {
+			keys[key] = tenant.ID
+		}
+	}
+
+	return usecases.NewTenantUseCase(tenants, keys, func() repositories.StatsRepository {
+		return newMemoryStats(cfg)
+	}, l, tr)
+}
@@ -0,0 +1,34 @@
+package dictionary
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/repositories"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/services"
+)
+
+// CuratedDictionary implements DictionaryService with the entries imported by the operators laid over
+// another dictionary. The articles of the curated nouns win, the word list, the gender variants, the
+// suggestions and the completions are those of the underlying dictionary.
+type CuratedDictionary struct {
+	services.DictionaryService
+	entries repositories.DictionaryRepository
+}
+
+// NewCuratedDictionary creates a new dictionary answering the curated nouns from the repository first
+func NewCuratedDictionary(dictionary services.DictionaryService, entries repositories.DictionaryRepository) *CuratedDictionary {
+	return &CuratedDictionary{DictionaryService: dictionary, entries: entries}
+}
+
+// LookupArticle returns the article of the curated entry of the noun, the one of the underlying
+// dictionary for nouns without an entry
+func (d *CuratedDictionary) LookupArticle(ctx context.Context, word string) (string, bool, error) {
+	entry, err := d.entries.Get(ctx, word)
+	if err != nil {
+		return "", false, err
+	}
+	if entry != nil {
+		return entry.Article, true, nil
+	}
+
+	return d.DictionaryService.LookupArticle(ctx, word)
+}
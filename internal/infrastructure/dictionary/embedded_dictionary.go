@@ -0,0 +1,192 @@
+package dictionary
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+//go:embed data/nouns.txt data/variants.txt
+var data embed.FS
+
+// EmbeddedDictionary implements DictionaryService with a word list compiled into the binary
+type EmbeddedDictionary struct {
+	entries  []entities.DictionaryEntry
+	articles map[string]string
+	variants map[string][]entities.GenderVariant
+	// sorted holds the entries ordered by their lower-cased nouns, keys holds these nouns for the prefix search
+	sorted []entities.DictionaryEntry
+	keys   []string
+}
+
+// NewEmbeddedDictionary creates a new dictionary from the embedded word list
+func NewEmbeddedDictionary() (*EmbeddedDictionary, error) {
+	content, err := data.ReadFile("data/nouns.txt")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dictionary: %w", err)
+	}
+
+	var entries []entities.DictionaryEntry
+	articles := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for line := 1; scanner.Scan(); line++ {
+		entry := strings.TrimSpace(scanner.Text())
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+
+		article, noun, ok := strings.Cut(entry, " ")
+		if !ok || noun == "" {
+			return nil, fmt.Errorf("invalid dictionary entry on line %d: %q", line, entry)
+		}
+		entries = append(entries, entities.DictionaryEntry{Noun: noun, Article: strings.ToLower(article)})
+		articles[strings.ToLower(noun)] = strings.ToLower(article)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to parse dictionary: %w", err)
+	}
+
+	variants, err := readVariants()
+	if err != nil {
+		return nil, err
+	}
+
+	sorted := slices.Clone(entries)
+	slices.SortStableFunc(sorted, func(a, b entities.DictionaryEntry) int {
+		return strings.Compare(strings.ToLower(a.Noun), strings.ToLower(b.Noun))
+	})
+	sorted = slices.CompactFunc(sorted, func(a, b entities.DictionaryEntry) bool {
+		return strings.EqualFold(a.Noun, b.Noun)
+	})
+	keys := make([]string, len(sorted))
+	for i, entry := range sorted {
+		keys[i] = strings.ToLower(entry.Noun)
+	}
+
+	return &EmbeddedDictionary{entries: entries, articles: articles, variants: variants, sorted: sorted, keys: keys}, nil
+}
+
+// readVariants parses the "<article> <noun> = <meaning>" lines of the gender variants list
+func readVariants() (map[string][]entities.GenderVariant, error) {
+	content, err := data.ReadFile("data/variants.txt")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read gender variants: %w", err)
+	}
+
+	variants := make(map[string][]entities.GenderVariant)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for line := 1; scanner.Scan(); line++ {
+		entry := strings.TrimSpace(scanner.Text())
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+
+		word, meaning, ok := strings.Cut(entry, " = ")
+		article, noun, hasNoun := strings.Cut(strings.TrimSpace(word), " ")
+		if !ok || !hasNoun || noun == "" || strings.TrimSpace(meaning) == "" {
+			return nil, fmt.Errorf("invalid gender variant on line %d: %q", line, entry)
+		}
+		key := strings.ToLower(noun)
+		variants[key] = append(variants[key], entities.GenderVariant{
+			Article: strings.ToLower(article),
+			Meaning: strings.TrimSpace(meaning),
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to parse gender variants: %w", err)
+	}
+
+	return variants, nil
+}
+
+// LookupArticle returns the article of the noun, the lookup is case-insensitive
+func (d *EmbeddedDictionary) LookupArticle(_ context.Context, word string) (string, bool, error) {
+	article, ok := d.articles[strings.ToLower(strings.TrimSpace(word))]
+	return article, ok, nil
+}
+
+// Entries returns all nouns in the order of the word list
+func (d *EmbeddedDictionary) Entries(_ context.Context) ([]entities.DictionaryEntry, error) {
+	return slices.Clone(d.entries), nil
+}
+
+// Variants returns the meanings of every article of the noun, the lookup is case-insensitive
+func (d *EmbeddedDictionary) Variants(_ context.Context, word string) ([]entities.GenderVariant, error) {
+	return slices.Clone(d.variants[strings.ToLower(strings.TrimSpace(word))]), nil
+}
+
+// Suggest returns the dictionary nouns within a small edit distance of the word, the closest first.
+// Up to one typo is allowed in short words and up to two in words of at least six letters.
+func (d *EmbeddedDictionary) Suggest(_ context.Context, word string, limit int) ([]string, error) {
+	word = strings.ToLower(strings.TrimSpace(word))
+	length := utf8.RuneCountInString(word)
+	if length < 3 || limit <= 0 {
+		return nil, nil
+	}
+	maxDistance := 1
+	if length >= 6 {
+		maxDistance = 2
+	}
+
+	type candidate struct {
+		noun     string
+		distance int
+	}
+	var candidates []candidate
+	seen := make(map[string]bool)
+	add := func(noun string) {
+		key := strings.ToLower(noun)
+		if seen[key] || key == word {
+			return
+		}
+		seen[key] = true
+		if distance := entities.EditDistance(word, key); distance <= maxDistance {
+			candidates = append(candidates, candidate{noun: noun, distance: distance})
+		}
+	}
+	for _, entry := range d.entries {
+		add(entry.Noun)
+	}
+	for noun := range d.variants {
+		first, size := utf8.DecodeRuneInString(noun)
+		add(string(unicode.ToUpper(first)) + noun[size:])
+	}
+
+	slices.SortStableFunc(candidates, func(a, b candidate) int {
+		if a.distance != b.distance {
+			return a.distance - b.distance
+		}
+		return strings.Compare(a.noun, b.noun)
+	})
+
+	suggestions := make([]string, 0, min(limit, len(candidates)))
+	for _, c := range candidates[:min(limit, len(candidates))] {
+		suggestions = append(suggestions, c.noun)
+	}
+
+	return suggestions, nil
+}
+
+// Complete returns the dictionary nouns starting with the prefix in alphabetical order, the search is
+// case-insensitive and finds the first match by binary search
+func (d *EmbeddedDictionary) Complete(_ context.Context, prefix string, limit int) ([]entities.DictionaryEntry, error) {
+	prefix = strings.ToLower(strings.TrimSpace(prefix))
+	if prefix == "" || limit <= 0 {
+		return nil, nil
+	}
+
+	start, _ := slices.BinarySearch(d.keys, prefix)
+	var matches []entities.DictionaryEntry
+	for i := start; i < len(d.keys) && len(matches) < limit && strings.HasPrefix(d.keys[i], prefix); i++ {
+		matches = append(matches, d.sorted[i])
+	}
+
+	return matches, nil
+}
@@ -0,0 +1,68 @@
+package featureflags
+
+import (
+	"cloud.google.com/go/firestore"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"google.golang.org/api/iterator"
+)
+
+// firestoreFlag is a document of the flags collection, its ID is the feature name
+type firestoreFlag struct {
+	Enabled       bool    `firestore:"enabled"`
+	Percentage    int     `firestore:"percentage"`
+	Chats         []int64 `firestore:"chats"`
+	DisabledChats []int64 `firestore:"disabledChats"`
+}
+
+// FirestoreSource loads the feature flags from the documents of a Firestore collection
+type FirestoreSource struct {
+	client     *firestore.Client
+	collection string
+}
+
+// NewFirestoreSource creates the source of the collection in the default database of the project
+func NewFirestoreSource(ctx context.Context, projectID, collection string) (*FirestoreSource, error) {
+	client, err := firestore.NewClient(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Firestore client: %w", err)
+	}
+
+	return &FirestoreSource{client: client, collection: collection}, nil
+}
+
+// Load reads every flag of the collection
+func (s *FirestoreSource) Load(ctx context.Context) (map[entities.Feature]entities.FeatureFlag, error) {
+	flags := make(map[entities.Feature]entities.FeatureFlag)
+	documents := s.client.Collection(s.collection).Documents(ctx)
+	defer documents.Stop()
+	for {
+		document, err := documents.Next()
+		if errors.Is(err, iterator.Done) {
+			return flags, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read feature flags: %w", err)
+		}
+
+		var stored firestoreFlag
+		if err := document.DataTo(&stored); err != nil {
+			return nil, fmt.Errorf("invalid feature flag %s: %w", document.Ref.ID, err)
+		}
+		feature := entities.Feature(document.Ref.ID)
+		flags[feature] = entities.FeatureFlag{
+			Feature:       feature,
+			Enabled:       stored.Enabled,
+			Percentage:    stored.Percentage,
+			Chats:         stored.Chats,
+			DisabledChats: stored.DisabledChats,
+		}
+	}
+}
+
+// Close closes the Firestore client
+func (s *FirestoreSource) Close() error {
+	return s.client.Close()
+}
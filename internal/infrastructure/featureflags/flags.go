@@ -0,0 +1,133 @@
+package featureflags
+
+import (
+	"context"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// refreshTimeout limits a refresh of the remote overrides
+const refreshTimeout = 10 * time.Second
+
+// Source loads the remote overrides of the feature flags
+type Source interface {
+	Load(ctx context.Context) (map[entities.Feature]entities.FeatureFlag, error)
+}
+
+// Flags implements services.FeatureFlags with the flags of the configuration overridden by the flags of a
+// remote source. The overrides are refreshed in the background once they are older than the refresh
+// interval, and the last loaded overrides stay in place when a refresh fails.
+type Flags struct {
+	defaults   map[entities.Feature]entities.FeatureFlag
+	remote     Source
+	refresh    time.Duration
+	mu         sync.RWMutex
+	overrides  map[entities.Feature]entities.FeatureFlag
+	loadedAt   time.Time
+	refreshing atomic.Bool
+	logger     logging.Logger
+}
+
+// New creates the feature flags, a nil source only uses the flags of the configuration
+func New(defaults map[entities.Feature]entities.FeatureFlag, remote Source, refresh time.Duration, logger logging.Logger) *Flags {
+	return &Flags{
+		defaults: defaults,
+		remote:   remote,
+		refresh:  refresh,
+		logger:   logger,
+	}
+}
+
+// Enabled reports whether the feature is on for the chat
+func (f *Flags) Enabled(ctx context.Context, feature entities.Feature, chatID int64) bool {
+	f.refreshIfStale(ctx)
+
+	f.mu.RLock()
+	flag, ok := f.overrides[feature]
+	f.mu.RUnlock()
+	if !ok {
+		flag, ok = f.defaults[feature]
+	}
+
+	return ok && flag.EnabledFor(chatID)
+}
+
+// Refresh loads the remote overrides now, it's called on start so the first updates see them
+func (f *Flags) Refresh(ctx context.Context) error {
+	if f.remote == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, refreshTimeout)
+	defer cancel()
+
+	overrides, err := f.remote.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load feature flags: %w", err)
+	}
+
+	f.mu.Lock()
+	f.overrides = overrides
+	f.loadedAt = time.Now()
+	f.mu.Unlock()
+
+	return nil
+}
+
+// refreshIfStale starts a background refresh of outdated overrides, lookups don't wait for it
+func (f *Flags) refreshIfStale(ctx context.Context) {
+	if f.remote == nil {
+		return
+	}
+	f.mu.RLock()
+	stale := time.Since(f.loadedAt) >= f.refresh
+	f.mu.RUnlock()
+	if !stale || !f.refreshing.CompareAndSwap(false, true) {
+		return
+	}
+
+	go func() {
+		defer f.refreshing.Store(false)
+		if err := f.Refresh(context.WithoutCancel(ctx)); err != nil {
+			f.logger.With(ctx).Err(err).Warning("Failed to refresh feature flags, keeping the previous ones")
+		}
+	}()
+}
+
+// ParseRollout parses the rollout of a feature in the configuration: "on", "off" or a percentage like "25%"
+func ParseRollout(feature entities.Feature, rollout string) (entities.FeatureFlag, error) {
+	flag := entities.FeatureFlag{Feature: feature}
+	switch rollout = strings.ToLower(strings.TrimSpace(rollout)); rollout {
+	case "on":
+		flag.Enabled = true
+	case "off":
+	default:
+		percentage, err := strconv.Atoi(strings.TrimSuffix(rollout, "%"))
+		if !strings.HasSuffix(rollout, "%") || err != nil || percentage < 0 || percentage > 100 {
+			return flag, fmt.Errorf("the rollout of %s must be on, off or a percentage like 25%%, got %q", feature, rollout)
+		}
+		flag.Percentage = percentage
+	}
+
+	return flag, nil
+}
+
+// ParseRollouts parses the rollouts of the configuration by feature name
+func ParseRollouts(rollouts map[string]string) (map[entities.Feature]entities.FeatureFlag, error) {
+	flags := make(map[entities.Feature]entities.FeatureFlag, len(rollouts))
+	for name, rollout := range rollouts {
+		flag, err := ParseRollout(entities.Feature(name), rollout)
+		if err != nil {
+			return nil, err
+		}
+		flags[flag.Feature] = flag
+	}
+
+	return flags, nil
+}
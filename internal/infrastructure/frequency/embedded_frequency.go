@@ -0,0 +1,69 @@
+package frequency
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed data/frequency.txt
+var data embed.FS
+
+// EmbeddedFrequencyList implements FrequencyService with a frequency list compiled into the binary
+type EmbeddedFrequencyList struct {
+	ranks map[string]int
+	// nouns are the nouns as spelled in the list, the most frequent first
+	nouns []string
+}
+
+// NewEmbeddedFrequencyList creates a new frequency list from the embedded data
+func NewEmbeddedFrequencyList() (*EmbeddedFrequencyList, error) {
+	content, err := data.ReadFile("data/frequency.txt")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read frequency list: %w", err)
+	}
+
+	ranks := make(map[string]int)
+	var nouns []string
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for line := 1; scanner.Scan(); line++ {
+		entry := strings.TrimSpace(scanner.Text())
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+
+		value, noun, ok := strings.Cut(entry, " ")
+		rank, err := strconv.Atoi(value)
+		if !ok || noun == "" || err != nil || rank <= 0 {
+			return nil, fmt.Errorf("invalid frequency entry on line %d: %q", line, entry)
+		}
+		ranks[strings.ToLower(noun)] = rank
+		nouns = append(nouns, noun)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to parse frequency list: %w", err)
+	}
+
+	sort.SliceStable(nouns, func(i, j int) bool {
+		return ranks[strings.ToLower(nouns[i])] < ranks[strings.ToLower(nouns[j])]
+	})
+
+	return &EmbeddedFrequencyList{ranks: ranks, nouns: nouns}, nil
+}
+
+// Rank returns the frequency rank of the noun, the lookup is case-insensitive
+func (f *EmbeddedFrequencyList) Rank(_ context.Context, noun string) (int, bool, error) {
+	rank, ok := f.ranks[strings.ToLower(strings.TrimSpace(noun))]
+	return rank, ok, nil
+}
+
+// Top returns at most limit nouns of the list, the most frequent first
+func (f *EmbeddedFrequencyList) Top(_ context.Context, limit int) ([]string, error) {
+	return slices.Clone(f.nouns[:min(max(limit, 0), len(f.nouns))]), nil
+}
@@ -0,0 +1,134 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	StatusOK       = "ok"
+	StatusDegraded = "degraded"
+	StatusDown     = "down"
+
+	defaultTimeout = 3 * time.Second
+)
+
+// Checker probes a single external dependency
+type Checker interface {
+	Name() string
+	// Critical reports whether the service cannot serve requests without the dependency
+	Critical() bool
+	Check(ctx context.Context) error
+}
+
+// CheckResult holds the probe result of a single dependency
+type CheckResult struct {
+	Status    string `json:"status"`
+	Critical  bool   `json:"critical"`
+	LatencyMs int64  `json:"latencyMs"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Report holds the overall readiness and the per-dependency results
+type Report struct {
+	Status       string                 `json:"status"`
+	Dependencies map[string]CheckResult `json:"dependencies,omitempty"`
+}
+
+// Ready reports whether all critical dependencies are reachable
+func (r *Report) Ready() bool {
+	return r.Status != StatusDown
+}
+
+// Service runs the registered dependency checkers
+type Service struct {
+	checkers []Checker
+	timeout  time.Duration
+}
+
+// NewService creates a new health service with the given checkers
+func NewService(timeout time.Duration, checkers ...Checker) *Service {
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return &Service{
+		checkers: checkers,
+		timeout:  timeout,
+	}
+}
+
+// Register adds a checker to the service
+func (s *Service) Register(checker Checker) {
+	s.checkers = append(s.checkers, checker)
+}
+
+// Liveness reports that the process is up without touching dependencies
+func (s *Service) Liveness(_ context.Context) *Report {
+	return &Report{Status: StatusOK}
+}
+
+// Readiness probes all dependencies concurrently
+func (s *Service) Readiness(ctx context.Context) *Report {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	report := &Report{
+		Status:       StatusOK,
+		Dependencies: make(map[string]CheckResult, len(s.checkers)),
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, checker := range s.checkers {
+		wg.Add(1)
+		go func(checker Checker) {
+			defer wg.Done()
+			result := s.check(ctx, checker)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Dependencies[checker.Name()] = result
+			switch {
+			case result.Status == StatusOK:
+			case checker.Critical():
+				report.Status = StatusDown
+			case report.Status == StatusOK:
+				report.Status = StatusDegraded
+			}
+		}(checker)
+	}
+	wg.Wait()
+
+	return report
+}
+
+// check runs a single checker, giving up when the context is done even if the checker ignores it
+func (s *Service) check(ctx context.Context, checker Checker) CheckResult {
+	start := time.Now()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- checker.Check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	result := CheckResult{
+		Status:    StatusOK,
+		Critical:  checker.Critical(),
+		LatencyMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+
+	return result
+}
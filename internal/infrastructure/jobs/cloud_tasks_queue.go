@@ -0,0 +1,80 @@
+package jobs
+
+import (
+	cloudtasks "cloud.google.com/go/cloudtasks/apiv2"
+	"cloud.google.com/go/cloudtasks/apiv2/cloudtaskspb"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"strings"
+)
+
+const (
+	// WorkerPath is the path of the endpoint processing the queued jobs
+	WorkerPath = "/tasks/worker"
+	// WorkerTokenHeader carries the shared token authenticating task deliveries
+	WorkerTokenHeader = "X-Worker-Token"
+	// RetryCountHeader is set by Cloud Tasks to the number of previous delivery attempts
+	RetryCountHeader = "X-CloudTasks-TaskRetryCount"
+)
+
+// CloudTasksQueue implements JobQueue with Google Cloud Tasks delivering jobs to the worker endpoint
+type CloudTasksQueue struct {
+	client    *cloudtasks.Client
+	queue     string
+	workerURL string
+	token     string
+}
+
+// NewCloudTasksQueue creates a new queue adding HTTP tasks to the queue resource
+// "projects/<project>/locations/<location>/queues/<queue>"
+func NewCloudTasksQueue(ctx context.Context, queue, workerURL, token string) (*CloudTasksQueue, error) {
+	client, err := cloudtasks.NewClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Cloud Tasks client: %w", err)
+	}
+
+	return &CloudTasksQueue{
+		client:    client,
+		queue:     queue,
+		workerURL: strings.TrimSuffix(workerURL, "/") + WorkerPath,
+		token:     token,
+	}, nil
+}
+
+// Enqueue adds a task posting the job to the worker, the job ID deduplicates repeated enqueues
+func (q *CloudTasksQueue) Enqueue(ctx context.Context, job *entities.Job) error {
+	body, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to encode job: %w", err)
+	}
+
+	_, err = q.client.CreateTask(ctx, &cloudtaskspb.CreateTaskRequest{
+		Parent: q.queue,
+		Task: &cloudtaskspb.Task{
+			Name: q.queue + "/tasks/" + job.ID,
+			MessageType: &cloudtaskspb.Task_HttpRequest{
+				HttpRequest: &cloudtaskspb.HttpRequest{
+					Url:        q.workerURL,
+					HttpMethod: cloudtaskspb.HttpMethod_POST,
+					Headers: map[string]string{
+						"Content-Type":    "application/json",
+						WorkerTokenHeader: q.token,
+					},
+					Body: body,
+				},
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to enqueue %s job: %w", job.Type, err)
+	}
+
+	return nil
+}
+
+// Close closes the underlying client
+func (q *CloudTasksQueue) Close() error {
+	return q.client.Close()
+}
@@ -0,0 +1,42 @@
+package jobs
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+)
+
+// Dispatcher processes a job, it is the worker side of the queue
+type Dispatcher func(ctx context.Context, job *entities.Job) error
+
+// LocalQueue implements JobQueue by processing jobs in a goroutine of the running instance.
+// Jobs are lost when the instance stops, so it is meant for development and the console.
+type LocalQueue struct {
+	dispatch Dispatcher
+	logger   logging.Logger
+}
+
+// NewLocalQueue creates a new in-process queue
+func NewLocalQueue(dispatch Dispatcher, logger logging.Logger) *LocalQueue {
+	return &LocalQueue{
+		dispatch: dispatch,
+		logger:   logger,
+	}
+}
+
+// Enqueue starts processing the job without waiting for the result
+func (q *LocalQueue) Enqueue(ctx context.Context, job *entities.Job) error {
+	go func(ctx context.Context) {
+		// Failures are logged by the dispatcher, the local queue doesn't retry
+		_ = q.dispatch(ctx, job)
+	}(context.WithoutCancel(ctx))
+
+	q.logger.With(ctx).Field("jobId", job.ID).Field("jobType", job.Type).Debug("Job enqueued locally")
+
+	return nil
+}
+
+// Close does nothing, running jobs are not waited for
+func (q *LocalQueue) Close() error {
+	return nil
+}
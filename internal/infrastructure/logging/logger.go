@@ -2,14 +2,14 @@ package logging
 
 import (
 	"context"
+	"github.com/DeryabinSergey/germanarticlebot/libs/logger"
 )
 
-// Logger defines the logging interface
+// Logger defines the logging interface, entries are built from typed fields with With:
+//
+//	l.With(ctx).Err(err).Field("word", word).Warning("Failed to look up dictionary")
 type Logger interface {
-	Debug(ctx context.Context, payload interface{})
-	Info(ctx context.Context, payload interface{})
-	Warning(ctx context.Context, payload interface{})
-	Error(ctx context.Context, payload interface{})
-	Critical(ctx context.Context, payload interface{})
+	With(ctx context.Context) *logger.Entry
+	Flush(ctx context.Context) error
 	Close(ctx context.Context) error
-}
\ No newline at end of file
+}
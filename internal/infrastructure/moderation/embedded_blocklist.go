@@ -0,0 +1,127 @@
+package moderation
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+//go:embed data/blocklist.txt
+var data embed.FS
+
+// categories are the content categories of the blocklist
+var categories = map[string]entities.ContentCategory{
+	string(entities.ContentProfanity): entities.ContentProfanity,
+	string(entities.ContentViolence):  entities.ContentViolence,
+	string(entities.ContentPolitics):  entities.ContentPolitics,
+}
+
+// blockedPrefix is a blocked beginning of words
+type blockedPrefix struct {
+	prefix   string
+	category entities.ContentCategory
+}
+
+// EmbeddedBlocklist implements ContentModerator with a list of blocked words compiled into the binary
+type EmbeddedBlocklist struct {
+	words    map[string]entities.ContentCategory
+	prefixes []blockedPrefix
+}
+
+// NewEmbeddedBlocklist creates a new content moderator from the embedded blocklist
+func NewEmbeddedBlocklist() (*EmbeddedBlocklist, error) {
+	content, err := data.ReadFile("data/blocklist.txt")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read blocklist: %w", err)
+	}
+
+	blocklist := &EmbeddedBlocklist{words: make(map[string]entities.ContentCategory)}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for line := 1; scanner.Scan(); line++ {
+		entry := strings.TrimSpace(scanner.Text())
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+
+		name, word, ok := strings.Cut(entry, " ")
+		category, known := categories[name]
+		word = strings.ToLower(strings.TrimSpace(word))
+		if !ok || !known || word == "" || word == "*" {
+			return nil, fmt.Errorf("invalid blocklist entry on line %d: %q", line, entry)
+		}
+		if prefix, ok := strings.CutSuffix(word, "*"); ok {
+			blocklist.prefixes = append(blocklist.prefixes, blockedPrefix{prefix: prefix, category: category})
+			continue
+		}
+		blocklist.words[word] = category
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to parse blocklist: %w", err)
+	}
+
+	return blocklist, nil
+}
+
+// Screen returns the category of the first blocked word of the text, empty for texts without one
+func (b *EmbeddedBlocklist) Screen(_ context.Context, text string, allowed []string) (entities.ContentCategory, error) {
+	var stems []string
+	for _, word := range allowed {
+		// Articles and particles of the translations, like "a" in "a weapon", would exempt too many words
+		for _, token := range tokenize(word) {
+			if utf8.RuneCountInString(token) >= 3 {
+				stems = append(stems, stem(token))
+			}
+		}
+	}
+
+	for _, token := range tokenize(text) {
+		if exempt(token, stems) {
+			continue
+		}
+		if category, ok := b.words[token]; ok {
+			return category, nil
+		}
+		for _, blocked := range b.prefixes {
+			if strings.HasPrefix(token, blocked.prefix) {
+				return blocked.category, nil
+			}
+		}
+	}
+
+	return "", nil
+}
+
+// tokenize splits the text into lower-cased words
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// stem drops the last letter of longer words, so the inflected forms like Waffen or войны share the stem
+// of Waffe and война. Short words are kept whole, their stems would exempt too many words.
+func stem(word string) string {
+	if utf8.RuneCountInString(word) <= 4 {
+		return word
+	}
+	_, size := utf8.DecodeLastRuneInString(word)
+
+	return word[:len(word)-size]
+}
+
+// exempt reports whether the word starts with one of the stems
+func exempt(word string, stems []string) bool {
+	for _, stem := range stems {
+		if strings.HasPrefix(word, stem) {
+			return true
+		}
+	}
+
+	return false
+}
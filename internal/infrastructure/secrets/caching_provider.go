@@ -0,0 +1,67 @@
+package secrets
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type cachedSecret struct {
+	value     string
+	fetchedAt time.Time
+}
+
+// CachingProvider caches values of another provider and re-fetches them after the TTL,
+// so rotated secrets are picked up without a restart
+type CachingProvider struct {
+	next  Provider
+	ttl   time.Duration
+	mu    sync.Mutex
+	cache map[string]cachedSecret
+}
+
+// NewCachingProvider wraps the provider with a cache, a zero TTL caches values forever
+func NewCachingProvider(next Provider, ttl time.Duration) *CachingProvider {
+	return &CachingProvider{
+		next:  next,
+		ttl:   ttl,
+		cache: make(map[string]cachedSecret),
+	}
+}
+
+// Get returns the cached value or fetches it, falling back to the stale value if the refresh fails
+func (p *CachingProvider) Get(ctx context.Context, name string) (string, error) {
+	p.mu.Lock()
+	cached, ok := p.cache[name]
+	p.mu.Unlock()
+
+	if ok && (p.ttl == 0 || time.Since(cached.fetchedAt) < p.ttl) {
+		return cached.value, nil
+	}
+
+	value, err := p.next.Get(ctx, name)
+	if err != nil {
+		if ok {
+			return cached.value, nil
+		}
+		return "", err
+	}
+
+	p.mu.Lock()
+	p.cache[name] = cachedSecret{value: value, fetchedAt: time.Now()}
+	p.mu.Unlock()
+
+	return value, nil
+}
+
+// Invalidate drops the cached value so the next Get fetches the current version
+func (p *CachingProvider) Invalidate(name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.cache, name)
+}
+
+// Close closes the wrapped provider
+func (p *CachingProvider) Close() error {
+	return p.next.Close()
+}
@@ -0,0 +1,61 @@
+package secrets
+
+import (
+	secretmanager "cloud.google.com/go/secretmanager/apiv1"
+	"cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+	"context"
+	"fmt"
+	"strings"
+)
+
+// GoogleSecretManager implements Provider using Google Secret Manager
+type GoogleSecretManager struct {
+	client    *secretmanager.Client
+	projectID string
+}
+
+// NewGoogleSecretManager creates a new Secret Manager provider for the project
+func NewGoogleSecretManager(ctx context.Context, projectID string) (*GoogleSecretManager, error) {
+	client, err := secretmanager.NewClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Secret Manager client: %w", err)
+	}
+
+	return &GoogleSecretManager{
+		client:    client,
+		projectID: projectID,
+	}, nil
+}
+
+// Get accesses the secret version, a short name resolves to the latest version in the project
+func (p *GoogleSecretManager) Get(ctx context.Context, name string) (string, error) {
+	resp, err := p.client.AccessSecretVersion(ctx, &secretmanagerpb.AccessSecretVersionRequest{
+		Name: p.resourceName(name),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to access secret %s: %w", name, err)
+	}
+
+	return strings.TrimSpace(string(resp.GetPayload().GetData())), nil
+}
+
+// Close closes the underlying client
+func (p *GoogleSecretManager) Close() error {
+	return p.client.Close()
+}
+
+// resourceName accepts "name", "name/versions/3" or a full "projects/..." resource name
+func (p *GoogleSecretManager) resourceName(name string) string {
+	if strings.HasPrefix(name, "projects/") {
+		if !strings.Contains(name, "/versions/") {
+			return name + "/versions/latest"
+		}
+		return name
+	}
+
+	if !strings.Contains(name, "/versions/") {
+		name += "/versions/latest"
+	}
+
+	return "projects/" + p.projectID + "/secrets/" + name
+}
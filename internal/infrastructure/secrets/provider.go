@@ -0,0 +1,28 @@
+package secrets
+
+import (
+	"context"
+)
+
+// Provider resolves secret values by name
+type Provider interface {
+	Get(ctx context.Context, name string) (string, error)
+	Close() error
+}
+
+// Source returns the current value of a secret, so callers pick up rotated values
+type Source func(ctx context.Context) (string, error)
+
+// StaticSource returns a source of a value that never changes
+func StaticSource(value string) Source {
+	return func(context.Context) (string, error) {
+		return value, nil
+	}
+}
+
+// ProviderSource returns a source reading the named secret from the provider on every call
+func ProviderSource(provider Provider, name string) Source {
+	return func(ctx context.Context) (string, error) {
+		return provider.Get(ctx, name)
+	}
+}
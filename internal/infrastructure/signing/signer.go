@@ -0,0 +1,137 @@
+package signing
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/secrets"
+	"strings"
+)
+
+const (
+	// AlgorithmHMAC signs with a secret shared with the consumers, signatures are "sha256=" and the hex HMAC-SHA256
+	AlgorithmHMAC = "hmac-sha256"
+	// AlgorithmEd25519 signs with a private key, signatures are "ed25519=" and the base64 signature
+	AlgorithmEd25519 = "ed25519"
+
+	minHMACKeyLength = 16
+)
+
+// Signer implements ResponseSigner, the key is read from its source on every call so rotated keys
+// are picked up. Without a configured key ID it is derived from the key.
+type Signer struct {
+	algorithm string
+	key       secrets.Source
+	keyID     string
+}
+
+// NewSigner creates a signer of the algorithm, the key must pass ValidateKey
+func NewSigner(algorithm string, key secrets.Source, keyID string) *Signer {
+	return &Signer{
+		algorithm: algorithm,
+		key:       key,
+		keyID:     keyID,
+	}
+}
+
+// ValidateKey checks that the key can sign with the algorithm: HMAC keys need 16 characters, Ed25519
+// keys are the base64 encoded 32 byte seed or 64 byte private key
+func ValidateKey(algorithm, key string) error {
+	switch algorithm {
+	case AlgorithmHMAC:
+		if len(key) < minHMACKeyLength {
+			return fmt.Errorf("HMAC signing keys must be at least %d characters long", minHMACKeyLength)
+		}
+		return nil
+	case AlgorithmEd25519:
+		_, err := parsePrivateKey(key)
+		return err
+	default:
+		return fmt.Errorf("unknown signing algorithm %q, must be %s or %s", algorithm, AlgorithmHMAC, AlgorithmEd25519)
+	}
+}
+
+// Sign signs the body with the current key
+func (s *Signer) Sign(ctx context.Context, body []byte) (string, string, error) {
+	key, err := s.key(ctx)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read signing key: %w", err)
+	}
+
+	switch s.algorithm {
+	case AlgorithmHMAC:
+		if err := ValidateKey(s.algorithm, key); err != nil {
+			return "", "", err
+		}
+		mac := hmac.New(sha256.New, []byte(key))
+		mac.Write(body)
+		return "sha256=" + hex.EncodeToString(mac.Sum(nil)), s.id([]byte(key)), nil
+
+	default:
+		private, err := parsePrivateKey(key)
+		if err != nil {
+			return "", "", err
+		}
+		public := private.Public().(ed25519.PublicKey)
+		return "ed25519=" + base64.StdEncoding.EncodeToString(ed25519.Sign(private, body)), s.id(public), nil
+	}
+}
+
+// PublicKeys returns the public key of Ed25519 signers, HMAC keys are secret
+func (s *Signer) PublicKeys(ctx context.Context) ([]entities.VerificationKey, error) {
+	if s.algorithm != AlgorithmEd25519 {
+		return nil, nil
+	}
+
+	key, err := s.key(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing key: %w", err)
+	}
+	private, err := parsePrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	public := private.Public().(ed25519.PublicKey)
+
+	return []entities.VerificationKey{{
+		KeyType:   "OKP",
+		Curve:     "Ed25519",
+		KeyID:     s.id(public),
+		Algorithm: "EdDSA",
+		Use:       "sig",
+		X:         base64.RawURLEncoding.EncodeToString(public),
+	}}, nil
+}
+
+// id returns the configured key ID or the first bytes of the hash of the key material, which
+// identifies the key without revealing it
+func (s *Signer) id(material []byte) string {
+	if s.keyID != "" {
+		return s.keyID
+	}
+
+	sum := sha256.Sum256(material)
+	return hex.EncodeToString(sum[:8])
+}
+
+func parsePrivateKey(key string) (ed25519.PrivateKey, error) {
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(key))
+	if err != nil {
+		return nil, errors.New("Ed25519 signing keys must be base64 encoded")
+	}
+
+	switch len(data) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(data), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(data), nil
+	default:
+		return nil, fmt.Errorf("Ed25519 signing keys must be %d or %d bytes long", ed25519.SeedSize, ed25519.PrivateKeySize)
+	}
+}
@@ -0,0 +1,190 @@
+package document
+
+import (
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ArticleCount counts the quiz answers to nouns of an article
+type ArticleCount struct {
+	Answers int `json:"answers"`
+	Correct int `json:"correct"`
+}
+
+// Activity is the stored learning activity of a user, the backends keep it as one document per user
+type Activity struct {
+	Days map[string]*entities.DailyActivity `json:"days"`
+	// LastLookup is the last day each word was counted on
+	LastLookup map[string]string `json:"lastLookup"`
+	// Glossary holds the article and the translation of the last answer of each word, words looked up
+	// before it was kept have none
+	Glossary map[string]*entities.LookedUpWord `json:"glossary,omitempty"`
+	Articles map[string]*ArticleCount          `json:"articles"`
+	Confused map[string]*entities.ConfusedWord `json:"confused"`
+	// Mastered maps the nouns whose last quiz answer was correct to their spelling
+	Mastered map[string]string `json:"mastered"`
+	ActiveAt time.Time         `json:"activeAt"`
+}
+
+// NewActivity creates the empty activity of a user
+func NewActivity() *Activity {
+	return &Activity{
+		Days:       make(map[string]*entities.DailyActivity),
+		LastLookup: make(map[string]string),
+		Glossary:   make(map[string]*entities.LookedUpWord),
+		Articles:   make(map[string]*ArticleCount),
+		Confused:   make(map[string]*entities.ConfusedWord),
+		Mastered:   make(map[string]string),
+	}
+}
+
+// Touch marks the user active at the time
+func (a *Activity) Touch(at time.Time) {
+	if at.After(a.ActiveAt) {
+		a.ActiveAt = at
+	}
+}
+
+// RecordLookup counts the word for the day of at in its time zone, once per word and day, and keeps its
+// answer in the glossary. It reports whether the activity changed.
+func (a *Activity) RecordLookup(word entities.LookedUpWord, at time.Time) bool {
+	a.Touch(at)
+	day, key := at.Format(entities.ActivityDayLayout), strings.ToLower(word.Word)
+	changed := false
+	if known := a.Glossary[key]; (word.Article != "" || word.Translation != "") && (known == nil || *known != word) {
+		a.Glossary[key] = &word
+		changed = true
+	}
+	if a.LastLookup[key] == day {
+		return changed
+	}
+	a.LastLookup[key] = day
+	a.day(day).Lookups++
+
+	return true
+}
+
+// RecordQuizAnswer counts the answer for its day in the time zone of the answer and the article of the noun
+func (a *Activity) RecordQuizAnswer(answer *entities.QuizAnswer) {
+	a.Touch(answer.AnsweredAt)
+	day := a.day(answer.AnsweredAt.Format(entities.ActivityDayLayout))
+	day.QuizAnswers++
+
+	article := strings.ToLower(answer.Article)
+	count, ok := a.Articles[article]
+	if !ok {
+		count = &ArticleCount{}
+		a.Articles[article] = count
+	}
+	count.Answers++
+
+	key := strings.ToLower(answer.Noun)
+	if answer.Correct {
+		day.QuizCorrect++
+		count.Correct++
+		a.Mastered[key] = answer.Noun
+		return
+	}
+
+	delete(a.Mastered, key)
+	confused, ok := a.Confused[key]
+	if !ok {
+		confused = &entities.ConfusedWord{Noun: answer.Noun, Article: answer.Article}
+		a.Confused[key] = confused
+	}
+	confused.Mistakes++
+}
+
+// Search returns at most limit words looked up or met in the quiz matching the query in the word or its
+// translation, ranked by their score and then by the last lookup, the latest first
+func (a *Activity) Search(query string, limit int) []entities.WordMatch {
+	words := make(map[string]*entities.WordMatch, len(a.LastLookup))
+	word := func(key, spelling string) *entities.WordMatch {
+		match, ok := words[key]
+		if !ok {
+			match = &entities.WordMatch{Word: spelling}
+			words[key] = match
+		}
+		return match
+	}
+	for key, day := range a.LastLookup {
+		match := word(key, entities.NormalizeWord(key))
+		match.LastLookup = day
+		if glossary := a.Glossary[key]; glossary != nil {
+			match.Word, match.Article, match.Translation = glossary.Word, glossary.Article, glossary.Translation
+		}
+	}
+	for key, confused := range a.Confused {
+		if match := word(key, confused.Noun); match.Article == "" {
+			match.Article = confused.Article
+		}
+	}
+	for key, noun := range a.Mastered {
+		word(key, noun).Mastered = true
+	}
+
+	var matches []entities.WordMatch
+	for _, match := range words {
+		match.Score = max(entities.MatchScore(query, match.Word), entities.TranslationScore(query, match.Translation))
+		if match.Score > 0 {
+			matches = append(matches, *match)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		if matches[i].LastLookup != matches[j].LastLookup {
+			return matches[i].LastLookup > matches[j].LastLookup
+		}
+		return matches[i].Word < matches[j].Word
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	return matches
+}
+
+// Entity returns a copy of the activity as the activity of the user
+func (a *Activity) Entity(userID int64) *entities.UserActivity {
+	activity := &entities.UserActivity{UserID: userID}
+	for _, day := range a.Days {
+		activity.Days = append(activity.Days, *day)
+	}
+	sort.Slice(activity.Days, func(i, j int) bool { return activity.Days[i].Day < activity.Days[j].Day })
+	activity.Words = len(a.LastLookup)
+	activity.LookedUp = make(map[string]string, len(a.LastLookup))
+	for word, day := range a.LastLookup {
+		activity.LookedUp[word] = day
+	}
+	for _, word := range a.Glossary {
+		activity.Glossary = append(activity.Glossary, *word)
+	}
+	sort.Slice(activity.Glossary, func(i, j int) bool { return activity.Glossary[i].Word < activity.Glossary[j].Word })
+	for article, count := range a.Articles {
+		activity.Articles = append(activity.Articles, entities.ArticleAccuracy{Article: article, Answers: count.Answers, Correct: count.Correct})
+	}
+	for _, confused := range a.Confused {
+		activity.Confused = append(activity.Confused, *confused)
+	}
+	for _, noun := range a.Mastered {
+		activity.Mastered = append(activity.Mastered, noun)
+	}
+	sort.Strings(activity.Mastered)
+
+	return activity
+}
+
+// day returns the counters of the day, created if needed
+func (a *Activity) day(day string) *entities.DailyActivity {
+	activity, ok := a.Days[day]
+	if !ok {
+		activity = &entities.DailyActivity{Day: day}
+		a.Days[day] = activity
+	}
+
+	return activity
+}
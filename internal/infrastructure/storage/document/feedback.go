@@ -0,0 +1,43 @@
+package document
+
+import (
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"strings"
+)
+
+// FeedbackOrder maps the ordering fields of feedback lists to the sort keys of a rating
+var FeedbackOrder = map[string]func(feedback *entities.Feedback) SortKey{
+	"createdAt": func(feedback *entities.Feedback) SortKey {
+		return SortKey{Key: TimeKey(feedback.CreatedAt), ID: feedback.ID}
+	},
+	"word": func(feedback *entities.Feedback) SortKey {
+		return SortKey{Key: strings.ToLower(feedback.Word), ID: feedback.ID}
+	},
+}
+
+// FeedbackPage returns the page of the filter with its order, newest first unless the page orders it otherwise
+func FeedbackPage(filter entities.FeedbackFilter) (entities.PageRequest, func(feedback *entities.Feedback) SortKey) {
+	page := filter.Page
+	keyOf, ok := FeedbackOrder[page.OrderBy]
+	if !ok {
+		page.OrderBy, page.Descending = "createdAt", true
+		keyOf = FeedbackOrder[page.OrderBy]
+	}
+
+	return page, keyOf
+}
+
+// MatchesFeedback reports whether the feedback passes the filter
+func MatchesFeedback(filter entities.FeedbackFilter, feedback *entities.Feedback) bool {
+	if filter.Verdict != "" && feedback.Verdict != filter.Verdict {
+		return false
+	}
+	if filter.Word != "" && !strings.EqualFold(feedback.Word, filter.Word) {
+		return false
+	}
+	if filter.Language != "" && feedback.Language != filter.Language {
+		return false
+	}
+
+	return true
+}
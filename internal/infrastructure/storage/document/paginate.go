@@ -0,0 +1,103 @@
+// Package document holds the stored shapes and the list logic shared by the storage backends
+package document
+
+import (
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SortKey is the value of the ordering field of an item and its ID breaking ties,
+// the keys of a field must compare as strings in their natural order
+type SortKey struct {
+	Key string
+	ID  string
+}
+
+// timeKeyLayout formats times as fixed-width sort keys
+const timeKeyLayout = "20060102150405.000000000"
+
+// TimeKey formats a time as a fixed-width sort key
+func TimeKey(t time.Time) string {
+	return t.UTC().Format(timeKeyLayout)
+}
+
+// ParseTimeKey reads the time of a sort key formatted by TimeKey
+func ParseTimeKey(key string) (time.Time, error) {
+	return time.Parse(timeKeyLayout, key)
+}
+
+// CountKey formats a non-negative counter as a fixed-width sort key
+func CountKey(n int64) string {
+	return fmt.Sprintf("%020d", n)
+}
+
+// Paginate sorts the items by the keys of the page order and returns the page after the cursor of the
+// page token. Ties are always broken by ascending ID so pages stay stable in both directions.
+func Paginate[T any](items []T, page entities.PageRequest, keyOf func(item T) SortKey) (entities.Page[T], error) {
+	compare := func(a, b SortKey) int {
+		c := strings.Compare(a.Key, b.Key)
+		if page.Descending {
+			c = -c
+		}
+		if c == 0 {
+			c = strings.Compare(a.ID, b.ID)
+		}
+		return c
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return compare(keyOf(items[i]), keyOf(items[j])) < 0
+	})
+
+	start := 0
+	after, err := After(page)
+	if err != nil {
+		return entities.Page[T]{}, err
+	}
+	if after != nil {
+		start = sort.Search(len(items), func(i int) bool {
+			return compare(keyOf(items[i]), *after) > 0
+		})
+	}
+
+	end := len(items)
+	if page.Limit > 0 && start+page.Limit < end {
+		end = start + page.Limit
+	}
+
+	result := entities.Page[T]{Items: items[start:end]}
+	if end < len(items) {
+		result.NextPageToken = NextPageToken(page, keyOf(items[end-1]))
+	}
+
+	return result, nil
+}
+
+// After returns the sort key of the last item of the previous page, nil for the first page
+func After(page entities.PageRequest) (*SortKey, error) {
+	if page.PageToken == "" {
+		return nil, nil
+	}
+
+	cursor, err := entities.DecodePageToken(page.PageToken)
+	if err != nil {
+		return nil, err
+	}
+	if cursor.OrderBy != page.OrderBy || cursor.Descending != page.Descending {
+		return nil, entities.ErrInvalidPageToken
+	}
+
+	return &SortKey{Key: cursor.Key, ID: cursor.ID}, nil
+}
+
+// NextPageToken returns the token of the page following the item with the sort key
+func NextPageToken(page entities.PageRequest, last SortKey) string {
+	return entities.EncodePageToken(entities.PageCursor{
+		OrderBy:    page.OrderBy,
+		Descending: page.Descending,
+		Key:        last.Key,
+		ID:         last.ID,
+	})
+}
@@ -0,0 +1,95 @@
+package document
+
+import (
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"strings"
+	"time"
+)
+
+// Layouts of the day of the quota and the month of the AI spend
+const (
+	QuotaDateLayout  = "2006-01-02"
+	SpendMonthLayout = "2006-01"
+)
+
+// Names of the stored usage counters, the quota of a day and the lookups of an adapter are counted under
+// the prefix followed by the day or the adapter. The since counter holds the start of the counting in
+// nanoseconds since the epoch.
+const (
+	SinceCounter         = "since"
+	CacheHitsCounter     = "cache.hits"
+	CacheMissesCounter   = "cache.misses"
+	AICallsCounter       = "ai.calls"
+	AIErrorsCounter      = "ai.errors"
+	GroupsAddedCounter   = "churn.groupsAdded"
+	GroupsRemovedCounter = "churn.groupsRemoved"
+	BlockedCounter       = "churn.blocked"
+	UnblockedCounter     = "churn.unblocked"
+	QuotaCounters        = "quota."
+	AdapterCounters      = "adapter."
+)
+
+// ChurnCounters maps the chat member events to their counters
+var ChurnCounters = map[entities.ChatMemberEvent]string{
+	entities.ChatMemberAddedToGroup:     GroupsAddedCounter,
+	entities.ChatMemberRemovedFromGroup: GroupsRemovedCounter,
+	entities.ChatMemberBlocked:          BlockedCounter,
+	entities.ChatMemberUnblocked:        UnblockedCounter,
+}
+
+// AICallCounters returns the counters incremented by an AI call at the time
+func AICallCounters(at time.Time, failed bool) []string {
+	counters := []string{AICallsCounter, QuotaCounters + at.UTC().Format(QuotaDateLayout)}
+	if failed {
+		counters = append(counters, AIErrorsCounter)
+	}
+
+	return counters
+}
+
+// Dashboard returns the metrics of the counters at the time, the top words, the spend and the active users
+// are left to the backend. Without a since counter the counting starts now.
+func Dashboard(counters map[string]int64, now time.Time) *entities.DashboardStats {
+	now = now.UTC()
+	stats := &entities.DashboardStats{
+		GeneratedAt:      now,
+		Since:            now,
+		LookupsByAdapter: make(map[entities.Adapter]int64),
+		Cache: entities.CacheStats{
+			Hits:    counters[CacheHitsCounter],
+			Misses:  counters[CacheMissesCounter],
+			HitRate: ratio(counters[CacheHitsCounter], counters[CacheHitsCounter]+counters[CacheMissesCounter]),
+		},
+		AI: entities.AIStats{
+			Calls:     counters[AICallsCounter],
+			Errors:    counters[AIErrorsCounter],
+			ErrorRate: ratio(counters[AIErrorsCounter], counters[AICallsCounter]),
+		},
+		Quota: entities.QuotaStats{Date: now.Format(QuotaDateLayout)},
+		Spend: entities.SpendStats{Month: now.Format(SpendMonthLayout)},
+	}
+	if since, ok := counters[SinceCounter]; ok {
+		stats.Since = time.Unix(0, since).UTC()
+	}
+	stats.Telegram.Churn = entities.ChurnStats{
+		GroupsAdded:   counters[GroupsAddedCounter],
+		GroupsRemoved: counters[GroupsRemovedCounter],
+		Blocked:       counters[BlockedCounter],
+		Unblocked:     counters[UnblockedCounter],
+	}
+	stats.Quota.Used = counters[QuotaCounters+stats.Quota.Date]
+	for name, value := range counters {
+		if adapter, ok := strings.CutPrefix(name, AdapterCounters); ok {
+			stats.LookupsByAdapter[entities.Adapter(adapter)] = value
+		}
+	}
+
+	return stats
+}
+
+func ratio(part, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total)
+}
@@ -0,0 +1,33 @@
+package document
+
+import (
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"strings"
+)
+
+// WordOrder maps the ordering fields of word lists to the sort keys of a word
+var WordOrder = map[string]func(stat entities.WordStat) SortKey{
+	"lookups": func(stat entities.WordStat) SortKey {
+		return SortKey{Key: CountKey(stat.Lookups), ID: stat.Word}
+	},
+	"word": func(stat entities.WordStat) SortKey {
+		return SortKey{Key: stat.Word, ID: stat.Word}
+	},
+}
+
+// WordPage returns the page of the filter with its order, most looked-up first unless the page orders them otherwise
+func WordPage(filter entities.WordStatFilter) (entities.PageRequest, func(stat entities.WordStat) SortKey) {
+	page := filter.Page
+	keyOf, ok := WordOrder[page.OrderBy]
+	if !ok {
+		page.OrderBy, page.Descending = "lookups", true
+		keyOf = WordOrder[page.OrderBy]
+	}
+
+	return page, keyOf
+}
+
+// WordKey normalizes a looked-up word into the key its lookups are counted by, empty for blank words
+func WordKey(word string) string {
+	return strings.ToLower(strings.TrimSpace(word))
+}
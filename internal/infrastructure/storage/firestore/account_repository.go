@@ -0,0 +1,166 @@
+package firestore
+
+import (
+	gcfirestore "cloud.google.com/go/firestore"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"sort"
+	"time"
+)
+
+// accountEntry is a stored link code, token or identity, the documents of a user are queried by userId
+// with the single-field index Firestore creates by default
+type accountEntry struct {
+	UserID    int64     `firestore:"userId"`
+	Provider  string    `firestore:"provider,omitempty"`
+	Subject   string    `firestore:"subject,omitempty"`
+	ExpiresAt time.Time `firestore:"expiresAt,omitempty"`
+}
+
+// AccountRepository keeps the link codes, the API token hashes and the linked identities in Firestore
+// collections. The codes and the token hashes name their documents, the identities are named by the hash of
+// the provider and the subject. A TTL policy on expiresAt removes the expired link codes.
+type AccountRepository struct {
+	client *Client
+}
+
+// NewAccountRepository creates a new Firestore account repository
+func NewAccountRepository(client *Client) *AccountRepository {
+	return &AccountRepository{client: client}
+}
+
+// SaveLinkCode stores the one-time code of the user for ttl, the previous code of the user is dropped
+func (r *AccountRepository) SaveLinkCode(ctx context.Context, code string, userID int64, ttl time.Duration) error {
+	linkCodes := r.client.client.Collection(linkCodesCollection)
+	if _, err := deleteAll(ctx, r.client.client, linkCodes.Where("userId", "==", userID)); err != nil {
+		return fmt.Errorf("failed to delete previous link code: %w", err)
+	}
+
+	_, err := linkCodes.Doc(code).Set(ctx, accountEntry{UserID: userID, ExpiresAt: time.Now().Add(ttl)})
+	return err
+}
+
+// TakeLinkCode returns the user of the code and deletes it in a transaction, so a code is taken once,
+// false for unknown and expired codes
+func (r *AccountRepository) TakeLinkCode(ctx context.Context, code string) (int64, bool, error) {
+	ref := r.client.client.Collection(linkCodesCollection).Doc(code)
+	var (
+		stored accountEntry
+		found  bool
+	)
+	err := r.client.client.RunTransaction(ctx, func(ctx context.Context, tx *gcfirestore.Transaction) error {
+		found = false
+		snapshot, err := tx.Get(ref)
+		if status.Code(err) == codes.NotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := snapshot.DataTo(&stored); err != nil {
+			return fmt.Errorf("invalid link code: %w", err)
+		}
+		found = true
+		return tx.Delete(ref)
+	})
+	if err != nil || !found {
+		return 0, false, err
+	}
+
+	return stored.UserID, time.Now().Before(stored.ExpiresAt), nil
+}
+
+// SaveToken binds the token hash to the user, replacing the previous token of the user
+func (r *AccountRepository) SaveToken(ctx context.Context, tokenHash string, userID int64) error {
+	tokens := r.client.client.Collection(tokensCollection)
+	if _, err := deleteAll(ctx, r.client.client, tokens.Where("userId", "==", userID)); err != nil {
+		return fmt.Errorf("failed to delete previous token: %w", err)
+	}
+
+	_, err := tokens.Doc(tokenHash).Set(ctx, accountEntry{UserID: userID})
+	return err
+}
+
+// FindToken returns the user of the token hash, false for unknown tokens
+func (r *AccountRepository) FindToken(ctx context.Context, tokenHash string) (int64, bool, error) {
+	return findUser(ctx, r.client.client.Collection(tokensCollection).Doc(tokenHash))
+}
+
+// SaveIdentity binds the external identity of the provider to the user
+func (r *AccountRepository) SaveIdentity(ctx context.Context, provider, subject string, userID int64) error {
+	_, err := r.client.client.Collection(identitiesCollection).Doc(hashID(provider+":"+subject)).
+		Set(ctx, accountEntry{UserID: userID, Provider: provider, Subject: subject})
+	return err
+}
+
+// FindIdentity returns the user of the external identity, false for identities not linked to the bot
+func (r *AccountRepository) FindIdentity(ctx context.Context, provider, subject string) (int64, bool, error) {
+	return findUser(ctx, r.client.client.Collection(identitiesCollection).Doc(hashID(provider+":"+subject)))
+}
+
+// Identities returns the external identities linked to the user ordered by provider and subject, they are
+// sorted here to do without a composite index
+func (r *AccountRepository) Identities(ctx context.Context, userID int64) ([]entities.Identity, error) {
+	documents := r.client.client.Collection(identitiesCollection).Where("userId", "==", userID).Documents(ctx)
+	defer documents.Stop()
+
+	identities := make([]entities.Identity, 0)
+	for {
+		snapshot, err := documents.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list identities: %w", err)
+		}
+
+		var stored accountEntry
+		if err := snapshot.DataTo(&stored); err != nil {
+			return nil, fmt.Errorf("invalid identity %s: %w", snapshot.Ref.ID, err)
+		}
+		identities = append(identities, entities.Identity{UserID: userID, Provider: stored.Provider, Subject: stored.Subject})
+	}
+	sort.Slice(identities, func(i, j int) bool {
+		if identities[i].Provider != identities[j].Provider {
+			return identities[i].Provider < identities[j].Provider
+		}
+		return identities[i].Subject < identities[j].Subject
+	})
+
+	return identities, nil
+}
+
+// DeleteUser removes the link code, the token and the external identities of the user
+func (r *AccountRepository) DeleteUser(ctx context.Context, userID int64) error {
+	for _, collection := range []string{linkCodesCollection, tokensCollection, identitiesCollection} {
+		query := r.client.client.Collection(collection).Where("userId", "==", userID)
+		if _, err := deleteAll(ctx, r.client.client, query); err != nil {
+			return fmt.Errorf("failed to delete %s of user %d: %w", collection, userID, err)
+		}
+	}
+
+	return nil
+}
+
+// findUser reads the user ID of the document, ok is false for missing documents
+func findUser(ctx context.Context, ref *gcfirestore.DocumentRef) (int64, bool, error) {
+	snapshot, err := ref.Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	var stored accountEntry
+	if err := snapshot.DataTo(&stored); err != nil {
+		return 0, false, fmt.Errorf("invalid document %s: %w", ref.ID, err)
+	}
+
+	return stored.UserID, true, nil
+}
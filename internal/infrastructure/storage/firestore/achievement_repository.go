@@ -0,0 +1,98 @@
+package firestore
+
+import (
+	gcfirestore "cloud.google.com/go/firestore"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"sort"
+)
+
+// achievementEntry is a stored achievement, the achievements of a user are queried by userId with the
+// single-field index Firestore creates by default
+type achievementEntry struct {
+	UserID int64  `firestore:"userId"`
+	Data   []byte `firestore:"data"`
+}
+
+// AchievementRepository keeps the achievements of users in a Firestore collection, one document per user
+// and achievement named by both IDs, so an achievement is awarded once by creating its document
+type AchievementRepository struct {
+	client *Client
+}
+
+// NewAchievementRepository creates a new Firestore achievement repository
+func NewAchievementRepository(client *Client) *AchievementRepository {
+	return &AchievementRepository{client: client}
+}
+
+// Award stores the achievement of the user, false if the user already has it
+func (r *AchievementRepository) Award(ctx context.Context, id int64, achievement entities.Achievement) (bool, error) {
+	data, err := json.Marshal(achievement)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = r.collection().Doc(userID(id)+"_"+achievement.ID).Create(ctx, achievementEntry{UserID: id, Data: data})
+	if status.Code(err) == codes.AlreadyExists {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// List returns the achievements of the user, oldest first, they are sorted here to do without a composite index
+func (r *AchievementRepository) List(ctx context.Context, id int64) ([]entities.Achievement, error) {
+	documents := r.collection().Where("userId", "==", id).Documents(ctx)
+	defer documents.Stop()
+
+	var achievements []entities.Achievement
+	for {
+		snapshot, err := documents.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list achievements: %w", err)
+		}
+
+		var stored achievementEntry
+		if err := snapshot.DataTo(&stored); err != nil {
+			return nil, fmt.Errorf("invalid achievement %s: %w", snapshot.Ref.ID, err)
+		}
+		var achievement entities.Achievement
+		if err := json.Unmarshal(stored.Data, &achievement); err != nil {
+			return nil, fmt.Errorf("invalid achievement %s: %w", snapshot.Ref.ID, err)
+		}
+		achievements = append(achievements, achievement)
+	}
+	sort.Slice(achievements, func(i, j int) bool {
+		if !achievements[i].AwardedAt.Equal(achievements[j].AwardedAt) {
+			return achievements[i].AwardedAt.Before(achievements[j].AwardedAt)
+		}
+		return achievements[i].ID < achievements[j].ID
+	})
+
+	return achievements, nil
+}
+
+// Delete removes the achievements of the user
+func (r *AchievementRepository) Delete(ctx context.Context, id int64) error {
+	if _, err := deleteAll(ctx, r.client.client, r.collection().Where("userId", "==", id)); err != nil {
+		return fmt.Errorf("failed to delete achievements of user %d: %w", id, err)
+	}
+
+	return nil
+}
+
+func (r *AchievementRepository) collection() *gcfirestore.CollectionRef {
+	return r.client.client.Collection(achievementsCollection)
+}
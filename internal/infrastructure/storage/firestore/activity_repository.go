@@ -0,0 +1,179 @@
+package firestore
+
+import (
+	gcfirestore "cloud.google.com/go/firestore"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/storage/document"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+// ActivityRepository keeps the learning activity of users in a Firestore collection, one document per user
+// updated in transactions, so concurrent instances don't lose each other's counts
+type ActivityRepository struct {
+	client *Client
+}
+
+// NewActivityRepository creates a new Firestore activity repository
+func NewActivityRepository(client *Client) *ActivityRepository {
+	return &ActivityRepository{client: client}
+}
+
+// RecordLookup counts the word for the day of at in its time zone, once per word and day, and keeps its answer
+func (r *ActivityRepository) RecordLookup(ctx context.Context, userID int64, word entities.LookedUpWord, at time.Time) error {
+	return r.update(ctx, userID, func(activity *document.Activity) bool {
+		return activity.RecordLookup(word, at)
+	})
+}
+
+// RecordQuizAnswer counts the answer for its day in its time zone and the article of the noun
+func (r *ActivityRepository) RecordQuizAnswer(ctx context.Context, userID int64, answer *entities.QuizAnswer) error {
+	return r.update(ctx, userID, func(activity *document.Activity) bool {
+		activity.RecordQuizAnswer(answer)
+		return true
+	})
+}
+
+// SearchWords returns at most limit words of the user matching the query, the best matches first
+func (r *ActivityRepository) SearchWords(ctx context.Context, id int64, query string, limit int) ([]entities.WordMatch, error) {
+	activity, ok, err := r.load(ctx, id)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	return activity.Search(query, limit), nil
+}
+
+// Activity returns the activity of the user, empty if none was recorded
+func (r *ActivityRepository) Activity(ctx context.Context, id int64) (*entities.UserActivity, error) {
+	activity, ok, err := r.load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return &entities.UserActivity{UserID: id}, nil
+	}
+
+	return activity.Entity(id), nil
+}
+
+// load reads the stored activity of the user, ok is false if none was recorded
+func (r *ActivityRepository) load(ctx context.Context, id int64) (*document.Activity, bool, error) {
+	stored, ok, err := get(ctx, r.collection().Doc(userID(id)))
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	activity := document.NewActivity()
+	if err := json.Unmarshal(stored.Data, activity); err != nil {
+		return nil, false, fmt.Errorf("invalid activity of user %d: %w", id, err)
+	}
+
+	return activity, true, nil
+}
+
+// Delete removes the activity of the user, unknown users are ignored
+func (r *ActivityRepository) Delete(ctx context.Context, id int64) error {
+	_, err := r.collection().Doc(userID(id)).Delete(ctx)
+	return err
+}
+
+// DeleteInactive removes the activity of the users last active before the time and returns their number.
+// The activity is kept in the JSON of the documents, so all of them are read. A document is deleted only
+// if it wasn't updated since it was read, users active meanwhile keep their activity.
+func (r *ActivityRepository) DeleteInactive(ctx context.Context, before time.Time) (int, error) {
+	writer := r.client.client.BulkWriter(ctx)
+	var jobs []*gcfirestore.BulkWriterJob
+	documents := r.collection().Documents(ctx)
+	defer documents.Stop()
+	for {
+		snapshot, err := documents.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			writer.End()
+			return 0, err
+		}
+
+		var stored entry
+		if err := snapshot.DataTo(&stored); err != nil {
+			writer.End()
+			return 0, fmt.Errorf("invalid activity %s: %w", snapshot.Ref.ID, err)
+		}
+		activity := document.NewActivity()
+		if err := json.Unmarshal(stored.Data, activity); err != nil {
+			writer.End()
+			return 0, fmt.Errorf("invalid activity %s: %w", snapshot.Ref.ID, err)
+		}
+		if !activity.ActiveAt.Before(before) {
+			continue
+		}
+
+		job, err := writer.Delete(snapshot.Ref, gcfirestore.LastUpdateTime(snapshot.UpdateTime))
+		if err != nil {
+			writer.End()
+			return 0, err
+		}
+		jobs = append(jobs, job)
+	}
+	writer.End()
+
+	removed := 0
+	for _, job := range jobs {
+		_, err := job.Results()
+		switch {
+		case status.Code(err) == codes.FailedPrecondition:
+		case err != nil:
+			return removed, err
+		default:
+			removed++
+		}
+	}
+
+	return removed, nil
+}
+
+// update applies the change to the activity of the user in a transaction, the document is written
+// only when the change reports a modification
+func (r *ActivityRepository) update(ctx context.Context, id int64, change func(activity *document.Activity) bool) error {
+	ref := r.collection().Doc(userID(id))
+
+	return r.client.client.RunTransaction(ctx, func(ctx context.Context, tx *gcfirestore.Transaction) error {
+		activity := document.NewActivity()
+		snapshot, err := tx.Get(ref)
+		switch {
+		case status.Code(err) == codes.NotFound:
+		case err != nil:
+			return err
+		default:
+			var stored entry
+			if err := snapshot.DataTo(&stored); err != nil {
+				return fmt.Errorf("invalid activity of user %d: %w", id, err)
+			}
+			if err := json.Unmarshal(stored.Data, activity); err != nil {
+				return fmt.Errorf("invalid activity of user %d: %w", id, err)
+			}
+		}
+
+		if !change(activity) {
+			return nil
+		}
+		data, err := json.Marshal(activity)
+		if err != nil {
+			return err
+		}
+
+		return tx.Set(ref, entry{Data: data})
+	})
+}
+
+func (r *ActivityRepository) collection() *gcfirestore.CollectionRef {
+	return r.client.client.Collection(activityCollection)
+}
@@ -0,0 +1,82 @@
+package firestore
+
+import (
+	gcfirestore "cloud.google.com/go/firestore"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"google.golang.org/api/iterator"
+	"time"
+)
+
+// auditEntry is a stored admin audit entry with the fields it is queried by
+type auditEntry struct {
+	Action string    `firestore:"action"`
+	At     time.Time `firestore:"at"`
+	Data   []byte    `firestore:"data"`
+}
+
+// AuditRepository keeps the admin audit log in a Firestore collection named by the entry IDs, entries are
+// only ever created. The entries of an action are listed with a composite index.
+type AuditRepository struct {
+	client *Client
+}
+
+// NewAuditRepository creates a new Firestore audit repository
+func NewAuditRepository(client *Client) *AuditRepository {
+	return &AuditRepository{client: client}
+}
+
+// Append stores the entry
+func (r *AuditRepository) Append(ctx context.Context, entry *entities.AuditEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection().Doc(entry.ID).Create(ctx, auditEntry{Action: string(entry.Action), At: entry.At, Data: data})
+	return err
+}
+
+// List returns at most limit entries of the action, of every action when it's empty, newest first
+func (r *AuditRepository) List(ctx context.Context, action entities.AuditAction, limit int) ([]*entities.AuditEntry, error) {
+	entries := make([]*entities.AuditEntry, 0)
+	if limit <= 0 {
+		return entries, nil
+	}
+
+	query := r.collection().Query
+	if action != "" {
+		query = query.Where("action", "==", string(action))
+	}
+	documents := query.OrderBy("at", gcfirestore.Desc).Limit(limit).Documents(ctx)
+	defer documents.Stop()
+
+	for {
+		snapshot, err := documents.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list audit entries: %w", err)
+		}
+
+		var stored auditEntry
+		if err := snapshot.DataTo(&stored); err != nil {
+			return nil, fmt.Errorf("invalid audit entry %s: %w", snapshot.Ref.ID, err)
+		}
+		var entry entities.AuditEntry
+		if err := json.Unmarshal(stored.Data, &entry); err != nil {
+			return nil, fmt.Errorf("invalid audit entry %s: %w", snapshot.Ref.ID, err)
+		}
+		entries = append(entries, &entry)
+	}
+
+	return entries, nil
+}
+
+func (r *AuditRepository) collection() *gcfirestore.CollectionRef {
+	return r.client.client.Collection(auditCollection)
+}
@@ -0,0 +1,69 @@
+package firestore
+
+import (
+	gcfirestore "cloud.google.com/go/firestore"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"time"
+)
+
+// CacheRepository keeps article responses in a Firestore collection, the documents are found by the hash of
+// their key and keep the key for the removal of the variants of a word
+type CacheRepository struct {
+	client *Client
+}
+
+// NewCacheRepository creates a new Firestore cache
+func NewCacheRepository(client *Client) *CacheRepository {
+	return &CacheRepository{client: client}
+}
+
+// Get returns the cached response if it has not expired
+func (r *CacheRepository) Get(ctx context.Context, key string) (*entities.ArticleResponse, bool, error) {
+	stored, ok, err := get(ctx, r.collection().Doc(hashID(key)))
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	var response entities.ArticleResponse
+	if err := json.Unmarshal(stored.Data, &response); err != nil {
+		return nil, false, fmt.Errorf("invalid cached response %q: %w", key, err)
+	}
+
+	return &response, true, nil
+}
+
+// Set stores the response for ttl
+func (r *CacheRepository) Set(ctx context.Context, key string, response *entities.ArticleResponse, ttl time.Duration) error {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection().Doc(hashID(key)).Set(ctx, entry{Key: key, Data: data, ExpiresAt: time.Now().Add(ttl)})
+	return err
+}
+
+// Delete removes the entry of the key and of its variants, e.g. the levels of a word for "haus|en"
+func (r *CacheRepository) Delete(ctx context.Context, key string) (int, error) {
+	removed, err := deleteAll(ctx, r.client.client, r.collection().Where("key", "==", key))
+	if err != nil {
+		return removed, err
+	}
+
+	// The variants continue the key after a "|", "}" is the character following it
+	variants, err := deleteAll(ctx, r.client.client, r.collection().Where("key", ">=", key+"|").Where("key", "<", key+"}"))
+
+	return removed + variants, err
+}
+
+// Clear removes every entry
+func (r *CacheRepository) Clear(ctx context.Context) (int, error) {
+	return deleteAll(ctx, r.client.client, r.collection().Query)
+}
+
+func (r *CacheRepository) collection() *gcfirestore.CollectionRef {
+	return r.client.client.Collection(cacheCollection)
+}
@@ -0,0 +1,129 @@
+package firestore
+
+import (
+	gcfirestore "cloud.google.com/go/firestore"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"sort"
+	"strconv"
+)
+
+// chatEntry is a stored chat, the opt-out has a field of its own so touching a chat keeps it
+type chatEntry struct {
+	Data     []byte `firestore:"data"`
+	OptedOut bool   `firestore:"optedOut"`
+}
+
+// ChatRepository keeps the known chats in a Firestore collection named by the chat IDs
+type ChatRepository struct {
+	client *Client
+}
+
+// NewChatRepository creates a new Firestore chat repository
+func NewChatRepository(client *Client) *ChatRepository {
+	return &ChatRepository{client: client}
+}
+
+// Touch adds the chat or updates its language and the time it was seen, the opt-out is kept
+func (r *ChatRepository) Touch(ctx context.Context, chat *entities.KnownChat) error {
+	data, err := json.Marshal(chat)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection().Doc(chatID(chat.ID)).Set(ctx, map[string]interface{}{"data": data}, gcfirestore.MergeAll)
+	return err
+}
+
+// SetOptOut sets whether the chat gets announcements, false if the chat is unknown
+func (r *ChatRepository) SetOptOut(ctx context.Context, id int64, optedOut bool) (bool, error) {
+	_, err := r.collection().Doc(chatID(id)).Update(ctx, []gcfirestore.Update{{Path: "optedOut", Value: optedOut}})
+	if status.Code(err) == codes.NotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Get returns the chat, false if it is unknown
+func (r *ChatRepository) Get(ctx context.Context, id int64) (*entities.KnownChat, bool, error) {
+	snapshot, err := r.collection().Doc(chatID(id)).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	chat, err := parseChat(snapshot)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return &chat, true, nil
+}
+
+// Delete forgets the chat, unknown chats are ignored
+func (r *ChatRepository) Delete(ctx context.Context, id int64) error {
+	_, err := r.collection().Doc(chatID(id)).Delete(ctx)
+	return err
+}
+
+// List returns all known chats ordered by ID, they are sorted here as the document names order them as strings
+func (r *ChatRepository) List(ctx context.Context) ([]entities.KnownChat, error) {
+	documents := r.collection().Documents(ctx)
+	defer documents.Stop()
+
+	chats := make([]entities.KnownChat, 0)
+	for {
+		snapshot, err := documents.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list chats: %w", err)
+		}
+
+		chat, err := parseChat(snapshot)
+		if err != nil {
+			return nil, err
+		}
+		chats = append(chats, chat)
+	}
+	sort.Slice(chats, func(i, j int) bool { return chats[i].ID < chats[j].ID })
+
+	return chats, nil
+}
+
+func (r *ChatRepository) collection() *gcfirestore.CollectionRef {
+	return r.client.client.Collection(chatsCollection)
+}
+
+// parseChat reads a chat with the opt-out of its field
+func parseChat(snapshot *gcfirestore.DocumentSnapshot) (entities.KnownChat, error) {
+	var chat entities.KnownChat
+	var stored chatEntry
+	if err := snapshot.DataTo(&stored); err != nil {
+		return chat, fmt.Errorf("invalid chat %s: %w", snapshot.Ref.ID, err)
+	}
+	if err := json.Unmarshal(stored.Data, &chat); err != nil {
+		return chat, fmt.Errorf("invalid chat %s: %w", snapshot.Ref.ID, err)
+	}
+	chat.OptedOut = stored.OptedOut
+
+	return chat, nil
+}
+
+// chatID names the document of the chat, group chats have negative IDs
+func chatID(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
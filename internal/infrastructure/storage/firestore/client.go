@@ -0,0 +1,166 @@
+// Package firestore keeps the repositories in the collections of a Firestore database
+package firestore
+
+import (
+	gcfirestore "cloud.google.com/go/firestore"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"strconv"
+	"time"
+)
+
+// Collections of the repositories
+const (
+	cacheCollection              = "cache"
+	preferencesCollection        = "preferences"
+	feedbackCollection           = "feedback"
+	jobsCollection               = "jobs"
+	activityCollection           = "activity"
+	promptsCollection            = "prompts"
+	remindersCollection          = "reminders"
+	dictionaryCollection         = "dictionary"
+	idempotencyCollection        = "idempotency"
+	linkCodesCollection          = "linkCodes"
+	tokensCollection             = "tokens"
+	identitiesCollection         = "identities"
+	leaderboardMembersCollection = "leaderboardMembers"
+	leaderboardScoresCollection  = "leaderboardScores"
+	achievementsCollection       = "achievements"
+	deadLettersCollection        = "deadLetters"
+	chatsCollection              = "chats"
+	auditCollection              = "audit"
+	statsCountersCollection      = "statsCounters"
+	statsWordsCollection         = "statsWords"
+	statsSpendCollection         = "statsSpend"
+	statsUsersCollection         = "statsUsers"
+	conversationsCollection      = "conversations"
+	repliesCollection            = "replies"
+)
+
+// entry is a stored document: the JSON of the entity and the expiration of the entries that expire,
+// a TTL policy on expiresAt lets Firestore remove them
+type entry struct {
+	Key       string    `firestore:"key,omitempty"`
+	Data      []byte    `firestore:"data"`
+	ExpiresAt time.Time `firestore:"expiresAt,omitempty"`
+}
+
+// expired reports whether the entry expired and is waiting for the TTL policy
+func (e *entry) expired() bool {
+	return !e.ExpiresAt.IsZero() && time.Now().After(e.ExpiresAt)
+}
+
+// Client is the Firestore database shared by the repositories
+type Client struct {
+	client    *gcfirestore.Client
+	projectID string
+}
+
+// NewClient connects to the default database of the project
+func NewClient(ctx context.Context, projectID string) (*Client, error) {
+	client, err := gcfirestore.NewClient(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Firestore client: %w", err)
+	}
+
+	return &Client{client: client, projectID: projectID}, nil
+}
+
+// Close closes the Firestore client
+func (c *Client) Close() error {
+	return c.client.Close()
+}
+
+// Name returns the dependency name
+func (c *Client) Name() string {
+	return "firestore"
+}
+
+// Critical reports that the stored answers and user data aren't available without Firestore
+func (c *Client) Critical() bool {
+	return true
+}
+
+// Check reads a single cached answer
+func (c *Client) Check(ctx context.Context) error {
+	documents := c.client.Collection(cacheCollection).Limit(1).Documents(ctx)
+	defer documents.Stop()
+	if _, err := documents.Next(); err != nil && !errors.Is(err, iterator.Done) {
+		return err
+	}
+
+	return nil
+}
+
+// get reads the entry of the document, ok is false for missing and expired entries
+func get(ctx context.Context, ref *gcfirestore.DocumentRef) (*entry, bool, error) {
+	snapshot, err := ref.Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	var stored entry
+	if err := snapshot.DataTo(&stored); err != nil {
+		return nil, false, fmt.Errorf("invalid document %s: %w", ref.ID, err)
+	}
+	if stored.expired() {
+		return nil, false, nil
+	}
+
+	return &stored, true, nil
+}
+
+// deleteAll removes the documents of the query and returns their number
+func deleteAll(ctx context.Context, client *gcfirestore.Client, query gcfirestore.Query) (int, error) {
+	writer := client.BulkWriter(ctx)
+	var jobs []*gcfirestore.BulkWriterJob
+	documents := query.Documents(ctx)
+	defer documents.Stop()
+	for {
+		document, err := documents.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			writer.End()
+			return 0, err
+		}
+		job, err := writer.Delete(document.Ref)
+		if err != nil {
+			writer.End()
+			return 0, err
+		}
+		jobs = append(jobs, job)
+	}
+	writer.End()
+
+	removed := 0
+	for _, job := range jobs {
+		if _, err := job.Results(); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+
+	return removed, nil
+}
+
+// hashID returns a document ID for a key that may contain characters Firestore doesn't allow in IDs
+func hashID(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// userID returns the document ID of a user
+func userID(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
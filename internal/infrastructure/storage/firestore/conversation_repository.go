@@ -0,0 +1,57 @@
+package firestore
+
+import (
+	gcfirestore "cloud.google.com/go/firestore"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"time"
+)
+
+// ConversationRepository keeps the last word of every chat in a Firestore collection named by the chat
+// IDs, a TTL policy on expiresAt removes the expired conversations
+type ConversationRepository struct {
+	client *Client
+}
+
+// NewConversationRepository creates a new Firestore conversation repository
+func NewConversationRepository(client *Client) *ConversationRepository {
+	return &ConversationRepository{client: client}
+}
+
+// Get returns the conversation of the chat, false if there is none or it has expired
+func (r *ConversationRepository) Get(ctx context.Context, id int64) (*entities.Conversation, bool, error) {
+	stored, ok, err := get(ctx, r.collection().Doc(chatID(id)))
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	var conversation entities.Conversation
+	if err := json.Unmarshal(stored.Data, &conversation); err != nil {
+		return nil, false, fmt.Errorf("invalid conversation of chat %d: %w", id, err)
+	}
+
+	return &conversation, true, nil
+}
+
+// Save replaces the conversation of the chat
+func (r *ConversationRepository) Save(ctx context.Context, conversation *entities.Conversation, ttl time.Duration) error {
+	data, err := json.Marshal(conversation)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection().Doc(chatID(conversation.ChatID)).Set(ctx, entry{Data: data, ExpiresAt: time.Now().Add(ttl)})
+	return err
+}
+
+// Delete forgets the conversation of the chat
+func (r *ConversationRepository) Delete(ctx context.Context, id int64) error {
+	_, err := r.collection().Doc(chatID(id)).Delete(ctx)
+	return err
+}
+
+func (r *ConversationRepository) collection() *gcfirestore.CollectionRef {
+	return r.client.client.Collection(conversationsCollection)
+}
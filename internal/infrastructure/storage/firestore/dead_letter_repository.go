@@ -0,0 +1,136 @@
+package firestore
+
+import (
+	gcfirestore "cloud.google.com/go/firestore"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"google.golang.org/api/iterator"
+	"sort"
+	"time"
+)
+
+// deadLetterEntry is a stored dead letter with the fields it is queried by
+type deadLetterEntry struct {
+	UserID   int64     `firestore:"userId"`
+	FailedAt time.Time `firestore:"failedAt"`
+	Data     []byte    `firestore:"data"`
+}
+
+// DeadLetterRepository keeps the failed Telegram updates in a Firestore collection named by their IDs until
+// they are replayed or purged by the retention sweep. The letters are queried with the single-field indexes
+// Firestore creates by default.
+type DeadLetterRepository struct {
+	client *Client
+}
+
+// NewDeadLetterRepository creates a new Firestore dead letter repository
+func NewDeadLetterRepository(client *Client) *DeadLetterRepository {
+	return &DeadLetterRepository{client: client}
+}
+
+// Save stores the dead letter, replacing the previous one with the same ID
+func (r *DeadLetterRepository) Save(ctx context.Context, letter *entities.DeadLetter) error {
+	data, err := json.Marshal(letter)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection().Doc(letter.ID).Set(ctx, deadLetterEntry{UserID: letter.UserID, FailedAt: letter.FailedAt, Data: data})
+	return err
+}
+
+// Get returns the dead letter
+func (r *DeadLetterRepository) Get(ctx context.Context, id string) (*entities.DeadLetter, bool, error) {
+	stored, ok, err := get(ctx, r.collection().Doc(id))
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	var letter entities.DeadLetter
+	if err := json.Unmarshal(stored.Data, &letter); err != nil {
+		return nil, false, fmt.Errorf("invalid dead letter %s: %w", id, err)
+	}
+
+	return &letter, true, nil
+}
+
+// List returns at most limit dead letters, oldest first
+func (r *DeadLetterRepository) List(ctx context.Context, limit int) ([]*entities.DeadLetter, error) {
+	if limit <= 0 {
+		return make([]*entities.DeadLetter, 0), nil
+	}
+
+	return r.list(ctx, r.collection().OrderBy("failedAt", gcfirestore.Asc).Limit(limit))
+}
+
+// Delete removes the dead letter, unknown IDs are ignored
+func (r *DeadLetterRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.collection().Doc(id).Delete(ctx)
+	return err
+}
+
+// ListUser returns the dead letters of the user, oldest first, they are sorted here to do without a composite index
+func (r *DeadLetterRepository) ListUser(ctx context.Context, id int64) ([]*entities.DeadLetter, error) {
+	letters, err := r.list(ctx, r.collection().Where("userId", "==", id))
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(letters, func(i, j int) bool { return letters[i].FailedAt.Before(letters[j].FailedAt) })
+
+	return letters, nil
+}
+
+// DeleteUser removes the dead letters of the user
+func (r *DeadLetterRepository) DeleteUser(ctx context.Context, id int64) error {
+	if _, err := deleteAll(ctx, r.client.client, r.collection().Where("userId", "==", id)); err != nil {
+		return fmt.Errorf("failed to delete dead letters of user %d: %w", id, err)
+	}
+
+	return nil
+}
+
+// DeleteBefore removes the dead letters that failed before the time and returns their number
+func (r *DeadLetterRepository) DeleteBefore(ctx context.Context, before time.Time) (int, error) {
+	count, err := deleteAll(ctx, r.client.client, r.collection().Where("failedAt", "<", before))
+	if err != nil {
+		return count, fmt.Errorf("failed to delete dead letters: %w", err)
+	}
+
+	return count, nil
+}
+
+// list reads the dead letters of the query
+func (r *DeadLetterRepository) list(ctx context.Context, query gcfirestore.Query) ([]*entities.DeadLetter, error) {
+	documents := query.Documents(ctx)
+	defer documents.Stop()
+
+	letters := make([]*entities.DeadLetter, 0)
+	for {
+		snapshot, err := documents.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list dead letters: %w", err)
+		}
+
+		var stored deadLetterEntry
+		if err := snapshot.DataTo(&stored); err != nil {
+			return nil, fmt.Errorf("invalid dead letter %s: %w", snapshot.Ref.ID, err)
+		}
+		var letter entities.DeadLetter
+		if err := json.Unmarshal(stored.Data, &letter); err != nil {
+			return nil, fmt.Errorf("invalid dead letter %s: %w", snapshot.Ref.ID, err)
+		}
+		letters = append(letters, &letter)
+	}
+
+	return letters, nil
+}
+
+func (r *DeadLetterRepository) collection() *gcfirestore.CollectionRef {
+	return r.client.client.Collection(deadLettersCollection)
+}
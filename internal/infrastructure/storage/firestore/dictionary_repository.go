@@ -0,0 +1,69 @@
+package firestore
+
+import (
+	gcfirestore "cloud.google.com/go/firestore"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+)
+
+// DictionaryRepository keeps the curated dictionary entries in a Firestore collection, one document per
+// noun found by the hash of the lower-cased noun
+type DictionaryRepository struct {
+	client *Client
+}
+
+// NewDictionaryRepository creates a new Firestore dictionary repository
+func NewDictionaryRepository(client *Client) *DictionaryRepository {
+	return &DictionaryRepository{client: client}
+}
+
+// Get returns the entry of the noun, nil for nouns without one
+func (r *DictionaryRepository) Get(ctx context.Context, word string) (*entities.CuratedEntry, error) {
+	key := entities.CuratedKey(word)
+	stored, ok, err := get(ctx, r.collection().Doc(hashID(key)))
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	var entry entities.CuratedEntry
+	if err := json.Unmarshal(stored.Data, &entry); err != nil {
+		return nil, fmt.Errorf("invalid dictionary entry %q: %w", word, err)
+	}
+
+	return &entry, nil
+}
+
+// Save replaces the entries of their nouns, the writes are batched but not atomic
+func (r *DictionaryRepository) Save(ctx context.Context, entries []entities.CuratedEntry) error {
+	writer := r.client.client.BulkWriter(ctx)
+	jobs := make([]*gcfirestore.BulkWriterJob, 0, len(entries))
+	for _, curated := range entries {
+		data, err := json.Marshal(curated)
+		if err != nil {
+			writer.End()
+			return err
+		}
+		key := curated.Key()
+		job, err := writer.Set(r.collection().Doc(hashID(key)), entry{Key: key, Data: data})
+		if err != nil {
+			writer.End()
+			return err
+		}
+		jobs = append(jobs, job)
+	}
+	writer.End()
+
+	for _, job := range jobs {
+		if _, err := job.Results(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *DictionaryRepository) collection() *gcfirestore.CollectionRef {
+	return r.client.client.Collection(dictionaryCollection)
+}
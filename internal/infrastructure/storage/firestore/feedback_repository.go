@@ -0,0 +1,183 @@
+package firestore
+
+import (
+	gcfirestore "cloud.google.com/go/firestore"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/storage/document"
+	"google.golang.org/api/iterator"
+	"sort"
+	"strings"
+	"time"
+)
+
+// feedbackDocument is a stored rating, the filtered and ordered fields are kept next to its JSON
+type feedbackDocument struct {
+	ID       string `firestore:"id"`
+	Verdict  string `firestore:"verdict"`
+	Word     string `firestore:"word"`
+	Language string `firestore:"language"`
+	UserID   int64  `firestore:"userId,omitempty"`
+	// CreatedAt is the sort key of the creation time
+	CreatedAt string `firestore:"createdAt"`
+	Data      []byte `firestore:"data"`
+}
+
+// feedbackFields maps the ordering fields of feedback lists to the fields of the documents
+var feedbackFields = map[string]string{
+	"createdAt": "createdAt",
+	"word":      "word",
+}
+
+// FeedbackRepository keeps user ratings in a Firestore collection. The lists are queried natively,
+// so every combination of the filters with an order needs its composite index.
+type FeedbackRepository struct {
+	client *Client
+}
+
+// NewFeedbackRepository creates a new Firestore feedback repository
+func NewFeedbackRepository(client *Client) *FeedbackRepository {
+	return &FeedbackRepository{client: client}
+}
+
+// Save stores the feedback
+func (r *FeedbackRepository) Save(ctx context.Context, feedback *entities.Feedback) error {
+	data, err := json.Marshal(feedback)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection().Doc(feedback.ID).Set(ctx, feedbackDocument{
+		ID:        feedback.ID,
+		Verdict:   string(feedback.Verdict),
+		Word:      strings.ToLower(feedback.Word),
+		Language:  feedback.Language,
+		UserID:    feedback.UserID,
+		CreatedAt: document.TimeKey(feedback.CreatedAt),
+		Data:      data,
+	})
+	return err
+}
+
+// List returns a page of the matching feedback, newest first unless the page orders it otherwise
+func (r *FeedbackRepository) List(ctx context.Context, filter entities.FeedbackFilter) (entities.Page[*entities.Feedback], error) {
+	page, keyOf := document.FeedbackPage(filter)
+	after, err := document.After(page)
+	if err != nil {
+		return entities.Page[*entities.Feedback]{}, err
+	}
+
+	query := r.collection().Query
+	if filter.Verdict != "" {
+		query = query.Where("verdict", "==", string(filter.Verdict))
+	}
+	if filter.Word != "" {
+		query = query.Where("word", "==", strings.ToLower(filter.Word))
+	}
+	if filter.Language != "" {
+		query = query.Where("language", "==", filter.Language)
+	}
+	direction := gcfirestore.Asc
+	if page.Descending {
+		direction = gcfirestore.Desc
+	}
+	// Ties are broken by ascending ID as in the lists of every backend
+	query = query.OrderBy(feedbackFields[page.OrderBy], direction).OrderBy("id", gcfirestore.Asc)
+	if after != nil {
+		query = query.StartAfter(after.Key, after.ID)
+	}
+	if page.Limit > 0 {
+		// One more rating tells whether a next page exists
+		query = query.Limit(page.Limit + 1)
+	}
+
+	result := entities.Page[*entities.Feedback]{Items: make([]*entities.Feedback, 0)}
+	documents := query.Documents(ctx)
+	defer documents.Stop()
+	for {
+		snapshot, err := documents.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return entities.Page[*entities.Feedback]{}, fmt.Errorf("failed to list feedback: %w", err)
+		}
+		if page.Limit > 0 && len(result.Items) == page.Limit {
+			result.NextPageToken = document.NextPageToken(page, keyOf(result.Items[len(result.Items)-1]))
+			break
+		}
+
+		var stored feedbackDocument
+		if err := snapshot.DataTo(&stored); err != nil {
+			return entities.Page[*entities.Feedback]{}, fmt.Errorf("invalid feedback %s: %w", snapshot.Ref.ID, err)
+		}
+		var feedback entities.Feedback
+		if err := json.Unmarshal(stored.Data, &feedback); err != nil {
+			return entities.Page[*entities.Feedback]{}, fmt.Errorf("invalid feedback %s: %w", snapshot.Ref.ID, err)
+		}
+		result.Items = append(result.Items, &feedback)
+	}
+
+	return result, nil
+}
+
+// ListUser returns the ratings of the user, oldest first. They are sorted after reading, so the query
+// needs no composite index.
+func (r *FeedbackRepository) ListUser(ctx context.Context, userID int64) ([]*entities.Feedback, error) {
+	result := make([]*entities.Feedback, 0)
+	documents := r.collection().Where("userId", "==", userID).Documents(ctx)
+	defer documents.Stop()
+	for {
+		snapshot, err := documents.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list feedback of user %d: %w", userID, err)
+		}
+
+		var stored feedbackDocument
+		if err := snapshot.DataTo(&stored); err != nil {
+			return nil, fmt.Errorf("invalid feedback %s: %w", snapshot.Ref.ID, err)
+		}
+		var feedback entities.Feedback
+		if err := json.Unmarshal(stored.Data, &feedback); err != nil {
+			return nil, fmt.Errorf("invalid feedback %s: %w", snapshot.Ref.ID, err)
+		}
+		result = append(result, &feedback)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].CreatedAt.Before(result[j].CreatedAt)
+		}
+		return result[i].ID < result[j].ID
+	})
+
+	return result, nil
+}
+
+// DeleteUser removes the ratings of the user
+func (r *FeedbackRepository) DeleteUser(ctx context.Context, userID int64) error {
+	if _, err := deleteAll(ctx, r.client.client, r.collection().Where("userId", "==", userID)); err != nil {
+		return fmt.Errorf("failed to delete feedback of user %d: %w", userID, err)
+	}
+
+	return nil
+}
+
+// DeleteBefore removes the ratings created before the time and returns their number
+func (r *FeedbackRepository) DeleteBefore(ctx context.Context, before time.Time) (int, error) {
+	removed, err := deleteAll(ctx, r.client.client, r.collection().Where("createdAt", "<", document.TimeKey(before)))
+	if err != nil {
+		return removed, fmt.Errorf("failed to delete feedback: %w", err)
+	}
+
+	return removed, nil
+}
+
+func (r *FeedbackRepository) collection() *gcfirestore.CollectionRef {
+	return r.client.client.Collection(feedbackCollection)
+}
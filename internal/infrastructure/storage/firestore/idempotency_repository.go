@@ -0,0 +1,85 @@
+package firestore
+
+import (
+	gcfirestore "cloud.google.com/go/firestore"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+// IdempotencyRepository keeps the responses of requests with idempotency keys in a Firestore collection,
+// one document per key named by its hash. A key is reserved in a transaction, so concurrent requests of
+// all instances get one reservation, and a TTL policy on expiresAt removes the expired keys.
+type IdempotencyRepository struct {
+	client *Client
+}
+
+// NewIdempotencyRepository creates a new Firestore idempotency repository
+func NewIdempotencyRepository(client *Client) *IdempotencyRepository {
+	return &IdempotencyRepository{client: client}
+}
+
+// Begin reserves the key for the request with the fingerprint and returns nil, or the record of
+// an earlier request with the key
+func (r *IdempotencyRepository) Begin(ctx context.Context, key, fingerprint string, ttl time.Duration) (*entities.IdempotentResponse, error) {
+	data, err := json.Marshal(&entities.IdempotentResponse{Fingerprint: fingerprint})
+	if err != nil {
+		return nil, err
+	}
+
+	ref := r.collection().Doc(hashID(key))
+	var earlier *entities.IdempotentResponse
+	err = r.client.client.RunTransaction(ctx, func(ctx context.Context, tx *gcfirestore.Transaction) error {
+		earlier = nil
+		snapshot, err := tx.Get(ref)
+		if err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+		if err == nil {
+			var stored entry
+			if err := snapshot.DataTo(&stored); err != nil {
+				return fmt.Errorf("invalid idempotency key: %w", err)
+			}
+			if !stored.expired() {
+				var response entities.IdempotentResponse
+				if err := json.Unmarshal(stored.Data, &response); err != nil {
+					return fmt.Errorf("invalid response of idempotency key: %w", err)
+				}
+				earlier = &response
+				return nil
+			}
+		}
+
+		return tx.Set(ref, entry{Key: key, Data: data, ExpiresAt: time.Now().Add(ttl)})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+
+	return earlier, nil
+}
+
+// Complete stores the response of the request holding the key
+func (r *IdempotencyRepository) Complete(ctx context.Context, key string, response *entities.IdempotentResponse, ttl time.Duration) error {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection().Doc(hashID(key)).Set(ctx, entry{Key: key, Data: data, ExpiresAt: time.Now().Add(ttl)})
+	return err
+}
+
+// Release frees the key of a failed request, so a retry is processed again
+func (r *IdempotencyRepository) Release(ctx context.Context, key string) error {
+	_, err := r.collection().Doc(hashID(key)).Delete(ctx)
+	return err
+}
+
+func (r *IdempotencyRepository) collection() *gcfirestore.CollectionRef {
+	return r.client.client.Collection(idempotencyCollection)
+}
@@ -0,0 +1,67 @@
+package firestore
+
+import (
+	gcfirestore "cloud.google.com/go/firestore"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"time"
+)
+
+// jobEntry is a stored job state with the user it works for, the jobs of a user are queried by userId with
+// the single-field index Firestore creates by default
+type jobEntry struct {
+	UserID    int64     `firestore:"userId,omitempty"`
+	Data      []byte    `firestore:"data"`
+	ExpiresAt time.Time `firestore:"expiresAt"`
+}
+
+// JobRepository keeps the states of background jobs in a Firestore collection, one document per job
+type JobRepository struct {
+	client *Client
+}
+
+// NewJobRepository creates a new Firestore job repository
+func NewJobRepository(client *Client) *JobRepository {
+	return &JobRepository{client: client}
+}
+
+// Save stores the status for ttl, replacing the previous one of the job
+func (r *JobRepository) Save(ctx context.Context, status *entities.JobStatus, ttl time.Duration) error {
+	data, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection().Doc(status.ID).Set(ctx, jobEntry{UserID: status.UserID, Data: data, ExpiresAt: time.Now().Add(ttl)})
+	return err
+}
+
+// Get returns the status of the job if it has not expired
+func (r *JobRepository) Get(ctx context.Context, id string) (*entities.JobStatus, bool, error) {
+	stored, ok, err := get(ctx, r.collection().Doc(id))
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	var status entities.JobStatus
+	if err := json.Unmarshal(stored.Data, &status); err != nil {
+		return nil, false, fmt.Errorf("invalid status of job %s: %w", id, err)
+	}
+
+	return &status, true, nil
+}
+
+// DeleteUser removes the states of the jobs working for the user
+func (r *JobRepository) DeleteUser(ctx context.Context, userID int64) error {
+	if _, err := deleteAll(ctx, r.client.client, r.collection().Where("userId", "==", userID)); err != nil {
+		return fmt.Errorf("failed to delete jobs of user %d: %w", userID, err)
+	}
+
+	return nil
+}
+
+func (r *JobRepository) collection() *gcfirestore.CollectionRef {
+	return r.client.client.Collection(jobsCollection)
+}
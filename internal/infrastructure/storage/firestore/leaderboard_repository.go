@@ -0,0 +1,180 @@
+package firestore
+
+import (
+	gcfirestore "cloud.google.com/go/firestore"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"google.golang.org/api/iterator"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// leaderboardTTL keeps the scores of a week after its last answer, long enough to summarize the previous week
+const leaderboardTTL = 14 * 24 * time.Hour
+
+// scoreEntry is the stored score of a member in a week of a chat, the answers are counted with increments
+type scoreEntry struct {
+	Week      string    `firestore:"week"`
+	ChatID    int64     `firestore:"chatId"`
+	UserID    int64     `firestore:"userId"`
+	Answers   int       `firestore:"answers"`
+	Correct   int       `firestore:"correct"`
+	ExpiresAt time.Time `firestore:"expiresAt"`
+}
+
+// LeaderboardRepository keeps the leaderboard members in a Firestore collection named by the user IDs and
+// the scores in a collection with one document per week, chat and member. The scores are queried with the
+// single-field indexes Firestore creates by default, a TTL policy on expiresAt removes them two weeks after
+// the last answer of the week.
+type LeaderboardRepository struct {
+	client *Client
+}
+
+// NewLeaderboardRepository creates a new Firestore leaderboard repository
+func NewLeaderboardRepository(client *Client) *LeaderboardRepository {
+	return &LeaderboardRepository{client: client}
+}
+
+// SaveMember adds the member or updates the display name of the member
+func (r *LeaderboardRepository) SaveMember(ctx context.Context, member *entities.LeaderboardMember) error {
+	data, err := json.Marshal(member)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.members().Doc(userID(member.UserID)).Set(ctx, entry{Data: data})
+	return err
+}
+
+// DeleteMember removes the member together with all scores of the member
+func (r *LeaderboardRepository) DeleteMember(ctx context.Context, id int64) error {
+	if _, err := r.members().Doc(userID(id)).Delete(ctx); err != nil {
+		return err
+	}
+	if _, err := deleteAll(ctx, r.client.client, r.scores().Where("userId", "==", id)); err != nil {
+		return fmt.Errorf("failed to delete leaderboard scores of user %d: %w", id, err)
+	}
+
+	return nil
+}
+
+// Member returns the member of the user, nil for users who didn't opt in
+func (r *LeaderboardRepository) Member(ctx context.Context, id int64) (*entities.LeaderboardMember, error) {
+	stored, ok, err := get(ctx, r.members().Doc(userID(id)))
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	var member entities.LeaderboardMember
+	if err := json.Unmarshal(stored.Data, &member); err != nil {
+		return nil, fmt.Errorf("invalid leaderboard member %d: %w", id, err)
+	}
+
+	return &member, nil
+}
+
+// RecordScore counts the answer of the member in the week of the chat
+func (r *LeaderboardRepository) RecordScore(ctx context.Context, week string, chatID int64, id int64, correct bool) error {
+	increment := 0
+	if correct {
+		increment = 1
+	}
+
+	ref := r.scores().Doc(week + "_" + strconv.FormatInt(chatID, 10) + "_" + userID(id))
+	_, err := ref.Set(ctx, map[string]interface{}{
+		"week":      week,
+		"chatId":    chatID,
+		"userId":    id,
+		"answers":   gcfirestore.Increment(1),
+		"correct":   gcfirestore.Increment(increment),
+		"expiresAt": time.Now().Add(leaderboardTTL),
+	}, gcfirestore.MergeAll)
+	return err
+}
+
+// Scores returns the scores of the week in the chat in no particular order
+func (r *LeaderboardRepository) Scores(ctx context.Context, week string, chatID int64) ([]entities.LeaderboardScore, error) {
+	stored, err := r.list(ctx, r.scores().Where("week", "==", week).Where("chatId", "==", chatID))
+	if err != nil {
+		return nil, err
+	}
+
+	scores := make([]entities.LeaderboardScore, 0, len(stored))
+	for _, score := range stored {
+		scores = append(scores, entities.LeaderboardScore{UserID: score.UserID, Answers: score.Answers, Correct: score.Correct})
+	}
+
+	return scores, nil
+}
+
+// DeleteChat removes the scores of the group chat in all weeks
+func (r *LeaderboardRepository) DeleteChat(ctx context.Context, chatID int64) error {
+	if _, err := deleteAll(ctx, r.client.client, r.scores().Where("chatId", "==", chatID)); err != nil {
+		return fmt.Errorf("failed to delete leaderboard scores of chat %d: %w", chatID, err)
+	}
+
+	return nil
+}
+
+// Chats returns the group chats with scores in the week, without LeaderboardGlobal. The chats are collected
+// from the scores of the week to do without a composite index.
+func (r *LeaderboardRepository) Chats(ctx context.Context, week string) ([]int64, error) {
+	stored, err := r.list(ctx, r.scores().Where("week", "==", week))
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[int64]bool)
+	var chats []int64
+	for _, score := range stored {
+		if score.ChatID == entities.LeaderboardGlobal || seen[score.ChatID] {
+			continue
+		}
+		seen[score.ChatID] = true
+		chats = append(chats, score.ChatID)
+	}
+	sort.Slice(chats, func(i, j int) bool { return chats[i] < chats[j] })
+
+	return chats, nil
+}
+
+// list reads the scores of the query, scores waiting for the TTL policy are skipped
+func (r *LeaderboardRepository) list(ctx context.Context, query gcfirestore.Query) ([]scoreEntry, error) {
+	documents := query.Documents(ctx)
+	defer documents.Stop()
+
+	var scores []scoreEntry
+	now := time.Now()
+	for {
+		snapshot, err := documents.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list leaderboard scores: %w", err)
+		}
+
+		var score scoreEntry
+		if err := snapshot.DataTo(&score); err != nil {
+			return nil, fmt.Errorf("invalid leaderboard score %s: %w", snapshot.Ref.ID, err)
+		}
+		if now.After(score.ExpiresAt) {
+			continue
+		}
+		scores = append(scores, score)
+	}
+
+	return scores, nil
+}
+
+func (r *LeaderboardRepository) members() *gcfirestore.CollectionRef {
+	return r.client.client.Collection(leaderboardMembersCollection)
+}
+
+func (r *LeaderboardRepository) scores() *gcfirestore.CollectionRef {
+	return r.client.client.Collection(leaderboardScoresCollection)
+}
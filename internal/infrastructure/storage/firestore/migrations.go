@@ -0,0 +1,230 @@
+package firestore
+
+import (
+	admin "cloud.google.com/go/firestore/apiv1/admin"
+	"cloud.google.com/go/firestore/apiv1/admin/adminpb"
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/storage/migration"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// migrationsCollection keeps the schema document tracking the applied version
+const migrationsCollection = "migrations"
+
+// migrations are the versioned index definitions, in the format of firestore.indexes.json of the Firebase CLI
+//
+//go:embed migrations/*.json
+var migrations embed.FS
+
+// indexDefinitions are the composite indexes and field overrides created by a migration
+type indexDefinitions struct {
+	Indexes []struct {
+		CollectionGroup string `json:"collectionGroup"`
+		QueryScope      string `json:"queryScope"`
+		Fields          []struct {
+			FieldPath string `json:"fieldPath"`
+			Order     string `json:"order"`
+		} `json:"fields"`
+	} `json:"indexes"`
+	FieldOverrides []struct {
+		CollectionGroup string `json:"collectionGroup"`
+		FieldPath       string `json:"fieldPath"`
+		// TTL lets Firestore remove the documents once the time of the field passed
+		TTL bool `json:"ttl"`
+	} `json:"fieldOverrides"`
+}
+
+// schema is the document tracking the applied version
+type schema struct {
+	Version   int64     `firestore:"version"`
+	Dirty     bool      `firestore:"dirty"`
+	AppliedAt time.Time `firestore:"appliedAt"`
+}
+
+// versionedDefinitions is a migration file with the version of its name, e.g. 000001_create_repositories.json
+type versionedDefinitions struct {
+	version uint
+	name    string
+}
+
+// Status returns the version of the schema without changing it
+func (c *Client) Status(ctx context.Context) (migration.Status, error) {
+	files, err := definitionFiles()
+	if err != nil {
+		return migration.Status{}, err
+	}
+	current, err := c.schema(ctx)
+	if err != nil {
+		return migration.Status{}, err
+	}
+
+	return c.status(current, files), nil
+}
+
+// Up creates the indexes and field overrides of the migrations the database is missing. Both are created
+// idempotently, so instances migrating concurrently don't conflict. The indexes are built by Firestore in
+// the background, queries needing them fail until they are ready.
+func (c *Client) Up(ctx context.Context) (migration.Status, error) {
+	files, err := definitionFiles()
+	if err != nil {
+		return migration.Status{}, err
+	}
+	current, err := c.schema(ctx)
+	if err != nil {
+		return migration.Status{}, err
+	}
+	if current.Dirty {
+		return c.status(current, files), fmt.Errorf("firestore schema is dirty at version %d, fix it and reset the %s document", current.Version, migrationsCollection)
+	}
+
+	var pending []versionedDefinitions
+	for _, file := range files {
+		if int64(file.version) > current.Version {
+			pending = append(pending, file)
+		}
+	}
+	if len(pending) == 0 {
+		return c.status(current, files), nil
+	}
+
+	client, err := admin.NewFirestoreAdminClient(ctx)
+	if err != nil {
+		return c.status(current, files), fmt.Errorf("failed to create Firestore admin client: %w", err)
+	}
+	defer client.Close()
+
+	for _, file := range pending {
+		current = schema{Version: int64(file.version), Dirty: true, AppliedAt: time.Now().UTC()}
+		if err := c.saveSchema(ctx, current); err != nil {
+			return c.status(current, files), err
+		}
+		if err := c.apply(ctx, client, file.name); err != nil {
+			return c.status(current, files), fmt.Errorf("failed to apply Firestore migration %s: %w", file.name, err)
+		}
+		current.Dirty = false
+		if err := c.saveSchema(ctx, current); err != nil {
+			return c.status(current, files), err
+		}
+	}
+
+	return c.status(current, files), nil
+}
+
+// apply creates the indexes and field overrides of the migration file, existing ones are kept
+func (c *Client) apply(ctx context.Context, client *admin.FirestoreAdminClient, name string) error {
+	data, err := migrations.ReadFile(path.Join("migrations", name))
+	if err != nil {
+		return err
+	}
+	var definitions indexDefinitions
+	if err := json.Unmarshal(data, &definitions); err != nil {
+		return fmt.Errorf("invalid index definitions: %w", err)
+	}
+
+	database := fmt.Sprintf("projects/%s/databases/(default)", c.projectID)
+	for _, definition := range definitions.Indexes {
+		index := &adminpb.Index{QueryScope: adminpb.Index_COLLECTION}
+		if definition.QueryScope == "COLLECTION_GROUP" {
+			index.QueryScope = adminpb.Index_COLLECTION_GROUP
+		}
+		for _, field := range definition.Fields {
+			order := adminpb.Index_IndexField_ASCENDING
+			if field.Order == "DESCENDING" {
+				order = adminpb.Index_IndexField_DESCENDING
+			}
+			index.Fields = append(index.Fields, &adminpb.Index_IndexField{
+				FieldPath: field.FieldPath,
+				ValueMode: &adminpb.Index_IndexField_Order_{Order: order},
+			})
+		}
+		_, err := client.CreateIndex(ctx, &adminpb.CreateIndexRequest{
+			Parent: database + "/collectionGroups/" + definition.CollectionGroup,
+			Index:  index,
+		})
+		if err != nil && status.Code(err) != codes.AlreadyExists {
+			return fmt.Errorf("failed to create index of %s: %w", definition.CollectionGroup, err)
+		}
+	}
+
+	for _, override := range definitions.FieldOverrides {
+		field := &adminpb.Field{Name: database + "/collectionGroups/" + override.CollectionGroup + "/fields/" + override.FieldPath}
+		if override.TTL {
+			field.TtlConfig = &adminpb.Field_TtlConfig{}
+		}
+		_, err := client.UpdateField(ctx, &adminpb.UpdateFieldRequest{
+			Field:      field,
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"ttl_config"}},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update field %s of %s: %w", override.FieldPath, override.CollectionGroup, err)
+		}
+	}
+
+	return nil
+}
+
+// schema reads the applied version, zero before the first migration
+func (c *Client) schema(ctx context.Context) (schema, error) {
+	var current schema
+	snapshot, err := c.client.Collection(migrationsCollection).Doc("schema").Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return current, nil
+	}
+	if err != nil {
+		return current, fmt.Errorf("failed to read Firestore schema version: %w", err)
+	}
+	if err := snapshot.DataTo(&current); err != nil {
+		return current, fmt.Errorf("invalid Firestore schema version: %w", err)
+	}
+
+	return current, nil
+}
+
+func (c *Client) saveSchema(ctx context.Context, current schema) error {
+	if _, err := c.client.Collection(migrationsCollection).Doc("schema").Set(ctx, current); err != nil {
+		return fmt.Errorf("failed to save Firestore schema version: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Client) status(current schema, files []versionedDefinitions) migration.Status {
+	result := migration.Status{Backend: "firestore", Version: uint(current.Version), Dirty: current.Dirty}
+	if len(files) > 0 {
+		result.Latest = files[len(files)-1].version
+	}
+
+	return result
+}
+
+// definitionFiles returns the migration files ordered by their version
+func definitionFiles() ([]versionedDefinitions, error) {
+	entries, err := fs.ReadDir(migrations, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]versionedDefinitions, 0, len(entries))
+	for _, entry := range entries {
+		prefix, _, _ := strings.Cut(entry.Name(), "_")
+		version, err := strconv.ParseUint(prefix, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Firestore migration name %s", entry.Name())
+		}
+		files = append(files, versionedDefinitions{version: uint(version), name: entry.Name()})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].version < files[j].version })
+
+	return files, nil
+}
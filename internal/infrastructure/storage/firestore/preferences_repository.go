@@ -0,0 +1,58 @@
+package firestore
+
+import (
+	gcfirestore "cloud.google.com/go/firestore"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+)
+
+// PreferencesRepository keeps user settings in a Firestore collection, one document per user
+type PreferencesRepository struct {
+	client *Client
+}
+
+// NewPreferencesRepository creates a new Firestore preferences repository
+func NewPreferencesRepository(client *Client) *PreferencesRepository {
+	return &PreferencesRepository{client: client}
+}
+
+// Get returns the preferences of the user, default preferences if none were saved
+func (r *PreferencesRepository) Get(ctx context.Context, id int64) (*entities.UserPreferences, error) {
+	stored, ok, err := get(ctx, r.collection().Doc(userID(id)))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return &entities.UserPreferences{UserID: id}, nil
+	}
+
+	var preferences entities.UserPreferences
+	if err := json.Unmarshal(stored.Data, &preferences); err != nil {
+		return nil, fmt.Errorf("invalid preferences of user %d: %w", id, err)
+	}
+
+	return &preferences, nil
+}
+
+// Save replaces the preferences of the user
+func (r *PreferencesRepository) Save(ctx context.Context, preferences *entities.UserPreferences) error {
+	data, err := json.Marshal(preferences)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection().Doc(userID(preferences.UserID)).Set(ctx, entry{Data: data})
+	return err
+}
+
+// Delete removes the preferences of the user
+func (r *PreferencesRepository) Delete(ctx context.Context, id int64) error {
+	_, err := r.collection().Doc(userID(id)).Delete(ctx)
+	return err
+}
+
+func (r *PreferencesRepository) collection() *gcfirestore.CollectionRef {
+	return r.client.client.Collection(preferencesCollection)
+}
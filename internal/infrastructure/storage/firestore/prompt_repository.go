@@ -0,0 +1,56 @@
+package firestore
+
+import (
+	gcfirestore "cloud.google.com/go/firestore"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+)
+
+// PromptRepository keeps the prompt overrides of the tenants in a Firestore collection, one document per
+// tenant named by its ID
+type PromptRepository struct {
+	client *Client
+}
+
+// NewPromptRepository creates a new Firestore prompt repository
+func NewPromptRepository(client *Client) *PromptRepository {
+	return &PromptRepository{client: client}
+}
+
+// Get returns the prompt overrides of the tenant, nil if none were saved
+func (r *PromptRepository) Get(ctx context.Context, tenantID string) (*entities.PromptOverrides, error) {
+	stored, ok, err := get(ctx, r.collection().Doc(tenantID))
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	var overrides entities.PromptOverrides
+	if err := json.Unmarshal(stored.Data, &overrides); err != nil {
+		return nil, fmt.Errorf("invalid prompt overrides of tenant %s: %w", tenantID, err)
+	}
+
+	return &overrides, nil
+}
+
+// Save replaces the prompt overrides of the tenant
+func (r *PromptRepository) Save(ctx context.Context, overrides *entities.PromptOverrides) error {
+	data, err := json.Marshal(overrides)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection().Doc(overrides.TenantID).Set(ctx, entry{Data: data})
+	return err
+}
+
+// Delete removes the prompt overrides of the tenant
+func (r *PromptRepository) Delete(ctx context.Context, tenantID string) error {
+	_, err := r.collection().Doc(tenantID).Delete(ctx)
+	return err
+}
+
+func (r *PromptRepository) collection() *gcfirestore.CollectionRef {
+	return r.client.client.Collection(promptsCollection)
+}
@@ -0,0 +1,94 @@
+package firestore
+
+import (
+	gcfirestore "cloud.google.com/go/firestore"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"google.golang.org/api/iterator"
+	"time"
+)
+
+// reminderEntry is a stored reminder, the time it's due is a field of its own to query the due reminders
+type reminderEntry struct {
+	Data   []byte    `firestore:"data"`
+	NextAt time.Time `firestore:"nextAt"`
+}
+
+// ReminderRepository keeps the practice reminders in a Firestore collection, one document per user named
+// by the user ID. The due reminders are queried with the single-field index Firestore creates by default.
+type ReminderRepository struct {
+	client *Client
+}
+
+// NewReminderRepository creates a new Firestore reminder repository
+func NewReminderRepository(client *Client) *ReminderRepository {
+	return &ReminderRepository{client: client}
+}
+
+// Get returns the reminder of the user, nil if none is set
+func (r *ReminderRepository) Get(ctx context.Context, id int64) (*entities.Reminder, error) {
+	stored, ok, err := get(ctx, r.collection().Doc(userID(id)))
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	var reminder entities.Reminder
+	if err := json.Unmarshal(stored.Data, &reminder); err != nil {
+		return nil, fmt.Errorf("invalid reminder of user %d: %w", id, err)
+	}
+
+	return &reminder, nil
+}
+
+// Save replaces the reminder of the user
+func (r *ReminderRepository) Save(ctx context.Context, reminder *entities.Reminder) error {
+	data, err := json.Marshal(reminder)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection().Doc(userID(reminder.UserID)).Set(ctx, reminderEntry{Data: data, NextAt: reminder.NextAt})
+	return err
+}
+
+// Delete removes the reminder of the user
+func (r *ReminderRepository) Delete(ctx context.Context, id int64) error {
+	_, err := r.collection().Doc(userID(id)).Delete(ctx)
+	return err
+}
+
+// Due returns at most limit reminders due at the time, the longest due first
+func (r *ReminderRepository) Due(ctx context.Context, now time.Time, limit int) ([]entities.Reminder, error) {
+	documents := r.collection().Where("nextAt", "<=", now).OrderBy("nextAt", gcfirestore.Asc).Limit(limit).Documents(ctx)
+	defer documents.Stop()
+
+	result := make([]entities.Reminder, 0)
+	for {
+		snapshot, err := documents.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list due reminders: %w", err)
+		}
+
+		var stored reminderEntry
+		if err := snapshot.DataTo(&stored); err != nil {
+			return nil, fmt.Errorf("invalid reminder %s: %w", snapshot.Ref.ID, err)
+		}
+		var reminder entities.Reminder
+		if err := json.Unmarshal(stored.Data, &reminder); err != nil {
+			return nil, fmt.Errorf("invalid reminder %s: %w", snapshot.Ref.ID, err)
+		}
+		result = append(result, reminder)
+	}
+
+	return result, nil
+}
+
+func (r *ReminderRepository) collection() *gcfirestore.CollectionRef {
+	return r.client.client.Collection(remindersCollection)
+}
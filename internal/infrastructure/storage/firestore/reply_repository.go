@@ -0,0 +1,53 @@
+package firestore
+
+import (
+	gcfirestore "cloud.google.com/go/firestore"
+	"context"
+	"strconv"
+	"time"
+)
+
+// ReplyRepository keeps the answers to the messages in a Firestore collection named by the chat and the
+// message IDs, a TTL policy on expiresAt removes them ttl after the answer
+type ReplyRepository struct {
+	client *Client
+	ttl    time.Duration
+}
+
+// NewReplyRepository creates a new Firestore reply repository keeping the answers for ttl
+func NewReplyRepository(client *Client, ttl time.Duration) *ReplyRepository {
+	return &ReplyRepository{client: client, ttl: ttl}
+}
+
+// Get returns the message ID of the answer to the message of the chat
+func (r *ReplyRepository) Get(ctx context.Context, chatID int64, messageID int) (int, bool, error) {
+	stored, ok, err := get(ctx, r.collection().Doc(replyID(chatID, messageID)))
+	if err != nil || !ok {
+		return 0, false, err
+	}
+
+	reply, err := strconv.Atoi(string(stored.Data))
+	if err != nil {
+		return 0, false, err
+	}
+
+	return reply, true, nil
+}
+
+// Save stores the answer to the message
+func (r *ReplyRepository) Save(ctx context.Context, chatID int64, messageID, reply int) error {
+	_, err := r.collection().Doc(replyID(chatID, messageID)).Set(ctx, entry{
+		Data:      []byte(strconv.Itoa(reply)),
+		ExpiresAt: time.Now().Add(r.ttl),
+	})
+	return err
+}
+
+func (r *ReplyRepository) collection() *gcfirestore.CollectionRef {
+	return r.client.client.Collection(repliesCollection)
+}
+
+// replyID names the document of the answer to the message of the chat
+func replyID(chatID int64, messageID int) string {
+	return strconv.FormatInt(chatID, 10) + "_" + strconv.Itoa(messageID)
+}
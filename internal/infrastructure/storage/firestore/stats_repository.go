@@ -0,0 +1,306 @@
+package firestore
+
+import (
+	gcfirestore "cloud.google.com/go/firestore"
+	"cloud.google.com/go/firestore/apiv1/firestorepb"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/storage/document"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"strconv"
+	"time"
+)
+
+// statsWord is a stored looked-up word with the number of its lookups
+type statsWord struct {
+	Word    string `firestore:"word"`
+	Lookups int64  `firestore:"lookups"`
+}
+
+// StatsRepository keeps the usage metrics in Firestore collections: a document per named counter, per
+// looked-up word found by the hash of the word, per month of AI spend and per Telegram user with the time
+// the user was last seen. The counting starts with the creation of the first counter. Recording never
+// fails the request, failures are logged.
+type StatsRepository struct {
+	client   *Client
+	callCost float64
+	logger   logging.Logger
+}
+
+// NewStatsRepository creates a new Firestore stats repository adding callCost USD to the monthly spend of every AI call
+func NewStatsRepository(client *Client, callCost float64, logger logging.Logger) *StatsRepository {
+	return &StatsRepository{client: client, callCost: callCost, logger: logger}
+}
+
+// RecordLookup increments the lookup counters of the word and of the adapter
+func (r *StatsRepository) RecordLookup(ctx context.Context, word, _ string, adapter entities.Adapter) {
+	key := document.WordKey(word)
+	if key == "" {
+		return
+	}
+
+	_, err := r.collection(statsWordsCollection).Doc(hashID(key)).Set(ctx, map[string]interface{}{
+		"word":    key,
+		"lookups": gcfirestore.Increment(1),
+	}, gcfirestore.MergeAll)
+	if err == nil && adapter != "" {
+		err = r.increment(ctx, document.AdapterCounters+string(adapter))
+	}
+	r.logFailure(ctx, err, "Failed to record lookup")
+}
+
+// RecordCacheLookup increments the cache hit or miss counter
+func (r *StatsRepository) RecordCacheLookup(ctx context.Context, hit bool) {
+	counter := document.CacheMissesCounter
+	if hit {
+		counter = document.CacheHitsCounter
+	}
+
+	r.logFailure(ctx, r.increment(ctx, counter), "Failed to record cache lookup")
+}
+
+// RecordAICall increments the AI call counters, the daily quota usage and the monthly spend
+func (r *StatsRepository) RecordAICall(ctx context.Context, failed bool) {
+	now := time.Now().UTC()
+	err := r.increment(ctx, document.AICallCounters(now, failed)...)
+	if err == nil {
+		// Failed calls are billed as well when the model has answered
+		_, err = r.collection(statsSpendCollection).Doc(now.Format(document.SpendMonthLayout)).Set(ctx, map[string]interface{}{
+			"spent": gcfirestore.Increment(r.callCost),
+		}, gcfirestore.MergeAll)
+	}
+	r.logFailure(ctx, err, "Failed to record AI call")
+}
+
+// MonthlySpend returns the estimated AI spend of the current month in USD
+func (r *StatsRepository) MonthlySpend(ctx context.Context) (float64, error) {
+	snapshot, err := r.collection(statsSpendCollection).Doc(time.Now().UTC().Format(document.SpendMonthLayout)).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read monthly spend: %w", err)
+	}
+
+	var stored struct {
+		Spent float64 `firestore:"spent"`
+	}
+	if err := snapshot.DataTo(&stored); err != nil {
+		return 0, fmt.Errorf("invalid monthly spend %s: %w", snapshot.Ref.ID, err)
+	}
+
+	return stored.Spent, nil
+}
+
+// RecordTelegramUser marks the Telegram user as active now
+func (r *StatsRepository) RecordTelegramUser(ctx context.Context, id int64) {
+	_, err := r.collection(statsUsersCollection).Doc(userID(id)).Set(ctx, map[string]interface{}{
+		"seenAt": time.Now(),
+	})
+	r.logFailure(ctx, err, "Failed to record Telegram user")
+}
+
+// DeleteUser forgets when the Telegram user was last seen
+func (r *StatsRepository) DeleteUser(ctx context.Context, id int64) error {
+	_, err := r.collection(statsUsersCollection).Doc(userID(id)).Delete(ctx)
+	return err
+}
+
+// RecordChatMemberEvent increments the churn counter of the event, unknown events are ignored
+func (r *StatsRepository) RecordChatMemberEvent(ctx context.Context, event entities.ChatMemberEvent) {
+	counter, ok := document.ChurnCounters[event]
+	if !ok {
+		return
+	}
+
+	r.logFailure(ctx, r.increment(ctx, counter), "Failed to record chat member event")
+}
+
+// Snapshot returns the current metrics with the given number of top words
+func (r *StatsRepository) Snapshot(ctx context.Context, topWords int) (*entities.DashboardStats, error) {
+	counters, err := r.counters(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	stats := document.Dashboard(counters, now)
+
+	query := r.collection(statsWordsCollection).OrderBy("lookups", gcfirestore.Desc).OrderBy("word", gcfirestore.Asc)
+	if topWords > 0 {
+		query = query.Limit(topWords)
+	}
+	if stats.TopWords, err = r.words(ctx, query); err != nil {
+		return nil, err
+	}
+	if stats.Spend.Spent, err = r.MonthlySpend(ctx); err != nil {
+		return nil, err
+	}
+	if stats.Telegram.ActiveUsers24h, err = r.activeUsers(ctx, now.Add(-24*time.Hour)); err != nil {
+		return nil, err
+	}
+	if stats.Telegram.ActiveUsers7d, err = r.activeUsers(ctx, now.Add(-7*24*time.Hour)); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// ListWords returns a page of the looked-up words, most looked-up first unless the page orders them otherwise.
+// The words of a prefix are read and sorted in full, as the range of the prefix can't be ordered by lookups.
+func (r *StatsRepository) ListWords(ctx context.Context, filter entities.WordStatFilter) (entities.Page[entities.WordStat], error) {
+	page, keyOf := document.WordPage(filter)
+	after, err := document.After(page)
+	if err != nil {
+		return entities.Page[entities.WordStat]{}, err
+	}
+
+	if prefix := document.WordKey(filter.Prefix); prefix != "" {
+		// The largest code point sorts after every word of the prefix
+		words, err := r.words(ctx, r.collection(statsWordsCollection).
+			Where("word", ">=", prefix).Where("word", "<", prefix+"\U0010FFFF"))
+		if err != nil {
+			return entities.Page[entities.WordStat]{}, err
+		}
+		return document.Paginate(words, page, keyOf)
+	}
+
+	direction := gcfirestore.Asc
+	if page.Descending {
+		direction = gcfirestore.Desc
+	}
+	// The ordering fields are named as the fields of the stored words, ties of lookups are broken by
+	// ascending word as in the lists of every backend
+	query := r.collection(statsWordsCollection).OrderBy(page.OrderBy, direction)
+	if page.OrderBy == "lookups" {
+		query = query.OrderBy("word", gcfirestore.Asc)
+	}
+	if after != nil {
+		cursor := []interface{}{after.ID}
+		if page.OrderBy == "lookups" {
+			lookups, err := strconv.ParseInt(after.Key, 10, 64)
+			if err != nil {
+				return entities.Page[entities.WordStat]{}, entities.ErrInvalidPageToken
+			}
+			cursor = []interface{}{lookups, after.ID}
+		}
+		query = query.StartAfter(cursor...)
+	}
+	if page.Limit > 0 {
+		// One more word tells whether a next page exists
+		query = query.Limit(page.Limit + 1)
+	}
+
+	words, err := r.words(ctx, query)
+	if err != nil {
+		return entities.Page[entities.WordStat]{}, err
+	}
+	result := entities.Page[entities.WordStat]{Items: words}
+	if page.Limit > 0 && len(words) > page.Limit {
+		result.Items = words[:page.Limit]
+		result.NextPageToken = document.NextPageToken(page, keyOf(result.Items[page.Limit-1]))
+	}
+
+	return result, nil
+}
+
+// counters reads every named counter, the since counter is the creation of the first of them
+func (r *StatsRepository) counters(ctx context.Context) (map[string]int64, error) {
+	counters := make(map[string]int64)
+	var since time.Time
+	documents := r.collection(statsCountersCollection).Documents(ctx)
+	defer documents.Stop()
+	for {
+		snapshot, err := documents.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read stats counters: %w", err)
+		}
+
+		var stored struct {
+			Value int64 `firestore:"value"`
+		}
+		if err := snapshot.DataTo(&stored); err != nil {
+			return nil, fmt.Errorf("invalid stats counter %s: %w", snapshot.Ref.ID, err)
+		}
+		counters[snapshot.Ref.ID] = stored.Value
+		if since.IsZero() || snapshot.CreateTime.Before(since) {
+			since = snapshot.CreateTime
+		}
+	}
+	if !since.IsZero() {
+		counters[document.SinceCounter] = since.UnixNano()
+	}
+
+	return counters, nil
+}
+
+// words reads the words of the query
+func (r *StatsRepository) words(ctx context.Context, query gcfirestore.Query) ([]entities.WordStat, error) {
+	words := make([]entities.WordStat, 0)
+	documents := query.Documents(ctx)
+	defer documents.Stop()
+	for {
+		snapshot, err := documents.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list words: %w", err)
+		}
+
+		var stored statsWord
+		if err := snapshot.DataTo(&stored); err != nil {
+			return nil, fmt.Errorf("invalid word %s: %w", snapshot.Ref.ID, err)
+		}
+		words = append(words, entities.WordStat{Word: stored.Word, Lookups: stored.Lookups})
+	}
+
+	return words, nil
+}
+
+// activeUsers counts the Telegram users seen since the time
+func (r *StatsRepository) activeUsers(ctx context.Context, since time.Time) (int, error) {
+	query := r.collection(statsUsersCollection).Where("seenAt", ">=", since)
+	result, err := query.NewAggregationQuery().WithCount("count").Get(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count active Telegram users: %w", err)
+	}
+	count, ok := result["count"].(*firestorepb.Value)
+	if !ok {
+		return 0, fmt.Errorf("invalid count of active Telegram users: %v", result["count"])
+	}
+
+	return int(count.GetIntegerValue()), nil
+}
+
+// increment adds one to every named counter
+func (r *StatsRepository) increment(ctx context.Context, names ...string) error {
+	for _, name := range names {
+		_, err := r.collection(statsCountersCollection).Doc(name).Set(ctx, map[string]interface{}{
+			"value": gcfirestore.Increment(1),
+		}, gcfirestore.MergeAll)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// logFailure logs a failure to record a metric, the request goes on without it
+func (r *StatsRepository) logFailure(ctx context.Context, err error, message string) {
+	if err != nil {
+		r.logger.With(ctx).Err(err).Sampled().Warning(message)
+	}
+}
+
+func (r *StatsRepository) collection(name string) *gcfirestore.CollectionRef {
+	return r.client.client.Collection(name)
+}
@@ -0,0 +1,184 @@
+package memory
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type linkCodeEntry struct {
+	userID    int64
+	expiresAt time.Time
+}
+
+// AccountRepository keeps link codes, API tokens and linked identities in memory of the running
+// instance, every user holds at most one code and one token
+type AccountRepository struct {
+	mu         sync.Mutex
+	codes      map[string]linkCodeEntry
+	userCodes  map[int64]string
+	tokens     map[string]int64
+	userTokens map[int64]string
+	identities map[string]int64
+	maxEntries int
+}
+
+// NewAccountRepository creates a new in-memory account repository holding at most maxEntries link codes
+func NewAccountRepository(maxEntries int) *AccountRepository {
+	return &AccountRepository{
+		codes:      make(map[string]linkCodeEntry),
+		userCodes:  make(map[int64]string),
+		tokens:     make(map[string]int64),
+		userTokens: make(map[int64]string),
+		identities: make(map[string]int64),
+		maxEntries: maxEntries,
+	}
+}
+
+// SaveLinkCode stores the one-time code of the user for ttl, the previous code of the user is dropped
+func (r *AccountRepository) SaveLinkCode(_ context.Context, code string, userID int64, ttl time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if previous, ok := r.userCodes[userID]; ok {
+		delete(r.codes, previous)
+	}
+	if r.maxEntries > 0 && len(r.codes) >= r.maxEntries {
+		r.evict(now)
+	}
+	r.codes[code] = linkCodeEntry{userID: userID, expiresAt: now.Add(ttl)}
+	r.userCodes[userID] = code
+
+	return nil
+}
+
+// TakeLinkCode returns the user of the code and deletes it, false for unknown and expired codes
+func (r *AccountRepository) TakeLinkCode(_ context.Context, code string) (int64, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.codes[code]
+	if !ok {
+		return 0, false, nil
+	}
+	delete(r.codes, code)
+	delete(r.userCodes, entry.userID)
+
+	return entry.userID, time.Now().Before(entry.expiresAt), nil
+}
+
+// SaveToken binds the token hash to the user, replacing the previous token of the user
+func (r *AccountRepository) SaveToken(_ context.Context, tokenHash string, userID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if previous, ok := r.userTokens[userID]; ok {
+		delete(r.tokens, previous)
+	}
+	r.tokens[tokenHash] = userID
+	r.userTokens[userID] = tokenHash
+
+	return nil
+}
+
+// FindToken returns the user of the token hash, false for unknown tokens
+func (r *AccountRepository) FindToken(_ context.Context, tokenHash string) (int64, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	userID, ok := r.tokens[tokenHash]
+
+	return userID, ok, nil
+}
+
+// SaveIdentity binds the external identity of the provider to the user
+func (r *AccountRepository) SaveIdentity(_ context.Context, provider, subject string, userID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.identities[provider+":"+subject] = userID
+
+	return nil
+}
+
+// FindIdentity returns the user of the external identity, false for identities not linked to the bot
+func (r *AccountRepository) FindIdentity(_ context.Context, provider, subject string) (int64, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	userID, ok := r.identities[provider+":"+subject]
+
+	return userID, ok, nil
+}
+
+// Identities returns the external identities linked to the user ordered by provider and subject
+func (r *AccountRepository) Identities(_ context.Context, userID int64) ([]entities.Identity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	identities := make([]entities.Identity, 0)
+	for key, linked := range r.identities {
+		if linked != userID {
+			continue
+		}
+		provider, subject, _ := strings.Cut(key, ":")
+		identities = append(identities, entities.Identity{UserID: userID, Provider: provider, Subject: subject})
+	}
+	sort.Slice(identities, func(i, j int) bool {
+		if identities[i].Provider != identities[j].Provider {
+			return identities[i].Provider < identities[j].Provider
+		}
+		return identities[i].Subject < identities[j].Subject
+	})
+
+	return identities, nil
+}
+
+// DeleteUser removes the link code, the token and the external identities of the user
+func (r *AccountRepository) DeleteUser(_ context.Context, userID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if code, ok := r.userCodes[userID]; ok {
+		delete(r.codes, code)
+		delete(r.userCodes, userID)
+	}
+	if token, ok := r.userTokens[userID]; ok {
+		delete(r.tokens, token)
+		delete(r.userTokens, userID)
+	}
+	for identity, linked := range r.identities {
+		if linked == userID {
+			delete(r.identities, identity)
+		}
+	}
+
+	return nil
+}
+
+// evict removes expired codes, or the code closest to expiration if none has expired
+func (r *AccountRepository) evict(now time.Time) {
+	var (
+		oldestCode string
+		oldestAt   time.Time
+	)
+	for code, entry := range r.codes {
+		if now.After(entry.expiresAt) {
+			delete(r.codes, code)
+			delete(r.userCodes, entry.userID)
+			continue
+		}
+		if oldestCode == "" || entry.expiresAt.Before(oldestAt) {
+			oldestCode, oldestAt = code, entry.expiresAt
+		}
+	}
+
+	if len(r.codes) >= r.maxEntries && oldestCode != "" {
+		delete(r.userCodes, r.codes[oldestCode].userID)
+		delete(r.codes, oldestCode)
+	}
+}
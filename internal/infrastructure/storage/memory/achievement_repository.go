@@ -0,0 +1,51 @@
+package memory
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"sync"
+)
+
+// AchievementRepository keeps the achievements of users in memory of the running instance
+type AchievementRepository struct {
+	mu           sync.RWMutex
+	achievements map[int64][]entities.Achievement
+}
+
+// NewAchievementRepository creates a new in-memory achievement repository
+func NewAchievementRepository() *AchievementRepository {
+	return &AchievementRepository{achievements: make(map[int64][]entities.Achievement)}
+}
+
+// Award stores the achievement of the user, false if the user already has it
+func (r *AchievementRepository) Award(_ context.Context, userID int64, achievement entities.Achievement) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, awarded := range r.achievements[userID] {
+		if awarded.ID == achievement.ID {
+			return false, nil
+		}
+	}
+	r.achievements[userID] = append(r.achievements[userID], achievement)
+
+	return true, nil
+}
+
+// List returns a copy of the achievements of the user, oldest first
+func (r *AchievementRepository) List(_ context.Context, userID int64) ([]entities.Achievement, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]entities.Achievement(nil), r.achievements[userID]...), nil
+}
+
+// Delete removes the achievements of the user
+func (r *AchievementRepository) Delete(_ context.Context, userID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.achievements, userID)
+
+	return nil
+}
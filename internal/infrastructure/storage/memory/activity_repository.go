@@ -0,0 +1,128 @@
+package memory
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/storage/document"
+	"sync"
+	"time"
+)
+
+// ActivityRepository keeps the learning activity of users in memory of the running instance
+type ActivityRepository struct {
+	mu       sync.Mutex
+	users    map[int64]*document.Activity
+	maxUsers int
+}
+
+// NewActivityRepository creates a new in-memory activity repository holding at most maxUsers users,
+// the least recently active ones are dropped beyond it
+func NewActivityRepository(maxUsers int) *ActivityRepository {
+	return &ActivityRepository{
+		users:    make(map[int64]*document.Activity),
+		maxUsers: maxUsers,
+	}
+}
+
+// RecordLookup counts the word for the day of at in its time zone, once per word and day, and keeps its answer
+func (r *ActivityRepository) RecordLookup(_ context.Context, userID int64, word entities.LookedUpWord, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.user(userID).RecordLookup(word, at)
+
+	return nil
+}
+
+// RecordQuizAnswer counts the answer for its day in its time zone and the article of the noun
+func (r *ActivityRepository) RecordQuizAnswer(_ context.Context, userID int64, answer *entities.QuizAnswer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.user(userID).RecordQuizAnswer(answer)
+
+	return nil
+}
+
+// SearchWords returns at most limit words of the user matching the query, the best matches first
+func (r *ActivityRepository) SearchWords(_ context.Context, userID int64, query string, limit int) ([]entities.WordMatch, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[userID]
+	if !ok {
+		return nil, nil
+	}
+
+	return user.Search(query, limit), nil
+}
+
+// Activity returns a copy of the activity of the user, empty if none was recorded
+func (r *ActivityRepository) Activity(_ context.Context, userID int64) (*entities.UserActivity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[userID]
+	if !ok {
+		return &entities.UserActivity{UserID: userID}, nil
+	}
+
+	return user.Entity(userID), nil
+}
+
+// Delete removes the activity of the user, unknown users are ignored
+func (r *ActivityRepository) Delete(_ context.Context, userID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.users, userID)
+
+	return nil
+}
+
+// DeleteInactive removes the activity of the users last active before the time and returns their number
+func (r *ActivityRepository) DeleteInactive(_ context.Context, before time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	removed := 0
+	for userID, user := range r.users {
+		if user.ActiveAt.Before(before) {
+			delete(r.users, userID)
+			removed++
+		}
+	}
+
+	return removed, nil
+}
+
+// user returns the activity of the user, created if needed
+func (r *ActivityRepository) user(userID int64) *document.Activity {
+	user, ok := r.users[userID]
+	if !ok {
+		if r.maxUsers > 0 && len(r.users) >= r.maxUsers {
+			r.evict()
+		}
+		user = document.NewActivity()
+		r.users[userID] = user
+	}
+
+	return user
+}
+
+// evict removes the least recently active user
+func (r *ActivityRepository) evict() {
+	var (
+		oldestID int64
+		oldestAt time.Time
+		found    bool
+	)
+	for userID, user := range r.users {
+		if !found || user.ActiveAt.Before(oldestAt) {
+			oldestID, oldestAt, found = userID, user.ActiveAt, true
+		}
+	}
+	if found {
+		delete(r.users, oldestID)
+	}
+}
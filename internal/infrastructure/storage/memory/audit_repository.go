@@ -0,0 +1,47 @@
+package memory
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"sync"
+)
+
+// AuditRepository keeps the admin audit log in memory of the running instance, no entry is ever dropped
+type AuditRepository struct {
+	mu      sync.RWMutex
+	entries []*entities.AuditEntry
+}
+
+// NewAuditRepository creates a new in-memory audit repository
+func NewAuditRepository() *AuditRepository {
+	return &AuditRepository{}
+}
+
+// Append stores a copy of the entry
+func (r *AuditRepository) Append(_ context.Context, entry *entities.AuditEntry) error {
+	stored := *entry
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries = append(r.entries, &stored)
+
+	return nil
+}
+
+// List returns copies of at most limit entries of the action, newest first
+func (r *AuditRepository) List(_ context.Context, action entities.AuditAction, limit int) ([]*entities.AuditEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries := make([]*entities.AuditEntry, 0, min(limit, len(r.entries)))
+	for i := len(r.entries) - 1; i >= 0 && len(entries) < limit; i-- {
+		if action != "" && r.entries[i].Action != action {
+			continue
+		}
+		entry := *r.entries[i]
+		entries = append(entries, &entry)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,111 @@
+package memory
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"strings"
+	"sync"
+	"time"
+)
+
+type cacheEntry struct {
+	response  *entities.ArticleResponse
+	expiresAt time.Time
+}
+
+// CacheRepository keeps article responses in memory of the running instance
+type CacheRepository struct {
+	mu         sync.Mutex
+	entries    map[string]cacheEntry
+	maxEntries int
+}
+
+// NewCacheRepository creates a new in-memory cache holding at most maxEntries responses
+func NewCacheRepository(maxEntries int) *CacheRepository {
+	return &CacheRepository{
+		entries:    make(map[string]cacheEntry),
+		maxEntries: maxEntries,
+	}
+}
+
+// Get returns the cached response if it has not expired
+func (r *CacheRepository) Get(_ context.Context, key string) (*entities.ArticleResponse, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(r.entries, key)
+		return nil, false, nil
+	}
+
+	return entry.response, true, nil
+}
+
+// Set stores the response, evicting expired entries and then the oldest one when the cache is full
+func (r *CacheRepository) Set(_ context.Context, key string, response *entities.ArticleResponse, ttl time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.entries[key]; !exists && r.maxEntries > 0 && len(r.entries) >= r.maxEntries {
+		r.evict()
+	}
+	r.entries[key] = cacheEntry{
+		response:  response,
+		expiresAt: time.Now().Add(ttl),
+	}
+
+	return nil
+}
+
+// Delete removes the entry of the key and of its variants, e.g. the levels of a word for "haus|en"
+func (r *CacheRepository) Delete(_ context.Context, key string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	removed := 0
+	for candidate := range r.entries {
+		if candidate == key || strings.HasPrefix(candidate, key+"|") {
+			delete(r.entries, candidate)
+			removed++
+		}
+	}
+
+	return removed, nil
+}
+
+// Clear removes every entry
+func (r *CacheRepository) Clear(_ context.Context) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	removed := len(r.entries)
+	r.entries = make(map[string]cacheEntry)
+
+	return removed, nil
+}
+
+// evict removes expired entries, or the entry closest to expiration if none has expired
+func (r *CacheRepository) evict() {
+	now := time.Now()
+	var (
+		oldestKey string
+		oldestAt  time.Time
+	)
+	for key, entry := range r.entries {
+		if now.After(entry.expiresAt) {
+			delete(r.entries, key)
+			continue
+		}
+		if oldestKey == "" || entry.expiresAt.Before(oldestAt) {
+			oldestKey, oldestAt = key, entry.expiresAt
+		}
+	}
+
+	if len(r.entries) >= r.maxEntries && oldestKey != "" {
+		delete(r.entries, oldestKey)
+	}
+}
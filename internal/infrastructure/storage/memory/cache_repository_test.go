@@ -0,0 +1,76 @@
+package memory
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/libs/race"
+	"testing"
+	"time"
+)
+
+// benchmarkResponse returns a cached answer with examples of both numbers
+func benchmarkResponse() *entities.ArticleResponse {
+	examples := entities.TranslationsInfo{
+		NominativeExample: "Das Haus ist groß.", NominativeTranslation: "The house is big.",
+		AccusativeExample: "Ich sehe das Haus.", AccusativeTranslation: "I see the house.",
+		DativeExample: "Ich wohne in dem Haus.", DativeTranslation: "I live in the house.",
+		GenitiveExample: "Das Dach des Hauses ist rot.", GenitiveTranslation: "The roof of the house is red.",
+	}
+	response := entities.NewSuccessResponse([]entities.ArticleInfo{{
+		WordWithArticle: "das Haus",
+		Translation:     "house",
+		Plural:          "die Häuser",
+		Example: entities.ExamplesInfo{
+			Singular: entities.ExampleInfo{Definite: examples, Indefinite: examples},
+			Plural:   entities.ExampleInfo{Definite: examples, Indefinite: examples},
+		},
+	}})
+	response.Stamp(entities.SourceGemini, time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC))
+
+	return response
+}
+
+func BenchmarkCacheRepositorySet(b *testing.B) {
+	ctx := context.Background()
+	r := NewCacheRepository(1000)
+	response := benchmarkResponse()
+	b.ReportAllocs()
+	for range b.N {
+		if err := r.Set(ctx, "haus|en", response, time.Hour); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCacheRepositoryGet(b *testing.B) {
+	ctx := context.Background()
+	r := NewCacheRepository(1000)
+	if err := r.Set(ctx, "haus|en", benchmarkResponse(), time.Hour); err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		if _, ok, err := r.Get(ctx, "haus|en"); err != nil || !ok {
+			b.Fatal("the cached answer can't be read", err)
+		}
+	}
+}
+
+// TestCacheRepositoryAllocations fails when reading or writing the cache allocates at all, the cache keeps
+// the answers as they are
+func TestCacheRepositoryAllocations(t *testing.T) {
+	if race.Enabled {
+		t.Skip("The race detector changes the allocations")
+	}
+	ctx := context.Background()
+	r := NewCacheRepository(1000)
+	response := benchmarkResponse()
+
+	if allocs := testing.AllocsPerRun(100, func() { _ = r.Set(ctx, "haus|en", response, time.Hour) }); allocs > 0 {
+		t.Errorf("Set allocates %.0f times per call", allocs)
+	}
+	if allocs := testing.AllocsPerRun(100, func() { _, _, _ = r.Get(ctx, "haus|en") }); allocs > 0 {
+		t.Errorf("Get allocates %.0f times per call", allocs)
+	}
+}
@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"sort"
+	"sync"
+)
+
+// ChatRepository keeps the known chats in memory of the running instance
+type ChatRepository struct {
+	mu    sync.RWMutex
+	chats map[int64]entities.KnownChat
+}
+
+// NewChatRepository creates a new in-memory chat repository
+func NewChatRepository() *ChatRepository {
+	return &ChatRepository{chats: make(map[int64]entities.KnownChat)}
+}
+
+// Touch adds the chat or updates its language and the time it was seen, the opt-out is kept
+func (r *ChatRepository) Touch(_ context.Context, chat *entities.KnownChat) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	touched := *chat
+	if known, ok := r.chats[chat.ID]; ok {
+		touched.OptedOut = known.OptedOut
+	}
+	r.chats[chat.ID] = touched
+
+	return nil
+}
+
+// SetOptOut sets whether the chat gets announcements
+func (r *ChatRepository) SetOptOut(_ context.Context, chatID int64, optedOut bool) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	chat, ok := r.chats[chatID]
+	if !ok {
+		return false, nil
+	}
+	chat.OptedOut = optedOut
+	r.chats[chatID] = chat
+
+	return true, nil
+}
+
+// Get returns a copy of the chat, false if it is unknown
+func (r *ChatRepository) Get(_ context.Context, chatID int64) (*entities.KnownChat, bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	chat, ok := r.chats[chatID]
+	if !ok {
+		return nil, false, nil
+	}
+
+	return &chat, true, nil
+}
+
+// Delete forgets the chat
+func (r *ChatRepository) Delete(_ context.Context, chatID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.chats, chatID)
+
+	return nil
+}
+
+// List returns copies of all known chats ordered by ID
+func (r *ChatRepository) List(_ context.Context) ([]entities.KnownChat, error) {
+	r.mu.RLock()
+	chats := make([]entities.KnownChat, 0, len(r.chats))
+	for _, chat := range r.chats {
+		chats = append(chats, chat)
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(chats, func(i, j int) bool { return chats[i].ID < chats[j].ID })
+
+	return chats, nil
+}
@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"sync"
+	"time"
+)
+
+type conversationEntry struct {
+	conversation entities.Conversation
+	expiresAt    time.Time
+}
+
+// ConversationRepository keeps the last word of every chat in memory of the running instance
+type ConversationRepository struct {
+	mu      sync.Mutex
+	entries map[int64]conversationEntry
+}
+
+// NewConversationRepository creates a new in-memory conversation repository
+func NewConversationRepository() *ConversationRepository {
+	return &ConversationRepository{entries: make(map[int64]conversationEntry)}
+}
+
+// Get returns a copy of the conversation of the chat if it has not expired
+func (r *ConversationRepository) Get(_ context.Context, chatID int64) (*entities.Conversation, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.entries[chatID]
+	if !ok {
+		return nil, false, nil
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(r.entries, chatID)
+		return nil, false, nil
+	}
+
+	conversation := entry.conversation
+	return &conversation, true, nil
+}
+
+// Save replaces the conversation of the chat and drops expired conversations of other chats
+func (r *ConversationRepository) Save(_ context.Context, conversation *entities.Conversation, ttl time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for chatID, entry := range r.entries {
+		if now.After(entry.expiresAt) {
+			delete(r.entries, chatID)
+		}
+	}
+	r.entries[conversation.ChatID] = conversationEntry{
+		conversation: *conversation,
+		expiresAt:    now.Add(ttl),
+	}
+
+	return nil
+}
+
+// Delete forgets the conversation of the chat
+func (r *ConversationRepository) Delete(_ context.Context, chatID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.entries, chatID)
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package memory
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"slices"
+	"sync"
+	"time"
+)
+
+// DeadLetterRepository keeps failed Telegram updates in memory of the running instance
+type DeadLetterRepository struct {
+	mu         sync.RWMutex
+	letters    []*entities.DeadLetter
+	maxEntries int
+}
+
+// NewDeadLetterRepository creates a new in-memory dead letter repository keeping at most maxEntries updates
+func NewDeadLetterRepository(maxEntries int) *DeadLetterRepository {
+	return &DeadLetterRepository{maxEntries: maxEntries}
+}
+
+// Save stores the dead letter, a new one is appended and the oldest one is dropped when the repository is full
+func (r *DeadLetterRepository) Save(_ context.Context, letter *entities.DeadLetter) error {
+	stored := *letter
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if i := r.index(letter.ID); i >= 0 {
+		r.letters[i] = &stored
+		return nil
+	}
+	r.letters = append(r.letters, &stored)
+	if r.maxEntries > 0 && len(r.letters) > r.maxEntries {
+		r.letters = r.letters[len(r.letters)-r.maxEntries:]
+	}
+
+	return nil
+}
+
+// Get returns a copy of the dead letter
+func (r *DeadLetterRepository) Get(_ context.Context, id string) (*entities.DeadLetter, bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i := r.index(id)
+	if i < 0 {
+		return nil, false, nil
+	}
+	letter := *r.letters[i]
+
+	return &letter, true, nil
+}
+
+// List returns copies of at most limit dead letters, oldest first
+func (r *DeadLetterRepository) List(_ context.Context, limit int) ([]*entities.DeadLetter, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	letters := make([]*entities.DeadLetter, 0, min(limit, len(r.letters)))
+	for _, stored := range r.letters[:min(limit, len(r.letters))] {
+		letter := *stored
+		letters = append(letters, &letter)
+	}
+
+	return letters, nil
+}
+
+// Delete removes the dead letter, unknown IDs are ignored
+func (r *DeadLetterRepository) Delete(_ context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if i := r.index(id); i >= 0 {
+		r.letters = slices.Delete(r.letters, i, i+1)
+	}
+
+	return nil
+}
+
+// ListUser returns copies of the dead letters of the user, oldest first
+func (r *DeadLetterRepository) ListUser(_ context.Context, userID int64) ([]*entities.DeadLetter, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var letters []*entities.DeadLetter
+	for _, stored := range r.letters {
+		if stored.UserID == userID {
+			letter := *stored
+			letters = append(letters, &letter)
+		}
+	}
+
+	return letters, nil
+}
+
+// DeleteUser removes the dead letters of the user
+func (r *DeadLetterRepository) DeleteUser(_ context.Context, userID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.letters = slices.DeleteFunc(r.letters, func(letter *entities.DeadLetter) bool { return letter.UserID == userID })
+
+	return nil
+}
+
+// DeleteBefore removes the dead letters that failed before the time and returns their number
+func (r *DeadLetterRepository) DeleteBefore(_ context.Context, before time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := len(r.letters)
+	r.letters = slices.DeleteFunc(r.letters, func(letter *entities.DeadLetter) bool { return letter.FailedAt.Before(before) })
+
+	return count - len(r.letters), nil
+}
+
+func (r *DeadLetterRepository) index(id string) int {
+	return slices.IndexFunc(r.letters, func(letter *entities.DeadLetter) bool { return letter.ID == id })
+}
@@ -0,0 +1,42 @@
+package memory
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"sync"
+)
+
+// DictionaryRepository keeps the curated dictionary entries in memory of the running instance
+type DictionaryRepository struct {
+	mu      sync.RWMutex
+	entries map[string]entities.CuratedEntry
+}
+
+// NewDictionaryRepository creates a new in-memory dictionary repository
+func NewDictionaryRepository() *DictionaryRepository {
+	return &DictionaryRepository{entries: make(map[string]entities.CuratedEntry)}
+}
+
+// Get returns a copy of the entry of the noun, nil for nouns without one
+func (r *DictionaryRepository) Get(_ context.Context, word string) (*entities.CuratedEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entry, ok := r.entries[entities.CuratedKey(word)]
+	if !ok {
+		return nil, nil
+	}
+
+	return &entry, nil
+}
+
+// Save replaces the entries of their nouns
+func (r *DictionaryRepository) Save(_ context.Context, entries []entities.CuratedEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, entry := range entries {
+		r.entries[entry.Key()] = entry
+	}
+
+	return nil
+}
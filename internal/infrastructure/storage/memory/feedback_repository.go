@@ -0,0 +1,103 @@
+package memory
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/storage/document"
+	"sync"
+	"time"
+)
+
+// FeedbackRepository keeps user ratings in memory of the running instance
+type FeedbackRepository struct {
+	mu         sync.RWMutex
+	feedback   []*entities.Feedback
+	maxEntries int
+}
+
+// NewFeedbackRepository creates a new in-memory feedback repository keeping at most maxEntries ratings
+func NewFeedbackRepository(maxEntries int) *FeedbackRepository {
+	return &FeedbackRepository{maxEntries: maxEntries}
+}
+
+// Save appends the feedback, dropping the oldest one when the repository is full
+func (r *FeedbackRepository) Save(_ context.Context, feedback *entities.Feedback) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.feedback = append(r.feedback, feedback)
+	if r.maxEntries > 0 && len(r.feedback) > r.maxEntries {
+		r.feedback = r.feedback[len(r.feedback)-r.maxEntries:]
+	}
+
+	return nil
+}
+
+// List returns a page of the matching feedback, newest first unless the page orders it otherwise
+func (r *FeedbackRepository) List(_ context.Context, filter entities.FeedbackFilter) (entities.Page[*entities.Feedback], error) {
+	r.mu.RLock()
+	result := make([]*entities.Feedback, 0)
+	for _, feedback := range r.feedback {
+		if document.MatchesFeedback(filter, feedback) {
+			result = append(result, feedback)
+		}
+	}
+	r.mu.RUnlock()
+
+	page, keyOf := document.FeedbackPage(filter)
+
+	return document.Paginate(result, page, keyOf)
+}
+
+// ListUser returns the ratings of the user, oldest first
+func (r *FeedbackRepository) ListUser(_ context.Context, userID int64) ([]*entities.Feedback, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*entities.Feedback, 0)
+	for _, feedback := range r.feedback {
+		if feedback.UserID == userID {
+			result = append(result, feedback)
+		}
+	}
+
+	return result, nil
+}
+
+// DeleteUser removes the ratings of the user
+func (r *FeedbackRepository) DeleteUser(_ context.Context, userID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.remove(func(feedback *entities.Feedback) bool {
+		return feedback.UserID == userID
+	})
+
+	return nil
+}
+
+// DeleteBefore removes the ratings created before the time and returns their number
+func (r *FeedbackRepository) DeleteBefore(_ context.Context, before time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.remove(func(feedback *entities.Feedback) bool {
+		return feedback.CreatedAt.Before(before)
+	}), nil
+}
+
+// remove drops the matching ratings and returns their number, the caller holds the lock
+func (r *FeedbackRepository) remove(matches func(feedback *entities.Feedback) bool) int {
+	kept := r.feedback[:0]
+	for _, feedback := range r.feedback {
+		if !matches(feedback) {
+			kept = append(kept, feedback)
+		}
+	}
+	removed := len(r.feedback) - len(kept)
+	// The dropped tail is cleared, so the removed ratings can be collected
+	clear(r.feedback[len(kept):])
+	r.feedback = kept
+
+	return removed
+}
@@ -0,0 +1,94 @@
+package memory
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"sync"
+	"time"
+)
+
+type idempotencyEntry struct {
+	response  *entities.IdempotentResponse
+	expiresAt time.Time
+}
+
+// IdempotencyRepository keeps the responses of requests with idempotency keys in memory of the running instance
+type IdempotencyRepository struct {
+	mu         sync.Mutex
+	entries    map[string]idempotencyEntry
+	maxEntries int
+}
+
+// NewIdempotencyRepository creates a new in-memory idempotency repository holding at most maxEntries keys
+func NewIdempotencyRepository(maxEntries int) *IdempotencyRepository {
+	return &IdempotencyRepository{
+		entries:    make(map[string]idempotencyEntry),
+		maxEntries: maxEntries,
+	}
+}
+
+// Begin reserves the key for the request with the fingerprint and returns nil, or the record of
+// an earlier request with the key
+func (r *IdempotencyRepository) Begin(_ context.Context, key, fingerprint string, ttl time.Duration) (*entities.IdempotentResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if entry, ok := r.entries[key]; ok && now.Before(entry.expiresAt) {
+		return entry.response, nil
+	}
+
+	if r.maxEntries > 0 && len(r.entries) >= r.maxEntries {
+		r.evict(now)
+	}
+	r.entries[key] = idempotencyEntry{
+		response:  &entities.IdempotentResponse{Fingerprint: fingerprint},
+		expiresAt: now.Add(ttl),
+	}
+
+	return nil, nil
+}
+
+// Complete stores the response of the request holding the key
+func (r *IdempotencyRepository) Complete(_ context.Context, key string, response *entities.IdempotentResponse, ttl time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries[key] = idempotencyEntry{
+		response:  response,
+		expiresAt: time.Now().Add(ttl),
+	}
+
+	return nil
+}
+
+// Release frees the key of a failed request, so a retry is processed again
+func (r *IdempotencyRepository) Release(_ context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.entries, key)
+
+	return nil
+}
+
+// evict removes expired entries, or the entry closest to expiration if none has expired
+func (r *IdempotencyRepository) evict(now time.Time) {
+	var (
+		oldestKey string
+		oldestAt  time.Time
+	)
+	for key, entry := range r.entries {
+		if now.After(entry.expiresAt) {
+			delete(r.entries, key)
+			continue
+		}
+		if oldestKey == "" || entry.expiresAt.Before(oldestAt) {
+			oldestKey, oldestAt = key, entry.expiresAt
+		}
+	}
+
+	if len(r.entries) >= r.maxEntries && oldestKey != "" {
+		delete(r.entries, oldestKey)
+	}
+}
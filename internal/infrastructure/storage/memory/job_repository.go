@@ -0,0 +1,106 @@
+package memory
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"sync"
+	"time"
+)
+
+type jobEntry struct {
+	status    entities.JobStatus
+	expiresAt time.Time
+}
+
+// JobRepository keeps the states of background jobs in memory of the running instance
+type JobRepository struct {
+	mu         sync.Mutex
+	entries    map[string]jobEntry
+	maxEntries int
+}
+
+// NewJobRepository creates a new in-memory job repository holding at most maxEntries jobs
+func NewJobRepository(maxEntries int) *JobRepository {
+	return &JobRepository{
+		entries:    make(map[string]jobEntry),
+		maxEntries: maxEntries,
+	}
+}
+
+// Save stores a copy of the status, replacing the previous one of the job
+func (r *JobRepository) Save(_ context.Context, status *entities.JobStatus, ttl time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if _, exists := r.entries[status.ID]; !exists && r.maxEntries > 0 && len(r.entries) >= r.maxEntries {
+		r.evict(now)
+	}
+
+	entry := jobEntry{status: *status, expiresAt: now.Add(ttl)}
+	if status.Progress != nil {
+		progress := *status.Progress
+		entry.status.Progress = &progress
+	}
+	r.entries[status.ID] = entry
+
+	return nil
+}
+
+// Get returns a copy of the status of the job if it has not expired
+func (r *JobRepository) Get(_ context.Context, id string) (*entities.JobStatus, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.entries[id]
+	if !ok {
+		return nil, false, nil
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(r.entries, id)
+		return nil, false, nil
+	}
+
+	status := entry.status
+	if status.Progress != nil {
+		progress := *status.Progress
+		status.Progress = &progress
+	}
+
+	return &status, true, nil
+}
+
+// DeleteUser removes the states of the jobs working for the user
+func (r *JobRepository) DeleteUser(_ context.Context, userID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, entry := range r.entries {
+		if entry.status.UserID == userID {
+			delete(r.entries, id)
+		}
+	}
+
+	return nil
+}
+
+// evict removes expired entries, or the entry closest to expiration if none has expired
+func (r *JobRepository) evict(now time.Time) {
+	var (
+		oldestKey string
+		oldestAt  time.Time
+	)
+	for key, entry := range r.entries {
+		if now.After(entry.expiresAt) {
+			delete(r.entries, key)
+			continue
+		}
+		if oldestKey == "" || entry.expiresAt.Before(oldestAt) {
+			oldestKey, oldestAt = key, entry.expiresAt
+		}
+	}
+
+	if len(r.entries) >= r.maxEntries && oldestKey != "" {
+		delete(r.entries, oldestKey)
+	}
+}
@@ -0,0 +1,163 @@
+package memory
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"sort"
+	"sync"
+)
+
+// leaderboardWeeks is the number of weeks whose scores are kept, the current one and the one being summarized
+const leaderboardWeeks = 2
+
+type leaderboardKey struct {
+	week   string
+	chatID int64
+}
+
+// LeaderboardRepository keeps the leaderboard members and their scores in memory of the running instance
+type LeaderboardRepository struct {
+	mu      sync.Mutex
+	members map[int64]entities.LeaderboardMember
+	scores  map[leaderboardKey]map[int64]*entities.LeaderboardScore
+}
+
+// NewLeaderboardRepository creates a new in-memory leaderboard repository, scores are kept for the
+// current and the previous week
+func NewLeaderboardRepository() *LeaderboardRepository {
+	return &LeaderboardRepository{
+		members: make(map[int64]entities.LeaderboardMember),
+		scores:  make(map[leaderboardKey]map[int64]*entities.LeaderboardScore),
+	}
+}
+
+// SaveMember adds the member or updates the display name of the member
+func (r *LeaderboardRepository) SaveMember(_ context.Context, member *entities.LeaderboardMember) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.members[member.UserID] = *member
+
+	return nil
+}
+
+// DeleteMember removes the member together with all scores of the member
+func (r *LeaderboardRepository) DeleteMember(_ context.Context, userID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.members, userID)
+	for key, scores := range r.scores {
+		delete(scores, userID)
+		if len(scores) == 0 {
+			delete(r.scores, key)
+		}
+	}
+
+	return nil
+}
+
+// Member returns a copy of the member of the user, nil for users who didn't opt in
+func (r *LeaderboardRepository) Member(_ context.Context, userID int64) (*entities.LeaderboardMember, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	member, ok := r.members[userID]
+	if !ok {
+		return nil, nil
+	}
+
+	return &member, nil
+}
+
+// RecordScore counts the answer of the member in the week of the chat, weeks older than the
+// kept ones are dropped when a new week starts
+func (r *LeaderboardRepository) RecordScore(_ context.Context, week string, chatID int64, userID int64, correct bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := leaderboardKey{week: week, chatID: chatID}
+	scores, ok := r.scores[key]
+	if !ok {
+		r.pruneWeeks(week)
+		scores = make(map[int64]*entities.LeaderboardScore)
+		r.scores[key] = scores
+	}
+	score, ok := scores[userID]
+	if !ok {
+		score = &entities.LeaderboardScore{UserID: userID}
+		scores[userID] = score
+	}
+	score.Answers++
+	if correct {
+		score.Correct++
+	}
+
+	return nil
+}
+
+// Scores returns copies of the scores of the week in the chat
+func (r *LeaderboardRepository) Scores(_ context.Context, week string, chatID int64) ([]entities.LeaderboardScore, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	scores := r.scores[leaderboardKey{week: week, chatID: chatID}]
+	result := make([]entities.LeaderboardScore, 0, len(scores))
+	for _, score := range scores {
+		result = append(result, *score)
+	}
+
+	return result, nil
+}
+
+// DeleteChat removes the scores of the group chat in all weeks
+func (r *LeaderboardRepository) DeleteChat(_ context.Context, chatID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key := range r.scores {
+		if key.chatID == chatID {
+			delete(r.scores, key)
+		}
+	}
+
+	return nil
+}
+
+// Chats returns the group chats with scores in the week
+func (r *LeaderboardRepository) Chats(_ context.Context, week string) ([]int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var chats []int64
+	for key := range r.scores {
+		if key.week == week && key.chatID != entities.LeaderboardGlobal {
+			chats = append(chats, key.chatID)
+		}
+	}
+	sort.Slice(chats, func(i, j int) bool { return chats[i] < chats[j] })
+
+	return chats, nil
+}
+
+// pruneWeeks keeps the scores of the newest weeks including the week, ISO week names sort chronologically
+func (r *LeaderboardRepository) pruneWeeks(week string) {
+	weeks := map[string]bool{week: true}
+	for key := range r.scores {
+		weeks[key.week] = true
+	}
+	if len(weeks) <= leaderboardWeeks {
+		return
+	}
+
+	names := make([]string, 0, len(weeks))
+	for name := range weeks {
+		names = append(names, name)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(names)))
+	oldest := names[leaderboardWeeks-1]
+	for key := range r.scores {
+		if key.week < oldest {
+			delete(r.scores, key)
+		}
+	}
+}
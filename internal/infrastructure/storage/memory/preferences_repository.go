@@ -0,0 +1,49 @@
+package memory
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"sync"
+)
+
+// PreferencesRepository keeps user settings in memory of the running instance
+type PreferencesRepository struct {
+	mu          sync.RWMutex
+	preferences map[int64]entities.UserPreferences
+}
+
+// NewPreferencesRepository creates a new in-memory preferences repository
+func NewPreferencesRepository() *PreferencesRepository {
+	return &PreferencesRepository{preferences: make(map[int64]entities.UserPreferences)}
+}
+
+// Get returns a copy of the preferences of the user, default preferences if none were saved
+func (r *PreferencesRepository) Get(_ context.Context, userID int64) (*entities.UserPreferences, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	preferences, ok := r.preferences[userID]
+	if !ok {
+		return &entities.UserPreferences{UserID: userID}, nil
+	}
+
+	return &preferences, nil
+}
+
+// Save replaces the preferences of the user
+func (r *PreferencesRepository) Save(_ context.Context, preferences *entities.UserPreferences) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.preferences[preferences.UserID] = *preferences
+
+	return nil
+}
+
+// Delete removes the preferences of the user
+func (r *PreferencesRepository) Delete(_ context.Context, userID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.preferences, userID)
+
+	return nil
+}
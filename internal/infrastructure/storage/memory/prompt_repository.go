@@ -0,0 +1,54 @@
+package memory
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"slices"
+	"sync"
+)
+
+// PromptRepository keeps the prompt overrides of the tenants in memory of the running instance
+type PromptRepository struct {
+	mu        sync.RWMutex
+	overrides map[string]entities.PromptOverrides
+}
+
+// NewPromptRepository creates a new in-memory prompt repository
+func NewPromptRepository() *PromptRepository {
+	return &PromptRepository{overrides: make(map[string]entities.PromptOverrides)}
+}
+
+// Get returns a copy of the prompt overrides of the tenant, nil if none were saved
+func (r *PromptRepository) Get(_ context.Context, tenantID string) (*entities.PromptOverrides, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	overrides, ok := r.overrides[tenantID]
+	if !ok {
+		return nil, nil
+	}
+	overrides.ExampleDomains = slices.Clone(overrides.ExampleDomains)
+
+	return &overrides, nil
+}
+
+// Save replaces the prompt overrides of the tenant
+func (r *PromptRepository) Save(_ context.Context, overrides *entities.PromptOverrides) error {
+	stored := *overrides
+	stored.ExampleDomains = slices.Clone(overrides.ExampleDomains)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.overrides[overrides.TenantID] = stored
+
+	return nil
+}
+
+// Delete removes the prompt overrides of the tenant
+func (r *PromptRepository) Delete(_ context.Context, tenantID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.overrides, tenantID)
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package memory
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ReminderRepository keeps the practice reminders in memory of the running instance
+type ReminderRepository struct {
+	mu        sync.RWMutex
+	reminders map[int64]entities.Reminder
+}
+
+// NewReminderRepository creates a new in-memory reminder repository
+func NewReminderRepository() *ReminderRepository {
+	return &ReminderRepository{reminders: make(map[int64]entities.Reminder)}
+}
+
+// Get returns a copy of the reminder of the user, nil if none is set
+func (r *ReminderRepository) Get(_ context.Context, userID int64) (*entities.Reminder, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	reminder, ok := r.reminders[userID]
+	if !ok {
+		return nil, nil
+	}
+
+	return &reminder, nil
+}
+
+// Save replaces the reminder of the user
+func (r *ReminderRepository) Save(_ context.Context, reminder *entities.Reminder) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reminders[reminder.UserID] = *reminder
+
+	return nil
+}
+
+// Delete removes the reminder of the user
+func (r *ReminderRepository) Delete(_ context.Context, userID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.reminders, userID)
+
+	return nil
+}
+
+// Due returns at most limit reminders due at the time, the longest due first
+func (r *ReminderRepository) Due(_ context.Context, now time.Time, limit int) ([]entities.Reminder, error) {
+	r.mu.RLock()
+	due := make([]entities.Reminder, 0)
+	for _, reminder := range r.reminders {
+		if !reminder.NextAt.After(now) {
+			due = append(due, reminder)
+		}
+	}
+	r.mu.RUnlock()
+
+	slices.SortFunc(due, func(a, b entities.Reminder) int {
+		return a.NextAt.Compare(b.NextAt)
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	return due, nil
+}
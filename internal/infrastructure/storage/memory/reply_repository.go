@@ -0,0 +1,54 @@
+package memory
+
+import (
+	"context"
+	"sync"
+)
+
+type replyKey struct {
+	chatID    int64
+	messageID int
+}
+
+// ReplyRepository keeps the answers of the latest messages in memory of the running instance
+type ReplyRepository struct {
+	mu         sync.Mutex
+	replies    map[replyKey]int
+	order      []replyKey
+	maxEntries int
+}
+
+// NewReplyRepository creates a new in-memory reply repository keeping the answers of at most maxEntries messages
+func NewReplyRepository(maxEntries int) *ReplyRepository {
+	return &ReplyRepository{
+		replies:    make(map[replyKey]int),
+		maxEntries: maxEntries,
+	}
+}
+
+// Get returns the message ID of the answer to the message of the chat
+func (r *ReplyRepository) Get(_ context.Context, chatID int64, messageID int) (int, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	replyID, ok := r.replies[replyKey{chatID: chatID, messageID: messageID}]
+	return replyID, ok, nil
+}
+
+// Save stores the answer to the message, the answer of the oldest message is forgotten when the repository is full
+func (r *ReplyRepository) Save(_ context.Context, chatID int64, messageID, replyID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := replyKey{chatID: chatID, messageID: messageID}
+	if _, ok := r.replies[key]; !ok {
+		r.order = append(r.order, key)
+	}
+	r.replies[key] = replyID
+	if r.maxEntries > 0 && len(r.order) > r.maxEntries {
+		delete(r.replies, r.order[0])
+		r.order = r.order[1:]
+	}
+
+	return nil
+}
@@ -0,0 +1,240 @@
+package memory
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/storage/document"
+	"maps"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	quotaDateLayout  = "2006-01-02"
+	spendMonthLayout = "2006-01"
+)
+
+// StatsRepository keeps usage metrics in memory of the running instance
+type StatsRepository struct {
+	mu            sync.RWMutex
+	since         time.Time
+	words         map[string]int64
+	adapters      map[entities.Adapter]int64
+	cacheHits     int64
+	cacheMisses   int64
+	aiCalls       int64
+	aiErrors      int64
+	quotaDate     string
+	quotaUsed     int64
+	callCost      float64
+	spendMonth    string
+	spent         float64
+	telegramUsers map[int64]time.Time
+	churn         entities.ChurnStats
+	now           func() time.Time
+}
+
+// NewStatsRepository creates a new in-memory stats repository
+func NewStatsRepository() *StatsRepository {
+	return &StatsRepository{
+		since:         time.Now().UTC(),
+		words:         make(map[string]int64),
+		adapters:      make(map[entities.Adapter]int64),
+		telegramUsers: make(map[int64]time.Time),
+		now:           func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// RecordLookup increments the lookup counters of the word and of the adapter
+func (r *StatsRepository) RecordLookup(_ context.Context, word, _ string, adapter entities.Adapter) {
+	key := document.WordKey(word)
+	if key == "" {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.words[key]++
+	if adapter != "" {
+		r.adapters[adapter]++
+	}
+}
+
+// RecordCacheLookup increments the cache hit or miss counter
+func (r *StatsRepository) RecordCacheLookup(_ context.Context, hit bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if hit {
+		r.cacheHits++
+		return
+	}
+	r.cacheMisses++
+}
+
+// SetCallCost sets the estimated cost of an AI call in USD, it is added to the monthly spend of every call
+func (r *StatsRepository) SetCallCost(cost float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.callCost = cost
+}
+
+// RecordAICall increments the AI call counters, the daily quota usage and the monthly spend
+func (r *StatsRepository) RecordAICall(_ context.Context, failed bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.aiCalls++
+	if failed {
+		r.aiErrors++
+	}
+
+	now := r.now()
+	today := now.Format(quotaDateLayout)
+	if r.quotaDate != today {
+		r.quotaDate = today
+		r.quotaUsed = 0
+	}
+	r.quotaUsed++
+
+	// Failed calls are billed as well when the model has answered
+	month := now.Format(spendMonthLayout)
+	if r.spendMonth != month {
+		r.spendMonth = month
+		r.spent = 0
+	}
+	r.spent += r.callCost
+}
+
+// MonthlySpend returns the estimated AI spend of the current month in USD
+func (r *StatsRepository) MonthlySpend(_ context.Context) (float64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.spendMonth != r.now().Format(spendMonthLayout) {
+		return 0, nil
+	}
+	return r.spent, nil
+}
+
+// RecordTelegramUser marks the Telegram user as active now
+func (r *StatsRepository) RecordTelegramUser(_ context.Context, userID int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.telegramUsers[userID] = r.now()
+}
+
+// DeleteUser forgets when the Telegram user was last seen
+func (r *StatsRepository) DeleteUser(_ context.Context, userID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.telegramUsers, userID)
+
+	return nil
+}
+
+// RecordChatMemberEvent increments the churn counter of the event, unknown events are ignored
+func (r *StatsRepository) RecordChatMemberEvent(_ context.Context, event entities.ChatMemberEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch event {
+	case entities.ChatMemberAddedToGroup:
+		r.churn.GroupsAdded++
+	case entities.ChatMemberRemovedFromGroup:
+		r.churn.GroupsRemoved++
+	case entities.ChatMemberBlocked:
+		r.churn.Blocked++
+	case entities.ChatMemberUnblocked:
+		r.churn.Unblocked++
+	}
+}
+
+// Snapshot returns the current metrics with the given number of top words
+func (r *StatsRepository) Snapshot(_ context.Context, topWords int) (*entities.DashboardStats, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := r.now()
+	stats := &entities.DashboardStats{
+		GeneratedAt:      now,
+		Since:            r.since,
+		TopWords:         make([]entities.WordStat, 0, len(r.words)),
+		LookupsByAdapter: maps.Clone(r.adapters),
+		Cache: entities.CacheStats{
+			Hits:    r.cacheHits,
+			Misses:  r.cacheMisses,
+			HitRate: ratio(r.cacheHits, r.cacheHits+r.cacheMisses),
+		},
+		AI: entities.AIStats{
+			Calls:     r.aiCalls,
+			Errors:    r.aiErrors,
+			ErrorRate: ratio(r.aiErrors, r.aiCalls),
+		},
+		Quota: entities.QuotaStats{
+			Date: now.Format(quotaDateLayout),
+		},
+		Spend: entities.SpendStats{
+			Month: now.Format(spendMonthLayout),
+		},
+	}
+
+	for word, lookups := range r.words {
+		stats.TopWords = append(stats.TopWords, entities.WordStat{Word: word, Lookups: lookups})
+	}
+	sort.Slice(stats.TopWords, func(i, j int) bool {
+		if stats.TopWords[i].Lookups == stats.TopWords[j].Lookups {
+			return stats.TopWords[i].Word < stats.TopWords[j].Word
+		}
+		return stats.TopWords[i].Lookups > stats.TopWords[j].Lookups
+	})
+	if topWords > 0 && len(stats.TopWords) > topWords {
+		stats.TopWords = stats.TopWords[:topWords]
+	}
+
+	for _, seen := range r.telegramUsers {
+		if now.Sub(seen) <= 24*time.Hour {
+			stats.Telegram.ActiveUsers24h++
+		}
+		if now.Sub(seen) <= 7*24*time.Hour {
+			stats.Telegram.ActiveUsers7d++
+		}
+	}
+	stats.Telegram.Churn = r.churn
+
+	if r.quotaDate == stats.Quota.Date {
+		stats.Quota.Used = r.quotaUsed
+	}
+	if r.spendMonth == stats.Spend.Month {
+		stats.Spend.Spent = r.spent
+	}
+
+	return stats, nil
+}
+
+// ListWords returns a page of the looked-up words, most looked-up first unless the page orders them otherwise
+func (r *StatsRepository) ListWords(_ context.Context, filter entities.WordStatFilter) (entities.Page[entities.WordStat], error) {
+	prefix := document.WordKey(filter.Prefix)
+
+	r.mu.RLock()
+	words := make([]entities.WordStat, 0, len(r.words))
+	for word, lookups := range r.words {
+		if strings.HasPrefix(word, prefix) {
+			words = append(words, entities.WordStat{Word: word, Lookups: lookups})
+		}
+	}
+	r.mu.RUnlock()
+
+	page, keyOf := document.WordPage(filter)
+
+	return document.Paginate(words, page, keyOf)
+}
+
+func ratio(part, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total)
+}
@@ -0,0 +1,48 @@
+// Package migration applies the versioned schemas of the storage backends and tracks their versions
+package migration
+
+import (
+	"context"
+	"fmt"
+)
+
+// Status is the schema version of a storage backend
+type Status struct {
+	Backend string `json:"backend"`
+	// Version is the last applied migration, zero before the first one
+	Version uint `json:"version"`
+	Latest  uint `json:"latest"`
+	// Dirty reports a migration that failed halfway, the schema needs a manual fix before migrating again
+	Dirty bool `json:"dirty,omitempty"`
+}
+
+// Current reports whether every migration was applied
+func (s Status) Current() bool {
+	return s.Version == s.Latest && !s.Dirty
+}
+
+// Migrator applies the versioned migrations of a storage backend
+type Migrator interface {
+	// Status returns the version of the schema without changing it
+	Status(ctx context.Context) (Status, error)
+	// Up applies the migrations the schema is missing, in order
+	Up(ctx context.Context) (Status, error)
+}
+
+// Prepare brings the schema to the latest version when apply is set, otherwise it fails unless the schema
+// is current, so instances don't run on a schema they don't know
+func Prepare(ctx context.Context, migrator Migrator, apply bool) (Status, error) {
+	if apply {
+		return migrator.Up(ctx)
+	}
+
+	status, err := migrator.Status(ctx)
+	if err != nil {
+		return status, err
+	}
+	if !status.Current() {
+		return status, fmt.Errorf("%s schema is at version %d of %d, apply the migrations with cmd/migrate", status.Backend, status.Version, status.Latest)
+	}
+
+	return status, nil
+}
@@ -0,0 +1,111 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"io/fs"
+	"os"
+)
+
+// SQL migrates a database with the golang-migrate files of a directory, e.g. 000001_create_tables.up.sql,
+// the applied version is tracked in the schema_migrations table
+type SQL struct {
+	backend    string
+	migrations fs.FS
+	directory  string
+	// open connects the migration driver, which is closed after every run
+	open func() (database.Driver, error)
+}
+
+// NewSQL creates the migrator of the backend with the migrations of the directory of the file system
+func NewSQL(backend string, migrations fs.FS, directory string, open func() (database.Driver, error)) *SQL {
+	return &SQL{backend: backend, migrations: migrations, directory: directory, open: open}
+}
+
+// Status returns the version of the schema without changing it
+func (m *SQL) Status(_ context.Context) (Status, error) {
+	var status Status
+	err := m.run(func(migrator *migrate.Migrate, latest uint) error {
+		var err error
+		status, err = m.status(migrator, latest)
+		return err
+	})
+
+	return status, err
+}
+
+// Up applies the migrations the schema is missing, concurrent instances wait for the lock of the first one
+func (m *SQL) Up(_ context.Context) (Status, error) {
+	var status Status
+	err := m.run(func(migrator *migrate.Migrate, latest uint) error {
+		if err := migrator.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+			return fmt.Errorf("failed to migrate %s schema: %w", m.backend, err)
+		}
+		var err error
+		status, err = m.status(migrator, latest)
+		return err
+	})
+
+	return status, err
+}
+
+// run connects the migrator for the function
+func (m *SQL) run(f func(migrator *migrate.Migrate, latest uint) error) error {
+	files, err := iofs.New(m.migrations, m.directory)
+	if err != nil {
+		return fmt.Errorf("invalid %s migrations: %w", m.backend, err)
+	}
+	latest, err := latestVersion(files)
+	if err != nil {
+		return fmt.Errorf("invalid %s migrations: %w", m.backend, err)
+	}
+	driver, err := m.open()
+	if err != nil {
+		return fmt.Errorf("failed to prepare %s migrations: %w", m.backend, err)
+	}
+	migrator, err := migrate.NewWithInstance("iofs", files, m.backend, driver)
+	if err != nil {
+		return fmt.Errorf("failed to prepare %s migrations: %w", m.backend, err)
+	}
+	defer migrator.Close()
+
+	return f(migrator, latest)
+}
+
+func (m *SQL) status(migrator *migrate.Migrate, latest uint) (Status, error) {
+	status := Status{Backend: m.backend, Latest: latest}
+	version, dirty, err := migrator.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return status, nil
+	}
+	if err != nil {
+		return status, fmt.Errorf("failed to read %s schema version: %w", m.backend, err)
+	}
+	status.Version, status.Dirty = version, dirty
+
+	return status, nil
+}
+
+// latestVersion returns the version of the last migration of the source
+func latestVersion(files source.Driver) (uint, error) {
+	version, err := files.First()
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	for err == nil {
+		var next uint
+		if next, err = files.Next(version); err == nil {
+			version = next
+		}
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return 0, err
+	}
+
+	return version, nil
+}
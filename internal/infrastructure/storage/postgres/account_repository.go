@@ -0,0 +1,146 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/jackc/pgx/v5"
+	"time"
+)
+
+// AccountRepository keeps the link codes, the API token hashes and the linked identities in the link_codes,
+// tokens and identities tables, every user holds at most one code and one token
+type AccountRepository struct {
+	client *Client
+}
+
+// NewAccountRepository creates a new PostgreSQL account repository
+func NewAccountRepository(client *Client) *AccountRepository {
+	return &AccountRepository{client: client}
+}
+
+// SaveLinkCode stores the one-time code of the user for ttl, the previous code of the user is dropped
+func (r *AccountRepository) SaveLinkCode(ctx context.Context, code string, userID int64, ttl time.Duration) error {
+	return r.replace(ctx,
+		"DELETE FROM link_codes WHERE code = $1 OR user_id = $2",
+		"INSERT INTO link_codes (code, user_id, expires_at) VALUES ($1, $2, $3)",
+		code, userID, time.Now().Add(ttl))
+}
+
+// TakeLinkCode returns the user of the code and deletes it, false for unknown and expired codes
+func (r *AccountRepository) TakeLinkCode(ctx context.Context, code string) (int64, bool, error) {
+	var (
+		userID int64
+		valid  bool
+	)
+	err := r.client.pool.QueryRow(ctx, "DELETE FROM link_codes WHERE code = $1 RETURNING user_id, expires_at > now()", code).
+		Scan(&userID, &valid)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	return userID, valid, nil
+}
+
+// SaveToken binds the token hash to the user, replacing the previous token of the user
+func (r *AccountRepository) SaveToken(ctx context.Context, tokenHash string, userID int64) error {
+	return r.replace(ctx,
+		"DELETE FROM tokens WHERE token_hash = $1 OR user_id = $2",
+		"INSERT INTO tokens (token_hash, user_id) VALUES ($1, $2)",
+		tokenHash, userID)
+}
+
+// FindToken returns the user of the token hash, false for unknown tokens
+func (r *AccountRepository) FindToken(ctx context.Context, tokenHash string) (int64, bool, error) {
+	return r.findUser(ctx, "SELECT user_id FROM tokens WHERE token_hash = $1", tokenHash)
+}
+
+// SaveIdentity binds the external identity of the provider to the user
+func (r *AccountRepository) SaveIdentity(ctx context.Context, provider, subject string, userID int64) error {
+	_, err := r.client.exec(ctx,
+		"INSERT INTO identities (provider, subject, user_id) VALUES ($1, $2, $3) ON CONFLICT (provider, subject) DO UPDATE SET user_id = excluded.user_id",
+		provider, subject, userID)
+	return err
+}
+
+// FindIdentity returns the user of the external identity, false for identities not linked to the bot
+func (r *AccountRepository) FindIdentity(ctx context.Context, provider, subject string) (int64, bool, error) {
+	return r.findUser(ctx, "SELECT user_id FROM identities WHERE provider = $1 AND subject = $2", provider, subject)
+}
+
+// Identities returns the external identities linked to the user ordered by provider and subject
+func (r *AccountRepository) Identities(ctx context.Context, userID int64) ([]entities.Identity, error) {
+	rows, err := r.client.pool.Query(ctx, "SELECT provider, subject FROM identities WHERE user_id = $1 ORDER BY provider, subject", userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list identities: %w", err)
+	}
+	defer rows.Close()
+
+	identities := make([]entities.Identity, 0)
+	for rows.Next() {
+		identity := entities.Identity{UserID: userID}
+		if err := rows.Scan(&identity.Provider, &identity.Subject); err != nil {
+			return nil, fmt.Errorf("failed to read identity: %w", err)
+		}
+		identities = append(identities, identity)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list identities: %w", err)
+	}
+
+	return identities, nil
+}
+
+// DeleteUser removes the link code, the token and the external identities of the user in a single transaction
+func (r *AccountRepository) DeleteUser(ctx context.Context, userID int64) error {
+	tx, err := r.client.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	for _, table := range []string{"link_codes", "tokens", "identities"} {
+		if _, err := tx.Exec(ctx, "DELETE FROM "+table+" WHERE user_id = $1", userID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// replace deletes the rows of the user and of the key, then inserts the new row in the same transaction.
+// Both statements take the key as $1 and the user as $2, the insert gets the remaining arguments.
+func (r *AccountRepository) replace(ctx context.Context, deleteQuery, insertQuery, key string, userID int64, args ...any) error {
+	tx, err := r.client.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, deleteQuery, key, userID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, insertQuery, append([]any{key, userID}, args...)...); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// findUser reads the user ID of the row of the query, ok is false when no row matches
+func (r *AccountRepository) findUser(ctx context.Context, query string, args ...any) (int64, bool, error) {
+	var userID int64
+	err := r.client.pool.QueryRow(ctx, query, args...).Scan(&userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	return userID, true, nil
+}
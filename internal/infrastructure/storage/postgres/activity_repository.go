@@ -0,0 +1,120 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/storage/document"
+	"time"
+)
+
+// ActivityRepository keeps the learning activity of users in the activity table, one row per user
+type ActivityRepository struct {
+	client *Client
+}
+
+// NewActivityRepository creates a new PostgreSQL activity repository
+func NewActivityRepository(client *Client) *ActivityRepository {
+	return &ActivityRepository{client: client}
+}
+
+// RecordLookup counts the word for the day of at in its time zone, once per word and day, and keeps its answer
+func (r *ActivityRepository) RecordLookup(ctx context.Context, userID int64, word entities.LookedUpWord, at time.Time) error {
+	return r.update(ctx, userID, func(activity *document.Activity) bool {
+		return activity.RecordLookup(word, at)
+	})
+}
+
+// RecordQuizAnswer counts the answer for its day in its time zone and the article of the noun
+func (r *ActivityRepository) RecordQuizAnswer(ctx context.Context, userID int64, answer *entities.QuizAnswer) error {
+	return r.update(ctx, userID, func(activity *document.Activity) bool {
+		activity.RecordQuizAnswer(answer)
+		return true
+	})
+}
+
+// SearchWords returns at most limit words of the user matching the query, the best matches first
+func (r *ActivityRepository) SearchWords(ctx context.Context, userID int64, query string, limit int) ([]entities.WordMatch, error) {
+	activity, ok, err := r.load(ctx, userID)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	return activity.Search(query, limit), nil
+}
+
+// Activity returns the activity of the user, empty if none was recorded
+func (r *ActivityRepository) Activity(ctx context.Context, userID int64) (*entities.UserActivity, error) {
+	activity, ok, err := r.load(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return &entities.UserActivity{UserID: userID}, nil
+	}
+
+	return activity.Entity(userID), nil
+}
+
+// load reads the stored activity of the user, ok is false if none was recorded
+func (r *ActivityRepository) load(ctx context.Context, userID int64) (*document.Activity, bool, error) {
+	data, ok, err := r.client.get(ctx, "SELECT data FROM activity WHERE user_id = $1", userID)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	activity := document.NewActivity()
+	if err := json.Unmarshal(data, activity); err != nil {
+		return nil, false, fmt.Errorf("invalid activity of user %d: %w", userID, err)
+	}
+
+	return activity, true, nil
+}
+
+// Delete removes the activity of the user, unknown users are ignored
+func (r *ActivityRepository) Delete(ctx context.Context, userID int64) error {
+	_, err := r.client.exec(ctx, "DELETE FROM activity WHERE user_id = $1", userID)
+	return err
+}
+
+// DeleteInactive removes the activity of the users last active before the time and returns their number
+func (r *ActivityRepository) DeleteInactive(ctx context.Context, before time.Time) (int, error) {
+	return r.client.exec(ctx, "DELETE FROM activity WHERE (data->>'activeAt')::timestamptz < $1", before)
+}
+
+// update applies the change to the activity of the user in a transaction holding the lock of its row,
+// so concurrent instances don't lose each other's counts. The row is written only when the change reports
+// a modification.
+func (r *ActivityRepository) update(ctx context.Context, userID int64, change func(activity *document.Activity) bool) error {
+	tx, err := r.client.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// The row of a new user is created first, so its lock serializes the first updates too
+	if _, err := tx.Exec(ctx, "INSERT INTO activity (user_id, data) VALUES ($1, '{}') ON CONFLICT (user_id) DO NOTHING", userID); err != nil {
+		return err
+	}
+	var data []byte
+	if err := tx.QueryRow(ctx, "SELECT data FROM activity WHERE user_id = $1 FOR UPDATE", userID).Scan(&data); err != nil {
+		return err
+	}
+	activity := document.NewActivity()
+	if err := json.Unmarshal(data, activity); err != nil {
+		return fmt.Errorf("invalid activity of user %d: %w", userID, err)
+	}
+
+	if !change(activity) {
+		return nil
+	}
+	if data, err = json.Marshal(activity); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, "UPDATE activity SET data = $2 WHERE user_id = $1", userID, data); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+)
+
+// AuditRepository keeps the admin audit log in the audit table, entries are only ever inserted and ordered
+// by the sequence of their rows
+type AuditRepository struct {
+	client *Client
+}
+
+// NewAuditRepository creates a new PostgreSQL audit repository
+func NewAuditRepository(client *Client) *AuditRepository {
+	return &AuditRepository{client: client}
+}
+
+// Append stores the entry
+func (r *AuditRepository) Append(ctx context.Context, entry *entities.AuditEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.exec(ctx, "INSERT INTO audit (id, action, data) VALUES ($1, $2, $3)", entry.ID, entry.Action, data)
+	return err
+}
+
+// List returns at most limit entries of the action, of every action when it's empty, newest first
+func (r *AuditRepository) List(ctx context.Context, action entities.AuditAction, limit int) ([]*entities.AuditEntry, error) {
+	query, args := "SELECT data FROM audit ORDER BY seq DESC LIMIT $1", []any{limit}
+	if action != "" {
+		query, args = "SELECT data FROM audit WHERE action = $1 ORDER BY seq DESC LIMIT $2", []any{action, limit}
+	}
+
+	rows, err := r.client.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]*entities.AuditEntry, 0)
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to read audit entry: %w", err)
+		}
+		var entry entities.AuditEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return nil, fmt.Errorf("invalid audit entry: %w", err)
+		}
+		entries = append(entries, &entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,108 @@
+// Package postgres keeps the repositories in the tables of a PostgreSQL database
+package postgres
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/storage/migration"
+	"github.com/golang-migrate/migrate/v4/database"
+	migratepgx "github.com/golang-migrate/migrate/v4/database/pgx/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// migrations are the versioned schemas of the tables
+//
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// Client is the pool of connections to the PostgreSQL database shared by the repositories
+type Client struct {
+	pool *pgxpool.Pool
+	*migration.SQL
+}
+
+// NewClient connects to the database of the URL with at most maxConns connections
+func NewClient(ctx context.Context, url string, maxConns int) (*Client, error) {
+	config, err := pgxpool.ParseConfig(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PostgreSQL URL: %w", err)
+	}
+	if maxConns > 0 {
+		config.MaxConns = int32(maxConns)
+	}
+	pool, err := pgxpool.NewWithConfig(ctx, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create PostgreSQL pool: %w", err)
+	}
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("failed to connect to PostgreSQL: %w", err)
+	}
+
+	// The migrations run on a connection of the pool, concurrent instances wait for the advisory lock of the first one
+	open := func() (database.Driver, error) {
+		return migratepgx.WithInstance(stdlib.OpenDBFromPool(pool), &migratepgx.Config{})
+	}
+
+	return &Client{pool: pool, SQL: migration.NewSQL("postgres", migrations, "migrations", open)}, nil
+}
+
+// RemoveExpired removes the expired answers, jobs, idempotency keys, link codes, conversations and replies
+func (c *Client) RemoveExpired(ctx context.Context) error {
+	for _, table := range []string{"cache", "jobs", "idempotency", "link_codes", "conversations", "replies"} {
+		if _, err := c.pool.Exec(ctx, "DELETE FROM "+table+" WHERE expires_at < now()"); err != nil {
+			return fmt.Errorf("failed to remove expired %s: %w", table, err)
+		}
+	}
+
+	return nil
+}
+
+// Close closes the connections of the pool
+func (c *Client) Close() error {
+	c.pool.Close()
+	return nil
+}
+
+// Name returns the dependency name
+func (c *Client) Name() string {
+	return "postgres"
+}
+
+// Critical reports that the stored answers and user data aren't available without the database
+func (c *Client) Critical() bool {
+	return true
+}
+
+// Check pings the database with a connection of the pool
+func (c *Client) Check(ctx context.Context) error {
+	return c.pool.Ping(ctx)
+}
+
+// get reads the data of the row of the query, ok is false when no row matches
+func (c *Client) get(ctx context.Context, query string, args ...any) ([]byte, bool, error) {
+	var data []byte
+	err := c.pool.QueryRow(ctx, query, args...).Scan(&data)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	return data, true, nil
+}
+
+// exec runs the statement and returns the number of affected rows
+func (c *Client) exec(ctx context.Context, query string, args ...any) (int, error) {
+	tag, err := c.pool.Exec(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(tag.RowsAffected()), nil
+}
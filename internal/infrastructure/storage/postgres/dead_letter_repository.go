@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"time"
+)
+
+// DeadLetterRepository keeps the failed Telegram updates in the dead_letters table until they are replayed
+// or purged by the retention sweep
+type DeadLetterRepository struct {
+	client *Client
+}
+
+// NewDeadLetterRepository creates a new PostgreSQL dead letter repository
+func NewDeadLetterRepository(client *Client) *DeadLetterRepository {
+	return &DeadLetterRepository{client: client}
+}
+
+// Save stores the dead letter, replacing the previous one with the same ID
+func (r *DeadLetterRepository) Save(ctx context.Context, letter *entities.DeadLetter) error {
+	data, err := json.Marshal(letter)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.exec(ctx, "INSERT INTO dead_letters (id, user_id, failed_at, data) VALUES ($1, $2, $3, $4) ON CONFLICT (id) DO UPDATE SET user_id = excluded.user_id, failed_at = excluded.failed_at, data = excluded.data",
+		letter.ID, letter.UserID, letter.FailedAt, data)
+	return err
+}
+
+// Get returns the dead letter
+func (r *DeadLetterRepository) Get(ctx context.Context, id string) (*entities.DeadLetter, bool, error) {
+	data, ok, err := r.client.get(ctx, "SELECT data FROM dead_letters WHERE id = $1", id)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	var letter entities.DeadLetter
+	if err := json.Unmarshal(data, &letter); err != nil {
+		return nil, false, fmt.Errorf("invalid dead letter %s: %w", id, err)
+	}
+
+	return &letter, true, nil
+}
+
+// List returns at most limit dead letters, oldest first
+func (r *DeadLetterRepository) List(ctx context.Context, limit int) ([]*entities.DeadLetter, error) {
+	return r.list(ctx, "SELECT data FROM dead_letters ORDER BY failed_at, id LIMIT $1", limit)
+}
+
+// Delete removes the dead letter, unknown IDs are ignored
+func (r *DeadLetterRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.client.exec(ctx, "DELETE FROM dead_letters WHERE id = $1", id)
+	return err
+}
+
+// ListUser returns the dead letters of the user, oldest first
+func (r *DeadLetterRepository) ListUser(ctx context.Context, userID int64) ([]*entities.DeadLetter, error) {
+	return r.list(ctx, "SELECT data FROM dead_letters WHERE user_id = $1 ORDER BY failed_at, id", userID)
+}
+
+// DeleteUser removes the dead letters of the user
+func (r *DeadLetterRepository) DeleteUser(ctx context.Context, userID int64) error {
+	_, err := r.client.exec(ctx, "DELETE FROM dead_letters WHERE user_id = $1", userID)
+	return err
+}
+
+// DeleteBefore removes the dead letters that failed before the time and returns their number
+func (r *DeadLetterRepository) DeleteBefore(ctx context.Context, before time.Time) (int, error) {
+	return r.client.exec(ctx, "DELETE FROM dead_letters WHERE failed_at < $1", before)
+}
+
+// list reads the dead letters of the query
+func (r *DeadLetterRepository) list(ctx context.Context, query string, args ...any) ([]*entities.DeadLetter, error) {
+	rows, err := r.client.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dead letters: %w", err)
+	}
+	defer rows.Close()
+
+	letters := make([]*entities.DeadLetter, 0)
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to read dead letter: %w", err)
+		}
+		var letter entities.DeadLetter
+		if err := json.Unmarshal(data, &letter); err != nil {
+			return nil, fmt.Errorf("invalid dead letter: %w", err)
+		}
+		letters = append(letters, &letter)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list dead letters: %w", err)
+	}
+
+	return letters, nil
+}
@@ -0,0 +1,165 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/storage/document"
+	"strings"
+	"time"
+)
+
+// feedbackColumns maps the ordering fields of feedback lists to the columns of the feedback table
+var feedbackColumns = map[string]string{
+	"createdAt": "created_at",
+	"word":      "word",
+}
+
+// FeedbackRepository keeps user ratings in the feedback table, the filtered and ordered fields are
+// kept in columns next to the JSON of the rating
+type FeedbackRepository struct {
+	client *Client
+}
+
+// NewFeedbackRepository creates a new PostgreSQL feedback repository
+func NewFeedbackRepository(client *Client) *FeedbackRepository {
+	return &FeedbackRepository{client: client}
+}
+
+// Save stores the feedback
+func (r *FeedbackRepository) Save(ctx context.Context, feedback *entities.Feedback) error {
+	data, err := json.Marshal(feedback)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.exec(ctx,
+		`INSERT INTO feedback (id, verdict, word, language, created_at, data) VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (id) DO UPDATE SET verdict = excluded.verdict, word = excluded.word, language = excluded.language,
+		created_at = excluded.created_at, data = excluded.data`,
+		feedback.ID, string(feedback.Verdict), strings.ToLower(feedback.Word), feedback.Language, document.TimeKey(feedback.CreatedAt), data)
+	return err
+}
+
+// List returns a page of the matching feedback, newest first unless the page orders it otherwise
+func (r *FeedbackRepository) List(ctx context.Context, filter entities.FeedbackFilter) (entities.Page[*entities.Feedback], error) {
+	page, keyOf := document.FeedbackPage(filter)
+	after, err := document.After(page)
+	if err != nil {
+		return entities.Page[*entities.Feedback]{}, err
+	}
+
+	column := feedbackColumns[page.OrderBy]
+	var (
+		conditions []string
+		args       []any
+	)
+	arg := func(value any) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	if filter.Verdict != "" {
+		conditions = append(conditions, "verdict = "+arg(string(filter.Verdict)))
+	}
+	if filter.Word != "" {
+		conditions = append(conditions, "word = "+arg(strings.ToLower(filter.Word)))
+	}
+	if filter.Language != "" {
+		conditions = append(conditions, "language = "+arg(filter.Language))
+	}
+	direction, beyond := "ASC", ">"
+	if page.Descending {
+		direction, beyond = "DESC", "<"
+	}
+	if after != nil {
+		// Ties are broken by ascending ID as in the lists of every backend
+		key := arg(after.Key)
+		conditions = append(conditions, fmt.Sprintf("(%s %s %s OR (%s = %s AND id > %s))", column, beyond, key, column, key, arg(after.ID)))
+	}
+
+	query := "SELECT data FROM feedback"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += fmt.Sprintf(" ORDER BY %s %s, id ASC", column, direction)
+	if page.Limit > 0 {
+		// One more rating tells whether a next page exists
+		query += " LIMIT " + arg(page.Limit+1)
+	}
+
+	rows, err := r.client.pool.Query(ctx, query, args...)
+	if err != nil {
+		return entities.Page[*entities.Feedback]{}, fmt.Errorf("failed to list feedback: %w", err)
+	}
+	defer rows.Close()
+
+	result := entities.Page[*entities.Feedback]{Items: make([]*entities.Feedback, 0)}
+	for rows.Next() {
+		if page.Limit > 0 && len(result.Items) == page.Limit {
+			result.NextPageToken = document.NextPageToken(page, keyOf(result.Items[len(result.Items)-1]))
+			break
+		}
+
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return entities.Page[*entities.Feedback]{}, fmt.Errorf("failed to read feedback: %w", err)
+		}
+		var feedback entities.Feedback
+		if err := json.Unmarshal(data, &feedback); err != nil {
+			return entities.Page[*entities.Feedback]{}, fmt.Errorf("invalid feedback: %w", err)
+		}
+		result.Items = append(result.Items, &feedback)
+	}
+	if err := rows.Err(); err != nil {
+		return entities.Page[*entities.Feedback]{}, fmt.Errorf("failed to list feedback: %w", err)
+	}
+
+	return result, nil
+}
+
+// ListUser returns the ratings of the user, oldest first
+func (r *FeedbackRepository) ListUser(ctx context.Context, userID int64) ([]*entities.Feedback, error) {
+	rows, err := r.client.pool.Query(ctx, "SELECT data FROM feedback WHERE (data->>'userId')::bigint = $1 ORDER BY created_at, id", userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list feedback of user %d: %w", userID, err)
+	}
+	defer rows.Close()
+
+	result := make([]*entities.Feedback, 0)
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to read feedback: %w", err)
+		}
+		var feedback entities.Feedback
+		if err := json.Unmarshal(data, &feedback); err != nil {
+			return nil, fmt.Errorf("invalid feedback: %w", err)
+		}
+		result = append(result, &feedback)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list feedback of user %d: %w", userID, err)
+	}
+
+	return result, nil
+}
+
+// DeleteUser removes the ratings of the user
+func (r *FeedbackRepository) DeleteUser(ctx context.Context, userID int64) error {
+	if _, err := r.client.exec(ctx, "DELETE FROM feedback WHERE (data->>'userId')::bigint = $1", userID); err != nil {
+		return fmt.Errorf("failed to delete feedback of user %d: %w", userID, err)
+	}
+
+	return nil
+}
+
+// DeleteBefore removes the ratings created before the time and returns their number
+func (r *FeedbackRepository) DeleteBefore(ctx context.Context, before time.Time) (int, error) {
+	removed, err := r.client.exec(ctx, "DELETE FROM feedback WHERE created_at < $1", document.TimeKey(before))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete feedback: %w", err)
+	}
+
+	return removed, nil
+}
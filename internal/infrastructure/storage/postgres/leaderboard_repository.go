@@ -0,0 +1,150 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+)
+
+// LeaderboardRepository keeps the leaderboard members in the leaderboard_members table and their weekly
+// scores in the leaderboard_scores table, the scores of the current and the previous week are kept
+type LeaderboardRepository struct {
+	client *Client
+}
+
+// NewLeaderboardRepository creates a new PostgreSQL leaderboard repository
+func NewLeaderboardRepository(client *Client) *LeaderboardRepository {
+	return &LeaderboardRepository{client: client}
+}
+
+// SaveMember adds the member or updates the display name of the member
+func (r *LeaderboardRepository) SaveMember(ctx context.Context, member *entities.LeaderboardMember) error {
+	data, err := json.Marshal(member)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.exec(ctx,
+		"INSERT INTO leaderboard_members (user_id, data) VALUES ($1, $2) ON CONFLICT (user_id) DO UPDATE SET data = excluded.data",
+		member.UserID, data)
+	return err
+}
+
+// DeleteMember removes the member together with all scores of the member in a single transaction
+func (r *LeaderboardRepository) DeleteMember(ctx context.Context, userID int64) error {
+	tx, err := r.client.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	for _, table := range []string{"leaderboard_members", "leaderboard_scores"} {
+		if _, err := tx.Exec(ctx, "DELETE FROM "+table+" WHERE user_id = $1", userID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Member returns the member of the user, nil for users who didn't opt in
+func (r *LeaderboardRepository) Member(ctx context.Context, userID int64) (*entities.LeaderboardMember, error) {
+	data, ok, err := r.client.get(ctx, "SELECT data FROM leaderboard_members WHERE user_id = $1", userID)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	var member entities.LeaderboardMember
+	if err := json.Unmarshal(data, &member); err != nil {
+		return nil, fmt.Errorf("invalid leaderboard member %d: %w", userID, err)
+	}
+
+	return &member, nil
+}
+
+// RecordScore counts the answer of the member in the week of the chat, the weeks before the previous one
+// are dropped in the same transaction, ISO week names sort chronologically
+func (r *LeaderboardRepository) RecordScore(ctx context.Context, week string, chatID int64, userID int64, correct bool) error {
+	tx, err := r.client.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx,
+		"INSERT INTO leaderboard_scores (week, chat_id, user_id, answers, correct) VALUES ($1, $2, $3, 1, $4) "+
+			"ON CONFLICT (week, chat_id, user_id) DO UPDATE SET answers = leaderboard_scores.answers + 1, correct = leaderboard_scores.correct + excluded.correct",
+		week, chatID, userID, countCorrect(correct)); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx,
+		"DELETE FROM leaderboard_scores WHERE week < (SELECT MAX(week) FROM leaderboard_scores WHERE week < $1)", week); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Scores returns the scores of the week in the chat in no particular order
+func (r *LeaderboardRepository) Scores(ctx context.Context, week string, chatID int64) ([]entities.LeaderboardScore, error) {
+	rows, err := r.client.pool.Query(ctx,
+		"SELECT user_id, answers, correct FROM leaderboard_scores WHERE week = $1 AND chat_id = $2", week, chatID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list leaderboard scores: %w", err)
+	}
+	defer rows.Close()
+
+	scores := make([]entities.LeaderboardScore, 0)
+	for rows.Next() {
+		var score entities.LeaderboardScore
+		if err := rows.Scan(&score.UserID, &score.Answers, &score.Correct); err != nil {
+			return nil, fmt.Errorf("failed to read leaderboard score: %w", err)
+		}
+		scores = append(scores, score)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list leaderboard scores: %w", err)
+	}
+
+	return scores, nil
+}
+
+// DeleteChat removes the scores of the group chat in all weeks
+func (r *LeaderboardRepository) DeleteChat(ctx context.Context, chatID int64) error {
+	_, err := r.client.exec(ctx, "DELETE FROM leaderboard_scores WHERE chat_id = $1", chatID)
+	return err
+}
+
+// Chats returns the group chats with scores in the week, without LeaderboardGlobal
+func (r *LeaderboardRepository) Chats(ctx context.Context, week string) ([]int64, error) {
+	rows, err := r.client.pool.Query(ctx,
+		"SELECT DISTINCT chat_id FROM leaderboard_scores WHERE week = $1 AND chat_id <> $2 ORDER BY chat_id", week, entities.LeaderboardGlobal)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list leaderboard chats: %w", err)
+	}
+	defer rows.Close()
+
+	var chats []int64
+	for rows.Next() {
+		var chatID int64
+		if err := rows.Scan(&chatID); err != nil {
+			return nil, fmt.Errorf("failed to read leaderboard chat: %w", err)
+		}
+		chats = append(chats, chatID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list leaderboard chats: %w", err)
+	}
+
+	return chats, nil
+}
+
+// countCorrect returns the increment of the correct answers
+func countCorrect(correct bool) int {
+	if correct {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS activity;
+DROP TABLE IF EXISTS jobs;
+DROP TABLE IF EXISTS feedback;
+DROP TABLE IF EXISTS preferences;
+DROP TABLE IF EXISTS cache;
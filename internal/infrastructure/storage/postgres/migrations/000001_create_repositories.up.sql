@@ -0,0 +1,34 @@
+-- The keys compare bytewise, so the variants of a cache key and the page cursors of the feedback lists
+-- are ordered as in every other backend
+CREATE TABLE IF NOT EXISTS cache (
+    key        TEXT COLLATE "C" PRIMARY KEY,
+    data       JSONB NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS preferences (
+    user_id BIGINT PRIMARY KEY,
+    data    JSONB NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS feedback (
+    id         TEXT COLLATE "C" PRIMARY KEY,
+    verdict    TEXT NOT NULL,
+    word       TEXT COLLATE "C" NOT NULL,
+    language   TEXT NOT NULL,
+    created_at TEXT COLLATE "C" NOT NULL,
+    data       JSONB NOT NULL
+);
+CREATE INDEX IF NOT EXISTS feedback_created_at ON feedback (created_at, id);
+CREATE INDEX IF NOT EXISTS feedback_word ON feedback (word, id);
+
+CREATE TABLE IF NOT EXISTS jobs (
+    id         TEXT PRIMARY KEY,
+    data       JSONB NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS activity (
+    user_id BIGINT PRIMARY KEY,
+    data    JSONB NOT NULL
+);
@@ -0,0 +1,4 @@
+CREATE TABLE IF NOT EXISTS prompts (
+    tenant_id TEXT PRIMARY KEY,
+    data      JSONB NOT NULL
+);
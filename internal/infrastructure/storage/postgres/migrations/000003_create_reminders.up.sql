@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS reminders (
+    user_id BIGINT PRIMARY KEY,
+    next_at TIMESTAMPTZ NOT NULL,
+    data    JSONB NOT NULL
+);
+CREATE INDEX IF NOT EXISTS reminders_next_at ON reminders (next_at);
@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS idempotency (
+    key        TEXT PRIMARY KEY,
+    data       JSONB NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL
+);
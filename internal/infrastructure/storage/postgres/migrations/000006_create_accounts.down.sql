@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS identities;
+DROP TABLE IF EXISTS tokens;
+DROP TABLE IF EXISTS link_codes;
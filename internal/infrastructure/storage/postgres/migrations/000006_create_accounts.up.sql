@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS link_codes (
+    code       TEXT PRIMARY KEY,
+    user_id    BIGINT NOT NULL UNIQUE,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+CREATE TABLE IF NOT EXISTS tokens (
+    token_hash TEXT PRIMARY KEY,
+    user_id    BIGINT NOT NULL UNIQUE
+);
+CREATE TABLE IF NOT EXISTS identities (
+    provider TEXT COLLATE "C" NOT NULL,
+    subject  TEXT COLLATE "C" NOT NULL,
+    user_id  BIGINT NOT NULL,
+    PRIMARY KEY (provider, subject)
+);
+CREATE INDEX IF NOT EXISTS identities_user_id ON identities (user_id);
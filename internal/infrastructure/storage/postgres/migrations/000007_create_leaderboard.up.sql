@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS leaderboard_members (
+    user_id BIGINT PRIMARY KEY,
+    data    JSONB NOT NULL
+);
+CREATE TABLE IF NOT EXISTS leaderboard_scores (
+    week    TEXT COLLATE "C" NOT NULL,
+    chat_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    answers INTEGER NOT NULL,
+    correct INTEGER NOT NULL,
+    PRIMARY KEY (week, chat_id, user_id)
+);
+CREATE INDEX IF NOT EXISTS leaderboard_scores_chat_id ON leaderboard_scores (chat_id);
+CREATE INDEX IF NOT EXISTS leaderboard_scores_user_id ON leaderboard_scores (user_id);
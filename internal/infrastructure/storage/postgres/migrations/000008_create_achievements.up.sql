@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS achievements (
+    user_id    BIGINT NOT NULL,
+    id         TEXT COLLATE "C" NOT NULL,
+    awarded_at TIMESTAMPTZ NOT NULL,
+    data       JSONB NOT NULL,
+    PRIMARY KEY (user_id, id)
+);
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS dead_letters (
+    id        TEXT COLLATE "C" PRIMARY KEY,
+    user_id   BIGINT NOT NULL,
+    failed_at TIMESTAMPTZ NOT NULL,
+    data      JSONB NOT NULL
+);
+CREATE INDEX IF NOT EXISTS dead_letters_failed_at ON dead_letters (failed_at);
+CREATE INDEX IF NOT EXISTS dead_letters_user_id ON dead_letters (user_id);
@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS chats (
+    id        BIGINT PRIMARY KEY,
+    opted_out BOOLEAN NOT NULL,
+    data      JSONB NOT NULL
+);
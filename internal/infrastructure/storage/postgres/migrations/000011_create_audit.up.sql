@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS audit (
+    seq    BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    id     TEXT COLLATE "C" NOT NULL UNIQUE,
+    action TEXT COLLATE "C" NOT NULL,
+    data   JSONB NOT NULL
+);
+CREATE INDEX IF NOT EXISTS audit_action ON audit (action, seq);
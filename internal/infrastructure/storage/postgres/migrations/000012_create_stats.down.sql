@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS stats_users;
+DROP TABLE IF EXISTS stats_spend;
+DROP TABLE IF EXISTS stats_words;
+DROP TABLE IF EXISTS stats_counters;
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS stats_counters (
+    name  TEXT COLLATE "C" PRIMARY KEY,
+    value BIGINT NOT NULL
+);
+-- The since counter holds the start of the counting in nanoseconds since the epoch
+INSERT INTO stats_counters (name, value) VALUES ('since', (EXTRACT(EPOCH FROM now()) * 1000000000)::BIGINT)
+    ON CONFLICT (name) DO NOTHING;
+CREATE TABLE IF NOT EXISTS stats_words (
+    word    TEXT COLLATE "C" PRIMARY KEY,
+    lookups BIGINT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS stats_words_lookups ON stats_words (lookups, word);
+CREATE TABLE IF NOT EXISTS stats_spend (
+    month TEXT PRIMARY KEY,
+    spent DOUBLE PRECISION NOT NULL
+);
+CREATE TABLE IF NOT EXISTS stats_users (
+    user_id BIGINT PRIMARY KEY,
+    seen_at TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX IF NOT EXISTS stats_users_seen_at ON stats_users (seen_at);
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS replies (
+    chat_id    BIGINT NOT NULL,
+    message_id BIGINT NOT NULL,
+    reply_id   BIGINT NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (chat_id, message_id)
+);
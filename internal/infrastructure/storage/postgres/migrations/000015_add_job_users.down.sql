@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS jobs_user_id;
+ALTER TABLE jobs DROP COLUMN IF EXISTS user_id;
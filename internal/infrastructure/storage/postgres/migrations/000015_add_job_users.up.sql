@@ -0,0 +1,2 @@
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS user_id BIGINT NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS jobs_user_id ON jobs (user_id);
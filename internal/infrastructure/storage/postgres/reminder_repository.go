@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"time"
+)
+
+// ReminderRepository keeps the practice reminders in the reminders table, indexed by the time they are due
+type ReminderRepository struct {
+	client *Client
+}
+
+// NewReminderRepository creates a new PostgreSQL reminder repository
+func NewReminderRepository(client *Client) *ReminderRepository {
+	return &ReminderRepository{client: client}
+}
+
+// Get returns the reminder of the user, nil if none is set
+func (r *ReminderRepository) Get(ctx context.Context, userID int64) (*entities.Reminder, error) {
+	data, ok, err := r.client.get(ctx, "SELECT data FROM reminders WHERE user_id = $1", userID)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	var reminder entities.Reminder
+	if err := json.Unmarshal(data, &reminder); err != nil {
+		return nil, fmt.Errorf("invalid reminder of user %d: %w", userID, err)
+	}
+
+	return &reminder, nil
+}
+
+// Save replaces the reminder of the user
+func (r *ReminderRepository) Save(ctx context.Context, reminder *entities.Reminder) error {
+	data, err := json.Marshal(reminder)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.exec(ctx,
+		"INSERT INTO reminders (user_id, next_at, data) VALUES ($1, $2, $3) ON CONFLICT (user_id) DO UPDATE SET next_at = excluded.next_at, data = excluded.data",
+		reminder.UserID, reminder.NextAt, data)
+	return err
+}
+
+// Delete removes the reminder of the user
+func (r *ReminderRepository) Delete(ctx context.Context, userID int64) error {
+	_, err := r.client.exec(ctx, "DELETE FROM reminders WHERE user_id = $1", userID)
+	return err
+}
+
+// Due returns at most limit reminders due at the time, the longest due first
+func (r *ReminderRepository) Due(ctx context.Context, now time.Time, limit int) ([]entities.Reminder, error) {
+	rows, err := r.client.pool.Query(ctx, "SELECT data FROM reminders WHERE next_at <= $1 ORDER BY next_at LIMIT $2", now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due reminders: %w", err)
+	}
+	defer rows.Close()
+
+	result := make([]entities.Reminder, 0)
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to read reminder: %w", err)
+		}
+		var reminder entities.Reminder
+		if err := json.Unmarshal(data, &reminder); err != nil {
+			return nil, fmt.Errorf("invalid reminder: %w", err)
+		}
+		result = append(result, reminder)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list due reminders: %w", err)
+	}
+
+	return result, nil
+}
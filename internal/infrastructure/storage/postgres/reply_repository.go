@@ -0,0 +1,43 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"time"
+)
+
+// ReplyRepository keeps the answers to the messages in the replies table for ttl after the answer
+type ReplyRepository struct {
+	client *Client
+	ttl    time.Duration
+}
+
+// NewReplyRepository creates a new PostgreSQL reply repository keeping the answers for ttl
+func NewReplyRepository(client *Client, ttl time.Duration) *ReplyRepository {
+	return &ReplyRepository{client: client, ttl: ttl}
+}
+
+// Get returns the message ID of the answer to the message of the chat
+func (r *ReplyRepository) Get(ctx context.Context, chatID int64, messageID int) (int, bool, error) {
+	var replyID int
+	err := r.client.pool.QueryRow(ctx,
+		"SELECT reply_id FROM replies WHERE chat_id = $1 AND message_id = $2 AND expires_at >= $3",
+		chatID, messageID, time.Now()).Scan(&replyID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	return replyID, true, nil
+}
+
+// Save stores the answer to the message
+func (r *ReplyRepository) Save(ctx context.Context, chatID int64, messageID, replyID int) error {
+	_, err := r.client.exec(ctx,
+		"INSERT INTO replies (chat_id, message_id, reply_id, expires_at) VALUES ($1, $2, $3, $4) ON CONFLICT (chat_id, message_id) DO UPDATE SET reply_id = excluded.reply_id, expires_at = excluded.expires_at",
+		chatID, messageID, replyID, time.Now().Add(r.ttl))
+	return err
+}
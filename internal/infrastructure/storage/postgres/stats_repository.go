@@ -0,0 +1,296 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/storage/document"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StatsRepository keeps the usage metrics in the stats tables: named counters, the lookups of every word,
+// the AI spend of every month and the time every Telegram user was last seen. Recording never fails the
+// request, failures are logged.
+type StatsRepository struct {
+	client   *Client
+	callCost float64
+	logger   logging.Logger
+}
+
+// NewStatsRepository creates a new PostgreSQL stats repository adding callCost USD to the monthly spend of every AI call
+func NewStatsRepository(client *Client, callCost float64, logger logging.Logger) *StatsRepository {
+	return &StatsRepository{client: client, callCost: callCost, logger: logger}
+}
+
+// RecordLookup increments the lookup counters of the word and of the adapter
+func (r *StatsRepository) RecordLookup(ctx context.Context, word, _ string, adapter entities.Adapter) {
+	key := document.WordKey(word)
+	if key == "" {
+		return
+	}
+
+	err := r.inTx(ctx, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx,
+			"INSERT INTO stats_words (word, lookups) VALUES ($1, 1) ON CONFLICT (word) DO UPDATE SET lookups = stats_words.lookups + 1",
+			key); err != nil {
+			return err
+		}
+		if adapter == "" {
+			return nil
+		}
+		return increment(ctx, tx, document.AdapterCounters+string(adapter))
+	})
+	r.logFailure(ctx, err, "Failed to record lookup")
+}
+
+// RecordCacheLookup increments the cache hit or miss counter
+func (r *StatsRepository) RecordCacheLookup(ctx context.Context, hit bool) {
+	counter := document.CacheMissesCounter
+	if hit {
+		counter = document.CacheHitsCounter
+	}
+
+	r.logFailure(ctx, increment(ctx, r.client.pool, counter), "Failed to record cache lookup")
+}
+
+// RecordAICall increments the AI call counters, the daily quota usage and the monthly spend
+func (r *StatsRepository) RecordAICall(ctx context.Context, failed bool) {
+	now := time.Now().UTC()
+
+	err := r.inTx(ctx, func(tx pgx.Tx) error {
+		if err := increment(ctx, tx, document.AICallCounters(now, failed)...); err != nil {
+			return err
+		}
+		// Failed calls are billed as well when the model has answered
+		_, err := tx.Exec(ctx,
+			"INSERT INTO stats_spend (month, spent) VALUES ($1, $2) ON CONFLICT (month) DO UPDATE SET spent = stats_spend.spent + excluded.spent",
+			now.Format(document.SpendMonthLayout), r.callCost)
+		return err
+	})
+	r.logFailure(ctx, err, "Failed to record AI call")
+}
+
+// MonthlySpend returns the estimated AI spend of the current month in USD
+func (r *StatsRepository) MonthlySpend(ctx context.Context) (float64, error) {
+	var spent float64
+	err := r.client.pool.QueryRow(ctx, "SELECT spent FROM stats_spend WHERE month = $1",
+		time.Now().UTC().Format(document.SpendMonthLayout)).Scan(&spent)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return 0, fmt.Errorf("failed to read monthly spend: %w", err)
+	}
+
+	return spent, nil
+}
+
+// RecordTelegramUser marks the Telegram user as active now
+func (r *StatsRepository) RecordTelegramUser(ctx context.Context, userID int64) {
+	_, err := r.client.exec(ctx,
+		"INSERT INTO stats_users (user_id, seen_at) VALUES ($1, $2) ON CONFLICT (user_id) DO UPDATE SET seen_at = excluded.seen_at",
+		userID, time.Now())
+	r.logFailure(ctx, err, "Failed to record Telegram user")
+}
+
+// DeleteUser forgets when the Telegram user was last seen
+func (r *StatsRepository) DeleteUser(ctx context.Context, userID int64) error {
+	_, err := r.client.exec(ctx, "DELETE FROM stats_users WHERE user_id = $1", userID)
+	return err
+}
+
+// RecordChatMemberEvent increments the churn counter of the event, unknown events are ignored
+func (r *StatsRepository) RecordChatMemberEvent(ctx context.Context, event entities.ChatMemberEvent) {
+	counter, ok := document.ChurnCounters[event]
+	if !ok {
+		return
+	}
+
+	r.logFailure(ctx, increment(ctx, r.client.pool, counter), "Failed to record chat member event")
+}
+
+// Snapshot returns the current metrics with the given number of top words
+func (r *StatsRepository) Snapshot(ctx context.Context, topWords int) (*entities.DashboardStats, error) {
+	counters, err := r.counters(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	stats := document.Dashboard(counters, now)
+
+	if stats.TopWords, err = r.topWords(ctx, topWords); err != nil {
+		return nil, err
+	}
+	if stats.Spend.Spent, err = r.MonthlySpend(ctx); err != nil {
+		return nil, err
+	}
+	err = r.client.pool.QueryRow(ctx,
+		"SELECT COUNT(*) FILTER (WHERE seen_at >= $1), COUNT(*) FROM stats_users WHERE seen_at >= $2",
+		now.Add(-24*time.Hour), now.Add(-7*24*time.Hour)).
+		Scan(&stats.Telegram.ActiveUsers24h, &stats.Telegram.ActiveUsers7d)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count active Telegram users: %w", err)
+	}
+
+	return stats, nil
+}
+
+// ListWords returns a page of the looked-up words, most looked-up first unless the page orders them otherwise
+func (r *StatsRepository) ListWords(ctx context.Context, filter entities.WordStatFilter) (entities.Page[entities.WordStat], error) {
+	page, keyOf := document.WordPage(filter)
+	after, err := document.After(page)
+	if err != nil {
+		return entities.Page[entities.WordStat]{}, err
+	}
+
+	column := "word"
+	if page.OrderBy == "lookups" {
+		column = "lookups"
+	}
+	var (
+		conditions []string
+		args       []any
+	)
+	if prefix := document.WordKey(filter.Prefix); prefix != "" {
+		args = append(args, prefix)
+		conditions = append(conditions, fmt.Sprintf("starts_with(word, $%d)", len(args)))
+	}
+	direction, beyond := "ASC", ">"
+	if page.Descending {
+		direction, beyond = "DESC", "<"
+	}
+	if after != nil {
+		var key any = after.Key
+		if column == "lookups" {
+			if key, err = strconv.ParseInt(after.Key, 10, 64); err != nil {
+				return entities.Page[entities.WordStat]{}, entities.ErrInvalidPageToken
+			}
+		}
+		// Ties are broken by ascending word as in the lists of every backend
+		args = append(args, key, after.ID)
+		conditions = append(conditions, fmt.Sprintf("(%s %s $%d OR (%s = $%d AND word > $%d))",
+			column, beyond, len(args)-1, column, len(args)-1, len(args)))
+	}
+
+	query := "SELECT word, lookups FROM stats_words"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += fmt.Sprintf(" ORDER BY %s %s, word ASC", column, direction)
+	if page.Limit > 0 {
+		// One more word tells whether a next page exists
+		args = append(args, page.Limit+1)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	words, err := r.words(ctx, query, args...)
+	if err != nil {
+		return entities.Page[entities.WordStat]{}, err
+	}
+	result := entities.Page[entities.WordStat]{Items: words}
+	if page.Limit > 0 && len(words) > page.Limit {
+		result.Items = words[:page.Limit]
+		result.NextPageToken = document.NextPageToken(page, keyOf(result.Items[page.Limit-1]))
+	}
+
+	return result, nil
+}
+
+// counters reads every named counter
+func (r *StatsRepository) counters(ctx context.Context) (map[string]int64, error) {
+	rows, err := r.client.pool.Query(ctx, "SELECT name, value FROM stats_counters")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stats counters: %w", err)
+	}
+	defer rows.Close()
+
+	counters := make(map[string]int64)
+	for rows.Next() {
+		var name string
+		var value int64
+		if err := rows.Scan(&name, &value); err != nil {
+			return nil, fmt.Errorf("failed to read stats counter: %w", err)
+		}
+		counters[name] = value
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read stats counters: %w", err)
+	}
+
+	return counters, nil
+}
+
+// topWords returns the most looked-up words, all of them when limit isn't positive
+func (r *StatsRepository) topWords(ctx context.Context, limit int) ([]entities.WordStat, error) {
+	if limit <= 0 {
+		return r.words(ctx, "SELECT word, lookups FROM stats_words ORDER BY lookups DESC, word ASC")
+	}
+
+	return r.words(ctx, "SELECT word, lookups FROM stats_words ORDER BY lookups DESC, word ASC LIMIT $1", limit)
+}
+
+// words reads the words of the query
+func (r *StatsRepository) words(ctx context.Context, query string, args ...any) ([]entities.WordStat, error) {
+	rows, err := r.client.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list words: %w", err)
+	}
+	defer rows.Close()
+
+	words := make([]entities.WordStat, 0)
+	for rows.Next() {
+		var stat entities.WordStat
+		if err := rows.Scan(&stat.Word, &stat.Lookups); err != nil {
+			return nil, fmt.Errorf("failed to read word: %w", err)
+		}
+		words = append(words, stat)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list words: %w", err)
+	}
+
+	return words, nil
+}
+
+// inTx runs the statements of apply in a transaction
+func (r *StatsRepository) inTx(ctx context.Context, apply func(tx pgx.Tx) error) error {
+	tx, err := r.client.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := apply(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// logFailure logs a failure to record a metric, the request goes on without it
+func (r *StatsRepository) logFailure(ctx context.Context, err error, message string) {
+	if err != nil {
+		r.logger.With(ctx).Err(err).Sampled().Warning(message)
+	}
+}
+
+// execer runs statements in the database or in a transaction
+type execer interface {
+	Exec(ctx context.Context, query string, args ...any) (pgconn.CommandTag, error)
+}
+
+// increment adds one to every named counter
+func increment(ctx context.Context, db execer, names ...string) error {
+	for _, name := range names {
+		if _, err := db.Exec(ctx,
+			"INSERT INTO stats_counters (name, value) VALUES ($1, 1) ON CONFLICT (name) DO UPDATE SET value = stats_counters.value + 1",
+			name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
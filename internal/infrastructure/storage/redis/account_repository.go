@@ -0,0 +1,180 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	goredis "github.com/redis/go-redis/v9"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AccountRepository keeps the link codes, the API token hashes and the linked identities in Redis keys
+// holding the user ID, the keys of the user find them again to replace and delete them. The link codes
+// expire with their TTL.
+type AccountRepository struct {
+	client *Client
+}
+
+// NewAccountRepository creates a new Redis account repository
+func NewAccountRepository(client *Client) *AccountRepository {
+	return &AccountRepository{client: client}
+}
+
+// SaveLinkCode stores the one-time code of the user for ttl, the previous code of the user is dropped
+func (r *AccountRepository) SaveLinkCode(ctx context.Context, code string, userID int64, ttl time.Duration) error {
+	id := strconv.FormatInt(userID, 10)
+	previous, ok, err := r.client.get(ctx, userLinkCodeKeys+id)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		if ok {
+			pipe.Del(ctx, linkCodeKeys+string(previous))
+		}
+		pipe.Set(ctx, linkCodeKeys+code, id, ttl)
+		pipe.Set(ctx, userLinkCodeKeys+id, code, ttl)
+		return nil
+	})
+	return err
+}
+
+// TakeLinkCode returns the user of the code and deletes it, false for unknown and expired codes. The key
+// of the user expires with the code.
+func (r *AccountRepository) TakeLinkCode(ctx context.Context, code string) (int64, bool, error) {
+	data, err := r.client.client.GetDel(ctx, linkCodeKeys+code).Bytes()
+	if errors.Is(err, goredis.Nil) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	return parseUserID(data)
+}
+
+// SaveToken binds the token hash to the user, replacing the previous token of the user
+func (r *AccountRepository) SaveToken(ctx context.Context, tokenHash string, userID int64) error {
+	id := strconv.FormatInt(userID, 10)
+	previous, ok, err := r.client.get(ctx, userTokenKeys+id)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		if ok {
+			pipe.Del(ctx, tokenKeys+string(previous))
+		}
+		pipe.Set(ctx, tokenKeys+tokenHash, id, 0)
+		pipe.Set(ctx, userTokenKeys+id, tokenHash, 0)
+		return nil
+	})
+	return err
+}
+
+// FindToken returns the user of the token hash, false for unknown tokens
+func (r *AccountRepository) FindToken(ctx context.Context, tokenHash string) (int64, bool, error) {
+	data, ok, err := r.client.get(ctx, tokenKeys+tokenHash)
+	if err != nil || !ok {
+		return 0, false, err
+	}
+
+	return parseUserID(data)
+}
+
+// SaveIdentity binds the external identity of the provider to the user, an identity linked to another
+// user before is moved
+func (r *AccountRepository) SaveIdentity(ctx context.Context, provider, subject string, userID int64) error {
+	id := strconv.FormatInt(userID, 10)
+	identity := provider + ":" + subject
+	previous, ok, err := r.client.get(ctx, identityKeys+identity)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		if ok && string(previous) != id {
+			pipe.SRem(ctx, userIdentityKeys+string(previous), identity)
+		}
+		pipe.Set(ctx, identityKeys+identity, id, 0)
+		pipe.SAdd(ctx, userIdentityKeys+id, identity)
+		return nil
+	})
+	return err
+}
+
+// FindIdentity returns the user of the external identity, false for identities not linked to the bot
+func (r *AccountRepository) FindIdentity(ctx context.Context, provider, subject string) (int64, bool, error) {
+	data, ok, err := r.client.get(ctx, identityKeys+provider+":"+subject)
+	if err != nil || !ok {
+		return 0, false, err
+	}
+
+	return parseUserID(data)
+}
+
+// Identities returns the external identities linked to the user ordered by provider and subject
+func (r *AccountRepository) Identities(ctx context.Context, userID int64) ([]entities.Identity, error) {
+	members, err := r.client.client.SMembers(ctx, userIdentityKeys+strconv.FormatInt(userID, 10)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list identities: %w", err)
+	}
+
+	identities := make([]entities.Identity, 0, len(members))
+	for _, member := range members {
+		provider, subject, _ := strings.Cut(member, ":")
+		identities = append(identities, entities.Identity{UserID: userID, Provider: provider, Subject: subject})
+	}
+	sort.Slice(identities, func(i, j int) bool {
+		if identities[i].Provider != identities[j].Provider {
+			return identities[i].Provider < identities[j].Provider
+		}
+		return identities[i].Subject < identities[j].Subject
+	})
+
+	return identities, nil
+}
+
+// DeleteUser removes the link code, the token and the external identities of the user
+func (r *AccountRepository) DeleteUser(ctx context.Context, userID int64) error {
+	id := strconv.FormatInt(userID, 10)
+	code, hasCode, err := r.client.get(ctx, userLinkCodeKeys+id)
+	if err != nil {
+		return err
+	}
+	token, hasToken, err := r.client.get(ctx, userTokenKeys+id)
+	if err != nil {
+		return err
+	}
+	identities, err := r.client.client.SMembers(ctx, userIdentityKeys+id).Result()
+	if err != nil {
+		return err
+	}
+
+	keys := []string{userLinkCodeKeys + id, userTokenKeys + id, userIdentityKeys + id}
+	if hasCode {
+		keys = append(keys, linkCodeKeys+string(code))
+	}
+	if hasToken {
+		keys = append(keys, tokenKeys+string(token))
+	}
+	for _, identity := range identities {
+		keys = append(keys, identityKeys+identity)
+	}
+
+	return r.client.client.Del(ctx, keys...).Err()
+}
+
+// parseUserID reads the user ID stored in a key, ok is always true for valid IDs
+func parseUserID(data []byte) (int64, bool, error) {
+	userID, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid user ID %q: %w", data, err)
+	}
+
+	return userID, true, nil
+}
@@ -0,0 +1,61 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"sort"
+	"strconv"
+)
+
+// AchievementRepository keeps the achievements of a user in a Redis hash, one field per achievement ID,
+// so an achievement is awarded once with HSETNX
+type AchievementRepository struct {
+	client *Client
+}
+
+// NewAchievementRepository creates a new Redis achievement repository
+func NewAchievementRepository(client *Client) *AchievementRepository {
+	return &AchievementRepository{client: client}
+}
+
+// Award stores the achievement of the user, false if the user already has it
+func (r *AchievementRepository) Award(ctx context.Context, userID int64, achievement entities.Achievement) (bool, error) {
+	data, err := json.Marshal(achievement)
+	if err != nil {
+		return false, err
+	}
+
+	return r.client.client.HSetNX(ctx, achievementKeys+strconv.FormatInt(userID, 10), achievement.ID, data).Result()
+}
+
+// List returns the achievements of the user, oldest first
+func (r *AchievementRepository) List(ctx context.Context, userID int64) ([]entities.Achievement, error) {
+	values, err := r.client.client.HVals(ctx, achievementKeys+strconv.FormatInt(userID, 10)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list achievements: %w", err)
+	}
+
+	var achievements []entities.Achievement
+	for _, value := range values {
+		var achievement entities.Achievement
+		if err := json.Unmarshal([]byte(value), &achievement); err != nil {
+			return nil, fmt.Errorf("invalid achievement of user %d: %w", userID, err)
+		}
+		achievements = append(achievements, achievement)
+	}
+	sort.Slice(achievements, func(i, j int) bool {
+		if !achievements[i].AwardedAt.Equal(achievements[j].AwardedAt) {
+			return achievements[i].AwardedAt.Before(achievements[j].AwardedAt)
+		}
+		return achievements[i].ID < achievements[j].ID
+	})
+
+	return achievements, nil
+}
+
+// Delete removes the achievements of the user
+func (r *AchievementRepository) Delete(ctx context.Context, userID int64) error {
+	return r.client.client.Del(ctx, achievementKeys+strconv.FormatInt(userID, 10)).Err()
+}
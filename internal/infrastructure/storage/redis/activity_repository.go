@@ -0,0 +1,167 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/storage/document"
+	goredis "github.com/redis/go-redis/v9"
+	"strconv"
+	"time"
+)
+
+// maxActivityRetries limits the attempts of an update losing the race to concurrent updates of the user
+const maxActivityRetries = 5
+
+// ActivityRepository keeps the learning activity of users in Redis, one key per user updated in optimistic
+// transactions, so concurrent instances don't lose each other's counts
+type ActivityRepository struct {
+	client *Client
+}
+
+// NewActivityRepository creates a new Redis activity repository
+func NewActivityRepository(client *Client) *ActivityRepository {
+	return &ActivityRepository{client: client}
+}
+
+// RecordLookup counts the word for the day of at in its time zone, once per word and day, and keeps its answer
+func (r *ActivityRepository) RecordLookup(ctx context.Context, userID int64, word entities.LookedUpWord, at time.Time) error {
+	return r.update(ctx, userID, func(activity *document.Activity) bool {
+		return activity.RecordLookup(word, at)
+	})
+}
+
+// RecordQuizAnswer counts the answer for its day in its time zone and the article of the noun
+func (r *ActivityRepository) RecordQuizAnswer(ctx context.Context, userID int64, answer *entities.QuizAnswer) error {
+	return r.update(ctx, userID, func(activity *document.Activity) bool {
+		activity.RecordQuizAnswer(answer)
+		return true
+	})
+}
+
+// SearchWords returns at most limit words of the user matching the query, the best matches first
+func (r *ActivityRepository) SearchWords(ctx context.Context, userID int64, query string, limit int) ([]entities.WordMatch, error) {
+	activity, ok, err := r.load(ctx, userID)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	return activity.Search(query, limit), nil
+}
+
+// Activity returns the activity of the user, empty if none was recorded
+func (r *ActivityRepository) Activity(ctx context.Context, userID int64) (*entities.UserActivity, error) {
+	activity, ok, err := r.load(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return &entities.UserActivity{UserID: userID}, nil
+	}
+
+	return activity.Entity(userID), nil
+}
+
+// load reads the stored activity of the user, ok is false if none was recorded
+func (r *ActivityRepository) load(ctx context.Context, userID int64) (*document.Activity, bool, error) {
+	data, ok, err := r.client.get(ctx, activityKey(userID))
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	activity := document.NewActivity()
+	if err := json.Unmarshal(data, activity); err != nil {
+		return nil, false, fmt.Errorf("invalid activity of user %d: %w", userID, err)
+	}
+
+	return activity, true, nil
+}
+
+// Delete removes the activity of the user, unknown users are ignored
+func (r *ActivityRepository) Delete(ctx context.Context, userID int64) error {
+	return r.client.client.Del(ctx, activityKey(userID)).Err()
+}
+
+// DeleteInactive removes the activity of the users last active before the time and returns their number.
+// Every key is checked in an optimistic transaction, users active meanwhile keep their activity.
+func (r *ActivityRepository) DeleteInactive(ctx context.Context, before time.Time) (int, error) {
+	removed := 0
+	keys := r.client.client.Scan(ctx, 0, activityKeys+"*", 100).Iterator()
+	for keys.Next(ctx) {
+		key := keys.Val()
+		err := r.client.client.Watch(ctx, func(tx *goredis.Tx) error {
+			data, err := tx.Get(ctx, key).Bytes()
+			if errors.Is(err, goredis.Nil) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			activity := document.NewActivity()
+			if err := json.Unmarshal(data, activity); err != nil {
+				return fmt.Errorf("invalid activity %s: %w", key, err)
+			}
+			if !activity.ActiveAt.Before(before) {
+				return nil
+			}
+
+			_, err = tx.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+				return pipe.Del(ctx, key).Err()
+			})
+			if err == nil {
+				removed++
+			}
+			return err
+		}, key)
+		if err != nil && !errors.Is(err, goredis.TxFailedErr) {
+			return removed, err
+		}
+	}
+
+	return removed, keys.Err()
+}
+
+// update applies the change to the activity of the user, the key is written only when the change
+// reports a modification and the update is retried when another one wrote the key meanwhile
+func (r *ActivityRepository) update(ctx context.Context, userID int64, change func(activity *document.Activity) bool) error {
+	key := activityKey(userID)
+	apply := func(tx *goredis.Tx) error {
+		activity := document.NewActivity()
+		data, err := tx.Get(ctx, key).Bytes()
+		switch {
+		case errors.Is(err, goredis.Nil):
+		case err != nil:
+			return err
+		default:
+			if err := json.Unmarshal(data, activity); err != nil {
+				return fmt.Errorf("invalid activity of user %d: %w", userID, err)
+			}
+		}
+
+		if !change(activity) {
+			return nil
+		}
+		if data, err = json.Marshal(activity); err != nil {
+			return err
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+			return pipe.Set(ctx, key, data, 0).Err()
+		})
+		return err
+	}
+
+	for attempt := 0; attempt < maxActivityRetries; attempt++ {
+		err := r.client.client.Watch(ctx, apply, key)
+		if !errors.Is(err, goredis.TxFailedErr) {
+			return err
+		}
+	}
+
+	return fmt.Errorf("failed to update activity of user %d: %w", userID, goredis.TxFailedErr)
+}
+
+func activityKey(userID int64) string {
+	return activityKeys + strconv.FormatInt(userID, 10)
+}
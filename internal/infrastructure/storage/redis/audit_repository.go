@@ -0,0 +1,62 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// AuditRepository keeps the admin audit log in Redis lists, every entry is pushed to the list of all
+// entries and to the list of its action and never removed
+type AuditRepository struct {
+	client *Client
+}
+
+// NewAuditRepository creates a new Redis audit repository
+func NewAuditRepository(client *Client) *AuditRepository {
+	return &AuditRepository{client: client}
+}
+
+// Append stores the entry
+func (r *AuditRepository) Append(ctx context.Context, entry *entities.AuditEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.LPush(ctx, auditLog, data)
+		pipe.LPush(ctx, auditActionKeys+string(entry.Action), data)
+		return nil
+	})
+	return err
+}
+
+// List returns at most limit entries of the action, of every action when it's empty, newest first
+func (r *AuditRepository) List(ctx context.Context, action entities.AuditAction, limit int) ([]*entities.AuditEntry, error) {
+	entries := make([]*entities.AuditEntry, 0)
+	if limit <= 0 {
+		return entries, nil
+	}
+
+	key := auditLog
+	if action != "" {
+		key = auditActionKeys + string(action)
+	}
+	values, err := r.client.client.LRange(ctx, key, 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+
+	for _, value := range values {
+		var entry entities.AuditEntry
+		if err := json.Unmarshal([]byte(value), &entry); err != nil {
+			return nil, fmt.Errorf("invalid audit entry: %w", err)
+		}
+		entries = append(entries, &entry)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,61 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"time"
+)
+
+// CacheRepository keeps article responses in Redis keys expiring with their TTL
+type CacheRepository struct {
+	client *Client
+}
+
+// NewCacheRepository creates a new Redis cache
+func NewCacheRepository(client *Client) *CacheRepository {
+	return &CacheRepository{client: client}
+}
+
+// Get returns the cached response if it has not expired
+func (r *CacheRepository) Get(ctx context.Context, key string) (*entities.ArticleResponse, bool, error) {
+	data, ok, err := r.client.get(ctx, cacheKeys+key)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	var response entities.ArticleResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, false, fmt.Errorf("invalid cached response %q: %w", key, err)
+	}
+
+	return &response, true, nil
+}
+
+// Set stores the response for ttl
+func (r *CacheRepository) Set(ctx context.Context, key string, response *entities.ArticleResponse, ttl time.Duration) error {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+
+	return r.client.client.Set(ctx, cacheKeys+key, data, ttl).Err()
+}
+
+// Delete removes the entry of the key and of its variants, e.g. the levels of a word for "haus|en"
+func (r *CacheRepository) Delete(ctx context.Context, key string) (int, error) {
+	removed, err := r.client.client.Del(ctx, cacheKeys+key).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	variants, err := r.client.deleteMatching(ctx, cacheKeys+globEscaper.Replace(key)+"|*")
+
+	return int(removed) + variants, err
+}
+
+// Clear removes every entry
+func (r *CacheRepository) Clear(ctx context.Context) (int, error) {
+	return r.client.deleteMatching(ctx, cacheKeys+"*")
+}
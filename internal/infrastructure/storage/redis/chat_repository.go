@@ -0,0 +1,160 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	goredis "github.com/redis/go-redis/v9"
+	"sort"
+	"strconv"
+)
+
+// maxChatRetries limits the attempts of an opt-out losing the race to concurrent touches of the chat
+const maxChatRetries = 5
+
+// Fields of the chat hashes, the opt-out has a field of its own so touching a chat keeps it
+const (
+	chatDataField     = "data"
+	chatOptedOutField = "optedOut"
+)
+
+// ChatRepository keeps every known chat in a Redis hash indexed by a set of the chat IDs
+type ChatRepository struct {
+	client *Client
+}
+
+// NewChatRepository creates a new Redis chat repository
+func NewChatRepository(client *Client) *ChatRepository {
+	return &ChatRepository{client: client}
+}
+
+// Touch adds the chat or updates its language and the time it was seen, the opt-out is kept
+func (r *ChatRepository) Touch(ctx context.Context, chat *entities.KnownChat) error {
+	data, err := json.Marshal(chat)
+	if err != nil {
+		return err
+	}
+
+	key := chatKey(chat.ID)
+	_, err = r.client.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.HSet(ctx, key, chatDataField, data)
+		pipe.HSetNX(ctx, key, chatOptedOutField, chat.OptedOut)
+		pipe.SAdd(ctx, chatIndex, chat.ID)
+		return nil
+	})
+	return err
+}
+
+// SetOptOut sets whether the chat gets announcements, false if the chat is unknown. The update is retried
+// when the chat was touched or deleted meanwhile.
+func (r *ChatRepository) SetOptOut(ctx context.Context, chatID int64, optedOut bool) (bool, error) {
+	key := chatKey(chatID)
+	var known bool
+	apply := func(tx *goredis.Tx) error {
+		exists, err := tx.Exists(ctx, key).Result()
+		if err != nil {
+			return err
+		}
+		if known = exists == 1; !known {
+			return nil
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+			return pipe.HSet(ctx, key, chatOptedOutField, optedOut).Err()
+		})
+		return err
+	}
+
+	for attempt := 0; attempt < maxChatRetries; attempt++ {
+		err := r.client.client.Watch(ctx, apply, key)
+		if !errors.Is(err, goredis.TxFailedErr) {
+			return known, err
+		}
+	}
+
+	return false, fmt.Errorf("failed to set opt-out of chat %d: %w", chatID, goredis.TxFailedErr)
+}
+
+// Get returns the chat, false if it is unknown
+func (r *ChatRepository) Get(ctx context.Context, chatID int64) (*entities.KnownChat, bool, error) {
+	fields, err := r.client.client.HGetAll(ctx, chatKey(chatID)).Result()
+	if err != nil {
+		return nil, false, err
+	}
+	if len(fields) == 0 {
+		return nil, false, nil
+	}
+
+	chat, err := parseChat(fields)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid chat %d: %w", chatID, err)
+	}
+
+	return &chat, true, nil
+}
+
+// Delete forgets the chat, unknown chats are ignored
+func (r *ChatRepository) Delete(ctx context.Context, chatID int64) error {
+	_, err := r.client.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.Del(ctx, chatKey(chatID))
+		pipe.SRem(ctx, chatIndex, chatID)
+		return nil
+	})
+	return err
+}
+
+// List returns all known chats ordered by ID
+func (r *ChatRepository) List(ctx context.Context) ([]entities.KnownChat, error) {
+	ids, err := r.client.client.SMembers(ctx, chatIndex).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list chats: %w", err)
+	}
+
+	reads := make([]*goredis.MapStringStringCmd, len(ids))
+	_, err = r.client.client.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+		for i, id := range ids {
+			reads[i] = pipe.HGetAll(ctx, chatKeys+id)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read chats: %w", err)
+	}
+
+	chats := make([]entities.KnownChat, 0, len(ids))
+	for i, read := range reads {
+		// Chats deleted meanwhile are empty
+		if len(read.Val()) == 0 {
+			continue
+		}
+		chat, err := parseChat(read.Val())
+		if err != nil {
+			return nil, fmt.Errorf("invalid chat %s: %w", ids[i], err)
+		}
+		chats = append(chats, chat)
+	}
+	sort.Slice(chats, func(i, j int) bool { return chats[i].ID < chats[j].ID })
+
+	return chats, nil
+}
+
+// parseChat reads a chat with the opt-out of its field
+func parseChat(fields map[string]string) (entities.KnownChat, error) {
+	var chat entities.KnownChat
+	if err := json.Unmarshal([]byte(fields[chatDataField]), &chat); err != nil {
+		return chat, err
+	}
+	optedOut, err := strconv.ParseBool(fields[chatOptedOutField])
+	if err != nil {
+		return chat, err
+	}
+	chat.OptedOut = optedOut
+
+	return chat, nil
+}
+
+func chatKey(chatID int64) string {
+	return chatKeys + strconv.FormatInt(chatID, 10)
+}
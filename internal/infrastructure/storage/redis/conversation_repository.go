@@ -0,0 +1,54 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"strconv"
+	"time"
+)
+
+// ConversationRepository keeps the last word of every chat in Redis keys expiring with their TTL
+type ConversationRepository struct {
+	client *Client
+}
+
+// NewConversationRepository creates a new Redis conversation repository
+func NewConversationRepository(client *Client) *ConversationRepository {
+	return &ConversationRepository{client: client}
+}
+
+// Get returns the conversation of the chat, false if there is none or it has expired
+func (r *ConversationRepository) Get(ctx context.Context, chatID int64) (*entities.Conversation, bool, error) {
+	data, ok, err := r.client.get(ctx, conversationKey(chatID))
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	var conversation entities.Conversation
+	if err := json.Unmarshal(data, &conversation); err != nil {
+		return nil, false, fmt.Errorf("invalid conversation of chat %d: %w", chatID, err)
+	}
+
+	return &conversation, true, nil
+}
+
+// Save replaces the conversation of the chat
+func (r *ConversationRepository) Save(ctx context.Context, conversation *entities.Conversation, ttl time.Duration) error {
+	data, err := json.Marshal(conversation)
+	if err != nil {
+		return err
+	}
+
+	return r.client.client.Set(ctx, conversationKey(conversation.ChatID), data, ttl).Err()
+}
+
+// Delete forgets the conversation of the chat
+func (r *ConversationRepository) Delete(ctx context.Context, chatID int64) error {
+	return r.client.client.Del(ctx, conversationKey(chatID)).Err()
+}
+
+func conversationKey(chatID int64) string {
+	return conversationKeys + strconv.FormatInt(chatID, 10)
+}
@@ -0,0 +1,180 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	goredis "github.com/redis/go-redis/v9"
+	"strconv"
+	"time"
+)
+
+// DeadLetterRepository keeps the failed Telegram updates in Redis, one key per update indexed by a sorted
+// set of the times they failed, until they are replayed or purged by the retention sweep
+type DeadLetterRepository struct {
+	client *Client
+}
+
+// NewDeadLetterRepository creates a new Redis dead letter repository
+func NewDeadLetterRepository(client *Client) *DeadLetterRepository {
+	return &DeadLetterRepository{client: client}
+}
+
+// Save stores the dead letter, replacing the previous one with the same ID
+func (r *DeadLetterRepository) Save(ctx context.Context, letter *entities.DeadLetter) error {
+	data, err := json.Marshal(letter)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.Set(ctx, deadLetterKeys+letter.ID, data, 0)
+		pipe.ZAdd(ctx, deadLetterIndex, goredis.Z{Score: float64(letter.FailedAt.UnixNano()), Member: letter.ID})
+		return nil
+	})
+	return err
+}
+
+// Get returns the dead letter
+func (r *DeadLetterRepository) Get(ctx context.Context, id string) (*entities.DeadLetter, bool, error) {
+	data, ok, err := r.client.get(ctx, deadLetterKeys+id)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	var letter entities.DeadLetter
+	if err := json.Unmarshal(data, &letter); err != nil {
+		return nil, false, fmt.Errorf("invalid dead letter %s: %w", id, err)
+	}
+
+	return &letter, true, nil
+}
+
+// List returns at most limit dead letters, oldest first
+func (r *DeadLetterRepository) List(ctx context.Context, limit int) ([]*entities.DeadLetter, error) {
+	if limit <= 0 {
+		return make([]*entities.DeadLetter, 0), nil
+	}
+
+	ids, err := r.client.client.ZRange(ctx, deadLetterIndex, 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dead letters: %w", err)
+	}
+
+	return r.read(ctx, ids)
+}
+
+// Delete removes the dead letter, unknown IDs are ignored
+func (r *DeadLetterRepository) Delete(ctx context.Context, id string) error {
+	return r.delete(ctx, []string{id})
+}
+
+// ListUser returns the dead letters of the user, oldest first. The letters are filtered after reading
+// all of them, as there are few between the retention sweeps.
+func (r *DeadLetterRepository) ListUser(ctx context.Context, userID int64) ([]*entities.DeadLetter, error) {
+	ids, err := r.client.client.ZRange(ctx, deadLetterIndex, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dead letters: %w", err)
+	}
+	all, err := r.read(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	letters := make([]*entities.DeadLetter, 0)
+	for _, letter := range all {
+		if letter.UserID == userID {
+			letters = append(letters, letter)
+		}
+	}
+
+	return letters, nil
+}
+
+// DeleteUser removes the dead letters of the user
+func (r *DeadLetterRepository) DeleteUser(ctx context.Context, userID int64) error {
+	letters, err := r.ListUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]string, len(letters))
+	for i, letter := range letters {
+		ids[i] = letter.ID
+	}
+	if err := r.delete(ctx, ids); err != nil {
+		return fmt.Errorf("failed to delete dead letters of user %d: %w", userID, err)
+	}
+
+	return nil
+}
+
+// DeleteBefore removes the dead letters that failed before the time and returns their number
+func (r *DeadLetterRepository) DeleteBefore(ctx context.Context, before time.Time) (int, error) {
+	ids, err := r.client.client.ZRangeByScore(ctx, deadLetterIndex, &goredis.ZRangeBy{
+		Min: "-inf",
+		Max: "(" + strconv.FormatInt(before.UnixNano(), 10),
+	}).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list old dead letters: %w", err)
+	}
+	if err := r.delete(ctx, ids); err != nil {
+		return 0, fmt.Errorf("failed to delete dead letters: %w", err)
+	}
+
+	return len(ids), nil
+}
+
+// read returns the dead letters with the IDs in their order
+func (r *DeadLetterRepository) read(ctx context.Context, ids []string) ([]*entities.DeadLetter, error) {
+	letters := make([]*entities.DeadLetter, 0, len(ids))
+	if len(ids) == 0 {
+		return letters, nil
+	}
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = deadLetterKeys + id
+	}
+	values, err := r.client.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dead letters: %w", err)
+	}
+
+	for i, value := range values {
+		// Letters replayed meanwhile are missing
+		data, ok := value.(string)
+		if !ok {
+			continue
+		}
+		var letter entities.DeadLetter
+		if err := json.Unmarshal([]byte(data), &letter); err != nil {
+			return nil, fmt.Errorf("invalid dead letter %s: %w", ids[i], err)
+		}
+		letters = append(letters, &letter)
+	}
+
+	return letters, nil
+}
+
+// delete removes the dead letters with the IDs from the index and their keys
+func (r *DeadLetterRepository) delete(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	keys := make([]string, len(ids))
+	members := make([]interface{}, len(ids))
+	for i, id := range ids {
+		keys[i] = deadLetterKeys + id
+		members[i] = id
+	}
+	_, err := r.client.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.ZRem(ctx, deadLetterIndex, members...)
+		pipe.Del(ctx, keys...)
+		return nil
+	})
+
+	return err
+}
@@ -0,0 +1,54 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// DictionaryRepository keeps the curated dictionary entries in Redis, one key per lower-cased noun
+type DictionaryRepository struct {
+	client *Client
+}
+
+// NewDictionaryRepository creates a new Redis dictionary repository
+func NewDictionaryRepository(client *Client) *DictionaryRepository {
+	return &DictionaryRepository{client: client}
+}
+
+// Get returns the entry of the noun, nil for nouns without one
+func (r *DictionaryRepository) Get(ctx context.Context, word string) (*entities.CuratedEntry, error) {
+	data, ok, err := r.client.get(ctx, dictionaryKeys+entities.CuratedKey(word))
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	var entry entities.CuratedEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("invalid dictionary entry %q: %w", word, err)
+	}
+
+	return &entry, nil
+}
+
+// Save replaces the entries of their nouns in a single transaction
+func (r *DictionaryRepository) Save(ctx context.Context, entries []entities.CuratedEntry) error {
+	values := make([][]byte, len(entries))
+	for i, entry := range entries {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		values[i] = data
+	}
+
+	_, err := r.client.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		for i, entry := range entries {
+			pipe.Set(ctx, dictionaryKeys+entry.Key(), values[i], 0)
+		}
+		return nil
+	})
+	return err
+}
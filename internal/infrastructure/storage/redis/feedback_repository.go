@@ -0,0 +1,189 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/storage/document"
+	goredis "github.com/redis/go-redis/v9"
+	"strconv"
+	"time"
+)
+
+// FeedbackRepository keeps user ratings in Redis, one key per rating indexed by a sorted set of their
+// creation times. The lists are filtered and ordered after reading the ratings, which are bounded.
+type FeedbackRepository struct {
+	client     *Client
+	maxEntries int
+}
+
+// NewFeedbackRepository creates a new Redis feedback repository keeping at most maxEntries ratings
+func NewFeedbackRepository(client *Client, maxEntries int) *FeedbackRepository {
+	return &FeedbackRepository{client: client, maxEntries: maxEntries}
+}
+
+// Save stores the feedback, dropping the oldest ones when the repository is full
+func (r *FeedbackRepository) Save(ctx context.Context, feedback *entities.Feedback) error {
+	data, err := json.Marshal(feedback)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.Set(ctx, feedbackKeys+feedback.ID, data, 0)
+		pipe.ZAdd(ctx, feedbackIndex, goredis.Z{Score: float64(feedback.CreatedAt.UnixNano()), Member: feedback.ID})
+		return nil
+	})
+	if err != nil || r.maxEntries <= 0 {
+		return err
+	}
+
+	return r.trim(ctx)
+}
+
+// List returns a page of the matching feedback, newest first unless the page orders it otherwise
+func (r *FeedbackRepository) List(ctx context.Context, filter entities.FeedbackFilter) (entities.Page[*entities.Feedback], error) {
+	all, err := r.all(ctx)
+	if err != nil {
+		return entities.Page[*entities.Feedback]{}, err
+	}
+
+	result := make([]*entities.Feedback, 0)
+	for _, feedback := range all {
+		if document.MatchesFeedback(filter, feedback) {
+			result = append(result, feedback)
+		}
+	}
+
+	page, keyOf := document.FeedbackPage(filter)
+
+	return document.Paginate(result, page, keyOf)
+}
+
+// ListUser returns the ratings of the user, oldest first
+func (r *FeedbackRepository) ListUser(ctx context.Context, userID int64) ([]*entities.Feedback, error) {
+	all, err := r.all(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*entities.Feedback, 0)
+	for _, feedback := range all {
+		if feedback.UserID == userID {
+			result = append(result, feedback)
+		}
+	}
+
+	return result, nil
+}
+
+// DeleteUser removes the ratings of the user
+func (r *FeedbackRepository) DeleteUser(ctx context.Context, userID int64) error {
+	feedback, err := r.ListUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]string, len(feedback))
+	for i, rating := range feedback {
+		ids[i] = rating.ID
+	}
+	if err := r.delete(ctx, ids); err != nil {
+		return fmt.Errorf("failed to delete feedback of user %d: %w", userID, err)
+	}
+
+	return nil
+}
+
+// DeleteBefore removes the ratings created before the time and returns their number
+func (r *FeedbackRepository) DeleteBefore(ctx context.Context, before time.Time) (int, error) {
+	ids, err := r.client.client.ZRangeByScore(ctx, feedbackIndex, &goredis.ZRangeBy{
+		Min: "-inf",
+		Max: "(" + strconv.FormatInt(before.UnixNano(), 10),
+	}).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list old feedback: %w", err)
+	}
+	if err := r.delete(ctx, ids); err != nil {
+		return 0, fmt.Errorf("failed to delete feedback: %w", err)
+	}
+
+	return len(ids), nil
+}
+
+// all reads every stored rating, oldest first
+func (r *FeedbackRepository) all(ctx context.Context) ([]*entities.Feedback, error) {
+	ids, err := r.client.client.ZRange(ctx, feedbackIndex, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list feedback: %w", err)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = feedbackKeys + id
+	}
+	values, err := r.client.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read feedback: %w", err)
+	}
+
+	result := make([]*entities.Feedback, 0, len(values))
+	for i, value := range values {
+		// Ratings trimmed meanwhile are missing
+		data, ok := value.(string)
+		if !ok {
+			continue
+		}
+		var feedback entities.Feedback
+		if err := json.Unmarshal([]byte(data), &feedback); err != nil {
+			return nil, fmt.Errorf("invalid feedback %s: %w", ids[i], err)
+		}
+		result = append(result, &feedback)
+	}
+
+	return result, nil
+}
+
+// delete removes the ratings with the IDs from the index and their keys
+func (r *FeedbackRepository) delete(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	keys := make([]string, len(ids))
+	members := make([]interface{}, len(ids))
+	for i, id := range ids {
+		keys[i] = feedbackKeys + id
+		members[i] = id
+	}
+	_, err := r.client.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.ZRem(ctx, feedbackIndex, members...)
+		pipe.Del(ctx, keys...)
+		return nil
+	})
+
+	return err
+}
+
+// trim drops the oldest ratings beyond the maximum
+func (r *FeedbackRepository) trim(ctx context.Context) error {
+	count, err := r.client.client.ZCard(ctx, feedbackIndex).Result()
+	if err != nil || count <= int64(r.maxEntries) {
+		return err
+	}
+
+	oldest, err := r.client.client.ZPopMin(ctx, feedbackIndex, count-int64(r.maxEntries)).Result()
+	if err != nil {
+		return err
+	}
+	keys := make([]string, len(oldest))
+	for i, z := range oldest {
+		keys[i] = feedbackKeys + fmt.Sprint(z.Member)
+	}
+
+	return r.client.client.Del(ctx, keys...).Err()
+}
@@ -0,0 +1,68 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"time"
+)
+
+// IdempotencyRepository keeps the responses of requests with idempotency keys in Redis keys expiring with
+// their TTL, a key is reserved with SET NX so concurrent requests of all instances get one reservation
+type IdempotencyRepository struct {
+	client *Client
+}
+
+// NewIdempotencyRepository creates a new Redis idempotency repository
+func NewIdempotencyRepository(client *Client) *IdempotencyRepository {
+	return &IdempotencyRepository{client: client}
+}
+
+// Begin reserves the key for the request with the fingerprint and returns nil, or the record of
+// an earlier request with the key
+func (r *IdempotencyRepository) Begin(ctx context.Context, key, fingerprint string, ttl time.Duration) (*entities.IdempotentResponse, error) {
+	data, err := json.Marshal(&entities.IdempotentResponse{Fingerprint: fingerprint})
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		reserved, err := r.client.client.SetNX(ctx, idempotencyKeys+key, data, ttl).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to reserve idempotency key: %w", err)
+		}
+		if reserved {
+			return nil, nil
+		}
+
+		stored, ok, err := r.client.get(ctx, idempotencyKeys+key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read idempotency key: %w", err)
+		}
+		// A key released or expired between both commands is reserved by the next attempt
+		if !ok {
+			continue
+		}
+		var response entities.IdempotentResponse
+		if err := json.Unmarshal(stored, &response); err != nil {
+			return nil, fmt.Errorf("invalid response of idempotency key: %w", err)
+		}
+		return &response, nil
+	}
+}
+
+// Complete stores the response of the request holding the key
+func (r *IdempotencyRepository) Complete(ctx context.Context, key string, response *entities.IdempotentResponse, ttl time.Duration) error {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+
+	return r.client.client.Set(ctx, idempotencyKeys+key, data, ttl).Err()
+}
+
+// Release frees the key of a failed request, so a retry is processed again
+func (r *IdempotencyRepository) Release(ctx context.Context, key string) error {
+	return r.client.client.Del(ctx, idempotencyKeys+key).Err()
+}
@@ -0,0 +1,72 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	goredis "github.com/redis/go-redis/v9"
+	"strconv"
+	"time"
+)
+
+// JobRepository keeps the states of background jobs in Redis keys expiring with their TTL, the jobs working
+// for a user are listed in a set of the user expiring with the latest of them
+type JobRepository struct {
+	client *Client
+}
+
+// NewJobRepository creates a new Redis job repository
+func NewJobRepository(client *Client) *JobRepository {
+	return &JobRepository{client: client}
+}
+
+// Save stores the status for ttl, replacing the previous one of the job
+func (r *JobRepository) Save(ctx context.Context, status *entities.JobStatus, ttl time.Duration) error {
+	data, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.Set(ctx, jobKeys+status.ID, data, ttl)
+		if status.UserID != 0 {
+			key := userJobKeys + strconv.FormatInt(status.UserID, 10)
+			pipe.SAdd(ctx, key, status.ID)
+			pipe.Expire(ctx, key, ttl)
+		}
+		return nil
+	})
+	return err
+}
+
+// Get returns the status of the job if it has not expired
+func (r *JobRepository) Get(ctx context.Context, id string) (*entities.JobStatus, bool, error) {
+	data, ok, err := r.client.get(ctx, jobKeys+id)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	var status entities.JobStatus
+	if err := json.Unmarshal(data, &status); err != nil {
+		return nil, false, fmt.Errorf("invalid status of job %s: %w", id, err)
+	}
+
+	return &status, true, nil
+}
+
+// DeleteUser removes the states of the jobs working for the user
+func (r *JobRepository) DeleteUser(ctx context.Context, userID int64) error {
+	key := userJobKeys + strconv.FormatInt(userID, 10)
+	ids, err := r.client.client.SMembers(ctx, key).Result()
+	if err != nil {
+		return fmt.Errorf("failed to list jobs of user %d: %w", userID, err)
+	}
+
+	keys := []string{key}
+	for _, id := range ids {
+		keys = append(keys, jobKeys+id)
+	}
+
+	return r.client.client.Del(ctx, keys...).Err()
+}
@@ -0,0 +1,169 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	goredis "github.com/redis/go-redis/v9"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// leaderboardTTL keeps the scores of a week after its last answer, long enough to summarize the previous week
+const leaderboardTTL = 14 * 24 * time.Hour
+
+// LeaderboardRepository keeps the leaderboard members in Redis keys and the scores of a week in a chat in
+// a hash, with the answers and the correct answers of every member counted in fields of their own. The
+// scores expire two weeks after the last answer of the week.
+type LeaderboardRepository struct {
+	client *Client
+}
+
+// NewLeaderboardRepository creates a new Redis leaderboard repository
+func NewLeaderboardRepository(client *Client) *LeaderboardRepository {
+	return &LeaderboardRepository{client: client}
+}
+
+// SaveMember adds the member or updates the display name of the member
+func (r *LeaderboardRepository) SaveMember(ctx context.Context, member *entities.LeaderboardMember) error {
+	data, err := json.Marshal(member)
+	if err != nil {
+		return err
+	}
+
+	return r.client.client.Set(ctx, leaderboardMemberKeys+strconv.FormatInt(member.UserID, 10), data, 0).Err()
+}
+
+// DeleteMember removes the member together with all scores of the member
+func (r *LeaderboardRepository) DeleteMember(ctx context.Context, userID int64) error {
+	id := strconv.FormatInt(userID, 10)
+	if err := r.client.client.Del(ctx, leaderboardMemberKeys+id).Err(); err != nil {
+		return err
+	}
+
+	keys := r.client.client.Scan(ctx, 0, leaderboardScoreKeys+"*", 100).Iterator()
+	for keys.Next(ctx) {
+		if err := r.client.client.HDel(ctx, keys.Val(), "answers:"+id, "correct:"+id).Err(); err != nil {
+			return err
+		}
+	}
+
+	return keys.Err()
+}
+
+// Member returns the member of the user, nil for users who didn't opt in
+func (r *LeaderboardRepository) Member(ctx context.Context, userID int64) (*entities.LeaderboardMember, error) {
+	data, ok, err := r.client.get(ctx, leaderboardMemberKeys+strconv.FormatInt(userID, 10))
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	var member entities.LeaderboardMember
+	if err := json.Unmarshal(data, &member); err != nil {
+		return nil, fmt.Errorf("invalid leaderboard member %d: %w", userID, err)
+	}
+
+	return &member, nil
+}
+
+// RecordScore counts the answer of the member in the week of the chat
+func (r *LeaderboardRepository) RecordScore(ctx context.Context, week string, chatID int64, userID int64, correct bool) error {
+	key := scoreKey(week, chatID)
+	id := strconv.FormatInt(userID, 10)
+	_, err := r.client.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.HIncrBy(ctx, key, "answers:"+id, 1)
+		if correct {
+			pipe.HIncrBy(ctx, key, "correct:"+id, 1)
+		}
+		pipe.Expire(ctx, key, leaderboardTTL)
+		if chatID != entities.LeaderboardGlobal {
+			pipe.SAdd(ctx, leaderboardChatKeys+week, chatID)
+			pipe.Expire(ctx, leaderboardChatKeys+week, leaderboardTTL)
+		}
+		return nil
+	})
+	return err
+}
+
+// Scores returns the scores of the week in the chat in no particular order
+func (r *LeaderboardRepository) Scores(ctx context.Context, week string, chatID int64) ([]entities.LeaderboardScore, error) {
+	fields, err := r.client.client.HGetAll(ctx, scoreKey(week, chatID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list leaderboard scores: %w", err)
+	}
+
+	scores := make(map[int64]*entities.LeaderboardScore)
+	for field, value := range fields {
+		counter, id, _ := strings.Cut(field, ":")
+		userID, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid leaderboard score %q: %w", field, err)
+		}
+		count, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid leaderboard score %q: %w", field, err)
+		}
+
+		score, ok := scores[userID]
+		if !ok {
+			score = &entities.LeaderboardScore{UserID: userID}
+			scores[userID] = score
+		}
+		if counter == "correct" {
+			score.Correct = count
+		} else {
+			score.Answers = count
+		}
+	}
+
+	result := make([]entities.LeaderboardScore, 0, len(scores))
+	for _, score := range scores {
+		result = append(result, *score)
+	}
+
+	return result, nil
+}
+
+// DeleteChat removes the scores of the group chat in all weeks
+func (r *LeaderboardRepository) DeleteChat(ctx context.Context, chatID int64) error {
+	if _, err := r.client.deleteMatching(ctx, leaderboardScoreKeys+"*:"+strconv.FormatInt(chatID, 10)); err != nil {
+		return err
+	}
+
+	keys := r.client.client.Scan(ctx, 0, leaderboardChatKeys+"*", 100).Iterator()
+	for keys.Next(ctx) {
+		if err := r.client.client.SRem(ctx, keys.Val(), chatID).Err(); err != nil {
+			return err
+		}
+	}
+
+	return keys.Err()
+}
+
+// Chats returns the group chats with scores in the week, without LeaderboardGlobal
+func (r *LeaderboardRepository) Chats(ctx context.Context, week string) ([]int64, error) {
+	members, err := r.client.client.SMembers(ctx, leaderboardChatKeys+week).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list leaderboard chats: %w", err)
+	}
+
+	var chats []int64
+	for _, member := range members {
+		chatID, err := strconv.ParseInt(member, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid leaderboard chat %q: %w", member, err)
+		}
+		chats = append(chats, chatID)
+	}
+	sort.Slice(chats, func(i, j int) bool { return chats[i] < chats[j] })
+
+	return chats, nil
+}
+
+// scoreKey returns the key of the scores of the week in the chat
+func scoreKey(week string, chatID int64) string {
+	return leaderboardScoreKeys + week + ":" + strconv.FormatInt(chatID, 10)
+}
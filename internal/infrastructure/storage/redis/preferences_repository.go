@@ -0,0 +1,56 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"strconv"
+)
+
+// PreferencesRepository keeps user settings in Redis, one key per user
+type PreferencesRepository struct {
+	client *Client
+}
+
+// NewPreferencesRepository creates a new Redis preferences repository
+func NewPreferencesRepository(client *Client) *PreferencesRepository {
+	return &PreferencesRepository{client: client}
+}
+
+// Get returns the preferences of the user, default preferences if none were saved
+func (r *PreferencesRepository) Get(ctx context.Context, userID int64) (*entities.UserPreferences, error) {
+	data, ok, err := r.client.get(ctx, preferencesKey(userID))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return &entities.UserPreferences{UserID: userID}, nil
+	}
+
+	var preferences entities.UserPreferences
+	if err := json.Unmarshal(data, &preferences); err != nil {
+		return nil, fmt.Errorf("invalid preferences of user %d: %w", userID, err)
+	}
+
+	return &preferences, nil
+}
+
+// Save replaces the preferences of the user
+func (r *PreferencesRepository) Save(ctx context.Context, preferences *entities.UserPreferences) error {
+	data, err := json.Marshal(preferences)
+	if err != nil {
+		return err
+	}
+
+	return r.client.client.Set(ctx, preferencesKey(preferences.UserID), data, 0).Err()
+}
+
+// Delete removes the preferences of the user
+func (r *PreferencesRepository) Delete(ctx context.Context, userID int64) error {
+	return r.client.client.Del(ctx, preferencesKey(userID)).Err()
+}
+
+func preferencesKey(userID int64) string {
+	return preferencesKeys + strconv.FormatInt(userID, 10)
+}
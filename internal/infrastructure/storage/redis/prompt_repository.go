@@ -0,0 +1,48 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+)
+
+// PromptRepository keeps the prompt overrides of the tenants in Redis, one key per tenant
+type PromptRepository struct {
+	client *Client
+}
+
+// NewPromptRepository creates a new Redis prompt repository
+func NewPromptRepository(client *Client) *PromptRepository {
+	return &PromptRepository{client: client}
+}
+
+// Get returns the prompt overrides of the tenant, nil if none were saved
+func (r *PromptRepository) Get(ctx context.Context, tenantID string) (*entities.PromptOverrides, error) {
+	data, ok, err := r.client.get(ctx, promptKeys+tenantID)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	var overrides entities.PromptOverrides
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return nil, fmt.Errorf("invalid prompt overrides of tenant %s: %w", tenantID, err)
+	}
+
+	return &overrides, nil
+}
+
+// Save replaces the prompt overrides of the tenant
+func (r *PromptRepository) Save(ctx context.Context, overrides *entities.PromptOverrides) error {
+	data, err := json.Marshal(overrides)
+	if err != nil {
+		return err
+	}
+
+	return r.client.client.Set(ctx, promptKeys+overrides.TenantID, data, 0).Err()
+}
+
+// Delete removes the prompt overrides of the tenant
+func (r *PromptRepository) Delete(ctx context.Context, tenantID string) error {
+	return r.client.client.Del(ctx, promptKeys+tenantID).Err()
+}
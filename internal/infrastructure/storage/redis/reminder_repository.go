@@ -0,0 +1,104 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	goredis "github.com/redis/go-redis/v9"
+	"strconv"
+	"time"
+)
+
+// ReminderRepository keeps the practice reminders in Redis, one key per user, indexed by the time they
+// are due in the reminders sorted set
+type ReminderRepository struct {
+	client *Client
+}
+
+// NewReminderRepository creates a new Redis reminder repository
+func NewReminderRepository(client *Client) *ReminderRepository {
+	return &ReminderRepository{client: client}
+}
+
+// Get returns the reminder of the user, nil if none is set
+func (r *ReminderRepository) Get(ctx context.Context, userID int64) (*entities.Reminder, error) {
+	data, ok, err := r.client.get(ctx, reminderKeys+strconv.FormatInt(userID, 10))
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	var reminder entities.Reminder
+	if err := json.Unmarshal(data, &reminder); err != nil {
+		return nil, fmt.Errorf("invalid reminder of user %d: %w", userID, err)
+	}
+
+	return &reminder, nil
+}
+
+// Save replaces the reminder of the user
+func (r *ReminderRepository) Save(ctx context.Context, reminder *entities.Reminder) error {
+	data, err := json.Marshal(reminder)
+	if err != nil {
+		return err
+	}
+
+	id := strconv.FormatInt(reminder.UserID, 10)
+	_, err = r.client.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.Set(ctx, reminderKeys+id, data, 0)
+		pipe.ZAdd(ctx, reminderIndex, goredis.Z{Score: float64(reminder.NextAt.UnixNano()), Member: id})
+		return nil
+	})
+	return err
+}
+
+// Delete removes the reminder of the user
+func (r *ReminderRepository) Delete(ctx context.Context, userID int64) error {
+	id := strconv.FormatInt(userID, 10)
+	_, err := r.client.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.Del(ctx, reminderKeys+id)
+		pipe.ZRem(ctx, reminderIndex, id)
+		return nil
+	})
+	return err
+}
+
+// Due returns at most limit reminders due at the time, the longest due first
+func (r *ReminderRepository) Due(ctx context.Context, now time.Time, limit int) ([]entities.Reminder, error) {
+	ids, err := r.client.client.ZRangeByScore(ctx, reminderIndex, &goredis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(now.UnixNano(), 10),
+		Count: int64(limit),
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due reminders: %w", err)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = reminderKeys + id
+	}
+	values, err := r.client.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read reminders: %w", err)
+	}
+
+	result := make([]entities.Reminder, 0, len(values))
+	for i, value := range values {
+		// Reminders stopped meanwhile are missing
+		data, ok := value.(string)
+		if !ok {
+			continue
+		}
+		var reminder entities.Reminder
+		if err := json.Unmarshal([]byte(data), &reminder); err != nil {
+			return nil, fmt.Errorf("invalid reminder %s: %w", ids[i], err)
+		}
+		result = append(result, reminder)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,42 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	goredis "github.com/redis/go-redis/v9"
+	"time"
+)
+
+// ReplyRepository keeps the answers to the messages in Redis keys expiring ttl after the answer
+type ReplyRepository struct {
+	client *Client
+	ttl    time.Duration
+}
+
+// NewReplyRepository creates a new Redis reply repository keeping the answers for ttl
+func NewReplyRepository(client *Client, ttl time.Duration) *ReplyRepository {
+	return &ReplyRepository{client: client, ttl: ttl}
+}
+
+// Get returns the message ID of the answer to the message of the chat
+func (r *ReplyRepository) Get(ctx context.Context, chatID int64, messageID int) (int, bool, error) {
+	replyID, err := r.client.client.Get(ctx, replyKey(chatID, messageID)).Int()
+	if errors.Is(err, goredis.Nil) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	return replyID, true, nil
+}
+
+// Save stores the answer to the message
+func (r *ReplyRepository) Save(ctx context.Context, chatID int64, messageID, replyID int) error {
+	return r.client.client.Set(ctx, replyKey(chatID, messageID), replyID, r.ttl).Err()
+}
+
+func replyKey(chatID int64, messageID int) string {
+	return fmt.Sprintf("%s%d:%d", replyKeys, chatID, messageID)
+}
@@ -0,0 +1,247 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/logging"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/storage/document"
+	goredis "github.com/redis/go-redis/v9"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// StatsRepository keeps the usage metrics in Redis: the named counters and the AI spend of every month in
+// hashes, the lookups of every word and the time every Telegram user was last seen in sorted sets.
+// Recording never fails the request, failures are logged.
+type StatsRepository struct {
+	client   *Client
+	callCost float64
+	logger   logging.Logger
+}
+
+// NewStatsRepository creates a new Redis stats repository adding callCost USD to the monthly spend of every AI call
+func NewStatsRepository(client *Client, callCost float64, logger logging.Logger) *StatsRepository {
+	return &StatsRepository{client: client, callCost: callCost, logger: logger}
+}
+
+// RecordLookup increments the lookup counters of the word and of the adapter
+func (r *StatsRepository) RecordLookup(ctx context.Context, word, _ string, adapter entities.Adapter) {
+	key := document.WordKey(word)
+	if key == "" {
+		return
+	}
+
+	_, err := r.client.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.ZIncrBy(ctx, statsWords, 1, key)
+		if adapter != "" {
+			increment(ctx, pipe, document.AdapterCounters+string(adapter))
+		}
+		return nil
+	})
+	r.logFailure(ctx, err, "Failed to record lookup")
+}
+
+// RecordCacheLookup increments the cache hit or miss counter
+func (r *StatsRepository) RecordCacheLookup(ctx context.Context, hit bool) {
+	counter := document.CacheMissesCounter
+	if hit {
+		counter = document.CacheHitsCounter
+	}
+
+	_, err := r.client.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		increment(ctx, pipe, counter)
+		return nil
+	})
+	r.logFailure(ctx, err, "Failed to record cache lookup")
+}
+
+// RecordAICall increments the AI call counters, the daily quota usage and the monthly spend
+func (r *StatsRepository) RecordAICall(ctx context.Context, failed bool) {
+	now := time.Now().UTC()
+	_, err := r.client.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		increment(ctx, pipe, document.AICallCounters(now, failed)...)
+		// Failed calls are billed as well when the model has answered
+		pipe.HIncrByFloat(ctx, statsSpend, now.Format(document.SpendMonthLayout), r.callCost)
+		return nil
+	})
+	r.logFailure(ctx, err, "Failed to record AI call")
+}
+
+// MonthlySpend returns the estimated AI spend of the current month in USD
+func (r *StatsRepository) MonthlySpend(ctx context.Context) (float64, error) {
+	spent, err := r.client.client.HGet(ctx, statsSpend, time.Now().UTC().Format(document.SpendMonthLayout)).Float64()
+	if errors.Is(err, goredis.Nil) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read monthly spend: %w", err)
+	}
+
+	return spent, nil
+}
+
+// RecordTelegramUser marks the Telegram user as active now
+func (r *StatsRepository) RecordTelegramUser(ctx context.Context, userID int64) {
+	err := r.client.client.ZAdd(ctx, statsUsers, goredis.Z{
+		Score:  float64(time.Now().UnixMilli()),
+		Member: strconv.FormatInt(userID, 10),
+	}).Err()
+	r.logFailure(ctx, err, "Failed to record Telegram user")
+}
+
+// DeleteUser forgets when the Telegram user was last seen
+func (r *StatsRepository) DeleteUser(ctx context.Context, userID int64) error {
+	return r.client.client.ZRem(ctx, statsUsers, strconv.FormatInt(userID, 10)).Err()
+}
+
+// RecordChatMemberEvent increments the churn counter of the event, unknown events are ignored
+func (r *StatsRepository) RecordChatMemberEvent(ctx context.Context, event entities.ChatMemberEvent) {
+	counter, ok := document.ChurnCounters[event]
+	if !ok {
+		return
+	}
+
+	_, err := r.client.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		increment(ctx, pipe, counter)
+		return nil
+	})
+	r.logFailure(ctx, err, "Failed to record chat member event")
+}
+
+// Snapshot returns the current metrics with the given number of top words
+func (r *StatsRepository) Snapshot(ctx context.Context, topWords int) (*entities.DashboardStats, error) {
+	fields, err := r.client.client.HGetAll(ctx, statsCounters).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stats counters: %w", err)
+	}
+	counters := make(map[string]int64, len(fields))
+	for name, value := range fields {
+		if counters[name], err = strconv.ParseInt(value, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid stats counter %s: %w", name, err)
+		}
+	}
+	now := time.Now()
+	stats := document.Dashboard(counters, now)
+
+	if stats.TopWords, err = r.topWords(ctx, topWords); err != nil {
+		return nil, err
+	}
+	if stats.Spend.Spent, err = r.MonthlySpend(ctx); err != nil {
+		return nil, err
+	}
+	var day, week *goredis.IntCmd
+	_, err = r.client.client.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+		day = pipe.ZCount(ctx, statsUsers, strconv.FormatInt(now.Add(-24*time.Hour).UnixMilli(), 10), "+inf")
+		week = pipe.ZCount(ctx, statsUsers, strconv.FormatInt(now.Add(-7*24*time.Hour).UnixMilli(), 10), "+inf")
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count active Telegram users: %w", err)
+	}
+	stats.Telegram.ActiveUsers24h, stats.Telegram.ActiveUsers7d = int(day.Val()), int(week.Val())
+
+	return stats, nil
+}
+
+// ListWords returns a page of the looked-up words, most looked-up first unless the page orders them otherwise.
+// The words of the prefix are read and sorted in full.
+func (r *StatsRepository) ListWords(ctx context.Context, filter entities.WordStatFilter) (entities.Page[entities.WordStat], error) {
+	words, err := r.words(ctx, document.WordKey(filter.Prefix))
+	if err != nil {
+		return entities.Page[entities.WordStat]{}, err
+	}
+	page, keyOf := document.WordPage(filter)
+
+	return document.Paginate(words, page, keyOf)
+}
+
+// topWords returns the most looked-up words, all of them when limit isn't positive. The words tied with the
+// last one are read as well, so ties are broken by ascending word as in the lists of every backend.
+func (r *StatsRepository) topWords(ctx context.Context, limit int) ([]entities.WordStat, error) {
+	lowest := "-inf"
+	if limit > 0 {
+		last, err := r.client.client.ZRevRangeWithScores(ctx, statsWords, int64(limit-1), int64(limit-1)).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list words: %w", err)
+		}
+		if len(last) > 0 {
+			lowest = strconv.FormatFloat(last[0].Score, 'f', -1, 64)
+		}
+	}
+	members, err := r.client.client.ZRangeByScoreWithScores(ctx, statsWords, &goredis.ZRangeBy{Min: lowest, Max: "+inf"}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list words: %w", err)
+	}
+
+	words := make([]entities.WordStat, 0, len(members))
+	for _, member := range members {
+		words = append(words, entities.WordStat{Word: member.Member.(string), Lookups: int64(member.Score)})
+	}
+	sort.Slice(words, func(i, j int) bool {
+		if words[i].Lookups != words[j].Lookups {
+			return words[i].Lookups > words[j].Lookups
+		}
+		return words[i].Word < words[j].Word
+	})
+	if limit > 0 && len(words) > limit {
+		words = words[:limit]
+	}
+
+	return words, nil
+}
+
+// words reads the words starting with the prefix, all of them when it's empty
+func (r *StatsRepository) words(ctx context.Context, prefix string) ([]entities.WordStat, error) {
+	words := make([]entities.WordStat, 0)
+	if prefix == "" {
+		members, err := r.client.client.ZRangeWithScores(ctx, statsWords, 0, -1).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list words: %w", err)
+		}
+		for _, member := range members {
+			words = append(words, entities.WordStat{Word: member.Member.(string), Lookups: int64(member.Score)})
+		}
+		return words, nil
+	}
+
+	// ZSCAN returns every member followed by its score, a member may be returned more than once
+	lookups := make(map[string]int64)
+	members := r.client.client.ZScan(ctx, statsWords, 0, globEscaper.Replace(prefix)+"*", 100).Iterator()
+	for members.Next(ctx) {
+		word := members.Val()
+		if !members.Next(ctx) {
+			break
+		}
+		score, err := strconv.ParseFloat(members.Val(), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid lookups of word %q: %w", word, err)
+		}
+		lookups[word] = int64(score)
+	}
+	if err := members.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list words: %w", err)
+	}
+	for word, count := range lookups {
+		words = append(words, entities.WordStat{Word: word, Lookups: count})
+	}
+
+	return words, nil
+}
+
+// logFailure logs a failure to record a metric, the request goes on without it
+func (r *StatsRepository) logFailure(ctx context.Context, err error, message string) {
+	if err != nil {
+		r.logger.With(ctx).Err(err).Sampled().Warning(message)
+	}
+}
+
+// increment adds one to every named counter, the counting starts with the first increment
+func increment(ctx context.Context, pipe goredis.Pipeliner, names ...string) {
+	pipe.HSetNX(ctx, statsCounters, document.SinceCounter, time.Now().UnixNano())
+	for _, name := range names {
+		pipe.HIncrBy(ctx, statsCounters, name, 1)
+	}
+}
@@ -0,0 +1,125 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"time"
+)
+
+// AccountRepository keeps the link codes, the API token hashes and the linked identities in the link_codes,
+// tokens and identities tables, every user holds at most one code and one token
+type AccountRepository struct {
+	client *Client
+}
+
+// NewAccountRepository creates a new SQLite account repository
+func NewAccountRepository(client *Client) *AccountRepository {
+	return &AccountRepository{client: client}
+}
+
+// SaveLinkCode stores the one-time code of the user for ttl, the previous code of the user is dropped
+func (r *AccountRepository) SaveLinkCode(ctx context.Context, code string, userID int64, ttl time.Duration) error {
+	// REPLACE deletes the rows conflicting on the code or the user
+	_, err := r.client.exec(ctx, "REPLACE INTO link_codes (code, user_id, expires_at) VALUES (?, ?, ?)",
+		code, userID, time.Now().Add(ttl).UnixNano())
+	return err
+}
+
+// TakeLinkCode returns the user of the code and deletes it, false for unknown and expired codes
+func (r *AccountRepository) TakeLinkCode(ctx context.Context, code string) (int64, bool, error) {
+	var (
+		userID    int64
+		expiresAt int64
+	)
+	err := r.client.db.QueryRowContext(ctx, "DELETE FROM link_codes WHERE code = ? RETURNING user_id, expires_at", code).
+		Scan(&userID, &expiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	return userID, time.Now().UnixNano() < expiresAt, nil
+}
+
+// SaveToken binds the token hash to the user, replacing the previous token of the user
+func (r *AccountRepository) SaveToken(ctx context.Context, tokenHash string, userID int64) error {
+	_, err := r.client.exec(ctx, "REPLACE INTO tokens (token_hash, user_id) VALUES (?, ?)", tokenHash, userID)
+	return err
+}
+
+// FindToken returns the user of the token hash, false for unknown tokens
+func (r *AccountRepository) FindToken(ctx context.Context, tokenHash string) (int64, bool, error) {
+	return r.findUser(ctx, "SELECT user_id FROM tokens WHERE token_hash = ?", tokenHash)
+}
+
+// SaveIdentity binds the external identity of the provider to the user
+func (r *AccountRepository) SaveIdentity(ctx context.Context, provider, subject string, userID int64) error {
+	_, err := r.client.exec(ctx,
+		"INSERT INTO identities (provider, subject, user_id) VALUES (?, ?, ?) ON CONFLICT (provider, subject) DO UPDATE SET user_id = excluded.user_id",
+		provider, subject, userID)
+	return err
+}
+
+// FindIdentity returns the user of the external identity, false for identities not linked to the bot
+func (r *AccountRepository) FindIdentity(ctx context.Context, provider, subject string) (int64, bool, error) {
+	return r.findUser(ctx, "SELECT user_id FROM identities WHERE provider = ? AND subject = ?", provider, subject)
+}
+
+// Identities returns the external identities linked to the user ordered by provider and subject
+func (r *AccountRepository) Identities(ctx context.Context, userID int64) ([]entities.Identity, error) {
+	rows, err := r.client.db.QueryContext(ctx, "SELECT provider, subject FROM identities WHERE user_id = ? ORDER BY provider, subject", userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list identities: %w", err)
+	}
+	defer rows.Close()
+
+	identities := make([]entities.Identity, 0)
+	for rows.Next() {
+		identity := entities.Identity{UserID: userID}
+		if err := rows.Scan(&identity.Provider, &identity.Subject); err != nil {
+			return nil, fmt.Errorf("failed to read identity: %w", err)
+		}
+		identities = append(identities, identity)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list identities: %w", err)
+	}
+
+	return identities, nil
+}
+
+// DeleteUser removes the link code, the token and the external identities of the user in a single transaction
+func (r *AccountRepository) DeleteUser(ctx context.Context, userID int64) error {
+	tx, err := r.client.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, table := range []string{"link_codes", "tokens", "identities"} {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM "+table+" WHERE user_id = ?", userID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// findUser reads the user ID of the row of the query, ok is false when no row matches
+func (r *AccountRepository) findUser(ctx context.Context, query string, args ...any) (int64, bool, error) {
+	var userID int64
+	err := r.client.db.QueryRowContext(ctx, query, args...).Scan(&userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	return userID, true, nil
+}
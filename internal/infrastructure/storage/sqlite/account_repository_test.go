@@ -0,0 +1,86 @@
+package sqlite
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAccountRepository(t *testing.T) {
+	ctx := context.Background()
+	r := NewAccountRepository(newTestClient(t))
+
+	if err := r.SaveLinkCode(ctx, "FIRST", 1, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.SaveLinkCode(ctx, "SECOND", 1, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, err := r.TakeLinkCode(ctx, "FIRST"); err != nil || ok {
+		t.Fatalf("TakeLinkCode of a replaced code = %t, %v, want false", ok, err)
+	}
+	if userID, ok, err := r.TakeLinkCode(ctx, "SECOND"); err != nil || !ok || userID != 1 {
+		t.Fatalf("TakeLinkCode = %d, %t, %v, want user 1", userID, ok, err)
+	}
+	if _, ok, err := r.TakeLinkCode(ctx, "SECOND"); err != nil || ok {
+		t.Fatalf("TakeLinkCode of a taken code = %t, %v, want false", ok, err)
+	}
+	if err := r.SaveLinkCode(ctx, "EXPIRED", 2, -time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, err := r.TakeLinkCode(ctx, "EXPIRED"); err != nil || ok {
+		t.Fatalf("TakeLinkCode of an expired code = %t, %v, want false", ok, err)
+	}
+
+	if err := r.SaveToken(ctx, "old", 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.SaveToken(ctx, "new", 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, err := r.FindToken(ctx, "old"); err != nil || ok {
+		t.Fatalf("FindToken of a replaced token = %t, %v, want false", ok, err)
+	}
+	if userID, ok, err := r.FindToken(ctx, "new"); err != nil || !ok || userID != 1 {
+		t.Fatalf("FindToken = %d, %t, %v, want user 1", userID, ok, err)
+	}
+
+	for _, identity := range []entities.Identity{{Provider: "google", Subject: "b"}, {Provider: "apple", Subject: "c"}, {Provider: "google", Subject: "a"}} {
+		if err := r.SaveIdentity(ctx, identity.Provider, identity.Subject, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if userID, ok, err := r.FindIdentity(ctx, "google", "a"); err != nil || !ok || userID != 1 {
+		t.Fatalf("FindIdentity = %d, %t, %v, want user 1", userID, ok, err)
+	}
+	identities, err := r.Identities(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []entities.Identity{
+		{UserID: 1, Provider: "apple", Subject: "c"},
+		{UserID: 1, Provider: "google", Subject: "a"},
+		{UserID: 1, Provider: "google", Subject: "b"},
+	}
+	if !reflect.DeepEqual(identities, want) {
+		t.Fatalf("Identities = %+v, want %+v", identities, want)
+	}
+
+	if err := r.SaveLinkCode(ctx, "THIRD", 1, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.DeleteUser(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, err := r.TakeLinkCode(ctx, "THIRD"); err != nil || ok {
+		t.Fatalf("TakeLinkCode of a deleted user = %t, %v, want false", ok, err)
+	}
+	if _, ok, err := r.FindToken(ctx, "new"); err != nil || ok {
+		t.Fatalf("FindToken of a deleted user = %t, %v, want false", ok, err)
+	}
+	if identities, err := r.Identities(ctx, 1); err != nil || len(identities) != 0 {
+		t.Fatalf("Identities of a deleted user = %+v, %v, want none", identities, err)
+	}
+}
@@ -0,0 +1,69 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+)
+
+// AchievementRepository keeps the achievements of users in the achievements table, one row per user and
+// achievement
+type AchievementRepository struct {
+	client *Client
+}
+
+// NewAchievementRepository creates a new SQLite achievement repository
+func NewAchievementRepository(client *Client) *AchievementRepository {
+	return &AchievementRepository{client: client}
+}
+
+// Award stores the achievement of the user, false if the user already has it
+func (r *AchievementRepository) Award(ctx context.Context, userID int64, achievement entities.Achievement) (bool, error) {
+	data, err := json.Marshal(achievement)
+	if err != nil {
+		return false, err
+	}
+
+	inserted, err := r.client.exec(ctx,
+		"INSERT INTO achievements (user_id, id, awarded_at, data) VALUES (?, ?, ?, ?) ON CONFLICT (user_id, id) DO NOTHING",
+		userID, achievement.ID, achievement.AwardedAt.UnixNano(), data)
+	if err != nil {
+		return false, err
+	}
+
+	return inserted == 1, nil
+}
+
+// List returns the achievements of the user, oldest first
+func (r *AchievementRepository) List(ctx context.Context, userID int64) ([]entities.Achievement, error) {
+	rows, err := r.client.db.QueryContext(ctx, "SELECT data FROM achievements WHERE user_id = ? ORDER BY awarded_at, id", userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list achievements: %w", err)
+	}
+	defer rows.Close()
+
+	var achievements []entities.Achievement
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to read achievement: %w", err)
+		}
+		var achievement entities.Achievement
+		if err := json.Unmarshal(data, &achievement); err != nil {
+			return nil, fmt.Errorf("invalid achievement of user %d: %w", userID, err)
+		}
+		achievements = append(achievements, achievement)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list achievements: %w", err)
+	}
+
+	return achievements, nil
+}
+
+// Delete removes the achievements of the user
+func (r *AchievementRepository) Delete(ctx context.Context, userID int64) error {
+	_, err := r.client.exec(ctx, "DELETE FROM achievements WHERE user_id = ?", userID)
+	return err
+}
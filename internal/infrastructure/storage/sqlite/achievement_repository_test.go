@@ -0,0 +1,45 @@
+package sqlite
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"testing"
+	"time"
+)
+
+func TestAchievementRepository(t *testing.T) {
+	ctx := context.Background()
+	r := NewAchievementRepository(newTestClient(t))
+
+	awardedAt := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+	for _, achievement := range []entities.Achievement{
+		{ID: "streak_10", Badge: "🔥", Title: "10-day streak", AwardedAt: awardedAt.Add(time.Hour)},
+		{ID: "lookups_100", Badge: "📚", Title: "100 words", AwardedAt: awardedAt},
+	} {
+		if awarded, err := r.Award(ctx, 1, achievement); err != nil || !awarded {
+			t.Fatalf("Award(%s) = %v, %v, want a new achievement", achievement.ID, awarded, err)
+		}
+	}
+	if awarded, err := r.Award(ctx, 1, entities.Achievement{ID: "streak_10", AwardedAt: awardedAt.Add(2 * time.Hour)}); err != nil || awarded {
+		t.Fatalf("Award of an awarded achievement = %v, %v, want false", awarded, err)
+	}
+
+	achievements, err := r.List(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(achievements) != 2 || achievements[0].ID != "lookups_100" || achievements[1].ID != "streak_10" ||
+		!achievements[1].AwardedAt.Equal(awardedAt.Add(time.Hour)) {
+		t.Fatalf("List = %+v, want lookups_100 and the first award of streak_10", achievements)
+	}
+	if achievements, err := r.List(ctx, 2); err != nil || len(achievements) != 0 {
+		t.Fatalf("List of another user = %+v, %v, want none", achievements, err)
+	}
+
+	if err := r.Delete(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if achievements, err := r.List(ctx, 1); err != nil || len(achievements) != 0 {
+		t.Fatalf("List after Delete = %+v, %v, want none", achievements, err)
+	}
+}
@@ -0,0 +1,164 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/storage/document"
+	"time"
+)
+
+// ActivityRepository keeps the learning activity of users in the activity table, one row per user
+type ActivityRepository struct {
+	client *Client
+}
+
+// NewActivityRepository creates a new SQLite activity repository
+func NewActivityRepository(client *Client) *ActivityRepository {
+	return &ActivityRepository{client: client}
+}
+
+// RecordLookup counts the word for the day of at in its time zone, once per word and day, and keeps its answer
+func (r *ActivityRepository) RecordLookup(ctx context.Context, userID int64, word entities.LookedUpWord, at time.Time) error {
+	return r.update(ctx, userID, func(activity *document.Activity) bool {
+		return activity.RecordLookup(word, at)
+	})
+}
+
+// RecordQuizAnswer counts the answer for its day in its time zone and the article of the noun
+func (r *ActivityRepository) RecordQuizAnswer(ctx context.Context, userID int64, answer *entities.QuizAnswer) error {
+	return r.update(ctx, userID, func(activity *document.Activity) bool {
+		activity.RecordQuizAnswer(answer)
+		return true
+	})
+}
+
+// SearchWords returns at most limit words of the user matching the query, the best matches first
+func (r *ActivityRepository) SearchWords(ctx context.Context, userID int64, query string, limit int) ([]entities.WordMatch, error) {
+	activity, ok, err := r.load(ctx, userID)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	return activity.Search(query, limit), nil
+}
+
+// Activity returns the activity of the user, empty if none was recorded
+func (r *ActivityRepository) Activity(ctx context.Context, userID int64) (*entities.UserActivity, error) {
+	activity, ok, err := r.load(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return &entities.UserActivity{UserID: userID}, nil
+	}
+
+	return activity.Entity(userID), nil
+}
+
+// load reads the stored activity of the user, ok is false if none was recorded
+func (r *ActivityRepository) load(ctx context.Context, userID int64) (*document.Activity, bool, error) {
+	data, ok, err := r.client.get(ctx, "SELECT data FROM activity WHERE user_id = ?", userID)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	activity := document.NewActivity()
+	if err := json.Unmarshal(data, activity); err != nil {
+		return nil, false, fmt.Errorf("invalid activity of user %d: %w", userID, err)
+	}
+
+	return activity, true, nil
+}
+
+// Delete removes the activity of the user, unknown users are ignored
+func (r *ActivityRepository) Delete(ctx context.Context, userID int64) error {
+	_, err := r.client.exec(ctx, "DELETE FROM activity WHERE user_id = ?", userID)
+	return err
+}
+
+// DeleteInactive removes the activity of the users last active before the time and returns their number.
+// The activity is kept as JSON, so the rows are checked in a transaction.
+func (r *ActivityRepository) DeleteInactive(ctx context.Context, before time.Time) (int, error) {
+	tx, err := r.client.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, "SELECT user_id, data FROM activity")
+	if err != nil {
+		return 0, err
+	}
+	var inactive []int64
+	for rows.Next() {
+		var (
+			userID int64
+			data   []byte
+		)
+		if err := rows.Scan(&userID, &data); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		activity := document.NewActivity()
+		if err := json.Unmarshal(data, activity); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("invalid activity of user %d: %w", userID, err)
+		}
+		if activity.ActiveAt.Before(before) {
+			inactive = append(inactive, userID)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, userID := range inactive {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM activity WHERE user_id = ?", userID); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(inactive), tx.Commit()
+}
+
+// update applies the change to the activity of the user in a transaction, the row is written only
+// when the change reports a modification
+func (r *ActivityRepository) update(ctx context.Context, userID int64, change func(activity *document.Activity) bool) error {
+	tx, err := r.client.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	activity := document.NewActivity()
+	var data []byte
+	err = tx.QueryRowContext(ctx, "SELECT data FROM activity WHERE user_id = ?", userID).Scan(&data)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		return err
+	default:
+		if err := json.Unmarshal(data, activity); err != nil {
+			return fmt.Errorf("invalid activity of user %d: %w", userID, err)
+		}
+	}
+
+	if !change(activity) {
+		return nil
+	}
+	if data, err = json.Marshal(activity); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx,
+		"INSERT INTO activity (user_id, data) VALUES (?, ?) ON CONFLICT (user_id) DO UPDATE SET data = excluded.data",
+		userID, data); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,37 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAuditRepository(t *testing.T) {
+	ctx := context.Background()
+	r := NewAuditRepository(newTestClient(t))
+
+	at := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+	entries := []*entities.AuditEntry{
+		{ID: "1", Action: entities.AuditActionCachePurge, Actor: "anna", ClientIP: "10.0.0.1", At: at},
+		{ID: "2", Action: entities.AuditActionBroadcast, Actor: "admin", Payload: json.RawMessage(`{"text":"Hallo"}`), At: at.Add(time.Minute)},
+		{ID: "3", Action: entities.AuditActionCachePurge, Actor: "admin", At: at.Add(2 * time.Minute)},
+	}
+	for _, entry := range entries {
+		if err := r.Append(ctx, entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := r.Append(ctx, entries[0]); err == nil {
+		t.Fatal("Append of an existing entry succeeded, want the entries to be append-only")
+	}
+
+	if listed, err := r.List(ctx, "", 2); err != nil || !reflect.DeepEqual(listed, []*entities.AuditEntry{entries[2], entries[1]}) {
+		t.Fatalf("List = %+v, %v, want the newest entries 3 and 2", listed, err)
+	}
+	if listed, err := r.List(ctx, entities.AuditActionCachePurge, 10); err != nil || !reflect.DeepEqual(listed, []*entities.AuditEntry{entries[2], entries[0]}) {
+		t.Fatalf("List of an action = %+v, %v, want the entries 3 and 1", listed, err)
+	}
+}
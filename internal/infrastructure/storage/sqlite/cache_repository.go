@@ -0,0 +1,58 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"time"
+)
+
+// CacheRepository keeps article responses in the cache table
+type CacheRepository struct {
+	client *Client
+}
+
+// NewCacheRepository creates a new SQLite cache
+func NewCacheRepository(client *Client) *CacheRepository {
+	return &CacheRepository{client: client}
+}
+
+// Get returns the cached response if it has not expired
+func (r *CacheRepository) Get(ctx context.Context, key string) (*entities.ArticleResponse, bool, error) {
+	data, ok, err := r.client.get(ctx, "SELECT data FROM cache WHERE key = ? AND expires_at >= ?", key, time.Now().UnixNano())
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	var response entities.ArticleResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, false, fmt.Errorf("invalid cached response %q: %w", key, err)
+	}
+
+	return &response, true, nil
+}
+
+// Set stores the response for ttl
+func (r *CacheRepository) Set(ctx context.Context, key string, response *entities.ArticleResponse, ttl time.Duration) error {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.exec(ctx,
+		"INSERT INTO cache (key, data, expires_at) VALUES (?, ?, ?) ON CONFLICT (key) DO UPDATE SET data = excluded.data, expires_at = excluded.expires_at",
+		key, data, time.Now().Add(ttl).UnixNano())
+	return err
+}
+
+// Delete removes the entry of the key and of its variants, e.g. the levels of a word for "haus|en"
+func (r *CacheRepository) Delete(ctx context.Context, key string) (int, error) {
+	// The variants continue the key after a "|", "}" is the character following it
+	return r.client.exec(ctx, "DELETE FROM cache WHERE key = ? OR (key >= ? AND key < ?)", key, key+"|", key+"}")
+}
+
+// Clear removes every entry
+func (r *CacheRepository) Clear(ctx context.Context) (int, error) {
+	return r.client.exec(ctx, "DELETE FROM cache")
+}
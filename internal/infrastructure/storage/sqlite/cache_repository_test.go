@@ -0,0 +1,112 @@
+package sqlite
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/libs/race"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// allocationTolerance is the allowed growth of the allocations per call over their baseline, 0.1 is 10%
+const allocationTolerance = 0.1
+
+// newTestClient returns a migrated database in the temporary directory of the test
+func newTestClient(tb testing.TB) *Client {
+	tb.Helper()
+	ctx := context.Background()
+	client, err := NewClient(ctx, filepath.Join(tb.TempDir(), "bot.db"))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() { _ = client.Close() })
+	if _, err := client.Up(ctx); err != nil {
+		tb.Fatal(err)
+	}
+
+	return client
+}
+
+// newTestCache returns a cache repository of a migrated database in the temporary directory of the test
+func newTestCache(tb testing.TB) *CacheRepository {
+	tb.Helper()
+	return NewCacheRepository(newTestClient(tb))
+}
+
+// benchmarkResponse returns a cached answer with examples of both numbers
+func benchmarkResponse() *entities.ArticleResponse {
+	examples := entities.TranslationsInfo{
+		NominativeExample: "Das Haus ist groß.", NominativeTranslation: "The house is big.",
+		AccusativeExample: "Ich sehe das Haus.", AccusativeTranslation: "I see the house.",
+		DativeExample: "Ich wohne in dem Haus.", DativeTranslation: "I live in the house.",
+		GenitiveExample: "Das Dach des Hauses ist rot.", GenitiveTranslation: "The roof of the house is red.",
+	}
+	response := entities.NewSuccessResponse([]entities.ArticleInfo{{
+		WordWithArticle: "das Haus",
+		Translation:     "house",
+		Plural:          "die Häuser",
+		Example: entities.ExamplesInfo{
+			Singular: entities.ExampleInfo{Definite: examples, Indefinite: examples},
+			Plural:   entities.ExampleInfo{Definite: examples, Indefinite: examples},
+		},
+	}})
+	response.Stamp(entities.SourceGemini, time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC))
+
+	return response
+}
+
+func BenchmarkCacheRepositorySet(b *testing.B) {
+	ctx := context.Background()
+	r := newTestCache(b)
+	response := benchmarkResponse()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		if err := r.Set(ctx, "haus|en", response, time.Hour); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCacheRepositoryGet(b *testing.B) {
+	ctx := context.Background()
+	r := newTestCache(b)
+	if err := r.Set(ctx, "haus|en", benchmarkResponse(), time.Hour); err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		if _, ok, err := r.Get(ctx, "haus|en"); err != nil || !ok {
+			b.Fatal("the cached answer can't be read", err)
+		}
+	}
+}
+
+// TestCacheRepositoryAllocations fails when reading or writing the cache allocates noticeably more than the
+// baseline, the durations depend on the disk and are left to the benchmarks
+func TestCacheRepositoryAllocations(t *testing.T) {
+	if race.Enabled {
+		t.Skip("The race detector changes the allocations")
+	}
+	ctx := context.Background()
+	r := newTestCache(t)
+	response := benchmarkResponse()
+
+	tests := []struct {
+		name     string
+		baseline float64
+		run      func()
+	}{
+		{name: "Set", baseline: 11, run: func() { _ = r.Set(ctx, "haus|en", response, time.Hour) }},
+		{name: "Get", baseline: 21, run: func() { _, _, _ = r.Get(ctx, "haus|en") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if allocs := testing.AllocsPerRun(100, tt.run); allocs > tt.baseline*(1+allocationTolerance) {
+				t.Errorf("%.0f allocations per call, the baseline is %.0f", allocs, tt.baseline)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+)
+
+// ChatRepository keeps the known chats in the chats table, the opt-out has a column of its own so touching
+// a chat keeps it
+type ChatRepository struct {
+	client *Client
+}
+
+// NewChatRepository creates a new SQLite chat repository
+func NewChatRepository(client *Client) *ChatRepository {
+	return &ChatRepository{client: client}
+}
+
+// Touch adds the chat or updates its language and the time it was seen, the opt-out is kept
+func (r *ChatRepository) Touch(ctx context.Context, chat *entities.KnownChat) error {
+	data, err := json.Marshal(chat)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.exec(ctx,
+		"INSERT INTO chats (id, opted_out, data) VALUES (?, ?, ?) ON CONFLICT (id) DO UPDATE SET data = excluded.data",
+		chat.ID, chat.OptedOut, data)
+	return err
+}
+
+// SetOptOut sets whether the chat gets announcements, false if the chat is unknown
+func (r *ChatRepository) SetOptOut(ctx context.Context, chatID int64, optedOut bool) (bool, error) {
+	updated, err := r.client.exec(ctx, "UPDATE chats SET opted_out = ? WHERE id = ?", optedOut, chatID)
+	if err != nil {
+		return false, err
+	}
+
+	return updated == 1, nil
+}
+
+// Get returns the chat, false if it is unknown
+func (r *ChatRepository) Get(ctx context.Context, chatID int64) (*entities.KnownChat, bool, error) {
+	chat, err := scanChat(r.client.db.QueryRowContext(ctx, "SELECT opted_out, data FROM chats WHERE id = ?", chatID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	return &chat, true, nil
+}
+
+// Delete forgets the chat, unknown chats are ignored
+func (r *ChatRepository) Delete(ctx context.Context, chatID int64) error {
+	_, err := r.client.exec(ctx, "DELETE FROM chats WHERE id = ?", chatID)
+	return err
+}
+
+// List returns all known chats ordered by ID
+func (r *ChatRepository) List(ctx context.Context) ([]entities.KnownChat, error) {
+	rows, err := r.client.db.QueryContext(ctx, "SELECT opted_out, data FROM chats ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list chats: %w", err)
+	}
+	defer rows.Close()
+
+	chats := make([]entities.KnownChat, 0)
+	for rows.Next() {
+		chat, err := scanChat(rows)
+		if err != nil {
+			return nil, err
+		}
+		chats = append(chats, chat)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list chats: %w", err)
+	}
+
+	return chats, nil
+}
+
+// scanChat reads a chat with the opt-out of its column
+func scanChat(row interface{ Scan(dest ...any) error }) (entities.KnownChat, error) {
+	var chat entities.KnownChat
+	var optedOut bool
+	var data []byte
+	if err := row.Scan(&optedOut, &data); err != nil {
+		return chat, err
+	}
+	if err := json.Unmarshal(data, &chat); err != nil {
+		return chat, fmt.Errorf("invalid chat: %w", err)
+	}
+	chat.OptedOut = optedOut
+
+	return chat, nil
+}
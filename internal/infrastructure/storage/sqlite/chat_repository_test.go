@@ -0,0 +1,56 @@
+package sqlite
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestChatRepository(t *testing.T) {
+	ctx := context.Background()
+	r := NewChatRepository(newTestClient(t))
+
+	seenAt := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+	for _, chat := range []*entities.KnownChat{
+		{ID: 1, Language: "en", SeenAt: seenAt},
+		{ID: -100, Group: true, Language: "de", SeenAt: seenAt},
+	} {
+		if err := r.Touch(ctx, chat); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if known, err := r.SetOptOut(ctx, 1, true); err != nil || !known {
+		t.Fatalf("SetOptOut = %v, %v, want a known chat", known, err)
+	}
+	if known, err := r.SetOptOut(ctx, 2, true); err != nil || known {
+		t.Fatalf("SetOptOut of an unknown chat = %v, %v, want false", known, err)
+	}
+
+	// Touching the chat keeps the opt-out
+	touched := &entities.KnownChat{ID: 1, Language: "ru", SeenAt: seenAt.Add(time.Hour)}
+	if err := r.Touch(ctx, touched); err != nil {
+		t.Fatal(err)
+	}
+	want := entities.KnownChat{ID: 1, Language: "ru", OptedOut: true, SeenAt: seenAt.Add(time.Hour)}
+	if chat, ok, err := r.Get(ctx, 1); err != nil || !ok || !reflect.DeepEqual(*chat, want) {
+		t.Fatalf("Get = %+v, %v, %v, want %+v", chat, ok, err, want)
+	}
+
+	chats, err := r.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chats) != 2 || chats[0].ID != -100 || chats[1].ID != 1 {
+		t.Fatalf("List = %+v, want the chats ordered by ID", chats)
+	}
+
+	if err := r.Delete(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, err := r.Get(ctx, 1); err != nil || ok {
+		t.Fatalf("Get of a deleted chat = %v, %v, want false", ok, err)
+	}
+}
@@ -0,0 +1,111 @@
+// Package sqlite keeps the repositories in the tables of a local SQLite database
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/storage/migration"
+	"github.com/golang-migrate/migrate/v4/database"
+	migratesqlite "github.com/golang-migrate/migrate/v4/database/sqlite"
+	// Registers the pure Go driver, no cgo is needed
+	_ "modernc.org/sqlite"
+	"net/url"
+	"time"
+)
+
+// migrations are the versioned schemas of the tables
+//
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// Client is the SQLite database shared by the repositories
+type Client struct {
+	db *sql.DB
+	*migration.SQL
+}
+
+// NewClient opens the database file, creating it if needed
+func NewClient(ctx context.Context, path string) (*Client, error) {
+	// The writers wait for each other instead of failing as busy
+	dsn := "file:" + url.PathEscape(path) + "?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SQLite database: %w", err)
+	}
+	// SQLite has a single writer, one connection serializes the transactions of the instance
+	db.SetMaxOpenConns(1)
+	if err := db.PingContext(ctx); err != nil {
+		return nil, errors.Join(fmt.Errorf("failed to open SQLite database: %w", err), db.Close())
+	}
+
+	// The migrations get their own connection, it's closed after every run
+	open := func() (database.Driver, error) {
+		migrationDB, err := sql.Open("sqlite", dsn)
+		if err != nil {
+			return nil, err
+		}
+		return migratesqlite.WithInstance(migrationDB, &migratesqlite.Config{})
+	}
+
+	return &Client{db: db, SQL: migration.NewSQL("sqlite", migrations, "migrations", open)}, nil
+}
+
+// RemoveExpired removes the expired answers, jobs, idempotency keys, link codes, conversations and replies
+func (c *Client) RemoveExpired(ctx context.Context) error {
+	now := time.Now().UnixNano()
+	for _, table := range []string{"cache", "jobs", "idempotency", "link_codes", "conversations", "replies"} {
+		if _, err := c.db.ExecContext(ctx, "DELETE FROM "+table+" WHERE expires_at < ?", now); err != nil {
+			return fmt.Errorf("failed to remove expired %s: %w", table, err)
+		}
+	}
+
+	return nil
+}
+
+// Close closes the database
+func (c *Client) Close() error {
+	return c.db.Close()
+}
+
+// Name returns the dependency name
+func (c *Client) Name() string {
+	return "sqlite"
+}
+
+// Critical reports that the stored answers and user data aren't available without the database
+func (c *Client) Critical() bool {
+	return true
+}
+
+// Check pings the database
+func (c *Client) Check(ctx context.Context) error {
+	return c.db.PingContext(ctx)
+}
+
+// get reads the data of the row of the query, ok is false when no row matches
+func (c *Client) get(ctx context.Context, query string, args ...any) ([]byte, bool, error) {
+	var data []byte
+	err := c.db.QueryRowContext(ctx, query, args...).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	return data, true, nil
+}
+
+// exec runs the statement and returns the number of affected rows
+func (c *Client) exec(ctx context.Context, query string, args ...any) (int, error) {
+	result, err := c.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	affected, err := result.RowsAffected()
+
+	return int(affected), err
+}
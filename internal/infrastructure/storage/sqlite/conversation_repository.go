@@ -0,0 +1,52 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"time"
+)
+
+// ConversationRepository keeps the last word of every chat in the conversations table until it expires
+type ConversationRepository struct {
+	client *Client
+}
+
+// NewConversationRepository creates a new SQLite conversation repository
+func NewConversationRepository(client *Client) *ConversationRepository {
+	return &ConversationRepository{client: client}
+}
+
+// Get returns the conversation of the chat, false if there is none or it has expired
+func (r *ConversationRepository) Get(ctx context.Context, chatID int64) (*entities.Conversation, bool, error) {
+	data, ok, err := r.client.get(ctx, "SELECT data FROM conversations WHERE chat_id = ? AND expires_at >= ?", chatID, time.Now().UnixNano())
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	var conversation entities.Conversation
+	if err := json.Unmarshal(data, &conversation); err != nil {
+		return nil, false, fmt.Errorf("invalid conversation of chat %d: %w", chatID, err)
+	}
+
+	return &conversation, true, nil
+}
+
+// Save replaces the conversation of the chat
+func (r *ConversationRepository) Save(ctx context.Context, conversation *entities.Conversation, ttl time.Duration) error {
+	data, err := json.Marshal(conversation)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.exec(ctx, "REPLACE INTO conversations (chat_id, data, expires_at) VALUES (?, ?, ?)",
+		conversation.ChatID, data, time.Now().Add(ttl).UnixNano())
+	return err
+}
+
+// Delete forgets the conversation of the chat
+func (r *ConversationRepository) Delete(ctx context.Context, chatID int64) error {
+	_, err := r.client.exec(ctx, "DELETE FROM conversations WHERE chat_id = ?", chatID)
+	return err
+}
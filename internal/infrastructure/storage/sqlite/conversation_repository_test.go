@@ -0,0 +1,36 @@
+package sqlite
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestConversationRepository(t *testing.T) {
+	ctx := context.Background()
+	r := NewConversationRepository(newTestClient(t))
+
+	conversation := &entities.Conversation{ChatID: 1, Word: "Haus", Language: "en", Meaning: -1, UpdatedAt: time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)}
+	if err := r.Save(ctx, conversation, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if stored, ok, err := r.Get(ctx, 1); err != nil || !ok || !reflect.DeepEqual(stored, conversation) {
+		t.Fatalf("Get = %+v, %v, %v, want %+v", stored, ok, err, conversation)
+	}
+
+	if err := r.Save(ctx, &entities.Conversation{ChatID: 2, Word: "Tisch"}, -time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, err := r.Get(ctx, 2); err != nil || ok {
+		t.Fatalf("Get of an expired conversation = %v, %v, want false", ok, err)
+	}
+
+	if err := r.Delete(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, err := r.Get(ctx, 1); err != nil || ok {
+		t.Fatalf("Get of a deleted conversation = %v, %v, want false", ok, err)
+	}
+}
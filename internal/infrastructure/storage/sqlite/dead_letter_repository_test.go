@@ -0,0 +1,60 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDeadLetterRepository(t *testing.T) {
+	ctx := context.Background()
+	r := NewDeadLetterRepository(newTestClient(t))
+
+	failedAt := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+	letters := []*entities.DeadLetter{
+		{ID: "b", UpdateID: 2, Update: json.RawMessage(`{"update_id":2}`), UserID: 1, Error: "timeout", FailedAt: failedAt},
+		{ID: "a", UpdateID: 3, Update: json.RawMessage(`{"update_id":3}`), UserID: 2, Error: "timeout", FailedAt: failedAt.Add(time.Hour)},
+		{ID: "c", UpdateID: 4, Update: json.RawMessage(`{"update_id":4}`), UserID: 1, Error: "timeout", FailedAt: failedAt.Add(2 * time.Hour)},
+	}
+	for _, letter := range letters {
+		if err := r.Save(ctx, letter); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	replayed := *letters[0]
+	replayed.Attempts = 1
+	replayed.Error = "still failing"
+	if err := r.Save(ctx, &replayed); err != nil {
+		t.Fatal(err)
+	}
+	if stored, ok, err := r.Get(ctx, "b"); err != nil || !ok || !reflect.DeepEqual(stored, &replayed) {
+		t.Fatalf("Get = %+v, %v, %v, want the replaced letter %+v", stored, ok, err, replayed)
+	}
+	if _, ok, err := r.Get(ctx, "unknown"); err != nil || ok {
+		t.Fatalf("Get of an unknown letter = %v, %v, want false", ok, err)
+	}
+
+	if listed, err := r.List(ctx, 2); err != nil || len(listed) != 2 || listed[0].ID != "b" || listed[1].ID != "a" {
+		t.Fatalf("List = %+v, %v, want the oldest letters b and a", listed, err)
+	}
+	if listed, err := r.ListUser(ctx, 1); err != nil || len(listed) != 2 || listed[0].ID != "b" || listed[1].ID != "c" {
+		t.Fatalf("ListUser = %+v, %v, want the letters b and c", listed, err)
+	}
+
+	if deleted, err := r.DeleteBefore(ctx, failedAt.Add(time.Hour)); err != nil || deleted != 1 {
+		t.Fatalf("DeleteBefore = %d, %v, want 1", deleted, err)
+	}
+	if err := r.DeleteUser(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Delete(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if listed, err := r.List(ctx, 10); err != nil || len(listed) != 0 {
+		t.Fatalf("List after deleting every letter = %+v, %v, want none", listed, err)
+	}
+}
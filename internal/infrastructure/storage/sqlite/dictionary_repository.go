@@ -0,0 +1,56 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+)
+
+// DictionaryRepository keeps the curated dictionary entries in the dictionary table, keyed by the lower-cased noun
+type DictionaryRepository struct {
+	client *Client
+}
+
+// NewDictionaryRepository creates a new SQLite dictionary repository
+func NewDictionaryRepository(client *Client) *DictionaryRepository {
+	return &DictionaryRepository{client: client}
+}
+
+// Get returns the entry of the noun, nil for nouns without one
+func (r *DictionaryRepository) Get(ctx context.Context, word string) (*entities.CuratedEntry, error) {
+	data, ok, err := r.client.get(ctx, "SELECT data FROM dictionary WHERE word = ?", entities.CuratedKey(word))
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	var entry entities.CuratedEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("invalid dictionary entry %q: %w", word, err)
+	}
+
+	return &entry, nil
+}
+
+// Save replaces the entries of their nouns in a single transaction
+func (r *DictionaryRepository) Save(ctx context.Context, entries []entities.CuratedEntry) error {
+	tx, err := r.client.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, entry := range entries {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx,
+			"INSERT INTO dictionary (word, data) VALUES (?, ?) ON CONFLICT (word) DO UPDATE SET data = excluded.data",
+			entry.Key(), data); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,160 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/internal/infrastructure/storage/document"
+	"strings"
+	"time"
+)
+
+// feedbackColumns maps the ordering fields of feedback lists to the columns of the feedback table
+var feedbackColumns = map[string]string{
+	"createdAt": "created_at",
+	"word":      "word",
+}
+
+// FeedbackRepository keeps user ratings in the feedback table, the filtered and ordered fields are
+// kept in columns next to the JSON of the rating
+type FeedbackRepository struct {
+	client *Client
+}
+
+// NewFeedbackRepository creates a new SQLite feedback repository
+func NewFeedbackRepository(client *Client) *FeedbackRepository {
+	return &FeedbackRepository{client: client}
+}
+
+// Save stores the feedback
+func (r *FeedbackRepository) Save(ctx context.Context, feedback *entities.Feedback) error {
+	data, err := json.Marshal(feedback)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.exec(ctx,
+		"INSERT OR REPLACE INTO feedback (id, verdict, word, language, created_at, data) VALUES (?, ?, ?, ?, ?, ?)",
+		feedback.ID, string(feedback.Verdict), strings.ToLower(feedback.Word), feedback.Language, document.TimeKey(feedback.CreatedAt), data)
+	return err
+}
+
+// List returns a page of the matching feedback, newest first unless the page orders it otherwise
+func (r *FeedbackRepository) List(ctx context.Context, filter entities.FeedbackFilter) (entities.Page[*entities.Feedback], error) {
+	page, keyOf := document.FeedbackPage(filter)
+	after, err := document.After(page)
+	if err != nil {
+		return entities.Page[*entities.Feedback]{}, err
+	}
+
+	column := feedbackColumns[page.OrderBy]
+	var (
+		conditions []string
+		args       []any
+	)
+	if filter.Verdict != "" {
+		conditions, args = append(conditions, "verdict = ?"), append(args, string(filter.Verdict))
+	}
+	if filter.Word != "" {
+		conditions, args = append(conditions, "word = ?"), append(args, strings.ToLower(filter.Word))
+	}
+	if filter.Language != "" {
+		conditions, args = append(conditions, "language = ?"), append(args, filter.Language)
+	}
+	direction, beyond := "ASC", ">"
+	if page.Descending {
+		direction, beyond = "DESC", "<"
+	}
+	if after != nil {
+		// Ties are broken by ascending ID as in the lists of every backend
+		conditions = append(conditions, fmt.Sprintf("(%s %s ? OR (%s = ? AND id > ?))", column, beyond, column))
+		args = append(args, after.Key, after.Key, after.ID)
+	}
+
+	query := "SELECT data FROM feedback"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += fmt.Sprintf(" ORDER BY %s %s, id ASC", column, direction)
+	if page.Limit > 0 {
+		// One more rating tells whether a next page exists
+		query += " LIMIT ?"
+		args = append(args, page.Limit+1)
+	}
+
+	rows, err := r.client.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return entities.Page[*entities.Feedback]{}, fmt.Errorf("failed to list feedback: %w", err)
+	}
+	defer rows.Close()
+
+	result := entities.Page[*entities.Feedback]{Items: make([]*entities.Feedback, 0)}
+	for rows.Next() {
+		if page.Limit > 0 && len(result.Items) == page.Limit {
+			result.NextPageToken = document.NextPageToken(page, keyOf(result.Items[len(result.Items)-1]))
+			break
+		}
+
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return entities.Page[*entities.Feedback]{}, fmt.Errorf("failed to read feedback: %w", err)
+		}
+		var feedback entities.Feedback
+		if err := json.Unmarshal(data, &feedback); err != nil {
+			return entities.Page[*entities.Feedback]{}, fmt.Errorf("invalid feedback: %w", err)
+		}
+		result.Items = append(result.Items, &feedback)
+	}
+	if err := rows.Err(); err != nil {
+		return entities.Page[*entities.Feedback]{}, fmt.Errorf("failed to list feedback: %w", err)
+	}
+
+	return result, nil
+}
+
+// ListUser returns the ratings of the user, oldest first
+func (r *FeedbackRepository) ListUser(ctx context.Context, userID int64) ([]*entities.Feedback, error) {
+	rows, err := r.client.db.QueryContext(ctx, "SELECT data FROM feedback WHERE json_extract(CAST(data AS TEXT), '$.userId') = ? ORDER BY created_at, id", userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list feedback of user %d: %w", userID, err)
+	}
+	defer rows.Close()
+
+	result := make([]*entities.Feedback, 0)
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to read feedback: %w", err)
+		}
+		var feedback entities.Feedback
+		if err := json.Unmarshal(data, &feedback); err != nil {
+			return nil, fmt.Errorf("invalid feedback: %w", err)
+		}
+		result = append(result, &feedback)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list feedback of user %d: %w", userID, err)
+	}
+
+	return result, nil
+}
+
+// DeleteUser removes the ratings of the user
+func (r *FeedbackRepository) DeleteUser(ctx context.Context, userID int64) error {
+	if _, err := r.client.exec(ctx, "DELETE FROM feedback WHERE json_extract(CAST(data AS TEXT), '$.userId') = ?", userID); err != nil {
+		return fmt.Errorf("failed to delete feedback of user %d: %w", userID, err)
+	}
+
+	return nil
+}
+
+// DeleteBefore removes the ratings created before the time and returns their number
+func (r *FeedbackRepository) DeleteBefore(ctx context.Context, before time.Time) (int, error) {
+	removed, err := r.client.exec(ctx, "DELETE FROM feedback WHERE created_at < ?", document.TimeKey(before))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete feedback: %w", err)
+	}
+
+	return removed, nil
+}
@@ -0,0 +1,74 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"time"
+)
+
+// IdempotencyRepository keeps the responses of requests with idempotency keys in the idempotency table
+type IdempotencyRepository struct {
+	client *Client
+}
+
+// NewIdempotencyRepository creates a new SQLite idempotency repository
+func NewIdempotencyRepository(client *Client) *IdempotencyRepository {
+	return &IdempotencyRepository{client: client}
+}
+
+// Begin reserves the key for the request with the fingerprint and returns nil, or the record of
+// an earlier request with the key. An expired record is replaced by the reservation.
+func (r *IdempotencyRepository) Begin(ctx context.Context, key, fingerprint string, ttl time.Duration) (*entities.IdempotentResponse, error) {
+	data, err := json.Marshal(&entities.IdempotentResponse{Fingerprint: fingerprint})
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		now := time.Now()
+		reserved, err := r.client.exec(ctx,
+			"INSERT INTO idempotency (key, data, expires_at) VALUES (?, ?, ?) ON CONFLICT (key) DO UPDATE SET data = excluded.data, expires_at = excluded.expires_at WHERE idempotency.expires_at < ?",
+			key, data, now.Add(ttl).UnixNano(), now.UnixNano())
+		if err != nil {
+			return nil, fmt.Errorf("failed to reserve idempotency key: %w", err)
+		}
+		if reserved > 0 {
+			return nil, nil
+		}
+
+		stored, ok, err := r.client.get(ctx, "SELECT data FROM idempotency WHERE key = ?", key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read idempotency key: %w", err)
+		}
+		// A key released between both statements is reserved by the next attempt
+		if !ok {
+			continue
+		}
+		var response entities.IdempotentResponse
+		if err := json.Unmarshal(stored, &response); err != nil {
+			return nil, fmt.Errorf("invalid response of idempotency key: %w", err)
+		}
+		return &response, nil
+	}
+}
+
+// Complete stores the response of the request holding the key
+func (r *IdempotencyRepository) Complete(ctx context.Context, key string, response *entities.IdempotentResponse, ttl time.Duration) error {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.exec(ctx,
+		"INSERT INTO idempotency (key, data, expires_at) VALUES (?, ?, ?) ON CONFLICT (key) DO UPDATE SET data = excluded.data, expires_at = excluded.expires_at",
+		key, data, time.Now().Add(ttl).UnixNano())
+	return err
+}
+
+// Release frees the key of a failed request, so a retry is processed again
+func (r *IdempotencyRepository) Release(ctx context.Context, key string) error {
+	_, err := r.client.exec(ctx, "DELETE FROM idempotency WHERE key = ?", key)
+	return err
+}
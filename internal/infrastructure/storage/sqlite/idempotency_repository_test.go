@@ -0,0 +1,42 @@
+package sqlite
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"testing"
+	"time"
+)
+
+func TestIdempotencyRepository(t *testing.T) {
+	ctx := context.Background()
+	r := NewIdempotencyRepository(newTestClient(t))
+
+	earlier, err := r.Begin(ctx, "key", "first", time.Hour)
+	if err != nil || earlier != nil {
+		t.Fatalf("Begin of a new key = %v, %v, want a reservation", earlier, err)
+	}
+	earlier, err = r.Begin(ctx, "key", "second", time.Hour)
+	if err != nil || earlier == nil || earlier.Fingerprint != "first" || earlier.Completed {
+		t.Fatalf("Begin of a reserved key = %+v, %v, want the pending first request", earlier, err)
+	}
+
+	response := &entities.IdempotentResponse{Fingerprint: "first", Completed: true, StatusCode: 200, Body: []byte(`{"success":true}`)}
+	if err := r.Complete(ctx, "key", response, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	earlier, err = r.Begin(ctx, "key", "first", time.Hour)
+	if err != nil || earlier == nil || !earlier.Completed || string(earlier.Body) != `{"success":true}` {
+		t.Fatalf("Begin of a completed key = %+v, %v, want the stored response", earlier, err)
+	}
+
+	if err := r.Release(ctx, "key"); err != nil {
+		t.Fatal(err)
+	}
+	if earlier, err = r.Begin(ctx, "key", "second", -time.Second); err != nil || earlier != nil {
+		t.Fatalf("Begin of a released key = %v, %v, want a reservation", earlier, err)
+	}
+	// The reservation has expired already, so the key is reserved again
+	if earlier, err = r.Begin(ctx, "key", "third", time.Hour); err != nil || earlier != nil {
+		t.Fatalf("Begin of an expired key = %v, %v, want a reservation", earlier, err)
+	}
+}
@@ -0,0 +1,54 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"time"
+)
+
+// JobRepository keeps the states of background jobs in the jobs table, the user of a job has a column of
+// its own
+type JobRepository struct {
+	client *Client
+}
+
+// NewJobRepository creates a new SQLite job repository
+func NewJobRepository(client *Client) *JobRepository {
+	return &JobRepository{client: client}
+}
+
+// Save stores the status for ttl, replacing the previous one of the job
+func (r *JobRepository) Save(ctx context.Context, status *entities.JobStatus, ttl time.Duration) error {
+	data, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.exec(ctx,
+		"INSERT INTO jobs (id, user_id, data, expires_at) VALUES (?, ?, ?, ?) ON CONFLICT (id) DO UPDATE SET data = excluded.data, expires_at = excluded.expires_at",
+		status.ID, status.UserID, data, time.Now().Add(ttl).UnixNano())
+	return err
+}
+
+// Get returns the status of the job if it has not expired
+func (r *JobRepository) Get(ctx context.Context, id string) (*entities.JobStatus, bool, error) {
+	data, ok, err := r.client.get(ctx, "SELECT data FROM jobs WHERE id = ? AND expires_at >= ?", id, time.Now().UnixNano())
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	var status entities.JobStatus
+	if err := json.Unmarshal(data, &status); err != nil {
+		return nil, false, fmt.Errorf("invalid status of job %s: %w", id, err)
+	}
+
+	return &status, true, nil
+}
+
+// DeleteUser removes the states of the jobs working for the user
+func (r *JobRepository) DeleteUser(ctx context.Context, userID int64) error {
+	_, err := r.client.exec(ctx, "DELETE FROM jobs WHERE user_id = ?", userID)
+	return err
+}
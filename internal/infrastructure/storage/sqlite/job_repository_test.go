@@ -0,0 +1,32 @@
+package sqlite
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"testing"
+	"time"
+)
+
+func TestJobRepositoryDeleteUser(t *testing.T) {
+	ctx := context.Background()
+	r := NewJobRepository(newTestClient(t))
+
+	for _, status := range []*entities.JobStatus{
+		{ID: "export", Type: "user.export", State: entities.JobDone, UserID: 1},
+		{ID: "other", Type: "user.export", State: entities.JobDone, UserID: 2},
+		{ID: "warmup", Type: "cache.warmup", State: entities.JobDone},
+	} {
+		if err := r.Save(ctx, status, time.Hour); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := r.DeleteUser(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	for id, want := range map[string]bool{"export": false, "other": true, "warmup": true} {
+		if _, ok, err := r.Get(ctx, id); err != nil || ok != want {
+			t.Errorf("Get(%s) = %v, %v, want %v", id, ok, err, want)
+		}
+	}
+}
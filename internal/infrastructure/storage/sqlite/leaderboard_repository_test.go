@@ -0,0 +1,85 @@
+package sqlite
+
+import (
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestLeaderboardRepository(t *testing.T) {
+	ctx := context.Background()
+	r := NewLeaderboardRepository(newTestClient(t))
+
+	member := &entities.LeaderboardMember{UserID: 1, Name: "Anna", Language: "de", JoinedAt: time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)}
+	if err := r.SaveMember(ctx, member); err != nil {
+		t.Fatal(err)
+	}
+	if stored, err := r.Member(ctx, 1); err != nil || !reflect.DeepEqual(stored, member) {
+		t.Fatalf("Member = %+v, %v, want %+v", stored, err, member)
+	}
+	if stored, err := r.Member(ctx, 2); err != nil || stored != nil {
+		t.Fatalf("Member of a user who didn't opt in = %+v, %v, want nil", stored, err)
+	}
+
+	for _, answer := range []struct {
+		week    string
+		chatID  int64
+		userID  int64
+		correct bool
+	}{
+		{"2026-W41", -100, 1, true},
+		{"2026-W42", -100, 1, true},
+		{"2026-W42", -100, 1, false},
+		{"2026-W42", -100, 2, true},
+		{"2026-W42", -200, 1, true},
+		{"2026-W42", entities.LeaderboardGlobal, 1, true},
+	} {
+		if err := r.RecordScore(ctx, answer.week, answer.chatID, answer.userID, answer.correct); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	scores, err := r.Scores(ctx, "2026-W42", -100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Slice(scores, func(i, j int) bool { return scores[i].UserID < scores[j].UserID })
+	want := []entities.LeaderboardScore{{UserID: 1, Answers: 2, Correct: 1}, {UserID: 2, Answers: 1, Correct: 1}}
+	if !reflect.DeepEqual(scores, want) {
+		t.Fatalf("Scores = %+v, want %+v", scores, want)
+	}
+	if chats, err := r.Chats(ctx, "2026-W42"); err != nil || !reflect.DeepEqual(chats, []int64{-200, -100}) {
+		t.Fatalf("Chats = %v, %v, want the group chats -200 and -100", chats, err)
+	}
+
+	// A new week drops the weeks before the previous one
+	if err := r.RecordScore(ctx, "2026-W43", -100, 1, true); err != nil {
+		t.Fatal(err)
+	}
+	if scores, err := r.Scores(ctx, "2026-W41", -100); err != nil || len(scores) != 0 {
+		t.Fatalf("Scores of a dropped week = %+v, %v, want none", scores, err)
+	}
+	if scores, err := r.Scores(ctx, "2026-W42", -100); err != nil || len(scores) != 2 {
+		t.Fatalf("Scores of the previous week = %+v, %v, want both members", scores, err)
+	}
+
+	if err := r.DeleteChat(ctx, -100); err != nil {
+		t.Fatal(err)
+	}
+	if chats, err := r.Chats(ctx, "2026-W42"); err != nil || !reflect.DeepEqual(chats, []int64{-200}) {
+		t.Fatalf("Chats after deleting a chat = %v, %v, want -200", chats, err)
+	}
+
+	if err := r.DeleteMember(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if stored, err := r.Member(ctx, 1); err != nil || stored != nil {
+		t.Fatalf("Member of a deleted member = %+v, %v, want nil", stored, err)
+	}
+	if scores, err := r.Scores(ctx, "2026-W42", entities.LeaderboardGlobal); err != nil || len(scores) != 0 {
+		t.Fatalf("Scores of a deleted member = %+v, %v, want none", scores, err)
+	}
+}
@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS cache (
+    key        TEXT PRIMARY KEY,
+    data       BLOB NOT NULL,
+    expires_at INTEGER NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS preferences (
+    user_id INTEGER PRIMARY KEY,
+    data    BLOB NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS feedback (
+    id         TEXT PRIMARY KEY,
+    verdict    TEXT NOT NULL,
+    word       TEXT NOT NULL,
+    language   TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    data       BLOB NOT NULL
+);
+CREATE INDEX IF NOT EXISTS feedback_created_at ON feedback (created_at, id);
+CREATE INDEX IF NOT EXISTS feedback_word ON feedback (word, id);
+
+CREATE TABLE IF NOT EXISTS jobs (
+    id         TEXT PRIMARY KEY,
+    data       BLOB NOT NULL,
+    expires_at INTEGER NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS activity (
+    user_id INTEGER PRIMARY KEY,
+    data    BLOB NOT NULL
+);
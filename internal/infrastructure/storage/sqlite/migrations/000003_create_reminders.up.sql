@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS reminders (
+    user_id INTEGER PRIMARY KEY,
+    next_at INTEGER NOT NULL,
+    data    BLOB NOT NULL
+);
+CREATE INDEX IF NOT EXISTS reminders_next_at ON reminders (next_at);
@@ -0,0 +1,4 @@
+CREATE TABLE IF NOT EXISTS dictionary (
+    word TEXT PRIMARY KEY,
+    data BLOB NOT NULL
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS leaderboard_scores;
+DROP TABLE IF EXISTS leaderboard_members;
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS achievements (
+    user_id    INTEGER NOT NULL,
+    id         TEXT NOT NULL,
+    awarded_at INTEGER NOT NULL,
+    data       BLOB NOT NULL,
+    PRIMARY KEY (user_id, id)
+);
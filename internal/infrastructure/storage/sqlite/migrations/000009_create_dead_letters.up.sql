@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS dead_letters (
+    id        TEXT PRIMARY KEY,
+    user_id   INTEGER NOT NULL,
+    failed_at INTEGER NOT NULL,
+    data      BLOB NOT NULL
+);
+CREATE INDEX IF NOT EXISTS dead_letters_failed_at ON dead_letters (failed_at);
+CREATE INDEX IF NOT EXISTS dead_letters_user_id ON dead_letters (user_id);
@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS chats (
+    id        INTEGER PRIMARY KEY,
+    opted_out INTEGER NOT NULL,
+    data      BLOB NOT NULL
+);
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS stats_counters (
+    name  TEXT PRIMARY KEY,
+    value INTEGER NOT NULL
+);
+-- The since counter holds the start of the counting in nanoseconds since the epoch
+INSERT INTO stats_counters (name, value) VALUES ('since', CAST(strftime('%s', 'now') AS INTEGER) * 1000000000)
+    ON CONFLICT (name) DO NOTHING;
+CREATE TABLE IF NOT EXISTS stats_words (
+    word    TEXT PRIMARY KEY,
+    lookups INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS stats_words_lookups ON stats_words (lookups, word);
+CREATE TABLE IF NOT EXISTS stats_spend (
+    month TEXT PRIMARY KEY,
+    spent REAL NOT NULL
+);
+CREATE TABLE IF NOT EXISTS stats_users (
+    user_id INTEGER PRIMARY KEY,
+    seen_at INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS stats_users_seen_at ON stats_users (seen_at);
@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS conversations (
+    chat_id    INTEGER PRIMARY KEY,
+    data       BLOB NOT NULL,
+    expires_at INTEGER NOT NULL
+);
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS replies (
+    chat_id    INTEGER NOT NULL,
+    message_id INTEGER NOT NULL,
+    reply_id   INTEGER NOT NULL,
+    expires_at INTEGER NOT NULL,
+    PRIMARY KEY (chat_id, message_id)
+);
@@ -0,0 +1,55 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+)
+
+// PreferencesRepository keeps user settings in the preferences table
+type PreferencesRepository struct {
+	client *Client
+}
+
+// NewPreferencesRepository creates a new SQLite preferences repository
+func NewPreferencesRepository(client *Client) *PreferencesRepository {
+	return &PreferencesRepository{client: client}
+}
+
+// Get returns the preferences of the user, default preferences if none were saved
+func (r *PreferencesRepository) Get(ctx context.Context, userID int64) (*entities.UserPreferences, error) {
+	data, ok, err := r.client.get(ctx, "SELECT data FROM preferences WHERE user_id = ?", userID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return &entities.UserPreferences{UserID: userID}, nil
+	}
+
+	var preferences entities.UserPreferences
+	if err := json.Unmarshal(data, &preferences); err != nil {
+		return nil, fmt.Errorf("invalid preferences of user %d: %w", userID, err)
+	}
+
+	return &preferences, nil
+}
+
+// Save replaces the preferences of the user
+func (r *PreferencesRepository) Save(ctx context.Context, preferences *entities.UserPreferences) error {
+	data, err := json.Marshal(preferences)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.exec(ctx,
+		"INSERT INTO preferences (user_id, data) VALUES (?, ?) ON CONFLICT (user_id) DO UPDATE SET data = excluded.data",
+		preferences.UserID, data)
+	return err
+}
+
+// Delete removes the preferences of the user
+func (r *PreferencesRepository) Delete(ctx context.Context, userID int64) error {
+	_, err := r.client.exec(ctx, "DELETE FROM preferences WHERE user_id = ?", userID)
+	return err
+}
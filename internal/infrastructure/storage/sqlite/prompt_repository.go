@@ -0,0 +1,52 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+)
+
+// PromptRepository keeps the prompt overrides of the tenants in the prompts table
+type PromptRepository struct {
+	client *Client
+}
+
+// NewPromptRepository creates a new SQLite prompt repository
+func NewPromptRepository(client *Client) *PromptRepository {
+	return &PromptRepository{client: client}
+}
+
+// Get returns the prompt overrides of the tenant, nil if none were saved
+func (r *PromptRepository) Get(ctx context.Context, tenantID string) (*entities.PromptOverrides, error) {
+	data, ok, err := r.client.get(ctx, "SELECT data FROM prompts WHERE tenant_id = ?", tenantID)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	var overrides entities.PromptOverrides
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return nil, fmt.Errorf("invalid prompt overrides of tenant %s: %w", tenantID, err)
+	}
+
+	return &overrides, nil
+}
+
+// Save replaces the prompt overrides of the tenant
+func (r *PromptRepository) Save(ctx context.Context, overrides *entities.PromptOverrides) error {
+	data, err := json.Marshal(overrides)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.exec(ctx,
+		"INSERT INTO prompts (tenant_id, data) VALUES (?, ?) ON CONFLICT (tenant_id) DO UPDATE SET data = excluded.data",
+		overrides.TenantID, data)
+	return err
+}
+
+// Delete removes the prompt overrides of the tenant
+func (r *PromptRepository) Delete(ctx context.Context, tenantID string) error {
+	_, err := r.client.exec(ctx, "DELETE FROM prompts WHERE tenant_id = ?", tenantID)
+	return err
+}
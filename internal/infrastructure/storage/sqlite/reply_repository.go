@@ -0,0 +1,42 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// ReplyRepository keeps the answers to the messages in the replies table for ttl after the answer
+type ReplyRepository struct {
+	client *Client
+	ttl    time.Duration
+}
+
+// NewReplyRepository creates a new SQLite reply repository keeping the answers for ttl
+func NewReplyRepository(client *Client, ttl time.Duration) *ReplyRepository {
+	return &ReplyRepository{client: client, ttl: ttl}
+}
+
+// Get returns the message ID of the answer to the message of the chat
+func (r *ReplyRepository) Get(ctx context.Context, chatID int64, messageID int) (int, bool, error) {
+	var replyID int
+	err := r.client.db.QueryRowContext(ctx,
+		"SELECT reply_id FROM replies WHERE chat_id = ? AND message_id = ? AND expires_at >= ?",
+		chatID, messageID, time.Now().UnixNano()).Scan(&replyID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	return replyID, true, nil
+}
+
+// Save stores the answer to the message
+func (r *ReplyRepository) Save(ctx context.Context, chatID int64, messageID, replyID int) error {
+	_, err := r.client.exec(ctx, "REPLACE INTO replies (chat_id, message_id, reply_id, expires_at) VALUES (?, ?, ?, ?)",
+		chatID, messageID, replyID, time.Now().Add(r.ttl).UnixNano())
+	return err
+}
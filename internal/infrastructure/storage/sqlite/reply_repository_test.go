@@ -0,0 +1,34 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestReplyRepository(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t)
+	r := NewReplyRepository(client, time.Hour)
+
+	if err := r.Save(ctx, -100, 1, 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Save(ctx, -100, 1, 3); err != nil {
+		t.Fatal(err)
+	}
+	if replyID, ok, err := r.Get(ctx, -100, 1); err != nil || !ok || replyID != 3 {
+		t.Fatalf("Get = %d, %v, %v, want the latest answer 3", replyID, ok, err)
+	}
+	if _, ok, err := r.Get(ctx, -200, 1); err != nil || ok {
+		t.Fatalf("Get of a message of another chat = %v, %v, want false", ok, err)
+	}
+
+	expired := NewReplyRepository(client, -time.Second)
+	if err := expired.Save(ctx, -100, 4, 5); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, err := r.Get(ctx, -100, 4); err != nil || ok {
+		t.Fatalf("Get of an expired answer = %v, %v, want false", ok, err)
+	}
+}
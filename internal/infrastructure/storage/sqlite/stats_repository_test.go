@@ -0,0 +1,114 @@
+package sqlite
+
+import (
+	cloudlogging "cloud.google.com/go/logging"
+	"context"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/entities"
+	"github.com/DeryabinSergey/germanarticlebot/libs/logger"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestStatsRepository(t *testing.T) {
+	ctx := context.Background()
+	r := NewStatsRepository(newTestClient(t), 0.5, logger.NewSlog(io.Discard, cloudlogging.Error, false))
+
+	for _, word := range []string{"Haus", " haus ", "Tisch", "Baum", ""} {
+		r.RecordLookup(ctx, word, "en", entities.AdapterTelegram)
+	}
+	r.RecordLookup(ctx, "Tisch", "en", "")
+	r.RecordCacheLookup(ctx, true)
+	r.RecordCacheLookup(ctx, false)
+	r.RecordCacheLookup(ctx, false)
+	r.RecordAICall(ctx, false)
+	r.RecordAICall(ctx, true)
+	r.RecordTelegramUser(ctx, 1)
+	r.RecordTelegramUser(ctx, 1)
+	r.RecordTelegramUser(ctx, 2)
+	r.RecordChatMemberEvent(ctx, entities.ChatMemberAddedToGroup)
+	r.RecordChatMemberEvent(ctx, entities.ChatMemberBlocked)
+
+	stats, err := r.Snapshot(ctx, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Since.IsZero() || stats.Since.After(stats.GeneratedAt) {
+		t.Errorf("Since = %v, want the creation of the schema", stats.Since)
+	}
+	if want := []entities.WordStat{{Word: "haus", Lookups: 2}, {Word: "tisch", Lookups: 2}}; !reflect.DeepEqual(stats.TopWords, want) {
+		t.Errorf("TopWords = %+v, want %+v", stats.TopWords, want)
+	}
+	if want := map[entities.Adapter]int64{entities.AdapterTelegram: 4}; !reflect.DeepEqual(stats.LookupsByAdapter, want) {
+		t.Errorf("LookupsByAdapter = %v, want %v", stats.LookupsByAdapter, want)
+	}
+	if stats.Cache.Hits != 1 || stats.Cache.Misses != 2 {
+		t.Errorf("Cache = %+v, want 1 hit and 2 misses", stats.Cache)
+	}
+	if stats.AI.Calls != 2 || stats.AI.Errors != 1 || stats.Quota.Used != 2 || stats.Spend.Spent != 1 {
+		t.Errorf("AI = %+v, quota %+v, spend %+v, want 2 calls, 1 error and 1 USD", stats.AI, stats.Quota, stats.Spend)
+	}
+	if stats.Telegram.ActiveUsers24h != 2 || stats.Telegram.ActiveUsers7d != 2 {
+		t.Errorf("Telegram = %+v, want 2 active users", stats.Telegram)
+	}
+	if want := (entities.ChurnStats{GroupsAdded: 1, Blocked: 1}); stats.Telegram.Churn != want {
+		t.Errorf("Churn = %+v, want %+v", stats.Telegram.Churn, want)
+	}
+	if spent, err := r.MonthlySpend(ctx); err != nil || spent != 1 {
+		t.Errorf("MonthlySpend = %v, %v, want 1", spent, err)
+	}
+
+	if err := r.DeleteUser(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if stats, err = r.Snapshot(ctx, 0); err != nil {
+		t.Fatal(err)
+	}
+	if stats.Telegram.ActiveUsers24h != 1 || stats.Telegram.ActiveUsers7d != 1 {
+		t.Errorf("Telegram after DeleteUser = %+v, want 1 active user", stats.Telegram)
+	}
+}
+
+func TestStatsRepositoryListWords(t *testing.T) {
+	ctx := context.Background()
+	r := NewStatsRepository(newTestClient(t), 0, logger.NewSlog(io.Discard, cloudlogging.Error, false))
+	for _, word := range []string{"haus", "haus", "haus", "hand", "hand", "hund", "tisch", "hähnchen"} {
+		r.RecordLookup(ctx, word, "en", "")
+	}
+
+	tests := []struct {
+		name   string
+		filter entities.WordStatFilter
+		want   [][]string
+	}{
+		{"most looked-up first", entities.WordStatFilter{Page: entities.PageRequest{Limit: 2}}, [][]string{{"haus", "hand"}, {"hund", "hähnchen"}, {"tisch"}}},
+		{"by word", entities.WordStatFilter{Page: entities.PageRequest{Limit: 3, OrderBy: "word"}}, [][]string{{"hand", "haus", "hund"}, {"hähnchen", "tisch"}}},
+		{"by word descending", entities.WordStatFilter{Page: entities.PageRequest{Limit: 3, OrderBy: "word", Descending: true}}, [][]string{{"tisch", "hähnchen", "hund"}, {"haus", "hand"}}},
+		{"prefix", entities.WordStatFilter{Prefix: "Ha", Page: entities.PageRequest{Limit: 1}}, [][]string{{"haus"}, {"hand"}}},
+		{"prefix with an umlaut", entities.WordStatFilter{Prefix: "hä"}, [][]string{{"hähnchen"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got [][]string
+			filter := tt.filter
+			for {
+				page, err := r.ListWords(ctx, filter)
+				if err != nil {
+					t.Fatal(err)
+				}
+				words := make([]string, 0, len(page.Items))
+				for _, stat := range page.Items {
+					words = append(words, stat.Word)
+				}
+				got = append(got, words)
+				if page.NextPageToken == "" {
+					break
+				}
+				filter.Page.PageToken = page.NextPageToken
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("pages = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -8,5 +8,6 @@ import (
 // Tracer defines the tracing interface
 type Tracer interface {
 	Start(ctx context.Context, spanName string, opts ...trace.SpanStartOption) (context.Context, trace.Span)
+	Flush(ctx context.Context) error
 	Close(ctx context.Context) error
-}
\ No newline at end of file
+}
@@ -0,0 +1,129 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/DeryabinSergey/germanarticlebot/internal/domain/services"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	// SignatureHeader carries "sha256=" and the hex HMAC-SHA256 of the timestamp, a dot and the body
+	SignatureHeader = "X-Webhook-Signature"
+	// TimestampHeader carries the Unix time of the delivery, receivers should reject old ones
+	TimestampHeader = "X-Webhook-Timestamp"
+
+	deliveryTimeout = 10 * time.Second
+)
+
+var errPrivateAddress = errors.New("callbacks to private addresses are not allowed")
+
+// Sender implements WebhookSender with HMAC signed HTTP posts. Callbacks to loopback, private and
+// link-local addresses, like the metadata server, are refused unless allowPrivate is set.
+type Sender struct {
+	client       *http.Client
+	secret       []byte
+	allowPrivate bool
+}
+
+// NewSender creates a sender signing the payloads with the secret
+func NewSender(secret string, allowPrivate bool) *Sender {
+	dialer := &net.Dialer{Timeout: deliveryTimeout}
+	if !allowPrivate {
+		// The resolved address is checked, so DNS names of private addresses are refused as well
+		dialer.Control = func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || isPrivate(ip) {
+				return errPrivateAddress
+			}
+			return nil
+		}
+	}
+
+	return &Sender{
+		client: &http.Client{
+			Timeout:   deliveryTimeout,
+			Transport: &http.Transport{DialContext: dialer.DialContext},
+			// Redirects could point to private addresses the callback URL was checked against
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+		secret:       []byte(secret),
+		allowPrivate: allowPrivate,
+	}
+}
+
+// Validate checks that the URL is an absolute HTTPS URL, plain HTTP is accepted only with allowPrivate
+func (s *Sender) Validate(callbackURL string) error {
+	u, err := url.Parse(callbackURL)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("callback URL must be absolute, got %q", callbackURL)
+	}
+	if u.Scheme != "https" && (u.Scheme != "http" || !s.allowPrivate) {
+		return fmt.Errorf("callback URL must use https, got %q", u.Scheme)
+	}
+	if ip := net.ParseIP(u.Hostname()); ip != nil && !s.allowPrivate && isPrivate(ip) {
+		return errPrivateAddress
+	}
+
+	return nil
+}
+
+// Send posts the signed payload, client errors of the receiver wrap ErrWebhookRejected
+func (s *Sender) Send(ctx context.Context, callbackURL string, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, callbackURL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("%w: %v", services.ErrWebhookRejected, err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, "sha256="+s.sign(timestamp, payload))
+
+	resp, err := s.client.Do(req)
+	if errors.Is(err, errPrivateAddress) {
+		return fmt.Errorf("%w: %v", services.ErrWebhookRejected, err)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to deliver webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests:
+		return fmt.Errorf("%w: receiver answered %d", services.ErrWebhookRejected, resp.StatusCode)
+	default:
+		return fmt.Errorf("webhook receiver answered %d", resp.StatusCode)
+	}
+}
+
+// sign returns the hex HMAC-SHA256 of the timestamp and the payload
+func (s *Sender) sign(timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func isPrivate(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsUnspecified() || ip.IsMulticast()
+}
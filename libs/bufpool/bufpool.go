@@ -0,0 +1,26 @@
+package bufpool
+
+import (
+	"bytes"
+	"sync"
+)
+
+// maxSize limits the capacity of the buffers kept in the pool, so a rare huge prompt or response doesn't
+// stay in memory for the life of the instance
+const maxSize = 64 << 10
+
+var pool = sync.Pool{New: func() any { return new(bytes.Buffer) }}
+
+// Get returns an empty buffer, hand it back with Put when its content isn't needed anymore
+func Get() *bytes.Buffer {
+	return pool.Get().(*bytes.Buffer)
+}
+
+// Put empties the buffer and returns it to the pool, it must not be used afterwards
+func Put(buf *bytes.Buffer) {
+	if buf.Cap() > maxSize {
+		return
+	}
+	buf.Reset()
+	pool.Put(buf)
+}
@@ -0,0 +1,82 @@
+package logger
+
+import (
+	"context"
+	"math/rand/v2"
+
+	"cloud.google.com/go/logging"
+)
+
+// writer writes a payload with a severity, entries below the configured level are dropped by it
+type writer interface {
+	write(ctx context.Context, severity logging.Severity, payload interface{})
+	sampleRate() float64
+}
+
+// Entry builds a log entry from typed fields, it is created by With of a logger:
+//
+//	l.With(ctx).Field("word", word).Info("Processing article request")
+type Entry struct {
+	ctx     context.Context
+	w       writer
+	fields  map[string]interface{}
+	sampled bool
+}
+
+func newEntry(ctx context.Context, w writer) *Entry {
+	return &Entry{ctx: ctx, w: w, fields: make(map[string]interface{})}
+}
+
+// Field adds a field to the entry, a repeated key overwrites the previous value
+func (e *Entry) Field(key string, value interface{}) *Entry {
+	e.fields[key] = value
+	return e
+}
+
+// Err adds the error message as the "error" field, nil errors are skipped
+func (e *Entry) Err(err error) *Entry {
+	if err != nil {
+		e.fields["error"] = err.Error()
+	}
+	return e
+}
+
+// Sampled marks the entry as written on a high-volume path, entries below Warning are then kept
+// only with the sample rate of the logger
+func (e *Entry) Sampled() *Entry {
+	e.sampled = true
+	return e
+}
+
+// Debug means debug or trace information.
+func (e *Entry) Debug(message string) { e.log(logging.Debug, message) }
+
+// Info means routine information, such as ongoing status or performance.
+func (e *Entry) Info(message string) { e.log(logging.Info, message) }
+
+// Notice means normal but significant events, such as start up, shut down, or configuration.
+func (e *Entry) Notice(message string) { e.log(logging.Notice, message) }
+
+// Warning means events that might cause problems.
+func (e *Entry) Warning(message string) { e.log(logging.Warning, message) }
+
+// Error means events that are likely to cause problems.
+func (e *Entry) Error(message string) { e.log(logging.Error, message) }
+
+// Critical means events that cause more severe problems or brief outages.
+func (e *Entry) Critical(message string) { e.log(logging.Critical, message) }
+
+func (e *Entry) log(severity logging.Severity, message string) {
+	if e.sampled && severity < logging.Warning {
+		if rate := e.w.sampleRate(); rate < 1 && rand.Float64() >= rate {
+			return
+		}
+	}
+
+	payload := make(map[string]interface{}, len(e.fields)+1)
+	for key, value := range e.fields {
+		payload[key] = value
+	}
+	payload["message"] = message
+	e.w.write(e.ctx, severity, payload)
+}
@@ -5,7 +5,9 @@ import (
 	"os"
 
 	"cloud.google.com/go/logging"
+	"github.com/DeryabinSergey/germanarticlebot/libs/requestid"
 	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/api/option"
 )
 
 type Log struct {
@@ -14,6 +16,7 @@ type Log struct {
 	client    *logging.Client
 	logger    *logging.Logger
 	level     logging.Severity
+	sample    float64
 }
 
 func New(client *logging.Client, logger *logging.Logger, level logging.Severity, projectID string, gcp bool) *Log {
@@ -23,19 +26,23 @@ func New(client *logging.Client, logger *logging.Logger, level logging.Severity,
 		client:    client,
 		logger:    logger,
 		level:     level,
+		sample:    1,
 	}
 }
 
 func Init(ctx context.Context, pID, applicationName string, gcp bool, level logging.Severity, opts ...logging.LoggerOption) (*Log, error) {
-	client, err := logging.NewClient(ctx, pID)
-	if err != nil {
-		return nil, err
-	}
-
+	var clientOpts []option.ClientOption
 	if !gcp {
+		// Entries are redirected to stdout, so no credentials are needed
+		clientOpts = append(clientOpts, option.WithoutAuthentication())
 		opts = append(opts, logging.RedirectAsJSON(os.Stdout))
 	}
 
+	client, err := logging.NewClient(ctx, pID, clientOpts...)
+	if err != nil {
+		return nil, err
+	}
+
 	return New(client, client.Logger(applicationName, opts...), level, pID, gcp), nil
 }
 
@@ -49,6 +56,15 @@ func (l *Log) Close(_ context.Context) error {
 	return nil
 }
 
+// Flush blocks until all buffered log entries are sent.
+func (l *Log) Flush(_ context.Context) error {
+	if l.logger != nil {
+		return l.logger.Flush()
+	}
+
+	return nil
+}
+
 // Log Default means the log entry has no assigned severity level.
 func (l *Log) Log(ctx context.Context, payload interface{}) {
 	l.logger.Log(l.build(ctx, logging.Default, payload))
@@ -56,74 +72,64 @@ func (l *Log) Log(ctx context.Context, payload interface{}) {
 
 // Debug means debug or trace information.
 func (l *Log) Debug(ctx context.Context, payload interface{}) {
-	if l.level > logging.Debug {
-		return
-	}
-
-	l.logger.Log(l.build(ctx, logging.Debug, payload))
+	l.write(ctx, logging.Debug, payload)
 }
 
 // Info means routine information, such as ongoing status or performance.
 func (l *Log) Info(ctx context.Context, payload interface{}) {
-	if l.level > logging.Info {
-		return
-	}
-
-	l.logger.Log(l.build(ctx, logging.Info, payload))
+	l.write(ctx, logging.Info, payload)
 }
 
 // Notice means normal but significant events, such as start up, shut down, or configuration.
 func (l *Log) Notice(ctx context.Context, payload interface{}) {
-	if l.level > logging.Notice {
-		return
-	}
-
-	l.logger.Log(l.build(ctx, logging.Notice, payload))
+	l.write(ctx, logging.Notice, payload)
 }
 
 // Warning means events that might cause problems.
 func (l *Log) Warning(ctx context.Context, payload interface{}) {
-	if l.level > logging.Warning {
-		return
-	}
-
-	l.logger.Log(l.build(ctx, logging.Warning, payload))
+	l.write(ctx, logging.Warning, payload)
 }
 
 // Error means events that are likely to cause problems.
 func (l *Log) Error(ctx context.Context, payload interface{}) {
-	if l.level > logging.Error {
-		return
-	}
-
-	l.logger.Log(l.build(ctx, logging.Error, payload))
+	l.write(ctx, logging.Error, payload)
 }
 
 // Critical means events that cause more severe problems or brief outages.
 func (l *Log) Critical(ctx context.Context, payload interface{}) {
-	if l.level > logging.Critical {
-		return
-	}
-
-	l.logger.Log(l.build(ctx, logging.Critical, payload))
+	l.write(ctx, logging.Critical, payload)
 }
 
 // Alert means a person must take action immediately.
 func (l *Log) Alert(ctx context.Context, payload interface{}) {
-	if l.level > logging.Alert {
-		return
-	}
-
-	l.logger.Log(l.build(ctx, logging.Alert, payload))
+	l.write(ctx, logging.Alert, payload)
 }
 
 // Emergency means one or more systems are unusable.
 func (l *Log) Emergency(ctx context.Context, payload interface{}) {
-	if l.level > logging.Emergency {
+	l.write(ctx, logging.Emergency, payload)
+}
+
+// SetSampleRate sets the share of sampled entries below Warning that are written, between 0 and 1
+func (l *Log) SetSampleRate(rate float64) {
+	l.sample = rate
+}
+
+// With starts an entry built from typed fields
+func (l *Log) With(ctx context.Context) *Entry {
+	return newEntry(ctx, l)
+}
+
+func (l *Log) sampleRate() float64 {
+	return l.sample
+}
+
+func (l *Log) write(ctx context.Context, severity logging.Severity, payload interface{}) {
+	if l.level > severity {
 		return
 	}
 
-	l.logger.Log(l.build(ctx, logging.Emergency, payload))
+	l.logger.Log(l.build(ctx, severity, payload))
 }
 
 func (l *Log) build(ctx context.Context, severity logging.Severity, payload interface{}) logging.Entry {
@@ -132,6 +138,10 @@ func (l *Log) build(ctx context.Context, severity logging.Severity, payload inte
 		Severity: severity,
 	}
 
+	if id := requestid.FromContext(ctx); id != "" {
+		e.Labels = map[string]string{"requestId": id}
+	}
+
 	if !l.gcp {
 		return e
 	}
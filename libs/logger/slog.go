@@ -0,0 +1,131 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"log/slog"
+
+	"cloud.google.com/go/logging"
+	"github.com/DeryabinSergey/germanarticlebot/libs/requestid"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Slog writes entries with log/slog, it is used when the application runs outside of GCP
+type Slog struct {
+	logger *slog.Logger
+	level  logging.Severity
+	sample float64
+}
+
+// NewSlog creates a logger writing JSON lines, or text lines when json is false, to w
+func NewSlog(w io.Writer, level logging.Severity, json bool) *Slog {
+	// Filtering by severity is done here, the handler gets every entry
+	options := &slog.HandlerOptions{Level: slog.LevelDebug}
+	var handler slog.Handler = slog.NewTextHandler(w, options)
+	if json {
+		handler = slog.NewJSONHandler(w, options)
+	}
+
+	return &Slog{logger: slog.New(handler), level: level, sample: 1}
+}
+
+// Close is a no-op, slog writes entries synchronously
+func (l *Slog) Close(_ context.Context) error {
+	return nil
+}
+
+// Flush is a no-op, slog writes entries synchronously
+func (l *Slog) Flush(_ context.Context) error {
+	return nil
+}
+
+// Debug means debug or trace information.
+func (l *Slog) Debug(ctx context.Context, payload interface{}) {
+	l.write(ctx, logging.Debug, payload)
+}
+
+// Info means routine information, such as ongoing status or performance.
+func (l *Slog) Info(ctx context.Context, payload interface{}) {
+	l.write(ctx, logging.Info, payload)
+}
+
+// Notice means normal but significant events, such as start up, shut down, or configuration.
+func (l *Slog) Notice(ctx context.Context, payload interface{}) {
+	l.write(ctx, logging.Notice, payload)
+}
+
+// Warning means events that might cause problems.
+func (l *Slog) Warning(ctx context.Context, payload interface{}) {
+	l.write(ctx, logging.Warning, payload)
+}
+
+// Error means events that are likely to cause problems.
+func (l *Slog) Error(ctx context.Context, payload interface{}) {
+	l.write(ctx, logging.Error, payload)
+}
+
+// Critical means events that cause more severe problems or brief outages.
+func (l *Slog) Critical(ctx context.Context, payload interface{}) {
+	l.write(ctx, logging.Critical, payload)
+}
+
+// SetSampleRate sets the share of sampled entries below Warning that are written, between 0 and 1
+func (l *Slog) SetSampleRate(rate float64) {
+	l.sample = rate
+}
+
+// With starts an entry built from typed fields
+func (l *Slog) With(ctx context.Context) *Entry {
+	return newEntry(ctx, l)
+}
+
+func (l *Slog) sampleRate() float64 {
+	return l.sample
+}
+
+func (l *Slog) write(ctx context.Context, severity logging.Severity, payload interface{}) {
+	if l.level > severity {
+		return
+	}
+
+	var (
+		message string
+		attrs   = []slog.Attr{slog.String("severity", severity.String())}
+	)
+	switch p := payload.(type) {
+	case string:
+		message = p
+	case map[string]interface{}:
+		message, _ = p["message"].(string)
+		for key, value := range p {
+			if key != "message" {
+				attrs = append(attrs, slog.Any(key, value))
+			}
+		}
+	default:
+		attrs = append(attrs, slog.Any("payload", p))
+	}
+
+	if id := requestid.FromContext(ctx); id != "" {
+		attrs = append(attrs, slog.String("requestId", id))
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		attrs = append(attrs, slog.String("traceId", sc.TraceID().String()), slog.String("spanId", sc.SpanID().String()))
+	}
+
+	l.logger.LogAttrs(ctx, slogLevel(severity), message, attrs...)
+}
+
+// slogLevel maps the Cloud Logging severity to the closest slog level
+func slogLevel(severity logging.Severity) slog.Level {
+	switch {
+	case severity >= logging.Error:
+		return slog.LevelError
+	case severity >= logging.Warning:
+		return slog.LevelWarn
+	case severity >= logging.Info:
+		return slog.LevelInfo
+	default:
+		return slog.LevelDebug
+	}
+}
@@ -0,0 +1,8 @@
+//go:build race
+
+// Package race tells whether the binary is built with the race detector, which changes the allocations
+// of the code it instruments
+package race
+
+// Enabled is true in builds with the race detector
+const Enabled = true
@@ -0,0 +1,43 @@
+package requestid
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/google/uuid"
+)
+
+// Header is the HTTP header carrying the correlation ID
+const Header = "X-Request-ID"
+
+type ctxKey struct{}
+
+var validID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// New generates a new correlation ID
+func New() string {
+	return uuid.NewString()
+}
+
+// Resolve returns the incoming ID when it is safe to log and echo, otherwise a new one
+func Resolve(incoming string) string {
+	if validID.MatchString(incoming) {
+		return incoming
+	}
+
+	return New()
+}
+
+// NewContext returns a copy of ctx carrying the correlation ID
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// FromContext returns the correlation ID stored in ctx or an empty string
+func FromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(ctxKey{}).(string); ok {
+		return id
+	}
+
+	return ""
+}
@@ -2,10 +2,15 @@ package tracer
 
 import (
 	"context"
+	"fmt"
+	"os"
 
+	"github.com/DeryabinSergey/germanarticlebot/libs/requestid"
 	texporter "github.com/GoogleCloudPlatform/opentelemetry-operations-go/exporter/trace"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
@@ -21,54 +26,54 @@ func New(tp *sdktrace.TracerProvider, tr trace.Tracer) *Tracer {
 	return &Tracer{tr: tr, tp: tp}
 }
 
-func Init(ctx context.Context, projectID, applicationName string, gcp bool) (tr *Tracer, err error) {
-	var (
-		traceProvider *sdktrace.TracerProvider
-		tracer        trace.Tracer
+// Exporters of the finished spans
+const (
+	ExporterCloudTrace = "cloudtrace"
+	// ExporterOTLP sends spans over OTLP/gRPC to OTEL_EXPORTER_OTLP_ENDPOINT, e.g. a collector or Jaeger
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+	// ExporterNone keeps the spans for context propagation without exporting them
+	ExporterNone = "none"
+)
+
+func Init(ctx context.Context, projectID, applicationName, exporterName string) (tr *Tracer, err error) {
+	res, err := resource.New(ctx,
+		// Add your own custom attributes to identify your application
+		resource.WithAttributes(semconv.ServiceNameKey.String(applicationName)),
 	)
+	if err != nil {
+		return tr, err
+	}
 
-	if gcp {
+	options := []sdktrace.TracerProviderOption{sdktrace.WithResource(res)}
+	switch exporterName {
+	case ExporterCloudTrace:
 		exporter, err := texporter.New(texporter.WithProjectID(projectID))
 		if err != nil {
 			return tr, err
 		}
-
-		res, err := resource.New(ctx,
-			// Use the GCP resource detector to detect information about the GCP platform
-			//resource.WithDetectors(gcp.NewDetector()),
-			// Keep the default detectors
-			//resource.WithTelemetrySDK(),
-			// Add your own custom attributes to identify your application
-			resource.WithAttributes(semconv.ServiceNameKey.String(applicationName)),
-		)
-		if err != nil {
-			return tr, err
-		}
-
-		traceProvider = sdktrace.NewTracerProvider(
-			sdktrace.WithBatcher(exporter),
-			sdktrace.WithResource(res),
-		)
-	} else {
-		//exporter, err := jaeger.New(jaeger.WithAgentEndpoint())
+		options = append(options, sdktrace.WithBatcher(exporter))
+	case ExporterOTLP:
 		exporter, err := otlptracegrpc.New(ctx)
 		if err != nil {
 			return tr, err
 		}
-		res, err := resource.New(ctx,
-			resource.WithAttributes(semconv.ServiceNameKey.String(applicationName)),
-		)
+		options = append(options, sdktrace.WithBatcher(exporter))
+	case ExporterStdout:
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
 		if err != nil {
 			return tr, err
 		}
-		traceProvider = sdktrace.NewTracerProvider(
-			sdktrace.WithBatcher(exporter),
-			sdktrace.WithResource(res),
-		)
+		// Spans are written right away, so they are interleaved with the local logs
+		options = append(options, sdktrace.WithSyncer(exporter))
+	case ExporterNone:
+	default:
+		return tr, fmt.Errorf("unknown trace exporter %q", exporterName)
 	}
 
+	traceProvider := sdktrace.NewTracerProvider(options...)
 	otel.SetTracerProvider(traceProvider)
-	tracer = otel.GetTracerProvider().Tracer(applicationName)
+	tracer := otel.GetTracerProvider().Tracer(applicationName)
 	return New(traceProvider, tracer), nil
 }
 
@@ -80,6 +85,20 @@ func (t *Tracer) Close(ctx context.Context) error {
 	return t.tp.Shutdown(ctx)
 }
 
+// Flush exports all spans that have not been exported yet.
+func (t *Tracer) Flush(ctx context.Context) error {
+	if t.tp == nil {
+		return nil
+	}
+
+	return t.tp.ForceFlush(ctx)
+}
+
+// Start starts a span tagged with the correlation ID of the context
 func (t *Tracer) Start(ctx context.Context, spanName string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	if id := requestid.FromContext(ctx); id != "" {
+		opts = append(opts, trace.WithAttributes(attribute.String("request.id", id)))
+	}
+
 	return t.tr.Start(ctx, spanName, opts...)
 }